@@ -0,0 +1,274 @@
+// Package abi provides helpers to build and decode contract calls from a
+// contract's JSON ABI, wrapping go-ethereum's accounts/abi package. Unlike the
+// wrapped package, arguments are given as strings, e.g. as taken from the
+// command line, and are converted to the Go types their ABI types map to.
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// LoadFile returns the ABI held by the JSON file at the given path, as output
+// by solc --abi.
+func LoadFile(path string) (ethabi.ABI, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ethabi.ABI{}, err
+	}
+
+	defer f.Close()
+
+	contractABI, err := ethabi.JSON(f)
+	if err != nil {
+		return ethabi.ABI{}, fmt.Errorf("invalid ABI in %s: %v", path, err)
+	}
+
+	return contractABI, nil
+}
+
+// PackCall returns the data of a call to the given method of a contract with
+// the given arguments, each parsed as of the method's corresponding input
+// with ParseArg.
+func PackCall(contractABI ethabi.ABI, method string, args ...string) ([]byte, error) {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+
+	if len(args) != len(m.Inputs) {
+		return nil, fmt.Errorf("invalid number of arguments for %s; got %d, expected %d", m.Sig(), len(args), len(m.Inputs))
+	}
+
+	values := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		value, err := ParseArg(m.Inputs[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument #%d of %s: %v", i, m.Sig(), err)
+		}
+
+		values[i] = value
+	}
+
+	return contractABI.Pack(method, values...)
+}
+
+// UnpackReturn returns the values returned by a call to the given method of a
+// contract, in order.
+func UnpackReturn(contractABI ethabi.ABI, method string, output []byte) ([]interface{}, error) {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+
+	return m.Outputs.UnpackValues(output)
+}
+
+// ParseArg returns the given string parsed as a value of the given ABI type.
+// Integers are given in decimal or as 0x prefixed hex, booleans as "true" or
+// "false", addresses and bytes as hex and strings as is. Arrays and slices are
+// given as a bracketed, comma separated list of their elements, e.g. "[1,2]".
+func ParseArg(typ ethabi.Type, arg string) (interface{}, error) {
+	value, err := parseArg(typ, strings.TrimSpace(arg))
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Interface(), nil
+}
+
+func parseArg(typ ethabi.Type, arg string) (reflect.Value, error) {
+	switch typ.T {
+	case ethabi.IntTy, ethabi.UintTy:
+		return parseInt(typ, arg)
+
+	case ethabi.BoolTy:
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %s", arg)
+		}
+
+		return reflect.ValueOf(b), nil
+
+	case ethabi.StringTy:
+		return reflect.ValueOf(arg), nil
+
+	case ethabi.AddressTy:
+		if !ethcmn.IsHexAddress(arg) {
+			return reflect.Value{}, fmt.Errorf("invalid address %s", arg)
+		}
+
+		return reflect.ValueOf(ethcmn.HexToAddress(arg)), nil
+
+	case ethabi.BytesTy:
+		bz, err := hexutil.Decode(arg)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bytes %s: %v", arg, err)
+		}
+
+		return reflect.ValueOf(bz), nil
+
+	case ethabi.FixedBytesTy:
+		bz, err := hexutil.Decode(arg)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid %s %s: %v", typ, arg, err)
+		}
+
+		if len(bz) != typ.Size {
+			return reflect.Value{}, fmt.Errorf("invalid %s %s: got %d bytes", typ, arg, len(bz))
+		}
+
+		value := reflect.New(typ.Type).Elem()
+		reflect.Copy(value, reflect.ValueOf(bz))
+
+		return value, nil
+
+	case ethabi.SliceTy, ethabi.ArrayTy:
+		elems, err := splitList(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value := reflect.New(typ.Type).Elem()
+		if typ.T == ethabi.SliceTy {
+			value = reflect.MakeSlice(typ.Type, len(elems), len(elems))
+		} else if len(elems) != typ.Size {
+			return reflect.Value{}, fmt.Errorf("invalid %s: got %d elements", typ, len(elems))
+		}
+
+		for i, elem := range elems {
+			elemValue, err := parseArg(*typ.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			value.Index(i).Set(elemValue)
+		}
+
+		return value, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported argument type %s", typ)
+	}
+}
+
+// parseInt parses an integer of the given ABI integer type, checking that it
+// fits the type.
+func parseInt(typ ethabi.Type, arg string) (reflect.Value, error) {
+	x, ok := new(big.Int).SetString(arg, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %s", arg)
+	}
+
+	min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(typ.Size))
+	if typ.T == ethabi.IntTy {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+
+	if x.Cmp(min) == -1 || x.Cmp(max) != -1 {
+		return reflect.Value{}, fmt.Errorf("integer %s overflows %s", arg, typ)
+	}
+
+	if typ.Kind == reflect.Ptr {
+		return reflect.ValueOf(x), nil
+	}
+
+	value := reflect.New(typ.Type).Elem()
+	if typ.T == ethabi.IntTy {
+		value.SetInt(x.Int64())
+	} else {
+		value.SetUint(x.Uint64())
+	}
+
+	return value, nil
+}
+
+// splitList splits a bracketed, comma separated list into its elements,
+// which may be lists themselves.
+func splitList(arg string) ([]string, error) {
+	if !strings.HasPrefix(arg, "[") || !strings.HasSuffix(arg, "]") {
+		return nil, fmt.Errorf("invalid list %s", arg)
+	}
+
+	inner := strings.TrimSpace(arg[1 : len(arg)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var (
+		elems []string
+		depth int
+		start int
+	)
+
+	for i, c := range inner {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				elems = append(elems, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+
+		if depth < 0 {
+			return nil, fmt.Errorf("invalid list %s", arg)
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid list %s", arg)
+	}
+
+	return append(elems, strings.TrimSpace(inner[start:])), nil
+}
+
+// FormatValue formats a value returned by UnpackReturn for display. Addresses
+// are formatted as checksummed hex, bytes as hex and lists as bracketed,
+// comma separated lists.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case ethcmn.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case *big.Int:
+		return v.String()
+	case string:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			bz := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(bz), rv)
+
+			return hexutil.Encode(bz)
+		}
+
+		elems := make([]string, rv.Len())
+		for i := range elems {
+			elems[i] = FormatValue(rv.Index(i).Interface())
+		}
+
+		return "[" + strings.Join(elems, ",") + "]"
+	}
+
+	return fmt.Sprint(value)
+}
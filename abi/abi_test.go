@@ -0,0 +1,156 @@
+package abi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"transfer","constant":false,
+	 "inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],
+	 "outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","constant":true,
+	 "inputs":[{"name":"owner","type":"address"}],
+	 "outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"all","constant":false,
+	 "inputs":[
+		{"name":"a","type":"int8"},{"name":"b","type":"bool"},{"name":"c","type":"string"},
+		{"name":"d","type":"bytes"},{"name":"e","type":"bytes2"},{"name":"f","type":"uint64[]"},
+		{"name":"g","type":"address[2]"}
+	 ],
+	 "outputs":[{"name":"","type":"address"},{"name":"","type":"bytes2"},{"name":"","type":"uint64[]"}]}
+]`
+
+const testAddr = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "abi")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "erc20.json")
+	require.Nil(t, ioutil.WriteFile(path, []byte(testABI), 0600))
+
+	contractABI, err := LoadFile(path)
+	require.Nil(t, err)
+	require.Len(t, contractABI.Methods, 3)
+
+	require.Nil(t, ioutil.WriteFile(path, []byte("{"), 0600))
+
+	_, err = LoadFile(path)
+	require.NotNil(t, err)
+
+	_, err = LoadFile(filepath.Join(dir, "missing.json"))
+	require.NotNil(t, err)
+}
+
+func TestPackCall(t *testing.T) {
+	contractABI, err := ethabi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+
+	transfer := "0xa9059cbb" +
+		"0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed" +
+		"0000000000000000000000000000000000000000000000000000000000000064"
+
+	testCases := []struct {
+		method    string
+		args      []string
+		expected  string
+		expectErr bool
+	}{
+		{"transfer", []string{testAddr, "100"}, transfer, false},
+		{"transfer", []string{testAddr, "0x64"}, transfer, false},
+		{"transfer", []string{testAddr, "-1"}, "", true},
+		{"transfer", []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", "100"}, "", true},
+		{"transfer", []string{testAddr}, "", true},
+		{"approve", []string{testAddr, "100"}, "", true},
+		{"all", []string{"-1", "true", "a,b", "0x01", "0x0102", "[1, 2]", "[" + testAddr + "," + testAddr + "]"}, "", false},
+		{"all", []string{"128", "true", "", "0x", "0x0102", "[]", "[" + testAddr + "," + testAddr + "]"}, "", true},
+		{"all", []string{"1", "yes", "", "0x", "0x0102", "[]", "[" + testAddr + "," + testAddr + "]"}, "", true},
+		{"all", []string{"1", "true", "", "0x", "0x01", "[]", "[" + testAddr + "," + testAddr + "]"}, "", true},
+		{"all", []string{"1", "true", "", "0x", "0x0102", "[1", "[" + testAddr + "," + testAddr + "]"}, "", true},
+		{"all", []string{"1", "true", "", "0x", "0x0102", "[]", "[" + testAddr + "]"}, "", true},
+	}
+
+	for i, tc := range testCases {
+		data, err := PackCall(contractABI, tc.method, tc.args...)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		if tc.expected != "" {
+			require.Equal(t, tc.expected, hexutil.Encode(data), fmt.Sprintf("unexpected data: test case #%d", i))
+		}
+	}
+}
+
+func TestParseArg(t *testing.T) {
+	testCases := []struct {
+		typ      string
+		arg      string
+		expected interface{}
+	}{
+		{"uint8", "255", uint8(255)},
+		{"int64", "-5", int64(-5)},
+		{"uint256", "0xff", big.NewInt(255)},
+		{"int128", "-1", big.NewInt(-1)},
+		{"bool", "false", false},
+		{"address", testAddr, ethcmn.HexToAddress(testAddr)},
+		{"bytes", "0x0102", []byte{0x01, 0x02}},
+		{"bytes3", "0x010203", [3]byte{0x01, 0x02, 0x03}},
+		{"uint16[][]", "[[1],[2, 3]]", [][]uint16{{1}, {2, 3}}},
+	}
+
+	for i, tc := range testCases {
+		typ, err := ethabi.NewType(tc.typ)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		value, err := ParseArg(typ, tc.arg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expected, value, fmt.Sprintf("unexpected value: test case #%d", i))
+	}
+
+	typ, err := ethabi.NewType("uint8")
+	require.Nil(t, err)
+
+	_, err = ParseArg(typ, "256")
+	require.NotNil(t, err)
+}
+
+func TestUnpackReturn(t *testing.T) {
+	contractABI, err := ethabi.JSON(strings.NewReader(testABI))
+	require.Nil(t, err)
+
+	output, err := contractABI.Methods["all"].Outputs.Pack(
+		ethcmn.HexToAddress(testAddr), [2]byte{0x01, 0x02}, []uint64{1, 2},
+	)
+	require.Nil(t, err)
+
+	values, err := UnpackReturn(contractABI, "all", output)
+	require.Nil(t, err)
+	require.Len(t, values, 3)
+
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = FormatValue(value)
+	}
+
+	require.Equal(t, []string{testAddr, "0x0102", "[1,2]"}, formatted)
+
+	_, err = UnpackReturn(contractABI, "approve", output)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,279 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/authz"
+	"github.com/cosmos/ethermint/x/circuit"
+	"github.com/cosmos/ethermint/x/evm"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// NewAnteHandler returns an ante handler authenticating Ethereum transactions
+// signed for the given Ethereum chain ID and embedded SDK transactions.
+//
+// A message without signers is only accepted if its type is one of the given
+// unsigned message types, e.g. the faucet's, whose handler limits it on its
+// own. An auth.StdTx, whose signatures and fee are never checked, is only
+// accepted if all of its messages are such unsigned messages. Any other
+// transaction is rejected.
+//
+// Every account has a single canonical nonce, its auth.Account sequence,
+// shared by both transaction kinds. An Ethereum transaction must carry the
+// sender's current sequence as its nonce while every signer of an EmbeddedTx
+// signs over its current sequence. Either way, the sequence of every
+// authenticated account is incremented by one. As both kinds consume the same
+// counter, transactions of either kind from a single account are strictly
+// ordered and neither kind can be replayed as, or ahead of, the other: once a
+// sequence is used by one kind, any pending transaction of the other kind
+// signed over the same sequence is rejected. Upon CheckTx, the sequence is
+// incremented in the check state only, as a speculative nonce letting a sender
+// queue consecutive transactions, which Commit resets to the committed one.
+//
+// A signer of an EmbeddedTx may delegate the signing of specific message
+// types to another account through an authz grant. The grantee then signs
+// over the signer's account number and sequence in place of the signer.
+//
+// Any transaction, of any kind, carrying a message of a type disabled by the
+// circuit breaker is rejected. As the circuit module's own messages cannot be
+// disabled, the breaker's authority can always reset it.
+//
+// The value and gas of an Ethereum transaction are paid in the given EVM-native
+// denom.
+//
+// An Ethereum transaction paying a gas price above the EVM module's maximum
+// gas price, or whose gas limit is out of its gas limit bounds, is rejected.
+// So is an Ethereum transaction whose nonce is not the sender's and, upon
+// CheckTx, one whose sender cannot afford its value plus its gas limit times
+// its gas price. Upon CheckTx, that cost is debited from the sender's balance
+// in the check state, alongside the speculative nonce, so that the sender's
+// subsequent transactions are checked against the balance left; Commit resets
+// it to the committed one. These aborts carry a types.AnteAbort as the data of
+// their result. Ethermint's own errors are raised in the given codespace.
+//
+// No state is modified unless the transaction is authenticated, so that a
+// rejected transaction pays no fee (see the EVM module's FailedTxRefund
+// parameter). An Ethereum transaction is metered by an ethGasMeter.
+func NewAnteHandler(
+	am auth.AccountMapper, ak authz.Keeper, ck circuit.Keeper, ek evm.Keeper, ethChainID *big.Int,
+	evmDenom string, codespace sdk.CodespaceType, unsignedMsgTypes ...string,
+) sdk.AnteHandler {
+
+	chainConfig := core.NewChainConfig(ethChainID)
+
+	unsigned := make(map[string]bool, len(unsignedMsgTypes))
+	for _, msgType := range unsignedMsgTypes {
+		unsigned[msgType] = true
+	}
+
+	return func(ctx sdk.Context, tx sdk.Tx) (sdk.Context, sdk.Result, bool) {
+		// the gas used by an Ethereum transaction is only that of its
+		// execution
+		if _, ok := tx.(*types.Transaction); ok {
+			ctx = ctx.WithGasMeter(newEthGasMeter())
+		}
+
+		if err := ck.CheckMsgs(ctx, tx.GetMsgs()); err != nil {
+			return ctx, err.Result(), true
+		}
+
+		var err sdk.Error
+
+		cacheCtx, write := ctx.CacheContext()
+
+		switch tx := tx.(type) {
+		case *types.Transaction:
+			err = handleEthTx(cacheCtx, am, ek, chainConfig, evmDenom, codespace, tx)
+
+		case types.EmbeddedTx:
+			if err = checkUnsignedMsgs(tx.GetMsgs(), unsigned); err == nil {
+				err = handleEmbeddedTx(cacheCtx, am, ak, tx)
+			}
+
+		case auth.StdTx:
+			err = handleStdTx(tx, unsigned)
+
+		default:
+			err = sdk.ErrUnauthorized(fmt.Sprintf("unsupported transaction type: %T", tx))
+		}
+
+		if err != nil {
+			return ctx, err.Result(), true
+		}
+
+		write()
+		return ctx, sdk.Result{}, false
+	}
+}
+
+// handleEthTx checks the gas price and limit of an Ethereum transaction
+// against the EVM module's parameters, authenticates it, creating the sender's
+// account if it does not exist, checks the sender can afford it upon CheckTx
+// and consumes the sender's nonce.
+func handleEthTx(
+	ctx sdk.Context, am auth.AccountMapper, ek evm.Keeper, chainConfig *ethparams.ChainConfig, evmDenom string,
+	codespace sdk.CodespaceType, tx *types.Transaction,
+) sdk.Error {
+
+	if err := ek.CheckGas(ctx, tx.GasPrice(), tx.Gas()); err != nil {
+		return err
+	}
+
+	sender, err := tx.Sender(chainConfig, big.NewInt(ctx.BlockHeight()))
+	if err != nil {
+		return types.ErrInvalidSender(codespace, err.Error())
+	}
+
+	addr := sdk.AccAddress(sender.Bytes())
+
+	acc := am.GetAccount(ctx, addr)
+	if acc == nil {
+		acc = am.NewAccountWithAddress(ctx, addr)
+	}
+
+	seq := acc.GetSequence()
+	if seq < 0 || uint64(seq) != tx.Nonce() {
+		reason := types.AbortNonceTooLow
+		if seq >= 0 && tx.Nonce() > uint64(seq) {
+			reason = types.AbortNonceTooHigh
+		}
+
+		return types.NewAbortError(
+			sdk.ErrInvalidSequence(fmt.Sprintf("invalid nonce; got %d, expected %d", tx.Nonce(), seq)),
+			types.AnteAbort{Reason: reason, Nonce: uint64(seq)},
+		)
+	}
+
+	// the sender's balance is only checked against the mempool, as is done
+	// by go-ethereum, leaving execution to fail an unaffordable transaction
+	if ctx.IsCheckTx() {
+		cost := ethTxCost(tx)
+
+		balance := types.EVMBalance(acc.GetCoins(), evmDenom)
+		if balance.Cmp(cost) < 0 {
+			return types.NewAbortError(
+				sdk.ErrInsufficientCoins(fmt.Sprintf("insufficient funds; required %s, balance %s", cost, balance)),
+				types.AnteAbort{Reason: types.AbortInsufficientFunds, Nonce: uint64(seq), Required: cost, Balance: balance},
+			)
+		}
+
+		if err := acc.SetCoins(types.SetEVMBalance(acc.GetCoins(), evmDenom, new(big.Int).Sub(balance, cost))); err != nil {
+			return sdk.ErrInternal(err.Error())
+		}
+	}
+
+	return incrementSequence(ctx, am, acc)
+}
+
+// ethTxCost returns the maximum cost of an Ethereum transaction to its sender,
+// i.e. its value plus its gas limit times its gas price.
+func ethTxCost(tx *types.Transaction) *big.Int {
+	cost := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	return cost.Add(cost, tx.Value())
+}
+
+// handleEmbeddedTx authenticates every signer of an EmbeddedTx over its
+// account number and current sequence, and consumes the signers' nonces. A
+// signature made by a grantee of the signer is accepted if the grantee is
+// authorized to sign every message the signer must sign.
+func handleEmbeddedTx(ctx sdk.Context, am auth.AccountMapper, ak authz.Keeper, tx types.EmbeddedTx) sdk.Error {
+	if err := tx.ValidateBasic(); err != nil {
+		return err
+	}
+
+	for i, signer := range tx.GetSigners() {
+		acc := am.GetAccount(ctx, signer)
+		if acc == nil {
+			return sdk.ErrUnknownAddress(signer.String())
+		}
+
+		signBytes := types.EmbeddedSignBytes(ctx.ChainID(), acc.GetAccountNumber(), acc.GetSequence(), tx.Messages)
+
+		pubKey, err := ethcrypto.SigToPub(ethcrypto.Keccak256(signBytes), tx.Signatures[i])
+		if err != nil {
+			return sdk.ErrUnauthorized(fmt.Sprintf("signature verification failed for %s", signer))
+		}
+
+		if signedBy := sdk.AccAddress(ethcrypto.PubkeyToAddress(*pubKey).Bytes()); !bytes.Equal(signedBy, signer) {
+			if err := authorizeGrantee(ctx, ak, signer, signedBy, tx.Messages); err != nil {
+				return err
+			}
+		} else if acc.GetPubKey() == nil {
+			if err := acc.SetPubKey(crypto.PubKeySecp256k1(ethcrypto.CompressPubkey(pubKey))); err != nil {
+				return sdk.ErrInternal(err.Error())
+			}
+		}
+
+		if err := incrementSequence(ctx, am, acc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleStdTx accepts an auth.StdTx only if all of its messages are unsigned
+// messages of the given types, as its signatures are not verified.
+func handleStdTx(tx auth.StdTx, unsigned map[string]bool) sdk.Error {
+	for _, msg := range tx.GetMsgs() {
+		if len(msg.GetSigners()) != 0 {
+			return sdk.ErrUnauthorized(
+				fmt.Sprintf("unauthenticated transaction carries message of type %s with signers", msg.Type()),
+			)
+		}
+	}
+
+	return checkUnsignedMsgs(tx.GetMsgs(), unsigned)
+}
+
+// checkUnsignedMsgs verifies that every given SDK message without signers is
+// of one of the given unsigned message types.
+func checkUnsignedMsgs(msgs []sdk.Msg, unsigned map[string]bool) sdk.Error {
+	for _, msg := range msgs {
+		if len(msg.GetSigners()) == 0 && !unsigned[msg.Type()] {
+			return sdk.ErrUnauthorized(fmt.Sprintf("message of type %s carries no signers", msg.Type()))
+		}
+	}
+
+	return nil
+}
+
+// authorizeGrantee verifies that a grantee may sign, on behalf of a granter,
+// every given message the granter must sign.
+func authorizeGrantee(ctx sdk.Context, ak authz.Keeper, granter, grantee sdk.AccAddress, msgs []sdk.Msg) sdk.Error {
+	for _, msg := range msgs {
+		for _, signer := range msg.GetSigners() {
+			if !bytes.Equal(signer, granter) {
+				continue
+			}
+
+			if err := ak.Authorize(ctx, granter, grantee, msg); err != nil {
+				return err
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// incrementSequence increments the canonical nonce of an account and persists
+// the account.
+func incrementSequence(ctx sdk.Context, am auth.AccountMapper, acc auth.Account) sdk.Error {
+	if err := acc.SetSequence(acc.GetSequence() + 1); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+
+	am.SetAccount(ctx, acc)
+	return nil
+}
@@ -0,0 +1,442 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/authz"
+	"github.com/cosmos/ethermint/x/circuit"
+	"github.com/cosmos/ethermint/x/evm"
+	"github.com/cosmos/ethermint/x/faucet"
+	"github.com/cosmos/ethermint/x/ica"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestAnteHandlerInterleavedNonces(t *testing.T) {
+	ethChainID := big.NewInt(3)
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), SetEthChainID(ethChainID))
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, ethChainID, types.DenomDefault,
+		app.codespace,
+	)
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	addr := sdk.AccAddress(privKey.PubKey().Address())
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+
+	ethTx := func(nonce uint64) sdk.Tx {
+		tx := types.NewTransaction(nonce, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+		tx.Sign(ethChainID, privKey.ToECDSA())
+		return tx
+	}
+
+	embeddedTx := func(seq int64) sdk.Tx {
+		msg := ica.NewMsgRemoteExecute("chain-a", "alice", addr, to, sdk.ZeroInt(), nil, 1)
+
+		tx, err := types.NewEmbeddedTxBuilder(app.codec, "ethermint", msg).Sign(privKey, 0, seq).EmbeddedTx()
+		require.Nil(t, err)
+
+		return tx
+	}
+
+	testCases := []struct {
+		tx          sdk.Tx
+		expectErr   bool
+		expectedSeq int64
+	}{
+		// the sender's account is created by its first Ethereum transaction
+		{ethTx(0), false, 1},
+		{embeddedTx(1), false, 2},
+		// a nonce consumed by an embedded transaction cannot be reused
+		{ethTx(1), true, 2},
+		// an embedded transaction cannot be replayed
+		{embeddedTx(1), true, 2},
+		{ethTx(2), false, 3},
+		// a sequence consumed by an Ethereum transaction cannot be reused
+		{embeddedTx(2), true, 3},
+		// nonces cannot be skipped
+		{ethTx(4), true, 3},
+		{embeddedTx(4), true, 3},
+		{embeddedTx(3), false, 4},
+		{ethTx(4), false, 5},
+	}
+
+	for i, tc := range testCases {
+		_, res, abort := anteHandler(ctx, tc.tx)
+
+		if tc.expectErr {
+			require.True(t, abort, fmt.Sprintf("expected abort: test case #%d", i))
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.False(t, abort, fmt.Sprintf("unexpected abort: test case #%d: %s", i, res.Log))
+		}
+
+		acc := app.accountMapper.GetAccount(ctx, addr)
+		require.Equal(t, tc.expectedSeq, acc.GetSequence(), fmt.Sprintf("unexpected sequence: test case #%d", i))
+	}
+
+	// the public key is recorded upon the first embedded transaction
+	require.Equal(t, privKey.PubKey(), app.accountMapper.GetAccount(ctx, addr).GetPubKey())
+}
+
+func TestAnteHandlerEmbeddedUnknownSigner(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	msg := ica.NewMsgRemoteExecute(
+		"chain-a", "alice", sdk.AccAddress(privKey.PubKey().Address()), ethcmn.Address{}, sdk.ZeroInt(), nil, 1,
+	)
+
+	tx, err := types.NewEmbeddedTxBuilder(app.codec, "ethermint", msg).Sign(privKey, 0, 0).EmbeddedTx()
+	require.Nil(t, err)
+
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	_, res, abort := anteHandler(ctx, tx)
+	require.True(t, abort)
+	require.Equal(t, sdk.ErrUnknownAddress("").ABCICode(), res.Code)
+}
+
+func TestAnteHandlerEmbeddedGrant(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint", Time: 100})
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	granterKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	granteeKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	granter := sdk.AccAddress(granterKey.PubKey().Address())
+	grantee := sdk.AccAddress(granteeKey.PubKey().Address())
+	app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, granter))
+
+	send := func(privKey crypto.PrivKeySecp256k1, amount, seq int64) sdk.Tx {
+		coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, amount)}
+		msg := bank.NewMsgSend(
+			[]bank.Input{bank.NewInput(granter, coins)}, []bank.Output{bank.NewOutput(grantee, coins)},
+		)
+
+		tx, err := types.NewEmbeddedTxBuilder(app.codec, "ethermint", msg).Sign(privKey, 0, seq).EmbeddedTx()
+		require.Nil(t, err)
+
+		return tx
+	}
+
+	grant := func(ctx sdk.Context) {
+		app.authzKeeper.SetGrant(ctx, authz.Grant{
+			Granter: granter, Grantee: grantee, MsgType: "bank", Expiration: 200,
+			SpendLimit: sdk.Coins{sdk.NewCoin(types.DenomDefault, 10)},
+		})
+	}
+
+	revoke := func(ctx sdk.Context) {
+		app.authzKeeper.DeleteGrant(ctx, granter, grantee, "bank")
+	}
+
+	testCases := []struct {
+		setup        func(sdk.Context)
+		tx           sdk.Tx
+		expectedCode sdk.CodeType
+		expectedSeq  int64
+	}{
+		{nil, send(granteeKey, 4, 0), authz.CodeGrantNotFound, 0},
+		{grant, send(granteeKey, 4, 0), sdk.CodeOK, 1},
+		// the spend limit is enforced and no nonce is consumed upon failure
+		{nil, send(granteeKey, 7, 1), authz.CodeSpendLimitExceeded, 1},
+		{nil, send(granteeKey, 6, 1), sdk.CodeOK, 2},
+		// the granter may always sign for itself
+		{nil, send(granterKey, 7, 2), sdk.CodeOK, 3},
+		{grant, send(granteeKey, 1, 3), sdk.CodeOK, 4},
+		{revoke, send(granteeKey, 1, 4), authz.CodeGrantNotFound, 4},
+	}
+
+	for i, tc := range testCases {
+		if tc.setup != nil {
+			tc.setup(ctx)
+		}
+
+		_, res, abort := anteHandler(ctx, tc.tx)
+
+		if tc.expectedCode == sdk.CodeOK {
+			require.False(t, abort, fmt.Sprintf("unexpected abort: test case #%d: %s", i, res.Log))
+		} else {
+			require.True(t, abort, fmt.Sprintf("expected abort: test case #%d", i))
+			require.Equal(
+				t, sdk.ToABCICode(authz.DefaultCodespace, tc.expectedCode), res.Code,
+				fmt.Sprintf("unexpected code: test case #%d", i),
+			)
+		}
+
+		acc := app.accountMapper.GetAccount(ctx, granter)
+		require.Equal(t, tc.expectedSeq, acc.GetSequence(), fmt.Sprintf("unexpected sequence: test case #%d", i))
+	}
+
+	// the grantee's public key is never recorded as the granter's
+	require.Equal(t, granterKey.PubKey(), app.accountMapper.GetAccount(ctx, granter).GetPubKey())
+}
+
+func TestAnteHandlerCircuitBreaker(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	authorityKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	authority := sdk.AccAddress(authorityKey.PubKey().Address())
+	app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, authority))
+
+	params := circuit.Params{Authority: authority, DisabledMsgTypes: []string{types.TypeTxEthereum}}
+	require.Nil(t, circuit.InitGenesis(ctx, app.circuitKeeper, circuit.GenesisState{Params: params}))
+
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+
+	ethTx := func(nonce uint64) sdk.Tx {
+		tx := types.NewTransaction(nonce, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+		tx.Sign(big.NewInt(DefaultEthChainID), authorityKey.ToECDSA())
+		return tx
+	}
+
+	embeddedTx := func(msg sdk.Msg, seq int64) sdk.Tx {
+		tx, err := types.NewEmbeddedTxBuilder(app.codec, "ethermint", msg).Sign(authorityKey, 0, seq).EmbeddedTx()
+		require.Nil(t, err)
+
+		return tx
+	}
+
+	// Ethereum transactions are rejected without consuming a nonce
+	_, res, abort := anteHandler(ctx, ethTx(0))
+	require.True(t, abort)
+	require.Equal(t, sdk.ToABCICode(circuit.DefaultCodespace, circuit.CodeCircuitOpen), res.Code)
+	require.Equal(t, int64(0), app.accountMapper.GetAccount(ctx, authority).GetSequence())
+
+	// the authority may still reset the breaker
+	_, res, abort = anteHandler(ctx, embeddedTx(circuit.NewMsgSetDisabled(authority), 0))
+	require.False(t, abort, res.Log)
+	require.True(t, app.Router().Route(circuit.MsgType)(ctx, circuit.NewMsgSetDisabled(authority)).IsOK())
+
+	_, res, abort = anteHandler(ctx, ethTx(1))
+	require.False(t, abort, res.Log)
+}
+
+func TestAnteHandlerGasParams(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	params := evm.DefaultParams()
+	params.MaxGasPrice, params.MinGasLimit, params.MaxGasLimit = 10, 21000, 100000
+	app.evmKeeper.SetParams(ctx, params)
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	addr := sdk.AccAddress(privKey.PubKey().Address())
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+
+	ethTx := func(nonce, gas uint64, gasPrice int64) sdk.Tx {
+		tx := types.NewTransaction(nonce, to, big.NewInt(0), gas, big.NewInt(gasPrice), nil)
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+		return tx
+	}
+
+	testCases := []struct {
+		tx           sdk.Tx
+		expectedCode sdk.CodeType
+		expectedSeq  int64
+	}{
+		{ethTx(0, 21000, 10), sdk.CodeOK, 1},
+		// no nonce is consumed by a rejected transaction
+		{ethTx(1, 21000, 11), evm.CodeGasPriceTooHigh, 1},
+		{ethTx(1, 20999, 1), evm.CodeGasLimitTooLow, 1},
+		{ethTx(1, 100001, 1), evm.CodeGasLimitTooHigh, 1},
+		{ethTx(1, 100000, 1), sdk.CodeOK, 2},
+	}
+
+	for i, tc := range testCases {
+		_, res, abort := anteHandler(ctx, tc.tx)
+
+		if tc.expectedCode == sdk.CodeOK {
+			require.False(t, abort, fmt.Sprintf("unexpected abort: test case #%d: %s", i, res.Log))
+		} else {
+			require.True(t, abort, fmt.Sprintf("expected abort: test case #%d", i))
+			require.Equal(
+				t, sdk.ToABCICode(evm.DefaultCodespace, tc.expectedCode), res.Code,
+				fmt.Sprintf("unexpected code: test case #%d", i),
+			)
+		}
+
+		acc := app.accountMapper.GetAccount(ctx, addr)
+		require.Equal(t, tc.expectedSeq, acc.GetSequence(), fmt.Sprintf("unexpected sequence: test case #%d", i))
+	}
+}
+
+func TestAnteHandlerAbortReasons(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	addr := sdk.AccAddress(privKey.PubKey().Address())
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+
+	// the sender can afford the gas of a transfer but no value, in both the
+	// check and deliver states
+	for _, isCheckTx := range []bool{true, false} {
+		_, _, err := app.coinKeeper.AddCoins(
+			app.NewContext(isCheckTx, abci.Header{}), addr, sdk.Coins{sdk.NewCoin(types.DenomDefault, 21000)},
+		)
+		require.Nil(t, err)
+	}
+
+	ethTx := func(nonce uint64, value int64) sdk.Tx {
+		tx := types.NewTransaction(nonce, to, big.NewInt(value), 21000, big.NewInt(1), nil)
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+		return tx
+	}
+
+	testCases := []struct {
+		checkTx       bool
+		tx            sdk.Tx
+		expectedAbort *types.AnteAbort
+	}{
+		{true, ethTx(0, 1), &types.AnteAbort{
+			Reason: types.AbortInsufficientFunds, Nonce: 0, Required: big.NewInt(21001), Balance: big.NewInt(21000),
+		}},
+		{true, ethTx(0, 0), nil},
+		{true, ethTx(0, 0), &types.AnteAbort{Reason: types.AbortNonceTooLow, Nonce: 1}},
+		{true, ethTx(2, 0), &types.AnteAbort{Reason: types.AbortNonceTooHigh, Nonce: 1}},
+		// the cost of the first transaction is debited from the check state
+		{true, ethTx(1, 0), &types.AnteAbort{
+			Reason: types.AbortInsufficientFunds, Nonce: 1, Required: big.NewInt(21000), Balance: big.NewInt(0),
+		}},
+		// execution fails an unaffordable transaction included in a block
+		{false, ethTx(0, 1), nil},
+	}
+
+	for i, tc := range testCases {
+		_, res, abort := anteHandler(app.NewContext(tc.checkTx, abci.Header{ChainID: "ethermint"}), tc.tx)
+
+		if tc.expectedAbort == nil {
+			require.False(t, abort, fmt.Sprintf("unexpected abort: test case #%d: %s", i, res.Log))
+			continue
+		}
+
+		require.True(t, abort, fmt.Sprintf("expected abort: test case #%d", i))
+
+		decoded, ok := types.DecodeAnteAbort(res.Data)
+		require.True(t, ok, fmt.Sprintf("expected abort data: test case #%d", i))
+		require.Equal(t, *tc.expectedAbort, decoded, fmt.Sprintf("unexpected abort: test case #%d", i))
+	}
+}
+
+func TestAnteHandlerUnsignedMsgs(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+
+	addr := sdk.AccAddress(ethcmn.BytesToAddress([]byte("recipient")).Bytes())
+	coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, 1)}
+
+	faucetMsg := faucet.NewMsgRequestFunds(addr)
+	sendMsg := bank.NewMsgSend([]bank.Input{bank.NewInput(addr, coins)}, []bank.Output{bank.NewOutput(addr, coins)})
+
+	testCases := []struct {
+		unsignedMsgTypes []string
+		tx               sdk.Tx
+		expectAbort      bool
+	}{
+		{nil, auth.NewStdTx([]sdk.Msg{faucetMsg}, auth.NewStdFee(0), nil, ""), true},
+		{[]string{faucet.MsgType}, auth.NewStdTx([]sdk.Msg{faucetMsg}, auth.NewStdFee(0), nil, ""), false},
+		{[]string{faucet.MsgType}, auth.NewStdTx([]sdk.Msg{faucetMsg, sendMsg}, auth.NewStdFee(0), nil, ""), true},
+		{[]string{"bank"}, auth.NewStdTx([]sdk.Msg{sendMsg}, auth.NewStdFee(0), nil, ""), true},
+		{nil, types.EmbeddedTx{Messages: []sdk.Msg{faucetMsg}}, true},
+		{[]string{faucet.MsgType}, types.EmbeddedTx{Messages: []sdk.Msg{faucetMsg}}, false},
+	}
+
+	for i, tc := range testCases {
+		anteHandler := NewAnteHandler(
+			app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+			types.DenomDefault, app.codespace, tc.unsignedMsgTypes...,
+		)
+
+		_, res, abort := anteHandler(ctx, tc.tx)
+		if tc.expectAbort {
+			require.True(t, abort, fmt.Sprintf("expected abort: test case #%d", i))
+			require.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), res.Code)
+		} else {
+			require.False(t, abort, fmt.Sprintf("unexpected abort: test case #%d: %s", i, res.Log))
+		}
+	}
+}
+
+func TestAnteHandlerUnprotectedTx(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	anteHandler := NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, big.NewInt(DefaultEthChainID),
+		types.DenomDefault, app.codespace,
+	)
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	// a transaction that is not replay protected is rejected as EIP-155 is
+	// active from genesis
+	tx := types.NewTransaction(0, ethcmn.BytesToAddress([]byte("recipient")), big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(0), privKey.ToECDSA())
+
+	_, res, abort := anteHandler(app.NewContext(false, abci.Header{ChainID: "ethermint"}), tx)
+	require.True(t, abort)
+	require.Equal(t, types.ErrInvalidSender(app.codespace, "").ABCICode(), res.Code)
+}
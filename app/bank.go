@@ -0,0 +1,27 @@
+package app
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+)
+
+// newBankHandler returns a handler of bank transfers. As with Ethereum, an
+// address needs no account to receive funds: the account of every output
+// without one is created upon the transfer, with a nonce of zero and the
+// received balance, atomically with the transfer itself. Coins of any denom
+// are transferred, those of the EVM-native denom changing the balances seen
+// by the EVM. Only MsgSend is handled, as the SDK does not implement issuing
+// coins.
+func newBankHandler(k bank.Keeper) sdk.Handler {
+	handler := bank.NewHandler(k)
+
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		if _, ok := msg.(bank.MsgSend); !ok {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized bank message type: %T", msg)).Result()
+		}
+
+		return handler(ctx, msg)
+	}
+}
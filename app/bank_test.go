@@ -0,0 +1,141 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestBankSendCreatesAccount(t *testing.T) {
+	// the block reward creates the fee collector's account in the first block
+	chain := newTestChainWithGenesis(t, "ethermint", func(g *GenesisState) {
+		g.Mint.Params.BlockReward = sdk.Coins{sdk.NewCoin(types.DenomDefault, 10)}
+	})
+
+	aliceKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	bobKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	alice := sdk.AccAddress(aliceKey.PubKey().Address())
+	bob := sdk.AccAddress(bobKey.PubKey().Address())
+	carol := sdk.AccAddress([]byte("carol"))
+
+	// fund alice in the state the first block is delivered against
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, alice, sdk.Coins{sdk.NewCoin(types.DenomDefault, 100)})
+	require.Nil(t, sdkErr)
+
+	send := func(privKey crypto.PrivKeySecp256k1, to sdk.AccAddress, amount, accNum, seq int64) []byte {
+		from := sdk.AccAddress(privKey.PubKey().Address())
+		coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, amount)}
+		msg := bank.NewMsgSend([]bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+
+		tx, err := types.NewEmbeddedTxBuilder(chain.app.codec, "ethermint", msg).
+			Sign(privKey, accNum, seq).
+			Build(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+		require.Nil(t, err)
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	// bob's account is created by the first transfer, after alice's and the
+	// fee collector's, so that bob may spend the received funds within the
+	// same block
+	res := chain.nextBlock(send(aliceKey, bob, 60, 0, 0), send(bobKey, carol, 25, 2, 0))
+	require.True(t, res[0].IsOK(), res[0].Log)
+	require.True(t, res[1].IsOK(), res[1].Log)
+
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 40)}, chain.account(alice).GetCoins())
+
+	bobAcc := chain.account(bob)
+	require.Equal(t, int64(2), bobAcc.GetAccountNumber())
+	require.Equal(t, int64(1), bobAcc.GetSequence())
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 35)}, bobAcc.GetCoins())
+	require.IsType(t, &types.Account{}, bobAcc)
+
+	carolAcc := chain.account(carol)
+	require.Equal(t, int64(3), carolAcc.GetAccountNumber())
+	require.Equal(t, int64(0), carolAcc.GetSequence())
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 25)}, carolAcc.GetCoins())
+
+	// a failed transfer creates no account
+	dave := sdk.AccAddress([]byte("dave"))
+
+	res = chain.nextBlock(send(aliceKey, dave, 1000, 0, 1))
+	require.Equal(t, sdk.ErrInsufficientCoins("").ABCICode(), sdk.ABCICodeType(res[0].Code))
+	require.Nil(t, chain.account(dave))
+}
+
+func TestBankHandlerUnknownMsg(t *testing.T) {
+	app := newTestChain(t, "ethermint").app
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+
+	msg := bank.MsgIssue{Banker: sdk.AccAddress([]byte("banker"))}
+
+	res := newBankHandler(app.coinKeeper.Keeper)(ctx, msg)
+	require.Equal(t, sdk.ErrUnknownRequest("").ABCICode(), res.Code)
+}
+
+func TestBankSendEVMDenom(t *testing.T) {
+	chain := newTestChain(t, "ethermint", SetEVMDenom("aatom"))
+	require.Equal(t, "aatom", chain.app.coinKeeper.EVMDenom())
+
+	aliceKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	alice := sdk.AccAddress(aliceKey.PubKey().Address())
+	bobAddr := ethcmn.BytesToAddress([]byte("bob"))
+	bob := sdk.AccAddress(bobAddr.Bytes())
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, alice, sdk.Coins{sdk.NewCoin("aatom", 100000), sdk.NewCoin(types.DenomDefault, 100)})
+	require.Nil(t, sdkErr)
+
+	// an Ethereum transaction transfers and pays for its gas in the EVM-native
+	// denom only
+	tx := types.NewTransaction(0, bobAddr, big.NewInt(1000), 21000, big.NewInt(2), nil)
+	tx.Sign(big.NewInt(DefaultEthChainID), aliceKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	res := chain.nextBlock(txBytes)
+	require.True(t, res[0].IsOK(), res[0].Log)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("aatom", 100000-1000-42000), sdk.NewCoin(types.DenomDefault, 100)}, chain.account(alice).GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("aatom", 1000)}, chain.account(bob).GetCoins())
+
+	// an embedded MsgSend transfers any denom, leaving the EVM balances of
+	// other denoms untouched
+	coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, 60)}
+	msg := bank.NewMsgSend([]bank.Input{bank.NewInput(alice, coins)}, []bank.Output{bank.NewOutput(bob, coins)})
+
+	embeddedTx, err := types.NewEmbeddedTxBuilder(chain.app.codec, "ethermint", msg).
+		Sign(aliceKey, 0, 1).
+		Build(big.NewInt(DefaultEthChainID), aliceKey.ToECDSA())
+	require.Nil(t, err)
+
+	txBytes, err = rlp.EncodeToBytes(embeddedTx)
+	require.Nil(t, err)
+
+	res = chain.nextBlock(txBytes)
+	require.True(t, res[0].IsOK(), res[0].Log)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("aatom", 100000-1000-42000), sdk.NewCoin(types.DenomDefault, 40)}, chain.account(alice).GetCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("aatom", 1000), sdk.NewCoin(types.DenomDefault, 60)}, chain.account(bob).GetCoins())
+
+	ctx = chain.app.NewContext(true, abci.Header{ChainID: "ethermint"})
+	require.Equal(t, big.NewInt(1000), chain.app.coinKeeper.GetEVMBalance(ctx, bob))
+}
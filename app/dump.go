@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DumpState returns the complete Ethereum state held in the application's
+// multi-store as of the given committed height, or the latest one if zero, in
+// geth's state dump format: the balance in the EVM-native denom, nonce, code
+// and storage of every account. The dump's root is the application hash of
+// the height, while the storage root of an account is left empty as contract
+// storage is not held in a trie.
+func (app *EthermintApp) DumpState(height int64) (ethstate.Dump, error) {
+	ctx, err := app.NewQueryContext(height, false)
+	if err != nil {
+		return ethstate.Dump{}, err
+	}
+
+	ms, err := app.queryMultiStore(ctx.BlockHeight(), app.LastBlockHeight())
+	if err != nil {
+		return ethstate.Dump{}, err
+	}
+
+	dump := ethstate.Dump{
+		Root:     fmt.Sprintf("%x", ms.LastCommitID().Hash),
+		Accounts: make(map[string]ethstate.DumpAccount),
+	}
+
+	code := db.NewCodeMapper(app.keyCode)
+	storage := db.NewStorageMapper(app.keyStorage)
+
+	types.IterateAccounts(ctx, app.codec, app.keyAccount, nil, func(acc auth.Account) bool {
+		addr := ethcmn.BytesToAddress(acc.GetAddress())
+
+		account := ethstate.DumpAccount{
+			Balance:  types.EVMBalance(acc.GetCoins(), app.coinKeeper.EVMDenom()).String(),
+			Nonce:    uint64(acc.GetSequence()),
+			CodeHash: ethcmn.Bytes2Hex(ethcrypto.Keccak256(nil)),
+			Storage:  make(map[string]string),
+		}
+
+		if ethermintAcc, ok := acc.(*types.Account); ok && len(ethermintAcc.CodeHash) != 0 {
+			account.CodeHash = ethcmn.Bytes2Hex(ethermintAcc.CodeHash)
+			account.Code = ethcmn.Bytes2Hex(code.GetAccountCode(ctx, ethermintAcc))
+		}
+
+		// storage values are RLP encoded as in an Ethereum storage trie
+		storage.IterateStorage(ctx, addr, func(slot, value ethcmn.Hash) bool {
+			bz, _ := rlp.EncodeToBytes(bytes.TrimLeft(value[:], "\x00"))
+			account.Storage[ethcmn.Bytes2Hex(slot[:])] = ethcmn.Bytes2Hex(bz)
+
+			return false
+		})
+
+		dump.Accounts[ethcmn.Bytes2Hex(addr[:])] = account
+		return false
+	})
+
+	return dump, nil
+}
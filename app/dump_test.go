@@ -0,0 +1,72 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpState(t *testing.T) {
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	code := []byte{0x60, 0x00, 0x80, 0xfd}
+	slot := ethcmn.BigToHash(big.NewInt(1))
+
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	chain := newAllocChain(t,
+		GenesisAccount{Address: sender, Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100000))},
+		GenesisAccount{
+			Address: addr,
+			Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100)),
+			Nonce:   2,
+			Code:    code,
+			Storage: map[string]string{slot.Hex(): ethcmn.BigToHash(big.NewInt(42)).Hex()},
+		},
+	)
+
+	dump, err := chain.app.DumpState(0)
+	require.Nil(t, err)
+	require.Equal(t, ethcmn.Bytes2Hex(chain.header.AppHash), dump.Root)
+
+	account, ok := dump.Accounts[ethcmn.Bytes2Hex(addr.Bytes())]
+	require.True(t, ok)
+	require.Equal(t, "100", account.Balance)
+	require.Equal(t, uint64(2), account.Nonce)
+	require.Equal(t, ethcmn.Bytes2Hex(ethcrypto.Keccak256(code)), account.CodeHash)
+	require.Equal(t, ethcmn.Bytes2Hex(code), account.Code)
+	require.Equal(t, map[string]string{ethcmn.Bytes2Hex(slot.Bytes()): "2a"}, account.Storage)
+
+	// the dump reflects the state committed by transactions, as of the height
+	// requested
+	recipient := ethcmn.BytesToAddress([]byte("recipient"))
+
+	tx := types.NewTransaction(0, recipient, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(DefaultEthChainID), priv)
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	res := chain.nextBlock(txBytes)[0]
+	require.True(t, res.IsOK(), res.Log)
+
+	dump, err = chain.app.DumpState(0)
+	require.Nil(t, err)
+	require.Equal(t, "1", dump.Accounts[ethcmn.Bytes2Hex(recipient.Bytes())].Balance)
+
+	dump, err = chain.app.DumpState(1)
+	require.Nil(t, err)
+	require.NotContains(t, dump.Accounts, ethcmn.Bytes2Hex(recipient.Bytes()))
+
+	_, err = chain.app.DumpState(3)
+	require.NotNil(t, err)
+}
@@ -1,43 +1,584 @@
 package app
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
 	bam "github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/state"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/addrmap"
+	"github.com/cosmos/ethermint/x/authz"
+	ethbank "github.com/cosmos/ethermint/x/bank"
+	"github.com/cosmos/ethermint/x/blockhash"
+	"github.com/cosmos/ethermint/x/circuit"
+	"github.com/cosmos/ethermint/x/denom"
+	"github.com/cosmos/ethermint/x/evm"
+	"github.com/cosmos/ethermint/x/faucet"
+	"github.com/cosmos/ethermint/x/ica"
+	"github.com/cosmos/ethermint/x/mint"
+	"github.com/cosmos/ethermint/x/withdraw"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
 const (
 	appName = "Ethermint"
+
+	// DefaultEthChainID is the default chain ID Ethereum transactions must be
+	// signed for.
+	DefaultEthChainID = 1
+
+	// AccountStoreName is the name of the store accounts are persisted in,
+	// keyed by auth.AddressStoreKey.
+	AccountStoreName = "acc"
+
+	// Pruning strategies of the application's multi-store. Syncable is the
+	// default strategy of the Cosmos SDK.
+	PruningNothing    = "nothing"
+	PruningEverything = "everything"
+	PruningSyncable   = "syncable"
+
+	// syncableRecentVersions is the number of recent versions retained by the
+	// Cosmos SDK's syncable pruning strategy.
+	syncableRecentVersions = 100
 )
 
+// precompileAddresses are the addresses of the stateful precompiled contracts
+// returned by Precompiles, reserved for them by core.RegisterPrecompile.
+var precompileAddresses = []ethcmn.Address{denom.PrecompileAddress, core.ChainInfoPrecompileAddress}
+
+func init() {
+	for _, addr := range precompileAddresses {
+		core.RegisterPrecompile(addr)
+	}
+}
+
 // EthermintApp implements an extended ABCI application.
 type EthermintApp struct {
 	*bam.BaseApp
 
-	codec  *wire.Codec
-	sealed bool
+	codec      *wire.Codec
+	db         dbm.DB
+	sealed     bool
+	ethChainID *big.Int
+	pruning    string
+	codespace  sdk.CodespaceType
 
-	// TODO: stores and keys
+	txHooks         core.TxHooks
+	beginBlockers   []sdk.BeginBlocker
+	faucetEnabled   bool
+	legacyAddresses bool
+	transferTags    bool
+	txIndexDisabled bool
+	icaExecutor     ica.Executor
+	txTTLCache      *txTTLCache
+	precheckConfig  PrecheckConfig
+	precheckMetrics *PrecheckMetrics
+	stateDiffs      *stateDiffCache
+	profiler        *blockProfiler
+	panicReports    *panicReports
+	eventWAL        *eventWAL
+	queryStores     *queryStoreCache
 
-	// TODO: keepers
+	// additional keys registered by options to be mounted
+	storeKeys []*sdk.KVStoreKey
 
-	// TODO: mappers
+	// keys to access the substores
+	keyMain      *sdk.KVStoreKey
+	keyAccount   *sdk.KVStoreKey
+	keyStake     *sdk.KVStoreKey
+	keySlashing  *sdk.KVStoreKey
+	keyMint      *sdk.KVStoreKey
+	keyFaucet    *sdk.KVStoreKey
+	keyICA       *sdk.KVStoreKey
+	keyBlockHash *sdk.KVStoreKey
+	keyDenom     *sdk.KVStoreKey
+	keyAuthz     *sdk.KVStoreKey
+	keyCircuit   *sdk.KVStoreKey
+	keyEVM       *sdk.KVStoreKey
+	keyAddrMap   *sdk.KVStoreKey
+	keyWithdraw  *sdk.KVStoreKey
+	keyStorage   *sdk.KVStoreKey
+	keyCode      *sdk.KVStoreKey
+
+	// mappers and keepers
+	accountMapper   auth.AccountMapper
+	coinKeeper      ethbank.Keeper
+	stakeKeeper     stake.Keeper
+	slashingKeeper  slashing.Keeper
+	mintKeeper      mint.Keeper
+	faucetKeeper    faucet.Keeper
+	icaKeeper       ica.Keeper
+	blockHashKeeper blockhash.Keeper
+	denomKeeper     denom.Keeper
+	authzKeeper     authz.Keeper
+	circuitKeeper   circuit.Keeper
+	evmKeeper       evm.Keeper
+	addrMapKeeper   addrmap.Keeper
+	withdrawKeeper  withdraw.Keeper
 }
 
 // NewEthermintApp returns a reference to a new initialized Ethermint
 // application.
-func NewEthermintApp(opts ...func(*EthermintApp)) *EthermintApp {
-	app := &EthermintApp{}
+func NewEthermintApp(logger log.Logger, db dbm.DB, opts ...func(*EthermintApp)) *EthermintApp {
+	codec := MakeCodec()
+
+	app := &EthermintApp{
+		BaseApp:      bam.NewBaseApp(appName, codec, logger, db),
+		codec:        codec,
+		db:           db,
+		ethChainID:   big.NewInt(DefaultEthChainID),
+		pruning:      PruningSyncable,
+		keyMain:      sdk.NewKVStoreKey("main"),
+		keyAccount:   sdk.NewKVStoreKey(AccountStoreName),
+		keyStake:     sdk.NewKVStoreKey("stake"),
+		keySlashing:  sdk.NewKVStoreKey("slashing"),
+		keyMint:      sdk.NewKVStoreKey("mint"),
+		keyFaucet:    sdk.NewKVStoreKey(faucet.StoreName),
+		keyICA:       sdk.NewKVStoreKey(ica.StoreName),
+		keyBlockHash: sdk.NewKVStoreKey(blockhash.StoreName),
+		keyDenom:     sdk.NewKVStoreKey(denom.StoreName),
+		keyAuthz:     sdk.NewKVStoreKey(authz.StoreName),
+		keyCircuit:   sdk.NewKVStoreKey(circuit.StoreName),
+		keyEVM:       sdk.NewKVStoreKey(evm.StoreName),
+		keyAddrMap:   sdk.NewKVStoreKey(addrmap.StoreName),
+		keyWithdraw:  sdk.NewKVStoreKey(withdraw.StoreName),
+		keyStorage:   sdk.NewKVStoreKey(state.StorageKey.Name()),
+		keyCode:      sdk.NewKVStoreKey(state.CodeKey.Name()),
+
+		precheckConfig:  DefaultPrecheckConfig(),
+		precheckMetrics: NopPrecheckMetrics(),
+		queryStores:     &queryStoreCache{},
+	}
+
+	// the bank module raises its errors in its default codespace, which is
+	// reserved so that no other codespace is registered over it
+	app.RegisterCodespace(bank.DefaultCodespace)
+	app.codespace = app.RegisterCodespace(types.DefaultCodespace)
 
-	// TODO: implement constructor
+	app.accountMapper = auth.NewAccountMapper(app.codec, app.keyAccount, types.ProtoAccount)
+	app.coinKeeper = ethbank.NewKeeper(app.accountMapper, types.DenomDefault)
+	app.stakeKeeper = stake.NewKeeper(
+		app.codec, app.keyStake, app.coinKeeper.Keeper, app.RegisterCodespace(stake.DefaultCodespace),
+	)
+	app.slashingKeeper = slashing.NewKeeper(
+		app.codec, app.keySlashing, app.stakeKeeper, app.RegisterCodespace(slashing.DefaultCodespace),
+	)
+	app.mintKeeper = mint.NewKeeper(app.codec, app.keyMint, app.coinKeeper.Keeper)
+	app.blockHashKeeper = blockhash.NewKeeper(app.codec, app.keyBlockHash)
+	app.denomKeeper = denom.NewKeeper(app.codec, app.keyDenom)
+	app.evmKeeper = evm.NewKeeper(app.codec, app.keyEVM, app.RegisterCodespace(evm.DefaultCodespace))
+	app.authzKeeper = authz.NewKeeper(app.codec, app.keyAuthz, app.RegisterCodespace(authz.DefaultCodespace))
+	app.circuitKeeper = circuit.NewKeeper(
+		app.codec, app.keyCircuit, app.RegisterCodespace(circuit.DefaultCodespace),
+	)
+	app.faucetKeeper = faucet.NewKeeper(
+		app.codec, app.keyFaucet, app.coinKeeper.Keeper, app.RegisterCodespace(faucet.DefaultCodespace),
+	)
+	app.addrMapKeeper = addrmap.NewKeeper(
+		app.keyAddrMap, app.coinKeeper.Keeper, app.RegisterCodespace(addrmap.DefaultCodespace),
+	)
+	app.withdrawKeeper = withdraw.NewKeeper(
+		app.keyWithdraw, app.stakeKeeper, app.RegisterCodespace(withdraw.DefaultCodespace),
+	)
+
+	app.Router().
+		AddRoute("bank", meterMsgGas(app.recoverMsgPanics(newBankHandler(app.coinKeeper.Keeper)))).
+		AddRoute("stake", meterMsgGas(app.recoverMsgPanics(stake.NewHandler(app.stakeKeeper)))).
+		AddRoute("slashing", meterMsgGas(app.recoverMsgPanics(slashing.NewHandler(app.slashingKeeper)))).
+		AddRoute("authz", meterMsgGas(app.recoverMsgPanics(authz.NewHandler(app.authzKeeper)))).
+		AddRoute(circuit.MsgType, meterMsgGas(app.recoverMsgPanics(circuit.NewHandler(app.circuitKeeper)))).
+		AddRoute(evm.MsgType, meterMsgGas(app.recoverMsgPanics(evm.NewHandler(app.evmKeeper)))).
+		AddRoute(withdraw.MsgType, meterMsgGas(app.recoverMsgPanics(withdraw.NewHandler(app.withdrawKeeper)))).
+		AddRoute(types.TypeTxEthereum, meterMsgGas(app.recoverMsgPanics(app.executeEthTx)))
+
+	// evidence of validator misbehavior must be handled prior to any other
+	// module's BeginBlocker
+	app.beginBlockers = append(
+		app.beginBlockers, app.slashingBeginBlocker, app.mintBeginBlocker, app.blockHashBeginBlocker,
+	)
 
 	for _, opt := range opts {
 		opt(app)
 	}
 
+	if app.faucetEnabled {
+		app.Router().AddRoute(faucet.MsgType, meterMsgGas(app.recoverMsgPanics(faucet.NewHandler(app.faucetKeeper))))
+	}
+
+	if app.legacyAddresses {
+		app.Router().AddRoute(
+			addrmap.MsgType, meterMsgGas(app.recoverMsgPanics(addrmap.NewHandler(app.addrMapKeeper))),
+		)
+	}
+
+	if app.icaExecutor != nil {
+		app.icaKeeper = ica.NewKeeper(
+			app.codec, app.keyICA, app.icaExecutor, app.RegisterCodespace(ica.DefaultCodespace),
+		)
+		app.Router().AddRoute("ica", meterMsgGas(app.recoverMsgPanics(ica.NewHandler(app.icaKeeper))))
+	}
+
+	var unsignedMsgTypes []string
+	if app.faucetEnabled {
+		unsignedMsgTypes = append(unsignedMsgTypes, faucet.MsgType)
+	}
+
+	app.SetTxDecoder(types.TxDecoder(app.codec))
+	app.SetAnteHandler(NewAnteHandler(
+		app.accountMapper, app.authzKeeper, app.circuitKeeper, app.evmKeeper, app.ethChainID,
+		app.coinKeeper.EVMDenom(), app.codespace, unsignedMsgTypes...,
+	))
+	app.SetInitChainer(app.InitChainer)
+	app.SetBeginBlocker(app.BeginBlocker)
+	app.SetEndBlocker(app.EndBlocker)
+	app.MountStoresIAVL(app.allStoreKeys()...)
+
+	if err := app.LoadLatestVersion(app.keyMain); err != nil {
+		cmn.Exit(err.Error())
+	}
+
 	app.seal()
 	return app
 }
 
+// MakeCodec returns a codec with all the modules' concrete types registered.
+func MakeCodec() *wire.Codec {
+	codec := wire.NewCodec()
+
+	bank.RegisterWire(codec)
+	stake.RegisterWire(codec)
+	slashing.RegisterWire(codec)
+	faucet.RegisterWire(codec)
+	ica.RegisterWire(codec)
+	authz.RegisterWire(codec)
+	circuit.RegisterWire(codec)
+	evm.RegisterWire(codec)
+	addrmap.RegisterWire(codec)
+	withdraw.RegisterWire(codec)
+	auth.RegisterWire(codec)
+	types.RegisterWire(codec)
+	sdk.RegisterWire(codec)
+	wire.RegisterCrypto(codec)
+	crypto.RegisterWire(codec)
+
+	return codec
+}
+
+// SetTxHooks returns an option that registers a set of hooks to be executed,
+// in order, for every Ethereum transaction prior to EVM execution. It panics
+// if the application is already sealed.
+func SetTxHooks(hooks ...core.TxHook) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetTxHooks() on sealed EthermintApp")
+		}
+
+		app.txHooks = append(app.txHooks, hooks...)
+	}
+}
+
+// SetEthChainID returns an option that sets the chain ID Ethereum transactions
+// must be signed for. It panics if the application is already sealed.
+func SetEthChainID(chainID *big.Int) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetEthChainID() on sealed EthermintApp")
+		}
+
+		app.ethChainID = chainID
+	}
+}
+
+// SetEVMDenom returns an option that designates the given denom, in place of
+// types.DenomDefault, as the EVM-native denom: the denom of the balances the
+// EVM reads and writes, of the value of Ethereum transactions and of their
+// gas. Coins of any other denom are only transferred by bank messages. The
+// denom's metadata should be registered with the denom module at genesis. It
+// panics if the denom is invalid or if the application is already sealed.
+func SetEVMDenom(denom string) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetEVMDenom() on sealed EthermintApp")
+		}
+
+		app.coinKeeper = app.coinKeeper.WithEVMDenom(denom)
+	}
+}
+
+// SetFeeCollector returns an option that sets the address of the account
+// collecting block rewards, overriding mint.DefaultFeeCollectorAddr. It panics
+// if the application is already sealed.
+func SetFeeCollector(addr sdk.AccAddress) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetFeeCollector() on sealed EthermintApp")
+		}
+
+		app.mintKeeper = app.mintKeeper.WithFeeCollector(addr)
+	}
+}
+
+// SetPruning returns an option that sets the pruning strategy of the
+// application's multi-store, one of PruningNothing, PruningEverything and
+// PruningSyncable. It panics if the strategy is invalid or if the application
+// is already sealed.
+func SetPruning(strategy string) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetPruning() on sealed EthermintApp")
+		}
+
+		bam.SetPruning(strategy)(app.BaseApp)
+		app.pruning = strategy
+	}
+}
+
+// EnableFaucet returns an option that enables the faucet module, allowing any
+// address to claim a capped amount of tokens per period out of the account at
+// faucet.FaucetAddr, which must be funded, e.g. at genesis. As a faucet claim
+// carries no signers, its message type is accepted by the ante handler as an
+// unsigned message type. It is intended for testnets only. It panics if the
+// application is already sealed.
+func EnableFaucet() func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableFaucet() on sealed EthermintApp")
+		}
+
+		app.faucetEnabled = true
+	}
+}
+
+// EnableLegacyAddresses returns an option that enables the address map module,
+// allowing users migrating from a vanilla Cosmos chain to link the legacy
+// address their key derives under the Cosmos sha256 based scheme to the
+// address it derives under the Ethereum keccak based scheme, moving the
+// balances of the former to the latter. It panics if the application is
+// already sealed.
+func EnableLegacyAddresses() func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableLegacyAddresses() on sealed EthermintApp")
+		}
+
+		app.legacyAddresses = true
+	}
+}
+
+// EnableInternalTransferTags returns an option that enables tagging the result
+// of every executed Ethereum transaction with the internal value transfers
+// made by its execution (see core.TagInternalFrom), allowing them to be
+// searched for. As they can only be recorded by tracing every opcode the EVM
+// executes, enabling the tags slows down the execution of every transaction
+// of every block. It panics if the application is already sealed.
+func EnableInternalTransferTags() func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableInternalTransferTags() on sealed EthermintApp")
+		}
+
+		app.transferTags = true
+	}
+}
+
+// EnableRemoteExecution returns an option that enables the interchain accounts
+// module, allowing authorized controllers to execute EVM calls on behalf of
+// their owners' derived accounts using the given Executor. It panics if the
+// application is already sealed.
+func EnableRemoteExecution(executor ica.Executor) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableRemoteExecution() on sealed EthermintApp")
+		}
+
+		app.icaExecutor = executor
+	}
+}
+
+// RegisterStoreKeys returns an option that registers additional KVStore keys
+// to be mounted by the application, allowing plugin modules to persist state.
+// All keys are mounted prior to loading the latest version of the
+// application's state. It panics if the application is already sealed or if a
+// key's name is already in use.
+func RegisterStoreKeys(keys ...*sdk.KVStoreKey) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("RegisterStoreKeys() on sealed EthermintApp")
+		}
+
+		for _, key := range keys {
+			if app.storeKeyRegistered(key.Name()) {
+				panic(fmt.Sprintf("store key with name %s already registered", key.Name()))
+			}
+
+			app.storeKeys = append(app.storeKeys, key)
+		}
+	}
+}
+
+// GetHashFn returns the function backing the EVM's BLOCKHASH opcode for
+// transactions executed within the given context's block.
+func (app *EthermintApp) GetHashFn(ctx sdk.Context) ethvm.GetHashFunc {
+	return app.blockHashKeeper.GetHashFn(ctx)
+}
+
+// BlockTime returns the value of the EVM's TIMESTAMP opcode for transactions
+// executed within the given context's block.
+func (app *EthermintApp) BlockTime(ctx sdk.Context) int64 {
+	return app.blockHashKeeper.GetBlockTime(ctx)
+}
+
+// DisabledOpcodes returns the opcodes the EVM refuses to execute for
+// transactions executed within the given context's block.
+func (app *EthermintApp) DisabledOpcodes(ctx sdk.Context) []ethvm.OpCode {
+	return app.evmKeeper.DisabledOpcodes(ctx)
+}
+
+// Precompiles returns the stateful precompiled contracts available to
+// transactions executed within the given context, keyed by address. They are
+// bound to the EVM by core.WithPrecompiles.
+func (app *EthermintApp) Precompiles(ctx sdk.Context) map[ethcmn.Address]ethvm.PrecompiledContract {
+	return map[ethcmn.Address]ethvm.PrecompiledContract{
+		denom.PrecompileAddress:         denom.NewPrecompile(ctx, app.denomKeeper),
+		core.ChainInfoPrecompileAddress: core.NewChainInfoPrecompile(ctx, app.stakeKeeper),
+	}
+}
+
+// TxHooks returns the hooks registered to run prior to EVM execution.
+func (app *EthermintApp) TxHooks() core.TxHooks {
+	return app.txHooks
+}
+
+// Codespace returns the codespace registered for Ethermint's own errors upon
+// construction. It is the first codespace available from
+// types.DefaultCodespace on, so that it never collides with the codespace of
+// a module, including a module added by an option, which registers its
+// codespace through RegisterCodespace.
+func (app *EthermintApp) Codespace() sdk.CodespaceType {
+	return app.codespace
+}
+
+// SetBeginBlockers returns an option that registers a set of module
+// BeginBlockers. Every BeginBlocker is executed at the start of each block in
+// the order in which it was registered. It panics if the application is
+// already sealed.
+func SetBeginBlockers(beginBlockers ...sdk.BeginBlocker) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetBeginBlockers() on sealed EthermintApp")
+		}
+
+		app.beginBlockers = append(app.beginBlockers, beginBlockers...)
+	}
+}
+
+// InitChainer implements the sdk.InitChainer type. It validates the
+// GenesisState held by the application state of the genesis file, any field
+// it omits taking its default value, and applies it: the Ethereum accounts it
+// allocates are created and the state of every module is initialized. It
+// panics if the genesis state cannot be decoded, is invalid or is for another
+// Ethereum chain ID.
+//
+// NOTE: The slashing module holds no genesis state: the signing info of a
+// validator is created once it first signs a block.
+func (app *EthermintApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+	genesis := DefaultGenesisState(app.ethChainID)
+	if len(req.AppStateBytes) != 0 {
+		if err := json.Unmarshal(req.AppStateBytes, &genesis); err != nil {
+			panic(fmt.Sprintf("invalid application state: %v", err))
+		}
+	}
+
+	if err := app.initGenesis(ctx, genesis); err != nil {
+		panic(err.Error())
+	}
+
+	return abci.ResponseInitChain{}
+}
+
+// BeginBlocker implements the sdk.BeginBlocker type. It executes all the
+// registered module BeginBlockers in order and returns a response containing
+// the union of all their tags.
+func (app *EthermintApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	var res abci.ResponseBeginBlock
+
+	for _, beginBlocker := range app.beginBlockers {
+		moduleRes := beginBlocker(ctx, req)
+		res.Tags = append(res.Tags, moduleRes.Tags...)
+	}
+
+	return res
+}
+
+// EndBlocker implements the sdk.EndBlocker type. It returns any validator set
+// updates, including the removal of validators revoked due to misbehavior.
+func (app *EthermintApp) EndBlocker(ctx sdk.Context, _ abci.RequestEndBlock) abci.ResponseEndBlock {
+	return abci.ResponseEndBlock{
+		ValidatorUpdates: stake.EndBlocker(ctx, app.stakeKeeper),
+	}
+}
+
+// slashingBeginBlocker handles any evidence of validator misbehavior (e.g.
+// double signing) along with validator liveness by jailing and slashing the
+// bonded stake of the offending validators.
+func (app *EthermintApp) slashingBeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	tags := slashing.BeginBlocker(ctx, req, app.slashingKeeper)
+	return abci.ResponseBeginBlock{Tags: tags.ToKVPairs()}
+}
+
+// mintBeginBlocker mints the block reward into the fee collector account.
+func (app *EthermintApp) mintBeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	tags := mint.BeginBlocker(ctx, req, app.mintKeeper)
+	return abci.ResponseBeginBlock{Tags: tags.ToKVPairs()}
+}
+
+// blockHashBeginBlocker records the hash of the previous block and the time of
+// the current block, backing the EVM's BLOCKHASH and TIMESTAMP opcodes.
+func (app *EthermintApp) blockHashBeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	tags := blockhash.BeginBlocker(ctx, req, app.blockHashKeeper)
+	return abci.ResponseBeginBlock{Tags: tags.ToKVPairs()}
+}
+
+// allStoreKeys returns all the store keys to be mounted, including the keys
+// used by the application itself and the keys registered by options.
+func (app *EthermintApp) allStoreKeys() []*sdk.KVStoreKey {
+	keys := []*sdk.KVStoreKey{
+		app.keyMain, app.keyAccount, app.keyStake, app.keySlashing, app.keyMint, app.keyFaucet,
+		app.keyICA, app.keyBlockHash, app.keyDenom, app.keyAuthz, app.keyCircuit, app.keyEVM,
+		app.keyAddrMap, app.keyWithdraw, app.keyStorage, app.keyCode,
+	}
+
+	return append(keys, app.storeKeys...)
+}
+
+// storeKeyRegistered returns true if a store key with the given name is either
+// used by the application itself or registered by an option.
+func (app *EthermintApp) storeKeyRegistered(name string) bool {
+	for _, key := range app.allStoreKeys() {
+		if key.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // seal seals the Ethermint application and prohibits any future modifications
 // that change critical components.
 func (app *EthermintApp) seal() {
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/addrmap"
+	"github.com/cosmos/ethermint/x/ica"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func newTestBeginBlocker(name string, order *[]string) sdk.BeginBlocker {
+	return func(_ sdk.Context, _ abci.RequestBeginBlock) abci.ResponseBeginBlock {
+		*order = append(*order, name)
+		return abci.ResponseBeginBlock{Tags: sdk.NewTags("module", []byte(name))}
+	}
+}
+
+func TestBeginBlockers(t *testing.T) {
+	var order []string
+
+	app := NewEthermintApp(
+		log.NewNopLogger(), dbm.NewMemDB(),
+		SetBeginBlockers(newTestBeginBlocker("feemarket", &order)),
+		SetBeginBlockers(newTestBeginBlocker("slashing", &order), newTestBeginBlocker("oracle", &order)),
+	)
+
+	app.InitChain(abci.RequestInitChain{})
+	ctx := app.NewContext(false, abci.Header{})
+	res := app.BeginBlocker(ctx, abci.RequestBeginBlock{})
+
+	expected := []string{"feemarket", "slashing", "oracle"}
+	require.Equal(t, expected, order)
+
+	// the built-in modules' BeginBlockers always run first
+	require.Equal(t, []byte("height"), res.Tags[0].Key)
+
+	moduleTags := res.Tags[len(res.Tags)-len(expected):]
+	for i, name := range expected {
+		require.Equal(t, []byte(name), moduleTags[i].Value, fmt.Sprintf("unexpected tag: test case #%d", i))
+	}
+}
+
+func TestSetBeginBlockersSealed(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+
+	require.Panics(t, func() {
+		SetBeginBlockers(newTestBeginBlocker("test", new([]string)))(app)
+	})
+}
+
+func TestRegisterStoreKeys(t *testing.T) {
+	keyPlugin := sdk.NewKVStoreKey("plugin")
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), RegisterStoreKeys(keyPlugin))
+
+	app.InitChain(abci.RequestInitChain{})
+	ctx := app.NewContext(false, abci.Header{})
+
+	require.NotPanics(t, func() { ctx.KVStore(keyPlugin).Set([]byte("key"), []byte("value")) })
+	require.Equal(t, []byte("value"), ctx.KVStore(keyPlugin).Get([]byte("key")))
+
+	// keys cannot be registered once sealed
+	require.Panics(t, func() { RegisterStoreKeys(sdk.NewKVStoreKey("other"))(app) })
+
+	// key names must be unique
+	require.Panics(t, func() {
+		NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), RegisterStoreKeys(sdk.NewKVStoreKey("acc")))
+	})
+}
+
+func TestCodespace(t *testing.T) {
+	var hostCodespace sdk.CodespaceType
+
+	// a module added by the host registers its codespace through an option
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), func(app *EthermintApp) {
+		hostCodespace = app.RegisterCodespace(types.DefaultCodespace)
+	})
+
+	reserved := []sdk.CodespaceType{
+		sdk.CodespaceRoot, bank.DefaultCodespace, app.stakeKeeper.Codespace(), app.withdrawKeeper.Codespace(),
+		hostCodespace,
+	}
+
+	for i, codespace := range reserved {
+		require.NotEqual(t, codespace, app.Codespace(), fmt.Sprintf("unexpected codespace: test case #%d", i))
+	}
+
+	// the same codespace is registered by every application
+	require.Equal(t, app.Codespace(), NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB()).Codespace())
+}
+
+func TestEnableRemoteExecution(t *testing.T) {
+	executor := func(_ sdk.Context, _ ica.Call) (types.ResultData, error) { return types.ResultData{}, nil }
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	require.Nil(t, app.Router().Route("ica"))
+
+	app = NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), EnableRemoteExecution(executor))
+	require.NotNil(t, app.Router().Route("ica"))
+
+	require.Panics(t, func() { EnableRemoteExecution(executor)(app) })
+}
+
+func TestEnableLegacyAddresses(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	require.Nil(t, app.Router().Route(addrmap.MsgType))
+
+	app = NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), EnableLegacyAddresses())
+	require.NotNil(t, app.Router().Route(addrmap.MsgType))
+
+	require.Panics(t, func() { EnableLegacyAddresses()(app) })
+}
+
+func TestGetHashFn(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	lastBlockHash := []byte{0x01, 0x02, 0x03}
+	header := abci.Header{Height: 5, LastBlockHash: lastBlockHash}
+
+	ctx := app.NewContext(false, header)
+	app.BeginBlocker(ctx, abci.RequestBeginBlock{Header: header})
+
+	getHash := app.GetHashFn(ctx)
+	require.Equal(t, ethcmn.BytesToHash(lastBlockHash), getHash(4))
+	require.Equal(t, ethcmn.Hash{}, getHash(3))
+	require.Equal(t, ethcmn.Hash{}, getHash(5))
+}
+
+func TestEncryptedDB(t *testing.T) {
+	memDB := dbm.NewMemDB()
+	key := func() ([]byte, error) { return make([]byte, db.KeySize), nil }
+
+	encDB, err := db.NewEncryptedDB(memDB, key)
+	require.Nil(t, err)
+
+	app := NewEthermintApp(log.NewNopLogger(), encDB)
+	app.InitChain(abci.RequestInitChain{})
+
+	addr := sdk.AccAddress([]byte("test_account"))
+	ctx := app.NewContext(false, abci.Header{})
+	app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, addr))
+
+	commitID := app.Commit()
+
+	// the state is reloaded from the encrypted database
+	encDB, err = db.NewEncryptedDB(memDB, key)
+	require.Nil(t, err)
+
+	app = NewEthermintApp(log.NewNopLogger(), encDB)
+	require.Equal(t, commitID.Data, app.LastCommitID().Hash)
+
+	res := app.Query(abci.RequestQuery{Path: "/store/acc/key", Data: auth.AddressStoreKey(addr)})
+	require.True(t, res.IsOK())
+	require.NotEmpty(t, res.Value)
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+var (
+	walEventsPrefix = []byte("events/")
+	walAckedKey     = []byte("acked")
+)
+
+type (
+	// EventSink defines an external indexer of the events, i.e. tags, emitted
+	// by every committed block, e.g. writing them to a database.
+	EventSink interface {
+		// IndexBlock indexes the events of a block. A block is acknowledged
+		// once indexed without error. As a block that was not acknowledged,
+		// e.g. due to a crash, is indexed again, indexing must be idempotent.
+		IndexBlock(events BlockEvents) error
+	}
+
+	// BlockEvents defines the events emitted while executing a block.
+	BlockEvents struct {
+		Height     int64        `json:"height"`
+		BeginBlock []cmn.KVPair `json:"begin_block"`
+		Txs        []TxEvents   `json:"txs"`
+		EndBlock   []cmn.KVPair `json:"end_block"`
+	}
+
+	// TxEvents defines the events emitted by a transaction of a block, in the
+	// order in which it was delivered, along with its result code.
+	TxEvents struct {
+		Code uint32       `json:"code"`
+		Tags []cmn.KVPair `json:"tags"`
+	}
+)
+
+// eventWAL is a write-ahead log of the events of the committed blocks an
+// EventSink has not acknowledged yet. It lets a sink that failed or crashed
+// resume from the block following the last one it acknowledged rather than
+// re-scan the chain.
+type eventWAL struct {
+	db   dbm.DB
+	sink EventSink
+
+	// block holds the events of the block being executed
+	block BlockEvents
+}
+
+// SetEventSink returns an option that indexes the events of every committed
+// block with the given sink. The events of a block are persisted to a
+// write-ahead log in the given database before its state is committed and
+// dropped once the sink acknowledges them. The sink is called within Commit,
+// so it should buffer any slow indexing. It panics if the application is
+// already sealed.
+func SetEventSink(sink EventSink, db dbm.DB) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetEventSink() on sealed EthermintApp")
+		}
+
+		app.eventWAL = &eventWAL{db: db, sink: sink}
+	}
+}
+
+// BeginBlock implements the ABCI application interface.
+func (app *EthermintApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	res := app.BaseApp.BeginBlock(req)
+
+	if app.eventWAL != nil {
+		app.eventWAL.block = BlockEvents{Height: req.Header.Height, BeginBlock: res.Tags, Txs: []TxEvents{}}
+	}
+
+	if app.profiler != nil {
+		app.profiler.begin(req.Header.Height)
+	}
+
+	return res
+}
+
+// EndBlock implements the ABCI application interface.
+func (app *EthermintApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	res := app.BaseApp.EndBlock(req)
+
+	if app.eventWAL != nil {
+		app.eventWAL.block.EndBlock = res.Tags
+	}
+
+	return res
+}
+
+// walEventsKey returns the key the events of the block at the given height
+// are persisted under. Heights are encoded in big endian so that blocks are
+// iterated over in order.
+func walEventsKey(height int64) []byte {
+	key := make([]byte, len(walEventsPrefix)+8)
+	copy(key, walEventsPrefix)
+	binary.BigEndian.PutUint64(key[len(walEventsPrefix):], uint64(height))
+
+	return key
+}
+
+// write persists the events of the block being executed. Persisting them
+// again, as when a block whose state was not committed is replayed, is a
+// no-op.
+func (w *eventWAL) write() {
+	bz, err := json.Marshal(w.block)
+	if err != nil {
+		panic(fmt.Sprintf("failed to encode events of block %d: %v", w.block.Height, err))
+	}
+
+	w.db.SetSync(walEventsKey(w.block.Height), bz)
+}
+
+// lastAcked returns the height of the last block acknowledged by the sink.
+func (w *eventWAL) lastAcked() int64 {
+	bz := w.db.Get(walAckedKey)
+	if len(bz) != 8 {
+		return 0
+	}
+
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// backlog returns the number of committed blocks whose events the sink has
+// not acknowledged yet.
+func (w *eventWAL) backlog() int64 {
+	acked := w.lastAcked()
+
+	var n int64
+
+	it := dbm.IteratePrefix(w.db, walEventsPrefix)
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		height := int64(binary.BigEndian.Uint64(it.Key()[len(walEventsPrefix):]))
+		if height > acked {
+			n++
+		}
+	}
+
+	return n
+}
+
+// flush indexes the events of every block not acknowledged yet in order,
+// dropping every acknowledged block from the log, until the sink fails.
+func (w *eventWAL) flush() error {
+	var blocks []BlockEvents
+
+	it := dbm.IteratePrefix(w.db, walEventsPrefix)
+	for ; it.Valid(); it.Next() {
+		var block BlockEvents
+		if err := json.Unmarshal(it.Value(), &block); err != nil {
+			it.Close()
+			return fmt.Errorf("invalid events in write-ahead log: %v", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	it.Close()
+
+	acked := w.lastAcked()
+
+	for _, block := range blocks {
+		// a block may be acknowledged but not yet dropped upon a crash
+		if block.Height > acked {
+			if err := w.sink.IndexBlock(block); err != nil {
+				return fmt.Errorf("failed to index events of block %d: %v", block.Height, err)
+			}
+
+			acked = block.Height
+		}
+
+		bz := make([]byte, 8)
+		binary.BigEndian.PutUint64(bz, uint64(acked))
+
+		batch := w.db.NewBatch()
+		batch.Set(walAckedKey, bz)
+		batch.Delete(walEventsKey(block.Height))
+		batch.WriteSync()
+	}
+
+	return nil
+}
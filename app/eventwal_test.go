@@ -0,0 +1,143 @@
+package app
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type testEventSink struct {
+	fail   bool
+	blocks []BlockEvents
+}
+
+func (sink *testEventSink) IndexBlock(events BlockEvents) error {
+	if sink.fail {
+		return errors.New("sink unavailable")
+	}
+
+	sink.blocks = append(sink.blocks, events)
+	return nil
+}
+
+func requireWALHeights(t *testing.T, db dbm.DB, expected ...int64) {
+	var heights []int64
+
+	it := dbm.IteratePrefix(db, walEventsPrefix)
+	for ; it.Valid(); it.Next() {
+		heights = append(heights, int64(binary.BigEndian.Uint64(it.Key()[len(walEventsPrefix):])))
+	}
+
+	it.Close()
+
+	require.Equal(t, expected, heights)
+}
+
+func requireIndexedBlock(t *testing.T, block BlockEvents, height int64, results []abci.ResponseDeliverTx) {
+	require.Equal(t, height, block.Height)
+	require.Subset(t, block.BeginBlock, sdk.NewTags("module", []byte("test")).ToKVPairs())
+	require.Len(t, block.Txs, len(results))
+
+	for i, res := range results {
+		require.Equal(t, TxEvents{Code: res.Code, Tags: res.Tags}, block.Txs[i], fmt.Sprintf("unexpected events: test case #%d", i))
+	}
+}
+
+func TestEventWAL(t *testing.T) {
+	appDB, walDB := dbm.NewMemDB(), dbm.NewMemDB()
+	sink := new(testEventSink)
+
+	newApp := func(sink EventSink) *EthermintApp {
+		return NewEthermintApp(
+			log.NewNopLogger(), appDB,
+			SetBeginBlockers(newTestBeginBlocker("test", new([]string))),
+			SetEventSink(sink, walDB),
+		)
+	}
+
+	chain := &testChain{app: newApp(sink), header: abci.Header{ChainID: "test-chain"}}
+	chain.app.InitChain(abci.RequestInitChain{ChainId: "test-chain"})
+
+	ctx := chain.app.NewContext(false, chain.header)
+	require.Nil(t, stake.InitGenesis(ctx, chain.app.stakeKeeper, stake.DefaultGenesisState()))
+
+	var results [][]abci.ResponseDeliverTx
+
+	results = append(results, chain.nextBlock([]byte("invalid tx")))
+	require.Len(t, sink.blocks, 1)
+	require.NotEqual(t, uint32(sdk.ABCICodeOK), sink.blocks[0].Txs[0].Code)
+	requireWALHeights(t, walDB)
+
+	// blocks are retained while the sink fails and indexed in order once it
+	// recovers
+	sink.fail = true
+	results = append(results, chain.nextBlock(), chain.nextBlock([]byte("invalid tx"), []byte("invalid tx")))
+	require.Len(t, sink.blocks, 1)
+	requireWALHeights(t, walDB, 2, 3)
+	require.Equal(t, int64(2), chain.app.eventWAL.backlog())
+
+	sink.fail = false
+	results = append(results, chain.nextBlock())
+	require.Len(t, sink.blocks, 4)
+	requireWALHeights(t, walDB)
+	require.Equal(t, int64(0), chain.app.eventWAL.backlog())
+
+	for i, block := range sink.blocks {
+		requireIndexedBlock(t, block, int64(i+1), results[i])
+	}
+
+	// blocks not acknowledged before a crash are indexed once restarted
+	sink.fail = true
+	chain.nextBlock([]byte("invalid tx"))
+	requireWALHeights(t, walDB, 5)
+
+	sink = new(testEventSink)
+	chain.app = newApp(sink)
+	chain.nextBlock()
+
+	require.Len(t, sink.blocks, 2)
+	require.Equal(t, int64(5), sink.blocks[0].Height)
+	require.Len(t, sink.blocks[0].Txs, 1)
+	require.Equal(t, int64(6), sink.blocks[1].Height)
+	requireWALHeights(t, walDB)
+}
+
+func TestEventWALSkipsAcked(t *testing.T) {
+	db := dbm.NewMemDB()
+	sink := new(testEventSink)
+	w := &eventWAL{db: db, sink: sink}
+
+	for height := int64(1); height <= 2; height++ {
+		w.block = BlockEvents{Height: height, Txs: []TxEvents{}}
+		w.write()
+	}
+
+	// a crash after acknowledging block 1 but before dropping it
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, 1)
+	db.Set(walAckedKey, bz)
+	require.Equal(t, int64(1), w.backlog())
+
+	require.Nil(t, w.flush())
+	require.Len(t, sink.blocks, 1)
+	require.Equal(t, int64(2), sink.blocks[0].Height)
+	require.Equal(t, int64(2), w.lastAcked())
+	requireWALHeights(t, db)
+}
+
+func TestSetEventSinkSealed(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+
+	require.Panics(t, func() {
+		SetEventSink(new(testEventSink), dbm.NewMemDB())(app)
+	})
+}
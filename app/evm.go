@@ -0,0 +1,293 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// executeEthTx executes an Ethereum transaction, authenticated by the ante
+// handler, with the EVM against the Ethereum state held in the application's
+// multi-store through a db.CommitStateDB: the balances in the EVM-native denom
+// (see SetEVMDenom), nonces and code hashes of its accounts and its contract
+// storage and code stores. The stateful precompiled contracts of Precompiles
+// are available to it. The registered TxHooks run prior to execution, any
+// error aborting the transaction.
+//
+// As on Ethereum, the sender pays for the gas used at the transaction's gas
+// price, credited to the fee collector, even if execution fails, in which
+// case its state changes are reverted but the result remains successful, the
+// failure being reflected by the types.ResultData encoded into the result's
+// data. The fee collector then refunds the share of the fee set by the EVM
+// module's FailedTxRefund parameter to the sender. A transaction that cannot
+// be executed at all, e.g. as its sender cannot pay for its gas and value,
+// fails with ErrTxNotExecutable and has no effect beyond the nonce consumed by
+// the ante handler.
+//
+// If internal transfer tags are enabled (see EnableInternalTransferTags), the
+// internal value transfers made by a successful execution are recorded by a
+// core.TransferTracer and tagged on the result (see core.TagInternalFrom).
+//
+// Upon DeliverTx, if state diffs are enabled (see EnableStateDiffs), the state
+// diff of the transaction is recorded by a core.StateDiffTracer.
+//
+// The gas used reported by the result is that of the execution, net of the
+// EVM's refunds for cleared storage slots and self-destructed contracts, as
+// in an Ethereum receipt (see ethGasMeter).
+//
+// As no block gas limit is enforced, the block gas limit exposed to the EVM is
+// the transaction's own gas limit.
+//
+// Upon CheckTx, the transaction is executed against a branch of the check
+// state that is discarded, so that the check state only tracks the speculative
+// nonces and balances left by the ante handler, reset to the committed ones by
+// Commit, and is not polluted with the effects of transactions yet to be
+// delivered. The cost debited by the ante handler is credited back to the
+// sender within the branch, the execution paying for its gas and value itself.
+func (app *EthermintApp) executeEthTx(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+	tx, ok := msg.(*types.Transaction)
+	if !ok {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized Ethereum message type: %T", msg)).Result()
+	}
+
+	if ctx.IsCheckTx() {
+		ctx, _ = ctx.CacheContext()
+	}
+
+	chainConfig := core.NewChainConfig(app.ethChainID)
+
+	from, err := tx.Sender(chainConfig, big.NewInt(ctx.BlockHeight()))
+	if err != nil {
+		return types.ErrInvalidSender(app.codespace, err.Error()).Result()
+	}
+
+	// the ante handler already consumed the nonce, which the EVM consumes
+	// again, deriving the address of a created contract from it, while the
+	// state database never regresses a sequence
+	acc := app.accountMapper.GetAccount(ctx, from.Bytes())
+	if err := acc.SetSequence(int64(tx.Nonce())); err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	if ctx.IsCheckTx() {
+		evmDenom := app.coinKeeper.EVMDenom()
+		balance := new(big.Int).Add(types.EVMBalance(acc.GetCoins(), evmDenom), ethTxCost(tx))
+
+		if err := acc.SetCoins(types.SetEVMBalance(acc.GetCoins(), evmDenom, balance)); err != nil {
+			return sdk.ErrInternal(err.Error()).Result()
+		}
+	}
+
+	app.accountMapper.SetAccount(ctx, acc)
+
+	stateDB := db.NewCommitStateDB(
+		ctx, app.accountMapper, app.coinKeeper.EVMDenom(), db.NewStorageMapper(app.keyStorage), db.NewCodeMapper(app.keyCode),
+	)
+	stateDB.Prepare(tx.Hash(), ethcmn.Hash{}, 0)
+
+	header := ctx.BlockHeader()
+	header.Time = app.BlockTime(ctx)
+
+	coinbase := ethcmn.BytesToAddress(app.mintKeeper.FeeCollector())
+
+	vmCtx := core.NewVMContext(header, from, coinbase, tx.GasPrice(), tx.Gas(), app.GetHashFn(ctx))
+	if err := app.txHooks.Run(&core.TxContext{
+		Ctx: ctx, Tx: tx, From: from, VMContext: &vmCtx, StateDB: stateDB,
+	}); err != nil {
+		return types.ErrTxNotExecutable(app.codespace, err.Error()).Result()
+	}
+
+	var (
+		tracers   evmTracers
+		transfers *core.TransferTracer
+	)
+
+	if app.transferTags {
+		transfers = core.NewTransferTracer()
+		tracers = append(tracers, transfers)
+	}
+
+	var stateDiff *core.StateDiffTracer
+	if app.stateDiffs != nil && !ctx.IsCheckTx() {
+		accounts := []ethcmn.Address{from, coinbase}
+		if tx.To() != nil {
+			accounts = append(accounts, *tx.To())
+		}
+
+		stateDiff = core.NewStateDiffTracer(stateDB, accounts...)
+		tracers = append(tracers, stateDiff)
+	}
+
+	// the EVM only reports to tracers, at the cost of a call per executed
+	// opcode, in debug mode
+	tracing := ethvm.Config{}
+	if len(tracers) > 0 {
+		tracing = ethvm.Config{Debug: true, Tracer: tracers}
+	}
+
+	vmConfig := core.NewVMConfig(chainConfig, vmCtx.BlockNumber, app.DisabledOpcodes(ctx), tracing)
+	evm := ethvm.NewEVM(vmCtx, stateDB, chainConfig, vmConfig)
+
+	ethMsg := ethtypes.NewMessage(from, tx.To(), tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data(), false)
+
+	var (
+		ret     []byte
+		gasUsed uint64
+		failed  bool
+	)
+
+	core.WithPrecompiles(app.Precompiles(ctx), func() {
+		ret, gasUsed, failed, err = ethcore.ApplyMessage(evm, ethMsg, new(ethcore.GasPool).AddGas(tx.Gas()))
+	})
+	if err != nil {
+		return types.ErrTxNotExecutable(app.codespace, err.Error()).Result()
+	}
+
+	if meter, ok := ctx.GasMeter().(*ethGasMeter); ok {
+		meter.consumeEVMGas(gasUsed)
+	}
+
+	if failed {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), tx.GasPrice())
+
+		refund := app.evmKeeper.FailedTxRefund(ctx, fee)
+		stateDB.SubBalance(coinbase, refund)
+		stateDB.AddBalance(from, refund)
+	}
+
+	if stateDiff != nil {
+		app.stateDiffs.add(ctx.BlockHeight(), stateDiff.StateDiff(tx.Hash()))
+	}
+
+	stateDB.Commit()
+
+	data := types.ResultData{
+		Ret:     ret,
+		Logs:    types.NewLogs(stateDB.Logs()),
+		GasUsed: gasUsed,
+		Failed:  failed,
+	}
+
+	// as in an Ethereum receipt, the address is set even if creation failed
+	if tx.To() == nil {
+		data.ContractAddress = ethcrypto.CreateAddress(from, tx.Nonce())
+	}
+
+	bz, err := types.EncodeResultData(app.codec, data)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	res := sdk.Result{Data: bz}
+	if transfers != nil {
+		res.Tags = transfers.Tags()
+	}
+
+	if failed {
+		res.Log = "execution failed"
+		if len(ret) > 0 {
+			res.Log = core.RevertMessage(ret)
+		}
+	}
+
+	return res
+}
+
+// evmTracers implements Ethereum's vm.Tracer interface, dispatching every
+// event to several tracers of the same execution in order. The first error
+// returned by a tracer is returned.
+type evmTracers []ethvm.Tracer
+
+// CaptureStart implements Ethereum's vm.Tracer interface.
+func (ts evmTracers) CaptureStart(
+	from, to ethcmn.Address, call bool, input []byte, gas uint64, value *big.Int,
+) error {
+
+	for _, t := range ts {
+		if err := t.CaptureStart(from, to, call, input, gas, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CaptureState implements Ethereum's vm.Tracer interface.
+func (ts evmTracers) CaptureState(
+	env *ethvm.EVM, pc uint64, op ethvm.OpCode, gas, cost uint64,
+	memory *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, depth int, err error,
+) error {
+
+	for _, t := range ts {
+		if err := t.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CaptureFault implements Ethereum's vm.Tracer interface.
+func (ts evmTracers) CaptureFault(
+	env *ethvm.EVM, pc uint64, op ethvm.OpCode, gas, cost uint64,
+	memory *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, depth int, err error,
+) error {
+
+	for _, t := range ts {
+		if err := t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CaptureEnd implements Ethereum's vm.Tracer interface.
+func (ts evmTracers) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	for _, tracer := range ts {
+		if err := tracer.CaptureEnd(output, gasUsed, t, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ethGasMeter implements the sdk.GasMeter of an Ethereum transaction, set by
+// the ante handler. As the EVM prices every state access itself, the gas the
+// multi-store consumes is not metered: the meter only reports the gas used by
+// the transaction's execution, so that the gas used of its result matches that
+// of its Ethereum receipt. A transaction rejected before execution uses no
+// gas.
+type ethGasMeter struct {
+	consumed sdk.Gas
+}
+
+func newEthGasMeter() *ethGasMeter {
+	return &ethGasMeter{}
+}
+
+// GasConsumed implements the sdk.GasMeter interface.
+func (m *ethGasMeter) GasConsumed() sdk.Gas {
+	return m.consumed
+}
+
+// ConsumeGas implements the sdk.GasMeter interface. Gas consumed by the
+// multi-store is ignored.
+func (m *ethGasMeter) ConsumeGas(sdk.Gas, string) {}
+
+// consumeEVMGas records the gas used by the transaction's execution.
+func (m *ethGasMeter) consumeEVMGas(gas uint64) {
+	m.consumed += sdk.Gas(gas)
+}
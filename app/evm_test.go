@@ -0,0 +1,543 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/denom"
+	"github.com/cosmos/ethermint/x/evm"
+	"github.com/cosmos/ethermint/x/mint"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// initCode returns the init code of a contract of the given hex encoded
+// runtime code, of at most 255 bytes.
+func initCode(runtime string) []byte {
+	return ethcmn.FromHex(fmt.Sprintf("0x60%02x80600b6000396000f3", len(runtime)/2) + runtime)
+}
+
+// proxyCode returns the runtime code of a contract forwarding its call data to
+// the given address, returning the data returned or reverting if the call
+// fails.
+func proxyCode(target ethcmn.Address) string {
+	return "366000600037" + "60006000366000600073" + ethcmn.Bytes2Hex(target.Bytes()) + "5af1" +
+		"3d600060003e" + "603357" + "3d6000fd" + "5b3d6000f3"
+}
+
+// counterCode is the init code of a contract whose runtime code increments the
+// value at storage slot zero and returns the new value on every call.
+var counterCode = ethcmn.FromHex("0x601280600b6000396000f3" + "6000546001018060005560005260206000f3")
+
+func TestExecuteEthTx(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+	recipient := sdk.AccAddress(ethcmn.BytesToAddress([]byte("recipient")).Bytes())
+	feeCollector := chain.app.mintKeeper.FeeCollector()
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	sign := func(nonce uint64, to *ethcmn.Address, value int64, gas uint64, data []byte) []byte {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(value), gas, big.NewInt(1), data)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(value), gas, big.NewInt(1), data)
+		}
+
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	resultData := func(res abci.ResponseDeliverTx) types.ResultData {
+		require.True(t, res.IsOK(), res.Log)
+
+		data, err := types.DecodeResultData(chain.app.codec, res.Data)
+		require.Nil(t, err)
+
+		return data
+	}
+
+	balance := func(addr sdk.AccAddress) int64 {
+		acc := chain.account(addr)
+		if acc == nil {
+			return 0
+		}
+
+		return acc.GetCoins().AmountOf(types.DenomDefault).Int64()
+	}
+
+	// a value transfer credits the recipient, creating its account, and pays
+	// for its gas to the fee collector, alongside the block reward
+	to := ethcmn.BytesToAddress(recipient)
+	fees := balance(feeCollector) + mint.DefaultParams().BlockReward.AmountOf(types.DenomDefault).Int64()
+
+	data := resultData(chain.nextBlock(sign(0, &to, 1000, 21000, nil))[0])
+	require.False(t, data.Failed)
+	require.Equal(t, uint64(21000), data.GasUsed)
+
+	require.Equal(t, int64(1000), balance(recipient))
+	require.Equal(t, int64(1000000-1000-21000), balance(sender))
+	require.Equal(t, fees+21000, balance(feeCollector))
+	require.Equal(t, int64(1), chain.account(sender).GetSequence())
+
+	// a contract is created at the address derived from the sender's nonce and
+	// its storage persists across blocks
+	data = resultData(chain.nextBlock(sign(1, nil, 0, 100000, counterCode))[0])
+	require.False(t, data.Failed)
+
+	contract := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), 1)
+	require.Equal(t, contract, data.ContractAddress)
+	require.NotEmpty(t, chain.account(sdk.AccAddress(contract.Bytes())).(*types.Account).CodeHash)
+
+	for i := int64(1); i <= 2; i++ {
+		data = resultData(chain.nextBlock(sign(uint64(i+1), &contract, 0, 100000, nil))[0])
+		require.False(t, data.Failed)
+		require.Equal(t, ethcmn.BigToHash(big.NewInt(i)).Bytes(), data.Ret)
+	}
+
+	// a failed execution reverts its state changes, including its value
+	// transfer, but still pays for all of its gas
+	before := balance(sender)
+
+	data = resultData(chain.nextBlock(sign(4, &contract, 500, 21100, nil))[0])
+	require.True(t, data.Failed)
+	require.Equal(t, uint64(21100), data.GasUsed)
+
+	require.Equal(t, before-21100, balance(sender))
+	require.Equal(t, int64(0), balance(sdk.AccAddress(contract.Bytes())))
+	require.Equal(t, int64(5), chain.account(sender).GetSequence())
+
+	data = resultData(chain.nextBlock(sign(5, &contract, 0, 100000, nil))[0])
+	require.Equal(t, ethcmn.BigToHash(big.NewInt(3)).Bytes(), data.Ret)
+
+	// a transaction its sender cannot pay for is not executed, but consumes
+	// the sender's nonce
+	before = balance(sender)
+
+	res := chain.nextBlock(sign(6, &to, before, 21000, nil))
+	require.Equal(t, types.ErrTxNotExecutable(chain.app.codespace, "").ABCICode(), sdk.ABCICodeType(res[0].Code))
+	require.Zero(t, res[0].GasUsed)
+	require.Equal(t, before, balance(sender))
+	require.Equal(t, int64(1000), balance(recipient))
+	require.Equal(t, int64(7), chain.account(sender).GetSequence())
+}
+
+func TestExecuteEthTxFailedTxRefund(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+	feeCollector := chain.app.mintKeeper.FeeCollector()
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	params := evm.DefaultParams()
+	params.FailedTxRefund = 50
+	chain.app.evmKeeper.SetParams(ctx, params)
+
+	balance := func(addr sdk.AccAddress) int64 {
+		return chain.account(addr).GetCoins().AmountOf(types.DenomDefault).Int64()
+	}
+
+	// a contract creation whose init code hits an invalid opcode consumes its
+	// entire gas limit
+	tx := types.NewContractCreation(0, big.NewInt(0), 60000, big.NewInt(2), []byte{0xfe})
+	tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	res := chain.nextBlock(txBytes)[0]
+	require.True(t, res.IsOK(), res.Log)
+
+	data, err := types.DecodeResultData(chain.app.codec, res.Data)
+	require.Nil(t, err)
+	require.True(t, data.Failed)
+	require.Equal(t, uint64(60000), data.GasUsed)
+
+	// the fee collector refunds half of the fee paid for the gas used
+	reward := mint.DefaultParams().BlockReward.AmountOf(types.DenomDefault).Int64()
+	require.Equal(t, int64(1000000-60000), balance(sender))
+	require.Equal(t, reward+60000, balance(feeCollector))
+}
+
+func TestExecuteEthTxRevertedCall(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	deliver := func(nonce uint64, to *ethcmn.Address, input []byte) types.ResultData {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 200000, big.NewInt(1), input)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(0), 200000, big.NewInt(1), input)
+		}
+
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		res := chain.nextBlock(bz)[0]
+		require.True(t, res.IsOK(), res.Log)
+
+		data, err := types.DecodeResultData(chain.app.codec, res.Data)
+		require.Nil(t, err)
+
+		return data
+	}
+
+	// the callee sets storage slot zero and logs before reverting, while the
+	// caller sets slot zero, calls the callee, records whether the call
+	// succeeded at slot one and logs
+	callee := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), 0)
+	caller := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), 1)
+
+	require.False(t, deliver(0, nil, initCode("6001600055"+"60006000a0"+"60006000fd")).Failed)
+	require.False(t, deliver(1, nil, initCode(
+		"6001600055"+"60006000600060006000"+"73"+ethcmn.Bytes2Hex(callee.Bytes())+"5af1"+"600155"+"60006000a0"+"00",
+	)).Failed)
+
+	// the callee's changes are reverted by the journal, leaving the caller's
+	data := deliver(2, &caller, nil)
+	require.False(t, data.Failed)
+	require.Len(t, data.Logs, 1)
+	require.Equal(t, caller, data.Logs[0].Address)
+
+	ctx = chain.app.NewContext(true, abci.Header{ChainID: "ethermint"})
+	storage := db.NewStorageMapper(chain.app.keyStorage)
+
+	require.Equal(t, ethcmn.Hash{}, storage.GetState(ctx, callee, ethcmn.Hash{}))
+	require.Equal(t, ethcmn.BigToHash(big.NewInt(1)), storage.GetState(ctx, caller, ethcmn.Hash{}))
+	require.Equal(t, ethcmn.Hash{}, storage.GetState(ctx, caller, ethcmn.BigToHash(big.NewInt(1))))
+}
+
+// clearCode is the init code of a contract setting storage slot zero, whose
+// runtime code clears it.
+var clearCode = ethcmn.FromHex("0x60016000556006806010600039" + "6000f3" + "600060005500")
+
+func TestExecuteEthTxGasUsed(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	sign := func(nonce uint64, to *ethcmn.Address, data []byte) []byte {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1), data)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(0), 100000, big.NewInt(1), data)
+		}
+
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	contract := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), 0)
+
+	testCases := []struct {
+		tx              []byte
+		expectOK        bool
+		expectedGasUsed int64
+	}{
+		// the gas used by the EVM, none of which is consumed by the stores:
+		// the intrinsic gas, that of the init code and the code deposit
+		{sign(0, nil, clearCode), true, 53000 + 6*4 + 16*68 + 20030 + 6*200},
+		// clearing the slot is refunded up to half of the gas used
+		{sign(1, &contract, nil), true, (21000 + 3 + 3 + 5000) / 2},
+		// a transaction rejected by the ante handler uses no gas
+		{sign(1, &contract, nil), false, 0},
+	}
+
+	for i, tc := range testCases {
+		res := chain.nextBlock(tc.tx)[0]
+		require.Equal(t, tc.expectOK, res.IsOK(), fmt.Sprintf("unexpected result: test case #%d", i))
+		require.Equal(t, tc.expectedGasUsed, res.GasUsed, fmt.Sprintf("unexpected gas used: test case #%d", i))
+
+		if tc.expectOK {
+			data, err := types.DecodeResultData(chain.app.codec, res.Data)
+			require.Nil(t, err)
+			require.Equal(t, uint64(tc.expectedGasUsed), data.GasUsed, fmt.Sprintf("unexpected result gas used: test case #%d", i))
+		}
+	}
+}
+
+func TestExecuteEthTxPrecompiles(t *testing.T) {
+	chain := newTestChain(t, "ethermint-1")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint-1"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 10000000)})
+	require.Nil(t, sdkErr)
+
+	nonce := uint64(0)
+	deliver := func(to *ethcmn.Address, input []byte) types.ResultData {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 200000, big.NewInt(1), input)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(0), 200000, big.NewInt(1), input)
+		}
+
+		nonce++
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		res := chain.nextBlock(bz)[0]
+		require.True(t, res.IsOK(), res.Log)
+
+		data, err := types.DecodeResultData(chain.app.codec, res.Data)
+		require.Nil(t, err)
+
+		return data
+	}
+
+	denomABI, err := ethabi.JSON(strings.NewReader(denom.PrecompileABI))
+	require.Nil(t, err)
+
+	chainInfoABI, err := ethabi.JSON(strings.NewReader(core.ChainInfoPrecompileABI))
+	require.Nil(t, err)
+
+	testCases := []struct {
+		target   ethcmn.Address
+		contract ethabi.ABI
+		method   string
+		args     []interface{}
+		expected []interface{}
+	}{
+		{
+			denom.PrecompileAddress, denomABI, "metadata", []interface{}{types.DenomDefault},
+			[]interface{}{"Photon", "PHOTON", uint8(18)},
+		},
+		{core.ChainInfoPrecompileAddress, chainInfoABI, "chainId", nil, []interface{}{"ethermint-1"}},
+		// the test chain's blocks carry no proposer and its genesis bonds no
+		// validator
+		{core.ChainInfoPrecompileAddress, chainInfoABI, "proposer", nil, []interface{}{ethcmn.Address{}}},
+		{core.ChainInfoPrecompileAddress, chainInfoABI, "bondedValidators", nil, []interface{}{uint64(0)}},
+	}
+
+	for i, tc := range testCases {
+		// a contract calls the precompile, forwarding the transaction's input
+		proxy := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), nonce)
+		require.False(t, deliver(nil, initCode(proxyCode(tc.target))).Failed, fmt.Sprintf("unexpected failure: test case #%d", i))
+
+		input, err := tc.contract.Pack(tc.method, tc.args...)
+		require.Nil(t, err)
+
+		data := deliver(&proxy, input)
+		require.False(t, data.Failed, fmt.Sprintf("unexpected failure: test case #%d", i))
+
+		values, err := tc.contract.Methods[tc.method].Outputs.UnpackValues(data.Ret)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expected, values, fmt.Sprintf("unexpected values: test case #%d", i))
+	}
+
+	// a failed call to the precompile fails the calling contract
+	proxy := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), nonce)
+	require.False(t, deliver(nil, initCode(proxyCode(denom.PrecompileAddress))).Failed)
+
+	input, err := denomABI.Pack("metadata", "unknown")
+	require.Nil(t, err)
+	require.True(t, deliver(&proxy, input).Failed)
+}
+
+func TestExecuteEthTxInternalTransferTags(t *testing.T) {
+	// internalTags returns the internal transfer tags of the given result
+	internalTags := func(res abci.ResponseDeliverTx) map[string]string {
+		values := make(map[string]string)
+		for _, tag := range res.Tags {
+			if strings.HasPrefix(string(tag.Key), "internal.") {
+				values[string(tag.Key)] = string(tag.Value)
+			}
+		}
+
+		return values
+	}
+
+	for _, enabled := range []bool{true, false} {
+		var opts []func(*EthermintApp)
+		if enabled {
+			opts = append(opts, EnableInternalTransferTags())
+		}
+
+		chain := newTestChain(t, "ethermint", opts...)
+
+		privKey, err := crypto.GenerateKey()
+		require.Nil(t, err)
+
+		sender := sdk.AccAddress(privKey.PubKey().Address())
+		recipient := ethcmn.BytesToAddress([]byte("recipient"))
+
+		ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+		_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+		require.Nil(t, sdkErr)
+
+		deliver := func(nonce uint64, to *ethcmn.Address, value int64, gas uint64, input []byte) abci.ResponseDeliverTx {
+			var tx *types.Transaction
+			if to == nil {
+				tx = types.NewContractCreation(nonce, big.NewInt(value), gas, big.NewInt(1), input)
+			} else {
+				tx = types.NewTransaction(nonce, *to, big.NewInt(value), gas, big.NewInt(1), input)
+			}
+
+			tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+			bz, err := rlp.EncodeToBytes(tx)
+			require.Nil(t, err)
+
+			res := chain.nextBlock(bz)[0]
+			require.True(t, res.IsOK(), res.Log)
+
+			return res
+		}
+
+		// the forwarder sends the value it receives on to the recipient
+		forwarder := ethcrypto.CreateAddress(ethcmn.BytesToAddress(sender), 0)
+		deliver(0, nil, 0, 100000, initCode("6000600060006000"+"34"+"73"+ethcmn.Bytes2Hex(recipient.Bytes())+"5af1"+"00"))
+
+		res := deliver(1, &forwarder, 500, 100000, nil)
+		if !enabled {
+			// transfers are only traced if tagging is enabled
+			require.Empty(t, internalTags(res))
+			continue
+		}
+
+		require.Equal(t, map[string]string{
+			core.TagInternalFrom:  forwarder.Hex(),
+			core.TagInternalTo:    recipient.Hex(),
+			core.TagInternalValue: "500",
+		}, internalTags(res))
+
+		// the transfers of a failed execution are reverted and not tagged
+		res = deliver(2, &forwarder, 500, 21100, nil)
+		require.Empty(t, internalTags(res))
+	}
+}
+
+func TestExecuteEthTxCheckState(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+	recipient := sdk.AccAddress(ethcmn.BytesToAddress([]byte("recipient")).Bytes())
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	chain.nextBlock()
+
+	signTx := func(nonce uint64) []byte {
+		tx := types.NewTransaction(nonce, ethcmn.BytesToAddress(recipient), big.NewInt(100), 21000, big.NewInt(1), nil)
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	checkAccount := func(addr sdk.AccAddress) auth.Account {
+		ctx := chain.app.NewContext(true, abci.Header{ChainID: "ethermint"})
+		return chain.app.accountMapper.GetAccount(ctx, addr)
+	}
+
+	checkBalance := func(addr sdk.AccAddress) *big.Int {
+		return types.EVMBalance(checkAccount(addr).GetCoins(), chain.app.coinKeeper.EVMDenom())
+	}
+
+	tx0, tx1 := signTx(0), signTx(1)
+
+	// the check state consumes the sender's nonce and debits the cost of the
+	// transaction speculatively without executing the transfer
+	res := chain.app.CheckTx(tx0)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, int64(1), checkAccount(sender).GetSequence())
+	require.Equal(t, big.NewInt(1000000-21100), checkBalance(sender))
+	require.Nil(t, checkAccount(recipient))
+
+	committedCtx, err := chain.app.NewQueryContext(0, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), chain.app.accountMapper.GetAccount(committedCtx, sender).GetSequence())
+
+	require.False(t, chain.app.CheckTx(tx0).IsOK())
+
+	res = chain.app.CheckTx(tx1)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, int64(2), checkAccount(sender).GetSequence())
+	require.Equal(t, big.NewInt(1000000-2*21100), checkBalance(sender))
+
+	// a transaction the committed balance, but not the balance left, affords
+	// is rejected
+	unaffordable := types.NewTransaction(2, ethcmn.BytesToAddress(recipient), big.NewInt(950000), 21000, big.NewInt(1), nil)
+	unaffordable.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+	bz, err := rlp.EncodeToBytes(unaffordable)
+	require.Nil(t, err)
+	require.False(t, chain.app.CheckTx(bz).IsOK())
+
+	// the speculative nonce and balance are reset upon commit
+	chain.nextBlock()
+	require.Equal(t, int64(0), checkAccount(sender).GetSequence())
+	require.Equal(t, big.NewInt(1000000), checkBalance(sender))
+
+	results := chain.nextBlock(tx0)
+	require.True(t, results[0].IsOK(), results[0].Log)
+	require.Equal(t, int64(1), checkAccount(sender).GetSequence())
+	require.Equal(t, big.NewInt(100), types.EVMBalance(chain.account(recipient).GetCoins(), chain.app.coinKeeper.EVMDenom()))
+}
@@ -0,0 +1,339 @@
+package app
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/authz"
+	"github.com/cosmos/ethermint/x/circuit"
+	"github.com/cosmos/ethermint/x/denom"
+	"github.com/cosmos/ethermint/x/evm"
+	"github.com/cosmos/ethermint/x/faucet"
+	"github.com/cosmos/ethermint/x/ica"
+	"github.com/cosmos/ethermint/x/mint"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+type (
+	// GenesisState defines the application state of a genesis file, i.e. its
+	// app_state, holding the Ethereum chain configuration, the Ethereum
+	// accounts allocated at genesis and the genesis state of every module.
+	GenesisState struct {
+		ChainConfig *ethparams.ChainConfig `json:"chain_config"`
+		Alloc       []GenesisAccount       `json:"alloc"`
+
+		EVM     evm.GenesisState     `json:"evm"`
+		Circuit circuit.GenesisState `json:"circuit"`
+		Denom   denom.GenesisState   `json:"denom"`
+		Authz   authz.GenesisState   `json:"authz"`
+		Faucet  faucet.GenesisState  `json:"faucet"`
+		Mint    mint.GenesisState    `json:"mint"`
+		ICA     ica.GenesisState     `json:"ica"`
+		Stake   StakeGenesisState    `json:"stake"`
+	}
+
+	// StakeGenesisState defines the stake module's genesis state. It is
+	// encoded in amino JSON as the public keys of its validators are
+	// interfaces.
+	StakeGenesisState stake.GenesisState
+
+	// GenesisAccount defines an Ethereum account allocated at genesis. The
+	// code hash is optional and, if set, must be the Keccak256 hash of the
+	// code, e.g. as taken from a go-ethereum state dump. Storage keys are hex
+	// encoded 32 byte words and values hex encoded words of at most 32 bytes.
+	GenesisAccount struct {
+		Address  ethcmn.Address           `json:"address"`
+		Balance  *ethmath.HexOrDecimal256 `json:"balance"`
+		Nonce    uint64                   `json:"nonce"`
+		Code     hexutil.Bytes            `json:"code"`
+		CodeHash *ethcmn.Hash             `json:"code_hash,omitempty"`
+		Storage  map[string]string        `json:"storage"`
+	}
+
+	// GenesisError defines a problem of a genesis state along with the path
+	// of the field it was found in, e.g. "alloc[2].storage".
+	GenesisError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+)
+
+// genesisCodec encodes the module genesis states held in amino JSON.
+var genesisCodec = MakeCodec()
+
+// Error implements the error interface.
+func (err GenesisError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Field, err.Message)
+}
+
+// DefaultGenesisState returns the default GenesisState of a chain with the
+// given Ethereum chain ID. No Ethereum account is allocated.
+func DefaultGenesisState(ethChainID *big.Int) GenesisState {
+	return GenesisState{
+		ChainConfig: core.NewChainConfig(ethChainID),
+		Alloc:       []GenesisAccount{},
+		EVM:         evm.DefaultGenesisState(),
+		Circuit:     circuit.DefaultGenesisState(),
+		Denom:       denom.DefaultGenesisState(),
+		Authz:       authz.DefaultGenesisState(),
+		Faucet:      faucet.DefaultGenesisState(),
+		Mint:        mint.DefaultGenesisState(),
+		ICA:         ica.DefaultGenesisState(),
+		Stake:       StakeGenesisState(stake.DefaultGenesisState()),
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (gs StakeGenesisState) MarshalJSON() ([]byte, error) {
+	return genesisCodec.MarshalJSON(stake.GenesisState(gs))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (gs *StakeGenesisState) UnmarshalJSON(bz []byte) error {
+	return genesisCodec.UnmarshalJSON(bz, (*stake.GenesisState)(gs))
+}
+
+// initGenesis validates the given genesis state and applies it: the accounts
+// it allocates are created and every module's genesis state is initialized.
+// The chain configuration must be for the application's Ethereum chain ID.
+func (app *EthermintApp) initGenesis(ctx sdk.Context, genesis GenesisState) error {
+	if errs := ValidateGenesis(genesis); len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+
+		return fmt.Errorf("invalid genesis state: %s", strings.Join(msgs, "; "))
+	}
+
+	if genesis.ChainConfig.ChainID.Cmp(app.ethChainID) != 0 {
+		return fmt.Errorf("genesis chain ID %s does not match chain ID %s", genesis.ChainConfig.ChainID, app.ethChainID)
+	}
+
+	if err := app.allocAccounts(ctx, genesis.Alloc); err != nil {
+		return err
+	}
+
+	modules := []func() error{
+		func() error { return evm.InitGenesis(ctx, app.evmKeeper, genesis.EVM) },
+		func() error { return circuit.InitGenesis(ctx, app.circuitKeeper, genesis.Circuit) },
+		func() error { return denom.InitGenesis(ctx, app.denomKeeper, genesis.Denom) },
+		func() error { return authz.InitGenesis(ctx, app.authzKeeper, genesis.Authz) },
+		func() error { return faucet.InitGenesis(ctx, app.faucetKeeper, genesis.Faucet) },
+		func() error { return mint.InitGenesis(ctx, app.mintKeeper, genesis.Mint) },
+		func() error { return stake.InitGenesis(ctx, app.stakeKeeper, stake.GenesisState(genesis.Stake)) },
+	}
+
+	// the ICA module's keeper only exists once remote execution is enabled
+	if app.icaExecutor != nil {
+		modules = append(modules, func() error { return ica.InitGenesis(ctx, app.icaKeeper, genesis.ICA) })
+	}
+
+	for _, initModule := range modules {
+		if err := initModule(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allocAccounts creates the Ethereum accounts allocated at genesis, along with
+// their code and storage. Accounts allocated at genesis are retained even if
+// empty.
+func (app *EthermintApp) allocAccounts(ctx sdk.Context, alloc []GenesisAccount) error {
+	code := db.NewCodeMapper(app.keyCode)
+	storage := db.NewStorageMapper(app.keyStorage)
+
+	for _, genAcc := range alloc {
+		acc := app.accountMapper.GetAccount(ctx, genAcc.Address.Bytes())
+		if acc == nil {
+			acc = app.accountMapper.NewAccountWithAddress(ctx, genAcc.Address.Bytes())
+		}
+
+		ethermintAcc, ok := acc.(*types.Account)
+		if !ok {
+			return fmt.Errorf("cannot allocate account type %T", acc)
+		}
+
+		if err := ethermintAcc.SetSequence(int64(genAcc.Nonce)); err != nil {
+			return err
+		}
+
+		if genAcc.Balance != nil {
+			coins := types.SetEVMBalance(ethermintAcc.GetCoins(), app.coinKeeper.EVMDenom(), (*big.Int)(genAcc.Balance))
+			if err := ethermintAcc.SetCoins(coins); err != nil {
+				return err
+			}
+		}
+
+		code.SetAccountCode(ctx, ethermintAcc, genAcc.Code)
+		app.accountMapper.SetAccount(ctx, ethermintAcc)
+
+		for key, value := range genAcc.Storage {
+			// the storage was validated to hold hex encoded words
+			slot, _ := decodeHexWord(key)
+			word, _ := decodeHexWord(value)
+
+			storage.SetState(ctx, genAcc.Address, ethcmn.BytesToHash(slot), ethcmn.BytesToHash(word))
+		}
+	}
+
+	return nil
+}
+
+// ValidateGenesis returns every problem found in the given genesis state,
+// rather than only the first, so that a genesis file can be fixed in a single
+// pass ahead of a network launch. The chain configuration must have a
+// positive chain ID and schedule its forks in order, the allocated accounts
+// must be consistent (see GenesisAccount) and unique, and every module's
+// genesis state must be valid. An empty slice is returned if the genesis
+// state is valid.
+func ValidateGenesis(genesis GenesisState) []GenesisError {
+	errs := validateChainConfig(genesis.ChainConfig)
+	errs = append(errs, validateAlloc(genesis.Alloc)...)
+
+	modules := []struct {
+		field    string
+		validate func() error
+	}{
+		{"evm", func() error { return evm.ValidateGenesis(genesis.EVM) }},
+		{"circuit", func() error { return circuit.ValidateGenesis(genesis.Circuit) }},
+		{"denom", func() error { return denom.ValidateGenesis(genesis.Denom) }},
+		{"authz", func() error { return authz.ValidateGenesis(genesis.Authz) }},
+		{"faucet", func() error { return faucet.ValidateGenesis(genesis.Faucet) }},
+		{"mint", func() error { return mint.ValidateGenesis(genesis.Mint) }},
+		{"ica", func() error { return ica.ValidateGenesis(genesis.ICA) }},
+	}
+
+	for _, module := range modules {
+		if err := module.validate(); err != nil {
+			errs = append(errs, GenesisError{Field: module.field, Message: err.Error()})
+		}
+	}
+
+	return errs
+}
+
+// validateChainConfig returns the problems of a chain configuration. Once a
+// fork is unscheduled, no later fork may be scheduled.
+func validateChainConfig(config *ethparams.ChainConfig) []GenesisError {
+	errs := []GenesisError{}
+
+	if config == nil {
+		return append(errs, GenesisError{Field: "chain_config", Message: "missing chain configuration"})
+	}
+
+	if config.ChainID == nil || config.ChainID.Sign() <= 0 {
+		errs = append(errs, GenesisError{Field: "chain_config.chainId", Message: "chain ID must be positive"})
+	}
+
+	forks := []struct {
+		field string
+		block *big.Int
+	}{
+		{"homesteadBlock", config.HomesteadBlock},
+		{"eip150Block", config.EIP150Block},
+		{"eip155Block", config.EIP155Block},
+		{"eip158Block", config.EIP158Block},
+		{"byzantiumBlock", config.ByzantiumBlock},
+		{"constantinopleBlock", config.ConstantinopleBlock},
+	}
+
+	for i := 1; i < len(forks); i++ {
+		prev, fork := forks[i-1], forks[i]
+		if fork.block == nil || (prev.block != nil && prev.block.Cmp(fork.block) <= 0) {
+			continue
+		}
+
+		msg := fmt.Sprintf("fork scheduled before %s", prev.field)
+		if prev.block == nil {
+			msg = fmt.Sprintf("fork scheduled while %s is not", prev.field)
+		}
+
+		errs = append(errs, GenesisError{Field: "chain_config." + fork.field, Message: msg})
+	}
+
+	return errs
+}
+
+// validateAlloc returns the problems of the accounts allocated at genesis.
+func validateAlloc(alloc []GenesisAccount) []GenesisError {
+	errs := []GenesisError{}
+	seen := make(map[ethcmn.Address]bool, len(alloc))
+
+	for i, acc := range alloc {
+		field := fmt.Sprintf("alloc[%d]", i)
+
+		if seen[acc.Address] {
+			errs = append(errs, GenesisError{Field: field + ".address", Message: "duplicate address " + acc.Address.Hex()})
+		}
+
+		seen[acc.Address] = true
+
+		if acc.Address == types.EmbeddedTxAddress {
+			errs = append(errs, GenesisError{Field: field + ".address", Message: "reserved address " + acc.Address.Hex()})
+		}
+
+		if acc.Balance != nil && (*big.Int)(acc.Balance).Sign() < 0 {
+			errs = append(errs, GenesisError{Field: field + ".balance", Message: "negative balance"})
+		}
+
+		if codeHash := ethcrypto.Keccak256Hash(acc.Code); acc.CodeHash != nil && *acc.CodeHash != codeHash {
+			errs = append(errs, GenesisError{
+				Field:   field + ".code_hash",
+				Message: fmt.Sprintf("code hash %s does not match the hash %s of the code", acc.CodeHash.Hex(), codeHash.Hex()),
+			})
+		}
+
+		errs = append(errs, validateStorage(field+".storage", acc.Storage)...)
+	}
+
+	return errs
+}
+
+// validateStorage returns the problems of the storage of an account allocated
+// at genesis, in the order of its keys.
+func validateStorage(field string, storage map[string]string) []GenesisError {
+	var errs []GenesisError
+
+	keys := make([]string, 0, len(storage))
+	for key := range storage {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if bz, err := decodeHexWord(key); err != nil || len(bz) != ethcmn.HashLength {
+			errs = append(errs, GenesisError{Field: field, Message: fmt.Sprintf("key %q is not a 32 byte word", key)})
+		}
+
+		if bz, err := decodeHexWord(storage[key]); err != nil || len(bz) > ethcmn.HashLength {
+			errs = append(errs, GenesisError{
+				Field:   field,
+				Message: fmt.Sprintf("value %q of key %q is not a word of at most 32 bytes", storage[key], key),
+			})
+		}
+	}
+
+	return errs
+}
+
+// decodeHexWord decodes a hex encoded word with an optional 0x prefix.
+func decodeHexWord(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+}
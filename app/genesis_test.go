@@ -0,0 +1,188 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/state"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm"
+	"github.com/cosmos/ethermint/x/ica"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	addr := ethcmn.BytesToAddress([]byte("address"))
+	code := []byte("code")
+	codeHash := ethcrypto.Keccak256Hash(code)
+	word := ethcmn.BytesToHash([]byte("word")).Hex()
+
+	// the default genesis state is valid, also once encoded
+	genesis := DefaultGenesisState(big.NewInt(DefaultEthChainID))
+	require.Empty(t, ValidateGenesis(genesis))
+
+	bz, err := json.Marshal(genesis)
+	require.Nil(t, err)
+
+	var decoded GenesisState
+	require.Nil(t, json.Unmarshal(bz, &decoded))
+	require.Empty(t, ValidateGenesis(decoded))
+
+	testCases := []struct {
+		modify   func(*GenesisState)
+		expected []GenesisError
+	}{
+		{
+			func(g *GenesisState) {
+				g.Alloc = []GenesisAccount{{
+					Address:  addr,
+					Balance:  (*ethmath.HexOrDecimal256)(big.NewInt(1)),
+					Code:     code,
+					CodeHash: &codeHash,
+					Storage:  map[string]string{word: "0x01"},
+				}}
+			},
+			[]GenesisError{},
+		},
+		{
+			func(g *GenesisState) {
+				g.Alloc = []GenesisAccount{
+					{Address: addr, Code: code, CodeHash: &ethcmn.Hash{}},
+					{Address: addr, Balance: (*ethmath.HexOrDecimal256)(big.NewInt(-1))},
+					{Address: types.EmbeddedTxAddress, Storage: map[string]string{"0x01": word, word: word + "00"}},
+				}
+			},
+			[]GenesisError{
+				{
+					Field: "alloc[0].code_hash",
+					Message: fmt.Sprintf(
+						"code hash %s does not match the hash %s of the code", ethcmn.Hash{}.Hex(), codeHash.Hex(),
+					),
+				},
+				{Field: "alloc[1].address", Message: "duplicate address " + addr.Hex()},
+				{Field: "alloc[1].balance", Message: "negative balance"},
+				{Field: "alloc[2].address", Message: "reserved address " + types.EmbeddedTxAddress.Hex()},
+				// storage problems are ordered by key
+				{
+					Field:   "alloc[2].storage",
+					Message: fmt.Sprintf("value %q of key %q is not a word of at most 32 bytes", word+"00", word),
+				},
+				{Field: "alloc[2].storage", Message: `key "0x01" is not a 32 byte word`},
+			},
+		},
+		{
+			func(g *GenesisState) { g.ChainConfig = nil },
+			[]GenesisError{{Field: "chain_config", Message: "missing chain configuration"}},
+		},
+		{
+			func(g *GenesisState) {
+				g.ChainConfig.ChainID = big.NewInt(0)
+				g.ChainConfig.EIP155Block = big.NewInt(10)
+				g.ChainConfig.EIP158Block = big.NewInt(5)
+				g.ChainConfig.ByzantiumBlock = big.NewInt(10)
+				g.ChainConfig.ConstantinopleBlock = nil
+			},
+			[]GenesisError{
+				{Field: "chain_config.chainId", Message: "chain ID must be positive"},
+				{Field: "chain_config.eip158Block", Message: "fork scheduled before eip155Block"},
+			},
+		},
+		{
+			func(g *GenesisState) {
+				g.ChainConfig.ByzantiumBlock = nil
+				g.ChainConfig.ConstantinopleBlock = big.NewInt(1)
+			},
+			[]GenesisError{{Field: "chain_config.constantinopleBlock", Message: "fork scheduled while byzantiumBlock is not"}},
+		},
+		{
+			func(g *GenesisState) {
+				g.EVM.Params = evm.Params{MinGasLimit: 2, MaxGasLimit: 1}
+				g.ICA.Controllers = []ica.Controller{{}}
+			},
+			[]GenesisError{
+				{Field: "evm", Message: "minimum gas limit 2 exceeds maximum gas limit 1"},
+				{Field: "ica", Message: "controller must have an ID and an authority"},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		genesis := DefaultGenesisState(big.NewInt(DefaultEthChainID))
+		tc.modify(&genesis)
+
+		require.Equal(t, tc.expected, ValidateGenesis(genesis), fmt.Sprintf("unexpected errors: test case #%d", i))
+	}
+}
+
+func TestInitChainer(t *testing.T) {
+	addr := ethcmn.BytesToAddress([]byte("address"))
+	code := []byte("code")
+	slot := ethcmn.BytesToHash([]byte("slot"))
+	word := ethcmn.BytesToHash([]byte("word"))
+
+	genesis := DefaultGenesisState(big.NewInt(DefaultEthChainID))
+	genesis.Alloc = []GenesisAccount{{
+		Address: addr,
+		Balance: (*ethmath.HexOrDecimal256)(big.NewInt(1000)),
+		Nonce:   3,
+		Code:    code,
+		Storage: map[string]string{slot.Hex(): word.Hex()},
+	}}
+	genesis.Mint.Params.BlockReward = sdk.Coins{sdk.NewCoin(types.DenomDefault, 7)}
+	genesis.Stake.Params.MaxValidators = 7
+
+	appState, err := json.Marshal(genesis)
+	require.Nil(t, err)
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{ChainId: "ethermint", AppStateBytes: appState})
+
+	ctx := app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	require.Equal(t, genesis.Mint.Params, app.mintKeeper.GetParams(ctx))
+	require.Equal(t, uint16(7), app.stakeKeeper.GetParams(ctx).MaxValidators)
+
+	stateDB, err := ethstate.New(
+		ethcmn.Hash{}, state.NewContextDatabase(ctx, app.accountMapper, types.DenomDefault, app.keyStorage, app.keyCode),
+	)
+	require.Nil(t, err)
+
+	require.Equal(t, big.NewInt(1000), stateDB.GetBalance(addr))
+	require.Equal(t, uint64(3), stateDB.GetNonce(addr))
+	require.Equal(t, code, stateDB.GetCode(addr))
+	require.Equal(t, word, stateDB.GetState(addr, slot))
+
+	// the stake module's EndBlocker requires its genesis state
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: "ethermint", Height: 1}})
+	require.NotPanics(t, func() { app.EndBlock(abci.RequestEndBlock{Height: 1}) })
+}
+
+func TestInitChainerInvalid(t *testing.T) {
+	testCases := []func(*GenesisState){
+		func(g *GenesisState) { g.Alloc = []GenesisAccount{{Address: types.EmbeddedTxAddress}} },
+		func(g *GenesisState) { g.ChainConfig.ChainID = big.NewInt(DefaultEthChainID + 1) },
+	}
+
+	for i, tc := range testCases {
+		genesis := DefaultGenesisState(big.NewInt(DefaultEthChainID))
+		tc(&genesis)
+
+		appState, err := json.Marshal(genesis)
+		require.Nil(t, err)
+
+		app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+		require.Panics(t, func() {
+			app.InitChain(abci.RequestInitChain{ChainId: "ethermint", AppStateBytes: appState})
+		}, fmt.Sprintf("expected panic: test case #%d", i))
+	}
+}
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// TagEthTxHash is the tag holding the hex encoded hash of an Ethereum
+// transaction. Every Ethereum transaction included in a block is tagged with
+// it, whether its execution succeeds or fails, so that Tendermint's indexer
+// can serve its receipt by its Ethereum hash once the tag is indexed.
+const TagEthTxHash = "eth.hash"
+
+// Tags holding the EIP-55 encoded sender and recipient of an Ethereum
+// transaction, allowing Tendermint's indexer to serve the transaction history
+// of an account. A contract creation has no recipient tag.
+const (
+	TagEthFrom = "eth.from"
+	TagEthTo   = "eth.to"
+)
+
+// txTTLCache tracks the height at which every pending transaction was first
+// checked. It allows transactions that remain in the mempool for longer than a
+// given number of blocks to be evicted upon recheck.
+type txTTLCache struct {
+	mtx sync.Mutex
+
+	ttl       int64
+	firstSeen map[string]int64
+}
+
+func newTxTTLCache(ttl int64) *txTTLCache {
+	return &txTTLCache{
+		ttl:       ttl,
+		firstSeen: make(map[string]int64),
+	}
+}
+
+// checkExpired records the given height as the height at which a transaction
+// was first seen unless previously recorded. It returns an error if the
+// transaction has been pending for at least ttl blocks.
+func (c *txTTLCache) checkExpired(txBytes []byte, height int64) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	key := string(tmhash.Sum(txBytes))
+
+	firstSeen, ok := c.firstSeen[key]
+	if !ok {
+		c.firstSeen[key] = height
+		return nil
+	}
+
+	if height-firstSeen >= c.ttl {
+		delete(c.firstSeen, key)
+		return fmt.Errorf("transaction pending since height %d exceeded TTL of %d blocks", firstSeen, c.ttl)
+	}
+
+	return nil
+}
+
+// remove stops tracking a given transaction.
+func (c *txTTLCache) remove(txBytes []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.firstSeen, string(tmhash.Sum(txBytes)))
+}
+
+// prune stops tracking all transactions that expired prior to a given height.
+// It ensures transactions that are never rechecked are not tracked forever.
+// Transactions expiring at the given height are kept so they still fail
+// their upcoming recheck.
+func (c *txTTLCache) prune(height int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for key, firstSeen := range c.firstSeen {
+		if height-firstSeen > c.ttl {
+			delete(c.firstSeen, key)
+		}
+	}
+}
+
+// SetMempoolTTL returns an option that sets the number of blocks a transaction
+// may remain pending in the mempool. Transactions exceeding their TTL fail
+// upon mempool recheck and are therefore dropped, unblocking the sender's
+// nonce sequence. A TTL of zero disables expiry. It panics if the application
+// is already sealed.
+func SetMempoolTTL(blocks int64) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetMempoolTTL() on sealed EthermintApp")
+		}
+
+		if blocks <= 0 {
+			app.txTTLCache = nil
+			return
+		}
+
+		app.txTTLCache = newTxTTLCache(blocks)
+	}
+}
+
+// DisableTxIndexing returns an option that stops tagging Ethereum transactions
+// with TagEthTxHash, TagEthFrom and TagEthTo, for nodes that serve no receipts
+// or account histories, e.g. validators, so that Tendermint's indexer stores
+// nothing for them even if it indexes all tags. It panics if the application
+// is already sealed.
+//
+// NOTE: Indexing itself is disabled by Tendermint's tx_index configuration.
+// The application neither owns nor can prune Tendermint's index, so no
+// retention window is offered: nodes that must bound their index should index
+// no tags and rely on an EventSink instead.
+func DisableTxIndexing() func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("DisableTxIndexing() on sealed EthermintApp")
+		}
+
+		app.txIndexDisabled = true
+	}
+}
+
+// CheckTx implements the ABCI application interface. It fails any transaction
+// that does not pass the stateless prechecks or that exceeded the mempool TTL
+// prior to performing the regular checks.
+func (app *EthermintApp) CheckTx(txBytes []byte) abci.ResponseCheckTx {
+	if reason, err := precheckTx(txBytes, app.precheckConfig, app.decodeStdTx, app.zeroGasAllowed, app.codespace); err != nil {
+		app.precheckMetrics.Rejects.With("reason", reason).Add(1)
+
+		result := err.Result()
+		return abci.ResponseCheckTx{Code: uint32(result.Code), Log: result.Log}
+	}
+
+	if app.txTTLCache == nil {
+		return app.BaseApp.CheckTx(txBytes)
+	}
+
+	if err := app.txTTLCache.checkExpired(txBytes, app.LastBlockHeight()); err != nil {
+		result := types.ErrTxExpired(app.codespace, err.Error()).Result()
+		return abci.ResponseCheckTx{Code: uint32(result.Code), Log: result.Log}
+	}
+
+	res := app.BaseApp.CheckTx(txBytes)
+	if res.IsErr() {
+		app.txTTLCache.remove(txBytes)
+	}
+
+	return res
+}
+
+// DeliverTx implements the ABCI application interface. Any transaction
+// included in a block is no longer pending. An Ethereum transaction is tagged
+// with its hash, sender and recipient even if it fails, as it is included in
+// the block regardless, unless indexing is disabled. An Ethereum transaction
+// failing was not executed and uses no gas (see ethGasMeter).
+func (app *EthermintApp) DeliverTx(txBytes []byte) abci.ResponseDeliverTx {
+	if app.txTTLCache != nil {
+		app.txTTLCache.remove(txBytes)
+	}
+
+	start := time.Now()
+	res := app.BaseApp.DeliverTx(txBytes)
+	elapsed := time.Since(start)
+
+	tx, isEthTx := decodeEthTx(txBytes)
+	if isEthTx && res.IsErr() {
+		// the gas meter of a transaction rejected by the ante handler is
+		// that of the block
+		res.GasUsed = 0
+	}
+
+	if app.profiler != nil {
+		app.profiler.record(app.Logger, txBytes, res, elapsed)
+	}
+
+	if isEthTx && !app.txIndexDisabled {
+		res.Tags = append(res.Tags, app.ethTxTags(tx)...)
+	}
+
+	if app.eventWAL != nil {
+		app.eventWAL.block.Txs = append(app.eventWAL.block.Txs, TxEvents{Code: res.Code, Tags: res.Tags})
+	}
+
+	return res
+}
+
+// Commit implements the ABCI application interface. The events of the block
+// are written ahead of committing its state, if an event sink is set, and
+// indexed once committed.
+func (app *EthermintApp) Commit() abci.ResponseCommit {
+	if app.eventWAL != nil {
+		app.eventWAL.write()
+	}
+
+	res := app.BaseApp.Commit()
+
+	if app.eventWAL != nil {
+		if err := app.eventWAL.flush(); err != nil {
+			app.Logger.Error("failed to index block events", "err", err)
+		}
+	}
+
+	if app.txTTLCache != nil {
+		app.txTTLCache.prune(app.LastBlockHeight())
+	}
+
+	if app.stateDiffs != nil {
+		app.stateDiffs.prune(app.LastBlockHeight())
+	}
+
+	if app.profiler != nil {
+		app.profiler.commit()
+	}
+
+	return res
+}
+
+// ethTxHash returns the hash of the given transaction if it is an Ethereum
+// transaction that is not an EmbeddedTx.
+func ethTxHash(txBytes []byte) (ethcmn.Hash, bool) {
+	tx, ok := decodeEthTx(txBytes)
+	if !ok {
+		return ethcmn.Hash{}, false
+	}
+
+	return tx.Hash(), true
+}
+
+// decodeEthTx decodes the given transaction bytes as an Ethereum transaction.
+// A boolean is returned reflecting if the bytes encode one.
+func decodeEthTx(txBytes []byte) (*types.Transaction, bool) {
+	if !isRLPList(txBytes) {
+		return nil, false
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(txBytes, tx); err != nil || tx.IsEmbeddedTx() {
+		return nil, false
+	}
+
+	return tx, true
+}
+
+// ethTxTags returns the tags indexing the given Ethereum transaction. A
+// transaction whose sender cannot be derived, which the ante handler rejects,
+// is only tagged with its hash.
+func (app *EthermintApp) ethTxTags(tx *types.Transaction) []cmn.KVPair {
+	tags := []cmn.KVPair{{Key: []byte(TagEthTxHash), Value: []byte(tx.Hash().Hex())}}
+
+	// the transaction is delivered in the block following the last committed
+	height := big.NewInt(app.LastBlockHeight() + 1)
+
+	if from, err := tx.Sender(core.NewChainConfig(app.ethChainID), height); err == nil {
+		tags = append(tags, cmn.KVPair{Key: []byte(TagEthFrom), Value: []byte(from.Hex())})
+	}
+
+	if to := tx.To(); to != nil {
+		tags = append(tags, cmn.KVPair{Key: []byte(TagEthTo), Value: []byte(to.Hex())})
+	}
+
+	return tags
+}
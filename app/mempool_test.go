@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func TestTxTTLCache(t *testing.T) {
+	cache := newTxTTLCache(2)
+
+	tx1, tx2 := []byte("tx1"), []byte("tx2")
+
+	testCases := []struct {
+		txBytes   []byte
+		height    int64
+		expectErr bool
+	}{
+		{tx1, 10, false},
+		{tx1, 11, false},
+		{tx2, 11, false},
+		{tx1, 12, true},
+		// an expired transaction is no longer tracked
+		{tx1, 12, false},
+		{tx2, 12, false},
+		{tx2, 13, true},
+	}
+
+	for i, tc := range testCases {
+		err := cache.checkExpired(tc.txBytes, tc.height)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
+
+func TestTxTTLCachePrune(t *testing.T) {
+	cache := newTxTTLCache(2)
+
+	require.Nil(t, cache.checkExpired([]byte("tx1"), 10))
+	require.Nil(t, cache.checkExpired([]byte("tx2"), 11))
+
+	// transactions expiring at the given height must be kept for their recheck
+	cache.prune(12)
+	require.Len(t, cache.firstSeen, 2)
+
+	cache.prune(13)
+	require.Len(t, cache.firstSeen, 1)
+
+	cache.remove([]byte("tx2"))
+	require.Empty(t, cache.firstSeen)
+}
+
+func TestDeliverTxEthTxHashTag(t *testing.T) {
+	chain := newTestChain(t, "test-chain", SetEthChainID(big.NewInt(3)))
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+
+	tx := types.NewTransaction(0, ethcmn.BytesToAddress([]byte("recipient")), big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(3), privKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	hashTag := cmn.KVPair{Key: []byte(TagEthTxHash), Value: []byte(tx.Hash().Hex())}
+	fromTag := cmn.KVPair{Key: []byte(TagEthFrom), Value: []byte(ethcmn.BytesToAddress(sender).Hex())}
+	toTag := cmn.KVPair{Key: []byte(TagEthTo), Value: []byte(tx.To().Hex())}
+
+	// a transaction failing execution is included all the same, consuming
+	// the sender's nonce, and is tagged so that its receipt can be served
+	res := chain.nextBlock(txBytes, []byte("not an ethereum tx"))
+	require.Equal(t, types.ErrTxNotExecutable(chain.app.codespace, "").ABCICode(), sdk.ABCICodeType(res[0].Code))
+	require.Equal(t, TagMsgGasUsed, string(res[0].Tags[0].Key))
+	require.Equal(t, []cmn.KVPair{hashTag, fromTag, toTag}, res[0].Tags[1:])
+	require.Equal(t, int64(1), chain.account(sender).GetSequence())
+	require.Empty(t, res[1].Tags)
+
+	// no transaction is tagged once indexing is disabled
+	chain = newTestChain(t, "test-chain", SetEthChainID(big.NewInt(3)), DisableTxIndexing())
+
+	res = chain.nextBlock(txBytes)
+	require.Len(t, res[0].Tags, 1)
+	require.Equal(t, TagMsgGasUsed, string(res[0].Tags[0].Key))
+	require.Equal(t, int64(1), chain.account(sender).GetSequence())
+
+	require.Panics(t, func() { DisableTxIndexing()(chain.app) })
+}
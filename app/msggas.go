@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TagMsgGasUsed is the tag emitted with the gas consumed by every message of
+// a transaction. As the tags of all messages are appended in order, the n-th
+// occurrence of the tag corresponds to the n-th message of the transaction.
+const TagMsgGasUsed = "msg.gasUsed"
+
+// meterMsgGas wraps a given handler so that the gas consumed by each message
+// it handles is recorded, allowing the cost of every message of a
+// multi-message transaction to be attributed. The gas used is emitted as a tag
+// and appended to the message's log which the BaseApp includes, prefixed by the
+// message's index, in the transaction's result log.
+func meterMsgGas(handler sdk.Handler) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		start := ctx.GasMeter().GasConsumed()
+
+		res := handler(ctx, msg)
+		gasUsed := ctx.GasMeter().GasConsumed() - start
+
+		res.Tags = res.Tags.AppendTag(TagMsgGasUsed, []byte(strconv.FormatInt(gasUsed, 10)))
+
+		if res.Log != "" {
+			res.Log = fmt.Sprintf("%s; gas used: %d", res.Log, gasUsed)
+		} else {
+			res.Log = fmt.Sprintf("gas used: %d", gasUsed)
+		}
+
+		return res
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestMeterMsgGas(t *testing.T) {
+	testCases := []struct {
+		gas         int64
+		log         string
+		expectedLog string
+	}{
+		{0, "", "gas used: 0"},
+		{100, "", "gas used: 100"},
+		{250, "transferred", "transferred; gas used: 250"},
+	}
+
+	for i, tc := range testCases {
+		ctx := sdk.NewContext(nil, abci.Header{}, false, log.NewNopLogger()).
+			WithGasMeter(sdk.NewGasMeter(1000))
+
+		// gas consumed prior to the message must not be attributed to it
+		ctx.GasMeter().ConsumeGas(10, "ante")
+
+		handler := meterMsgGas(func(ctx sdk.Context, _ sdk.Msg) sdk.Result {
+			ctx.GasMeter().ConsumeGas(tc.gas, "test")
+			return sdk.Result{Log: tc.log, Tags: sdk.NewTags("key", []byte("value"))}
+		})
+
+		res := handler(ctx, nil)
+		require.Equal(t, tc.expectedLog, res.Log, fmt.Sprintf("unexpected log: test case #%d", i))
+		require.Equal(
+			t, sdk.NewTags("key", []byte("value"), TagMsgGasUsed, []byte(fmt.Sprint(tc.gas))), res.Tags,
+			fmt.Sprintf("unexpected tags: test case #%d", i),
+		)
+	}
+}
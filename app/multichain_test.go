@@ -0,0 +1,125 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/mint"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// testChain drives an EthermintApp through ABCI block by block, as a single
+// validator chain would, so that several isolated chains can be run side by
+// side within a single test.
+type testChain struct {
+	app    *EthermintApp
+	header abci.Header
+}
+
+func newTestChain(t *testing.T, chainID string, opts ...func(*EthermintApp)) *testChain {
+	return newTestChainWithGenesis(t, chainID, func(*GenesisState) {}, opts...)
+}
+
+// newTestChainWithGenesis returns a testChain initialized with the default
+// genesis state modified by the given function.
+func newTestChainWithGenesis(
+	t *testing.T, chainID string, modify func(*GenesisState), opts ...func(*EthermintApp),
+) *testChain {
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), opts...)
+
+	genesis := DefaultGenesisState(app.ethChainID)
+	modify(&genesis)
+
+	appState, err := json.Marshal(genesis)
+	require.Nil(t, err)
+
+	app.InitChain(abci.RequestInitChain{ChainId: chainID, AppStateBytes: appState})
+
+	return &testChain{app: app, header: abci.Header{ChainID: chainID}}
+}
+
+// nextBlock executes and commits a block containing the given transactions,
+// returning their results.
+func (c *testChain) nextBlock(txs ...[]byte) []abci.ResponseDeliverTx {
+	c.header.Height++
+	c.header.Time++
+	c.app.BeginBlock(abci.RequestBeginBlock{Header: c.header})
+
+	results := make([]abci.ResponseDeliverTx, len(txs))
+	for i, tx := range txs {
+		results[i] = c.app.DeliverTx(tx)
+	}
+
+	c.app.EndBlock(abci.RequestEndBlock{Height: c.header.Height})
+	c.header.AppHash = c.app.Commit().Data
+
+	return results
+}
+
+// account returns the committed account at the given address, if any.
+func (c *testChain) account(addr sdk.AccAddress) auth.Account {
+	ctx := c.app.NewContext(true, abci.Header{ChainID: c.header.ChainID})
+	return c.app.accountMapper.GetAccount(ctx, addr)
+}
+
+func TestMultipleChains(t *testing.T) {
+	feeCollectorB := sdk.AccAddress([]byte("fee_collector_b"))
+
+	reward := sdk.Coins{sdk.NewCoin(types.DenomDefault, 10)}
+	withReward := func(g *GenesisState) { g.Mint.Params.BlockReward = reward }
+
+	chainA := newTestChainWithGenesis(t, "chain-a", withReward, SetEthChainID(big.NewInt(3)))
+	chainB := newTestChainWithGenesis(t, "chain-b", withReward, SetEthChainID(big.NewInt(4)), SetFeeCollector(feeCollectorB))
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+
+	tx := types.NewTransaction(0, ethcmn.BytesToAddress([]byte("recipient")), big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(3), privKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	// a transaction signed for one chain cannot be replayed on the other
+	chainA.nextBlock(txBytes)
+	res := chainB.nextBlock(txBytes)
+
+	require.Equal(t, types.ErrInvalidSender(chainB.app.codespace, "").ABCICode(), sdk.ABCICodeType(res[0].Code))
+	require.Equal(t, int64(1), chainA.account(sender).GetSequence())
+	require.Nil(t, chainB.account(sender))
+
+	// each chain mints into its own fee collector
+	require.Equal(t, reward, chainA.account(mint.DefaultFeeCollectorAddr).GetCoins())
+	require.Nil(t, chainA.account(feeCollectorB))
+	require.Equal(t, reward, chainB.account(feeCollectorB).GetCoins())
+	require.Nil(t, chainB.account(mint.DefaultFeeCollectorAddr))
+
+	require.NotEqual(t, chainA.header.AppHash, chainB.header.AppHash)
+
+	// a chain replayed alongside the others reaches the same state
+	replayA := newTestChainWithGenesis(t, "chain-a", withReward, SetEthChainID(big.NewInt(3)))
+	replayA.nextBlock(txBytes)
+
+	for i := 0; i < 3; i++ {
+		chainA.nextBlock()
+		chainB.nextBlock()
+		replayA.nextBlock()
+
+		require.Equal(t, chainA.header.AppHash, replayA.header.AppHash, fmt.Sprintf("unexpected app hash: block #%d", i))
+	}
+}
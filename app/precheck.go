@@ -0,0 +1,267 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/types"
+
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Reasons a transaction may be rejected by the prechecks, used to label the
+// rejects metric.
+const (
+	rejectTxSize       = "tx_size"
+	rejectDecode       = "decode"
+	rejectInvalid      = "invalid"
+	rejectTooManyMsgs  = "too_many_msgs"
+	rejectGasPrice     = "gas_price"
+	rejectInitCodeSize = "init_code_size"
+	rejectIntrinsicGas = "intrinsic_gas"
+	rejectPayloadGas   = "payload_gas"
+)
+
+// PrecheckConfig defines the configuration of the stateless checks performed
+// on every transaction prior to any checks requiring state access.
+type PrecheckConfig struct {
+	// MaxTxSize is the maximum size of an encoded transaction in bytes
+	MaxTxSize int
+
+	// MinGasPrice is the minimum gas price of an Ethereum transaction
+	MinGasPrice *big.Int
+
+	// MinGasPerPayloadByte is the minimum gas limit an Ethereum transaction
+	// must provide per byte of payload, rejecting large payloads that barely
+	// cover their intrinsic gas (zero disables the check)
+	MinGasPerPayloadByte uint64
+
+	// MaxInitCodeSize is the maximum size of the init code of a contract
+	// creation in bytes (zero disables the check)
+	MaxInitCodeSize int
+
+	// MaxMsgs is the maximum number of messages of an SDK transaction (zero
+	// disables the check)
+	MaxMsgs int
+}
+
+// PrecheckMetrics contains the metrics exposed by the prechecks.
+type PrecheckMetrics struct {
+	// Rejects is the number of transactions rejected, labeled by reason
+	Rejects metrics.Counter
+}
+
+// DefaultPrecheckConfig returns the default PrecheckConfig.
+func DefaultPrecheckConfig() PrecheckConfig {
+	return PrecheckConfig{
+		MaxTxSize:            32 * 1024,
+		MinGasPrice:          big.NewInt(1),
+		MinGasPerPayloadByte: 16,
+		MaxInitCodeSize:      2 * ethparams.MaxCodeSize,
+		MaxMsgs:              16,
+	}
+}
+
+// PrometheusPrecheckMetrics returns PrecheckMetrics built using the Prometheus
+// client library and registered with its default registry.
+func PrometheusPrecheckMetrics(namespace string) *PrecheckMetrics {
+	return &PrecheckMetrics{
+		Rejects: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "precheck",
+			Name:      "rejects",
+			Help:      "Number of transactions rejected by the CheckTx prechecks.",
+		}, []string{"reason"}),
+	}
+}
+
+// NopPrecheckMetrics returns no-op PrecheckMetrics.
+func NopPrecheckMetrics() *PrecheckMetrics {
+	return &PrecheckMetrics{
+		Rejects: discard.NewCounter(),
+	}
+}
+
+// SetPrecheckConfig returns an option that sets the configuration of the
+// stateless checks performed on every transaction entering the mempool. It
+// panics if the application is already sealed.
+func SetPrecheckConfig(config PrecheckConfig) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetPrecheckConfig() on sealed EthermintApp")
+		}
+
+		app.precheckConfig = config
+	}
+}
+
+// SetPrecheckMetrics returns an option that sets the metrics reporting the
+// transactions rejected by the prechecks. It panics if the application is
+// already sealed.
+func SetPrecheckMetrics(m *PrecheckMetrics) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetPrecheckMetrics() on sealed EthermintApp")
+		}
+
+		app.precheckMetrics = m
+	}
+}
+
+// precheckTx performs lightweight stateless validation of an encoded
+// transaction, returning the reason of any rejection along with the error. Any
+// transaction exceeding the maximum size is rejected. A transaction encoded as
+// an RLP list is treated as an Ethereum transaction and must be well-formed,
+// pay at least the minimum gas price, respect the init code size limit and
+// provide enough gas to cover both its intrinsic gas and the minimum gas per
+// payload byte. An Ethereum transaction paying a zero gas price is exempt from
+// the gas price checks if the given zeroGas function allows it. Any other
+// transaction decoded by the given decoder may not contain more than the
+// maximum number of messages and is otherwise left to the regular checks.
+// Errors are raised in the given codespace.
+func precheckTx(
+	txBytes []byte, config PrecheckConfig, decoder sdk.TxDecoder, zeroGas func([]byte) bool,
+	codespace sdk.CodespaceType,
+) (string, sdk.Error) {
+
+	if len(txBytes) > config.MaxTxSize {
+		return rejectTxSize, types.ErrTxTooLarge(
+			codespace, fmt.Sprintf("size %d exceeds maximum of %d", len(txBytes), config.MaxTxSize),
+		)
+	}
+
+	if !isRLPList(txBytes) {
+		return precheckMsgs(txBytes, config, decoder, codespace)
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(txBytes, tx); err != nil {
+		return rejectDecode, sdk.ErrTxDecode(err.Error())
+	}
+
+	if reason, err := precheckGasPrice(txBytes, tx, config, zeroGas, codespace); err != nil {
+		return reason, err
+	}
+
+	if config.MaxInitCodeSize > 0 && tx.To() == nil && len(tx.Data()) > config.MaxInitCodeSize {
+		return rejectInitCodeSize, types.ErrInitCodeSize(
+			codespace,
+			fmt.Sprintf("init code size %d exceeds maximum of %d", len(tx.Data()), config.MaxInitCodeSize),
+		)
+	}
+
+	intrinsicGas, err := ethcore.IntrinsicGas(tx.Data(), tx.To() == nil, true)
+	if err != nil {
+		return rejectIntrinsicGas, types.ErrIntrinsicGas(codespace, err.Error())
+	}
+
+	if tx.Gas() < intrinsicGas {
+		return rejectIntrinsicGas, types.ErrIntrinsicGas(
+			codespace, fmt.Sprintf("gas limit %d below intrinsic gas %d", tx.Gas(), intrinsicGas),
+		)
+	}
+
+	// the payload size is bounded by the maximum transaction size so the
+	// product cannot overflow for any sensible ratio
+	if minGas := uint64(len(tx.Data())) * config.MinGasPerPayloadByte; tx.Gas() < minGas {
+		return rejectPayloadGas, types.ErrPayloadGas(
+			codespace,
+			fmt.Sprintf("gas limit %d below minimum of %d for %d payload bytes", tx.Gas(), minGas, len(tx.Data())),
+		)
+	}
+
+	return "", nil
+}
+
+// precheckGasPrice rejects an Ethereum transaction that is malformed or pays
+// less than the minimum gas price, unless it pays a zero gas price and the
+// given zeroGas function allows it.
+func precheckGasPrice(
+	txBytes []byte, tx *types.Transaction, config PrecheckConfig, zeroGas func([]byte) bool,
+	codespace sdk.CodespaceType,
+) (string, sdk.Error) {
+
+	if tx.GasPrice().Sign() == 0 && zeroGas != nil && zeroGas(txBytes) {
+		return "", nil
+	}
+
+	if err := tx.ValidateBasic(); err != nil {
+		return rejectInvalid, err
+	}
+
+	if config.MinGasPrice != nil && tx.GasPrice().Cmp(config.MinGasPrice) < 0 {
+		return rejectGasPrice, types.ErrGasPriceTooLow(
+			codespace, fmt.Sprintf("gas price %s below minimum of %s", tx.GasPrice(), config.MinGasPrice),
+		)
+	}
+
+	return "", nil
+}
+
+// precheckMsgs rejects a non-Ethereum transaction containing more than the
+// maximum number of messages. A transaction that cannot be decoded is left to
+// the regular checks.
+func precheckMsgs(
+	txBytes []byte, config PrecheckConfig, decoder sdk.TxDecoder, codespace sdk.CodespaceType,
+) (string, sdk.Error) {
+
+	if config.MaxMsgs <= 0 || decoder == nil {
+		return "", nil
+	}
+
+	tx, err := decoder(txBytes)
+	if err != nil {
+		return "", nil
+	}
+
+	if numMsgs := len(tx.GetMsgs()); numMsgs > config.MaxMsgs {
+		return rejectTooManyMsgs, types.ErrTooManyMsgs(
+			codespace, fmt.Sprintf("%d messages exceed maximum of %d", numMsgs, config.MaxMsgs),
+		)
+	}
+
+	return "", nil
+}
+
+// decodeStdTx decodes an SDK transaction the same way the BaseApp's default
+// transaction decoder does.
+func (app *EthermintApp) decodeStdTx(txBytes []byte) (sdk.Tx, sdk.Error) {
+	var tx auth.StdTx
+	if err := app.codec.UnmarshalBinary(txBytes, &tx); err != nil {
+		return nil, sdk.ErrTxDecode(err.Error())
+	}
+
+	return tx, nil
+}
+
+// zeroGasAllowed returns true if the given transaction embeds an SDK
+// transaction whose messages the circuit module allows to be submitted with a
+// zero gas price, as of the last committed state.
+func (app *EthermintApp) zeroGasAllowed(txBytes []byte) bool {
+	tx, err := types.TxDecoder(app.codec)(txBytes)
+	if err != nil {
+		return false
+	}
+
+	embeddedTx, ok := tx.(types.EmbeddedTx)
+	if !ok {
+		return false
+	}
+
+	return app.circuitKeeper.ZeroGasAllowed(app.NewContext(true, abci.Header{}), embeddedTx.GetMsgs())
+}
+
+// isRLPList returns true if the given bytes consist of exactly one RLP list.
+func isRLPList(bz []byte) bool {
+	kind, _, rest, err := rlp.Split(bz)
+	return err == nil && kind == rlp.List && len(rest) == 0
+}
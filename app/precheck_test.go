@@ -0,0 +1,214 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/circuit"
+	"github.com/cosmos/ethermint/x/ica"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func encodeTestTx(t *testing.T, tx *types.Transaction) []byte {
+	privKey, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	tx.Sign(big.NewInt(3), privKey)
+
+	bz, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	return bz
+}
+
+func TestPrecheckTx(t *testing.T) {
+	config := PrecheckConfig{MaxTxSize: 256, MinGasPrice: big.NewInt(2)}
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+
+	validTx := encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(2), nil))
+
+	testCases := []struct {
+		txBytes      []byte
+		expectedCode uint32
+	}{
+		{validTx, 0},
+		// non-Ethereum transactions are left to the regular checks
+		{[]byte("not an ethereum tx"), 0},
+		{bytes.Repeat([]byte{0x01}, 257), uint32(types.CodeTxTooLarge)},
+		// truncated transactions are no longer well-formed RLP lists
+		{validTx[:len(validTx)-1], 0},
+		{append([]byte{0xc1}, 0x01), 2},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(0), nil)), uint32(types.CodeInvalidValue)},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil)), uint32(types.CodeGasPriceTooLow)},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 20999, big.NewInt(2), nil)), uint32(types.CodeIntrinsicGas)},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(2), []byte("data"))), uint32(types.CodeIntrinsicGas)},
+		{encodeTestTx(t, types.NewContractCreation(0, big.NewInt(1), 21000, big.NewInt(2), nil)), uint32(types.CodeIntrinsicGas)},
+		{encodeTestTx(t, types.NewContractCreation(0, big.NewInt(1), 53000, big.NewInt(2), nil)), 0},
+	}
+
+	for i, tc := range testCases {
+		_, err := precheckTx(tc.txBytes, config, nil, nil, types.DefaultCodespace)
+
+		if tc.expectedCode == 0 {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			continue
+		}
+
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		require.Equal(t, tc.expectedCode, uint32(err.Code())&0xffff, fmt.Sprintf("unexpected code: test case #%d", i))
+	}
+}
+
+func TestPrecheckTxHeuristics(t *testing.T) {
+	config := PrecheckConfig{MaxTxSize: 1024, MinGasPerPayloadByte: 250, MaxInitCodeSize: 64, MaxMsgs: 2}
+	to := ethcmn.BytesToAddress([]byte("recipient"))
+	payload := bytes.Repeat([]byte{0x00}, 100)
+
+	decoder := func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		if len(txBytes) == 0 {
+			return nil, sdk.ErrTxDecode("empty")
+		}
+
+		return auth.StdTx{Msgs: make([]sdk.Msg, len(txBytes))}, nil
+	}
+
+	testCases := []struct {
+		txBytes        []byte
+		expectedReason string
+		expectedCode   uint32
+	}{
+		// 100 zero bytes require an intrinsic gas of 21400 but a payload gas of 25000
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 22000, big.NewInt(1), payload)), rejectPayloadGas, uint32(types.CodePayloadGas)},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), payload)), rejectIntrinsicGas, uint32(types.CodeIntrinsicGas)},
+		{encodeTestTx(t, types.NewTransaction(0, to, big.NewInt(1), 25000, big.NewInt(1), payload)), "", 0},
+		{encodeTestTx(t, types.NewContractCreation(0, big.NewInt(1), 100000, big.NewInt(1), payload)), rejectInitCodeSize, uint32(types.CodeInitCodeSize)},
+		{encodeTestTx(t, types.NewContractCreation(0, big.NewInt(1), 100000, big.NewInt(1), payload[:64])), "", 0},
+		{[]byte{0x01, 0x02}, "", 0},
+		{[]byte{0x01, 0x02, 0x03}, rejectTooManyMsgs, uint32(types.CodeTooManyMsgs)},
+		// undecodable transactions are left to the regular checks
+		{[]byte{}, "", 0},
+	}
+
+	for i, tc := range testCases {
+		reason, err := precheckTx(tc.txBytes, config, decoder, nil, types.DefaultCodespace)
+		require.Equal(t, tc.expectedReason, reason, fmt.Sprintf("unexpected reason: test case #%d", i))
+
+		if tc.expectedCode == 0 {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			continue
+		}
+
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		require.Equal(t, tc.expectedCode, uint32(err.Code())&0xffff, fmt.Sprintf("unexpected code: test case #%d", i))
+	}
+}
+
+func TestCheckTxPrecheck(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), SetPrecheckConfig(PrecheckConfig{MaxTxSize: 8}))
+
+	res := app.CheckTx(bytes.Repeat([]byte{0x01}, 9))
+	require.Equal(t, types.ErrTxTooLarge(app.codespace, "").ABCICode(), sdk.ABCICodeType(res.Code))
+}
+
+// testCounter implements the metrics.Counter interface, counting the
+// additions made per label values.
+type testCounter struct {
+	labelValues []string
+	counts      map[string]float64
+}
+
+func (c *testCounter) With(labelValues ...string) metrics.Counter {
+	return &testCounter{labelValues: append(c.labelValues, labelValues...), counts: c.counts}
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.counts[fmt.Sprint(c.labelValues)] += delta
+}
+
+func TestCheckTxPrecheckMetrics(t *testing.T) {
+	rejects := &testCounter{counts: make(map[string]float64)}
+
+	app := NewEthermintApp(
+		log.NewNopLogger(), dbm.NewMemDB(),
+		SetPrecheckConfig(PrecheckConfig{MaxTxSize: 8}),
+		SetPrecheckMetrics(&PrecheckMetrics{Rejects: rejects}),
+	)
+
+	app.CheckTx(bytes.Repeat([]byte{0x01}, 9))
+	app.CheckTx(bytes.Repeat([]byte{0x01}, 10))
+
+	require.Equal(t, map[string]float64{fmt.Sprint([]string{"reason", rejectTxSize}): 2}, rejects.counts)
+}
+
+func TestCheckTxZeroGas(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{ChainId: "ethermint"})
+
+	ctx := app.NewContext(true, abci.Header{ChainID: "ethermint"})
+
+	authorityKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	authority := sdk.AccAddress(authorityKey.PubKey().Address())
+	app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, authority))
+
+	embeddedTx := func(msg sdk.Msg, gasPrice int64) []byte {
+		tx, err := types.NewEmbeddedTxBuilder(app.codec, "ethermint", msg).
+			WithGas(1000000, big.NewInt(gasPrice)).
+			Sign(authorityKey, 0, 0).
+			Build(big.NewInt(DefaultEthChainID), authorityKey.ToECDSA())
+		require.Nil(t, err)
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	circuitMsg := circuit.NewMsgSetDisabled(authority)
+	icaMsg := ica.NewMsgRemoteExecute("chain-a", "alice", authority, ethcmn.Address{}, sdk.ZeroInt(), nil, 1)
+
+	// no message may be submitted with a zero gas price by default
+	res := app.CheckTx(embeddedTx(circuitMsg, 0))
+	require.Equal(t, uint32(types.CodeInvalidValue), res.Code&0xffff, res.Log)
+
+	params := circuit.Params{Authority: authority, ZeroGasMsgTypes: []string{circuit.MsgType}}
+	require.Nil(t, circuit.InitGenesis(ctx, app.circuitKeeper, circuit.GenesisState{Params: params}))
+
+	testCases := []struct {
+		tx           []byte
+		expectedCode uint32
+	}{
+		{embeddedTx(icaMsg, 0), uint32(types.CodeInvalidValue)},
+		{embeddedTx(circuitMsg, 0), 0},
+	}
+
+	for i, tc := range testCases {
+		res := app.CheckTx(tc.tx)
+		require.Equal(t, tc.expectedCode, res.Code&0xffff, fmt.Sprintf("unexpected code: test case #%d: %s", i, res.Log))
+	}
+
+	// Ethereum transactions always pay a gas price
+	ethTx := types.NewTransaction(1, ethcmn.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+	ethTx.Sign(big.NewInt(DefaultEthChainID), authorityKey.ToECDSA())
+
+	ethTxBytes, err := rlp.EncodeToBytes(ethTx)
+	require.Nil(t, err)
+
+	res = app.CheckTx(ethTxBytes)
+	require.Equal(t, uint32(types.CodeInvalidValue), res.Code&0xffff, res.Log)
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// priorityUnitSize defines the number of bytes the gas price of a transaction
+// is scored against, so that the scores of transactions of similar sizes and
+// low gas prices are not truncated to the same value.
+const priorityUnitSize = 1024
+
+// TxPriority returns the priority of a transaction for a mempool that orders
+// transactions by priority, as Tendermint's mempool does not yet, e.g. to
+// gossip and include the highest ones first under load. A transaction is
+// scored by its gas price per kilobyte, i.e. its gas price scaled by 1024 and
+// divided by its size, saturating at math.MaxInt64, so that economically
+// dense transactions are favoured over large ones offering the same price. An
+// embedded transaction is scored by the gas price of its Ethereum envelope.
+// Any other transaction, which does not offer a gas price, scores zero, as
+// does a malformed one, leaving its rejection to CheckTx.
+func TxPriority(txBytes []byte) int64 {
+	if !isRLPList(txBytes) {
+		return 0
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(txBytes, tx); err != nil {
+		return 0
+	}
+
+	priority := new(big.Int).Mul(tx.GasPrice(), big.NewInt(priorityUnitSize))
+	priority.Quo(priority, big.NewInt(int64(len(txBytes))))
+
+	if !priority.IsInt64() {
+		return math.MaxInt64
+	}
+
+	return priority.Int64()
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPriority(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	encode := func(gasPrice *big.Int, data []byte) []byte {
+		tx := types.NewTransaction(0, ethcmn.BytesToAddress([]byte("recipient")), big.NewInt(0), 100000, gasPrice, data)
+		tx.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	small := encode(big.NewInt(1000), nil)
+	large := encode(big.NewInt(1000), make([]byte, 2048))
+	expensive := encode(big.NewInt(2000), make([]byte, 2048))
+
+	testCases := []struct {
+		txBytes  []byte
+		expected int64
+	}{
+		{small, 1000 * 1024 / int64(len(small))},
+		{large, 1000 * 1024 / int64(len(large))},
+		{expensive, 2000 * 1024 / int64(len(expensive))},
+		{encode(big.NewInt(0), nil), 0},
+		{encode(new(big.Int).Lsh(big.NewInt(1), 100), nil), math.MaxInt64},
+		{[]byte{0x01, 0x02}, 0},
+		{nil, 0},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, TxPriority(tc.txBytes), fmt.Sprintf("unexpected priority: test case #%d", i))
+	}
+
+	// at the same gas price, the denser transaction is favoured
+	require.True(t, TxPriority(small) > TxPriority(large))
+	require.True(t, TxPriority(expensive) > TxPriority(large))
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type (
+	// BlockProfile defines the execution time of every transaction of a
+	// block, as served under QueryPathBlockProfile.
+	BlockProfile struct {
+		Height int64       `json:"height"`
+		Txs    []TxProfile `json:"txs"`
+	}
+
+	// TxProfile defines the execution time of a transaction of a block, in the
+	// order in which it was delivered. The hash is the Ethereum hash of an
+	// Ethereum transaction and the Tendermint hash of any other transaction.
+	// A transaction is slow if its execution time exceeds the threshold set
+	// by EnableBlockProfiling.
+	TxProfile struct {
+		Hash     string        `json:"hash"`
+		Code     uint32        `json:"code"`
+		GasUsed  int64         `json:"gas_used"`
+		Duration time.Duration `json:"duration"`
+		Slow     bool          `json:"slow"`
+	}
+)
+
+// blockProfiler records the execution time of the transactions of the block
+// being executed and retains the profile of the last committed block.
+type blockProfiler struct {
+	mtx sync.RWMutex
+
+	threshold time.Duration
+
+	// block holds the profile of the block being executed
+	block BlockProfile
+	// last holds the profile of the last committed block
+	last BlockProfile
+}
+
+// EnableBlockProfiling returns an option that enables recording the execution
+// time of every transaction delivered, logging every transaction exceeding the
+// given threshold, to help diagnose slow blocks. The profile of the last
+// committed block is served under QueryPathBlockProfile. A threshold of zero
+// disables profiling. It panics if the application is already sealed.
+func EnableBlockProfiling(threshold time.Duration) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableBlockProfiling() on sealed EthermintApp")
+		}
+
+		if threshold <= 0 {
+			app.profiler = nil
+			return
+		}
+
+		app.profiler = &blockProfiler{threshold: threshold}
+	}
+}
+
+// begin starts the profile of the block at the given height.
+func (p *blockProfiler) begin(height int64) {
+	p.block = BlockProfile{Height: height, Txs: []TxProfile{}}
+}
+
+// record adds the execution time of a delivered transaction to the profile of
+// the block being executed, logging the transaction if it is slow.
+func (p *blockProfiler) record(logger log.Logger, txBytes []byte, res abci.ResponseDeliverTx, d time.Duration) {
+	hash := fmt.Sprintf("%X", tmhash.Sum(txBytes))
+	if ethHash, ok := ethTxHash(txBytes); ok {
+		hash = ethHash.Hex()
+	}
+
+	tx := TxProfile{Hash: hash, Code: res.Code, GasUsed: res.GasUsed, Duration: d, Slow: d > p.threshold}
+
+	if tx.Slow {
+		logger.Info(
+			"slow transaction", "height", p.block.Height, "index", len(p.block.Txs), "hash", hash,
+			"duration", d, "gas_used", res.GasUsed,
+		)
+	}
+
+	p.block.Txs = append(p.block.Txs, tx)
+}
+
+// commit retains the profile of the block being executed as that of the last
+// committed block.
+func (p *blockProfiler) commit() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.last = p.block
+}
+
+// lastBlock returns the profile of the last committed block.
+func (p *blockProfiler) lastBlock() BlockProfile {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	return p.last
+}
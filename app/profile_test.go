@@ -0,0 +1,79 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestQueryBlockProfile(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+
+	res := app.Query(abci.RequestQuery{Path: QueryPathBlockProfile})
+	require.False(t, res.IsOK())
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	tx := types.NewTransaction(0, ethcmn.BytesToAddress([]byte("recipient")), big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(3), privKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	otherTx := []byte("not an ethereum tx")
+
+	testCases := []struct {
+		threshold  time.Duration
+		expectSlow bool
+	}{
+		{time.Nanosecond, true},
+		{time.Hour, false},
+	}
+
+	for i, tc := range testCases {
+		chain := newTestChain(t, "test-chain", SetEthChainID(big.NewInt(3)), EnableBlockProfiling(tc.threshold))
+		results := chain.nextBlock(txBytes, otherTx)
+
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathBlockProfile})
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var profile BlockProfile
+		require.Nil(t, json.Unmarshal(res.Value, &profile))
+		require.Equal(t, int64(1), profile.Height, fmt.Sprintf("unexpected height: test case #%d", i))
+		require.Len(t, profile.Txs, 2, fmt.Sprintf("unexpected profile: test case #%d", i))
+
+		require.Equal(t, tx.Hash().Hex(), profile.Txs[0].Hash)
+		require.Equal(t, fmt.Sprintf("%X", tmhash.Sum(otherTx)), profile.Txs[1].Hash)
+
+		for j, txProfile := range profile.Txs {
+			require.Equal(t, results[j].Code, txProfile.Code)
+			require.Equal(t, results[j].GasUsed, txProfile.GasUsed)
+			require.True(t, txProfile.Duration > 0)
+			require.Equal(t, tc.expectSlow, txProfile.Slow, fmt.Sprintf("unexpected slow flag: test case #%d", i))
+		}
+
+		// the profile of a block is served once committed
+		chain.app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: "test-chain", Height: 2}})
+		chain.app.DeliverTx(otherTx)
+
+		res = chain.app.Query(abci.RequestQuery{Path: QueryPathBlockProfile})
+		require.Nil(t, json.Unmarshal(res.Value, &profile))
+		require.Equal(t, int64(1), profile.Height, fmt.Sprintf("unexpected height: test case #%d", i))
+	}
+
+	require.Panics(t, func() { EnableBlockProfiling(time.Second)(app) })
+}
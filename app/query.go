@@ -0,0 +1,946 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/state"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/version"
+	"github.com/cosmos/ethermint/x/withdraw"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+const (
+	// QueryPathVersion defines the ABCI query path serving the Ethermint
+	// version.
+	QueryPathVersion = "/app/version"
+
+	// QueryPathInfo defines the ABCI query path serving the JSON encoded
+	// application metadata.
+	QueryPathInfo = "/app/info"
+
+	// QueryPathStateDiff defines the ABCI query path serving the JSON encoded
+	// state diff of the transaction whose hash is given as the query data.
+	QueryPathStateDiff = "/debug/statediff"
+
+	// QueryPathDenomMetadata defines the ABCI query path serving the JSON
+	// encoded metadata of the denom given as the query data, or the metadata
+	// of all registered denoms if none is given.
+	QueryPathDenomMetadata = "/denom/metadata"
+
+	// QueryPathTraceBlock defines the ABCI query path re-executing the
+	// Ethereum transactions of a committed block, given as a JSON encoded
+	// TraceBlockRequest, and serving their JSON encoded traces.
+	QueryPathTraceBlock = "/debug/traceblock"
+
+	// QueryPathNonce defines the ABCI query path serving the JSON encoded
+	// nonce, as of the latest committed block, of the account whose address
+	// is given as the query data, either as raw bytes or in any form accepted
+	// by types.ParseAddress.
+	QueryPathNonce = "/auth/nonce"
+
+	// QueryPathNonces defines the ABCI query path serving the JSON encoded
+	// nonces, as of the latest committed block, of the accounts whose
+	// addresses are given as a JSON encoded array in any form accepted by
+	// types.ParseAddress, in the order of the addresses.
+	QueryPathNonces = "/auth/nonces"
+
+	// QueryPathAccount defines the ABCI query path serving the account, as of
+	// the latest committed block, whose address is given as the query data in
+	// the same forms as for QueryPathNonce, as an amino JSON encoded
+	// AccountResponse.
+	QueryPathAccount = "/auth/account"
+
+	// QueryPathEstimateGas defines the ABCI query path estimating the gas of
+	// a message call, given as a JSON encoded EstimateGasRequest, and serving
+	// the JSON encoded estimate.
+	QueryPathEstimateGas = "/eth/estimategas"
+
+	// QueryPathCall defines the ABCI query path executing a message call,
+	// given as a JSON encoded EstimateGasRequest, and serving its return
+	// data. A zero gas limit defaults to maxCallGas. The query fails if the
+	// call does, with the revert reason if it reverted.
+	QueryPathCall = "/eth/call"
+
+	// QueryPathAccountCount defines the ABCI query path serving the JSON
+	// encoded number of accounts whose address starts with the prefix given
+	// as the query data, or of all accounts if none is given.
+	QueryPathAccountCount = "/auth/accounts/count"
+
+	// QueryPathAccounts defines the ABCI query path serving a page of
+	// accounts, given as a JSON encoded AccountsRequest, as an amino JSON
+	// encoded AccountsResponse.
+	QueryPathAccounts = "/auth/accounts"
+
+	// QueryPathCommitInfo defines the ABCI query path serving the JSON encoded
+	// CommitInfo of the height given in the request, or of the latest height
+	// if none is given. It lets clients verify a store proof against the
+	// application hash of a Tendermint header.
+	QueryPathCommitInfo = "/app/commitinfo"
+
+	// QueryPathLinkedAddress defines the ABCI query path serving the JSON
+	// encoded LinkedAddressResponse of the address given as the query data,
+	// in the same forms as for QueryPathNonce, which may be either the
+	// Ethereum or the legacy Cosmos address of a linked public key.
+	QueryPathLinkedAddress = "/auth/linkedaddress"
+
+	// QueryPathBlockProfile defines the ABCI query path serving the JSON
+	// encoded BlockProfile of the last committed block, if block profiling
+	// is enabled.
+	QueryPathBlockProfile = "/debug/blockprofile"
+
+	// QueryPathWithdrawAddress defines the ABCI query path serving the JSON
+	// encoded WithdrawAddressResponse of the validator operated by the
+	// address given as the query data, in the same forms as for
+	// QueryPathNonce.
+	QueryPathWithdrawAddress = "/withdraw/address"
+
+	// defaultAccountsLimit and maxAccountsLimit are the default and maximum
+	// number of accounts served per page under QueryPathAccounts.
+	defaultAccountsLimit = 100
+	maxAccountsLimit     = 1000
+
+	// maxNoncesAddresses is the maximum number of addresses whose nonces are
+	// served by a single query under QueryPathNonces.
+	maxNoncesAddresses = 1000
+
+	// maxCallGas is the gas limit of message calls executed by queries, which
+	// caps gas estimates, as no block gas limit is enforced.
+	maxCallGas = 50000000
+)
+
+// TraceBlockRequest defines the data of a QueryPathTraceBlock query. As the
+// application retains no blocks, the block's header fields and transactions
+// are provided by the caller, i.e. queried from Tendermint.
+type TraceBlockRequest struct {
+	Height        int64           `json:"height"`
+	Time          int64           `json:"time"`
+	LastBlockHash []byte          `json:"last_block_hash"`
+	Tracer        string          `json:"tracer"`
+	TracerConfig  json.RawMessage `json:"tracer_config,omitempty"`
+
+	// Txs are the RLP encoded Ethereum transactions of the block in the order
+	// in which they were included.
+	Txs [][]byte `json:"txs"`
+}
+
+// EstimateGasRequest defines the data of a QueryPathEstimateGas query. The
+// call is executed on the state as of the block at the given height, whose
+// header fields are provided by the caller, i.e. queried from Tendermint. A
+// zero gas limit defaults to maxCallGas.
+type EstimateGasRequest struct {
+	Height        int64           `json:"height"`
+	Time          int64           `json:"time"`
+	LastBlockHash []byte          `json:"last_block_hash"`
+	From          ethcmn.Address  `json:"from"`
+	To            *ethcmn.Address `json:"to"`
+	Gas           uint64          `json:"gas"`
+	GasPrice      *big.Int        `json:"gas_price"`
+	Value         *big.Int        `json:"value"`
+	Data          []byte          `json:"data"`
+}
+
+// AccountsRequest defines the data of a QueryPathAccounts query. Accounts are
+// listed in ascending address order, restricted to those whose address starts
+// with the given prefix if any. Pages are numbered from one and a zero limit
+// defaults to defaultAccountsLimit.
+type AccountsRequest struct {
+	Prefix []byte `json:"prefix"`
+	Page   int    `json:"page"`
+	Limit  int    `json:"limit"`
+}
+
+// AccountsResponse defines the result of a QueryPathAccounts query, where
+// Total is the number of accounts matching the request across all pages.
+type AccountsResponse struct {
+	Total    int64          `json:"total"`
+	Accounts []auth.Account `json:"accounts"`
+}
+
+// AccountResponse defines the result of a QueryPathAccount query. The address
+// is given both as a checksummed hex Ethereum address and as a bech32 SDK
+// account address, whichever form it was queried with. The account is nil if
+// it does not exist.
+type AccountResponse struct {
+	Address       string       `json:"address"`
+	Bech32Address string       `json:"bech32_address"`
+	Account       auth.Account `json:"account"`
+}
+
+// CommitInfo defines the result of a QueryPathCommitInfo query: the commit ID
+// of every store as of a height, sorted by store name. The application hash of
+// the height, as included in the header of the following block, is the simple
+// Merkle root of the stores' commit IDs keyed by store name.
+type CommitInfo struct {
+	Version int64           `json:"version"`
+	Stores  []StoreCommitID `json:"stores"`
+}
+
+// StoreCommitID defines the commit ID of a named store, whose hash is the root
+// hash of the store's IAVL tree.
+type StoreCommitID struct {
+	Name     string       `json:"name"`
+	CommitID sdk.CommitID `json:"commit_id"`
+}
+
+// rootMultiStoreCommitInfo mirrors the commit info persisted by the SDK's root
+// multistore for every version under rootMultiStoreCommitInfoKey, which the
+// SDK does not expose.
+type rootMultiStoreCommitInfo struct {
+	Version    int64
+	StoreInfos []rootMultiStoreInfo
+}
+
+type rootMultiStoreInfo struct {
+	Name string
+	Core struct {
+		CommitID sdk.CommitID
+	}
+}
+
+// LinkedAddressResponse defines the result of a QueryPathLinkedAddress query:
+// the Ethereum address, in checksummed hex, and the legacy Cosmos address, in
+// bech32, a public key derives, as linked through the address map module.
+type LinkedAddressResponse struct {
+	Address       string `json:"address"`
+	LegacyAddress string `json:"legacy_address"`
+}
+
+// WithdrawAddressResponse defines the result of a QueryPathWithdrawAddress
+// query: the operator address of a validator and the address its fees and
+// rewards are withdrawn to, both in checksummed hex.
+type WithdrawAddressResponse struct {
+	Validator       string `json:"validator"`
+	WithdrawAddress string `json:"withdraw_address"`
+}
+
+// AppInfo defines the application metadata served under QueryPathInfo. The
+// event backlog is the number of committed blocks whose events the event sink,
+// if any, has not indexed yet (see SetEventSink), and the codespace is the one
+// Ethermint's errors are raised in (see Codespace).
+type AppInfo struct {
+	Name                    string            `json:"name"`
+	Version                 string            `json:"version"`
+	GitCommit               string            `json:"git_commit"`
+	LastBlockHeight         int64             `json:"last_block_height"`
+	LastBlockAppHash        []byte            `json:"last_block_app_hash"`
+	Stores                  []string          `json:"stores"`
+	StateDiffsEnabled       bool              `json:"state_diffs_enabled"`
+	Pruning                 string            `json:"pruning"`
+	EarliestQueryableHeight int64             `json:"earliest_queryable_height"`
+	EventBacklog            int64             `json:"event_backlog"`
+	Codespace               sdk.CodespaceType `json:"codespace"`
+}
+
+// Info implements the ABCI application interface. It extends the BaseApp's
+// response with the Ethermint version.
+func (app *EthermintApp) Info(req abci.RequestInfo) abci.ResponseInfo {
+	res := app.BaseApp.Info(req)
+	res.Version = version.Version
+
+	return res
+}
+
+// Query implements the ABCI application interface. It serves the Ethermint
+// specific query paths and defers all others, including store queries against
+// the Ethereum contract storage and code stores, to the BaseApp. A proof may
+// also be requested by suffixing the query path with "?prove=true". Paths
+// served through keepers read the committed state as of the request's height,
+// or the latest one if none is given (see NewQueryContext).
+func (app *EthermintApp) Query(req abci.RequestQuery) abci.ResponseQuery {
+	if i := strings.Index(req.Path, "?"); i >= 0 {
+		params, err := url.ParseQuery(req.Path[i+1:])
+		if err != nil {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("invalid query parameters: %v", err)).QueryResult()
+		}
+
+		req.Path = req.Path[:i]
+		req.Prove = req.Prove || params.Get("prove") == "true"
+	}
+
+	path := strings.TrimSuffix(req.Path, "/")
+
+	switch {
+	case path == QueryPathVersion:
+		return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: []byte(version.Version)}
+
+	case path == QueryPathInfo:
+		return app.queryInfo()
+
+	case path == QueryPathStateDiff:
+		return app.queryStateDiff(req)
+
+	case path == QueryPathDenomMetadata:
+		return app.queryDenomMetadata(req)
+
+	case path == QueryPathTraceBlock:
+		return app.queryTraceBlock(req)
+
+	case path == QueryPathNonce:
+		return app.queryNonce(req)
+
+	case path == QueryPathNonces:
+		return app.queryNonces(req)
+
+	case path == QueryPathAccount:
+		return app.queryAccount(req)
+
+	case path == QueryPathEstimateGas:
+		return app.queryEstimateGas(req)
+
+	case path == QueryPathCall:
+		return app.queryCall(req)
+
+	case path == QueryPathAccountCount:
+		return app.queryAccountCount(req)
+
+	case path == QueryPathCommitInfo:
+		return app.queryCommitInfo(req)
+
+	case path == QueryPathAccounts:
+		return app.queryAccounts(req)
+
+	case path == QueryPathLinkedAddress:
+		return app.queryLinkedAddress(req)
+
+	case path == QueryPathBlockProfile:
+		return app.queryBlockProfile()
+
+	case path == QueryPathWithdrawAddress:
+		return app.queryWithdrawAddress(req)
+
+	default:
+		return app.BaseApp.Query(req)
+	}
+}
+
+func (app *EthermintApp) queryInfo() abci.ResponseQuery {
+	lastCommitID := app.LastCommitID()
+
+	info := AppInfo{
+		Name:              app.Name(),
+		Version:           version.Version,
+		GitCommit:         version.GitCommit,
+		LastBlockHeight:   lastCommitID.Version,
+		LastBlockAppHash:  lastCommitID.Hash,
+		StateDiffsEnabled: app.StateDiffsEnabled(),
+		Pruning:           app.pruning,
+		Codespace:         app.codespace,
+
+		EarliestQueryableHeight: earliestQueryableHeight(app.pruning, lastCommitID.Version),
+	}
+
+	for _, key := range app.allStoreKeys() {
+		info.Stores = append(info.Stores, key.Name())
+	}
+
+	if app.eventWAL != nil {
+		info.EventBacklog = app.eventWAL.backlog()
+	}
+
+	bz, err := json.Marshal(info)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// rootMultiStoreCommitInfoKey returns the key the SDK's root multistore
+// persists the commit info of the given version under.
+func rootMultiStoreCommitInfoKey(version int64) []byte {
+	return []byte(fmt.Sprintf("s/%d", version))
+}
+
+func (app *EthermintApp) queryCommitInfo(req abci.RequestQuery) abci.ResponseQuery {
+	height := req.Height
+	if height == 0 {
+		height = app.LastBlockHeight()
+	}
+
+	bz := app.db.Get(rootMultiStoreCommitInfoKey(height))
+	if bz == nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("no commit info at height %d", height)).QueryResult()
+	}
+
+	var stored rootMultiStoreCommitInfo
+	if err := wire.NewCodec().UnmarshalBinary(bz, &stored); err != nil {
+		return sdk.ErrInternal(fmt.Sprintf("invalid commit info at height %d: %v", height, err)).QueryResult()
+	}
+
+	info := CommitInfo{Version: stored.Version, Stores: make([]StoreCommitID, len(stored.StoreInfos))}
+	for i, si := range stored.StoreInfos {
+		info.Stores[i] = StoreCommitID{Name: si.Name, CommitID: si.Core.CommitID}
+	}
+
+	sort.Slice(info.Stores, func(i, j int) bool { return info.Stores[i].Name < info.Stores[j].Name })
+
+	bz, err := json.Marshal(info)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Height: height, Value: bz}
+}
+
+func (app *EthermintApp) queryStateDiff(req abci.RequestQuery) abci.ResponseQuery {
+	if !app.StateDiffsEnabled() {
+		return sdk.ErrUnknownRequest("state diffs are not enabled").QueryResult()
+	}
+
+	if len(req.Data) != ethcmn.HashLength {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid transaction hash length %d", len(req.Data))).QueryResult()
+	}
+
+	diff, ok := app.StateDiff(ethcmn.BytesToHash(req.Data))
+	if !ok {
+		return sdk.ErrUnknownRequest("no state diff retained for transaction").QueryResult()
+	}
+
+	bz, err := json.Marshal(diff)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+func (app *EthermintApp) queryDenomMetadata(req abci.RequestQuery) abci.ResponseQuery {
+	ctx, err := app.NewQueryContext(req.Height, req.Prove)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	var res interface{}
+	if len(req.Data) == 0 {
+		res = app.denomKeeper.GetAllMetadata(ctx)
+	} else {
+		metadata, ok := app.denomKeeper.GetMetadata(ctx, string(req.Data))
+		if !ok {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unknown denom %s", req.Data)).QueryResult()
+		}
+
+		res = metadata
+	}
+
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryNonce serves the committed nonce of an account rather than the one of
+// the check state, as the latter accounts for the nonces consumed by
+// transactions pending in the mempool. An account that does not exist has a
+// nonce of zero.
+func (app *EthermintApp) queryNonce(req abci.RequestQuery) abci.ResponseQuery {
+	addr, err := parseQueryAddress(req.Data)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	acc, err := app.committedAccount(addr)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	var nonce int64
+	if acc != nil {
+		nonce = acc.GetSequence()
+	}
+
+	bz, encErr := json.Marshal(nonce)
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryNonces serves the committed nonces of many accounts at once, sparing
+// clients a query per account, e.g. for every sender of the transactions
+// pending in the mempool.
+func (app *EthermintApp) queryNonces(req abci.RequestQuery) abci.ResponseQuery {
+	var addrs []string
+	if err := json.Unmarshal(req.Data, &addrs); err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid nonces request: %v", err)).QueryResult()
+	}
+
+	if len(addrs) > maxNoncesAddresses {
+		return sdk.ErrUnknownRequest(
+			fmt.Sprintf("too many addresses: %d exceeds %d", len(addrs), maxNoncesAddresses),
+		).QueryResult()
+	}
+
+	nonces := make([]int64, len(addrs))
+	for i, s := range addrs {
+		addr, parseErr := types.ParseAddress(s)
+		if parseErr != nil {
+			return sdk.ErrUnknownRequest(parseErr.Error()).QueryResult()
+		}
+
+		acc, err := app.committedAccount(addr)
+		if err != nil {
+			return err.QueryResult()
+		}
+
+		if acc != nil {
+			nonces[i] = acc.GetSequence()
+		}
+	}
+
+	bz, encErr := json.Marshal(nonces)
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryAccount serves the committed account at an address along with both
+// forms of the address, so that Ethereum and Cosmos tooling may each use the
+// form they expect. The account is encoded with the application's codec, as
+// it is an amino interface.
+func (app *EthermintApp) queryAccount(req abci.RequestQuery) abci.ResponseQuery {
+	addr, err := parseQueryAddress(req.Data)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	acc, err := app.committedAccount(addr)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	bz, encErr := app.codec.MarshalJSON(AccountResponse{
+		Address:       types.ChecksumHex(addr),
+		Bech32Address: types.Bech32Address(addr),
+		Account:       acc,
+	})
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// parseQueryAddress parses the address given as the data of a query, either
+// as raw bytes or in any form accepted by types.ParseAddress. As any string
+// form is longer than an address, data of an address' length is taken as raw
+// bytes.
+func parseQueryAddress(data []byte) (ethcmn.Address, sdk.Error) {
+	if len(data) == ethcmn.AddressLength {
+		return ethcmn.BytesToAddress(data), nil
+	}
+
+	addr, err := types.ParseAddress(string(data))
+	if err != nil {
+		return ethcmn.Address{}, sdk.ErrUnknownRequest(err.Error())
+	}
+
+	return addr, nil
+}
+
+// committedAccount returns the account at an address as of the latest
+// committed block, or nil if it does not exist.
+func (app *EthermintApp) committedAccount(addr ethcmn.Address) (auth.Account, sdk.Error) {
+	res := app.BaseApp.Query(abci.RequestQuery{
+		Path: fmt.Sprintf("/store/%s/key", app.keyAccount.Name()),
+		Data: auth.AddressStoreKey(addr.Bytes()),
+	})
+	if !res.IsOK() {
+		return nil, sdk.ErrInternal(res.Log)
+	}
+
+	if len(res.Value) == 0 {
+		return nil, nil
+	}
+
+	var acc auth.Account
+	if err := app.codec.UnmarshalBinaryBare(res.Value, &acc); err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+
+	return acc, nil
+}
+
+// queryLinkedAddress serves the addresses linked to the queried address. As
+// both forms of address have the same length, the queried address is looked up
+// as a legacy address first, then as an Ethereum address.
+func (app *EthermintApp) queryLinkedAddress(req abci.RequestQuery) abci.ResponseQuery {
+	addr, err := parseQueryAddress(req.Data)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	ctx, ctxErr := app.NewQueryContext(req.Height, req.Prove)
+	if ctxErr != nil {
+		return sdk.ErrUnknownRequest(ctxErr.Error()).QueryResult()
+	}
+
+	res := LinkedAddressResponse{LegacyAddress: types.Bech32Address(addr)}
+
+	if linked, ok := app.addrMapKeeper.GetEthereumAddress(ctx, addr.Bytes()); ok {
+		res.Address = types.ChecksumHex(ethcmn.BytesToAddress(linked))
+	} else if linked, ok := app.addrMapKeeper.GetLegacyAddress(ctx, addr.Bytes()); ok {
+		res.Address = types.ChecksumHex(addr)
+		res.LegacyAddress = linked.String()
+	} else {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("address %s is not linked", addr.Hex())).QueryResult()
+	}
+
+	bz, encErr := json.Marshal(res)
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+func (app *EthermintApp) queryBlockProfile() abci.ResponseQuery {
+	if app.profiler == nil {
+		return sdk.ErrUnknownRequest("block profiling is not enabled").QueryResult()
+	}
+
+	bz, err := json.Marshal(app.profiler.lastBlock())
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+func (app *EthermintApp) queryWithdrawAddress(req abci.RequestQuery) abci.ResponseQuery {
+	addr, err := parseQueryAddress(req.Data)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	ctx, ctxErr := app.NewQueryContext(req.Height, req.Prove)
+	if ctxErr != nil {
+		return sdk.ErrUnknownRequest(ctxErr.Error()).QueryResult()
+	}
+
+	if app.stakeKeeper.Validator(ctx, addr.Bytes()) == nil {
+		return withdraw.ErrNotValidator(app.withdrawKeeper.Codespace(), "").QueryResult()
+	}
+
+	withdrawAddr := app.withdrawKeeper.GetWithdrawAddress(ctx, addr.Bytes())
+	res := WithdrawAddressResponse{
+		Validator:       types.ChecksumHex(addr),
+		WithdrawAddress: types.ChecksumHex(ethcmn.BytesToAddress(withdrawAddr)),
+	}
+
+	bz, encErr := json.Marshal(res)
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryAccountCount counts the accounts by iterating over them, so that no
+// count must be maintained by the account mapper, at a cost linear in the
+// number of accounts counted.
+func (app *EthermintApp) queryAccountCount(req abci.RequestQuery) abci.ResponseQuery {
+	ctx, err := app.NewQueryContext(req.Height, req.Prove)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	var count int64
+	types.IterateAccounts(ctx, app.codec, app.keyAccount, req.Data, func(auth.Account) bool {
+		count++
+		return false
+	})
+
+	bz, err := json.Marshal(count)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryAccounts serves a page of accounts. The accounts are encoded with the
+// application's codec, as they are amino interfaces.
+func (app *EthermintApp) queryAccounts(req abci.RequestQuery) abci.ResponseQuery {
+	var accountsReq AccountsRequest
+	if err := json.Unmarshal(req.Data, &accountsReq); err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid accounts request: %v", err)).QueryResult()
+	}
+
+	if accountsReq.Limit == 0 {
+		accountsReq.Limit = defaultAccountsLimit
+	}
+
+	if accountsReq.Page < 1 || accountsReq.Limit < 0 || accountsReq.Limit > maxAccountsLimit {
+		return sdk.ErrUnknownRequest(
+			fmt.Sprintf("invalid page %d or limit %d", accountsReq.Page, accountsReq.Limit),
+		).QueryResult()
+	}
+
+	ctx, err := app.NewQueryContext(req.Height, req.Prove)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	start := int64(accountsReq.Page-1) * int64(accountsReq.Limit)
+
+	res := AccountsResponse{Accounts: []auth.Account{}}
+	types.IterateAccounts(ctx, app.codec, app.keyAccount, accountsReq.Prefix, func(acc auth.Account) bool {
+		if res.Total >= start && len(res.Accounts) < accountsReq.Limit {
+			res.Accounts = append(res.Accounts, acc)
+		}
+
+		res.Total++
+		return false
+	})
+
+	bz, err := app.codec.MarshalJSON(res)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryTraceBlock re-executes the transactions of a block on the committed
+// state prior to the block, any change being discarded, so that tracing never
+// interferes with the state being committed. As the state prior to the first
+// block is not committed, it cannot be traced. As blocks carry no proposer
+// address, fees are credited to the zero address and the block gas limit is
+// taken as the total gas of the block's transactions.
+func (app *EthermintApp) queryTraceBlock(req abci.RequestQuery) abci.ResponseQuery {
+	var traceReq TraceBlockRequest
+	if err := json.Unmarshal(req.Data, &traceReq); err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid trace block request: %v", err)).QueryResult()
+	}
+
+	if traceReq.Height <= 1 {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid block height %d", traceReq.Height)).QueryResult()
+	}
+
+	txs := make([]*types.Transaction, len(traceReq.Txs))
+
+	var gasLimit uint64
+	for i, txBytes := range traceReq.Txs {
+		txs[i] = new(types.Transaction)
+		if err := rlp.DecodeBytes(txBytes, txs[i]); err != nil {
+			return sdk.ErrTxDecode(fmt.Sprintf("invalid transaction #%d: %v", i, err)).QueryResult()
+		}
+
+		gasLimit += txs[i].Gas()
+	}
+
+	stateCtx, stateDB, err := app.queryStateDB(traceReq.Height - 1)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	// the latest committed state backs the block hash history and opcode
+	// circuit
+	header := abci.Header{Height: traceReq.Height, Time: traceReq.Time, LastBlockHash: traceReq.LastBlockHash}
+	ctx, err := app.NewQueryContext(0, false)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	ctx = ctx.WithBlockHeader(header).WithBlockHeight(traceReq.Height)
+
+	config := core.TraceBlockConfig{
+		ChainConfig:     core.NewChainConfig(app.ethChainID),
+		Header:          header,
+		GasLimit:        gasLimit,
+		GetHash:         app.GetHashFn(ctx),
+		DisabledOpcodes: app.DisabledOpcodes(ctx),
+		Precompiles:     app.Precompiles(stateCtx),
+		Tracer:          traceReq.Tracer,
+		TracerConfig:    traceReq.TracerConfig,
+	}
+
+	results := make([]core.TxTraceResult, 0, len(txs))
+	err = core.TraceBlock(stateDB, config, txs, func(res core.TxTraceResult) error {
+		results = append(results, res)
+		return nil
+	})
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	bz, err := json.Marshal(results)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// prepareCall decodes the message call of an EstimateGasRequest and returns the
+// committed state as of the requested block to execute it on, any change being
+// discarded, along with the block's configuration, binding the precompiled
+// contracts to the state. As blocks carry no proposer address, fees are
+// credited to the zero address.
+func (app *EthermintApp) prepareCall(data []byte) (
+	stateDB *ethstate.StateDB, config core.CallConfig, call core.CallMsg, sdkErr sdk.Error,
+) {
+
+	var callReq EstimateGasRequest
+	if err := json.Unmarshal(data, &callReq); err != nil {
+		sdkErr = sdk.ErrUnknownRequest(fmt.Sprintf("invalid call request: %v", err))
+		return
+	}
+
+	if callReq.Height <= 0 {
+		sdkErr = sdk.ErrUnknownRequest(fmt.Sprintf("invalid block height %d", callReq.Height))
+		return
+	}
+
+	if callReq.Gas > maxCallGas {
+		sdkErr = sdk.ErrUnknownRequest(fmt.Sprintf("gas limit exceeds %d", maxCallGas))
+		return
+	}
+
+	stateCtx, stateDB, err := app.queryStateDB(callReq.Height)
+	if err != nil {
+		sdkErr = sdk.ErrUnknownRequest(err.Error())
+		return
+	}
+
+	// the latest committed state backs the block hash history and opcode
+	// circuit
+	header := abci.Header{Height: callReq.Height, Time: callReq.Time, LastBlockHash: callReq.LastBlockHash}
+	ctx, err := app.NewQueryContext(0, false)
+	if err != nil {
+		sdkErr = sdk.ErrUnknownRequest(err.Error())
+		return
+	}
+
+	ctx = ctx.WithBlockHeader(header).WithBlockHeight(callReq.Height)
+
+	config = core.CallConfig{
+		ChainConfig:     core.NewChainConfig(app.ethChainID),
+		Header:          header,
+		GasLimit:        maxCallGas,
+		GetHash:         app.GetHashFn(ctx),
+		DisabledOpcodes: app.DisabledOpcodes(ctx),
+		Precompiles:     app.Precompiles(stateCtx),
+	}
+
+	call = core.CallMsg{
+		From:     callReq.From,
+		To:       callReq.To,
+		Gas:      callReq.Gas,
+		GasPrice: callReq.GasPrice,
+		Value:    callReq.Value,
+		Data:     callReq.Data,
+	}
+
+	return stateDB, config, call, nil
+}
+
+// queryStateDB returns the Ethereum state held in the application's
+// multi-store as of the given committed height (see NewQueryContext), laid
+// out as by executeEthTx, along with the context reading the state. Any change
+// to it is discarded.
+func (app *EthermintApp) queryStateDB(height int64) (sdk.Context, *ethstate.StateDB, error) {
+	ctx, err := app.NewQueryContext(height, false)
+	if err != nil {
+		return sdk.Context{}, nil, err
+	}
+
+	stateDB, err := ethstate.New(
+		ethcmn.Hash{}, state.NewContextDatabase(ctx, app.accountMapper, app.coinKeeper.EVMDenom(), app.keyStorage, app.keyCode),
+	)
+	if err != nil {
+		return sdk.Context{}, nil, err
+	}
+
+	return ctx, stateDB, nil
+}
+
+// queryEstimateGas estimates the gas of a message call on the state as of a
+// committed block.
+func (app *EthermintApp) queryEstimateGas(req abci.RequestQuery) abci.ResponseQuery {
+	stateDB, config, call, sdkErr := app.prepareCall(req.Data)
+	if sdkErr != nil {
+		return sdkErr.QueryResult()
+	}
+
+	gas, err := core.EstimateGas(stateDB, config, call)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	bz, err := json.Marshal(gas)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: bz}
+}
+
+// queryCall executes a message call on the state as of a committed block and
+// serves its return data.
+func (app *EthermintApp) queryCall(req abci.RequestQuery) abci.ResponseQuery {
+	stateDB, config, call, sdkErr := app.prepareCall(req.Data)
+	if sdkErr != nil {
+		return sdkErr.QueryResult()
+	}
+
+	res, err := core.ApplyCall(stateDB, config, call)
+	if err != nil {
+		return sdk.ErrUnknownRequest(err.Error()).QueryResult()
+	}
+
+	if res.Failed {
+		if len(res.ReturnValue) > 0 {
+			return sdk.ErrUnknownRequest(core.RevertMessage(res.ReturnValue)).QueryResult()
+		}
+
+		return sdk.ErrUnknownRequest("execution failed").QueryResult()
+	}
+
+	return abci.ResponseQuery{Code: uint32(sdk.ABCICodeOK), Value: res.ReturnValue}
+}
+
+// earliestQueryableHeight returns the earliest height whose state is retained
+// under a given pruning strategy at a given latest height. The sync waypoints
+// retained by the syncable strategy are not considered as the state in
+// between them is not.
+func earliestQueryableHeight(pruning string, latest int64) int64 {
+	if latest == 0 {
+		return 0
+	}
+
+	switch pruning {
+	case PruningNothing:
+		return 1
+
+	case PruningEverything:
+		return latest
+
+	default:
+		if latest <= syncableRecentVersions {
+			return 1
+		}
+
+		return latest - syncableRecentVersions
+	}
+}
@@ -0,0 +1,697 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/db"
+	"github.com/cosmos/ethermint/state"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/version"
+	"github.com/cosmos/ethermint/x/addrmap"
+	"github.com/cosmos/ethermint/x/denom"
+	"github.com/cosmos/ethermint/x/withdraw"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestQueryAppMetadata(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), EnableStateDiffs(10), SetPruning(PruningNothing))
+
+	require.Equal(t, version.Version, app.Info(abci.RequestInfo{}).Version)
+
+	res := app.Query(abci.RequestQuery{Path: QueryPathVersion})
+	require.True(t, res.IsOK())
+	require.Equal(t, version.Version, string(res.Value))
+
+	res = app.Query(abci.RequestQuery{Path: QueryPathInfo})
+	require.True(t, res.IsOK())
+
+	var info AppInfo
+	require.Nil(t, json.Unmarshal(res.Value, &info))
+	require.Equal(t, appName, info.Name)
+	require.Equal(t, version.Version, info.Version)
+	require.True(t, info.StateDiffsEnabled)
+	require.Contains(t, info.Stores, "acc")
+	require.NotContains(t, info.Stores, state.AccountsKey.Name())
+	require.Equal(t, PruningNothing, info.Pruning)
+	require.Zero(t, info.EventBacklog)
+	require.Equal(t, app.Codespace(), info.Codespace)
+}
+
+func TestQueryCommitInfo(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+	chain.nextBlock()
+	chain.nextBlock()
+
+	for _, height := range []int64{0, 1, 2} {
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathCommitInfo, Height: height})
+		require.True(t, res.IsOK(), res.Log)
+
+		var info CommitInfo
+		require.Nil(t, json.Unmarshal(res.Value, &info))
+
+		expectedHeight := height
+		if height == 0 {
+			expectedHeight = 2
+		}
+
+		require.Equal(t, expectedHeight, res.Height)
+		require.Equal(t, expectedHeight, info.Version)
+		require.Len(t, info.Stores, len(chain.app.allStoreKeys()))
+
+		for i, store := range info.Stores {
+			require.Equal(t, expectedHeight, store.CommitID.Version)
+
+			if i > 0 {
+				require.True(t, info.Stores[i-1].Name < store.Name, "stores not sorted by name")
+			}
+		}
+	}
+
+	res := chain.app.Query(abci.RequestQuery{Path: QueryPathCommitInfo, Height: 3})
+	require.False(t, res.IsOK())
+}
+
+func TestQueryDenomMetadata(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	bridged := denom.Metadata{Denom: "uatom", Name: "Cosmos Hub Atom", Symbol: "ATOM", Decimals: 6}
+	app.denomKeeper.SetMetadata(app.NewContext(false, abci.Header{}), bridged)
+	app.Commit()
+
+	res := app.Query(abci.RequestQuery{Path: QueryPathDenomMetadata})
+	require.True(t, res.IsOK())
+
+	var all []denom.Metadata
+	require.Nil(t, json.Unmarshal(res.Value, &all))
+	require.Equal(t, []denom.Metadata{denom.NativeMetadata(), bridged}, all)
+
+	res = app.Query(abci.RequestQuery{Path: QueryPathDenomMetadata, Data: []byte("uatom")})
+	require.True(t, res.IsOK())
+
+	var metadata denom.Metadata
+	require.Nil(t, json.Unmarshal(res.Value, &metadata))
+	require.Equal(t, bridged, metadata)
+
+	res = app.Query(abci.RequestQuery{Path: QueryPathDenomMetadata, Data: []byte("unknown")})
+	require.False(t, res.IsOK())
+
+	// the precompile serves the same registry
+	require.Contains(t, app.Precompiles(app.NewContext(true, abci.Header{})), denom.PrecompileAddress)
+}
+
+func TestEarliestQueryableHeight(t *testing.T) {
+	testCases := []struct {
+		pruning        string
+		latest         int64
+		expectedHeight int64
+	}{
+		{PruningNothing, 0, 0},
+		{PruningNothing, 1000, 1},
+		{PruningEverything, 1000, 1000},
+		{PruningSyncable, 50, 1},
+		{PruningSyncable, 100, 1},
+		{PruningSyncable, 1000, 900},
+	}
+
+	for i, tc := range testCases {
+		height := earliestQueryableHeight(tc.pruning, tc.latest)
+		require.Equal(t, tc.expectedHeight, height, fmt.Sprintf("unexpected height: test case #%d", i))
+	}
+}
+
+func TestSetPruning(t *testing.T) {
+	require.Panics(t, func() { NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), SetPruning("invalid")) })
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	require.Equal(t, PruningSyncable, app.pruning)
+	require.Panics(t, func() { SetPruning(PruningNothing)(app) })
+}
+
+func TestQueryStore(t *testing.T) {
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	code := []byte{0x60, 0x00, 0x80, 0xfd}
+	slot := ethcmn.BigToHash(big.NewInt(1))
+
+	chain := newAllocChain(t, GenesisAccount{
+		Address: addr,
+		Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100)),
+		Code:    code,
+		Storage: map[string]string{slot.Hex(): ethcmn.BigToHash(big.NewInt(42)).Hex()},
+	})
+
+	accKey := auth.AddressStoreKey(sdk.AccAddress(addr.Bytes()))
+	storageKey := db.StorageKey(addr, slot)
+	codeKey := ethcrypto.Keccak256(code)
+
+	testCases := []struct {
+		path        string
+		data        []byte
+		expectOK    bool
+		expectProof bool
+	}{
+		{"/store/acc/key", accKey, true, false},
+		{"/store/acc/key?prove=true", accKey, true, true},
+		{"/store/storage/key", storageKey, true, false},
+		{"/store/storage/key?prove=true", storageKey, true, true},
+		{"/store/code/key", codeKey, true, false},
+		{"/store/code/key?prove=true", codeKey, true, true},
+		{"/store/account/key", addr.Bytes(), false, false},
+		{"/store/unknown/key", addr.Bytes(), false, false},
+		{"/store/acc/key?prove=%zz", accKey, false, false},
+	}
+
+	for i, tc := range testCases {
+		res := chain.app.Query(abci.RequestQuery{Path: tc.path, Data: tc.data})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+		require.NotEmpty(t, res.Value, fmt.Sprintf("unexpected value: test case #%d", i))
+		require.Equal(t, tc.expectProof, len(res.Proof) != 0, fmt.Sprintf("unexpected proof: test case #%d", i))
+	}
+
+	require.Equal(t, code, chain.app.Query(abci.RequestQuery{Path: "/store/code/key", Data: codeKey}).Value)
+}
+
+// newAllocChain returns a testChain whose first block is committed, allocating
+// the given accounts at genesis.
+func newAllocChain(t *testing.T, alloc ...GenesisAccount) *testChain {
+	chain := newTestChainWithGenesis(t, "ethermint", func(g *GenesisState) { g.Alloc = alloc })
+	chain.nextBlock()
+
+	return chain
+}
+
+func TestQueryNonce(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+
+	ctx := app.NewContext(false, abci.Header{})
+	acc := app.accountMapper.NewAccountWithAddress(ctx, sdk.AccAddress(addr.Bytes()))
+	require.Nil(t, acc.SetSequence(3))
+	app.accountMapper.SetAccount(ctx, acc)
+	app.Commit()
+
+	// nonces consumed by pending transactions are not reflected
+	checkCtx := app.NewContext(true, abci.Header{})
+	acc = app.accountMapper.GetAccount(checkCtx, sdk.AccAddress(addr.Bytes()))
+	require.Nil(t, acc.SetSequence(4))
+	app.accountMapper.SetAccount(checkCtx, acc)
+
+	testCases := []struct {
+		data          []byte
+		expectOK      bool
+		expectedNonce int64
+	}{
+		{addr.Bytes(), true, 3},
+		{[]byte(addr.Hex()), true, 3},
+		{[]byte(types.Bech32Address(addr)), true, 3},
+		{ethcmn.Address{}.Bytes(), true, 0},
+		{[]byte{0x01}, false, 0},
+		{[]byte("0x756f45e3fa69347a9a973a725e3c98bc4db0b5a"), false, 0},
+	}
+
+	for i, tc := range testCases {
+		res := app.Query(abci.RequestQuery{Path: QueryPathNonce, Data: tc.data})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var nonce int64
+		require.Nil(t, json.Unmarshal(res.Value, &nonce))
+		require.Equal(t, tc.expectedNonce, nonce, fmt.Sprintf("unexpected nonce: test case #%d", i))
+	}
+}
+
+func TestQueryNonces(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+
+	ctx := app.NewContext(false, abci.Header{})
+	acc := app.accountMapper.NewAccountWithAddress(ctx, sdk.AccAddress(addr.Bytes()))
+	require.Nil(t, acc.SetSequence(3))
+	app.accountMapper.SetAccount(ctx, acc)
+	app.Commit()
+
+	// nonces consumed by pending transactions are not reflected
+	checkCtx := app.NewContext(true, abci.Header{})
+	acc = app.accountMapper.GetAccount(checkCtx, sdk.AccAddress(addr.Bytes()))
+	require.Nil(t, acc.SetSequence(4))
+	app.accountMapper.SetAccount(checkCtx, acc)
+
+	tooMany := make([]string, maxNoncesAddresses+1)
+	for i := range tooMany {
+		tooMany[i] = addr.Hex()
+	}
+
+	testCases := []struct {
+		addrs          []string
+		expectOK       bool
+		expectedNonces []int64
+	}{
+		{[]string{}, true, []int64{}},
+		{[]string{addr.Hex()}, true, []int64{3}},
+		{[]string{ethcmn.Address{}.Hex(), types.Bech32Address(addr), addr.Hex()}, true, []int64{0, 3, 3}},
+		{tooMany[:maxNoncesAddresses], true, nil},
+		{tooMany, false, nil},
+		{[]string{addr.Hex(), "0x756f45e3fa69347a9a973a725e3c98bc4db0b5a"}, false, nil},
+	}
+
+	for i, tc := range testCases {
+		data, err := json.Marshal(tc.addrs)
+		require.Nil(t, err)
+
+		res := app.Query(abci.RequestQuery{Path: QueryPathNonces, Data: data})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var nonces []int64
+		require.Nil(t, json.Unmarshal(res.Value, &nonces))
+		require.Len(t, nonces, len(tc.addrs), fmt.Sprintf("unexpected nonces: test case #%d", i))
+
+		if tc.expectedNonces != nil {
+			require.Equal(t, tc.expectedNonces, nonces, fmt.Sprintf("unexpected nonces: test case #%d", i))
+		}
+	}
+
+	res := app.Query(abci.RequestQuery{Path: QueryPathNonces, Data: []byte(addr.Hex())})
+	require.False(t, res.IsOK())
+}
+
+func TestQueryAccount(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	missing := ethcmn.HexToAddress("0x0100000000000000000000000000000000000000")
+
+	ctx := app.NewContext(false, abci.Header{})
+	app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, sdk.AccAddress(addr.Bytes())))
+	app.Commit()
+
+	testCases := []struct {
+		data          []byte
+		expectOK      bool
+		expectedAddr  ethcmn.Address
+		expectAccount bool
+	}{
+		{addr.Bytes(), true, addr, true},
+		{[]byte(addr.Hex()), true, addr, true},
+		{[]byte(strings.ToLower(addr.Hex())), true, addr, true},
+		{[]byte(types.Bech32Address(addr)), true, addr, true},
+		{[]byte(types.Bech32Address(missing)), true, missing, false},
+		{[]byte("0x756f45E3FA69347A9A973A725E3C98bC4db0b5a0"), false, ethcmn.Address{}, false},
+		{[]byte("cosmosaccaddr1invalid"), false, ethcmn.Address{}, false},
+		{nil, false, ethcmn.Address{}, false},
+	}
+
+	for i, tc := range testCases {
+		res := app.Query(abci.RequestQuery{Path: QueryPathAccount, Data: tc.data})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var accRes AccountResponse
+		require.Nil(t, app.codec.UnmarshalJSON(res.Value, &accRes))
+
+		// both forms of the address are served whichever was queried
+		require.Equal(t, tc.expectedAddr.Hex(), accRes.Address, fmt.Sprintf("unexpected address: test case #%d", i))
+		require.Equal(t, types.Bech32Address(tc.expectedAddr), accRes.Bech32Address, fmt.Sprintf("unexpected address: test case #%d", i))
+
+		if !tc.expectAccount {
+			require.Nil(t, accRes.Account, fmt.Sprintf("unexpected account: test case #%d", i))
+			continue
+		}
+
+		require.NotNil(t, accRes.Account, fmt.Sprintf("expected account: test case #%d", i))
+		require.Equal(t, sdk.AccAddress(tc.expectedAddr.Bytes()), accRes.Account.GetAddress(), fmt.Sprintf("unexpected account: test case #%d", i))
+	}
+}
+
+func TestQueryAccounts(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	addrs := []ethcmn.Address{
+		ethcmn.HexToAddress("0x0100000000000000000000000000000000000000"),
+		ethcmn.HexToAddress("0x0102000000000000000000000000000000000000"),
+		ethcmn.HexToAddress("0x0200000000000000000000000000000000000000"),
+	}
+
+	ctx := app.NewContext(false, abci.Header{})
+	for _, addr := range addrs {
+		app.accountMapper.SetAccount(ctx, app.accountMapper.NewAccountWithAddress(ctx, sdk.AccAddress(addr.Bytes())))
+	}
+
+	app.Commit()
+
+	countCases := []struct {
+		prefix   []byte
+		expected int64
+	}{
+		{nil, 3},
+		{[]byte{0x01}, 2},
+		{[]byte{0x01, 0x02}, 1},
+		{[]byte{0x03}, 0},
+	}
+
+	for i, tc := range countCases {
+		res := app.Query(abci.RequestQuery{Path: QueryPathAccountCount, Data: tc.prefix})
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var count int64
+		require.Nil(t, json.Unmarshal(res.Value, &count))
+		require.Equal(t, tc.expected, count, fmt.Sprintf("unexpected count: test case #%d", i))
+	}
+
+	testCases := []struct {
+		req           string
+		expectOK      bool
+		expectedTotal int64
+		expected      []ethcmn.Address
+	}{
+		{`{"page":1}`, true, 3, addrs},
+		{`{"page":1,"limit":2}`, true, 3, addrs[:2]},
+		{`{"page":2,"limit":2}`, true, 3, addrs[2:]},
+		{`{"page":3,"limit":2}`, true, 3, nil},
+		{`{"page":1,"prefix":"AQI="}`, true, 1, addrs[1:2]},
+		{`{"page":0}`, false, 0, nil},
+		{`{"page":1,"limit":1001}`, false, 0, nil},
+		{`{"page":1,"limit":-1}`, false, 0, nil},
+		{`{`, false, 0, nil},
+	}
+
+	for i, tc := range testCases {
+		res := app.Query(abci.RequestQuery{Path: QueryPathAccounts, Data: []byte(tc.req)})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var accountsRes AccountsResponse
+		require.Nil(t, app.codec.UnmarshalJSON(res.Value, &accountsRes))
+		require.Equal(t, tc.expectedTotal, accountsRes.Total, fmt.Sprintf("unexpected total: test case #%d", i))
+
+		var listed []ethcmn.Address
+		for _, acc := range accountsRes.Accounts {
+			require.IsType(t, &types.Account{}, acc)
+			listed = append(listed, ethcmn.BytesToAddress(acc.GetAddress()))
+		}
+
+		require.Equal(t, tc.expected, listed, fmt.Sprintf("unexpected accounts: test case #%d", i))
+	}
+}
+
+func TestQueryTraceBlock(t *testing.T) {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	// the block traced is the second one, executed on the state committed by
+	// the first
+	chain := newAllocChain(t, GenesisAccount{Address: sender, Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100000))})
+
+	tx := types.NewTransaction(0, ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(DefaultEthChainID), priv)
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	testCases := []struct {
+		req      TraceBlockRequest
+		expectOK bool
+	}{
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{txBytes}}, true},
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{txBytes}, Tracer: core.TracerStateDiff}, true},
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{txBytes}, Tracer: core.TracerCall, TracerConfig: json.RawMessage(`{"onlyTopCall":true}`)}, true},
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{txBytes}, Tracer: core.TracerCall, TracerConfig: json.RawMessage(`[]`)}, false},
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{txBytes}, Tracer: "unknown"}, false},
+		{TraceBlockRequest{Height: 2, Txs: [][]byte{[]byte("invalid")}}, false},
+		{TraceBlockRequest{Height: 3, Txs: [][]byte{txBytes}}, false},
+		{TraceBlockRequest{Height: 1, Txs: [][]byte{txBytes}}, false},
+		{TraceBlockRequest{Height: 0}, false},
+	}
+
+	for i, tc := range testCases {
+		bz, err := json.Marshal(tc.req)
+		require.Nil(t, err)
+
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathTraceBlock, Data: bz})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d: %s", i, res.Log))
+
+		var results []core.TxTraceResult
+		require.Nil(t, json.Unmarshal(res.Value, &results))
+		require.Len(t, results, 1)
+		require.Equal(t, tx.Hash(), results[0].TxHash)
+		require.Empty(t, results[0].Error)
+	}
+
+	// tracing leaves the committed state untouched
+	require.Equal(t, int64(1), chain.app.LastBlockHeight())
+	require.Equal(t, int64(100000), chain.account(sdk.AccAddress(sender.Bytes())).GetCoins().AmountOf(types.DenomDefault).Int64())
+}
+
+func TestQueryEstimateGas(t *testing.T) {
+	sender := ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	contract := ethcmn.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	// the contract reverts with an empty payload
+	chain := newAllocChain(t,
+		GenesisAccount{Address: sender, Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100000))},
+		GenesisAccount{Address: contract, Code: []byte{0x60, 0x00, 0x80, 0xfd}},
+	)
+
+	recipient := ethcmn.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	testCases := []struct {
+		req         EstimateGasRequest
+		expectedGas uint64
+		expectOK    bool
+	}{
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient}, 21000, true},
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient, Data: []byte{0x01}}, 21068, true},
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient, Value: big.NewInt(200000)}, 0, false},
+		{EstimateGasRequest{Height: 1, From: sender, To: &contract}, 0, false},
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient, Gas: maxCallGas + 1}, 0, false},
+		{EstimateGasRequest{Height: 2, From: sender, To: &recipient}, 0, false},
+		{EstimateGasRequest{Height: 0, From: sender, To: &recipient}, 0, false},
+	}
+
+	for i, tc := range testCases {
+		bz, err := json.Marshal(tc.req)
+		require.Nil(t, err)
+
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathEstimateGas, Data: bz})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d: %s", i, res.Log))
+
+		var gas uint64
+		require.Nil(t, json.Unmarshal(res.Value, &gas))
+		require.Equal(t, tc.expectedGas, gas, fmt.Sprintf("unexpected gas: test case #%d", i))
+	}
+
+	// estimating leaves the committed state untouched
+	require.Equal(t, int64(1), chain.app.LastBlockHeight())
+	require.Equal(t, int64(100000), chain.account(sdk.AccAddress(sender.Bytes())).GetCoins().AmountOf(types.DenomDefault).Int64())
+}
+
+func TestQueryCall(t *testing.T) {
+	sender := ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	reverter := ethcmn.HexToAddress("0x2000000000000000000000000000000000000002")
+	returner := ethcmn.HexToAddress("0x3000000000000000000000000000000000000003")
+	recipient := ethcmn.HexToAddress("0x4000000000000000000000000000000000000004")
+
+	// the first contract reverts with an empty payload and the second one
+	// returns 42 as a 32 byte word
+	chain := newAllocChain(t,
+		GenesisAccount{Address: sender, Balance: (*ethmath.HexOrDecimal256)(big.NewInt(100000))},
+		GenesisAccount{Address: reverter, Code: []byte{0x60, 0x00, 0x80, 0xfd}},
+		GenesisAccount{Address: returner, Code: []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}},
+	)
+
+	testCases := []struct {
+		req      EstimateGasRequest
+		expected []byte
+		expectOK bool
+	}{
+		{EstimateGasRequest{Height: 1, From: sender, To: &returner}, ethcmn.LeftPadBytes([]byte{0x2a}, 32), true},
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient}, nil, true},
+		{EstimateGasRequest{Height: 1, From: sender, To: &reverter}, nil, false},
+		{EstimateGasRequest{Height: 1, From: sender, To: &returner, Gas: 21000}, nil, false},
+		{EstimateGasRequest{Height: 1, From: sender, To: &recipient, Value: big.NewInt(200000)}, nil, false},
+		{EstimateGasRequest{Height: 2, From: sender, To: &returner}, nil, false},
+	}
+
+	for i, tc := range testCases {
+		bz, err := json.Marshal(tc.req)
+		require.Nil(t, err)
+
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathCall, Data: bz})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d: %s", i, res.Log))
+		require.Equal(t, tc.expected, res.Value, fmt.Sprintf("unexpected return data: test case #%d", i))
+	}
+
+	// calls leave the committed state untouched
+	require.Equal(t, int64(1), chain.app.LastBlockHeight())
+	require.Equal(t, int64(100000), chain.account(sdk.AccAddress(sender.Bytes())).GetCoins().AmountOf(types.DenomDefault).Int64())
+}
+
+func TestQueryLinkedAddress(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), EnableLegacyAddresses())
+	app.InitChain(abci.RequestInitChain{})
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	pubKey := privKey.PubKey().(crypto.PubKeySecp256k1)
+	addr := ethcmn.BytesToAddress(addrmap.EthereumAddress(pubKey))
+	legacy := addrmap.LegacyAddress(pubKey)
+
+	res := app.Query(abci.RequestQuery{Path: QueryPathLinkedAddress, Data: addr.Bytes()})
+	require.False(t, res.IsOK())
+
+	_, _, sdkErr := app.addrMapKeeper.Link(app.NewContext(false, abci.Header{}), pubKey)
+	require.Nil(t, sdkErr)
+	app.Commit()
+
+	expected := LinkedAddressResponse{Address: types.ChecksumHex(addr), LegacyAddress: legacy.String()}
+
+	testCases := []struct {
+		data      []byte
+		expectErr bool
+	}{
+		{addr.Bytes(), false},
+		{[]byte(addr.Hex()), false},
+		{legacy.Bytes(), false},
+		{[]byte(legacy.String()), false},
+		{ethcmn.BytesToAddress([]byte("unknown")).Bytes(), true},
+		{[]byte("invalid"), true},
+	}
+
+	for i, tc := range testCases {
+		res := app.Query(abci.RequestQuery{Path: QueryPathLinkedAddress, Data: tc.data})
+
+		if tc.expectErr {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var linked LinkedAddressResponse
+		require.Nil(t, json.Unmarshal(res.Value, &linked))
+		require.Equal(t, expected, linked, fmt.Sprintf("unexpected response: test case #%d", i))
+	}
+}
+
+func TestQueryWithdrawAddress(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	operator := sdk.AccAddress(privKey.PubKey().Address())
+	treasury := ethcmn.BytesToAddress([]byte("treasury"))
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	chain.app.accountMapper.SetAccount(ctx, chain.app.accountMapper.NewAccountWithAddress(ctx, operator))
+	chain.app.stakeKeeper.SetValidator(ctx, stake.NewValidator(operator, privKey.PubKey(), stake.Description{}))
+	chain.nextBlock()
+
+	query := func(addr sdk.AccAddress) (WithdrawAddressResponse, abci.ResponseQuery) {
+		res := chain.app.Query(abci.RequestQuery{Path: QueryPathWithdrawAddress, Data: addr.Bytes()})
+
+		var withdrawAddr WithdrawAddressResponse
+		if res.IsOK() {
+			require.Nil(t, json.Unmarshal(res.Value, &withdrawAddr))
+		}
+
+		return withdrawAddr, res
+	}
+
+	// the operator address is the default withdrawal address
+	withdrawAddr, res := query(operator)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, types.ChecksumHex(ethcmn.BytesToAddress(operator)), withdrawAddr.WithdrawAddress)
+
+	tx, err := types.NewEmbeddedTxBuilder(
+		chain.app.codec, "ethermint", withdraw.NewMsgSetWithdrawAddress(operator, treasury.Bytes()),
+	).Sign(privKey, 0, 0).Build(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+	require.Nil(t, err)
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	results := chain.nextBlock(txBytes)
+	require.True(t, results[0].IsOK(), results[0].Log)
+
+	withdrawAddr, res = query(operator)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, WithdrawAddressResponse{
+		Validator:       types.ChecksumHex(ethcmn.BytesToAddress(operator)),
+		WithdrawAddress: types.ChecksumHex(treasury),
+	}, withdrawAddr)
+
+	// the withdrawal address of an address operating no validator is unknown
+	_, res = query(treasury.Bytes())
+	require.Equal(t, uint32(sdk.ToABCICode(chain.app.withdrawKeeper.Codespace(), withdraw.CodeNotValidator)), res.Code)
+}
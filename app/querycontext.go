@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// queryStoreCache retains the multi-store last loaded to serve queries, so
+// that queries against the latest height, by far the most common, do not
+// reload every store.
+type queryStoreCache struct {
+	mtx sync.Mutex
+
+	height int64
+	ms     store.CommitMultiStore
+}
+
+// NewQueryContext returns a context reading the application state as of the
+// given height, or as of the latest committed height if zero, for serving
+// queries. Unlike the check state, the state never reflects the transactions
+// pending in the mempool. The context carries an infinite gas meter and the
+// header of the block at the height, as far as the application records it,
+// i.e. its height, time and last block hash. Any write through the context is
+// discarded. A proof may only be requested for a committed height, as there is
+// no application hash to prove against otherwise. An error is returned if the
+// state at the height is not committed or no longer retained.
+func (app *EthermintApp) NewQueryContext(height int64, prove bool) (sdk.Context, error) {
+	latest := app.LastBlockHeight()
+
+	if height < 0 || height > latest {
+		return sdk.Context{}, fmt.Errorf("invalid height %d, the latest committed height being %d", height, latest)
+	}
+
+	if height == 0 {
+		height = latest
+	}
+
+	if prove && height == 0 {
+		return sdk.Context{}, fmt.Errorf("cannot prove the state prior to the first commit")
+	}
+
+	ms, err := app.queryMultiStore(height, latest)
+	if err != nil {
+		return sdk.Context{}, err
+	}
+
+	ctx := sdk.NewContext(ms.CacheMultiStore(), abci.Header{Height: height}, true, app.Logger)
+
+	header := abci.Header{Height: height, Time: app.blockHashKeeper.GetBlockTime(ctx)}
+	if hash, ok := app.blockHashKeeper.GetBlockHash(ctx, height-1); ok {
+		header.LastBlockHash = hash
+	}
+
+	return ctx.WithBlockHeader(header), nil
+}
+
+// queryMultiStore returns a multi-store loaded at the given height from the
+// application's database, independently of the multi-store the application
+// commits to. Only the multi-store of the latest height is retained, as older
+// heights may be pruned at any commit.
+func (app *EthermintApp) queryMultiStore(height, latest int64) (store.CommitMultiStore, error) {
+	app.queryStores.mtx.Lock()
+	defer app.queryStores.mtx.Unlock()
+
+	if app.queryStores.ms != nil && app.queryStores.height == height && height == latest {
+		return app.queryStores.ms, nil
+	}
+
+	ms := store.NewCommitMultiStore(app.db)
+	for _, key := range app.allStoreKeys() {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	}
+
+	if err := ms.LoadVersion(height); err != nil {
+		return nil, fmt.Errorf("state at height %d is not available: %v", height, err)
+	}
+
+	if height == latest {
+		app.queryStores.height, app.queryStores.ms = height, ms
+	}
+
+	return ms, nil
+}
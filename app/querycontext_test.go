@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestNewQueryContext(t *testing.T) {
+	chain := newTestChain(t, "ethermint")
+
+	for i := 1; i <= 3; i++ {
+		if i > 1 {
+			chain.header.LastBlockHash = []byte(fmt.Sprintf("block %d", i-1))
+		}
+
+		chain.nextBlock()
+	}
+
+	testCases := []struct {
+		height            int64
+		prove             bool
+		expectedHeight    int64
+		expectedLastBlock []byte
+		expectErr         bool
+	}{
+		{0, false, 3, []byte("block 2"), false},
+		{0, true, 3, []byte("block 2"), false},
+		{1, false, 1, nil, false},
+		{1, true, 1, nil, false},
+		{2, false, 2, []byte("block 1"), false},
+		{3, false, 3, []byte("block 2"), false},
+		{4, false, 0, nil, true},
+		{-1, false, 0, nil, true},
+	}
+
+	for i, tc := range testCases {
+		ctx, err := chain.app.NewQueryContext(tc.height, tc.prove)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expectedHeight, ctx.BlockHeight(), fmt.Sprintf("unexpected height: test case #%d", i))
+		require.Equal(t, tc.expectedHeight, ctx.BlockHeader().Height, fmt.Sprintf("unexpected header: test case #%d", i))
+		require.Equal(t, tc.expectedHeight, ctx.BlockHeader().Time, fmt.Sprintf("unexpected time: test case #%d", i))
+		require.Equal(
+			t, tc.expectedLastBlock, ctx.BlockHeader().LastBlockHash, fmt.Sprintf("unexpected last block: test case #%d", i),
+		)
+		require.IsType(t, sdk.NewInfiniteGasMeter(), ctx.GasMeter(), fmt.Sprintf("unexpected gas meter: test case #%d", i))
+	}
+}
+
+func TestNewQueryContextState(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	app.InitChain(abci.RequestInitChain{})
+
+	// nothing is committed prior to the first commit, so nothing can be
+	// proven
+	_, err := app.NewQueryContext(0, true)
+	require.NotNil(t, err)
+
+	ctx, err := app.NewQueryContext(0, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), ctx.BlockHeight())
+
+	addr := sdk.AccAddress(ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0").Bytes())
+
+	deliverCtx := app.NewContext(false, abci.Header{})
+	acc := app.accountMapper.NewAccountWithAddress(deliverCtx, addr)
+	require.Nil(t, acc.SetSequence(3))
+	app.accountMapper.SetAccount(deliverCtx, acc)
+	app.Commit()
+
+	// the state consumed by pending transactions is not reflected
+	checkCtx := app.NewContext(true, abci.Header{})
+	acc = app.accountMapper.GetAccount(checkCtx, addr)
+	require.Nil(t, acc.SetSequence(4))
+	app.accountMapper.SetAccount(checkCtx, acc)
+
+	ctx, err = app.NewQueryContext(0, true)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), app.accountMapper.GetAccount(ctx, addr).GetSequence())
+
+	// writes through a query context are discarded
+	acc = app.accountMapper.GetAccount(ctx, addr)
+	require.Nil(t, acc.SetSequence(5))
+	app.accountMapper.SetAccount(ctx, acc)
+
+	ctx, err = app.NewQueryContext(1, false)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), app.accountMapper.GetAccount(ctx, addr).GetSequence())
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// panicReports defines where reports of unexpected panics raised while
+// delivering messages are persisted and whether the node halts upon one.
+type panicReports struct {
+	dir  string
+	halt bool
+
+	// exit halts the node, defaulting to cmn.Exit
+	exit func(reason string)
+}
+
+// SetPanicReports returns an option that persists a report, including the
+// stack trace, of every unexpected panic raised while delivering a message as
+// a file in the given directory. If halt is true, the node exits once the
+// report is persisted rather than continue with a state that may diverge from
+// other validators'. It panics if the application is already sealed.
+func SetPanicReports(dir string, halt bool) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("SetPanicReports() on sealed EthermintApp")
+		}
+
+		app.panicReports = &panicReports{dir: dir, halt: halt, exit: cmn.Exit}
+	}
+}
+
+// recoverMsgPanics wraps a given handler so that an unexpected panic raised
+// while handling a message fails the message with ErrConsensusFailure, which
+// discards the message's state changes, instead of an opaque internal error.
+// Upon delivery, the panic and its stack trace are logged and, if enabled,
+// persisted as a report. Running out of gas is expected and left to the
+// BaseApp.
+func (app *EthermintApp) recoverMsgPanics(handler sdk.Handler) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) (res sdk.Result) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			if _, ok := r.(sdk.ErrorOutOfGas); ok {
+				panic(r)
+			}
+
+			reason := fmt.Sprintf("panic handling %s message: %v", msg.Type(), r)
+			res = types.ErrConsensusFailure(app.codespace, reason).Result()
+
+			if ctx.IsCheckTx() {
+				return
+			}
+
+			report := fmt.Sprintf(
+				"consensus failure candidate\nchain ID: %s\nheight: %d\n%s\n\n%s",
+				ctx.ChainID(), ctx.BlockHeight(), reason, debug.Stack(),
+			)
+			ctx.Logger().Error(report)
+
+			app.reportPanic(ctx, reason, report)
+		}()
+
+		return handler(ctx, msg)
+	}
+}
+
+// reportPanic persists a report of an unexpected panic, if enabled, and halts
+// the node if configured to.
+func (app *EthermintApp) reportPanic(ctx sdk.Context, reason, report string) {
+	if app.panicReports == nil {
+		return
+	}
+
+	name := fmt.Sprintf("consensus_failure_%d_%d.log", ctx.BlockHeight(), time.Now().UnixNano())
+	path := filepath.Join(app.panicReports.dir, name)
+
+	if err := os.MkdirAll(app.panicReports.dir, 0755); err != nil {
+		ctx.Logger().Error("failed to persist panic report", "err", err)
+	} else if err := ioutil.WriteFile(path, []byte(report), 0644); err != nil {
+		ctx.Logger().Error("failed to persist panic report", "err", err)
+	}
+
+	if app.panicReports.halt {
+		app.panicReports.exit(fmt.Sprintf("halting on consensus failure candidate at height %d: %s", ctx.BlockHeight(), reason))
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/authz"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func panickingHandler(v interface{}) sdk.Handler {
+	return func(_ sdk.Context, _ sdk.Msg) sdk.Result {
+		panic(v)
+	}
+}
+
+func TestRecoverMsgPanics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethermint_panic_reports")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var halted string
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), SetPanicReports(dir, false))
+	app.panicReports.exit = func(reason string) { halted = reason }
+	app.InitChain(abci.RequestInitChain{})
+
+	msg := authz.MsgRevoke{}
+	header := abci.Header{ChainID: "ethermint", Height: 7}
+
+	// panics are not reported upon CheckTx
+	res := app.recoverMsgPanics(panickingHandler("bad state"))(app.NewContext(true, header), msg)
+	require.Equal(t, types.ErrConsensusFailure(app.codespace, "").ABCICode(), res.Code)
+	require.Contains(t, res.Log, "bad state")
+
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Empty(t, files)
+
+	ctx := app.NewContext(false, header)
+
+	res = app.recoverMsgPanics(panickingHandler("bad state"))(ctx, msg)
+	require.Equal(t, types.ErrConsensusFailure(app.codespace, "").ABCICode(), res.Code)
+	require.Empty(t, halted)
+
+	// the report includes the stack trace of the panic
+	files, err = ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+
+	report, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.Nil(t, err)
+	require.Contains(t, string(report), "height: 7")
+	require.Contains(t, string(report), "bad state")
+	require.Contains(t, string(report), "panickingHandler")
+
+	// running out of gas is left to the BaseApp
+	require.Panics(t, func() {
+		app.recoverMsgPanics(panickingHandler(sdk.ErrorOutOfGas{Descriptor: "test"}))(ctx, msg)
+	})
+
+	// a successful message is unaffected
+	res = app.recoverMsgPanics(func(_ sdk.Context, _ sdk.Msg) sdk.Result { return sdk.Result{} })(ctx, msg)
+	require.True(t, res.IsOK())
+
+	app.panicReports.halt = true
+	app.recoverMsgPanics(panickingHandler("bad state"))(ctx, msg)
+	require.Contains(t, halted, "bad state")
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/cosmos/ethermint/core"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// stateDiffCache retains the state diffs of the transactions included in the
+// last given number of blocks.
+type stateDiffCache struct {
+	mtx sync.RWMutex
+
+	blocks int64
+	diffs  map[ethcmn.Hash]stateDiffEntry
+}
+
+// stateDiffEntry contains a state diff along with the height of the block
+// including its transaction.
+type stateDiffEntry struct {
+	height int64
+	diff   core.StateDiff
+}
+
+func newStateDiffCache(blocks int64) *stateDiffCache {
+	return &stateDiffCache{
+		blocks: blocks,
+		diffs:  make(map[ethcmn.Hash]stateDiffEntry),
+	}
+}
+
+// add records the state diff of a transaction included at a given height.
+func (c *stateDiffCache) add(height int64, diff core.StateDiff) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.diffs[diff.TxHash] = stateDiffEntry{height: height, diff: diff}
+}
+
+// get returns the state diff of the transaction with the given hash and true
+// if it is retained or false otherwise.
+func (c *stateDiffCache) get(txHash ethcmn.Hash) (core.StateDiff, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	entry, ok := c.diffs[txHash]
+	return entry.diff, ok
+}
+
+// prune drops the state diffs of all the transactions included in blocks that
+// are no longer retained as of the given latest height.
+func (c *stateDiffCache) prune(height int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for txHash, entry := range c.diffs {
+		if height-entry.height >= c.blocks {
+			delete(c.diffs, txHash)
+		}
+	}
+}
+
+// EnableStateDiffs returns an option that enables recording the state diffs,
+// i.e. changed storage slots and balance deltas, of all the Ethereum
+// transactions delivered in the last given number of blocks. It is meant as a
+// debugging aid as recording diffs slows down execution. A number of zero
+// blocks disables recording. It panics if the application is already sealed.
+func EnableStateDiffs(blocks int64) func(*EthermintApp) {
+	return func(app *EthermintApp) {
+		if app.sealed {
+			panic("EnableStateDiffs() on sealed EthermintApp")
+		}
+
+		if blocks <= 0 {
+			app.stateDiffs = nil
+			return
+		}
+
+		app.stateDiffs = newStateDiffCache(blocks)
+	}
+}
+
+// StateDiffsEnabled returns true if state diffs are recorded.
+func (app *EthermintApp) StateDiffsEnabled() bool {
+	return app.stateDiffs != nil
+}
+
+// StateDiff returns the recorded state diff of the transaction with the given
+// hash and true if it is retained or false otherwise.
+func (app *EthermintApp) StateDiff(txHash ethcmn.Hash) (core.StateDiff, bool) {
+	if app.stateDiffs == nil {
+		return core.StateDiff{}, false
+	}
+
+	return app.stateDiffs.get(txHash)
+}
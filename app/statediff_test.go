@@ -0,0 +1,138 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestStateDiffCachePrune(t *testing.T) {
+	cache := newStateDiffCache(2)
+
+	for height := int64(1); height <= 3; height++ {
+		cache.add(height, core.StateDiff{TxHash: ethcmn.BigToHash(sdk.NewInt(height).BigInt())})
+	}
+
+	cache.prune(4)
+
+	testCases := []struct {
+		height   int64
+		retained bool
+	}{
+		{1, false},
+		{2, false},
+		{3, true},
+	}
+
+	for i, tc := range testCases {
+		_, ok := cache.get(ethcmn.BigToHash(sdk.NewInt(tc.height).BigInt()))
+		require.Equal(t, tc.retained, ok, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestQueryStateDiff(t *testing.T) {
+	txHash := ethcmn.BytesToHash([]byte("tx"))
+	req := abci.RequestQuery{Path: QueryPathStateDiff, Data: txHash.Bytes()}
+
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	require.False(t, app.Query(req).IsOK())
+
+	app = NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), EnableStateDiffs(10))
+	require.False(t, app.Query(req).IsOK())
+	require.False(t, app.Query(abci.RequestQuery{Path: QueryPathStateDiff, Data: []byte("tx")}).IsOK())
+
+	expected := core.StateDiff{TxHash: txHash, Storage: []core.StorageDiff{}, Balances: []core.BalanceDiff{}}
+	app.stateDiffs.add(1, expected)
+
+	res := app.Query(req)
+	require.True(t, res.IsOK())
+
+	var diff core.StateDiff
+	require.Nil(t, json.Unmarshal(res.Value, &diff))
+	require.Equal(t, expected, diff)
+
+	// queries of other paths are served by the BaseApp
+	require.True(t, app.Query(abci.RequestQuery{Path: "/app/version"}).IsOK())
+}
+
+func TestDeliverTxStateDiff(t *testing.T) {
+	chain := newTestChain(t, "ethermint", EnableStateDiffs(1))
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := sdk.AccAddress(privKey.PubKey().Address())
+	from := ethcmn.BytesToAddress(sender.Bytes())
+	contract := ethcrypto.CreateAddress(from, 0)
+
+	ctx := chain.app.NewContext(false, abci.Header{ChainID: "ethermint"})
+	_, _, sdkErr := chain.app.coinKeeper.AddCoins(ctx, sender, sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000)})
+	require.Nil(t, sdkErr)
+
+	chain.nextBlock()
+
+	create := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), counterCode)
+	create.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+	call := types.NewTransaction(1, contract, big.NewInt(10), 100000, big.NewInt(1), nil)
+	call.Sign(big.NewInt(DefaultEthChainID), privKey.ToECDSA())
+
+	encode := func(tx *types.Transaction) []byte {
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	// transactions only checked are not recorded
+	require.True(t, chain.app.CheckTx(encode(create)).IsOK())
+	_, ok := chain.app.StateDiff(create.Hash())
+	require.False(t, ok)
+
+	results := chain.nextBlock(encode(create), encode(call))
+	for i, res := range results {
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected result: tx #%d: %s", i, res.Log))
+	}
+
+	data, err := types.DecodeResultData(chain.app.codec, results[1].Data)
+	require.Nil(t, err)
+
+	diff, ok := chain.app.StateDiff(call.Hash())
+	require.True(t, ok)
+	require.Equal(t, call.Hash(), diff.TxHash)
+	require.Equal(t, []core.StorageDiff{
+		{Address: contract, Key: ethcmn.Hash{}, Original: ethcmn.Hash{}, Current: ethcmn.BigToHash(big.NewInt(1))},
+	}, diff.Storage)
+
+	deltas := make(map[ethcmn.Address]*big.Int)
+	for _, balance := range diff.Balances {
+		deltas[balance.Address] = balance.Delta
+	}
+
+	fee := int64(data.GasUsed)
+	require.Equal(t, big.NewInt(-fee-10), deltas[from])
+	require.Equal(t, big.NewInt(10), deltas[contract])
+	require.Equal(t, big.NewInt(fee), deltas[ethcmn.BytesToAddress(chain.app.mintKeeper.FeeCollector())])
+
+	_, ok = chain.app.StateDiff(create.Hash())
+	require.True(t, ok)
+
+	// diffs are pruned once their block is no longer retained
+	chain.nextBlock()
+	_, ok = chain.app.StateDiff(call.Hash())
+	require.False(t, ok)
+}
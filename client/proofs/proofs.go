@@ -0,0 +1,194 @@
+// Package proofs provides helpers for clients, e.g. exchanges verifying
+// deposits, to verify the values served by ABCI store queries against the
+// application hash of a Tendermint header rather than trust the node serving
+// them.
+//
+// A value is proven in two steps: an IAVL proof proves it against the root
+// hash of its store and the commit IDs of every store, served under
+// app.QueryPathCommitInfo, prove that root hash against the application hash.
+// The header itself must be trusted, e.g. as verified by a Tendermint light
+// client. As the application hash committing to the state as of a height is
+// included in the header of the following block, values are proven as of the
+// height preceding the header's.
+package proofs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/app"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"golang.org/x/crypto/ripemd160"
+)
+
+var cdc = wire.NewCodec()
+
+// Querier defines the Tendermint RPC call required to query proofs, as
+// implemented by Tendermint's RPC clients.
+type Querier interface {
+	ABCIQueryWithOptions(path string, data cmn.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error)
+}
+
+// storeCore mirrors the part of a store's commit info the SDK's root
+// multistore hashes.
+type storeCore struct {
+	CommitID sdk.CommitID
+}
+
+// Hash implements the merkle.Hasher interface, hashing a store's commit ID
+// as the SDK's root multistore does.
+func (sc storeCore) Hash() []byte {
+	hasher := ripemd160.New()
+	hasher.Write(cdc.MustMarshalBinary(sc))
+
+	return hasher.Sum(nil)
+}
+
+// AppHash returns the application hash committing to the given store commit
+// IDs.
+func AppHash(info app.CommitInfo) []byte {
+	m := make(map[string]merkle.Hasher, len(info.Stores))
+	for _, store := range info.Stores {
+		m[store.Name] = storeCore{store.CommitID}
+	}
+
+	return merkle.SimpleHashFromMap(m)
+}
+
+// VerifyCommitInfo returns an error if the given commit info is not that of
+// the state the given header's application hash commits to.
+func VerifyCommitInfo(header tmtypes.Header, info app.CommitInfo) error {
+	if info.Version != header.Height-1 {
+		return fmt.Errorf("commit info of height %d cannot be verified against header of height %d",
+			info.Version, header.Height)
+	}
+
+	if !bytes.Equal(AppHash(info), header.AppHash) {
+		return fmt.Errorf("commit info does not match application hash %X", []byte(header.AppHash))
+	}
+
+	return nil
+}
+
+// VerifyValue returns an error if the given IAVL proof does not prove the
+// given value of the given key of the named store as of the given verified
+// commit info. A nil value is verified as the key being absent.
+func VerifyValue(info app.CommitInfo, storeName string, key, value, proof []byte) error {
+	var root []byte
+
+	for _, store := range info.Stores {
+		if store.Name == storeName {
+			root = store.CommitID.Hash
+		}
+	}
+
+	if root == nil {
+		return fmt.Errorf("no store %s in commit info", storeName)
+	}
+
+	var rangeProof iavl.RangeProof
+	if err := cdc.UnmarshalBinary(proof, &rangeProof); err != nil {
+		return fmt.Errorf("invalid proof: %v", err)
+	}
+
+	if err := rangeProof.Verify(root); err != nil {
+		return err
+	}
+
+	if value == nil {
+		return rangeProof.VerifyAbsence(key)
+	}
+
+	return rangeProof.VerifyItem(key, value)
+}
+
+// QueryCommitInfo queries the commit info of the state the given header's
+// application hash commits to and verifies it.
+func QueryCommitInfo(q Querier, header tmtypes.Header) (app.CommitInfo, error) {
+	res, err := q.ABCIQueryWithOptions(
+		app.QueryPathCommitInfo, nil, rpcclient.ABCIQueryOptions{Height: header.Height - 1, Trusted: true},
+	)
+	if err != nil {
+		return app.CommitInfo{}, err
+	}
+
+	if !res.Response.IsOK() {
+		return app.CommitInfo{}, fmt.Errorf("failed to query commit info: %s", res.Response.Log)
+	}
+
+	var info app.CommitInfo
+	if err := json.Unmarshal(res.Response.Value, &info); err != nil {
+		return app.CommitInfo{}, fmt.Errorf("invalid commit info: %v", err)
+	}
+
+	if err := VerifyCommitInfo(header, info); err != nil {
+		return app.CommitInfo{}, err
+	}
+
+	return info, nil
+}
+
+// QueryValue queries the value of the given key of the named store as of the
+// state the given header's application hash commits to and verifies it. A nil
+// value is returned if the key is proven absent.
+func QueryValue(q Querier, header tmtypes.Header, storeName string, key []byte) ([]byte, error) {
+	info, err := QueryCommitInfo(q, header)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := q.ABCIQueryWithOptions(
+		fmt.Sprintf("/store/%s/key", storeName), key, rpcclient.ABCIQueryOptions{Height: info.Version},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.Response.IsOK() || len(res.Response.Proof) == 0 {
+		return nil, fmt.Errorf("failed to query proof of %s key %X: %s", storeName, key, res.Response.Log)
+	}
+
+	if res.Response.Height != info.Version {
+		return nil, fmt.Errorf("proof of height %d served for height %d", res.Response.Height, info.Version)
+	}
+
+	value := res.Response.Value
+	if len(value) == 0 {
+		value = nil
+	}
+
+	if err := VerifyValue(info, storeName, key, value, res.Response.Proof); err != nil {
+		return nil, fmt.Errorf("invalid proof of %s key %X: %v", storeName, key, err)
+	}
+
+	return value, nil
+}
+
+// QueryAccount queries the account of the given Ethereum address as of the
+// state the given header's application hash commits to, decoded with the
+// given codec, e.g. as returned by app.MakeCodec, and verifies it. A nil
+// account is returned if the account is proven not to exist.
+func QueryAccount(q Querier, codec *wire.Codec, header tmtypes.Header, addr ethcmn.Address) (auth.Account, error) {
+	value, err := QueryValue(q, header, app.AccountStoreName, auth.AddressStoreKey(sdk.AccAddress(addr.Bytes())))
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var acc auth.Account
+	if err := codec.UnmarshalBinaryBare(value, &acc); err != nil {
+		return nil, fmt.Errorf("invalid account: %v", err)
+	}
+
+	return acc, nil
+}
@@ -0,0 +1,127 @@
+package proofs
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// testQuerier implements a Querier querying an application directly, letting
+// a test tamper with the responses as an untrusted node would.
+type testQuerier struct {
+	app    *app.EthermintApp
+	tamper func(path string, res *abci.ResponseQuery)
+}
+
+func (q testQuerier) ABCIQueryWithOptions(
+	path string, data cmn.HexBytes, opts rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+
+	res := q.app.Query(abci.RequestQuery{Path: path, Data: data, Height: opts.Height, Prove: !opts.Trusted})
+	if q.tamper != nil {
+		q.tamper(path, &res)
+	}
+
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
+
+// newTestApp returns an application having committed a block creating the
+// account of the given key, along with the header of the following block.
+func newTestApp(t *testing.T, privKey crypto.PrivKeySecp256k1) (*app.EthermintApp, tmtypes.Header) {
+	ethermint := app.NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB())
+	ethermint.InitChain(abci.RequestInitChain{ChainId: "ethermint"})
+
+	tx := types.NewTransaction(0, ethcmn.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	tx.Sign(big.NewInt(app.DefaultEthChainID), privKey.ToECDSA())
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	// the transaction fails as there is no EVM handler, but the ante handler
+	// still creates the sender's account
+	ethermint.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: "ethermint", Height: 1}})
+	ethermint.DeliverTx(txBytes)
+	appHash := ethermint.Commit().Data
+
+	return ethermint, tmtypes.Header{ChainID: "ethermint", Height: 2, AppHash: appHash}
+}
+
+func TestQueryAccount(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	addr := ethcmn.BytesToAddress(privKey.PubKey().Address())
+	ethermint, header := newTestApp(t, privKey)
+	codec := app.MakeCodec()
+
+	acc, err := QueryAccount(testQuerier{app: ethermint}, codec, header, addr)
+	require.Nil(t, err)
+	require.NotNil(t, acc)
+	require.Equal(t, addr.Bytes(), acc.GetAddress().Bytes())
+	require.Equal(t, int64(1), acc.GetSequence())
+
+	// an account that does not exist is proven absent
+	acc, err = QueryAccount(testQuerier{app: ethermint}, codec, header, ethcmn.BytesToAddress([]byte("unknown")))
+	require.Nil(t, err)
+	require.Nil(t, acc)
+
+	otherKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	other, _ := newTestApp(t, otherKey)
+
+	testCases := []struct {
+		header  tmtypes.Header
+		querier testQuerier
+	}{
+		// the header must commit to the queried state
+		{tmtypes.Header{Height: 2, AppHash: make([]byte, 20)}, testQuerier{app: ethermint}},
+		{tmtypes.Header{Height: 3, AppHash: header.AppHash}, testQuerier{app: ethermint}},
+		// the served commit info and values must be proven
+		{header, testQuerier{app: ethermint, tamper: func(path string, res *abci.ResponseQuery) {
+			if path == app.QueryPathCommitInfo {
+				*res = other.Query(abci.RequestQuery{Path: path, Height: 1})
+			}
+		}}},
+		{header, testQuerier{app: ethermint, tamper: func(path string, res *abci.ResponseQuery) {
+			if path != app.QueryPathCommitInfo {
+				res.Value = []byte("tampered")
+			}
+		}}},
+		{header, testQuerier{app: ethermint, tamper: func(path string, res *abci.ResponseQuery) {
+			if path != app.QueryPathCommitInfo {
+				res.Value = nil
+			}
+		}}},
+		{header, testQuerier{app: ethermint, tamper: func(path string, res *abci.ResponseQuery) {
+			if path != app.QueryPathCommitInfo {
+				res.Proof = nil
+			}
+		}}},
+		{header, testQuerier{app: other, tamper: func(path string, res *abci.ResponseQuery) {
+			if path == app.QueryPathCommitInfo {
+				*res = ethermint.Query(abci.RequestQuery{Path: path, Height: 1})
+			}
+		}}},
+	}
+
+	for i, tc := range testCases {
+		_, err := QueryAccount(tc.querier, codec, tc.header, addr)
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+	}
+}
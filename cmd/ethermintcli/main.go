@@ -1,7 +1,25 @@
 package main
 
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
 func main() {
-	// TODO: Implement CLI commands and logic
+	// TODO: Implement remaining CLI commands and logic
 	//
 	// Ref: https://github.com/cosmos/ethermint/issues/432
+	rootCmd := &cobra.Command{
+		Use:   "ethermintcli",
+		Short: "Ethermint client",
+	}
+
+	rootCmd.AddCommand(signMessageCmd(), verifyMessageCmd(), txCmd())
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
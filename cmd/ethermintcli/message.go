@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagKey = "key"
+	flagHex = "hex"
+)
+
+// signMessageCmd returns a command that signs an arbitrary message as of
+// EIP-191, as personal_sign does, e.g. to prove control of an account to an
+// off-chain service.
+func signMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-message <message>",
+		Short: "Sign an arbitrary message with the \\x19Ethereum Signed Message prefix",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyPath, err := cmd.Flags().GetString(flagKey)
+			if err != nil {
+				return err
+			}
+
+			if keyPath == "" {
+				return fmt.Errorf("--%s must be set", flagKey)
+			}
+
+			priv, err := ethcrypto.LoadECDSA(keyPath)
+			if err != nil {
+				return fmt.Errorf("invalid private key in %s: %v", keyPath, err)
+			}
+
+			msg, err := messageFromArg(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			sig, err := types.SignMessage(priv, msg)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(hexutil.Encode(sig))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagKey, "", "path to a file holding the hex encoded private key to sign with")
+	cmd.Flags().Bool(flagHex, false, "treat the message as hex encoded bytes")
+
+	return cmd
+}
+
+// verifyMessageCmd returns a command that verifies that a given account
+// signed an arbitrary message as of EIP-191.
+func verifyMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-message <address> <signature> <message>",
+		Short: "Verify the signature of an arbitrary message signed with the \\x19Ethereum Signed Message prefix",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := types.ParseHexAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			sig, err := hexutil.Decode(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid signature: %v", err)
+			}
+
+			msg, err := messageFromArg(cmd, args[2])
+			if err != nil {
+				return err
+			}
+
+			if err := types.VerifyMessage(addr, msg, sig); err != nil {
+				return err
+			}
+
+			fmt.Printf("valid signature by %s\n", types.ChecksumHex(addr))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagHex, false, "treat the message as hex encoded bytes")
+
+	return cmd
+}
+
+// messageFromArg returns the message given as a command argument, decoding it
+// if the command's hex flag is set. A message of "-" is read from stdin.
+func messageFromArg(cmd *cobra.Command, arg string) ([]byte, error) {
+	isHex, err := cmd.Flags().GetBool(flagHex)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := []byte(arg)
+	if arg == "-" {
+		if msg, err = ioutil.ReadAll(os.Stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	if !isHex {
+		return msg, nil
+	}
+
+	bz, err := hexutil.Decode(strings.TrimSpace(string(msg)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex message: %v", err)
+	}
+
+	return bz, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+const (
+	flagEncoding       = "encoding"
+	flagGenerateOnly   = "generate-only"
+	flagNode           = "node"
+	flagTendermintNode = "tendermint-node"
+)
+
+// addOutputFlags adds the flags selecting how the transaction built by a
+// command is encoded, by default in the given encoding, and where it is sent.
+func addOutputFlags(cmd *cobra.Command, defaultEncoding string) {
+	cmd.Flags().String(
+		flagEncoding, defaultEncoding,
+		fmt.Sprintf("encoding of the transaction, either %q or %q", types.TxEncodingRLP, types.TxEncodingAmino),
+	)
+	cmd.Flags().Bool(flagGenerateOnly, false, "print the unsigned transaction instead of signing and sending it")
+	cmd.Flags().String(flagNode, "", "JSON-RPC endpoint to send an RLP encoded transaction to instead of printing it")
+	cmd.Flags().String(
+		flagTendermintNode, "", "Tendermint RPC endpoint (tcp://<host>:<port>) to broadcast the transaction to instead of printing it",
+	)
+}
+
+// outputTx encodes a transaction as of the command's encoding flag and sends
+// it to the node given by the command's flags, if any, or prints it hex
+// encoded otherwise. An unsigned transaction generated only is always
+// printed.
+func outputTx(cmd *cobra.Command, tx sdk.Tx) error {
+	encoding, err := cmd.Flags().GetString(flagEncoding)
+	if err != nil {
+		return err
+	}
+
+	encoder, err := types.NewTxEncoder(app.MakeCodec(), encoding)
+	if err != nil {
+		return err
+	}
+
+	txBytes, err := encoder(tx)
+	if err != nil {
+		return err
+	}
+
+	generateOnly, err := cmd.Flags().GetBool(flagGenerateOnly)
+	if err != nil {
+		return err
+	}
+
+	node, err := cmd.Flags().GetString(flagNode)
+	if err != nil {
+		return err
+	}
+
+	tmNode, err := cmd.Flags().GetString(flagTendermintNode)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case generateOnly || (node == "" && tmNode == ""):
+		fmt.Println(hexutil.Encode(txBytes))
+		return nil
+
+	case node != "" && tmNode != "":
+		return fmt.Errorf("--%s and --%s are mutually exclusive", flagNode, flagTendermintNode)
+
+	case node != "":
+		if encoding != types.TxEncodingRLP {
+			return fmt.Errorf("only %s encoded transactions can be sent to --%s", types.TxEncodingRLP, flagNode)
+		}
+
+		return sendRawTx(node, txBytes)
+
+	default:
+		return broadcastTx(tmNode, txBytes)
+	}
+}
+
+// sendRawTx sends an RLP encoded transaction to a JSON-RPC endpoint and prints
+// its hash.
+func sendRawTx(node string, txBytes []byte) error {
+	client, err := ethrpc.Dial(node)
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	var hash ethcmn.Hash
+	if err := client.Call(&hash, "eth_sendRawTransaction", hexutil.Bytes(txBytes)); err != nil {
+		return err
+	}
+
+	fmt.Println(hash.Hex())
+	return nil
+}
+
+// broadcastTx broadcasts a transaction to a Tendermint RPC endpoint, waiting
+// for it to be checked, and prints its Tendermint hash.
+func broadcastTx(node string, txBytes []byte) error {
+	res, err := rpcclient.NewHTTP(node, "/websocket").BroadcastTxSync(txBytes)
+	if err != nil {
+		return err
+	}
+
+	if res.Code != uint32(sdk.ABCICodeOK) {
+		return fmt.Errorf("transaction rejected with code %d: %s", res.Code, res.Log)
+	}
+
+	fmt.Println(res.Hash.String())
+	return nil
+}
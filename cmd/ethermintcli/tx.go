@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/abi"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/faucet"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagABI      = "abi"
+	flagMethod   = "method"
+	flagArgs     = "args"
+	flagNonce    = "nonce"
+	flagGas      = "gas"
+	flagGasPrice = "gas-price"
+	flagValue    = "value"
+	flagChainID  = "chain-id"
+)
+
+// txCmd returns the parent command of the commands building transactions.
+func txCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Build, sign and send transactions",
+	}
+
+	cmd.AddCommand(callContractCmd(), requestFundsCmd())
+
+	return cmd
+}
+
+// callContractCmd returns a command that builds and signs a transaction
+// calling a contract method, encoding the call from the contract's JSON ABI.
+// The signed transaction is output as of the command's output flags, by
+// default as hex encoded RLP, e.g. to be sent later with
+// eth_sendRawTransaction.
+func callContractCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <contract>",
+		Short: "Call a contract method with arguments encoded as of the contract's ABI",
+		Example: "ethermintcli tx call 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed --abi erc20.json " +
+			"--method transfer --args 0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359,100 --key key.hex --nonce 0",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, err := types.ParseHexAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			data, err := callDataFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			tx, err := txFromFlags(cmd, to, data)
+			if err != nil {
+				return err
+			}
+
+			if err := signTxFromFlags(cmd, tx); err != nil {
+				return err
+			}
+
+			return outputTx(cmd, tx)
+		},
+	}
+
+	cmd.Flags().String(flagABI, "", "path to the contract's JSON ABI")
+	cmd.Flags().String(flagMethod, "", "name of the method to call")
+	cmd.Flags().StringSlice(flagArgs, nil, "comma separated arguments of the method, quoted if containing commas")
+	cmd.Flags().String(flagValue, "0", "value sent along with the call, in the native denom's base unit")
+	addEthTxFlags(cmd, 200000)
+	addOutputFlags(cmd, types.TxEncodingRLP)
+
+	return cmd
+}
+
+// requestFundsCmd returns a command that builds a transaction requesting
+// testnet faucet funds for an address. The request requires no signature, so
+// that it is output as an unsigned amino encoded SDK transaction by default.
+// As RLP, it is embedded into an Ethereum transaction signed by the key given.
+func requestFundsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "request-funds <address>",
+		Short: "Request testnet faucet funds for an address",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := types.ParseAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			msg := faucet.NewMsgRequestFunds(sdk.AccAddress(addr.Bytes()))
+
+			encoding, err := cmd.Flags().GetString(flagEncoding)
+			if err != nil {
+				return err
+			}
+
+			if encoding != types.TxEncodingRLP {
+				return outputTx(cmd, auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(0), nil, ""))
+			}
+
+			tx, err := embeddedTxFromFlags(cmd, msg)
+			if err != nil {
+				return err
+			}
+
+			if err := signTxFromFlags(cmd, tx); err != nil {
+				return err
+			}
+
+			return outputTx(cmd, tx)
+		},
+	}
+
+	addEthTxFlags(cmd, 0)
+	addOutputFlags(cmd, types.TxEncodingAmino)
+
+	return cmd
+}
+
+// addEthTxFlags adds the flags of the Ethereum transaction built by a command
+// with the given default gas limit, a zero gas limit defaulting to the
+// transaction's intrinsic gas.
+func addEthTxFlags(cmd *cobra.Command, defaultGas uint64) {
+	cmd.Flags().String(flagKey, "", "path to a file holding the hex encoded private key to sign with")
+	cmd.Flags().Uint64(flagNonce, 0, "nonce of the sender")
+	cmd.Flags().Uint64(flagGas, defaultGas, "gas limit of the transaction")
+	cmd.Flags().String(flagGasPrice, "1", "gas price of the transaction, in the native denom's base unit")
+	cmd.Flags().Int64(flagChainID, app.DefaultEthChainID, "Ethereum chain ID to sign for")
+}
+
+// callDataFromFlags returns the data of the contract call given by the
+// command's ABI, method and arguments flags.
+func callDataFromFlags(cmd *cobra.Command) ([]byte, error) {
+	abiPath, err := cmd.Flags().GetString(flagABI)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := cmd.Flags().GetString(flagMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if abiPath == "" || method == "" {
+		return nil, fmt.Errorf("--%s and --%s must be set", flagABI, flagMethod)
+	}
+
+	args, err := cmd.Flags().GetStringSlice(flagArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := abi.LoadFile(abiPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return abi.PackCall(contractABI, method, args...)
+}
+
+// txFromFlags returns an unsigned transaction to the given address with the
+// given data as of the command's flags.
+func txFromFlags(cmd *cobra.Command, to ethcmn.Address, data []byte) (*types.Transaction, error) {
+	nonce, err := cmd.Flags().GetUint64(flagNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := cmd.Flags().GetUint64(flagGas)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := bigIntFlag(cmd, flagGasPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := bigIntFlag(cmd, flagValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewTransaction(nonce, to, value, gas, gasPrice, data), nil
+}
+
+// embeddedTxFromFlags returns an unsigned Ethereum transaction embedding the
+// given messages as of the command's flags. The messages must require no
+// signature.
+func embeddedTxFromFlags(cmd *cobra.Command, msgs ...sdk.Msg) (*types.Transaction, error) {
+	nonce, err := cmd.Flags().GetUint64(flagNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	gas, err := cmd.Flags().GetUint64(flagGas)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := bigIntFlag(cmd, flagGasPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewEmbeddedTxBuilder(app.MakeCodec(), "", msgs...).
+		WithNonce(nonce).
+		WithGas(gas, gasPrice).
+		BuildUnsigned()
+}
+
+// signTxFromFlags signs a transaction with the key and chain ID given by the
+// command's flags, unless only an unsigned transaction is to be generated.
+func signTxFromFlags(cmd *cobra.Command, tx *types.Transaction) error {
+	generateOnly, err := cmd.Flags().GetBool(flagGenerateOnly)
+	if err != nil || generateOnly {
+		return err
+	}
+
+	keyPath, err := cmd.Flags().GetString(flagKey)
+	if err != nil {
+		return err
+	}
+
+	if keyPath == "" {
+		return fmt.Errorf("--%s must be set unless --%s is", flagKey, flagGenerateOnly)
+	}
+
+	priv, err := ethcrypto.LoadECDSA(keyPath)
+	if err != nil {
+		return fmt.Errorf("invalid private key in %s: %v", keyPath, err)
+	}
+
+	chainID, err := cmd.Flags().GetInt64(flagChainID)
+	if err != nil {
+		return err
+	}
+
+	tx.Sign(big.NewInt(chainID), priv)
+	return nil
+}
+
+// bigIntFlag returns the value of the given flag parsed as a non-negative
+// integer.
+func bigIntFlag(cmd *cobra.Command, name string) (*big.Int, error) {
+	s, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	x, ok := new(big.Int).SetString(s, 0)
+	if !ok || x.Sign() == -1 {
+		return nil, fmt.Errorf("invalid --%s %s", name, s)
+	}
+
+	return x, nil
+}
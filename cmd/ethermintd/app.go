@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/cosmos/ethermint/app"
+
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	flagStateDiffs           = "statediff.blocks"
+	flagInternalTransferTags = "tags.internal-transfers"
+)
+
+// openApplication loads the application from its database in the given
+// directory, as of its latest committed height, and returns it along with a
+// function closing the database. The Ethereum state is read from the
+// application's multi-store, as committed by its transactions. The
+// application is configured by the command's flags (see appOptionsFromFlags).
+func openApplication(cmd *cobra.Command, datadir string) (*app.EthermintApp, func(), error) {
+	opts, err := appOptionsFromFlags(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db := dbm.NewDB("application", dbm.LevelDBBackend, datadir)
+	return app.NewEthermintApp(log.NewNopLogger(), db, opts...), db.Close, nil
+}
+
+// appOptionsFromFlags returns the options of the application as configured by
+// the command's flags, inherited from the root command.
+func appOptionsFromFlags(cmd *cobra.Command) ([]func(*app.EthermintApp), error) {
+	var opts []func(*app.EthermintApp)
+
+	stateDiffBlocks, err := cmd.Flags().GetInt64(flagStateDiffs)
+	if err != nil {
+		return nil, err
+	}
+
+	if stateDiffBlocks > 0 {
+		opts = append(opts, app.EnableStateDiffs(stateDiffBlocks))
+	}
+
+	transferTags, err := cmd.Flags().GetBool(flagInternalTransferTags)
+	if err != nil {
+		return nil, err
+	}
+
+	if transferTags {
+		opts = append(opts, app.EnableInternalTransferTags())
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/cosmos/ethermint/snapshot"
+	"github.com/cosmos/ethermint/state"
+
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	flagFrom = "from"
+	flagFile = "file"
+)
+
+// bootstrapCmd returns a command that imports the Ethereum state at a given
+// height from another node's snapshot server, or from a snapshot file created
+// through the admin APIs, into an empty local database, allowing replicas to
+// be spun up without copying raw databases.
+func bootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Import the Ethereum state at a given height from another node over gRPC",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			from, err := cmd.Flags().GetString(flagFrom)
+			if err != nil {
+				return err
+			}
+
+			file, err := cmd.Flags().GetString(flagFile)
+			if err != nil {
+				return err
+			}
+
+			if (from == "") == (file == "") {
+				return fmt.Errorf("exactly one of --%s and --%s must be set", flagFrom, flagFile)
+			}
+
+			height, err := cmd.Flags().GetInt64(flagHeight)
+			if err != nil {
+				return err
+			}
+
+			datadir, err := cmd.Flags().GetString(flagDatadir)
+			if err != nil {
+				return err
+			}
+
+			ethermintDB, closeDB, err := openStateDatabase(datadir)
+			if err != nil {
+				return err
+			}
+
+			defer closeDB()
+
+			importer, err := state.NewSnapshotImporter(ethermintDB)
+			if err != nil {
+				return err
+			}
+
+			var header state.SnapshotHeader
+
+			if file != "" {
+				header, err = readSnapshotFile(file, importer)
+				from = file
+			} else {
+				header, err = fetchSnapshot(from, height, importer)
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to import snapshot from %s: %v", from, err)
+			}
+
+			commitID, entries, err := importer.Commit()
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("imported %d entries of the state at height %d from %s\n", entries, header.Height, from)
+			fmt.Printf("local version %d with hash %X\n", commitID.Version, commitID.Hash)
+
+			// the operator must check the verified roots against a trusted
+			// source
+			names := make([]string, 0, len(header.Roots))
+			for name := range header.Roots {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Printf("verified %s store root %X\n", name, header.Roots[name])
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagFrom, "", "address of the snapshot server to import the state from")
+	cmd.Flags().String(flagFile, "", "path to a snapshot file to import the state from")
+	cmd.Flags().Int64(flagHeight, 0, "height of the state to import from a snapshot server (defaults to the latest height)")
+	cmd.Flags().String(flagDatadir, path.Join(os.Getenv("HOME"), ".ethermint"), "directory for ethermint data")
+
+	return cmd
+}
+
+// fetchSnapshot streams the snapshot at a given height from a snapshot server
+// to a state.SnapshotWriter.
+func fetchSnapshot(addr string, height int64, w state.SnapshotWriter) (state.SnapshotHeader, error) {
+	conn, err := snapshot.Dial(addr)
+	if err != nil {
+		return state.SnapshotHeader{}, err
+	}
+
+	defer conn.Close()
+
+	return snapshot.Fetch(context.Background(), conn, height, w)
+}
+
+// readSnapshotFile reads a snapshot file to a state.SnapshotWriter.
+func readSnapshotFile(file string, w state.SnapshotWriter) (state.SnapshotHeader, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return state.SnapshotHeader{}, err
+	}
+
+	defer f.Close()
+
+	return snapshot.ReadFile(f, w)
+}
+
+// snapshotServerCmd returns a command that serves snapshots of the local
+// Ethereum state over gRPC to nodes bootstrapping from it.
+func snapshotServerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot-server",
+		Short: "Serve snapshots of the Ethereum state over gRPC to bootstrapping nodes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			laddr, err := cmd.Flags().GetString(flagLaddr)
+			if err != nil {
+				return err
+			}
+
+			datadir, err := cmd.Flags().GetString(flagDatadir)
+			if err != nil {
+				return err
+			}
+
+			ethermintDB, closeDB, err := openStateDatabase(datadir)
+			if err != nil {
+				return err
+			}
+
+			defer closeDB()
+
+			listener, err := net.Listen("tcp", laddr)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("serving state snapshots on %s\n", listener.Addr())
+			return snapshot.NewServer(ethermintDB).Serve(listener)
+		},
+	}
+
+	cmd.Flags().String(flagLaddr, "localhost:9090", "address to serve state snapshots on")
+	cmd.Flags().String(flagDatadir, path.Join(os.Getenv("HOME"), ".ethermint"), "directory for ethermint data")
+
+	return cmd
+}
+
+// openStateDatabase opens the state and code databases in the given directory
+// and returns the state database on top of them along with a function closing
+// them.
+func openStateDatabase(datadir string) (*state.Database, func(), error) {
+	stateDB := dbm.NewDB("state", dbm.LevelDBBackend, datadir)
+	codeDB := dbm.NewDB("code", dbm.LevelDBBackend, datadir)
+
+	closeDBs := func() {
+		stateDB.Close()
+		codeDB.Close()
+	}
+
+	ethermintDB, err := state.NewDatabase(stateDB, codeDB)
+	if err != nil {
+		closeDBs()
+		return nil, nil, fmt.Errorf("failed to initialize state database: %v", err)
+	}
+
+	return ethermintDB, closeDBs, nil
+}
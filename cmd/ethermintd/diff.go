@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cosmos/ethermint/state"
+
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/spf13/cobra"
+)
+
+// diffStateCmd returns a command that compares the Ethereum state of two
+// nodes' application databases at a given height and writes every differing
+// account, balance, nonce, code and storage slot to stdout, narrowing down
+// the cause of an application hash mismatch.
+func diffStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff-state [datadir-a] [datadir-b]",
+		Short: "Compare the Ethereum state of two data directories at a given height",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, err := cmd.Flags().GetInt64(flagHeight)
+			if err != nil {
+				return err
+			}
+
+			appA, closeA, err := openApplication(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			defer closeA()
+
+			appB, closeB, err := openApplication(cmd, args[1])
+			if err != nil {
+				return err
+			}
+			defer closeB()
+
+			// default to the latest height both data directories hold
+			if height <= 0 {
+				height = appA.LastBlockHeight()
+				if latest := appB.LastBlockHeight(); latest < height {
+					height = latest
+				}
+			}
+
+			dumpA, err := appA.DumpState(height)
+			if err != nil {
+				return fmt.Errorf("failed to load state of %s at height %d: %v", args[0], height, err)
+			}
+
+			dumpB, err := appB.DumpState(height)
+			if err != nil {
+				return fmt.Errorf("failed to load state of %s at height %d: %v", args[1], height, err)
+			}
+
+			return writeStateDiff(height, dumpA, dumpB)
+		},
+	}
+
+	cmd.Flags().Int64(flagHeight, 0, "height of the states to compare (defaults to the latest common height)")
+
+	return cmd
+}
+
+// writeStateDiff writes the differences between two state dumps of the given
+// height to stdout, one per line.
+func writeStateDiff(height int64, a, b ethstate.Dump) error {
+	diffs := state.DiffDumps(a, b)
+	if len(diffs) == 0 {
+		fmt.Printf("states are identical at height %d (root %s)\n", height, a.Root)
+		return nil
+	}
+
+	fmt.Printf("%d differences at height %d (root %s vs %s)\n", len(diffs), height, a.Root, b.Root)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tFIELD\tKEY\tA\tB")
+
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "0x%s\t%s\t%s\t%s\t%s\n", diff.Address, diff.Field, diff.Key, diff.A, diff.B)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagHeight  = "height"
+	flagDatadir = "datadir"
+)
+
+// dumpStateCmd returns a command that writes the complete Ethereum state at a
+// given height to stdout as a geth compatible state dump.
+func dumpStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump-state",
+		Short: "Dump the Ethereum state at a given height in geth's state dump format",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			height, err := cmd.Flags().GetInt64(flagHeight)
+			if err != nil {
+				return err
+			}
+
+			datadir, err := cmd.Flags().GetString(flagDatadir)
+			if err != nil {
+				return err
+			}
+
+			ethermintApp, closeDB, err := openApplication(cmd, datadir)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			dump, err := ethermintApp.DumpState(height)
+			if err != nil {
+				return fmt.Errorf("failed to dump state at height %d: %v", height, err)
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "    ")
+
+			return encoder.Encode(dump)
+		},
+	}
+
+	cmd.Flags().Int64(flagHeight, 0, "height of the state to dump (defaults to the latest height)")
+	cmd.Flags().String(flagDatadir, path.Join(os.Getenv("HOME"), ".ethermint", "data"), "directory of the application database")
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cosmos/ethermint/app"
+
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const flagJSON = "json"
+
+// validateGenesisCmd returns a command that validates a genesis file ahead of
+// a network launch, writing every problem found in its application state to
+// stdout along with the path of the offending field.
+func validateGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-genesis [genesis-file]",
+		Short: "Validate the EVM allocations, chain configuration and module parameters of a genesis file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			asJSON, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			genDoc, err := tmtypes.GenesisDocFromFile(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid genesis file: %v", err)
+			}
+
+			genesis, err := decodeGenesisState(genDoc.AppState())
+			if err != nil {
+				return fmt.Errorf("invalid application state: %v", err)
+			}
+
+			errs := app.ValidateGenesis(genesis)
+			if err := writeGenesisErrors(os.Stdout, errs, asJSON); err != nil {
+				return err
+			}
+
+			if len(errs) > 0 {
+				return fmt.Errorf("found %d problems in genesis file %s", len(errs), args[0])
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagJSON, false, "write the problems found as JSON")
+
+	return cmd
+}
+
+// decodeGenesisState decodes the application state of a genesis file. Any
+// unknown field, e.g. a misspelled one, is rejected rather than ignored.
+func decodeGenesisState(appState json.RawMessage) (app.GenesisState, error) {
+	var genesis app.GenesisState
+
+	if len(appState) == 0 {
+		return genesis, fmt.Errorf("missing app_state")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(appState))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&genesis); err != nil {
+		return genesis, err
+	}
+
+	return genesis, nil
+}
+
+// writeGenesisErrors writes the given problems of a genesis file, one per
+// line, or as a JSON array.
+func writeGenesisErrors(w io.Writer, errs []app.GenesisError, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+
+		return encoder.Encode(errs)
+	}
+
+	if len(errs) == 0 {
+		_, err := fmt.Fprintln(w, "genesis file is valid")
+		return err
+	}
+
+	for _, genErr := range errs {
+		if _, err := fmt.Fprintln(w, genErr.Error()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,37 @@
 package main
 
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
 func main() {
-	// TODO: Implement daemon command and logic
+	// TODO: Implement remaining daemon commands and logic
 	//
 	// Ref: https://github.com/cosmos/ethermint/issues/433
+	rootCmd := &cobra.Command{
+		Use:   "ethermintd",
+		Short: "Ethermint daemon",
+	}
+
+	rootCmd.PersistentFlags().Int64(
+		flagStateDiffs, 0, "number of blocks for which the state diffs of delivered transactions are retained (0 disables recording)",
+	)
+
+	rootCmd.PersistentFlags().Bool(
+		flagInternalTransferTags, false,
+		"tag the results of Ethereum transactions with their internal value transfers (traces every executed opcode)",
+	)
+
+	rootCmd.AddCommand(
+		dumpStateCmd(), diffStateCmd(), migrateCmd(), schemaCmd(), rpcServerCmd(), bootstrapCmd(), snapshotServerCmd(),
+		validateGenesisCmd(), p2pConfigCmd(),
+	)
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/ethermint/state"
+
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// migrateCmd returns a command that migrates the store layout of the state
+// and code databases to a given schema version.
+func migrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [version]",
+		Short: "Migrate the store layout to a given schema version (e.g. v2)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := strconv.ParseInt(strings.TrimPrefix(args[0], "v"), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid schema version %s", args[0])
+			}
+
+			datadir, err := cmd.Flags().GetString(flagDatadir)
+			if err != nil {
+				return err
+			}
+
+			stateDB := dbm.NewDB("state", dbm.LevelDBBackend, datadir)
+			codeDB := dbm.NewDB("code", dbm.LevelDBBackend, datadir)
+
+			defer stateDB.Close()
+			defer codeDB.Close()
+
+			current := state.SchemaVersion(stateDB)
+			if err := state.Migrate(stateDB, codeDB, target); err != nil {
+				return err
+			}
+
+			fmt.Printf("migrated store layout from schema version %d to %d\n", current, target)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagDatadir, path.Join(os.Getenv("HOME"), ".ethermint"), "directory for ethermint data")
+
+	return cmd
+}
+
+// schemaCmd returns a command that writes the documentation of the store
+// layout to stdout.
+func schemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the documentation of the store layout in markdown",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return state.WriteSchemaDoc(os.Stdout)
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/cosmos/ethermint/server"
+
+	"github.com/spf13/cobra"
+	tmcfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+const (
+	flagSeeds           = "p2p.seeds"
+	flagPersistentPeers = "p2p.persistent_peers"
+	flagPrivatePeerIDs  = "p2p.private_peer_ids"
+	flagPEX             = "p2p.pex"
+	flagSeedMode        = "p2p.seed_mode"
+	flagAddrBook        = "p2p.addr_book_file"
+	flagAddrBookStrict  = "p2p.addr_book_strict"
+	flagLocked          = "p2p.locked"
+	flagForce           = "force"
+)
+
+// p2pConfigCmd returns a command that writes the configuration file of the
+// Tendermint node backing the daemon with the given peer-to-peer settings, so
+// that e.g. a private consortium deployment can be locked to a fixed set of
+// peers without editing raw TOML. All other settings take their defaults.
+func p2pConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "p2p-config",
+		Short: "Write the Tendermint configuration file with the given peer-to-peer settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			p2pCfg, err := p2pConfigFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			datadir, err := cmd.Flags().GetString(flagDatadir)
+			if err != nil {
+				return err
+			}
+
+			force, err := cmd.Flags().GetBool(flagForce)
+			if err != nil {
+				return err
+			}
+
+			tmCfg := tmcfg.DefaultConfig().SetRoot(datadir)
+			p2pCfg.Apply(tmCfg.P2P)
+
+			configFile := filepath.Join(datadir, "config", "config.toml")
+			if cmn.FileExists(configFile) && !force {
+				return fmt.Errorf("configuration file %s already exists, use --%s to overwrite it", configFile, flagForce)
+			}
+
+			if err := cmn.EnsureDir(filepath.Dir(configFile), 0700); err != nil {
+				return err
+			}
+
+			tmcfg.WriteConfigFile(configFile, tmCfg)
+
+			fmt.Printf("wrote %s\n", configFile)
+			if p2pCfg.Locked() {
+				fmt.Printf("peer exchange disabled, connecting to %d persistent peers only\n", len(p2pCfg.PersistentPeers))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice(flagSeeds, nil, "addresses (id@host:port) of the seed nodes to discover peers from")
+	cmd.Flags().StringSlice(flagPersistentPeers, nil, "addresses (id@host:port) of the peers to keep connections to")
+	cmd.Flags().StringSlice(flagPrivatePeerIDs, nil, "IDs of the peers not to gossip to other peers")
+	cmd.Flags().Bool(flagPEX, true, "enable peer exchange")
+	cmd.Flags().Bool(flagSeedMode, false, "run the node as a seed node crawling the network for peers")
+	cmd.Flags().String(flagAddrBook, "", "path to the address book, relative to the data directory (defaults to Tendermint's)")
+	cmd.Flags().Bool(flagAddrBookStrict, true, "only add routable addresses to the address book")
+	cmd.Flags().Bool(flagLocked, false, "only connect to the persistent peers, disabling peer exchange and strict addresses")
+	cmd.Flags().Bool(flagForce, false, "overwrite an existing configuration file")
+	cmd.Flags().String(flagDatadir, path.Join(os.Getenv("HOME"), ".ethermint"), "directory for ethermint data")
+
+	return cmd
+}
+
+// p2pConfigFromFlags returns the peer-to-peer configuration of the Tendermint
+// node as configured by the command's flags. A locked configuration starts
+// out from server.LockedP2PConfig, yet any flag explicitly set takes
+// precedence as long as the peer set remains locked.
+func p2pConfigFromFlags(cmd *cobra.Command) (server.P2PConfig, error) {
+	locked, err := cmd.Flags().GetBool(flagLocked)
+	if err != nil {
+		return server.P2PConfig{}, err
+	}
+
+	cfg := server.DefaultP2PConfig()
+	if locked {
+		cfg = server.LockedP2PConfig()
+	}
+
+	if err := overrideP2PConfig(cmd, &cfg); err != nil {
+		return cfg, err
+	}
+
+	if locked && !cfg.Locked() {
+		return cfg, fmt.Errorf("--%s conflicts with peer exchange and seeds", flagLocked)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// overrideP2PConfig overrides the peer-to-peer configuration with every flag
+// explicitly set.
+func overrideP2PConfig(cmd *cobra.Command, cfg *server.P2PConfig) (err error) {
+	flags := cmd.Flags()
+
+	lists := []struct {
+		name  string
+		value *[]string
+	}{
+		{flagSeeds, &cfg.Seeds},
+		{flagPersistentPeers, &cfg.PersistentPeers},
+		{flagPrivatePeerIDs, &cfg.PrivatePeerIDs},
+	}
+
+	for _, list := range lists {
+		if flags.Changed(list.name) {
+			if *list.value, err = flags.GetStringSlice(list.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	toggles := []struct {
+		name  string
+		value *bool
+	}{
+		{flagPEX, &cfg.PEX},
+		{flagSeedMode, &cfg.SeedMode},
+		{flagAddrBookStrict, &cfg.AddrBookStrict},
+	}
+
+	for _, toggle := range toggles {
+		if flags.Changed(toggle.name) {
+			if *toggle.value, err = flags.GetBool(toggle.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if flags.Changed(flagAddrBook) {
+		cfg.AddrBook, err = flags.GetString(flagAddrBook)
+	}
+
+	return err
+}
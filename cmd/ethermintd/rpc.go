@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/rpc"
+	"github.com/cosmos/ethermint/snapshot"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	flagNode    = "node"
+	flagLaddr   = "laddr"
+	flagChainID = "chain-id"
+
+	flagGasPriceBlocks     = "gpo.blocks"
+	flagGasPricePercentile = "gpo.percentile"
+
+	flagEtherbase = "miner.etherbase"
+
+	flagNetworkName         = "network.name"
+	flagNetworkRPCURLs      = "network.rpc-urls"
+	flagNetworkExplorerURLs = "network.explorer-urls"
+
+	flagTLSCert   = "rpc.tls-cert"
+	flagTLSKey    = "rpc.tls-key"
+	flagAuthToken = "rpc.auth-token"
+	flagJWTSecret = "rpc.jwt-secret"
+
+	flagCORSOrigins = "rpc.cors-origins"
+
+	flagAdmin        = "rpc.admin"
+	flagLogLevel     = "log.level"
+	flagSnapshotFrom = "snapshot.from"
+	flagSnapshotDir  = "snapshot.dir"
+	flagMetadataDir  = "metadata.dir"
+
+	flagHealthReferenceNode   = "health.reference-node"
+	flagHealthReadyMaxLag     = "health.ready-max-block-lag"
+	flagHealthReadyMaxBacklog = "health.ready-max-event-backlog"
+	flagHealthLiveMaxLag      = "health.live-max-block-lag"
+	flagHealthLiveMaxBacklog  = "health.live-max-event-backlog"
+)
+
+// rpcServerCmd returns a command that serves the Ethereum JSON-RPC APIs over
+// HTTP and WebSocket. All chain data is queried from a remote Tendermint node,
+// allowing RPC servers to be scaled independently of full nodes.
+func rpcServerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-server",
+		Short: "Serve the Ethereum JSON-RPC APIs backed by a remote Tendermint node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			node, err := cmd.Flags().GetString(flagNode)
+			if err != nil {
+				return err
+			}
+
+			laddr, err := cmd.Flags().GetString(flagLaddr)
+			if err != nil {
+				return err
+			}
+
+			chainID, err := cmd.Flags().GetInt64(flagChainID)
+			if err != nil {
+				return err
+			}
+
+			gpoConfig := rpc.DefaultGasPriceConfig()
+
+			if gpoConfig.Blocks, err = cmd.Flags().GetInt(flagGasPriceBlocks); err != nil {
+				return err
+			}
+
+			if gpoConfig.Percentile, err = cmd.Flags().GetInt(flagGasPricePercentile); err != nil {
+				return err
+			}
+
+			hexEtherbase, err := cmd.Flags().GetString(flagEtherbase)
+			if err != nil {
+				return err
+			}
+
+			var etherbase ethcmn.Address
+			if hexEtherbase != "" {
+				if etherbase, err = types.ParseHexAddress(hexEtherbase); err != nil {
+					return err
+				}
+			}
+
+			network, err := networkConfigFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			authConfig, err := authConfigFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			healthConfig, err := healthConfigFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			tlsCert, err := cmd.Flags().GetString(flagTLSCert)
+			if err != nil {
+				return err
+			}
+
+			tlsKey, err := cmd.Flags().GetString(flagTLSKey)
+			if err != nil {
+				return err
+			}
+
+			if (tlsCert == "") != (tlsKey == "") {
+				return fmt.Errorf("both --%s and --%s must be set to enable TLS", flagTLSCert, flagTLSKey)
+			}
+
+			logLevel, err := cmd.Flags().GetString(flagLogLevel)
+			if err != nil {
+				return err
+			}
+
+			lvl, err := log.LvlFromString(logLevel)
+			if err != nil {
+				return err
+			}
+
+			logs := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+			logs.Verbosity(lvl)
+			log.Root().SetHandler(logs)
+
+			corsOrigins, err := cmd.Flags().GetStringSlice(flagCORSOrigins)
+			if err != nil {
+				return err
+			}
+
+			admin, err := cmd.Flags().GetBool(flagAdmin)
+			if err != nil {
+				return err
+			}
+
+			if admin {
+				if err := authConfig.ValidateAdmin(corsOrigins); err != nil {
+					return fmt.Errorf("cannot enable --%s: %v", flagAdmin, err)
+				}
+			}
+
+			cdc := app.MakeCodec()
+			backend := rpc.NewTendermintBackend(rpc.NewHTTPClient(node))
+			apis := rpc.GetRPCAPIs(cdc, backend, big.NewInt(chainID), gpoConfig, etherbase, network, admin)
+
+			metadataDir, err := cmd.Flags().GetString(flagMetadataDir)
+			if err != nil {
+				return err
+			}
+
+			// the metadata of verified contracts is only served if stored
+			var metadataStore *rpc.MetadataStore
+			if metadataDir != "" {
+				metadataStore = rpc.NewMetadataStore(dbm.NewDB("metadata", dbm.LevelDBBackend, metadataDir))
+				apis = append(apis, ethrpc.API{
+					Namespace: "ethermint",
+					Version:   "1.0",
+					Service:   rpc.NewPublicMetadataAPI(metadataStore),
+					Public:    true,
+				})
+			}
+
+			// WebSocket connections, which subscriptions require, are served
+			// on the same address to the same origins
+			modules := rpc.NewModules(func(srv *ethrpc.Server) http.Handler {
+				httpHandler := ethrpc.NewHTTPServer(corsOrigins, []string{"*"}, srv).Handler
+				return rpc.WithWebsocket(httpHandler, srv, corsOrigins)
+			})
+
+			if admin {
+				adminAPI, err := adminAPIFromFlags(cmd, backend, modules, logs)
+				if err != nil {
+					return err
+				}
+
+				apis = append(apis, adminAPI)
+
+				if metadataStore != nil {
+					apis = append(apis, ethrpc.API{
+						Namespace: rpc.AdminNamespace,
+						Version:   "1.0",
+						Service:   rpc.NewPrivateMetadataAPI(cdc, backend, metadataStore),
+						Public:    false,
+					})
+				}
+			}
+
+			if err := modules.Register(apis...); err != nil {
+				return err
+			}
+
+			listener, err := net.Listen("tcp", laddr)
+			if err != nil {
+				return err
+			}
+
+			// the HTTP server's timeouts are retained while its handler is
+			// replaced by the modules, which wrap every server they build, the
+			// health probes being served without authentication
+			httpServer := ethrpc.NewHTTPServer(corsOrigins, []string{"*"}, ethrpc.NewServer())
+			httpServer.Handler = rpc.WithHealthProbes(rpc.NewAuthHandler(authConfig, modules), backend, healthConfig)
+
+			if tlsCert != "" {
+				fmt.Printf("serving JSON-RPC over TLS on %s using node %s\n", listener.Addr(), node)
+				return httpServer.ServeTLS(listener, tlsCert, tlsKey)
+			}
+
+			fmt.Printf("serving JSON-RPC on %s using node %s\n", listener.Addr(), node)
+			return httpServer.Serve(listener)
+		},
+	}
+
+	cmd.Flags().String(flagNode, "tcp://localhost:26657", "address of the Tendermint node to query")
+	cmd.Flags().String(flagLaddr, "localhost:8545", "address to serve the JSON-RPC APIs on")
+	cmd.Flags().Int64(flagChainID, 1, "chain ID used to derive transaction senders")
+	cmd.Flags().Int(flagGasPriceBlocks, 20, "number of recent blocks to sample gas prices from")
+	cmd.Flags().Int(flagGasPricePercentile, 60, "percentile of the sampled gas prices to suggest")
+	cmd.Flags().String(flagEtherbase, "", "fee recipient address reported as the coinbase (defaults to the node's validator address)")
+	cmd.Flags().String(flagNetworkName, "Ethermint", "name of the chain reported to wallets")
+	cmd.Flags().StringSlice(flagNetworkRPCURLs, nil, "public URLs of the chain's JSON-RPC servers reported to wallets")
+	cmd.Flags().StringSlice(flagNetworkExplorerURLs, nil, "URLs of the chain's block explorers reported to wallets")
+	cmd.Flags().String(flagTLSCert, "", "path to the TLS certificate to serve the JSON-RPC APIs over HTTPS with")
+	cmd.Flags().String(flagTLSKey, "", "path to the private key of the TLS certificate")
+	cmd.Flags().String(flagAuthToken, "", "static bearer token required by every JSON-RPC request")
+	cmd.Flags().String(flagJWTSecret, "", "path to a hex encoded 32-byte secret signing the HS256 JWTs accepted as bearer tokens")
+	cmd.Flags().StringSlice(
+		flagCORSOrigins, []string{"*"},
+		"origins allowed to make cross-origin requests and WebSocket connections (* allows any, which --rpc.admin refuses)",
+	)
+	cmd.Flags().Bool(
+		flagAdmin, false,
+		"serve the admin and miner APIs controlling the node and the RPC server at runtime (requires --rpc.auth-token or --rpc.jwt-secret)",
+	)
+	cmd.Flags().String(flagLogLevel, "info", "level of the RPC server's logs (crit, error, warn, info, debug or trace)")
+	cmd.Flags().String(flagSnapshotFrom, "", "address of the snapshot server the admin APIs create snapshots from")
+	cmd.Flags().String(flagSnapshotDir, "", "directory the admin APIs write snapshots to")
+	cmd.Flags().String(flagMetadataDir, "", "directory to store the metadata of contracts verified through the admin APIs in")
+	cmd.Flags().String(flagHealthReferenceNode, "", "address of the Tendermint node the health probes measure the node's block lag against")
+	cmd.Flags().Int64(flagHealthReadyMaxLag, 5, "number of blocks the node may lag behind the reference node while ready (0 disables the check)")
+	cmd.Flags().Int64(flagHealthReadyMaxBacklog, 0, "number of blocks whose events the node may not have indexed while ready (0 disables the check)")
+	cmd.Flags().Int64(flagHealthLiveMaxLag, 0, "number of blocks the node may lag behind the reference node while live (0 disables the check)")
+	cmd.Flags().Int64(flagHealthLiveMaxBacklog, 0, "number of blocks whose events the node may not have indexed while live (0 disables the check)")
+
+	return cmd
+}
+
+// networkConfigFromFlags returns the metadata of the network reported to
+// wallets as configured by the command's flags.
+func networkConfigFromFlags(cmd *cobra.Command) (rpc.NetworkConfig, error) {
+	var (
+		cfg rpc.NetworkConfig
+		err error
+	)
+
+	if cfg.ChainName, err = cmd.Flags().GetString(flagNetworkName); err != nil {
+		return cfg, err
+	}
+
+	if cfg.RPCURLs, err = cmd.Flags().GetStringSlice(flagNetworkRPCURLs); err != nil {
+		return cfg, err
+	}
+
+	cfg.BlockExplorerURLs, err = cmd.Flags().GetStringSlice(flagNetworkExplorerURLs)
+	return cfg, err
+}
+
+// authConfigFromFlags returns the authentication required by the JSON-RPC
+// server as configured by the command's flags.
+func authConfigFromFlags(cmd *cobra.Command) (rpc.AuthConfig, error) {
+	var (
+		cfg rpc.AuthConfig
+		err error
+	)
+
+	if cfg.Token, err = cmd.Flags().GetString(flagAuthToken); err != nil {
+		return cfg, err
+	}
+
+	secretPath, err := cmd.Flags().GetString(flagJWTSecret)
+	if err != nil || secretPath == "" {
+		return cfg, err
+	}
+
+	bz, err := ioutil.ReadFile(secretPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	if cfg.JWTSecret, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(bz)), "0x")); err != nil {
+		return cfg, fmt.Errorf("invalid JWT secret in %s: %v", secretPath, err)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// healthConfigFromFlags returns the thresholds of the health probes as
+// configured by the command's flags. The block lag is only checked if a
+// reference node is set.
+func healthConfigFromFlags(cmd *cobra.Command) (rpc.HealthConfig, error) {
+	var (
+		cfg rpc.HealthConfig
+		err error
+	)
+
+	if cfg.Readiness.MaxBlockLag, err = cmd.Flags().GetInt64(flagHealthReadyMaxLag); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Readiness.MaxEventBacklog, err = cmd.Flags().GetInt64(flagHealthReadyMaxBacklog); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Liveness.MaxBlockLag, err = cmd.Flags().GetInt64(flagHealthLiveMaxLag); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Liveness.MaxEventBacklog, err = cmd.Flags().GetInt64(flagHealthLiveMaxBacklog); err != nil {
+		return cfg, err
+	}
+
+	referenceNode, err := cmd.Flags().GetString(flagHealthReferenceNode)
+	if err != nil || referenceNode == "" {
+		return cfg, err
+	}
+
+	cfg.Reference = rpc.NewTendermintBackend(rpc.NewHTTPClient(referenceNode))
+	return cfg, nil
+}
+
+// adminAPIFromFlags returns the admin API as configured by the command's
+// flags. Snapshot creation is only enabled if both a snapshot server and a
+// snapshot directory are configured.
+func adminAPIFromFlags(
+	cmd *cobra.Command, backend rpc.Backend, modules *rpc.Modules, logs *log.GlogHandler,
+) (ethrpc.API, error) {
+
+	snapshotFrom, err := cmd.Flags().GetString(flagSnapshotFrom)
+	if err != nil {
+		return ethrpc.API{}, err
+	}
+
+	snapshotDir, err := cmd.Flags().GetString(flagSnapshotDir)
+	if err != nil {
+		return ethrpc.API{}, err
+	}
+
+	var snapshotFn rpc.SnapshotFunc
+	if snapshotFrom != "" {
+		snapshotFn = func(height int64, w io.Writer) (int64, error) {
+			conn, err := snapshot.Dial(snapshotFrom)
+			if err != nil {
+				return 0, err
+			}
+
+			defer conn.Close()
+
+			header, err := snapshot.Fetch(context.Background(), conn, height, snapshot.NewFileWriter(w))
+			return header.Height, err
+		}
+	}
+
+	return ethrpc.API{
+		Namespace: rpc.AdminNamespace,
+		Version:   "1.0",
+		Service:   rpc.NewPrivateAdminAPI(backend, modules, logs, snapshotDir, snapshotFn),
+		Public:    false,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// blockRandomnessDomain separates the preimage of the block randomness from
+// any other hash computed over a block hash and height.
+var blockRandomnessDomain = []byte("ethermint/prevrandao")
+
+// BlockRandomness returns the value exposed to the EVM through the DIFFICULTY
+// (PREVRANDAO) opcode for the block with the given header. There is no proof
+// of work nor beacon chain randomness on a Tendermint chain, so the value is
+// defined as:
+//
+//	keccak256("ethermint/prevrandao" || LastBlockHash || uint64BE(Height))
+//
+// The last block hash commits to the previous block and its commit, both of
+// which are agreed upon by consensus, so every node computes the same value
+// for a given block. The height is included so that the value differs for
+// every block, including the first block which has no last block hash.
+//
+// NOTE: The value is deterministic but NOT unbiasable randomness. The
+// proposer of the previous block can influence it and it is known to every
+// node once the block is proposed, so contracts must not rely on it for
+// anything of value.
+func BlockRandomness(header abci.Header) *big.Int {
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, uint64(header.Height))
+
+	hash := ethcrypto.Keccak256(blockRandomnessDomain, header.LastBlockHash, height)
+	return new(big.Int).SetBytes(hash)
+}
+
+// BlockTime returns the value exposed to the EVM through the TIMESTAMP opcode
+// for the block with the given header, given the value exposed for the
+// previous block.
+//
+// The header's time is Tendermint's BFT time: the median of the times of the
+// votes committing the previous block, weighted by voting power. It is agreed
+// upon by consensus and cannot be skewed by a single proposer as long as more
+// than two thirds of the voting power is honest. It differs from Ethereum
+// mainnet's block timestamp in two ways. First, while both have a granularity
+// of one second, blocks are typically committed every few seconds rather than
+// every 12 to 15 seconds, so contracts must not assume a minimum interval
+// between blocks. Second, consecutive blocks may share the same timestamp,
+// whereas every Ethereum block's timestamp is strictly greater than its
+// parent's.
+//
+// The returned time never precedes the previous block's time, so that
+// contracts may rely on it being monotonic even if a header's time
+// regresses, e.g. upon a chain restart with a misconfigured genesis time.
+func BlockTime(header abci.Header, lastTime int64) int64 {
+	if header.Time < lastTime {
+		return lastTime
+	}
+
+	return header.Time
+}
+
+// NewVMContext returns the EVM block context for executing a transaction
+// within the block with the given header. The block number, time and
+// DIFFICULTY (PREVRANDAO) value are derived from the header alone so that
+// they are identical on all nodes. The header's time must have been resolved
+// with BlockTime. The given GetHashFunc backs the BLOCKHASH
+// opcode and may be nil, in which case the zero hash is returned for every
+// block.
+func NewVMContext(
+	header abci.Header, origin, coinbase ethcmn.Address, gasPrice *big.Int, gasLimit uint64, getHash ethvm.GetHashFunc,
+) ethvm.Context {
+
+	if getHash == nil {
+		getHash = func(uint64) ethcmn.Hash { return ethcmn.Hash{} }
+	}
+
+	return ethvm.Context{
+		CanTransfer: ethcore.CanTransfer,
+		Transfer:    ethcore.Transfer,
+		GetHash:     getHash,
+		Origin:      origin,
+		Coinbase:    coinbase,
+		BlockNumber: big.NewInt(header.Height),
+		Time:        big.NewInt(header.Time),
+		Difficulty:  BlockRandomness(header),
+		GasLimit:    gasLimit,
+		GasPrice:    new(big.Int).Set(gasPrice),
+	}
+}
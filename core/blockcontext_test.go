@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// difficultyCode is EVM byte code that returns the 32-byte value of the
+// DIFFICULTY (PREVRANDAO) opcode.
+var difficultyCode = []byte{
+	byte(ethvm.DIFFICULTY),
+	byte(ethvm.PUSH1), 0x00,
+	byte(ethvm.MSTORE),
+	byte(ethvm.PUSH1), 0x20,
+	byte(ethvm.PUSH1), 0x00,
+	byte(ethvm.RETURN),
+}
+
+// timestampCode is EVM byte code that returns the 32-byte value of the
+// TIMESTAMP opcode, i.e. a contract's block.timestamp.
+var timestampCode = []byte{
+	byte(ethvm.TIMESTAMP),
+	byte(ethvm.PUSH1), 0x00,
+	byte(ethvm.MSTORE),
+	byte(ethvm.PUSH1), 0x20,
+	byte(ethvm.PUSH1), 0x00,
+	byte(ethvm.RETURN),
+}
+
+func TestBlockRandomness(t *testing.T) {
+	hashA := ethcmn.HexToHash("0x01").Bytes()
+	hashB := ethcmn.HexToHash("0x02").Bytes()
+
+	testCases := []struct {
+		a, b          abci.Header
+		expectedEqual bool
+	}{
+		{abci.Header{Height: 2, LastBlockHash: hashA}, abci.Header{Height: 2, LastBlockHash: hashA}, true},
+		{abci.Header{Height: 2, LastBlockHash: hashA, Time: 1}, abci.Header{Height: 2, LastBlockHash: hashA, Time: 2}, true},
+		{abci.Header{Height: 2, LastBlockHash: hashA}, abci.Header{Height: 3, LastBlockHash: hashA}, false},
+		{abci.Header{Height: 2, LastBlockHash: hashA}, abci.Header{Height: 2, LastBlockHash: hashB}, false},
+		{abci.Header{Height: 1}, abci.Header{Height: 2}, false},
+	}
+
+	for i, tc := range testCases {
+		a, b := BlockRandomness(tc.a), BlockRandomness(tc.b)
+
+		require.NotZero(t, a.Sign(), fmt.Sprintf("unexpected zero randomness: test case #%d", i))
+		require.Equal(t, tc.expectedEqual, a.Cmp(b) == 0, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestNewVMContext(t *testing.T) {
+	header := abci.Header{Height: 10, Time: 1000, LastBlockHash: ethcmn.HexToHash("0x0a").Bytes()}
+
+	ctx := NewVMContext(header, testCaller, testRecipient, big.NewInt(5), 1000000, nil)
+	require.Equal(t, big.NewInt(10), ctx.BlockNumber)
+	require.Equal(t, big.NewInt(1000), ctx.Time)
+	require.Equal(t, BlockRandomness(header), ctx.Difficulty)
+	require.Equal(t, testCaller, ctx.Origin)
+	require.Equal(t, testRecipient, ctx.Coinbase)
+	require.Equal(t, ethcmn.Hash{}, ctx.GetHash(9))
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.SetCode(testContract, difficultyCode)
+
+	evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{})
+
+	ret, _, err := evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 1000000, new(big.Int))
+	require.Nil(t, err)
+	require.Equal(t, BlockRandomness(header), new(big.Int).SetBytes(ret))
+}
+
+func TestBlockTime(t *testing.T) {
+	testCases := []struct {
+		time, lastTime int64
+		expected       int64
+	}{
+		{1000, 0, 1000},
+		{1001, 1000, 1001},
+		{1000, 1000, 1000},
+		{999, 1000, 1000},
+		{0, 1000, 1000},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(
+			t, tc.expected, BlockTime(abci.Header{Time: tc.time}, tc.lastTime),
+			fmt.Sprintf("unexpected time: test case #%d", i),
+		)
+	}
+}
+
+func TestBlockTimestampOpcode(t *testing.T) {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.SetCode(testContract, timestampCode)
+
+	testCases := []struct {
+		time, lastTime int64
+		expected       int64
+	}{
+		{1000, 0, 1000},
+		{1000, 1000, 1000},
+		{990, 1000, 1000},
+	}
+
+	for i, tc := range testCases {
+		header := abci.Header{Height: 10, Time: tc.time}
+		header.Time = BlockTime(header, tc.lastTime)
+
+		ctx := NewVMContext(header, testCaller, testRecipient, big.NewInt(5), 1000000, nil)
+		evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{})
+
+		ret, _, err := evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 1000000, new(big.Int))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, big.NewInt(tc.expected), new(big.Int).SetBytes(ret), fmt.Sprintf("unexpected timestamp: test case #%d", i))
+	}
+}
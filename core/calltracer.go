@@ -0,0 +1,328 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+type (
+	// CallFrame represents a single message call or contract creation made
+	// during a transaction along with the calls it made in turn, in the format
+	// of geth's callTracer. The gas of a call to an account without code is
+	// unknown and left zero.
+	CallFrame struct {
+		Type    string         `json:"type"`
+		From    ethcmn.Address `json:"from"`
+		To      ethcmn.Address `json:"to"`
+		Value   *hexutil.Big   `json:"value,omitempty"`
+		Gas     hexutil.Uint64 `json:"gas"`
+		GasUsed hexutil.Uint64 `json:"gasUsed"`
+		Input   hexutil.Bytes  `json:"input"`
+		Output  hexutil.Bytes  `json:"output,omitempty"`
+		Error   string         `json:"error,omitempty"`
+		Calls   []*CallFrame   `json:"calls,omitempty"`
+	}
+
+	// CallTracer implements Ethereum's vm.Tracer interface. It records the
+	// tree of message calls and contract creations made during a single
+	// transaction, including calls that failed or were reverted.
+	CallTracer struct {
+		onlyTopCall bool
+
+		root   *CallFrame
+		frames []*openCall
+
+		// descended is set when a call was just issued so that the gas given
+		// to the callee is recorded on its first step
+		descended bool
+	}
+
+	// CallTracerConfig defines the JSON configuration of the call tracer.
+	CallTracerConfig struct {
+		// OnlyTopCall skips recording the calls made by the transaction's
+		// top-level call.
+		OnlyTopCall bool `json:"onlyTopCall"`
+	}
+
+	// openCall is a call issued by the frame at the depth below and not yet
+	// returned from.
+	openCall struct {
+		frame *CallFrame
+		depth int
+
+		// gasIn and gasCost are the gas available before the call and the
+		// cost of the call itself, including the gas given to the callee
+		gasIn   uint64
+		gasCost uint64
+
+		outOffset int64
+		outSize   int64
+	}
+)
+
+// NewCallTracer returns a reference to a new CallTracer.
+func NewCallTracer(config CallTracerConfig) *CallTracer {
+	return &CallTracer{onlyTopCall: config.OnlyTopCall}
+}
+
+// CaptureStart implements Ethereum's vm.Tracer interface. It records the
+// transaction's top-level call.
+func (ct *CallTracer) CaptureStart(
+	from, to ethcmn.Address, create bool, input []byte, gas uint64, value *big.Int,
+) error {
+
+	ct.root = &CallFrame{
+		Type:  ethvm.CALL.String(),
+		From:  from,
+		To:    to,
+		Value: (*hexutil.Big)(new(big.Int).Set(value)),
+		Gas:   hexutil.Uint64(gas),
+		Input: ethcmn.CopyBytes(input),
+	}
+
+	if create {
+		ct.root.Type = ethvm.CREATE.String()
+	}
+
+	ct.frames = nil
+	ct.descended = false
+
+	return nil
+}
+
+// CaptureState implements Ethereum's vm.Tracer interface. It is invoked prior
+// to the execution of every opcode. Any call that has returned is first
+// resolved through the result the call left on the stack, after which a new
+// call is opened for any message call or contract creation.
+func (ct *CallTracer) CaptureState(
+	env *ethvm.EVM, _ uint64, op ethvm.OpCode, gas, cost uint64,
+	memory *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, depth int, err error,
+) error {
+	if ct.root == nil || ct.onlyTopCall {
+		return nil
+	}
+
+	if ct.descended {
+		if call := ct.frames[len(ct.frames)-1]; depth >= call.depth {
+			call.frame.Gas = hexutil.Uint64(gas)
+		}
+
+		ct.descended = false
+	}
+
+	ct.resolveCalls(env, depth, gas, memory, stack)
+
+	if err != nil {
+		ct.fail(depth, err)
+		return nil
+	}
+
+	call := &openCall{depth: depth + 1, gasIn: gas, gasCost: cost}
+
+	switch op {
+	case ethvm.CREATE:
+		caller := contract.Address()
+		offset, size := stack.Back(1).Int64(), stack.Back(2).Int64()
+
+		call.frame = &CallFrame{
+			Type:  op.String(),
+			From:  caller,
+			To:    ethcrypto.CreateAddress(caller, env.StateDB.GetNonce(caller)),
+			Value: (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+			Input: memory.Get(offset, size),
+		}
+
+	case ethvm.CALL, ethvm.CALLCODE:
+		call.frame = &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    ethcmn.BigToAddress(stack.Back(1)),
+			Value: (*hexutil.Big)(new(big.Int).Set(stack.Back(2))),
+			Input: memory.Get(stack.Back(3).Int64(), stack.Back(4).Int64()),
+		}
+
+		call.outOffset, call.outSize = stack.Back(5).Int64(), stack.Back(6).Int64()
+
+	case ethvm.DELEGATECALL, ethvm.STATICCALL:
+		call.frame = &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    ethcmn.BigToAddress(stack.Back(1)),
+			Input: memory.Get(stack.Back(2).Int64(), stack.Back(3).Int64()),
+		}
+
+		call.outOffset, call.outSize = stack.Back(4).Int64(), stack.Back(5).Int64()
+
+	case ethvm.SELFDESTRUCT:
+		ct.current().Calls = append(ct.current().Calls, &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    ethcmn.BigToAddress(stack.Back(0)),
+			Value: (*hexutil.Big)(new(big.Int).Set(env.StateDB.GetBalance(contract.Address()))),
+		})
+
+		return nil
+
+	default:
+		return nil
+	}
+
+	ct.frames = append(ct.frames, call)
+	ct.descended = true
+
+	return nil
+}
+
+// CaptureFault implements Ethereum's vm.Tracer interface. It records the error
+// on the call that failed, including a call that reverted.
+func (ct *CallTracer) CaptureFault(
+	_ *ethvm.EVM, _ uint64, _ ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, _ *ethvm.Stack, _ *ethvm.Contract, depth int, err error,
+) error {
+	if ct.root != nil && !ct.onlyTopCall {
+		ct.fail(depth, err)
+	}
+
+	return nil
+}
+
+// CaptureEnd implements Ethereum's vm.Tracer interface. It records the result
+// of the top-level call. Any call still open is considered successful.
+func (ct *CallTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	if ct.root == nil {
+		return nil
+	}
+
+	for len(ct.frames) > 0 {
+		ct.closeCall()
+	}
+
+	ct.root.GasUsed = hexutil.Uint64(gasUsed)
+	ct.root.Output = ethcmn.CopyBytes(output)
+
+	if err != nil && ct.root.Error == "" {
+		ct.root.Error = err.Error()
+	}
+
+	return nil
+}
+
+// Root returns the top-level call of the last traced transaction or nil if no
+// transaction has been traced.
+func (ct *CallTracer) Root() *CallFrame {
+	return ct.root
+}
+
+// current returns the frame of the call currently executing.
+func (ct *CallTracer) current() *CallFrame {
+	if len(ct.frames) == 0 {
+		return ct.root
+	}
+
+	return ct.frames[len(ct.frames)-1].frame
+}
+
+// fail records the given error on the call executing at the given depth
+// unless an error was already recorded.
+func (ct *CallTracer) fail(depth int, err error) {
+	frame := ct.root
+	if n := len(ct.frames); n > 0 {
+		if ct.frames[n-1].depth != depth {
+			return
+		}
+
+		frame = ct.frames[n-1].frame
+	}
+
+	if frame.Error == "" {
+		frame.Error = err.Error()
+	}
+}
+
+// resolveCalls closes all calls that have returned given the current
+// execution depth. When execution resumes at the depth a call was issued at,
+// the call's result is on top of the stack and its output, if any, is in
+// memory.
+func (ct *CallTracer) resolveCalls(env *ethvm.EVM, depth int, gas uint64, memory *ethvm.Memory, stack *ethvm.Stack) {
+	for len(ct.frames) > 0 {
+		call := ct.frames[len(ct.frames)-1]
+
+		if call.depth <= depth {
+			return
+		}
+
+		if call.depth > depth+1 || len(stack.Data()) == 0 {
+			// the caller's result was never observed
+			ct.closeCall()
+			continue
+		}
+
+		frame := call.frame
+		if frame.Gas != 0 {
+			frame.GasUsed = hexutil.Uint64(call.gasIn - call.gasCost + uint64(frame.Gas) - gas)
+		}
+
+		if ret := stack.Back(0); ret.Sign() != 0 {
+			if frame.Type == ethvm.CREATE.String() {
+				frame.Output = env.StateDB.GetCode(ethcmn.BigToAddress(ret))
+			} else {
+				frame.Output = memory.Get(call.outOffset, call.outSize)
+			}
+		} else if frame.Error == "" {
+			frame.Error = "internal failure"
+		}
+
+		ct.closeCall()
+	}
+}
+
+// closeCall closes the latest open call, adding it to the calls of its
+// caller.
+func (ct *CallTracer) closeCall() {
+	n := len(ct.frames)
+	call := ct.frames[n-1]
+	ct.frames = ct.frames[:n-1]
+
+	parent := ct.current()
+	parent.Calls = append(parent.Calls, call.frame)
+}
+
+// callTxTracer adapts a CallTracer to a TxTracer.
+type callTxTracer struct {
+	*CallTracer
+}
+
+// newCallTxTracer implements TracerConstructor. The config, if given, holds
+// a CallTracerConfig.
+func newCallTxTracer(_ TracerContext, config json.RawMessage) (TxTracer, error) {
+	var callConfig CallTracerConfig
+	if len(config) != 0 {
+		if err := json.Unmarshal(config, &callConfig); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %v", TracerCall, err)
+		}
+	}
+
+	return callTxTracer{NewCallTracer(callConfig)}, nil
+}
+
+// Result implements the TxTracer interface. The gas of the top-level call is
+// that of the transaction, intrinsic gas included.
+func (t callTxTracer) Result(exec TxExecution) (interface{}, error) {
+	root := t.Root()
+	if root == nil {
+		return nil, errors.New("transaction was not executed")
+	}
+
+	root.Gas = hexutil.Uint64(exec.Gas)
+	root.GasUsed = hexutil.Uint64(exec.GasUsed)
+
+	return root, nil
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// callReturningCode returns EVM byte code that performs a CALL without value
+// to the given recipient, copying 32 bytes of its output to memory, and then
+// stops.
+func callReturningCode(to ethcmn.Address) []byte {
+	code := []byte{
+		byte(ethvm.PUSH1), 0x20, // retSize
+		byte(ethvm.PUSH1), 0x00, // retOffset
+		byte(ethvm.PUSH1), 0x00, // argsSize
+		byte(ethvm.PUSH1), 0x00, // argsOffset
+		byte(ethvm.PUSH1), 0x00, // value
+		byte(ethvm.PUSH20),
+	}
+
+	code = append(code, to.Bytes()...)
+	return append(code, byte(ethvm.GAS), byte(ethvm.CALL), byte(ethvm.STOP))
+}
+
+// returnCode returns EVM byte code that returns the given value as a 32 byte
+// word.
+func returnCode(value byte) []byte {
+	return []byte{
+		byte(ethvm.PUSH1), value, byte(ethvm.PUSH1), 0x00, byte(ethvm.MSTORE),
+		byte(ethvm.PUSH1), 0x20, byte(ethvm.PUSH1), 0x00, byte(ethvm.RETURN),
+	}
+}
+
+// revertCode returns EVM byte code that reverts without output.
+func revertCode() []byte {
+	return []byte{byte(ethvm.PUSH1), 0x00, byte(ethvm.PUSH1), 0x00, byte(ethvm.REVERT)}
+}
+
+func runCallTracer(t *testing.T, config CallTracerConfig, contractCode, recipientCode []byte) *CallFrame {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.SetCode(testContract, contractCode)
+	stateDB.SetCode(testRecipient, recipientCode)
+
+	tracer := NewCallTracer(config)
+	ctx := ethvm.Context{
+		CanTransfer: ethcore.CanTransfer,
+		Transfer:    ethcore.Transfer,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    1000000,
+		GasPrice:    big.NewInt(1),
+	}
+
+	evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{Debug: true, Tracer: tracer})
+
+	_, _, err = evm.Call(ethvm.AccountRef(testCaller), testContract, []byte{1, 2}, 1000000, new(big.Int))
+	require.Nil(t, err)
+
+	return tracer.Root()
+}
+
+func TestCallTracerInterface(t *testing.T) {
+	require.Implements(t, (*ethvm.Tracer)(nil), new(CallTracer))
+}
+
+func TestCallTracer(t *testing.T) {
+	root := runCallTracer(t, CallTracerConfig{}, callReturningCode(testRecipient), returnCode(42))
+
+	require.Equal(t, "CALL", root.Type)
+	require.Equal(t, testCaller, root.From)
+	require.Equal(t, testContract, root.To)
+	require.Equal(t, []byte{1, 2}, []byte(root.Input))
+	require.Empty(t, root.Error)
+	require.NotZero(t, root.GasUsed)
+	require.Len(t, root.Calls, 1)
+
+	call := root.Calls[0]
+	require.Equal(t, "CALL", call.Type)
+	require.Equal(t, testContract, call.From)
+	require.Equal(t, testRecipient, call.To)
+	require.Equal(t, ethcmn.BigToHash(big.NewInt(42)).Bytes(), []byte(call.Output))
+	require.Empty(t, call.Error)
+	require.Empty(t, call.Calls)
+	require.NotZero(t, call.Gas)
+	require.NotZero(t, call.GasUsed)
+	require.True(t, call.GasUsed < call.Gas)
+	require.True(t, call.GasUsed < root.GasUsed)
+
+	// the trace is serialized in the format of geth's callTracer
+	bz, err := json.Marshal(root)
+	require.Nil(t, err)
+	require.Contains(t, string(bz), `"output":"0x000000000000000000000000000000000000000000000000000000000000002a"`)
+}
+
+func TestCallTracerRevertedCall(t *testing.T) {
+	root := runCallTracer(t, CallTracerConfig{}, callReturningCode(testRecipient), revertCode())
+
+	require.Empty(t, root.Error)
+	require.Len(t, root.Calls, 1)
+	require.NotEmpty(t, root.Calls[0].Error)
+	require.Empty(t, root.Calls[0].Output)
+}
+
+func TestCallTracerCallWithoutCode(t *testing.T) {
+	root := runCallTracer(t, CallTracerConfig{}, callReturningCode(testRecipient), nil)
+
+	require.Len(t, root.Calls, 1)
+	require.Empty(t, root.Calls[0].Error)
+	require.Zero(t, root.Calls[0].Gas)
+}
+
+func TestCallTracerOnlyTopCall(t *testing.T) {
+	root := runCallTracer(t, CallTracerConfig{OnlyTopCall: true}, callReturningCode(testRecipient), returnCode(42))
+
+	require.Equal(t, testContract, root.To)
+	require.Empty(t, root.Calls)
+}
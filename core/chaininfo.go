@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+const (
+	// ChainInfoPrecompileGas is the fixed gas cost of a call to the chain
+	// info precompile.
+	ChainInfoPrecompileGas = 2000
+
+	// ChainInfoPrecompileABI is the ABI of the chain info precompile.
+	ChainInfoPrecompileABI = `[
+		{"name": "proposer", "type": "function", "constant": true,
+		 "inputs": [], "outputs": [{"name": "", "type": "address"}]},
+		{"name": "chainId", "type": "function", "constant": true,
+		 "inputs": [], "outputs": [{"name": "", "type": "string"}]},
+		{"name": "bondedValidators", "type": "function", "constant": true,
+		 "inputs": [], "outputs": [{"name": "", "type": "uint64"}]}
+	]`
+)
+
+var (
+	// ChainInfoPrecompileAddress is the address of the read-only precompile
+	// serving the Cosmos context of the current block to contracts.
+	ChainInfoPrecompileAddress = ethcmn.HexToAddress("0x0000000000000000000000000000000000000102")
+
+	chainInfoABI abi.ABI
+)
+
+func init() {
+	var err error
+
+	if chainInfoABI, err = abi.JSON(strings.NewReader(ChainInfoPrecompileABI)); err != nil {
+		panic(fmt.Sprintf("invalid chain info precompile ABI: %v", err))
+	}
+}
+
+// ChainInfoPrecompile implements a read-only EVM precompiled contract serving
+// the Cosmos context of the block a transaction is executed in, which the EVM
+// does not otherwise expose:
+//
+//	proposer()         the address of the block's proposer, as given by
+//	                   Tendermint, or the zero address if not given
+//	chainId()          the Tendermint chain ID, e.g. "ethermint-1"
+//	bondedValidators() the number of bonded validators
+//
+// It never modifies state and may therefore be invoked through STATICCALL.
+type ChainInfoPrecompile struct {
+	ctx    sdk.Context
+	valSet sdk.ValidatorSet
+}
+
+var _ ethvm.PrecompiledContract = ChainInfoPrecompile{}
+
+// NewChainInfoPrecompile returns a new ChainInfoPrecompile reading from the
+// given context and validator set.
+func NewChainInfoPrecompile(ctx sdk.Context, valSet sdk.ValidatorSet) ChainInfoPrecompile {
+	return ChainInfoPrecompile{ctx: ctx, valSet: valSet}
+}
+
+// RequiredGas implements the ethvm.PrecompiledContract interface.
+func (p ChainInfoPrecompile) RequiredGas(_ []byte) uint64 {
+	return ChainInfoPrecompileGas
+}
+
+// Run implements the ethvm.PrecompiledContract interface. It returns the ABI
+// encoded value of the method given as input.
+func (p ChainInfoPrecompile) Run(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("invalid input length %d", len(input))
+	}
+
+	method, err := chainInfoABI.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "proposer":
+		return method.Outputs.Pack(ethcmn.BytesToAddress(p.ctx.BlockHeader().Proposer.Address))
+
+	case "chainId":
+		return method.Outputs.Pack(p.ctx.ChainID())
+
+	default:
+		var count uint64
+
+		p.valSet.IterateValidatorsBonded(p.ctx, func(_ int64, _ sdk.Validator) bool {
+			count++
+			return false
+		})
+
+		return method.Outputs.Pack(count)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// testValidatorSet implements the part of an sdk.ValidatorSet the chain info
+// precompile uses, holding a given number of bonded validators.
+type testValidatorSet struct {
+	sdk.ValidatorSet
+
+	bonded int
+}
+
+func (vs testValidatorSet) IterateValidatorsBonded(_ sdk.Context, fn func(int64, sdk.Validator) bool) {
+	for i := 0; i < vs.bonded; i++ {
+		if fn(int64(i), nil) {
+			return
+		}
+	}
+}
+
+func TestChainInfoPrecompile(t *testing.T) {
+	proposer := ethcmn.BytesToAddress([]byte("proposer"))
+
+	testCases := []struct {
+		header   abci.Header
+		bonded   int
+		method   string
+		expected interface{}
+	}{
+		{abci.Header{Proposer: abci.Validator{Address: proposer.Bytes()}}, 0, "proposer", proposer},
+		// the proposer is not given by every Tendermint version
+		{abci.Header{}, 0, "proposer", ethcmn.Address{}},
+		{abci.Header{ChainID: "ethermint-1"}, 0, "chainId", "ethermint-1"},
+		{abci.Header{}, 0, "bondedValidators", uint64(0)},
+		{abci.Header{}, 4, "bondedValidators", uint64(4)},
+	}
+
+	for i, tc := range testCases {
+		ctx := sdk.NewContext(nil, tc.header, false, log.NewNopLogger())
+		p := NewChainInfoPrecompile(ctx, testValidatorSet{bonded: tc.bonded})
+
+		input, err := chainInfoABI.Pack(tc.method)
+		require.Nil(t, err)
+		require.Equal(t, uint64(ChainInfoPrecompileGas), p.RequiredGas(input))
+
+		ret, err := p.Run(input)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		values, err := chainInfoABI.Methods[tc.method].Outputs.UnpackValues(ret)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, []interface{}{tc.expected}, values, fmt.Sprintf("unexpected value: test case #%d", i))
+	}
+
+	// malformed input is rejected
+	p := NewChainInfoPrecompile(sdk.NewContext(nil, abci.Header{}, false, log.NewNopLogger()), testValidatorSet{})
+
+	_, err := p.Run([]byte{0x01, 0x02})
+	require.NotNil(t, err)
+
+	_, err = p.Run([]byte{0x01, 0x02, 0x03, 0x04})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,19 @@
+package core
+
+import (
+	"math/big"
+
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// NewChainConfig returns the Ethereum chain configuration transactions are
+// executed with on an Ethermint chain with the given Ethereum chain ID. Every
+// protocol change supported by the EVM is active from genesis. In particular,
+// EIP-170 limits the size of deployed code to params.MaxCodeSize (24576)
+// bytes, a limit hard-coded in the EVM's creation path.
+func NewChainConfig(chainID *big.Int) *ethparams.ChainConfig {
+	config := *ethparams.AllEthashProtocolChanges
+	config.ChainID = new(big.Int).Set(chainID)
+
+	return &config
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+type (
+	// CallMsg defines a message call executed without being signed nor
+	// included in a block, e.g. to estimate its gas. A zero gas limit
+	// defaults to the block's gas limit and a nil gas price or value to zero.
+	CallMsg struct {
+		From     ethcmn.Address
+		To       *ethcmn.Address
+		Gas      uint64
+		GasPrice *big.Int
+		Value    *big.Int
+		Data     []byte
+	}
+
+	// CallConfig defines the block a CallMsg is executed within.
+	CallConfig struct {
+		ChainConfig     *ethparams.ChainConfig
+		Header          abci.Header
+		Coinbase        ethcmn.Address
+		GasLimit        uint64
+		GetHash         ethvm.GetHashFunc
+		DisabledOpcodes []ethvm.OpCode
+
+		// Precompiles are the stateful precompiled contracts bound to the
+		// execution (see WithPrecompiles).
+		Precompiles map[ethcmn.Address]ethvm.PrecompiledContract
+	}
+
+	// CallResult defines the result of executing a CallMsg.
+	CallResult struct {
+		ReturnValue []byte
+		GasUsed     uint64
+		Failed      bool
+	}
+)
+
+// ApplyCall executes a CallMsg on the given state, reverting all of its
+// changes once executed. The sender's nonce is not checked. An error is
+// returned if the message cannot be executed at all, e.g. as its sender
+// cannot pay for its gas, in which case no result is returned.
+func ApplyCall(stateDB *ethstate.StateDB, config CallConfig, call CallMsg) (*CallResult, error) {
+	defer stateDB.RevertToSnapshot(stateDB.Snapshot())
+
+	gas := call.Gas
+	if gas == 0 {
+		gas = config.GasLimit
+	}
+
+	gasPrice, value := call.GasPrice, call.Value
+	if gasPrice == nil {
+		gasPrice = new(big.Int)
+	}
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	msg := ethtypes.NewMessage(
+		call.From, call.To, stateDB.GetNonce(call.From), value, gas, gasPrice, call.Data, false,
+	)
+
+	vmCtx := NewVMContext(config.Header, call.From, config.Coinbase, gasPrice, config.GasLimit, config.GetHash)
+	vmConfig := NewVMConfig(config.ChainConfig, vmCtx.BlockNumber, config.DisabledOpcodes, ethvm.Config{})
+	evm := ethvm.NewEVM(vmCtx, stateDB, config.ChainConfig, vmConfig)
+
+	var (
+		ret     []byte
+		gasUsed uint64
+		failed  bool
+		err     error
+	)
+
+	WithPrecompiles(config.Precompiles, func() {
+		ret, gasUsed, failed, err = ethcore.ApplyMessage(evm, msg, new(ethcore.GasPool).AddGas(gas))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CallResult{ReturnValue: ret, GasUsed: gasUsed, Failed: failed}, nil
+}
+
+// EstimateGas returns the lowest gas limit a CallMsg executes successfully
+// with on the given state, as geth's eth_estimateGas does. The message's gas
+// limit, or the block's if zero, caps the estimate. The cap is further
+// lowered to the gas the sender can afford at the message's gas price.
+//
+// The estimate is the result of a binary search over actual executions
+// rather than the gas used by a single execution, as the gas a message needs
+// may exceed the gas it uses: a call forwards at most 63/64 of the remaining
+// gas to its callee, gas refunds are only credited once execution completes
+// and contracts may branch on the gas left. The search is narrowed by first
+// executing the message with the cap, as it needs at least the gas it then
+// uses, and with that gas increased to account for the 63/64 rule, which
+// usually succeeds.
+//
+// The EVM has no access lists, as it predates EIP-2929, so the gas needed
+// does not depend on accounts and storage slots being warmed beforehand.
+//
+// An error is returned if the message fails with the cap, including the
+// revert reason if it reverted.
+func EstimateGas(stateDB *ethstate.StateDB, config CallConfig, call CallMsg) (uint64, error) {
+	hi := call.Gas
+	if hi == 0 {
+		hi = config.GasLimit
+	}
+
+	if call.GasPrice != nil && call.GasPrice.Sign() == 1 {
+		available := stateDB.GetBalance(call.From)
+		if call.Value != nil {
+			available = new(big.Int).Sub(available, call.Value)
+		}
+
+		if available.Sign() == -1 {
+			return 0, fmt.Errorf("insufficient funds for transfer")
+		}
+
+		allowance := new(big.Int).Quo(available, call.GasPrice)
+		if allowance.IsUint64() && allowance.Uint64() < hi {
+			hi = allowance.Uint64()
+		}
+	}
+
+	// execute returns the result of executing the message with the given
+	// gas limit, which is nil if the gas does not cover the intrinsic gas
+	execute := func(gas uint64) (*CallResult, error) {
+		call.Gas = gas
+
+		res, err := ApplyCall(stateDB, config, call)
+		if err == ethvm.ErrOutOfGas {
+			return nil, nil
+		}
+
+		return res, err
+	}
+
+	res, err := execute(hi)
+	if err != nil {
+		return 0, err
+	}
+
+	if res == nil || res.Failed {
+		if res != nil && len(res.ReturnValue) > 0 {
+			return 0, errors.New(RevertMessage(res.ReturnValue))
+		}
+
+		return 0, fmt.Errorf("gas required exceeds allowance (%d) or always failing transaction", hi)
+	}
+
+	lo := ethparams.TxGas - 1
+	if res.GasUsed > lo+1 {
+		lo = res.GasUsed - 1
+	}
+
+	optimistic := (res.GasUsed + ethparams.CallStipend) * 64 / 63
+	if optimistic > lo && optimistic < hi {
+		res, err := execute(optimistic)
+		if err != nil {
+			return 0, err
+		}
+
+		if res != nil && !res.Failed {
+			hi = optimistic
+		} else {
+			lo = optimistic
+		}
+	}
+
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+
+		res, err := execute(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if res != nil && !res.Failed {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, nil
+}
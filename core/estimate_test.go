@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+var testCallConfig = CallConfig{
+	ChainConfig: ethparams.AllEthashProtocolChanges,
+	Header:      abci.Header{Height: 10, Time: 1000},
+	GasLimit:    10000000,
+}
+
+// setSlotsCode returns EVM byte code that stores 1 in the given storage
+// slots.
+func setSlotsCode(slots ...byte) []byte {
+	var code []byte
+	for _, slot := range slots {
+		code = append(code, storeCode(slot, 0x01)...)
+	}
+
+	return append(code, byte(ethvm.STOP))
+}
+
+// forwardCode is EVM byte code that calls the given address with all the gas
+// left, which is capped to 63/64 of it, and reverts if the call fails.
+func forwardCode(to ethcmn.Address) []byte {
+	code := []byte{
+		byte(ethvm.PUSH1), 0x00, // retSize
+		byte(ethvm.PUSH1), 0x00, // retOffset
+		byte(ethvm.PUSH1), 0x00, // argsSize
+		byte(ethvm.PUSH1), 0x00, // argsOffset
+		byte(ethvm.PUSH1), 0x00, // value
+		byte(ethvm.PUSH20),
+	}
+
+	code = append(code, to.Bytes()...)
+
+	return append(code,
+		byte(ethvm.GAS),
+		byte(ethvm.CALL),
+		byte(ethvm.PUSH1), 0x28,
+		byte(ethvm.JUMPI),
+		byte(ethvm.PUSH1), 0x00,
+		byte(ethvm.DUP1),
+		byte(ethvm.REVERT),
+		byte(ethvm.JUMPDEST),
+		byte(ethvm.STOP),
+	)
+}
+
+// revertWithCode is EVM byte code that reverts with the given payload.
+func revertWithCode(payload []byte) []byte {
+	code := []byte{
+		byte(ethvm.PUSH1), byte(len(payload)),
+		byte(ethvm.PUSH1), 0x0c,
+		byte(ethvm.PUSH1), 0x00,
+		byte(ethvm.CODECOPY),
+		byte(ethvm.PUSH1), byte(len(payload)),
+		byte(ethvm.PUSH1), 0x00,
+		byte(ethvm.REVERT),
+	}
+
+	return append(code, payload...)
+}
+
+func newEstimateTestState(t *testing.T) *ethstate.StateDB {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.AddBalance(testCaller, big.NewInt(1000000))
+	stateDB.SetCode(testRecipient, setSlotsCode(0x00, 0x01, 0x02))
+	stateDB.SetState(testRecipient, ethcmn.Hash{}, ethcmn.BytesToHash([]byte{0x01}))
+
+	return stateDB
+}
+
+func TestEstimateGas(t *testing.T) {
+	testCases := []struct {
+		name         string
+		code         []byte
+		data         []byte
+		expectedUsed bool
+	}{
+		// the gas used is exact for plain transfers
+		{"transfer", nil, nil, true},
+		{"transfer with data", nil, []byte{0x01, 0x00, 0x02}, true},
+		{"storage writes", setSlotsCode(0x01, 0x02), nil, true},
+		// refunds are credited after execution, so more gas is needed
+		{"storage clear", append(storeCode(0x00, 0x00), byte(ethvm.STOP)), nil, false},
+		// a call only forwards 63/64 of the gas left to its callee
+		{"forwarded call", forwardCode(testRecipient), nil, false},
+	}
+
+	for i, tc := range testCases {
+		stateDB := newEstimateTestState(t)
+		stateDB.SetCode(testContract, tc.code)
+		stateDB.SetState(testContract, ethcmn.Hash{}, ethcmn.BytesToHash([]byte{0x01}))
+
+		call := CallMsg{From: testCaller, To: &testContract, Data: tc.data}
+
+		estimate, err := EstimateGas(stateDB, testCallConfig, call)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d (%s)", i, tc.name))
+
+		// the estimate is the lowest gas limit the call succeeds with
+		call.Gas = estimate
+		res, err := ApplyCall(stateDB, testCallConfig, call)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d (%s)", i, tc.name))
+		require.False(t, res.Failed, fmt.Sprintf("expected success: test case #%d (%s)", i, tc.name))
+		require.Equal(t, tc.expectedUsed, res.GasUsed == estimate, fmt.Sprintf("unexpected gas used: test case #%d (%s)", i, tc.name))
+
+		call.Gas = estimate - 1
+		res, err = ApplyCall(stateDB, testCallConfig, call)
+		require.True(t, err != nil || res.Failed, fmt.Sprintf("expected failure: test case #%d (%s)", i, tc.name))
+
+		// the state is never modified
+		require.Equal(t, uint64(0), stateDB.GetNonce(testCaller), fmt.Sprintf("unexpected nonce: test case #%d (%s)", i, tc.name))
+	}
+}
+
+func TestEstimateGasTransfer(t *testing.T) {
+	stateDB := newEstimateTestState(t)
+
+	estimate, err := EstimateGas(stateDB, testCallConfig, CallMsg{From: testCaller, To: &testContract})
+	require.Nil(t, err)
+	require.Equal(t, ethparams.TxGas, estimate)
+}
+
+func TestEstimateGasErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		call          CallMsg
+		expectedError string
+	}{
+		{
+			"revert reason",
+			CallMsg{From: testCaller, To: &testContract},
+			"execution reverted: denied",
+		},
+		{
+			"gas cap",
+			CallMsg{From: testCaller, To: &testRecipient, Gas: 30000},
+			"gas required exceeds allowance (30000) or always failing transaction",
+		},
+		{
+			"gas price allowance",
+			CallMsg{From: testCaller, To: &testRecipient, GasPrice: big.NewInt(20)},
+			"gas required exceeds allowance (50000) or always failing transaction",
+		},
+		{
+			"insufficient funds",
+			CallMsg{From: testCaller, To: &testRecipient, GasPrice: big.NewInt(1), Value: big.NewInt(2000000)},
+			"insufficient funds for transfer",
+		},
+		{
+			"insufficient balance",
+			CallMsg{From: testCaller, To: &testRecipient, Value: big.NewInt(2000000)},
+			ethvm.ErrInsufficientBalance.Error(),
+		},
+	}
+
+	for i, tc := range testCases {
+		stateDB := newEstimateTestState(t)
+		stateDB.SetCode(testContract, revertWithCode(encodeRevertError("denied")))
+
+		_, err := EstimateGas(stateDB, testCallConfig, tc.call)
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d (%s)", i, tc.name))
+		require.Equal(t, tc.expectedError, err.Error(), fmt.Sprintf("unexpected error: test case #%d (%s)", i, tc.name))
+	}
+}
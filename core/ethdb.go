@@ -2,14 +2,14 @@ package core
 
 import (
 	ethdb "github.com/ethereum/go-ethereum/ethdb"
-
-	dbm "github.com/tendermint/tendermint/libs/db"
 )
 
 // EthereumDB implements Ethereum's ethdb.Database and ethdb.Batch interfaces.
-// It will be used to facilitate persistence of codeHash => code mappings.
+// It will be used to facilitate persistence of codeHash => code mappings,
+// either in a database of their own or in a store of a multi-store, as any
+// dbm.DB or sdk.KVStore may back it.
 type EthereumDB struct {
-	CodeDB dbm.DB
+	CodeDB KVReadWriter
 }
 
 // Put implements Ethereum's ethdb.Putter interface. It wraps the database
@@ -28,7 +28,7 @@ func (edb *EthereumDB) Get(key []byte) ([]byte, error) {
 // Has implements Ethereum's ethdb.Database interface. It returns a boolean
 // determining if the underlying database has the given key or not.
 func (edb *EthereumDB) Has(key []byte) (bool, error) {
-	return edb.CodeDB.Has(key), nil
+	return edb.CodeDB.Get(key) != nil, nil
 }
 
 // Delete implements Ethereum's ethdb.Database interface. It removes a given
@@ -39,9 +39,11 @@ func (edb *EthereumDB) Delete(key []byte) error {
 }
 
 // Close implements Ethereum's ethdb.Database interface. It closes the
-// underlying database.
+// underlying database, if it may be closed.
 func (edb *EthereumDB) Close() {
-	edb.CodeDB.Close()
+	if closer, ok := edb.CodeDB.(interface{ Close() }); ok {
+		closer.Close()
+	}
 }
 
 // NewBatch implements Ethereum's ethdb.Database interface. It returns a new
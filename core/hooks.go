@@ -0,0 +1,44 @@
+package core
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+type (
+	// TxContext is the per-transaction execution context that is handed to
+	// every registered TxHook before the EVM executes a transaction. Hooks may
+	// observe the context as well as modify the block context and state the
+	// EVM is about to run with (e.g. to inject block randomness or oracle
+	// prices).
+	TxContext struct {
+		Ctx       sdk.Context
+		Tx        *types.Transaction
+		From      ethcmn.Address
+		VMContext *ethvm.Context
+		StateDB   ethvm.StateDB
+	}
+
+	// TxHook defines a function that is executed for every Ethereum
+	// transaction prior to EVM execution. A non-nil error aborts the
+	// transaction before any execution takes place.
+	TxHook func(txCtx *TxContext) error
+
+	// TxHooks defines an ordered set of TxHook functions.
+	TxHooks []TxHook
+)
+
+// Run executes each TxHook in the order it was registered. Execution stops at
+// the first hook that returns an error and the error is returned.
+func (hooks TxHooks) Run(txCtx *TxContext) error {
+	for _, hook := range hooks {
+		if err := hook(txCtx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
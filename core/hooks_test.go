@@ -0,0 +1,54 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxHooksRun(t *testing.T) {
+	var order []int
+
+	newHook := func(id int, err error) TxHook {
+		return func(txCtx *TxContext) error {
+			order = append(order, id)
+			txCtx.VMContext.Difficulty = big.NewInt(int64(id))
+			return err
+		}
+	}
+
+	testCases := []struct {
+		hooks         TxHooks
+		expectedOrder []int
+		expectErr     bool
+	}{
+		{nil, nil, false},
+		{TxHooks{newHook(1, nil), newHook(2, nil)}, []int{1, 2}, false},
+		{TxHooks{newHook(1, errors.New("abort")), newHook(2, nil)}, []int{1}, true},
+	}
+
+	for i, tc := range testCases {
+		order = nil
+		txCtx := &TxContext{From: ethcmn.Address{}, VMContext: &ethvm.Context{}}
+
+		err := tc.hooks.Run(txCtx)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+
+		require.Equal(t, tc.expectedOrder, order, fmt.Sprintf("unexpected hook order: test case #%d", i))
+
+		if len(tc.expectedOrder) > 0 {
+			last := tc.expectedOrder[len(tc.expectedOrder)-1]
+			require.Equal(t, big.NewInt(int64(last)), txCtx.VMContext.Difficulty, fmt.Sprintf("test case #%d", i))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// OpcodeDisabledError is returned when the EVM executes an opcode disabled on
+// the chain.
+type OpcodeDisabledError struct {
+	Op ethvm.OpCode
+}
+
+// Error implements the error interface.
+func (e OpcodeDisabledError) Error() string {
+	return fmt.Sprintf("opcode %s is disabled", e.Op)
+}
+
+// ParseOpcodes parses a list of opcodes, each given either by name (e.g.
+// "SELFDESTRUCT") or as a hex encoded byte (e.g. "0xf5"), so that opcodes
+// unknown to the EVM's fork may be disabled too. STOP cannot be disabled.
+func ParseOpcodes(names []string) ([]ethvm.OpCode, error) {
+	ops := make([]ethvm.OpCode, 0, len(names))
+	seen := make(map[ethvm.OpCode]bool, len(names))
+
+	for _, name := range names {
+		op := ethvm.StringToOp(name)
+
+		if strings.HasPrefix(name, "0x") {
+			b, err := strconv.ParseUint(name[2:], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid opcode %s: %v", name, err)
+			}
+
+			op = ethvm.OpCode(b)
+		}
+
+		// unknown names map to STOP, which is never a valid opcode to disable
+		// as the EVM treats a jump table with an invalid STOP as unset
+		if op == ethvm.STOP {
+			return nil, fmt.Errorf("invalid opcode %s", name)
+		}
+
+		if seen[op] {
+			return nil, fmt.Errorf("duplicate opcode %s", name)
+		}
+
+		seen[op] = true
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// NewVMConfig returns the given EVM configuration with the given opcodes
+// disabled in the instruction set of the given chain configuration's fork at
+// the given block number.
+//
+// A disabled opcode behaves as an invalid opcode: execution of the current
+// call frame halts, consuming all of its gas, and the error is returned to
+// the caller like any other exceptional halt. The configuration is derived
+// from consensus parameters alone, so every validator enforces the same set.
+func NewVMConfig(
+	chainConfig *ethparams.ChainConfig, blockNumber *big.Int, disabled []ethvm.OpCode, cfg ethvm.Config,
+) ethvm.Config {
+
+	if len(disabled) == 0 {
+		return cfg
+	}
+
+	switch {
+	case chainConfig.IsConstantinople(blockNumber):
+		cfg.JumpTable = ethvm.NewConstantinopleInstructionSet()
+	case chainConfig.IsByzantium(blockNumber):
+		cfg.JumpTable = ethvm.NewByzantiumInstructionSet()
+	case chainConfig.IsHomestead(blockNumber):
+		cfg.JumpTable = ethvm.NewHomesteadInstructionSet()
+	default:
+		cfg.JumpTable = ethvm.NewFrontierInstructionSet()
+	}
+
+	// the zero value of an instruction is invalid
+	var invalid ethvm.Config
+	for _, op := range disabled {
+		cfg.JumpTable[op] = invalid.JumpTable[op]
+	}
+
+	return cfg
+}
+
+// OpcodeError returns an OpcodeDisabledError if the given error, returned by
+// the EVM, results from executing one of the given disabled opcodes. Any
+// other error is returned as is.
+func OpcodeError(err error, disabled []ethvm.OpCode) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, op := range disabled {
+		if err.Error() == fmt.Sprintf("invalid opcode 0x%x", int(op)) {
+			return OpcodeDisabledError{Op: op}
+		}
+	}
+
+	return err
+}
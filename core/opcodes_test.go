@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// selfDestructCode is EVM byte code that self-destructs, sending its balance
+// to the zero address.
+var selfDestructCode = []byte{
+	byte(ethvm.PUSH1), 0x00,
+	byte(ethvm.SELFDESTRUCT),
+}
+
+func TestParseOpcodes(t *testing.T) {
+	testCases := []struct {
+		names     []string
+		expected  []ethvm.OpCode
+		expectErr bool
+	}{
+		{nil, []ethvm.OpCode{}, false},
+		{[]string{"SELFDESTRUCT", "CALLCODE"}, []ethvm.OpCode{ethvm.SELFDESTRUCT, ethvm.CALLCODE}, false},
+		{[]string{"0xf5"}, []ethvm.OpCode{ethvm.OpCode(0xf5)}, false},
+		{[]string{"0xff", "SELFDESTRUCT"}, nil, true},
+		{[]string{"STOP"}, nil, true},
+		{[]string{"0x00"}, nil, true},
+		{[]string{"UNKNOWN"}, nil, true},
+		{[]string{"0x100"}, nil, true},
+		{[]string{"0x"}, nil, true},
+	}
+
+	for i, tc := range testCases {
+		ops, err := ParseOpcodes(tc.names)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			require.Equal(t, tc.expected, ops, fmt.Sprintf("unexpected opcodes: test case #%d", i))
+		}
+	}
+}
+
+func TestNewVMConfig(t *testing.T) {
+	chainConfig := ethparams.AllEthashProtocolChanges
+
+	testCases := []struct {
+		code      []byte
+		disabled  []ethvm.OpCode
+		expectErr error
+	}{
+		{selfDestructCode, nil, nil},
+		{selfDestructCode, []ethvm.OpCode{ethvm.SELFDESTRUCT}, OpcodeDisabledError{Op: ethvm.SELFDESTRUCT}},
+		{selfDestructCode, []ethvm.OpCode{ethvm.CALLCODE}, nil},
+		{difficultyCode, []ethvm.OpCode{ethvm.SELFDESTRUCT}, nil},
+		{difficultyCode, []ethvm.OpCode{ethvm.DIFFICULTY}, OpcodeDisabledError{Op: ethvm.DIFFICULTY}},
+	}
+
+	for i, tc := range testCases {
+		stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+		require.Nil(t, err)
+
+		stateDB.SetCode(testContract, tc.code)
+
+		ctx := NewVMContext(abci.Header{Height: 10}, testCaller, testRecipient, big.NewInt(1), 1000000, nil)
+		cfg := NewVMConfig(chainConfig, ctx.BlockNumber, tc.disabled, ethvm.Config{})
+		evm := ethvm.NewEVM(ctx, stateDB, chainConfig, cfg)
+
+		_, _, err = evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 100000, new(big.Int))
+		require.Equal(t, tc.expectErr, OpcodeError(err, tc.disabled), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		// a self-destructed contract is only removed when enabled
+		if tc.expectErr == nil && tc.code[len(tc.code)-1] == byte(ethvm.SELFDESTRUCT) {
+			require.True(t, stateDB.HasSuicided(testContract), fmt.Sprintf("expected self-destruct: test case #%d", i))
+		} else {
+			require.False(t, stateDB.HasSuicided(testContract), fmt.Sprintf("unexpected self-destruct: test case #%d", i))
+		}
+	}
+
+	// errors unrelated to disabled opcodes are returned as is
+	require.Equal(t, ethvm.ErrOutOfGas, OpcodeError(ethvm.ErrOutOfGas, []ethvm.OpCode{ethvm.SELFDESTRUCT}))
+	require.Equal(t, "opcode SELFDESTRUCT is disabled", OpcodeDisabledError{Op: ethvm.SELFDESTRUCT}.Error())
+}
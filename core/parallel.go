@@ -0,0 +1,184 @@
+package core
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+)
+
+type (
+	// KVReadWriter defines the minimal key-value store interface required by
+	// the ParallelExecutor. Any sdk.KVStore satisfies it.
+	KVReadWriter interface {
+		Get(key []byte) []byte
+		Set(key, value []byte)
+		Delete(key []byte)
+	}
+
+	// TxExecutor executes the transaction at the given index within a block,
+	// performing all of its state accesses through the given store. Any error
+	// returned marks the transaction as failed and discards its writes.
+	TxExecutor func(index int, store KVReadWriter) error
+
+	// ParallelResult contains the outcome of executing a block of
+	// transactions with a ParallelExecutor.
+	ParallelResult struct {
+		// Errors contains the execution error of every transaction, if any
+		Errors []error
+
+		// Reexecuted is the number of transactions that had to be re-executed
+		// sequentially due to conflicts
+		Reexecuted int
+	}
+
+	// ParallelExecutor implements an experimental optimistic parallel
+	// execution engine. All the transactions of a block are first executed
+	// concurrently against the state prior to the block, each recording the
+	// values it read and the writes it made. Transactions are then committed
+	// in block order. A transaction whose reads no longer match the state
+	// resulting from all preceding transactions is in conflict and is
+	// re-executed sequentially against that state. The resulting state is thus
+	// always equal to the state of a sequential execution.
+	//
+	// CONTRACT: A TxExecutor must be deterministic and only access state
+	// through the given store. The base store must support concurrent reads.
+	ParallelExecutor struct {
+		workers int
+	}
+
+	// txView implements the KVReadWriter interface on top of a base store. It
+	// buffers all writes and records the value of every key read from the
+	// base store.
+	txView struct {
+		base   KVReadWriter
+		reads  map[string][]byte
+		writes map[string][]byte
+		order  []string
+	}
+)
+
+// NewParallelExecutor returns a reference to a new ParallelExecutor running
+// up to the given number of transactions concurrently. A non-positive number
+// of workers defaults to the number of CPUs.
+func NewParallelExecutor(workers int) *ParallelExecutor {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &ParallelExecutor{workers: workers}
+}
+
+// Execute executes the given number of transactions using the given
+// TxExecutor and commits the writes of all successful transactions to the
+// base store in block order.
+func (pe *ParallelExecutor) Execute(base KVReadWriter, numTxs int, exec TxExecutor) ParallelResult {
+	views := make([]*txView, numTxs)
+	errs := make([]error, numTxs)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, pe.workers)
+
+	for i := 0; i < numTxs; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			views[i] = newTxView(base)
+			errs[i] = exec(i, views[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	res := ParallelResult{Errors: errs}
+
+	for i, view := range views {
+		if !view.valid() {
+			view = newTxView(base)
+			res.Errors[i] = exec(i, view)
+			res.Reexecuted++
+		}
+
+		if res.Errors[i] == nil {
+			view.commit()
+		}
+	}
+
+	return res
+}
+
+func newTxView(base KVReadWriter) *txView {
+	return &txView{
+		base:   base,
+		reads:  make(map[string][]byte),
+		writes: make(map[string][]byte),
+	}
+}
+
+// Get implements the KVReadWriter interface. A key written by the transaction
+// itself is served from its writes, otherwise the value read from the base
+// store is recorded.
+func (v *txView) Get(key []byte) []byte {
+	k := string(key)
+
+	if value, ok := v.writes[k]; ok {
+		return value
+	}
+
+	if value, ok := v.reads[k]; ok {
+		return value
+	}
+
+	value := v.base.Get(key)
+	v.reads[k] = value
+
+	return value
+}
+
+// Set implements the KVReadWriter interface.
+func (v *txView) Set(key, value []byte) {
+	v.write(string(key), value)
+}
+
+// Delete implements the KVReadWriter interface. A deletion is recorded as a
+// write of a nil value.
+func (v *txView) Delete(key []byte) {
+	v.write(string(key), nil)
+}
+
+func (v *txView) write(k string, value []byte) {
+	if _, ok := v.writes[k]; !ok {
+		v.order = append(v.order, k)
+	}
+
+	v.writes[k] = value
+}
+
+// valid returns true if every value read by the transaction still matches the
+// value in the base store.
+func (v *txView) valid() bool {
+	for k, value := range v.reads {
+		if !bytes.Equal(value, v.base.Get([]byte(k))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// commit applies the transaction's writes to the base store in the order in
+// which the keys were first written.
+func (v *txView) commit() {
+	for _, k := range v.order {
+		if value := v.writes[k]; value != nil {
+			v.base.Set([]byte(k), value)
+		} else {
+			v.base.Delete([]byte(k))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memKVStore implements a KVReadWriter safe for concurrent use.
+type memKVStore struct {
+	mtx  sync.RWMutex
+	data map[string][]byte
+}
+
+func newMemKVStore() *memKVStore {
+	return &memKVStore{data: make(map[string][]byte)}
+}
+
+func (s *memKVStore) Get(key []byte) []byte {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.data[string(key)]
+}
+
+func (s *memKVStore) Set(key, value []byte) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.data[string(key)] = value
+}
+
+func (s *memKVStore) Delete(key []byte) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.data, string(key))
+}
+
+type testTransfer struct {
+	from, to string
+	amount   uint64
+}
+
+func getBalance(store KVReadWriter, addr string) uint64 {
+	bz := store.Get([]byte(addr))
+	if bz == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(bz)
+}
+
+func setBalance(store KVReadWriter, addr string, balance uint64) {
+	if balance == 0 {
+		store.Delete([]byte(addr))
+		return
+	}
+
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, balance)
+	store.Set([]byte(addr), bz)
+}
+
+func newTestTransferExecutor(transfers []testTransfer) TxExecutor {
+	return func(index int, store KVReadWriter) error {
+		tr := transfers[index]
+
+		fromBalance := getBalance(store, tr.from)
+		if fromBalance < tr.amount {
+			return errors.New("insufficient funds")
+		}
+
+		setBalance(store, tr.from, fromBalance-tr.amount)
+		setBalance(store, tr.to, getBalance(store, tr.to)+tr.amount)
+
+		return nil
+	}
+}
+
+func newTestTransferStore() *memKVStore {
+	store := newMemKVStore()
+
+	setBalance(store, "alice", 100)
+	setBalance(store, "bob", 50)
+	setBalance(store, "carol", 10)
+
+	return store
+}
+
+func TestParallelExecutor(t *testing.T) {
+	transfers := []testTransfer{
+		{"alice", "dave", 30},
+		{"carol", "erin", 10},
+		// conflicts with the first transfer and only succeeds sequentially
+		{"dave", "bob", 20},
+		{"bob", "carol", 200},
+		{"alice", "bob", 70},
+		// drains bob's balance deleting its key
+		{"bob", "frank", 140},
+	}
+
+	exec := newTestTransferExecutor(transfers)
+
+	expected := newTestTransferStore()
+	for i := range transfers {
+		// a failing transfer makes no writes
+		_ = exec(i, expected)
+	}
+
+	for _, workers := range []int{0, 1, 4} {
+		store := newTestTransferStore()
+		res := NewParallelExecutor(workers).Execute(store, len(transfers), exec)
+
+		require.Equal(t, expected.data, store.data, fmt.Sprintf("unexpected state: workers %d", workers))
+		require.Len(t, res.Errors, len(transfers))
+
+		for i, err := range res.Errors {
+			expectErr := i == 3
+			require.Equal(t, expectErr, err != nil, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
+
+func TestParallelExecutorNoConflicts(t *testing.T) {
+	transfers := []testTransfer{
+		{"alice", "dave", 30},
+		{"carol", "erin", 10},
+		{"bob", "frank", 20},
+	}
+
+	store := newTestTransferStore()
+	res := NewParallelExecutor(2).Execute(store, len(transfers), newTestTransferExecutor(transfers))
+
+	require.Zero(t, res.Reexecuted)
+	require.Equal(t, uint64(70), getBalance(store, "alice"))
+	require.Equal(t, uint64(30), getBalance(store, "dave"))
+	require.Equal(t, uint64(0), getBalance(store, "carol"))
+	require.Equal(t, uint64(20), getBalance(store, "frank"))
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	// precompilesMtx serializes the EVM executions of the process, guarding
+	// go-ethereum's precompiled contract maps and registeredPrecompiles
+	precompilesMtx        sync.Mutex
+	registeredPrecompiles = make(map[ethcmn.Address]bool)
+)
+
+// RegisterPrecompile reserves the address of a stateful precompiled contract,
+// allowing a contract to be bound to it by WithPrecompiles. The EVM only looks
+// precompiled contracts up in go-ethereum's global maps, which cannot hold
+// contracts bound to the state of a given execution, so the contracts are only
+// installed into the maps for the duration of a single execution. Outside of
+// such an execution, the address is a plain account. It panics if the address
+// is already taken by one of go-ethereum's precompiled contracts and is meant
+// to be called upon initialization (e.g. from an init function).
+func RegisterPrecompile(addr ethcmn.Address) {
+	precompilesMtx.Lock()
+	defer precompilesMtx.Unlock()
+
+	for _, precompiles := range []map[ethcmn.Address]ethvm.PrecompiledContract{
+		ethvm.PrecompiledContractsHomestead, ethvm.PrecompiledContractsByzantium,
+	} {
+		if p, ok := precompiles[addr]; ok {
+			panic(fmt.Sprintf("address %s is taken by precompiled contract %T", addr.Hex(), p))
+		}
+	}
+
+	registeredPrecompiles[addr] = true
+}
+
+// WithPrecompiles runs the given function, executing the EVM, with the given
+// precompiled contracts installed at their registered addresses (see
+// RegisterPrecompile). As go-ethereum's precompiled contract maps are shared
+// by every EVM of the process, every execution runs within WithPrecompiles,
+// binding its own contracts, and executions are serialized: ApplyCall,
+// EstimateGas and TraceBlock bind the contracts of their config. It panics if
+// an address was not registered.
+func WithPrecompiles(precompiles map[ethcmn.Address]ethvm.PrecompiledContract, fn func()) {
+	precompilesMtx.Lock()
+	defer precompilesMtx.Unlock()
+
+	for addr := range precompiles {
+		if !registeredPrecompiles[addr] {
+			panic(fmt.Sprintf("precompiled contract address %s is not registered", addr.Hex()))
+		}
+	}
+
+	installPrecompiles(precompiles)
+	defer uninstallPrecompiles(precompiles)
+
+	fn()
+}
+
+// installPrecompiles installs the given precompiled contracts into
+// go-ethereum's precompiled contract maps. The caller must hold
+// precompilesMtx.
+func installPrecompiles(precompiles map[ethcmn.Address]ethvm.PrecompiledContract) {
+	for addr, p := range precompiles {
+		ethvm.PrecompiledContractsHomestead[addr] = p
+		ethvm.PrecompiledContractsByzantium[addr] = p
+	}
+}
+
+// uninstallPrecompiles removes the given precompiled contracts from
+// go-ethereum's precompiled contract maps. The caller must hold
+// precompilesMtx.
+func uninstallPrecompiles(precompiles map[ethcmn.Address]ethvm.PrecompiledContract) {
+	for addr := range precompiles {
+		delete(ethvm.PrecompiledContractsHomestead, addr)
+		delete(ethvm.PrecompiledContractsByzantium, addr)
+	}
+}
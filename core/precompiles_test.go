@@ -0,0 +1,90 @@
+package core
+
+import (
+	"sync"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+// echoPrecompile implements a precompiled contract returning its input.
+type echoPrecompile struct{}
+
+func (echoPrecompile) RequiredGas(input []byte) uint64 { return uint64(len(input)) }
+
+func (echoPrecompile) Run(input []byte) ([]byte, error) { return input, nil }
+
+// prefixPrecompile implements a precompiled contract returning its input
+// prefixed with a given byte.
+type prefixPrecompile byte
+
+func (prefixPrecompile) RequiredGas(input []byte) uint64 { return 0 }
+
+func (p prefixPrecompile) Run(input []byte) ([]byte, error) {
+	return append([]byte{byte(p)}, input...), nil
+}
+
+func TestRegisterPrecompile(t *testing.T) {
+	addr := ethcmn.HexToAddress("0x00000000000000000000000000000000000001ff")
+
+	RegisterPrecompile(addr)
+	RegisterPrecompile(addr)
+
+	// go-ethereum's own precompiled contracts cannot be replaced
+	require.Panics(t, func() { RegisterPrecompile(ethcmn.BytesToAddress([]byte{1})) })
+
+	// contracts cannot be bound to unregistered addresses
+	require.Panics(t, func() {
+		WithPrecompiles(map[ethcmn.Address]ethvm.PrecompiledContract{
+			ethcmn.HexToAddress("0x00000000000000000000000000000000000002ff"): echoPrecompile{},
+		}, func() {})
+	})
+
+	for _, precompiles := range []map[ethcmn.Address]ethvm.PrecompiledContract{
+		ethvm.PrecompiledContractsHomestead, ethvm.PrecompiledContractsByzantium,
+	} {
+		// go-ethereum's maps are left untouched outside of WithPrecompiles
+		require.Nil(t, precompiles[addr])
+
+		WithPrecompiles(map[ethcmn.Address]ethvm.PrecompiledContract{addr: echoPrecompile{}}, func() {
+			p := precompiles[addr]
+			require.NotNil(t, p)
+			require.Equal(t, uint64(5), p.RequiredGas([]byte("input")))
+
+			ret, err := p.Run([]byte("input"))
+			require.Nil(t, err)
+			require.Equal(t, []byte("input"), ret)
+		})
+
+		// the contract is unbound once the function returns
+		require.Nil(t, precompiles[addr])
+	}
+}
+
+func TestWithPrecompilesConcurrent(t *testing.T) {
+	addr := ethcmn.HexToAddress("0x00000000000000000000000000000000000003ff")
+	RegisterPrecompile(addr)
+
+	var wg sync.WaitGroup
+
+	// concurrent executions each run the contract they bound
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(prefix byte) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				WithPrecompiles(map[ethcmn.Address]ethvm.PrecompiledContract{addr: prefixPrecompile(prefix)}, func() {
+					ret, err := ethvm.PrecompiledContractsByzantium[addr].Run([]byte("input"))
+					require.Nil(t, err)
+					require.Equal(t, append([]byte{prefix}, "input"...), ret)
+				})
+			}
+		}(byte(i))
+	}
+
+	wg.Wait()
+}
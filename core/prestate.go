@@ -0,0 +1,165 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+type (
+	// PrestateAccount is the state of an account prior to a transaction, in
+	// the format of geth's prestateTracer. The storage only holds the slots
+	// accessed by the transaction.
+	PrestateAccount struct {
+		Balance *hexutil.Big                `json:"balance"`
+		Nonce   uint64                      `json:"nonce"`
+		Code    hexutil.Bytes               `json:"code"`
+		Storage map[ethcmn.Hash]ethcmn.Hash `json:"storage"`
+	}
+
+	// PrestateTracer implements Ethereum's vm.Tracer interface. It records the
+	// state, prior to a single transaction, of every account and storage slot
+	// accessed by the transaction, e.g. so that the transaction can be replayed
+	// in isolation.
+	//
+	// As with the StateDiffTracer, the accounts involved before EVM execution
+	// (i.e. the sender, the recipient and the coinbase) must be given upon
+	// construction prior to applying the transaction. Every other account is
+	// recorded before the first operation accessing it is executed.
+	PrestateTracer struct {
+		stateDB  ethvm.StateDB
+		accounts map[ethcmn.Address]*PrestateAccount
+	}
+)
+
+// NewPrestateTracer returns a reference to a new PrestateTracer over the given
+// state. The given accounts are recorded immediately.
+func NewPrestateTracer(stateDB ethvm.StateDB, accounts ...ethcmn.Address) *PrestateTracer {
+	pt := &PrestateTracer{
+		stateDB:  stateDB,
+		accounts: make(map[ethcmn.Address]*PrestateAccount),
+	}
+
+	for _, addr := range accounts {
+		pt.touchAccount(addr)
+	}
+
+	return pt
+}
+
+// CaptureStart implements Ethereum's vm.Tracer interface. It performs a no-op
+// as it is invoked after the top-level transfer has been made.
+func (pt *PrestateTracer) CaptureStart(_, _ ethcmn.Address, _ bool, _ []byte, _ uint64, _ *big.Int) error {
+	return nil
+}
+
+// CaptureState implements Ethereum's vm.Tracer interface. It is invoked prior
+// to the execution of every opcode, recording any account or storage slot
+// about to be accessed for the first time.
+func (pt *PrestateTracer) CaptureState(
+	env *ethvm.EVM, _ uint64, op ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, _ int, err error,
+) error {
+	if err != nil {
+		return nil
+	}
+
+	switch op {
+	case ethvm.SLOAD, ethvm.SSTORE:
+		pt.touchStorage(contract.Address(), ethcmn.BigToHash(stack.Back(0)))
+
+	case ethvm.BALANCE, ethvm.EXTCODESIZE, ethvm.EXTCODECOPY:
+		pt.touchAccount(ethcmn.BigToAddress(stack.Back(0)))
+
+	case ethvm.CALL, ethvm.CALLCODE, ethvm.DELEGATECALL, ethvm.STATICCALL:
+		pt.touchAccount(contract.Address())
+		pt.touchAccount(ethcmn.BigToAddress(stack.Back(1)))
+
+	case ethvm.CREATE:
+		caller := contract.Address()
+
+		pt.touchAccount(caller)
+		pt.touchAccount(ethcrypto.CreateAddress(caller, env.StateDB.GetNonce(caller)))
+
+	case ethvm.SELFDESTRUCT:
+		pt.touchAccount(contract.Address())
+		pt.touchAccount(ethcmn.BigToAddress(stack.Back(0)))
+	}
+
+	return nil
+}
+
+// CaptureFault implements Ethereum's vm.Tracer interface. It performs a no-op.
+func (pt *PrestateTracer) CaptureFault(
+	_ *ethvm.EVM, _ uint64, _ ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, _ *ethvm.Stack, _ *ethvm.Contract, _ int, _ error,
+) error {
+	return nil
+}
+
+// CaptureEnd implements Ethereum's vm.Tracer interface. It performs a no-op.
+func (pt *PrestateTracer) CaptureEnd(_ []byte, _ uint64, _ time.Duration, _ error) error {
+	return nil
+}
+
+// Prestate returns the recorded state of every account accessed.
+func (pt *PrestateTracer) Prestate() map[ethcmn.Address]*PrestateAccount {
+	return pt.accounts
+}
+
+// touchAccount records the current state of a given account, excluding its
+// storage, if it was not touched before.
+func (pt *PrestateTracer) touchAccount(addr ethcmn.Address) *PrestateAccount {
+	if account, ok := pt.accounts[addr]; ok {
+		return account
+	}
+
+	account := &PrestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(pt.stateDB.GetBalance(addr))),
+		Nonce:   pt.stateDB.GetNonce(addr),
+		Code:    ethcmn.CopyBytes(pt.stateDB.GetCode(addr)),
+		Storage: make(map[ethcmn.Hash]ethcmn.Hash),
+	}
+
+	pt.accounts[addr] = account
+	return account
+}
+
+// touchStorage records the current value of a given storage slot if it was
+// not touched before.
+func (pt *PrestateTracer) touchStorage(addr ethcmn.Address, key ethcmn.Hash) {
+	account := pt.touchAccount(addr)
+	if _, ok := account.Storage[key]; ok {
+		return
+	}
+
+	account.Storage[key] = pt.stateDB.GetState(addr, key)
+}
+
+// prestateTxTracer adapts a PrestateTracer to a TxTracer.
+type prestateTxTracer struct {
+	*PrestateTracer
+}
+
+// newPrestateTxTracer implements TracerConstructor. The tracer takes no
+// config.
+func newPrestateTxTracer(ctx TracerContext, _ json.RawMessage) (TxTracer, error) {
+	accounts := []ethcmn.Address{ctx.From, ctx.Coinbase}
+	if ctx.To != nil {
+		accounts = append(accounts, *ctx.To)
+	} else {
+		accounts = append(accounts, ethcrypto.CreateAddress(ctx.From, ctx.StateDB.GetNonce(ctx.From)))
+	}
+
+	return prestateTxTracer{NewPrestateTracer(ctx.StateDB, accounts...)}, nil
+}
+
+// Result implements the TxTracer interface.
+func (t prestateTxTracer) Result(_ TxExecution) (interface{}, error) {
+	return t.Prestate(), nil
+}
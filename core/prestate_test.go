@@ -0,0 +1,66 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrestateTracerInterface(t *testing.T) {
+	require.Implements(t, (*ethvm.Tracer)(nil), new(PrestateTracer))
+}
+
+func TestPrestateTracer(t *testing.T) {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	code := append(storeCode(1, 42), callWithValueCode(testRecipient, 5)...)
+
+	stateDB.SetCode(testContract, code)
+	stateDB.AddBalance(testContract, big.NewInt(10))
+	stateDB.AddBalance(testCaller, big.NewInt(100))
+	stateDB.SetState(testContract, ethcmn.BigToHash(big.NewInt(1)), ethcmn.BigToHash(big.NewInt(7)))
+	stateDB.SetNonce(testCaller, 3)
+
+	tracer := NewPrestateTracer(stateDB, testCaller, testContract)
+	ctx := ethvm.Context{
+		CanTransfer: ethcore.CanTransfer,
+		Transfer:    ethcore.Transfer,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    1000000,
+		GasPrice:    big.NewInt(1),
+	}
+
+	evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{Debug: true, Tracer: tracer})
+
+	_, _, err = evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 1000000, big.NewInt(3))
+	require.Nil(t, err)
+
+	prestate := tracer.Prestate()
+	require.Len(t, prestate, 3)
+
+	caller := prestate[testCaller]
+	require.Equal(t, big.NewInt(100), caller.Balance.ToInt())
+	require.Equal(t, uint64(3), caller.Nonce)
+	require.Empty(t, caller.Code)
+
+	// the slot holds its value prior to being overwritten
+	contract := prestate[testContract]
+	require.Equal(t, big.NewInt(10), contract.Balance.ToInt())
+	require.Equal(t, code, []byte(contract.Code))
+	require.Equal(t, map[ethcmn.Hash]ethcmn.Hash{
+		ethcmn.BigToHash(big.NewInt(1)): ethcmn.BigToHash(big.NewInt(7)),
+	}, contract.Storage)
+
+	require.Equal(t, big.NewInt(0), prestate[testRecipient].Balance.ToInt())
+	require.Equal(t, big.NewInt(5), stateDB.GetBalance(testRecipient))
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+)
+
+var (
+	// errorSelector is the selector of the Error(string) revert payload
+	// emitted by require, revert and failed external calls.
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// panicSelector is the selector of the Panic(uint256) revert payload
+	// emitted by failed assertions and runtime errors.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+	// panicReasons maps the Solidity panic codes to their description.
+	panicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assertion failed",
+		0x11: "arithmetic overflow or underflow",
+		0x12: "division or modulo by zero",
+		0x21: "invalid enum value",
+		0x22: "invalid storage byte array encoding",
+		0x31: "pop on empty array",
+		0x32: "array index out of bounds",
+		0x41: "out of memory",
+		0x51: "call to uninitialized function",
+	}
+)
+
+// UnpackRevertReason decodes the human readable reason of a reverted
+// execution from its return data. Both Error(string) and Panic(uint256)
+// payloads are supported. A boolean is returned reflecting if the return data
+// contained a well-formed reason.
+func UnpackRevertReason(ret []byte) (string, bool) {
+	if len(ret) < 4 {
+		return "", false
+	}
+
+	selector, args := ret[:4], ret[4:]
+
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		return unpackErrorReason(args)
+
+	case bytes.Equal(selector, panicSelector):
+		return unpackPanicReason(args)
+	}
+
+	return "", false
+}
+
+// RevertMessage returns a message describing a reverted execution, including
+// the revert reason if it can be decoded from the given return data.
+func RevertMessage(ret []byte) string {
+	if reason, ok := UnpackRevertReason(ret); ok {
+		return "execution reverted: " + reason
+	}
+
+	return "execution reverted"
+}
+
+// unpackErrorReason decodes the ABI encoded string argument of an
+// Error(string) payload.
+func unpackErrorReason(args []byte) (string, bool) {
+	offset, ok := abiWord(args, 0)
+	if !ok || !offset.IsUint64() {
+		return "", false
+	}
+
+	length, ok := abiWord(args, offset.Uint64())
+	if !ok || !length.IsUint64() {
+		return "", false
+	}
+
+	start := offset.Uint64() + 32
+	end := start + length.Uint64()
+
+	if end < start || end > uint64(len(args)) || !utf8.Valid(args[start:end]) {
+		return "", false
+	}
+
+	return string(args[start:end]), true
+}
+
+// unpackPanicReason decodes the uint256 code of a Panic(uint256) payload.
+func unpackPanicReason(args []byte) (string, bool) {
+	code, ok := abiWord(args, 0)
+	if !ok {
+		return "", false
+	}
+
+	if code.IsUint64() {
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return fmt.Sprintf("panic: %s (0x%x)", reason, code), true
+		}
+	}
+
+	return fmt.Sprintf("panic: unknown code (0x%x)", code), true
+}
+
+// abiWord returns the 32 byte word at a given offset of ABI encoded data as an
+// unsigned integer. A boolean is returned reflecting if the word is in bounds.
+func abiWord(data []byte, offset uint64) (*big.Int, bool) {
+	if offset+32 < offset || offset+32 > uint64(len(data)) {
+		return nil, false
+	}
+
+	return new(big.Int).SetBytes(data[offset : offset+32]), true
+}
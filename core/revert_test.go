@@ -0,0 +1,64 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeRevertError returns the Error(string) revert payload of a reason.
+func encodeRevertError(reason string) []byte {
+	padded := make([]byte, (len(reason)+31)/32*32)
+	copy(padded, reason)
+
+	ret := append([]byte{}, errorSelector...)
+	ret = append(ret, ethcmn.LeftPadBytes([]byte{0x20}, 32)...)
+	ret = append(ret, ethcmn.LeftPadBytes([]byte{byte(len(reason))}, 32)...)
+
+	return append(ret, padded...)
+}
+
+// encodeRevertPanic returns the Panic(uint256) revert payload of a code.
+func encodeRevertPanic(code byte) []byte {
+	return append(append([]byte{}, panicSelector...), ethcmn.LeftPadBytes([]byte{code}, 32)...)
+}
+
+func TestUnpackRevertReason(t *testing.T) {
+	valid := encodeRevertError("insufficient balance")
+
+	testCases := []struct {
+		ret            []byte
+		expectedReason string
+		expectOK       bool
+	}{
+		{valid, "insufficient balance", true},
+		{encodeRevertError(""), "", true},
+		{encodeRevertPanic(0x01), "panic: assertion failed (0x1)", true},
+		{encodeRevertPanic(0x11), "panic: arithmetic overflow or underflow (0x11)", true},
+		{encodeRevertPanic(0x99), "panic: unknown code (0x99)", true},
+		{nil, "", false},
+		{[]byte{0x08, 0xc3, 0x79}, "", false},
+		// unknown selector
+		{append([]byte{0x01, 0x02, 0x03, 0x04}, valid[4:]...), "", false},
+		// truncated reason
+		{valid[:len(valid)-32], "", false},
+		// out of bounds offset
+		{append(append([]byte{}, errorSelector...), ethcmn.LeftPadBytes([]byte{0xff}, 32)...), "", false},
+		// truncated panic code
+		{encodeRevertPanic(0x01)[:20], "", false},
+	}
+
+	for i, tc := range testCases {
+		reason, ok := UnpackRevertReason(tc.ret)
+
+		require.Equal(t, tc.expectOK, ok, fmt.Sprintf("unexpected result: test case #%d", i))
+		require.Equal(t, tc.expectedReason, reason, fmt.Sprintf("unexpected reason: test case #%d", i))
+	}
+}
+
+func TestRevertMessage(t *testing.T) {
+	require.Equal(t, "execution reverted: not owner", RevertMessage(encodeRevertError("not owner")))
+	require.Equal(t, "execution reverted", RevertMessage(nil))
+}
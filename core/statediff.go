@@ -0,0 +1,199 @@
+package core
+
+import (
+	"math/big"
+	"time"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+type (
+	// StorageDiff represents a change of a single contract storage slot made
+	// by a transaction.
+	StorageDiff struct {
+		Address  ethcmn.Address `json:"address"`
+		Key      ethcmn.Hash    `json:"key"`
+		Original ethcmn.Hash    `json:"original"`
+		Current  ethcmn.Hash    `json:"current"`
+	}
+
+	// BalanceDiff represents a change of the balance of a single account made
+	// by a transaction. The delta is encoded as a decimal JSON number as it may
+	// be negative.
+	BalanceDiff struct {
+		Address  ethcmn.Address `json:"address"`
+		Original *hexutil.Big   `json:"original"`
+		Current  *hexutil.Big   `json:"current"`
+		Delta    *big.Int       `json:"delta"`
+	}
+
+	// StateDiff represents all the changes of contract storage and account
+	// balances made by a single transaction, in the order in which the
+	// changed slots and accounts were first touched.
+	StateDiff struct {
+		TxHash   ethcmn.Hash   `json:"txHash"`
+		Storage  []StorageDiff `json:"storage"`
+		Balances []BalanceDiff `json:"balances"`
+	}
+
+	// StateDiffTracer implements Ethereum's vm.Tracer interface. It records the
+	// original value of every storage slot and account balance touched during
+	// a single transaction so that a StateDiff can be computed once the
+	// transaction has been applied.
+	//
+	// An account's balance may only change through an internal value transfer
+	// observed by the tracer, or through the top-level transfer and the
+	// purchase and refund of gas which all precede EVM execution. Hence, the
+	// accounts involved in the latter (i.e. the sender, the recipient and the
+	// coinbase) must be given upon construction prior to applying the
+	// transaction.
+	StateDiffTracer struct {
+		stateDB ethvm.StateDB
+
+		storage      map[storageSlot]ethcmn.Hash
+		storageOrder []storageSlot
+		balances     map[ethcmn.Address]*big.Int
+		balanceOrder []ethcmn.Address
+	}
+
+	// storageSlot identifies a single contract storage slot.
+	storageSlot struct {
+		addr ethcmn.Address
+		key  ethcmn.Hash
+	}
+)
+
+// NewStateDiffTracer returns a reference to a new StateDiffTracer over the
+// given state. The balances of the given accounts are recorded immediately
+// and must be recorded before any gas is purchased.
+func NewStateDiffTracer(stateDB ethvm.StateDB, accounts ...ethcmn.Address) *StateDiffTracer {
+	sdt := &StateDiffTracer{
+		stateDB:  stateDB,
+		storage:  make(map[storageSlot]ethcmn.Hash),
+		balances: make(map[ethcmn.Address]*big.Int),
+	}
+
+	for _, addr := range accounts {
+		sdt.touchBalance(addr)
+	}
+
+	return sdt
+}
+
+// CaptureStart implements Ethereum's vm.Tracer interface. It performs a no-op
+// as it is invoked after the top-level transfer has been made.
+func (sdt *StateDiffTracer) CaptureStart(_, _ ethcmn.Address, _ bool, _ []byte, _ uint64, _ *big.Int) error {
+	return nil
+}
+
+// CaptureState implements Ethereum's vm.Tracer interface. It is invoked prior
+// to the execution of every opcode, recording the original value of any
+// storage slot about to be written and the original balance of any account
+// about to be involved in a value transfer.
+func (sdt *StateDiffTracer) CaptureState(
+	env *ethvm.EVM, _ uint64, op ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, _ int, err error,
+) error {
+	if err != nil {
+		return nil
+	}
+
+	switch op {
+	case ethvm.SSTORE:
+		sdt.touchStorage(contract.Address(), ethcmn.BigToHash(stack.Back(0)))
+
+	case ethvm.CALL, ethvm.CALLCODE:
+		sdt.touchBalance(contract.Address())
+		sdt.touchBalance(ethcmn.BigToAddress(stack.Back(1)))
+
+	case ethvm.CREATE:
+		caller := contract.Address()
+
+		sdt.touchBalance(caller)
+		sdt.touchBalance(ethcrypto.CreateAddress(caller, env.StateDB.GetNonce(caller)))
+
+	case ethvm.SELFDESTRUCT:
+		sdt.touchBalance(contract.Address())
+		sdt.touchBalance(ethcmn.BigToAddress(stack.Back(0)))
+	}
+
+	return nil
+}
+
+// CaptureFault implements Ethereum's vm.Tracer interface. It performs a no-op
+// as the state changes of a failed frame are reverted by the state itself.
+func (sdt *StateDiffTracer) CaptureFault(
+	_ *ethvm.EVM, _ uint64, _ ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, _ *ethvm.Stack, _ *ethvm.Contract, _ int, _ error,
+) error {
+	return nil
+}
+
+// CaptureEnd implements Ethereum's vm.Tracer interface. It performs a no-op.
+func (sdt *StateDiffTracer) CaptureEnd(_ []byte, _ uint64, _ time.Duration, _ error) error {
+	return nil
+}
+
+// StateDiff returns the changes made to the touched storage slots and account
+// balances for the transaction with the given hash. It must be called after
+// the transaction has been applied. Touched slots and accounts whose value
+// did not change are omitted.
+func (sdt *StateDiffTracer) StateDiff(txHash ethcmn.Hash) StateDiff {
+	diff := StateDiff{
+		TxHash:   txHash,
+		Storage:  []StorageDiff{},
+		Balances: []BalanceDiff{},
+	}
+
+	for _, slot := range sdt.storageOrder {
+		current := sdt.stateDB.GetState(slot.addr, slot.key)
+		if original := sdt.storage[slot]; original != current {
+			diff.Storage = append(diff.Storage, StorageDiff{
+				Address:  slot.addr,
+				Key:      slot.key,
+				Original: original,
+				Current:  current,
+			})
+		}
+	}
+
+	for _, addr := range sdt.balanceOrder {
+		original, current := sdt.balances[addr], new(big.Int).Set(sdt.stateDB.GetBalance(addr))
+		if original.Cmp(current) != 0 {
+			diff.Balances = append(diff.Balances, BalanceDiff{
+				Address:  addr,
+				Original: (*hexutil.Big)(original),
+				Current:  (*hexutil.Big)(current),
+				Delta:    new(big.Int).Sub(current, original),
+			})
+		}
+	}
+
+	return diff
+}
+
+// touchStorage records the current value of a given storage slot if it was
+// not touched before.
+func (sdt *StateDiffTracer) touchStorage(addr ethcmn.Address, key ethcmn.Hash) {
+	slot := storageSlot{addr: addr, key: key}
+	if _, ok := sdt.storage[slot]; ok {
+		return
+	}
+
+	sdt.storage[slot] = sdt.stateDB.GetState(addr, key)
+	sdt.storageOrder = append(sdt.storageOrder, slot)
+}
+
+// touchBalance records the current balance of a given account if it was not
+// touched before.
+func (sdt *StateDiffTracer) touchBalance(addr ethcmn.Address) {
+	if _, ok := sdt.balances[addr]; ok {
+		return
+	}
+
+	sdt.balances[addr] = new(big.Int).Set(sdt.stateDB.GetBalance(addr))
+	sdt.balanceOrder = append(sdt.balanceOrder, addr)
+}
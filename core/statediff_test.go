@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// storeCode returns EVM byte code that stores the given value (< 256) in the
+// given storage slot (< 256).
+func storeCode(slot, value byte) []byte {
+	return []byte{byte(ethvm.PUSH1), value, byte(ethvm.PUSH1), slot, byte(ethvm.SSTORE)}
+}
+
+func runStateDiffTracer(t *testing.T, code []byte, balance, value int64) StateDiff {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.SetCode(testContract, code)
+	stateDB.AddBalance(testContract, big.NewInt(balance))
+	stateDB.AddBalance(testCaller, big.NewInt(100))
+	stateDB.SetState(testContract, ethcmn.BigToHash(big.NewInt(2)), ethcmn.BigToHash(big.NewInt(7)))
+
+	tracer := NewStateDiffTracer(stateDB, testCaller, testContract)
+	ctx := ethvm.Context{
+		CanTransfer: ethcore.CanTransfer,
+		Transfer:    ethcore.Transfer,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    1000000,
+		GasPrice:    big.NewInt(1),
+	}
+
+	evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{Debug: true, Tracer: tracer})
+
+	_, _, err = evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 1000000, big.NewInt(value))
+	require.Nil(t, err)
+
+	return tracer.StateDiff(ethcmn.BytesToHash([]byte("tx")))
+}
+
+func TestStateDiffTracerInterface(t *testing.T) {
+	require.Implements(t, (*ethvm.Tracer)(nil), new(StateDiffTracer))
+}
+
+func TestStateDiffTracer(t *testing.T) {
+	code := append(storeCode(1, 42), storeCode(2, 7)...)
+	code = append(code, callWithValueCode(testRecipient, 5)...)
+
+	diff := runStateDiffTracer(t, code, 10, 3)
+	require.Equal(t, ethcmn.BytesToHash([]byte("tx")), diff.TxHash)
+
+	// slot 2 is overwritten with its original value and thus omitted
+	require.Equal(t, []StorageDiff{
+		{
+			Address:  testContract,
+			Key:      ethcmn.BigToHash(big.NewInt(1)),
+			Original: ethcmn.Hash{},
+			Current:  ethcmn.BigToHash(big.NewInt(42)),
+		},
+	}, diff.Storage)
+
+	require.Len(t, diff.Balances, 3)
+
+	expected := []struct {
+		addr                     ethcmn.Address
+		original, current, delta int64
+	}{
+		{testCaller, 100, 97, -3},
+		{testContract, 10, 8, -2},
+		{testRecipient, 0, 5, 5},
+	}
+
+	for i, tc := range expected {
+		require.Equal(t, tc.addr, diff.Balances[i].Address, fmt.Sprintf("unexpected address: test case #%d", i))
+		require.Equal(t, big.NewInt(tc.original), diff.Balances[i].Original.ToInt(), fmt.Sprintf("unexpected original balance: test case #%d", i))
+		require.Equal(t, big.NewInt(tc.current), diff.Balances[i].Current.ToInt(), fmt.Sprintf("unexpected current balance: test case #%d", i))
+		require.Equal(t, big.NewInt(tc.delta), diff.Balances[i].Delta, fmt.Sprintf("unexpected delta balance: test case #%d", i))
+	}
+}
+
+func TestStateDiffTracerFailedCall(t *testing.T) {
+	// the contract does not have enough funds so the call must fail
+	diff := runStateDiffTracer(t, callWithValueCode(testRecipient, 5), 1, 0)
+	require.Empty(t, diff.Storage)
+	require.Empty(t, diff.Balances)
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/ethermint/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// Tracers built into TraceBlock. Additional tracers may be registered with
+// RegisterTracer.
+const (
+	// TracerStructLogger records every executed opcode along with the stack,
+	// memory and storage, as geth's default tracer does.
+	TracerStructLogger = "structLogger"
+
+	// TracerStateDiff records the storage slots and balances changed (see
+	// StateDiffTracer).
+	TracerStateDiff = "stateDiff"
+
+	// TracerTransfers records the internal value transfers made (see
+	// TransferTracer).
+	TracerTransfers = "transfers"
+
+	// TracerCall records the tree of calls made, as geth's callTracer does
+	// (see CallTracer).
+	TracerCall = "callTracer"
+
+	// TracerPrestate records the state prior to the transaction of the
+	// accounts accessed, as geth's prestateTracer does (see PrestateTracer).
+	TracerPrestate = "prestateTracer"
+)
+
+type (
+	// TraceBlockConfig defines the block whose transactions are traced and
+	// the tracer to trace them with.
+	TraceBlockConfig struct {
+		ChainConfig *ethparams.ChainConfig
+		Header      abci.Header
+		Coinbase    ethcmn.Address
+		GasLimit    uint64
+		GetHash     ethvm.GetHashFunc
+
+		// DisabledOpcodes are the opcodes disabled on the chain (see
+		// NewVMConfig).
+		DisabledOpcodes []ethvm.OpCode
+
+		// Precompiles are the stateful precompiled contracts bound to the
+		// execution of every transaction (see WithPrecompiles).
+		Precompiles map[ethcmn.Address]ethvm.PrecompiledContract
+
+		// Tracer is the name of a registered tracer and defaults to
+		// TracerStructLogger. TracerConfig is the tracer's optional JSON
+		// configuration.
+		Tracer       string
+		TracerConfig json.RawMessage
+	}
+
+	// TxTraceResult is the trace of a single transaction of a block. The
+	// error is set instead of the result if the transaction could not be
+	// executed at all (e.g. as its sender cannot pay for gas).
+	TxTraceResult struct {
+		TxHash ethcmn.Hash `json:"txHash"`
+		Result interface{} `json:"result,omitempty"`
+		Error  string      `json:"error,omitempty"`
+	}
+
+	// ExecutionResult is the result of tracing a transaction with
+	// TracerStructLogger, in the format of geth's debug_traceTransaction.
+	ExecutionResult struct {
+		Gas         uint64         `json:"gas"`
+		Failed      bool           `json:"failed"`
+		ReturnValue string         `json:"returnValue"`
+		StructLogs  []StructLogRes `json:"structLogs"`
+	}
+
+	// StructLogRes is a single opcode executed by a transaction traced with
+	// TracerStructLogger.
+	StructLogRes struct {
+		Pc      uint64             `json:"pc"`
+		Op      string             `json:"op"`
+		Gas     uint64             `json:"gas"`
+		GasCost uint64             `json:"gasCost"`
+		Depth   int                `json:"depth"`
+		Error   string             `json:"error,omitempty"`
+		Stack   *[]string          `json:"stack,omitempty"`
+		Memory  *[]string          `json:"memory,omitempty"`
+		Storage *map[string]string `json:"storage,omitempty"`
+	}
+
+	// TransfersResult is the result of tracing a transaction with
+	// TracerTransfers.
+	TransfersResult struct {
+		Gas       uint64             `json:"gas"`
+		Failed    bool               `json:"failed"`
+		Transfers []InternalTransfer `json:"transfers"`
+	}
+)
+
+// TraceBlock re-executes the given transactions of a block on top of the
+// given state, i.e. the state prior to the block, in order and traces each
+// with the configured tracer. The result of every transaction is passed to
+// emit as soon as the transaction is traced, allowing results to be streamed.
+// Tracing stops at the first error returned by emit.
+//
+// The state is modified by every transaction so that each is traced on top of
+// the state left by its predecessors. It must hence be discarded once traced.
+func TraceBlock(
+	stateDB *ethstate.StateDB, config TraceBlockConfig, txs []*types.Transaction, emit func(TxTraceResult) error,
+) error {
+
+	if config.Tracer == "" {
+		config.Tracer = TracerStructLogger
+	}
+
+	newTracer, ok := lookupTracer(config.Tracer)
+	if !ok {
+		return fmt.Errorf("unsupported tracer %s", config.Tracer)
+	}
+
+	for i, tx := range txs {
+		stateDB.Prepare(tx.Hash(), ethcmn.Hash{}, i)
+
+		res, err := traceTx(stateDB, config, newTracer, tx)
+		if err != nil {
+			return err
+		}
+
+		stateDB.Finalise(true)
+
+		if err := emit(res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// traceTx executes and traces a single transaction with a tracer returned by
+// the given constructor. An error is only returned if the tracer cannot be
+// constructed, e.g. as its config is invalid.
+func traceTx(
+	stateDB *ethstate.StateDB, config TraceBlockConfig, newTracer TracerConstructor, tx *types.Transaction,
+) (TxTraceResult, error) {
+
+	res := TxTraceResult{TxHash: tx.Hash()}
+
+	from, err := tx.Sender(config.ChainConfig, big.NewInt(config.Header.Height))
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	tracer, err := newTracer(TracerContext{
+		StateDB:  stateDB,
+		TxHash:   res.TxHash,
+		From:     from,
+		To:       tx.To(),
+		Coinbase: config.Coinbase,
+	}, config.TracerConfig)
+	if err != nil {
+		return res, err
+	}
+
+	msg := ethtypes.NewMessage(from, tx.To(), tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data(), false)
+	vmCtx := NewVMContext(config.Header, from, config.Coinbase, tx.GasPrice(), config.GasLimit, config.GetHash)
+	vmConfig := NewVMConfig(
+		config.ChainConfig, vmCtx.BlockNumber, config.DisabledOpcodes, ethvm.Config{Debug: true, Tracer: tracer},
+	)
+	evm := ethvm.NewEVM(vmCtx, stateDB, config.ChainConfig, vmConfig)
+
+	var (
+		ret     []byte
+		gasUsed uint64
+		failed  bool
+	)
+
+	WithPrecompiles(config.Precompiles, func() {
+		ret, gasUsed, failed, err = ethcore.ApplyMessage(evm, msg, new(ethcore.GasPool).AddGas(tx.Gas()))
+	})
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	res.Result, err = tracer.Result(TxExecution{Gas: tx.Gas(), GasUsed: gasUsed, Failed: failed, ReturnValue: ret})
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	return res, nil
+}
+
+// formatStructLogs formats the structured logs recorded by the EVM for JSON
+// output.
+func formatStructLogs(logs []ethvm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+
+	for i, log := range logs {
+		formatted[i] = StructLogRes{
+			Pc:      log.Pc,
+			Op:      log.Op.String(),
+			Gas:     log.Gas,
+			GasCost: log.GasCost,
+			Depth:   log.Depth,
+		}
+
+		if log.Err != nil {
+			formatted[i].Error = log.Err.Error()
+		}
+
+		if log.Stack != nil {
+			stack := make([]string, len(log.Stack))
+			for j, value := range log.Stack {
+				stack[j] = fmt.Sprintf("%x", ethmath.PaddedBigBytes(value, 32))
+			}
+
+			formatted[i].Stack = &stack
+		}
+
+		if log.Memory != nil {
+			memory := make([]string, 0, (len(log.Memory)+31)/32)
+			for j := 0; j+32 <= len(log.Memory); j += 32 {
+				memory = append(memory, fmt.Sprintf("%x", log.Memory[j:j+32]))
+			}
+
+			formatted[i].Memory = &memory
+		}
+
+		if log.Storage != nil {
+			storage := make(map[string]string, len(log.Storage))
+			for key, value := range log.Storage {
+				storage[fmt.Sprintf("%x", key)] = fmt.Sprintf("%x", value)
+			}
+
+			formatted[i].Storage = &storage
+		}
+	}
+
+	return formatted
+}
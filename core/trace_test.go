@@ -0,0 +1,243 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+var testTraceChainID = big.NewInt(3)
+
+// newTraceTestBlock returns a state holding a contract storing a value along
+// with a funded sender and two transactions of the sender: a call to the
+// contract and a plain transfer.
+func newTraceTestBlock(t *testing.T) (*ethstate.StateDB, []*types.Transaction) {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.AddBalance(sender, big.NewInt(100000))
+	stateDB.SetCode(testContract, storeCode(1, 42))
+
+	txs := []*types.Transaction{
+		types.NewTransaction(0, testContract, big.NewInt(5), 50000, big.NewInt(1), nil),
+		types.NewTransaction(1, testRecipient, big.NewInt(1), 21000, big.NewInt(1), nil),
+	}
+
+	for _, tx := range txs {
+		tx.Sign(testTraceChainID, priv)
+	}
+
+	return stateDB, txs
+}
+
+func TestTraceBlock(t *testing.T) {
+	testCases := []struct {
+		tracer     string
+		expectType interface{}
+	}{
+		{"", ExecutionResult{}},
+		{TracerStructLogger, ExecutionResult{}},
+		{TracerStateDiff, StateDiff{}},
+		{TracerTransfers, TransfersResult{}},
+		{TracerCall, &CallFrame{}},
+		{TracerPrestate, map[ethcmn.Address]*PrestateAccount{}},
+	}
+
+	for i, tc := range testCases {
+		stateDB, txs := newTraceTestBlock(t)
+		config := TraceBlockConfig{
+			ChainConfig: NewChainConfig(testTraceChainID),
+			Header:      abci.Header{Height: 2, Time: 1},
+			GasLimit:    1000000,
+			Tracer:      tc.tracer,
+		}
+
+		var results []TxTraceResult
+		err := TraceBlock(stateDB, config, txs, func(res TxTraceResult) error {
+			results = append(results, res)
+			return nil
+		})
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Len(t, results, 2, fmt.Sprintf("unexpected results: test case #%d", i))
+
+		for j, res := range results {
+			require.Equal(t, txs[j].Hash(), res.TxHash, fmt.Sprintf("unexpected hash: test case #%d", i))
+			require.Empty(t, res.Error, fmt.Sprintf("unexpected error: test case #%d", i))
+			require.IsType(t, tc.expectType, res.Result, fmt.Sprintf("unexpected result: test case #%d", i))
+		}
+
+		require.Equal(t, ethcmn.BigToHash(big.NewInt(42)), stateDB.GetState(testContract, ethcmn.BigToHash(big.NewInt(1))))
+	}
+}
+
+func TestTraceBlockStructLogger(t *testing.T) {
+	stateDB, txs := newTraceTestBlock(t)
+	config := TraceBlockConfig{ChainConfig: NewChainConfig(testTraceChainID), GasLimit: 1000000}
+
+	var results []TxTraceResult
+	err := TraceBlock(stateDB, config, txs[:1], func(res TxTraceResult) error {
+		results = append(results, res)
+		return nil
+	})
+	require.Nil(t, err)
+
+	res := results[0].Result.(ExecutionResult)
+	require.False(t, res.Failed)
+	require.Len(t, res.StructLogs, 4)
+	require.Equal(t, "SSTORE", res.StructLogs[2].Op)
+	require.Equal(t, "STOP", res.StructLogs[3].Op)
+}
+
+func TestTraceBlockErrors(t *testing.T) {
+	stateDB, txs := newTraceTestBlock(t)
+	config := TraceBlockConfig{ChainConfig: NewChainConfig(testTraceChainID), GasLimit: 1000000, Tracer: "unknown"}
+
+	// unsupported tracer
+	err := TraceBlock(stateDB, config, txs, func(TxTraceResult) error { return nil })
+	require.NotNil(t, err)
+
+	// invalid tracer config
+	config.Tracer = TracerCall
+	config.TracerConfig = json.RawMessage("invalid")
+
+	err = TraceBlock(stateDB, config, txs, func(TxTraceResult) error { return nil })
+	require.NotNil(t, err)
+
+	// a transaction signed for another chain cannot be executed but the rest
+	// of the block is still traced
+	config.Tracer = TracerTransfers
+	config.TracerConfig = nil
+	config.ChainConfig = NewChainConfig(big.NewInt(4))
+
+	var results []TxTraceResult
+	err = TraceBlock(stateDB, config, txs, func(res TxTraceResult) error {
+		results = append(results, res)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.NotEmpty(t, results[0].Error)
+	require.Nil(t, results[0].Result)
+
+	// tracing stops at the first error returned by emit
+	calls := 0
+	err = TraceBlock(stateDB, config, txs, func(TxTraceResult) error {
+		calls++
+		return errors.New("stop")
+	})
+	require.NotNil(t, err)
+	require.Equal(t, 1, calls)
+}
+
+// gasTracer is a custom tracer returning the gas used by a transaction.
+type gasTracer struct {
+	*ethvm.StructLogger
+}
+
+func (gasTracer) Result(exec TxExecution) (interface{}, error) {
+	return exec.GasUsed, nil
+}
+
+func TestRegisterTracer(t *testing.T) {
+	RegisterTracer("testGas", func(TracerContext, json.RawMessage) (TxTracer, error) {
+		return gasTracer{ethvm.NewStructLogger(nil)}, nil
+	})
+
+	require.Contains(t, Tracers(), "testGas")
+	require.Panics(t, func() { RegisterTracer("testGas", newStructLogTracer) })
+	require.Panics(t, func() { RegisterTracer(TracerCall, newStructLogTracer) })
+	require.Panics(t, func() { RegisterTracer("", newStructLogTracer) })
+
+	stateDB, txs := newTraceTestBlock(t)
+	config := TraceBlockConfig{ChainConfig: NewChainConfig(testTraceChainID), GasLimit: 1000000, Tracer: "testGas"}
+
+	var results []TxTraceResult
+	err := TraceBlock(stateDB, config, txs, func(res TxTraceResult) error {
+		results = append(results, res)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, uint64(21000), results[1].Result)
+}
+
+// deployCode returns EVM init code deploying a contract of the given size,
+// consisting of zero bytes.
+func deployCode(size uint16) []byte {
+	return []byte{
+		byte(ethvm.PUSH2), byte(size >> 8), byte(size),
+		byte(ethvm.PUSH1), 0x00,
+		byte(ethvm.RETURN),
+	}
+}
+
+func TestTraceBlockMaxCodeSize(t *testing.T) {
+	testCases := []struct {
+		size      uint16
+		expectErr bool
+	}{
+		{ethparams.MaxCodeSize - 1, false},
+		{ethparams.MaxCodeSize, false},
+		{ethparams.MaxCodeSize + 1, true},
+	}
+
+	for i, tc := range testCases {
+		priv, err := ethcrypto.GenerateKey()
+		require.Nil(t, err)
+
+		sender := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+		stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+		require.Nil(t, err)
+
+		stateDB.AddBalance(sender, big.NewInt(10000000))
+
+		tx := types.NewContractCreation(0, new(big.Int), 6000000, big.NewInt(1), deployCode(tc.size))
+		tx.Sign(testTraceChainID, priv)
+
+		config := TraceBlockConfig{ChainConfig: NewChainConfig(testTraceChainID), GasLimit: 6000000, Tracer: TracerCall}
+
+		var results []TxTraceResult
+		err = TraceBlock(stateDB, config, []*types.Transaction{tx}, func(res TxTraceResult) error {
+			results = append(results, res)
+			return nil
+		})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Empty(t, results[0].Error, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		root := results[0].Result.(*CallFrame)
+		contract := ethcrypto.CreateAddress(sender, 0)
+
+		if tc.expectErr {
+			require.Equal(t, "evm: max code size exceeded", root.Error, fmt.Sprintf("expected error: test case #%d", i))
+			require.Zero(t, stateDB.GetCodeSize(contract), fmt.Sprintf("unexpected code: test case #%d", i))
+
+			// all gas is consumed
+			require.Equal(t, root.Gas, root.GasUsed, fmt.Sprintf("unexpected gas used: test case #%d", i))
+			continue
+		}
+
+		require.Empty(t, root.Error, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, int(tc.size), stateDB.GetCodeSize(contract), fmt.Sprintf("unexpected code: test case #%d", i))
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+type (
+	// TxTracer defines an EVM tracer tracing a single transaction for
+	// TraceBlock. Once the transaction has been applied, the tracer returns a
+	// JSON serializable result.
+	TxTracer interface {
+		ethvm.Tracer
+
+		// Result returns the trace of the transaction given the outcome of
+		// applying it.
+		Result(exec TxExecution) (interface{}, error)
+	}
+
+	// TracerContext defines the transaction a TxTracer is constructed for.
+	// The tracer is constructed before the transaction is applied, so the state
+	// still reflects the state prior to the transaction (e.g. the sender has not
+	// yet paid for gas).
+	TracerContext struct {
+		StateDB  ethvm.StateDB
+		TxHash   ethcmn.Hash
+		From     ethcmn.Address
+		To       *ethcmn.Address
+		Coinbase ethcmn.Address
+	}
+
+	// TxExecution is the outcome of applying a traced transaction.
+	TxExecution struct {
+		Gas         uint64
+		GasUsed     uint64
+		Failed      bool
+		ReturnValue []byte
+	}
+
+	// TracerConstructor returns a new TxTracer for the transaction of the given
+	// context. The config is the raw, possibly empty, JSON configuration given
+	// by the caller of the trace and its format is defined by the tracer.
+	TracerConstructor func(ctx TracerContext, config json.RawMessage) (TxTracer, error)
+)
+
+var (
+	tracersMtx sync.RWMutex
+
+	// tracers maps the name of every tracer supported by TraceBlock to its
+	// constructor
+	tracers = map[string]TracerConstructor{
+		TracerStructLogger: newStructLogTracer,
+		TracerStateDiff:    newStateDiffTxTracer,
+		TracerTransfers:    newTransferTxTracer,
+		TracerCall:         newCallTxTracer,
+		TracerPrestate:     newPrestateTxTracer,
+	}
+)
+
+// RegisterTracer registers a custom tracer under the given name, making it
+// available to TraceBlock and hence the debug tracing RPC methods. This allows
+// a node to be built with tracers specific to an application without having
+// to modify Ethermint itself. It panics if the name is empty or already taken
+// and is meant to be called upon initialization (e.g. from an init function).
+func RegisterTracer(name string, constructor TracerConstructor) {
+	tracersMtx.Lock()
+	defer tracersMtx.Unlock()
+
+	if name == "" {
+		panic("tracer name cannot be empty")
+	}
+
+	if _, ok := tracers[name]; ok {
+		panic(fmt.Sprintf("tracer %s is already registered", name))
+	}
+
+	tracers[name] = constructor
+}
+
+// Tracers returns the sorted names of every tracer supported by TraceBlock.
+func Tracers() []string {
+	tracersMtx.RLock()
+	defer tracersMtx.RUnlock()
+
+	names := make([]string, 0, len(tracers))
+	for name := range tracers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// lookupTracer returns the constructor of the tracer with the given name.
+func lookupTracer(name string) (TracerConstructor, bool) {
+	tracersMtx.RLock()
+	defer tracersMtx.RUnlock()
+
+	constructor, ok := tracers[name]
+	return constructor, ok
+}
+
+// structLogTracer adapts the EVM's struct logger to a TxTracer.
+type structLogTracer struct {
+	*ethvm.StructLogger
+}
+
+// newStructLogTracer implements TracerConstructor. The config, if given,
+// holds the struct logger's LogConfig (e.g. {"disableMemory": true}).
+func newStructLogTracer(_ TracerContext, config json.RawMessage) (TxTracer, error) {
+	var logConfig ethvm.LogConfig
+	if len(config) != 0 {
+		if err := json.Unmarshal(config, &logConfig); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %v", TracerStructLogger, err)
+		}
+	}
+
+	// never let a remote caller make the node print traces to its output
+	logConfig.Debug = false
+
+	return structLogTracer{ethvm.NewStructLogger(&logConfig)}, nil
+}
+
+// Result implements the TxTracer interface.
+func (t structLogTracer) Result(exec TxExecution) (interface{}, error) {
+	return ExecutionResult{
+		Gas:         exec.GasUsed,
+		Failed:      exec.Failed,
+		ReturnValue: hexutil.Encode(exec.ReturnValue),
+		StructLogs:  formatStructLogs(t.StructLogs()),
+	}, nil
+}
+
+// stateDiffTxTracer adapts a StateDiffTracer to a TxTracer.
+type stateDiffTxTracer struct {
+	*StateDiffTracer
+
+	txHash ethcmn.Hash
+}
+
+// newStateDiffTxTracer implements TracerConstructor. The tracer takes no
+// config.
+func newStateDiffTxTracer(ctx TracerContext, _ json.RawMessage) (TxTracer, error) {
+	accounts := []ethcmn.Address{ctx.From, ctx.Coinbase}
+	if ctx.To != nil {
+		accounts = append(accounts, *ctx.To)
+	}
+
+	return stateDiffTxTracer{NewStateDiffTracer(ctx.StateDB, accounts...), ctx.TxHash}, nil
+}
+
+// Result implements the TxTracer interface.
+func (t stateDiffTxTracer) Result(_ TxExecution) (interface{}, error) {
+	return t.StateDiff(t.txHash), nil
+}
+
+// transferTxTracer adapts a TransferTracer to a TxTracer.
+type transferTxTracer struct {
+	*TransferTracer
+}
+
+// newTransferTxTracer implements TracerConstructor. The tracer takes no
+// config.
+func newTransferTxTracer(_ TracerContext, _ json.RawMessage) (TxTracer, error) {
+	return transferTxTracer{NewTransferTracer()}, nil
+}
+
+// Result implements the TxTracer interface.
+func (t transferTxTracer) Result(exec TxExecution) (interface{}, error) {
+	return TransfersResult{Gas: exec.GasUsed, Failed: exec.Failed, Transfers: t.Transfers()}, nil
+}
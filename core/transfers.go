@@ -0,0 +1,240 @@
+package core
+
+import (
+	"math/big"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tags emitted for every internal value transfer recorded by a
+// TransferTracer.
+const (
+	TagInternalFrom  = "internal.from"
+	TagInternalTo    = "internal.to"
+	TagInternalValue = "internal.value"
+)
+
+// Internal transfer types reflecting the EVM operation that caused a transfer
+// of value.
+const (
+	TransferTypeCall         = "call"
+	TransferTypeCreate       = "create"
+	TransferTypeSelfDestruct = "selfdestruct"
+)
+
+type (
+	// InternalTransfer represents a transfer of value between two accounts that
+	// occurred as a result of a message call, contract creation or self-destruct
+	// during EVM execution. The top-level transfer of a transaction itself is
+	// not considered an internal transfer.
+	InternalTransfer struct {
+		Type  string
+		From  ethcmn.Address
+		To    ethcmn.Address
+		Value *big.Int
+		Depth int
+	}
+
+	// TransferTracer implements Ethereum's vm.Tracer interface. It records a
+	// summary of all internal value transfers that were successfully executed
+	// during a single transaction. Transfers made within a call frame that
+	// fails or reverts are discarded along with the frame.
+	TransferTracer struct {
+		frames    []transferFrame
+		transfers []InternalTransfer
+	}
+
+	// transferFrame contains the transfers made within a single call frame
+	// that was entered at a given depth.
+	transferFrame struct {
+		depth     int
+		transfers []InternalTransfer
+	}
+)
+
+// NewTransferTracer returns a reference to a new TransferTracer.
+func NewTransferTracer() *TransferTracer {
+	return &TransferTracer{}
+}
+
+// CaptureStart implements Ethereum's vm.Tracer interface. It resets any
+// previously recorded transfers.
+func (tt *TransferTracer) CaptureStart(_, _ ethcmn.Address, _ bool, _ []byte, _ uint64, _ *big.Int) error {
+	tt.frames = []transferFrame{{}}
+	tt.transfers = nil
+
+	return nil
+}
+
+// CaptureState implements Ethereum's vm.Tracer interface. It is invoked prior
+// to the execution of every opcode. Any call frame that has returned is first
+// resolved by inspecting the success flag the call left on the stack, after
+// which a new frame is opened for any message call or contract creation.
+func (tt *TransferTracer) CaptureState(
+	env *ethvm.EVM, _ uint64, op ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, stack *ethvm.Stack, contract *ethvm.Contract, depth int, err error,
+) error {
+	if err != nil {
+		return nil
+	}
+
+	tt.resolveFrames(depth, stack)
+
+	switch op {
+	case ethvm.CALL:
+		transfer := InternalTransfer{
+			Type:  TransferTypeCall,
+			From:  contract.Address(),
+			To:    ethcmn.BigToAddress(stack.Back(1)),
+			Value: new(big.Int).Set(stack.Back(2)),
+			Depth: depth,
+		}
+
+		tt.openFrame(depth, transfer)
+
+	case ethvm.CREATE:
+		caller := contract.Address()
+		transfer := InternalTransfer{
+			Type:  TransferTypeCreate,
+			From:  caller,
+			To:    ethcrypto.CreateAddress(caller, env.StateDB.GetNonce(caller)),
+			Value: new(big.Int).Set(stack.Back(0)),
+			Depth: depth,
+		}
+
+		tt.openFrame(depth, transfer)
+
+	case ethvm.CALLCODE, ethvm.DELEGATECALL, ethvm.STATICCALL:
+		// These calls never move value to another account, but a frame is still
+		// needed so that transfers made by the callee are discarded if it fails.
+		tt.openFrame(depth, InternalTransfer{})
+
+	case ethvm.SELFDESTRUCT:
+		transfer := InternalTransfer{
+			Type:  TransferTypeSelfDestruct,
+			From:  contract.Address(),
+			To:    ethcmn.BigToAddress(stack.Back(0)),
+			Value: new(big.Int).Set(env.StateDB.GetBalance(contract.Address())),
+			Depth: depth,
+		}
+
+		tt.record(transfer)
+	}
+
+	return nil
+}
+
+// CaptureFault implements Ethereum's vm.Tracer interface. It performs a no-op
+// as a failing frame is detected by its caller through the call's result.
+func (tt *TransferTracer) CaptureFault(
+	_ *ethvm.EVM, _ uint64, _ ethvm.OpCode, _, _ uint64,
+	_ *ethvm.Memory, _ *ethvm.Stack, _ *ethvm.Contract, _ int, _ error,
+) error {
+	return nil
+}
+
+// CaptureEnd implements Ethereum's vm.Tracer interface. If the transaction
+// failed, all recorded transfers are discarded as their state changes were
+// reverted. Otherwise, any frames still open are resolved as successful.
+func (tt *TransferTracer) CaptureEnd(_ []byte, _ uint64, _ time.Duration, err error) error {
+	if err != nil {
+		tt.frames = nil
+		tt.transfers = nil
+		return nil
+	}
+
+	for len(tt.frames) > 1 {
+		tt.closeFrame(true)
+	}
+
+	if len(tt.frames) == 1 {
+		tt.transfers = tt.frames[0].transfers
+	}
+
+	tt.frames = nil
+	return nil
+}
+
+// Transfers returns all the internal value transfers recorded during the
+// last traced transaction in the order in which they were executed.
+func (tt *TransferTracer) Transfers() []InternalTransfer {
+	return tt.transfers
+}
+
+// Tags returns a set of tags for each recorded internal value transfer which
+// may be used for transaction indexing.
+func (tt *TransferTracer) Tags() sdk.Tags {
+	tags := sdk.EmptyTags()
+
+	for _, t := range tt.transfers {
+		tags = tags.AppendTag(TagInternalFrom, []byte(t.From.Hex()))
+		tags = tags.AppendTag(TagInternalTo, []byte(t.To.Hex()))
+		tags = tags.AppendTag(TagInternalValue, []byte(t.Value.String()))
+	}
+
+	return tags
+}
+
+// openFrame opens a new call frame for a call issued at a given depth. The
+// given transfer is recorded within the new frame if it moves any value.
+func (tt *TransferTracer) openFrame(depth int, transfer InternalTransfer) {
+	frame := transferFrame{depth: depth}
+
+	if transfer.Value != nil && transfer.Value.Sign() > 0 {
+		frame.transfers = append(frame.transfers, transfer)
+	}
+
+	tt.frames = append(tt.frames, frame)
+}
+
+// closeFrame closes the current call frame. If the call succeeded, all of its
+// transfers are merged into the parent frame, otherwise they are discarded.
+func (tt *TransferTracer) closeFrame(success bool) {
+	n := len(tt.frames)
+	frame := tt.frames[n-1]
+	tt.frames = tt.frames[:n-1]
+
+	if success {
+		parent := &tt.frames[n-2]
+		parent.transfers = append(parent.transfers, frame.transfers...)
+	}
+}
+
+// resolveFrames closes all call frames that have returned given the current
+// execution depth. When execution resumes at the depth a call was issued, the
+// call's success flag is on top of the stack.
+//
+// NOTE: If the caller's frame ends immediately after a call returns, the
+// success flag is never observed and the call is treated as successful.
+func (tt *TransferTracer) resolveFrames(depth int, stack *ethvm.Stack) {
+	for len(tt.frames) > 1 {
+		frame := tt.frames[len(tt.frames)-1]
+
+		switch {
+		case frame.depth > depth:
+			tt.closeFrame(true)
+
+		case frame.depth == depth:
+			tt.closeFrame(len(stack.Data()) > 0 && stack.Back(0).Sign() != 0)
+
+		default:
+			return
+		}
+	}
+}
+
+// record records a transfer within the current call frame if it moves any
+// value.
+func (tt *TransferTracer) record(transfer InternalTransfer) {
+	if len(tt.frames) == 0 || transfer.Value.Sign() <= 0 {
+		return
+	}
+
+	frame := &tt.frames[len(tt.frames)-1]
+	frame.transfers = append(frame.transfers, transfer)
+}
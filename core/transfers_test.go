@@ -0,0 +1,94 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethdb "github.com/ethereum/go-ethereum/ethdb"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testCaller    = ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	testContract  = ethcmn.HexToAddress("0x2000000000000000000000000000000000000002")
+	testRecipient = ethcmn.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// callWithValueCode returns EVM byte code that performs a CALL to the given
+// recipient transferring the given value (< 256) and then stops.
+func callWithValueCode(to ethcmn.Address, value byte) []byte {
+	code := []byte{
+		byte(ethvm.PUSH1), 0x00, // retSize
+		byte(ethvm.PUSH1), 0x00, // retOffset
+		byte(ethvm.PUSH1), 0x00, // argsSize
+		byte(ethvm.PUSH1), 0x00, // argsOffset
+		byte(ethvm.PUSH1), value, // value
+		byte(ethvm.PUSH20),
+	}
+
+	code = append(code, to.Bytes()...)
+	return append(code, byte(ethvm.GAS), byte(ethvm.CALL), byte(ethvm.STOP))
+}
+
+func runTransferTracer(t *testing.T, code []byte, balance int64) *TransferTracer {
+	stateDB, err := ethstate.New(ethcmn.Hash{}, ethstate.NewDatabase(ethdb.NewMemDatabase()))
+	require.Nil(t, err)
+
+	stateDB.SetCode(testContract, code)
+	stateDB.AddBalance(testContract, big.NewInt(balance))
+
+	tracer := NewTransferTracer()
+	ctx := ethvm.Context{
+		CanTransfer: ethcore.CanTransfer,
+		Transfer:    ethcore.Transfer,
+		BlockNumber: big.NewInt(1),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    1000000,
+		GasPrice:    big.NewInt(1),
+	}
+
+	evm := ethvm.NewEVM(ctx, stateDB, ethparams.AllEthashProtocolChanges, ethvm.Config{Debug: true, Tracer: tracer})
+
+	_, _, err = evm.Call(ethvm.AccountRef(testCaller), testContract, nil, 1000000, new(big.Int))
+	require.Nil(t, err)
+
+	return tracer
+}
+
+func TestTransferTracerInterface(t *testing.T) {
+	require.Implements(t, (*ethvm.Tracer)(nil), new(TransferTracer))
+}
+
+func TestTransferTracerCall(t *testing.T) {
+	tracer := runTransferTracer(t, callWithValueCode(testRecipient, 5), 10)
+
+	transfers := tracer.Transfers()
+	require.Len(t, transfers, 1)
+	require.Equal(t, TransferTypeCall, transfers[0].Type)
+	require.Equal(t, testContract, transfers[0].From)
+	require.Equal(t, testRecipient, transfers[0].To)
+	require.Equal(t, big.NewInt(5), transfers[0].Value)
+	require.Equal(t, 1, transfers[0].Depth)
+
+	tags := tracer.Tags()
+	require.Len(t, tags, 3)
+	require.Equal(t, []byte(testRecipient.Hex()), tags[1].Value)
+}
+
+func TestTransferTracerFailedCall(t *testing.T) {
+	// the contract does not have enough funds so the call must fail
+	tracer := runTransferTracer(t, callWithValueCode(testRecipient, 5), 1)
+	require.Empty(t, tracer.Transfers())
+	require.Empty(t, tracer.Tags())
+}
+
+func TestTransferTracerZeroValueCall(t *testing.T) {
+	tracer := runTransferTracer(t, callWithValueCode(testRecipient, 0), 10)
+	require.Empty(t, tracer.Transfers())
+}
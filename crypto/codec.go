@@ -0,0 +1,21 @@
+package crypto
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+var cryptoCodec = wire.NewCodec()
+
+func init() {
+	wire.RegisterCrypto(cryptoCodec)
+	RegisterWire(cryptoCodec)
+}
+
+// RegisterWire registers the Ethereum secp256k1 key and signature types on a
+// wire codec. The Tendermint crypto interfaces must be registered with the
+// codec beforehand (e.g. through wire.RegisterCrypto).
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(PubKeySecp256k1{}, PubKeyAminoName, nil)
+	cdc.RegisterConcrete(PrivKeySecp256k1{}, PrivKeyAminoName, nil)
+	cdc.RegisterConcrete(SignatureSecp256k1{}, SignatureAminoName, nil)
+}
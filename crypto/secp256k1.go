@@ -0,0 +1,169 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/subtle"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+const (
+	// PubKeyAminoName defines the amino route of an Ethereum secp256k1 public
+	// key.
+	PubKeyAminoName = "ethermint/PubKeySecp256k1"
+
+	// PrivKeyAminoName defines the amino route of an Ethereum secp256k1
+	// private key.
+	PrivKeyAminoName = "ethermint/PrivKeySecp256k1"
+
+	// SignatureAminoName defines the amino route of an Ethereum secp256k1
+	// signature.
+	SignatureAminoName = "ethermint/SignatureSecp256k1"
+)
+
+var (
+	_ tmcrypto.PrivKey   = PrivKeySecp256k1{}
+	_ tmcrypto.PubKey    = PubKeySecp256k1{}
+	_ tmcrypto.Signature = SignatureSecp256k1{}
+)
+
+// ----------------------------------------------------------------------------
+// secp256k1 Private Key
+
+// PrivKeySecp256k1 defines a type alias for an ecdsa.PrivateKey that
+// implements Tendermint's PrivKey interface. Signatures are produced over the
+// keccak256 hash of a message as in Ethereum.
+type PrivKeySecp256k1 []byte
+
+// GenerateKey generates a new random secp256k1 private key. It returns an
+// error upon failure.
+func GenerateKey() (PrivKeySecp256k1, error) {
+	priv, err := ethcrypto.GenerateKey()
+	if err != nil {
+		return PrivKeySecp256k1{}, err
+	}
+
+	return PrivKeySecp256k1(ethcrypto.FromECDSA(priv)), nil
+}
+
+// PubKey returns the ECDSA private key's public key in its compressed form.
+func (privKey PrivKeySecp256k1) PubKey() tmcrypto.PubKey {
+	ecdsaPKey := privKey.ToECDSA()
+	return PubKeySecp256k1(ethcrypto.CompressPubkey(&ecdsaPKey.PublicKey))
+}
+
+// Bytes returns the amino encoded private key.
+func (privKey PrivKeySecp256k1) Bytes() []byte {
+	return cryptoCodec.MustMarshalBinaryBare(privKey)
+}
+
+// Equals returns true if two ECDSA private keys are equal and false otherwise.
+func (privKey PrivKeySecp256k1) Equals(other tmcrypto.PrivKey) bool {
+	if other, ok := other.(PrivKeySecp256k1); ok {
+		return subtle.ConstantTimeCompare(privKey, other) == 1
+	}
+
+	return false
+}
+
+// Sign creates a recoverable ECDSA signature on the secp256k1 curve over the
+// keccak256 hash of the provided message. The signature is of the
+// [R || S || V] format where V is 0 or 1.
+func (privKey PrivKeySecp256k1) Sign(msg []byte) (tmcrypto.Signature, error) {
+	sig, err := ethcrypto.Sign(ethcrypto.Keccak256(msg), privKey.ToECDSA())
+	if err != nil {
+		return nil, err
+	}
+
+	return SignatureSecp256k1(sig), nil
+}
+
+// ToECDSA returns the ECDSA private key as a reference to ecdsa.PrivateKey
+// type. It panics if the private key is invalid.
+func (privKey PrivKeySecp256k1) ToECDSA() *ecdsa.PrivateKey {
+	key, err := ethcrypto.ToECDSA(privKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// ----------------------------------------------------------------------------
+// secp256k1 Public Key
+
+// PubKeySecp256k1 defines a type alias for a compressed ecdsa.PublicKey that
+// implements Tendermint's PubKey interface.
+type PubKeySecp256k1 []byte
+
+// Address returns the address of the ECDSA public key. The address is
+// derived from the keccak256 hash of the uncompressed public key as in
+// Ethereum. An empty address is returned if the public key is invalid.
+func (key PubKeySecp256k1) Address() tmcrypto.Address {
+	pubk, err := ethcrypto.DecompressPubkey(key)
+	if err != nil {
+		return tmcrypto.Address{}
+	}
+
+	return tmcrypto.Address(ethcrypto.PubkeyToAddress(*pubk).Bytes())
+}
+
+// Bytes returns the amino encoded public key.
+func (key PubKeySecp256k1) Bytes() []byte {
+	return cryptoCodec.MustMarshalBinaryBare(key)
+}
+
+// VerifyBytes verifies that the ECDSA public key created a given signature
+// over the keccak256 hash of the provided message. The signature may be of
+// the [R || S || V] or [R || S] format.
+func (key PubKeySecp256k1) VerifyBytes(msg []byte, sig tmcrypto.Signature) bool {
+	secpSig, ok := sig.(SignatureSecp256k1)
+	if !ok {
+		return false
+	}
+
+	if len(secpSig) == 65 {
+		// remove the recovery ID
+		secpSig = secpSig[:64]
+	}
+
+	return ethcrypto.VerifySignature(key, ethcrypto.Keccak256(msg), secpSig)
+}
+
+// Equals returns true if two ECDSA public keys are equal and false otherwise.
+func (key PubKeySecp256k1) Equals(other tmcrypto.PubKey) bool {
+	if other, ok := other.(PubKeySecp256k1); ok {
+		return bytes.Equal(key, other)
+	}
+
+	return false
+}
+
+// ----------------------------------------------------------------------------
+// secp256k1 Signature
+
+// SignatureSecp256k1 defines a type alias for a recoverable ECDSA signature
+// on the secp256k1 curve that implements Tendermint's Signature interface.
+type SignatureSecp256k1 []byte
+
+// Bytes returns the amino encoded signature.
+func (sig SignatureSecp256k1) Bytes() []byte {
+	return cryptoCodec.MustMarshalBinaryBare(sig)
+}
+
+// IsZero returns true if the signature is empty.
+func (sig SignatureSecp256k1) IsZero() bool {
+	return len(sig) == 0
+}
+
+// Equals returns true if two signatures are equal and false otherwise.
+func (sig SignatureSecp256k1) Equals(other tmcrypto.Signature) bool {
+	if other, ok := other.(SignatureSecp256k1); ok {
+		return bytes.Equal(sig, other)
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package crypto
+
+import (
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/stretchr/testify/require"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+func TestPrivKeySecp256k1PubKey(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.Nil(t, err)
+
+	pubKey := privKey.PubKey()
+	require.IsType(t, PubKeySecp256k1{}, pubKey)
+
+	// the address must match the Ethereum address of the key
+	expectedAddr := ethcrypto.PubkeyToAddress(privKey.ToECDSA().PublicKey)
+	require.Equal(t, expectedAddr.Bytes(), pubKey.Address().Bytes())
+
+	require.True(t, privKey.Equals(privKey))
+	require.True(t, pubKey.Equals(privKey.PubKey()))
+
+	otherKey, err := GenerateKey()
+	require.Nil(t, err)
+	require.False(t, privKey.Equals(otherKey))
+	require.False(t, pubKey.Equals(otherKey.PubKey()))
+	require.False(t, privKey.Equals(tmcrypto.GenPrivKeySecp256k1()))
+}
+
+func TestPrivKeySecp256k1Sign(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.Nil(t, err)
+
+	msg := []byte("hello world")
+
+	sig, err := privKey.Sign(msg)
+	require.Nil(t, err)
+	require.Len(t, sig.(SignatureSecp256k1), 65)
+	require.False(t, sig.IsZero())
+
+	pubKey := privKey.PubKey()
+	require.True(t, pubKey.VerifyBytes(msg, sig))
+	require.True(t, pubKey.VerifyBytes(msg, sig.(SignatureSecp256k1)[:64]))
+	require.False(t, pubKey.VerifyBytes([]byte("other msg"), sig))
+
+	// the signature must be recoverable to the signer's Ethereum public key
+	recoveredKey, err := ethcrypto.SigToPub(ethcrypto.Keccak256(msg), sig.(SignatureSecp256k1))
+	require.Nil(t, err)
+	require.Equal(t, privKey.ToECDSA().PublicKey, *recoveredKey)
+
+	otherKey, err := GenerateKey()
+	require.Nil(t, err)
+	require.False(t, otherKey.PubKey().VerifyBytes(msg, sig))
+
+	// signatures of other types are rejected
+	require.False(t, pubKey.VerifyBytes(msg, tmcrypto.SignatureSecp256k1(sig.(SignatureSecp256k1))))
+}
+
+func TestSecp256k1Amino(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.Nil(t, err)
+
+	var decodedPrivKey tmcrypto.PrivKey
+	require.Nil(t, cryptoCodec.UnmarshalBinaryBare(privKey.Bytes(), &decodedPrivKey))
+	require.Equal(t, privKey, decodedPrivKey)
+
+	var decodedPubKey tmcrypto.PubKey
+	require.Nil(t, cryptoCodec.UnmarshalBinaryBare(privKey.PubKey().Bytes(), &decodedPubKey))
+	require.Equal(t, privKey.PubKey(), decodedPubKey)
+
+	sig, err := privKey.Sign([]byte("msg"))
+	require.Nil(t, err)
+
+	var decodedSig tmcrypto.Signature
+	require.Nil(t, cryptoCodec.UnmarshalBinaryBare(sig.Bytes(), &decodedSig))
+	require.Equal(t, sig, decodedSig)
+}
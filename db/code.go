@@ -0,0 +1,61 @@
+package db
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+var emptyCodeHash = ethcrypto.Keccak256(nil)
+
+// CodeMapper stores the code of contracts in a KVStore, alongside the
+// auth.AccountMapper holding their accounts. Code is keyed by its hash, as in
+// a state.Database's code store, and recorded on a types.Account by its hash
+// only, so that code deployed by many contracts is stored once.
+//
+// NOTE: Code is never deleted, as it may be shared by other contracts.
+type CodeMapper struct {
+	key sdk.StoreKey
+}
+
+// NewCodeMapper returns a new CodeMapper storing contract code in the store of
+// the given key.
+func NewCodeMapper(key sdk.StoreKey) CodeMapper {
+	return CodeMapper{key: key}
+}
+
+// GetCode returns the code of the given hash, or nil if it is the hash of
+// empty code or no such code is stored.
+func (cm CodeMapper) GetCode(ctx sdk.Context, codeHash []byte) []byte {
+	if len(codeHash) == 0 || bytes.Equal(codeHash, emptyCodeHash) {
+		return nil
+	}
+
+	return ctx.KVStore(cm.key).Get(codeHash)
+}
+
+// GetAccountCode returns the code of the given account, or nil if it has
+// none.
+func (cm CodeMapper) GetAccountCode(ctx sdk.Context, acc *types.Account) []byte {
+	return cm.GetCode(ctx, acc.CodeHash)
+}
+
+// SetAccountCode stores the given code, unless code of the same hash is
+// already stored, and records its hash on the given account, which must be
+// set by the caller. Setting empty code clears the account's code hash.
+func (cm CodeMapper) SetAccountCode(ctx sdk.Context, acc *types.Account, code []byte) {
+	if len(code) == 0 {
+		acc.CodeHash = nil
+		return
+	}
+
+	acc.CodeHash = ethcrypto.Keccak256(code)
+
+	store := ctx.KVStore(cm.key)
+	if !store.Has(acc.CodeHash) {
+		store.Set(acc.CodeHash, code)
+	}
+}
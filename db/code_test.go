@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeMapper(t *testing.T) {
+	tsdb := newTestStateDB(t)
+	cm := NewCodeMapper(tsdb.codeKey)
+	store := tsdb.ctx.KVStore(tsdb.codeKey)
+
+	code := []byte("code")
+	hash := ethcrypto.Keccak256(code)
+
+	// contracts with the same code share a single copy of it
+	accs := make([]*types.Account, 2)
+	for i := range accs {
+		accs[i] = tsdb.am.NewAccountWithAddress(tsdb.ctx, ethcmn.BytesToAddress([]byte{byte(i + 1)}).Bytes()).(*types.Account)
+		cm.SetAccountCode(tsdb.ctx, accs[i], code)
+
+		require.Equal(t, hash, accs[i].CodeHash)
+		require.Equal(t, code, cm.GetAccountCode(tsdb.ctx, accs[i]))
+	}
+
+	var stored [][]byte
+	iter := store.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		stored = append(stored, iter.Key())
+	}
+	iter.Close()
+
+	require.Equal(t, [][]byte{hash}, stored)
+	require.Equal(t, code, cm.GetCode(tsdb.ctx, hash))
+
+	// clearing the code of a contract retains that of others
+	cm.SetAccountCode(tsdb.ctx, accs[0], nil)
+	require.Nil(t, accs[0].CodeHash)
+	require.Nil(t, cm.GetAccountCode(tsdb.ctx, accs[0]))
+	require.Equal(t, code, cm.GetAccountCode(tsdb.ctx, accs[1]))
+
+	require.Nil(t, cm.GetCode(tsdb.ctx, emptyCodeHash))
+	require.Nil(t, cm.GetCode(tsdb.ctx, ethcrypto.Keccak256([]byte("unknown"))))
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// KeySize is the size, in bytes, of the AES-256 keys encrypting an
+// EncryptedDB.
+const KeySize = 32
+
+// KeySource defines a function returning the key encrypting an EncryptedDB,
+// e.g. by fetching it from a key management service.
+type KeySource func() ([]byte, error)
+
+// EnvKey returns a KeySource reading a hex encoded key from the environment
+// variable with the given name.
+func EnvKey(name string) KeySource {
+	return func() ([]byte, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		key, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key in %s: %v", name, err)
+		}
+
+		return key, nil
+	}
+}
+
+var _ dbm.DB = (*EncryptedDB)(nil)
+
+// EncryptedDB implements Tendermint's db.DB interface. It wraps a database so
+// that every value is encrypted at rest using AES-256-GCM, e.g. for permissioned
+// chains handling sensitive data.
+//
+// Keys are stored in plaintext so that iteration order is preserved. As the
+// IAVL stores backing the application persist their nodes under hashes, the
+// keys and values of the application's stores are both encrypted. Every value
+// is authenticated along with its key, so values cannot be swapped between
+// keys unnoticed.
+//
+// As the db.DB interface cannot return errors, reading a value that fails to
+// decrypt, e.g. due to a wrong key or tampering, panics.
+type EncryptedDB struct {
+	db   dbm.DB
+	aead cipher.AEAD
+}
+
+// NewEncryptedDB returns a reference to a new EncryptedDB wrapping the given
+// database with the key returned by the given KeySource.
+func NewEncryptedDB(db dbm.DB, source KeySource) (*EncryptedDB, error) {
+	key, err := source()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid encryption key size %d; expected %d", len(key), KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedDB{db: db, aead: aead}, nil
+}
+
+// Get implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Get(key []byte) []byte {
+	return edb.decrypt(key, edb.db.Get(key))
+}
+
+// Has implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Has(key []byte) bool {
+	return edb.db.Has(key)
+}
+
+// Set implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Set(key, value []byte) {
+	edb.db.Set(key, edb.encrypt(key, value))
+}
+
+// SetSync implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) SetSync(key, value []byte) {
+	edb.db.SetSync(key, edb.encrypt(key, value))
+}
+
+// Delete implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Delete(key []byte) {
+	edb.db.Delete(key)
+}
+
+// DeleteSync implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) DeleteSync(key []byte) {
+	edb.db.DeleteSync(key)
+}
+
+// Iterator implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Iterator(start, end []byte) dbm.Iterator {
+	return &encryptedIterator{Iterator: edb.db.Iterator(start, end), edb: edb}
+}
+
+// ReverseIterator implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	return &encryptedIterator{Iterator: edb.db.ReverseIterator(start, end), edb: edb}
+}
+
+// Close implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Close() {
+	edb.db.Close()
+}
+
+// NewBatch implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) NewBatch() dbm.Batch {
+	return &encryptedBatch{Batch: edb.db.NewBatch(), edb: edb}
+}
+
+// Print implements Tendermint's db.DB interface. The encrypted values are
+// printed.
+func (edb *EncryptedDB) Print() {
+	edb.db.Print()
+}
+
+// Stats implements Tendermint's db.DB interface.
+func (edb *EncryptedDB) Stats() map[string]string {
+	return edb.db.Stats()
+}
+
+// encrypt returns the given value encrypted and authenticated along with its
+// key, prefixed by a random nonce.
+func (edb *EncryptedDB) encrypt(key, value []byte) []byte {
+	nonce := make([]byte, edb.aead.NonceSize(), edb.aead.NonceSize()+len(value)+edb.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic(fmt.Sprintf("failed to generate nonce: %v", err))
+	}
+
+	return edb.aead.Seal(nonce, nonce, value, key)
+}
+
+// decrypt returns the plaintext of a value encrypted under the given key. A
+// nil value is returned as is.
+func (edb *EncryptedDB) decrypt(key, ciphertext []byte) []byte {
+	if ciphertext == nil {
+		return nil
+	}
+
+	nonceSize := edb.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		panic(fmt.Sprintf("failed to decrypt value of key %X: ciphertext too short", key))
+	}
+
+	value, err := edb.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], key)
+	if err != nil {
+		panic(fmt.Sprintf("failed to decrypt value of key %X: %v", key, err))
+	}
+
+	// an empty value is returned as such rather than nil, which denotes a
+	// missing key
+	if value == nil {
+		value = []byte{}
+	}
+
+	return value
+}
+
+// encryptedIterator wraps an iterator over an EncryptedDB's underlying
+// database, decrypting every value.
+type encryptedIterator struct {
+	dbm.Iterator
+	edb *EncryptedDB
+}
+
+// Value implements Tendermint's db.Iterator interface.
+func (it *encryptedIterator) Value() []byte {
+	return it.edb.decrypt(it.Key(), it.Iterator.Value())
+}
+
+// encryptedBatch wraps a batch of an EncryptedDB's underlying database,
+// encrypting every value set.
+type encryptedBatch struct {
+	dbm.Batch
+	edb *EncryptedDB
+}
+
+// Set implements Tendermint's db.SetDeleter interface.
+func (b *encryptedBatch) Set(key, value []byte) {
+	b.Batch.Set(key, b.edb.encrypt(key, value))
+}
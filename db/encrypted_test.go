@@ -0,0 +1,118 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func staticKey(b byte) KeySource {
+	return func() ([]byte, error) { return bytes.Repeat([]byte{b}, KeySize), nil }
+}
+
+func TestEncryptedDB(t *testing.T) {
+	memDB := dbm.NewMemDB()
+
+	edb, err := NewEncryptedDB(memDB, staticKey(0x01))
+	require.Nil(t, err)
+
+	edb.Set([]byte("a"), []byte("alice"))
+	edb.SetSync([]byte("b"), []byte("bob"))
+	edb.Set([]byte("c"), []byte{})
+
+	batch := edb.NewBatch()
+	batch.Set([]byte("d"), []byte("dave"))
+	batch.Delete([]byte("b"))
+	batch.Write()
+
+	testCases := []struct {
+		key      string
+		expected []byte
+	}{
+		{"a", []byte("alice")},
+		{"b", nil},
+		{"c", []byte{}},
+		{"d", []byte("dave")},
+		{"e", nil},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, edb.Get([]byte(tc.key)), fmt.Sprintf("unexpected value: test case #%d", i))
+		require.Equal(t, tc.expected != nil, edb.Has([]byte(tc.key)), fmt.Sprintf("unexpected has: test case #%d", i))
+
+		// values are not stored in plaintext
+		if len(tc.expected) > 0 {
+			stored := memDB.Get([]byte(tc.key))
+			require.False(t, bytes.Contains(stored, tc.expected), fmt.Sprintf("plaintext value: test case #%d", i))
+		}
+	}
+
+	var keys, values []string
+	for it := edb.Iterator(nil, nil); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+		values = append(values, string(it.Value()))
+	}
+
+	require.Equal(t, []string{"a", "c", "d"}, keys)
+	require.Equal(t, []string{"alice", "", "dave"}, values)
+
+	it := edb.ReverseIterator(nil, nil)
+	require.Equal(t, []byte("dave"), it.Value())
+	it.Close()
+
+	edb.Delete([]byte("a"))
+	require.Nil(t, edb.Get([]byte("a")))
+}
+
+func TestEncryptedDBTampering(t *testing.T) {
+	memDB := dbm.NewMemDB()
+
+	edb, err := NewEncryptedDB(memDB, staticKey(0x01))
+	require.Nil(t, err)
+
+	edb.Set([]byte("a"), []byte("alice"))
+
+	// a wrong key cannot decrypt values
+	other, err := NewEncryptedDB(memDB, staticKey(0x02))
+	require.Nil(t, err)
+	require.Panics(t, func() { other.Get([]byte("a")) })
+
+	// a value cannot be moved to another key
+	memDB.Set([]byte("b"), memDB.Get([]byte("a")))
+	require.Panics(t, func() { edb.Get([]byte("b")) })
+
+	memDB.Set([]byte("c"), []byte{0x01})
+	require.Panics(t, func() { edb.Get([]byte("c")) })
+}
+
+func TestNewEncryptedDBInvalidKey(t *testing.T) {
+	_, err := NewEncryptedDB(dbm.NewMemDB(), func() ([]byte, error) { return make([]byte, 16), nil })
+	require.NotNil(t, err)
+
+	_, err = NewEncryptedDB(dbm.NewMemDB(), func() ([]byte, error) { return nil, fmt.Errorf("unavailable") })
+	require.NotNil(t, err)
+}
+
+func TestEnvKey(t *testing.T) {
+	const name = "ETHERMINT_TEST_DB_KEY"
+	defer os.Unsetenv(name)
+
+	_, err := EnvKey(name)()
+	require.NotNil(t, err)
+
+	require.Nil(t, os.Setenv(name, "0x"+fmt.Sprintf("%064x", 1)))
+
+	key, err := EnvKey(name)()
+	require.Nil(t, err)
+	require.Len(t, key, KeySize)
+	require.Equal(t, byte(1), key[KeySize-1])
+
+	require.Nil(t, os.Setenv(name, "not hex"))
+
+	_, err = EnvKey(name)()
+	require.NotNil(t, err)
+}
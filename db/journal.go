@@ -0,0 +1,116 @@
+package db
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// journalEntry defines a change to the state of a CommitStateDB that can be
+// reverted.
+type journalEntry interface {
+	revert(csdb *CommitStateDB)
+}
+
+// journal records the changes made to the state of a CommitStateDB since its
+// last commit, in order, so that they can be reverted to any snapshot.
+type journal struct {
+	entries []journalEntry
+}
+
+// append records the given change.
+func (j *journal) append(entry journalEntry) {
+	j.entries = append(j.entries, entry)
+}
+
+// length returns the number of changes recorded.
+func (j *journal) length() int {
+	return len(j.entries)
+}
+
+// revert reverts, in reverse order, every change recorded after the given
+// number of changes and discards them.
+func (j *journal) revert(csdb *CommitStateDB, length int) {
+	for i := len(j.entries) - 1; i >= length; i-- {
+		j.entries[i].revert(csdb)
+	}
+
+	j.entries = j.entries[:length]
+}
+
+// storeChange records a write to a key of a store, along with the key's value
+// prior to the write or nil if it was not set.
+type storeChange struct {
+	store sdk.KVStore
+	key   []byte
+	prev  []byte
+}
+
+func (ch storeChange) revert(*CommitStateDB) {
+	if ch.prev == nil {
+		ch.store.Delete(ch.key)
+		return
+	}
+
+	ch.store.Set(ch.key, ch.prev)
+}
+
+// suicideChange records that an account self-destructed.
+type suicideChange struct {
+	addr ethcmn.Address
+}
+
+func (ch suicideChange) revert(csdb *CommitStateDB) {
+	delete(csdb.suicided, ch.addr)
+}
+
+// journalMultiStore implements a MultiStore serving the stores of a cache
+// context so that every write to them, including those made through an
+// auth.AccountMapper, is recorded by a journal. Recording the prior value of a
+// key consumes no gas.
+//
+// NOTE: Only GetKVStore and GetKVStoreWithGas are supported as the
+// multi-store is only ever accessed through a context's KVStore method.
+// Writes through a cache or prefix of its stores are not recorded.
+type journalMultiStore struct {
+	sdk.MultiStore
+
+	cacheCtx sdk.Context
+	journal  *journal
+}
+
+// GetKVStore implements the MultiStore interface.
+func (jms journalMultiStore) GetKVStore(key sdk.StoreKey) sdk.KVStore {
+	return jms.GetKVStoreWithGas(sdk.NewInfiniteGasMeter(), key)
+}
+
+// GetKVStoreWithGas implements the MultiStore interface.
+func (jms journalMultiStore) GetKVStoreWithGas(meter sdk.GasMeter, key sdk.StoreKey) sdk.KVStore {
+	return journalStore{
+		KVStore: jms.cacheCtx.WithGasMeter(meter).KVStore(key),
+		parent:  jms.cacheCtx.WithGasMeter(sdk.NewInfiniteGasMeter()).KVStore(key),
+		journal: jms.journal,
+	}
+}
+
+// journalStore wraps a store of a journalMultiStore, recording every write to
+// it along with the prior value of the key written, read from the unmetered
+// parent store.
+type journalStore struct {
+	sdk.KVStore
+
+	parent  sdk.KVStore
+	journal *journal
+}
+
+// Set implements the KVStore interface.
+func (js journalStore) Set(key, value []byte) {
+	js.journal.append(storeChange{store: js.parent, key: key, prev: js.parent.Get(key)})
+	js.KVStore.Set(key, value)
+}
+
+// Delete implements the KVStore interface.
+func (js journalStore) Delete(key []byte) {
+	js.journal.append(storeChange{store: js.parent, key: key, prev: js.parent.Get(key)})
+	js.KVStore.Delete(key)
+}
@@ -0,0 +1,427 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+var _ ethvm.StateDB = (*CommitStateDB)(nil)
+
+// revision defines a snapshot of a CommitStateDB: the number of changes
+// recorded by its journal, the number of its logs and its refund counter at
+// the time the snapshot was taken.
+type revision struct {
+	journalLen int
+	logSize    int
+	refund     uint64
+}
+
+// CommitStateDB implements go-ethereum's vm.StateDB interface directly against
+// the Ethereum state held in an application's multi-store, so that the EVM
+// runs against committed application state without an intermediate trie. The
+// state is laid out as in a state.Database opened with
+// state.NewContextDatabase, so both may be used on the same stores: the
+// balance of an account is its amount of the EVM-native denom, its nonce its
+// sequence and its code hash that of the types.Account, while contract
+// storage and code are held by a StorageMapper and a CodeMapper.
+//
+// Changes are written to a cache of the context's multi-store, leaving the
+// context's stores untouched until the CommitStateDB is committed. Every write
+// to the cache is recorded by a journal along with the prior value written
+// over, so that a failed or reverted EVM call reverts its changes by replaying
+// the journal backwards to the call's snapshot.
+//
+// NOTE: As accounts cannot be removed, a self-destructed account retains its
+// sequence, which never regresses, while its balance, code and storage are
+// cleared upon commit.
+type CommitStateDB struct {
+	parentCtx sdk.Context
+	am        auth.AccountMapper
+	evmDenom  string
+	sm        StorageMapper
+	cm        CodeMapper
+
+	// ctx is a context on the cache of the parent context's multi-store whose
+	// writes are recorded by the journal
+	ctx        sdk.Context
+	writeCache func()
+	journal    *journal
+	revisions  []revision
+
+	// suicided holds the accounts self-destructed since the last commit
+	suicided map[ethcmn.Address]bool
+
+	refund    uint64
+	logs      []*ethtypes.Log
+	preimages map[ethcmn.Hash][]byte
+
+	thash   ethcmn.Hash
+	bhash   ethcmn.Hash
+	txIndex int
+}
+
+// NewCommitStateDB returns a reference to a new CommitStateDB serving the
+// state of the given context: the accounts of the given auth.AccountMapper,
+// whose balances are their amounts of the given EVM-native denom, the contract
+// storage of the given StorageMapper and the contract code of the given
+// CodeMapper.
+func NewCommitStateDB(
+	ctx sdk.Context, am auth.AccountMapper, evmDenom string, sm StorageMapper, cm CodeMapper,
+) *CommitStateDB {
+
+	csdb := &CommitStateDB{
+		parentCtx: ctx,
+		am:        am,
+		evmDenom:  evmDenom,
+		sm:        sm,
+		cm:        cm,
+		preimages: make(map[ethcmn.Hash][]byte),
+	}
+
+	csdb.reset()
+	return csdb
+}
+
+// reset discards every uncommitted change and snapshot, opening a fresh cache
+// of the context's multi-store.
+func (csdb *CommitStateDB) reset() {
+	cacheCtx, writeCache := csdb.parentCtx.CacheContext()
+
+	csdb.journal = new(journal)
+	csdb.ctx = cacheCtx.WithMultiStore(journalMultiStore{cacheCtx: cacheCtx, journal: csdb.journal})
+	csdb.writeCache = writeCache
+	csdb.revisions = nil
+	csdb.suicided = make(map[ethcmn.Address]bool)
+}
+
+// current returns the context of the current state.
+func (csdb *CommitStateDB) current() sdk.Context {
+	return csdb.ctx
+}
+
+// Prepare sets the hashes of the transaction and block, and the transaction's
+// index, the logs added next are attributed to.
+func (csdb *CommitStateDB) Prepare(thash, bhash ethcmn.Hash, txIndex int) {
+	csdb.thash = thash
+	csdb.bhash = bhash
+	csdb.txIndex = txIndex
+}
+
+// CreateAccount implements the vm.StateDB interface. An existing account, e.g.
+// one funded prior to the creation of a contract at its address, retains its
+// balance and sequence while its code and storage are cleared.
+func (csdb *CommitStateDB) CreateAccount(addr ethcmn.Address) {
+	csdb.clearContract(addr)
+}
+
+// SubBalance implements the vm.StateDB interface.
+func (csdb *CommitStateDB) SubBalance(addr ethcmn.Address, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+
+	csdb.setBalance(addr, new(big.Int).Sub(csdb.GetBalance(addr), amount))
+}
+
+// AddBalance implements the vm.StateDB interface.
+func (csdb *CommitStateDB) AddBalance(addr ethcmn.Address, amount *big.Int) {
+	if amount.Sign() == 0 {
+		return
+	}
+
+	csdb.setBalance(addr, new(big.Int).Add(csdb.GetBalance(addr), amount))
+}
+
+// GetBalance implements the vm.StateDB interface. It returns zero if the
+// account does not exist.
+func (csdb *CommitStateDB) GetBalance(addr ethcmn.Address) *big.Int {
+	acc := csdb.getAccount(addr)
+	if acc == nil {
+		return new(big.Int)
+	}
+
+	return types.EVMBalance(acc.GetCoins(), csdb.evmDenom)
+}
+
+// setBalance sets the balance of the account at the given address, creating
+// the account if it does not exist, retaining its coins of other
+// denominations.
+func (csdb *CommitStateDB) setBalance(addr ethcmn.Address, balance *big.Int) {
+	acc := csdb.getOrNewAccount(addr)
+	if err := acc.SetCoins(types.SetEVMBalance(acc.GetCoins(), csdb.evmDenom, balance)); err != nil {
+		panic(err)
+	}
+
+	csdb.am.SetAccount(csdb.current(), acc)
+}
+
+// GetNonce implements the vm.StateDB interface. It returns zero if the account
+// does not exist.
+func (csdb *CommitStateDB) GetNonce(addr ethcmn.Address) uint64 {
+	acc := csdb.getAccount(addr)
+	if acc == nil || acc.GetSequence() < 0 {
+		return 0
+	}
+
+	return uint64(acc.GetSequence())
+}
+
+// SetNonce implements the vm.StateDB interface. The sequence is shared with
+// embedded transactions so it never regresses, even if a self-destructed
+// contract is re-created.
+func (csdb *CommitStateDB) SetNonce(addr ethcmn.Address, nonce uint64) {
+	acc := csdb.getOrNewAccount(addr)
+
+	if seq := acc.GetSequence(); seq < 0 || uint64(seq) < nonce {
+		if err := acc.SetSequence(int64(nonce)); err != nil {
+			panic(err)
+		}
+	}
+
+	csdb.am.SetAccount(csdb.current(), acc)
+}
+
+// GetCodeHash implements the vm.StateDB interface. It returns the empty hash
+// if the account does not exist.
+func (csdb *CommitStateDB) GetCodeHash(addr ethcmn.Address) ethcmn.Hash {
+	acc := csdb.getAccount(addr)
+	if acc == nil {
+		return ethcmn.Hash{}
+	}
+
+	return ethcmn.BytesToHash(codeHash(acc))
+}
+
+// GetCode implements the vm.StateDB interface.
+func (csdb *CommitStateDB) GetCode(addr ethcmn.Address) []byte {
+	acc := csdb.getAccount(addr)
+	if acc == nil {
+		return nil
+	}
+
+	return csdb.cm.GetCode(csdb.current(), codeHash(acc))
+}
+
+// SetCode implements the vm.StateDB interface. The code is stored by the
+// CodeMapper, shared by every account with the same code. Setting the code of
+// an account other than a types.Account panics.
+func (csdb *CommitStateDB) SetCode(addr ethcmn.Address, code []byte) {
+	acc := csdb.getOrNewAccount(addr)
+
+	ethermintAcc, ok := acc.(*types.Account)
+	if !ok {
+		panic(fmt.Sprintf("cannot set code of account type %T", acc))
+	}
+
+	csdb.cm.SetAccountCode(csdb.current(), ethermintAcc, code)
+	csdb.am.SetAccount(csdb.current(), acc)
+}
+
+// GetCodeSize implements the vm.StateDB interface.
+func (csdb *CommitStateDB) GetCodeSize(addr ethcmn.Address) int {
+	return len(csdb.GetCode(addr))
+}
+
+// AddRefund implements the vm.StateDB interface.
+func (csdb *CommitStateDB) AddRefund(gas uint64) {
+	csdb.refund += gas
+}
+
+// GetRefund implements the vm.StateDB interface.
+func (csdb *CommitStateDB) GetRefund() uint64 {
+	return csdb.refund
+}
+
+// GetState implements the vm.StateDB interface. It returns the empty hash if
+// the storage slot is not set.
+func (csdb *CommitStateDB) GetState(addr ethcmn.Address, key ethcmn.Hash) ethcmn.Hash {
+	return csdb.sm.GetState(csdb.current(), addr, key)
+}
+
+// SetState implements the vm.StateDB interface. Setting the empty hash deletes
+// the storage slot.
+func (csdb *CommitStateDB) SetState(addr ethcmn.Address, key, value ethcmn.Hash) {
+	csdb.sm.SetState(csdb.current(), addr, key, value)
+}
+
+// Suicide implements the vm.StateDB interface. It marks the account at the
+// given address as self-destructed and clears its balance, returning false if
+// the account does not exist. Its code and storage remain accessible until the
+// CommitStateDB is committed.
+func (csdb *CommitStateDB) Suicide(addr ethcmn.Address) bool {
+	if csdb.getAccount(addr) == nil {
+		return false
+	}
+
+	if !csdb.suicided[addr] {
+		csdb.suicided[addr] = true
+		csdb.journal.append(suicideChange{addr: addr})
+	}
+
+	csdb.setBalance(addr, new(big.Int))
+	return true
+}
+
+// HasSuicided implements the vm.StateDB interface.
+func (csdb *CommitStateDB) HasSuicided(addr ethcmn.Address) bool {
+	return csdb.suicided[addr]
+}
+
+// Exist implements the vm.StateDB interface. A self-destructed account exists
+// until the CommitStateDB is committed.
+func (csdb *CommitStateDB) Exist(addr ethcmn.Address) bool {
+	return csdb.getAccount(addr) != nil
+}
+
+// Empty implements the vm.StateDB interface. An account is empty, as defined
+// by EIP-161, if it does not exist or has no balance, nonce or code.
+func (csdb *CommitStateDB) Empty(addr ethcmn.Address) bool {
+	acc := csdb.getAccount(addr)
+	if acc == nil {
+		return true
+	}
+
+	return acc.GetSequence() <= 0 &&
+		acc.GetCoins().AmountOf(csdb.evmDenom).IsZero() &&
+		bytes.Equal(codeHash(acc), emptyCodeHash)
+}
+
+// Snapshot implements the vm.StateDB interface. It returns the identifier of a
+// new snapshot of the current state to revert to.
+func (csdb *CommitStateDB) Snapshot() int {
+	csdb.revisions = append(csdb.revisions, revision{
+		journalLen: csdb.journal.length(),
+		logSize:    len(csdb.logs),
+		refund:     csdb.refund,
+	})
+
+	return len(csdb.revisions) - 1
+}
+
+// RevertToSnapshot implements the vm.StateDB interface. It discards every
+// change made since the snapshot of the given identifier was taken, including
+// the snapshots taken since. It panics if there is no such snapshot.
+func (csdb *CommitStateDB) RevertToSnapshot(revid int) {
+	if revid < 0 || revid >= len(csdb.revisions) {
+		panic(fmt.Sprintf("revision id %v cannot be reverted", revid))
+	}
+
+	rev := csdb.revisions[revid]
+
+	csdb.journal.revert(csdb, rev.journalLen)
+	csdb.logs = csdb.logs[:rev.logSize]
+	csdb.refund = rev.refund
+	csdb.revisions = csdb.revisions[:revid]
+}
+
+// AddLog implements the vm.StateDB interface. The log is attributed to the
+// transaction and block set by Prepare.
+func (csdb *CommitStateDB) AddLog(log *ethtypes.Log) {
+	log.TxHash = csdb.thash
+	log.BlockHash = csdb.bhash
+	log.TxIndex = uint(csdb.txIndex)
+	log.Index = uint(len(csdb.logs))
+
+	csdb.logs = append(csdb.logs, log)
+}
+
+// Logs returns the logs added since the CommitStateDB was created, excluding
+// those reverted.
+func (csdb *CommitStateDB) Logs() []*ethtypes.Log {
+	return csdb.logs
+}
+
+// AddPreimage implements the vm.StateDB interface.
+func (csdb *CommitStateDB) AddPreimage(hash ethcmn.Hash, preimage []byte) {
+	if _, ok := csdb.preimages[hash]; !ok {
+		csdb.preimages[hash] = ethcmn.CopyBytes(preimage)
+	}
+}
+
+// Preimages returns the SHA3 preimages recorded by the EVM.
+func (csdb *CommitStateDB) Preimages() map[ethcmn.Hash][]byte {
+	return csdb.preimages
+}
+
+// ForEachStorage implements the vm.StateDB interface. It calls the given
+// callback with every set storage slot of the account at the given address,
+// in ascending order of their keys, until it returns false.
+func (csdb *CommitStateDB) ForEachStorage(addr ethcmn.Address, cb func(key, value ethcmn.Hash) bool) {
+	csdb.sm.IterateStorage(csdb.current(), addr, func(key, value ethcmn.Hash) bool {
+		return !cb(key, value)
+	})
+}
+
+// Commit writes every change since the last commit to the stores of the
+// context the CommitStateDB was created with, clearing the balance, code and
+// storage of every self-destructed account, in ascending order of their
+// addresses. The journal and snapshots are discarded and the refund counter
+// reset, while logs and recorded preimages are retained.
+func (csdb *CommitStateDB) Commit() {
+	suicided := make([]ethcmn.Address, 0, len(csdb.suicided))
+	for addr := range csdb.suicided {
+		suicided = append(suicided, addr)
+	}
+
+	sort.Slice(suicided, func(i, j int) bool {
+		return bytes.Compare(suicided[i][:], suicided[j][:]) < 0
+	})
+
+	for _, addr := range suicided {
+		csdb.setBalance(addr, new(big.Int))
+		csdb.clearContract(addr)
+	}
+
+	csdb.writeCache()
+
+	csdb.refund = 0
+	csdb.reset()
+}
+
+// clearContract clears the code hash and deletes every storage slot of the
+// account at the given address, creating the account if it does not exist.
+func (csdb *CommitStateDB) clearContract(addr ethcmn.Address) {
+	acc := csdb.getOrNewAccount(addr)
+	if ethermintAcc, ok := acc.(*types.Account); ok {
+		ethermintAcc.CodeHash = nil
+	}
+
+	csdb.am.SetAccount(csdb.current(), acc)
+	csdb.sm.ClearStorage(csdb.current(), addr)
+}
+
+// getAccount returns the account at the given address, or nil if it does not
+// exist.
+func (csdb *CommitStateDB) getAccount(addr ethcmn.Address) auth.Account {
+	return csdb.am.GetAccount(csdb.current(), addr.Bytes())
+}
+
+// getOrNewAccount returns the account at the given address, or a new account
+// if it does not exist, which is only stored once set.
+func (csdb *CommitStateDB) getOrNewAccount(addr ethcmn.Address) auth.Account {
+	if acc := csdb.getAccount(addr); acc != nil {
+		return acc
+	}
+
+	return csdb.am.NewAccountWithAddress(csdb.current(), addr.Bytes())
+}
+
+// codeHash returns the code hash of the given account, the hash of empty code
+// if it is not a types.Account or has no code.
+func codeHash(acc auth.Account) []byte {
+	if ethermintAcc, ok := acc.(*types.Account); ok && len(ethermintAcc.CodeHash) != 0 {
+		return ethermintAcc.CodeHash
+	}
+
+	return emptyCodeHash
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/state"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// counterCode is the init code of a contract whose runtime code increments the
+// value at storage slot zero and returns the new value on every call.
+var counterCode = ethcmn.FromHex("0x601280600b6000396000f3" + "6000546001018060005560005260206000f3")
+
+type testStateDB struct {
+	ctx        sdk.Context
+	am         auth.AccountMapper
+	storageKey *sdk.KVStoreKey
+	codeKey    *sdk.KVStoreKey
+}
+
+func newTestStateDB(t *testing.T) testStateDB {
+	cdc := wire.NewCodec()
+	auth.RegisterWire(cdc)
+	types.RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+
+	keyAcc := sdk.NewKVStoreKey("acc")
+	tsdb := testStateDB{
+		storageKey: sdk.NewKVStoreKey(state.StorageKey.Name()),
+		codeKey:    sdk.NewKVStoreKey(state.CodeKey.Name()),
+	}
+
+	memDB := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(memDB)
+	for _, key := range []*sdk.KVStoreKey{keyAcc, tsdb.storageKey, tsdb.codeKey} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, memDB)
+	}
+	require.Nil(t, ms.LoadLatestVersion())
+
+	tsdb.ctx = sdk.NewContext(ms, abci.Header{Height: 1}, false, log.NewNopLogger())
+	tsdb.am = auth.NewAccountMapper(cdc, keyAcc, types.ProtoAccount)
+
+	return tsdb
+}
+
+func (tsdb testStateDB) open() *CommitStateDB {
+	return NewCommitStateDB(tsdb.ctx, tsdb.am, types.DenomDefault, NewStorageMapper(tsdb.storageKey), NewCodeMapper(tsdb.codeKey))
+}
+
+func TestCommitStateDB(t *testing.T) {
+	tsdb := newTestStateDB(t)
+
+	addr := ethcmn.BytesToAddress([]byte("contract"))
+	slot := ethcmn.BytesToHash([]byte("slot"))
+	value := ethcmn.BytesToHash([]byte("value"))
+	code := []byte("code")
+
+	// the account holds coins of other denominations which must be retained
+	acc := tsdb.am.NewAccountWithAddress(tsdb.ctx, addr.Bytes())
+	require.Nil(t, acc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(types.DenomDefault, 10)}))
+	require.Nil(t, acc.SetSequence(3))
+	tsdb.am.SetAccount(tsdb.ctx, acc)
+
+	csdb := tsdb.open()
+	require.True(t, csdb.Exist(addr))
+	require.False(t, csdb.Empty(addr))
+	require.Equal(t, big.NewInt(10), csdb.GetBalance(addr))
+	require.Equal(t, uint64(3), csdb.GetNonce(addr))
+	require.Equal(t, ethcmn.BytesToHash(emptyCodeHash), csdb.GetCodeHash(addr))
+
+	other := ethcmn.BytesToAddress([]byte("other"))
+	require.False(t, csdb.Exist(other))
+	require.True(t, csdb.Empty(other))
+	require.Equal(t, ethcmn.Hash{}, csdb.GetCodeHash(other))
+
+	csdb.AddBalance(addr, big.NewInt(90))
+	csdb.SubBalance(addr, big.NewInt(20))
+	csdb.SetNonce(addr, 4)
+	csdb.SetCode(addr, code)
+	csdb.SetState(addr, slot, value)
+
+	require.Equal(t, big.NewInt(80), csdb.GetBalance(addr))
+	require.Equal(t, code, csdb.GetCode(addr))
+	require.Equal(t, len(code), csdb.GetCodeSize(addr))
+	require.Equal(t, value, csdb.GetState(addr, slot))
+
+	// no change is written until committed
+	require.Equal(t, int64(3), tsdb.am.GetAccount(tsdb.ctx, addr.Bytes()).GetSequence())
+
+	csdb.Commit()
+
+	acc = tsdb.am.GetAccount(tsdb.ctx, addr.Bytes())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(types.DenomDefault, 80)}, acc.GetCoins())
+	require.Equal(t, int64(4), acc.GetSequence())
+	require.Equal(t, ethcrypto.Keccak256(code), acc.(*types.Account).CodeHash)
+
+	// the state is laid out as in a state.Database on the same stores
+	stateDB, err := ethstate.New(ethcmn.Hash{}, state.NewContextDatabase(tsdb.ctx, tsdb.am, types.DenomDefault, tsdb.storageKey, tsdb.codeKey))
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(80), stateDB.GetBalance(addr))
+	require.Equal(t, code, stateDB.GetCode(addr))
+	require.Equal(t, value, stateDB.GetState(addr, slot))
+
+	// a self-destructed account is cleared upon commit, retaining its sequence
+	csdb = tsdb.open()
+	require.True(t, csdb.Suicide(addr))
+	require.False(t, csdb.Suicide(other))
+	require.True(t, csdb.HasSuicided(addr))
+	require.True(t, csdb.Exist(addr))
+	require.Equal(t, new(big.Int), csdb.GetBalance(addr))
+	require.Equal(t, code, csdb.GetCode(addr))
+
+	csdb.Commit()
+	require.False(t, csdb.HasSuicided(addr))
+	require.Equal(t, uint64(4), csdb.GetNonce(addr))
+	require.Empty(t, csdb.GetCode(addr))
+	require.Equal(t, ethcmn.Hash{}, csdb.GetState(addr, slot))
+
+	acc = tsdb.am.GetAccount(tsdb.ctx, addr.Bytes())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, acc.GetCoins())
+	require.Equal(t, int64(4), acc.GetSequence())
+	require.Empty(t, acc.(*types.Account).CodeHash)
+
+	csdb.SetNonce(addr, 0)
+	require.Equal(t, uint64(4), csdb.GetNonce(addr))
+}
+
+func TestCommitStateDBSnapshots(t *testing.T) {
+	csdb := newTestStateDB(t).open()
+
+	addr := ethcmn.BytesToAddress([]byte("account"))
+	slots := []ethcmn.Hash{ethcmn.BytesToHash([]byte{1}), ethcmn.BytesToHash([]byte{2})}
+
+	csdb.Prepare(ethcmn.BytesToHash([]byte("tx")), ethcmn.BytesToHash([]byte("block")), 2)
+	csdb.AddBalance(addr, big.NewInt(10))
+	csdb.SetState(addr, slots[0], ethcmn.BytesToHash([]byte{0xa}))
+	csdb.AddRefund(5)
+	csdb.AddLog(&ethtypes.Log{Address: addr})
+
+	outer := csdb.Snapshot()
+	csdb.AddBalance(addr, big.NewInt(10))
+	csdb.SetState(addr, slots[1], ethcmn.BytesToHash([]byte{0xb}))
+	csdb.AddRefund(5)
+	csdb.AddLog(&ethtypes.Log{Address: addr})
+
+	inner := csdb.Snapshot()
+	csdb.SetState(addr, slots[0], ethcmn.Hash{})
+	require.True(t, csdb.Suicide(addr))
+
+	csdb.RevertToSnapshot(inner)
+	require.False(t, csdb.HasSuicided(addr))
+	require.Equal(t, big.NewInt(20), csdb.GetBalance(addr))
+
+	var storage []ethcmn.Hash
+	csdb.ForEachStorage(addr, func(key, value ethcmn.Hash) bool {
+		storage = append(storage, key, value)
+		return true
+	})
+	require.Equal(t, []ethcmn.Hash{
+		slots[0], ethcmn.BytesToHash([]byte{0xa}), slots[1], ethcmn.BytesToHash([]byte{0xb}),
+	}, storage)
+
+	csdb.RevertToSnapshot(outer)
+	require.Equal(t, big.NewInt(10), csdb.GetBalance(addr))
+	require.Equal(t, ethcmn.Hash{}, csdb.GetState(addr, slots[1]))
+	require.Equal(t, uint64(5), csdb.GetRefund())
+	require.Len(t, csdb.Logs(), 1)
+
+	ethLog := csdb.Logs()[0]
+	require.Equal(t, ethcmn.BytesToHash([]byte("tx")), ethLog.TxHash)
+	require.Equal(t, ethcmn.BytesToHash([]byte("block")), ethLog.BlockHash)
+	require.Equal(t, uint(2), ethLog.TxIndex)
+
+	// reverted snapshots cannot be reverted to again
+	require.Panics(t, func() { csdb.RevertToSnapshot(inner) })
+	require.Panics(t, func() { csdb.RevertToSnapshot(outer) })
+
+	csdb.Commit()
+	require.Zero(t, csdb.GetRefund())
+	require.Equal(t, big.NewInt(10), csdb.GetBalance(addr))
+}
+
+func TestCommitStateDBEVM(t *testing.T) {
+	tsdb := newTestStateDB(t)
+	csdb := tsdb.open()
+
+	sender := ethcmn.BytesToAddress([]byte("sender"))
+	csdb.AddBalance(sender, big.NewInt(1000))
+
+	chainConfig := core.NewChainConfig(big.NewInt(1))
+	vmCtx := core.NewVMContext(
+		tsdb.ctx.BlockHeader(), sender, ethcmn.Address{}, big.NewInt(1), 1000000,
+		func(uint64) ethcmn.Hash { return ethcmn.Hash{} },
+	)
+	evm := ethvm.NewEVM(vmCtx, csdb, chainConfig, ethvm.Config{})
+
+	_, contract, _, err := evm.Create(ethvm.AccountRef(sender), counterCode, 100000, big.NewInt(100))
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(900), csdb.GetBalance(sender))
+	require.Equal(t, big.NewInt(100), csdb.GetBalance(contract))
+	require.Equal(t, uint64(1), csdb.GetNonce(contract))
+
+	csdb.Commit()
+
+	for i := int64(1); i <= 2; i++ {
+		ret, _, err := evm.Call(ethvm.AccountRef(sender), contract, nil, 100000, new(big.Int))
+		require.Nil(t, err)
+		require.Equal(t, ethcmn.BigToHash(big.NewInt(i)).Bytes(), ret)
+	}
+
+	// a call running out of gas reverts its state changes
+	_, _, err = evm.Call(ethvm.AccountRef(sender), contract, nil, 1000, big.NewInt(50))
+	require.NotNil(t, err)
+	require.Equal(t, big.NewInt(100), csdb.GetBalance(contract))
+
+	csdb.Commit()
+	require.Equal(t, ethcmn.BigToHash(big.NewInt(2)), csdb.GetState(contract, ethcmn.Hash{}))
+}
+
+func TestCommitStateDBJournal(t *testing.T) {
+	tsdb := newTestStateDB(t)
+	csdb := tsdb.open()
+
+	addr := ethcmn.BytesToAddress([]byte("account"))
+	created := ethcmn.BytesToAddress([]byte("created"))
+
+	csdb.AddBalance(addr, big.NewInt(10))
+	csdb.SetNonce(addr, 1)
+
+	// an account created through the account mapper, including the account
+	// number it consumes, is removed again upon revert
+	revid := csdb.Snapshot()
+	csdb.AddBalance(created, big.NewInt(5))
+	csdb.SetCode(created, []byte("code"))
+	csdb.SetNonce(addr, 2)
+	require.True(t, csdb.Exist(created))
+
+	csdb.RevertToSnapshot(revid)
+	require.False(t, csdb.Exist(created))
+	require.Equal(t, uint64(1), csdb.GetNonce(addr))
+
+	// no change reaches the context's stores until committed
+	require.Nil(t, tsdb.am.GetAccount(tsdb.ctx, addr.Bytes()))
+
+	csdb.Commit()
+	require.Equal(t, int64(1), tsdb.am.GetAccount(tsdb.ctx, addr.Bytes()).GetSequence())
+	require.Nil(t, tsdb.am.GetAccount(tsdb.ctx, created.Bytes()))
+
+	csdb.AddBalance(created, big.NewInt(5))
+	csdb.Commit()
+	require.Equal(t, int64(1), tsdb.am.GetAccount(tsdb.ctx, created.Bytes()).GetAccountNumber())
+
+	// snapshots do not outlive a commit
+	revid = csdb.Snapshot()
+	csdb.Commit()
+	require.Panics(t, func() { csdb.RevertToSnapshot(revid) })
+}
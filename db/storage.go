@@ -0,0 +1,114 @@
+package db
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// StorageMapper stores the 32-byte storage slots of contracts in a KVStore,
+// alongside the auth.AccountMapper holding their accounts. Slots are keyed by
+// the hash of the contract's address followed by the slot's key, as in a
+// state.Database's storage store, so that the slots of a contract are
+// iterated over in ascending order of their keys. Values are RLP encoded
+// without leading zeroes, as in an Ethereum storage trie.
+type StorageMapper struct {
+	key sdk.StoreKey
+}
+
+// NewStorageMapper returns a new StorageMapper storing contract storage in
+// the store of the given key.
+func NewStorageMapper(key sdk.StoreKey) StorageMapper {
+	return StorageMapper{key: key}
+}
+
+// StoragePrefix returns the prefix of the keys of the storage slots of the
+// contract at the given address.
+func StoragePrefix(addr ethcmn.Address) []byte {
+	return ethcrypto.Keccak256(addr.Bytes())
+}
+
+// StorageKey returns the key of the given storage slot of the contract at the
+// given address.
+func StorageKey(addr ethcmn.Address, slot ethcmn.Hash) []byte {
+	return append(StoragePrefix(addr), slot.Bytes()...)
+}
+
+// GetState returns the value of the given storage slot of the contract at the
+// given address, or the empty hash if the slot is not set.
+func (sm StorageMapper) GetState(ctx sdk.Context, addr ethcmn.Address, slot ethcmn.Hash) ethcmn.Hash {
+	return decodeStorageValue(ctx.KVStore(sm.key).Get(StorageKey(addr, slot)))
+}
+
+// SetState sets the value of the given storage slot of the contract at the
+// given address. Setting the empty hash deletes the slot.
+func (sm StorageMapper) SetState(ctx sdk.Context, addr ethcmn.Address, slot, value ethcmn.Hash) {
+	store := ctx.KVStore(sm.key)
+
+	if value == (ethcmn.Hash{}) {
+		store.Delete(StorageKey(addr, slot))
+		return
+	}
+
+	bz, err := rlp.EncodeToBytes(bytes.TrimLeft(value[:], "\x00"))
+	if err != nil {
+		panic(err)
+	}
+
+	store.Set(StorageKey(addr, slot), bz)
+}
+
+// IterateStorage calls the given function with every set storage slot of the
+// contract at the given address, in ascending order of their keys, until it
+// returns true.
+func (sm StorageMapper) IterateStorage(
+	ctx sdk.Context, addr ethcmn.Address, process func(slot, value ethcmn.Hash) (stop bool),
+) {
+
+	prefix := StoragePrefix(addr)
+
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(sm.key), prefix)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if process(ethcmn.BytesToHash(iter.Key()[len(prefix):]), decodeStorageValue(iter.Value())) {
+			return
+		}
+	}
+}
+
+// ClearStorage deletes every storage slot of the contract at the given
+// address.
+func (sm StorageMapper) ClearStorage(ctx sdk.Context, addr ethcmn.Address) {
+	var slots []ethcmn.Hash
+
+	// collect the slots first as the store cannot be written while iterated
+	sm.IterateStorage(ctx, addr, func(slot, _ ethcmn.Hash) bool {
+		slots = append(slots, slot)
+		return false
+	})
+
+	store := ctx.KVStore(sm.key)
+	for _, slot := range slots {
+		store.Delete(StorageKey(addr, slot))
+	}
+}
+
+// decodeStorageValue returns the storage value of the given encoding, or the
+// empty hash if there is none.
+func decodeStorageValue(bz []byte) ethcmn.Hash {
+	if len(bz) == 0 {
+		return ethcmn.Hash{}
+	}
+
+	_, content, _, err := rlp.Split(bz)
+	if err != nil {
+		panic(err)
+	}
+
+	return ethcmn.BytesToHash(content)
+}
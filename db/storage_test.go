@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageMapper(t *testing.T) {
+	tsdb := newTestStateDB(t)
+	sm := NewStorageMapper(tsdb.storageKey)
+
+	addr := ethcmn.BytesToAddress([]byte("contract"))
+	other := ethcmn.BytesToAddress([]byte("other"))
+
+	slots := []ethcmn.Hash{ethcmn.BytesToHash([]byte{1}), ethcmn.BytesToHash([]byte{2}), ethcmn.BytesToHash([]byte{3})}
+	values := []ethcmn.Hash{ethcmn.BytesToHash([]byte{0xa}), ethcmn.BytesToHash([]byte{0xb}), ethcmn.HexToHash("0x01" + "00")}
+
+	// slots are set in reverse order as they are iterated in ascending order
+	for i := len(slots) - 1; i >= 0; i-- {
+		sm.SetState(tsdb.ctx, addr, slots[i], values[i])
+	}
+	sm.SetState(tsdb.ctx, other, slots[0], values[0])
+
+	for i, slot := range slots {
+		require.Equal(t, values[i], sm.GetState(tsdb.ctx, addr, slot))
+	}
+
+	// values are stored without leading zeroes
+	require.Equal(t, []byte{0x82, 0x01, 0x00}, tsdb.ctx.KVStore(tsdb.storageKey).Get(StorageKey(addr, slots[2])))
+
+	iterate := func(addr ethcmn.Address, limit int) []ethcmn.Hash {
+		var storage []ethcmn.Hash
+		sm.IterateStorage(tsdb.ctx, addr, func(slot, value ethcmn.Hash) bool {
+			storage = append(storage, slot, value)
+			return len(storage) == 2*limit
+		})
+
+		return storage
+	}
+
+	require.Equal(t, []ethcmn.Hash{slots[0], values[0], slots[1], values[1], slots[2], values[2]}, iterate(addr, 0))
+	require.Equal(t, []ethcmn.Hash{slots[0], values[0]}, iterate(addr, 1))
+
+	// setting the empty hash deletes the slot
+	sm.SetState(tsdb.ctx, addr, slots[1], ethcmn.Hash{})
+	require.Equal(t, ethcmn.Hash{}, sm.GetState(tsdb.ctx, addr, slots[1]))
+	require.False(t, tsdb.ctx.KVStore(tsdb.storageKey).Has(StorageKey(addr, slots[1])))
+	require.Equal(t, []ethcmn.Hash{slots[0], values[0], slots[2], values[2]}, iterate(addr, 0))
+
+	// clearing the storage of a contract retains that of others
+	sm.ClearStorage(tsdb.ctx, addr)
+	require.Empty(t, iterate(addr, 0))
+	require.Equal(t, []ethcmn.Hash{slots[0], values[0]}, iterate(other, 0))
+}
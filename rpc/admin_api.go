@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SnapshotFunc writes a snapshot of the Ethereum state at a given height to a
+// writer and returns the height of the snapshot written. A non-positive height
+// requests the latest height.
+type SnapshotFunc func(height int64, w io.Writer) (int64, error)
+
+// SnapshotStatus describes the latest snapshot created through the admin API.
+type SnapshotStatus struct {
+	InProgress bool           `json:"inProgress"`
+	Height     hexutil.Uint64 `json:"height"`
+	Path       string         `json:"path"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// PrivateAdminAPI offers the node operator JSON-RPC methods served under the
+// "admin" namespace, allowing the node and the RPC server to be managed
+// without restarting them.
+type PrivateAdminAPI struct {
+	backend Backend
+	modules *Modules
+	logs    *log.GlogHandler
+
+	snapshotDir string
+	snapshotFn  SnapshotFunc
+
+	mtx            sync.Mutex
+	snapshotStatus *SnapshotStatus
+}
+
+// NewPrivateAdminAPI returns a reference to a new PrivateAdminAPI managing the
+// peers of the Backend's node, the namespaces served by the given Modules and
+// the verbosity of the given log handler. Snapshots are created using the
+// given SnapshotFunc and written to the given directory. Any of the log
+// handler, the snapshot directory and the SnapshotFunc may be unset, in which
+// case the corresponding methods return an error.
+func NewPrivateAdminAPI(
+	backend Backend, modules *Modules, logs *log.GlogHandler, snapshotDir string, snapshotFn SnapshotFunc,
+) *PrivateAdminAPI {
+
+	return &PrivateAdminAPI{
+		backend:     backend,
+		modules:     modules,
+		logs:        logs,
+		snapshotDir: snapshotDir,
+		snapshotFn:  snapshotFn,
+	}
+}
+
+// Peers returns the peers the node is connected to.
+func (api *PrivateAdminAPI) Peers() ([]PeerInfo, error) {
+	return api.backend.Peers()
+}
+
+// AddPeer adds a persistent peer, in the form <ID>@<host>:<port>, to the node.
+// The node dials the peer asynchronously and redials it whenever the
+// connection to it is lost.
+func (api *PrivateAdminAPI) AddPeer(peer string) (bool, error) {
+	if err := api.backend.DialPeers([]string{peer}, true); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Modules returns every JSON-RPC namespace served and whether it is enabled.
+func (api *PrivateAdminAPI) Modules() map[string]bool {
+	return api.modules.Enabled()
+}
+
+// EnableModule enables the JSON-RPC namespace with the given name.
+func (api *PrivateAdminAPI) EnableModule(namespace string) (bool, error) {
+	if err := api.modules.SetEnabled(namespace, true); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DisableModule disables the JSON-RPC namespace with the given name. Calls to
+// the namespace's methods fail until it is enabled again.
+func (api *PrivateAdminAPI) DisableModule(namespace string) (bool, error) {
+	if err := api.modules.SetEnabled(namespace, false); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetLogLevel sets the level of the RPC server's logs to one of "crit",
+// "error", "warn", "info", "debug" or "trace".
+func (api *PrivateAdminAPI) SetLogLevel(level string) (bool, error) {
+	if api.logs == nil {
+		return false, errors.New("log level changes are not enabled")
+	}
+
+	lvl, err := log.LvlFromString(level)
+	if err != nil {
+		return false, err
+	}
+
+	api.logs.Verbosity(lvl)
+	return true, nil
+}
+
+// CreateSnapshot starts creating a snapshot of the Ethereum state at the
+// given height, or the latest height if zero, in the snapshot directory. As
+// a snapshot may take a while to create, it is created in the background and
+// its progress is reported by SnapshotStatus. Only a single snapshot is
+// created at a time.
+func (api *PrivateAdminAPI) CreateSnapshot(height hexutil.Uint64) (bool, error) {
+	if api.snapshotDir == "" || api.snapshotFn == nil {
+		return false, errors.New("snapshot creation is not enabled")
+	}
+
+	api.mtx.Lock()
+	defer api.mtx.Unlock()
+
+	if api.snapshotStatus != nil && api.snapshotStatus.InProgress {
+		return false, errors.New("a snapshot is already being created")
+	}
+
+	api.snapshotStatus = &SnapshotStatus{InProgress: true, Height: height}
+
+	go func() {
+		status := api.createSnapshot(int64(height))
+
+		api.mtx.Lock()
+		api.snapshotStatus = &status
+		api.mtx.Unlock()
+	}()
+
+	return true, nil
+}
+
+// SnapshotStatus returns the status of the latest snapshot created or nil if
+// no snapshot has been created.
+func (api *PrivateAdminAPI) SnapshotStatus() *SnapshotStatus {
+	api.mtx.Lock()
+	defer api.mtx.Unlock()
+
+	if api.snapshotStatus == nil {
+		return nil
+	}
+
+	status := *api.snapshotStatus
+	return &status
+}
+
+// createSnapshot writes a snapshot to a temporary file in the snapshot
+// directory and, once complete, renames it after the snapshot's height.
+func (api *PrivateAdminAPI) createSnapshot(height int64) SnapshotStatus {
+	fail := func(err error) SnapshotStatus {
+		return SnapshotStatus{Height: hexutil.Uint64(height), Error: err.Error()}
+	}
+
+	f, err := ioutil.TempFile(api.snapshotDir, "snapshot-")
+	if err != nil {
+		return fail(err)
+	}
+
+	height, err = api.snapshotFn(height, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return fail(err)
+	}
+
+	path := filepath.Join(api.snapshotDir, fmt.Sprintf("snapshot-%d.json", height))
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fail(err)
+	}
+
+	return SnapshotStatus{Height: hexutil.Uint64(height), Path: path}
+}
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForSnapshot waits for the snapshot in progress to complete and returns
+// its status.
+func waitForSnapshot(t *testing.T, api *PrivateAdminAPI) *SnapshotStatus {
+	for i := 0; i < 100; i++ {
+		if status := api.SnapshotStatus(); status != nil && !status.InProgress {
+			return status
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.FailNow(t, "snapshot did not complete")
+	return nil
+}
+
+func TestAdminPeers(t *testing.T) {
+	backend := &mockBackend{}
+	api := NewPrivateAdminAPI(backend, newTestModules(t), nil, "", nil)
+
+	peers, err := api.Peers()
+	require.Nil(t, err)
+	require.Empty(t, peers)
+
+	ok, err := api.AddPeer("abcd@10.0.0.1:26656")
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	peers, err = api.Peers()
+	require.Nil(t, err)
+	require.Equal(t, []PeerInfo{{ID: "abcd@10.0.0.1:26656", Outbound: true}}, peers)
+}
+
+func TestAdminSetLogLevel(t *testing.T) {
+	// log level changes must be enabled
+	api := NewPrivateAdminAPI(&mockBackend{}, newTestModules(t), nil, "", nil)
+
+	_, err := api.SetLogLevel("debug")
+	require.NotNil(t, err)
+
+	api = NewPrivateAdminAPI(&mockBackend{}, newTestModules(t), log.NewGlogHandler(log.DiscardHandler()), "", nil)
+
+	testCases := []struct {
+		level     string
+		expectErr bool
+	}{
+		{"debug", false},
+		{"warn", false},
+		{"trace", false},
+		{"verbose", true},
+		{"", true},
+	}
+
+	for i, tc := range testCases {
+		ok, err := api.SetLogLevel(tc.level)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.True(t, ok, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestAdminCreateSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var failure error
+	snapshotFn := func(height int64, w io.Writer) (int64, error) {
+		if failure != nil {
+			return 0, failure
+		}
+
+		if height == 0 {
+			height = 7
+		}
+
+		_, err := w.Write([]byte("snapshot"))
+		return height, err
+	}
+
+	// snapshot creation must be enabled
+	_, err = NewPrivateAdminAPI(&mockBackend{}, newTestModules(t), nil, "", nil).CreateSnapshot(0)
+	require.NotNil(t, err)
+
+	api := NewPrivateAdminAPI(&mockBackend{}, newTestModules(t), nil, dir, snapshotFn)
+	require.Nil(t, api.SnapshotStatus())
+
+	// the latest height is snapshotted by default
+	ok, err := api.CreateSnapshot(0)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	status := waitForSnapshot(t, api)
+	require.Empty(t, status.Error)
+	require.Equal(t, uint64(7), uint64(status.Height))
+	require.Equal(t, filepath.Join(dir, "snapshot-7.json"), status.Path)
+
+	bz, err := ioutil.ReadFile(status.Path)
+	require.Nil(t, err)
+	require.Equal(t, []byte("snapshot"), bz)
+
+	// a failed snapshot leaves no file behind
+	failure = errors.New("failed")
+
+	_, err = api.CreateSnapshot(3)
+	require.Nil(t, err)
+
+	status = waitForSnapshot(t, api)
+	require.Equal(t, "failed", status.Error)
+	require.Empty(t, status.Path)
+
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
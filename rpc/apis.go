@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// GetRPCAPIs returns the list of all Ethereum JSON-RPC APIs served by an
+// Ethermint node using the given Backend. The codec is used to encode any
+// transactions that are built and broadcasted by the node itself, the gas
+// price configuration to suggest gas prices, the etherbase as the initial
+// fee recipient reported as the node's coinbase and the network configuration
+// to describe the chain to wallets.
+//
+// The private miner API, changing the etherbase, is only included if admin is
+// set. As the served APIs do not distinguish private from public ones, it must
+// then only be served behind authentication (see AuthConfig.ValidateAdmin),
+// like the admin APIs.
+func GetRPCAPIs(
+	cdc *wire.Codec, backend Backend, chainID *big.Int, gpoConfig GasPriceConfig, etherbase ethcmn.Address,
+	network NetworkConfig, admin bool,
+) []ethrpc.API {
+
+	eb := NewEtherbase(etherbase)
+
+	apis := []ethrpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicEthAPI(backend, chainID, NewGasPriceOracle(backend, gpoConfig), eb),
+			Public:    true,
+		},
+		{
+			Namespace: "ethermint",
+			Version:   "1.0",
+			Service:   NewPublicEthermintAPI(backend, chainID, network),
+			Public:    true,
+		},
+		{
+			Namespace: "faucet",
+			Version:   "1.0",
+			Service:   NewPublicFaucetAPI(cdc, backend),
+			Public:    true,
+		},
+		{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPublicDebugAPI(backend),
+			Public:    true,
+		},
+	}
+
+	if admin {
+		apis = append(apis, ethrpc.API{
+			Namespace: "miner",
+			Version:   "1.0",
+			Service:   NewPrivateMinerAPI(eb),
+			Public:    false,
+		})
+	}
+
+	return apis
+}
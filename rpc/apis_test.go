@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRPCAPIsMiner(t *testing.T) {
+	backend := &mockBackend{}
+	getAPIs := func(admin bool) []ethrpc.API {
+		return GetRPCAPIs(nil, backend, testChainID, DefaultGasPriceConfig(), ethcmn.Address{}, NetworkConfig{}, admin)
+	}
+
+	hasMiner := func(apis []ethrpc.API) bool {
+		for _, api := range apis {
+			if api.Namespace == "miner" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	require.False(t, hasMiner(getAPIs(false)))
+	require.True(t, hasMiner(getAPIs(true)))
+
+	modules := NewModules(func(srv *ethrpc.Server) http.Handler { return srv })
+	require.Nil(t, modules.Register(getAPIs(true)...))
+
+	handler := NewAuthHandler(AuthConfig{Token: "secret-token"}, modules)
+	setEtherbase := func(authorization string) *httptest.ResponseRecorder {
+		body := `{"jsonrpc":"2.0","id":1,"method":"miner_setEtherbase","params":["0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0"]}`
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// the miner API is only served to authenticated callers
+	require.Equal(t, http.StatusUnauthorized, setEtherbase("").Code)
+	require.Equal(t, http.StatusUnauthorized, setEtherbase("Bearer other-token").Code)
+
+	rec := setEtherbase("Bearer secret-token")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), `"error"`)
+}
@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// JWTSecretLength is the required length of a JWT secret in bytes.
+	JWTSecretLength = 32
+
+	// jwtMaxClockSkew is the maximum difference between the issuance time of
+	// a JWT and the current time for the token to be accepted, bounding the
+	// window in which an intercepted token can be replayed.
+	jwtMaxClockSkew = 60 * time.Second
+)
+
+// AuthConfig defines the authentication required by the RPC servers. Requests
+// are accepted if they carry either the static bearer token or a JWT signed
+// with the secret. No authentication is required if neither is set.
+type AuthConfig struct {
+	// Token is a static bearer token
+	Token string
+
+	// JWTSecret is the secret HS256 JWTs must be signed with. As with geth's
+	// engine API, a JWT must carry an "iat" claim within a minute of the
+	// current time.
+	JWTSecret []byte
+}
+
+// Enabled returns true if the configuration requires authentication.
+func (cfg AuthConfig) Enabled() bool {
+	return cfg.Token != "" || len(cfg.JWTSecret) != 0
+}
+
+// Validate returns an error if the configuration is invalid.
+func (cfg AuthConfig) Validate() error {
+	if len(cfg.JWTSecret) != 0 && len(cfg.JWTSecret) != JWTSecretLength {
+		return fmt.Errorf("invalid JWT secret length %d; expected %d", len(cfg.JWTSecret), JWTSecretLength)
+	}
+
+	return nil
+}
+
+// ValidateAdmin returns an error if the admin APIs, which control the node,
+// may not be served under the configuration to the given CORS allowed origins:
+// authentication must be required and the wildcard origin, letting any web
+// page loaded by a browser on the operator's network call them, is refused.
+func (cfg AuthConfig) ValidateAdmin(corsOrigins []string) error {
+	if !cfg.Enabled() {
+		return errors.New("the admin APIs require authentication")
+	}
+
+	for _, origin := range corsOrigins {
+		if origin == "*" {
+			return errors.New("the admin APIs cannot be served to any CORS origin")
+		}
+	}
+
+	return nil
+}
+
+// NewAuthHandler returns an http.Handler that only passes requests carrying
+// valid credentials, as an "Authorization: Bearer <token>" header, to the
+// given handler. All other requests are rejected with 401 Unauthorized. The
+// given handler is returned as is if the configuration requires no
+// authentication.
+func NewAuthHandler(cfg AuthConfig, next http.Handler) http.Handler {
+	if !cfg.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := cfg.authenticate(r.Header.Get("Authorization"), time.Now()); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns an error if the given authorization header does not
+// carry valid credentials as of the given time.
+func (cfg AuthConfig) authenticate(header string, now time.Time) error {
+	const prefix = "Bearer "
+
+	if !strings.HasPrefix(header, prefix) {
+		return errors.New("missing bearer token")
+	}
+
+	token := strings.TrimSpace(header[len(prefix):])
+
+	if cfg.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) == 1 {
+		return nil
+	}
+
+	if len(cfg.JWTSecret) != 0 {
+		return verifyJWT(token, cfg.JWTSecret, now)
+	}
+
+	return errors.New("invalid bearer token")
+}
+
+// NewJWT returns an HS256 JWT signed with the given secret and issued at the
+// given time.
+func NewJWT(secret []byte, issuedAt time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iat":%d}`, issuedAt.Unix())))
+
+	signingInput := header + "." + claims
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(jwtSignature(secret, signingInput))
+}
+
+// verifyJWT returns an error if the given token is not an HS256 JWT signed
+// with the given secret and issued within jwtMaxClockSkew of the given time.
+func verifyJWT(token string, secret []byte, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return errors.New("unsupported JWT algorithm")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, jwtSignature(secret, parts[0]+"."+parts[1])) {
+		return errors.New("invalid JWT signature")
+	}
+
+	var claims struct {
+		IssuedAt *int64 `json:"iat"`
+	}
+
+	if err := decodeJWTPart(parts[1], &claims); err != nil || claims.IssuedAt == nil {
+		return errors.New("missing JWT issuance time")
+	}
+
+	if skew := now.Sub(time.Unix(*claims.IssuedAt, 0)); skew > jwtMaxClockSkew || skew < -jwtMaxClockSkew {
+		return errors.New("stale JWT")
+	}
+
+	return nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	bz, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bz, v)
+}
+
+func jwtSignature(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthConfigAuthenticate(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x01}, JWTSecretLength)
+	otherSecret := bytes.Repeat([]byte{0x02}, JWTSecretLength)
+	now := time.Unix(1000000, 0)
+
+	noneAlg := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	forged := NewJWT(secret, now)
+	forged = noneAlg + forged[strings.Index(forged, "."):]
+
+	testCases := []struct {
+		cfg     AuthConfig
+		header  string
+		expPass bool
+	}{
+		{AuthConfig{Token: "secret-token"}, "Bearer secret-token", true},
+		{AuthConfig{Token: "secret-token"}, "Bearer other-token", false},
+		{AuthConfig{Token: "secret-token"}, "secret-token", false},
+		{AuthConfig{Token: "secret-token"}, "", false},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(secret, now), true},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(secret, now.Add(-59*time.Second)), true},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(secret, now.Add(59*time.Second)), true},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(secret, now.Add(-61*time.Second)), false},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(secret, now.Add(61*time.Second)), false},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + NewJWT(otherSecret, now), false},
+		{AuthConfig{JWTSecret: secret}, "Bearer " + forged, false},
+		{AuthConfig{JWTSecret: secret}, "Bearer malformed", false},
+		// either credential is accepted when both are configured
+		{AuthConfig{Token: "secret-token", JWTSecret: secret}, "Bearer secret-token", true},
+		{AuthConfig{Token: "secret-token", JWTSecret: secret}, "Bearer " + NewJWT(secret, now), true},
+	}
+
+	for i, tc := range testCases {
+		err := tc.cfg.authenticate(tc.header, now)
+		require.Equal(t, tc.expPass, err == nil, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	require.Nil(t, AuthConfig{}.Validate())
+	require.Nil(t, AuthConfig{JWTSecret: make([]byte, JWTSecretLength)}.Validate())
+	require.NotNil(t, AuthConfig{JWTSecret: make([]byte, 16)}.Validate())
+}
+
+func TestAuthConfigValidateAdmin(t *testing.T) {
+	testCases := []struct {
+		cfg         AuthConfig
+		corsOrigins []string
+		expPass     bool
+	}{
+		{AuthConfig{}, nil, false},
+		{AuthConfig{}, []string{"https://example.com"}, false},
+		{AuthConfig{Token: "secret-token"}, nil, true},
+		{AuthConfig{Token: "secret-token"}, []string{"https://example.com"}, true},
+		{AuthConfig{Token: "secret-token"}, []string{"https://example.com", "*"}, false},
+		{AuthConfig{JWTSecret: make([]byte, JWTSecretLength)}, nil, true},
+		{AuthConfig{JWTSecret: make([]byte, JWTSecretLength)}, []string{"*"}, false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.cfg.ValidateAdmin(tc.corsOrigins)
+		require.Equal(t, tc.expPass, err == nil, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestNewAuthHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// no authentication is required by default
+	rec := httptest.NewRecorder()
+	NewAuthHandler(AuthConfig{}, next).ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	handler := NewAuthHandler(AuthConfig{Token: "secret-token"}, next)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
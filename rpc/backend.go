@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// BroadcastMode defines the point at which broadcasting a transaction returns.
+type BroadcastMode string
+
+// Supported broadcast modes
+const (
+	// BroadcastAsync returns immediately without waiting for any checks.
+	BroadcastAsync BroadcastMode = "async"
+
+	// BroadcastSync returns once the transaction passed the mempool checks.
+	BroadcastSync BroadcastMode = "sync"
+
+	// BroadcastCommit returns once the transaction is committed in a block.
+	BroadcastCommit BroadcastMode = "commit"
+)
+
+// BroadcastResult defines the result of broadcasting a transaction. The
+// height, gas used and execution result are only set when broadcasting in
+// BroadcastCommit mode.
+type BroadcastResult struct {
+	Hash    []byte
+	Height  int64
+	Code    uint32
+	Log     string
+	Data    []byte
+	GasUsed int64
+}
+
+// BlockTx defines an Ethereum transaction included in a block along with its
+// index among all the transactions of the block, including those that are not
+// Ethereum transactions, i.e. its Tendermint index, which is the index it is
+// served at.
+type BlockTx struct {
+	*types.Transaction
+
+	Index uint64
+}
+
+// SyncStatus defines the synchronization status of a node.
+type SyncStatus struct {
+	LatestBlockHeight int64
+	CatchingUp        bool
+	TxIndexEnabled    bool
+}
+
+// BlockHeader defines the header fields of a committed block. The application
+// hash commits to the state as of the preceding block.
+type BlockHeader struct {
+	Hash          ethcmn.Hash
+	Height        int64
+	Time          int64
+	LastBlockHash []byte
+	AppHash       []byte
+}
+
+// PeerInfo describes a peer the node backing a Backend is connected to.
+type PeerInfo struct {
+	ID         string `json:"id"`
+	Moniker    string `json:"moniker"`
+	ListenAddr string `json:"listenAddr"`
+	Network    string `json:"network"`
+	Version    string `json:"version"`
+	Outbound   bool   `json:"outbound"`
+}
+
+// Backend defines the set of chain queries required to serve the Ethereum
+// JSON-RPC APIs. It decouples the APIs from the source of committed chain data.
+type Backend interface {
+	// LatestBlockNumber returns the height of the latest committed block.
+	LatestBlockNumber() (int64, error)
+
+	// BlockTransactions returns the hash of the block at a given height along
+	// with all the Ethereum transactions it contains, in the order in which
+	// they were included in the block, and their index in the block.
+	BlockTransactions(height int64) (ethcmn.Hash, []BlockTx, error)
+
+	// BlockHeader returns the header of the block at a given height. The time
+	// is given in seconds since the Unix epoch.
+	BlockHeader(height int64) (*BlockHeader, error)
+
+	// PendingTransactions returns all the Ethereum transactions currently
+	// pending in the mempool.
+	PendingTransactions() ([]*types.Transaction, error)
+
+	// BroadcastTx broadcasts the given encoded transaction to the network
+	// using the given mode. An error is returned if the transaction fails the
+	// mempool checks.
+	BroadcastTx(txBytes []byte, mode BroadcastMode) (*BroadcastResult, error)
+
+	// TxResult returns the result of the committed Ethereum transaction with
+	// the given hash as if broadcast in BroadcastCommit mode. A transaction
+	// whose execution failed is committed all the same and its result
+	// reflects the failure. A nil result is returned if no such transaction
+	// was committed.
+	TxResult(hash ethcmn.Hash) (*BroadcastResult, error)
+
+	// AccountTransactions returns the hashes of the committed Ethereum
+	// transactions sent by the given account, or received by it if recipient
+	// is set, ordered by height, on the given 1-based page of the given size,
+	// along with the total number of such transactions. A page past the last
+	// one is empty.
+	AccountTransactions(account ethcmn.Address, recipient bool, page, perPage int) ([]ethcmn.Hash, int, error)
+
+	// QueryStore returns the value stored under a given key in the
+	// application's store with the given name. A nil value is returned if the
+	// key does not exist.
+	QueryStore(storeName string, key []byte) ([]byte, error)
+
+	// ValidatorAddress returns the address of the validator operating the
+	// node backing the Backend.
+	ValidatorAddress() (ethcmn.Address, error)
+
+	// Query performs an application query at the given path with the given
+	// data. An error is returned if the query fails.
+	Query(path string, data []byte) ([]byte, error)
+
+	// SyncStatus returns the synchronization status of the node backing the
+	// Backend.
+	SyncStatus() (*SyncStatus, error)
+
+	// Peers returns the peers the node backing the Backend is connected to.
+	Peers() ([]PeerInfo, error)
+
+	// DialPeers dials the given peers, in the form <ID>@<host>:<port>, from
+	// the node backing the Backend. Persistent peers are redialed whenever
+	// the connection to them is lost.
+	DialPeers(peers []string, persistent bool) error
+}
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// callCacheSize is the number of message call results retained by the
+	// eth_call cache.
+	callCacheSize = 1024
+
+	// maxCachedCallResultSize is the size of the largest return data retained
+	// by the eth_call cache, bounding its memory to a few megabytes. Larger
+	// results are rare for the view functions dashboards poll.
+	maxCachedCallResultSize = 4096
+)
+
+// callCache is a bounded cache of the return data of message calls, keyed by
+// the call and the height of the state it was executed on. As the state of a
+// committed block never changes, a result never goes stale: a view function
+// polled every second is only executed once per block.
+type callCache struct {
+	cache *lru.Cache
+}
+
+// newCallCache returns a reference to a new callCache retaining up to the
+// given number of results, evicting the least recently used.
+func newCallCache(size int) *callCache {
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+
+	return &callCache{cache: cache}
+}
+
+// get returns the cached return data of the given encoded call request, which
+// includes the height of the state the call is executed on.
+func (c *callCache) get(req []byte) ([]byte, bool) {
+	ret, ok := c.cache.Get(ethcrypto.Keccak256Hash(req))
+	if !ok {
+		return nil, false
+	}
+
+	return ret.([]byte), true
+}
+
+// add caches the return data of the given encoded call request, unless it
+// exceeds maxCachedCallResultSize.
+func (c *callCache) add(req, ret []byte) {
+	if len(ret) > maxCachedCallResultSize {
+		return
+	}
+
+	c.cache.Add(ethcrypto.Keccak256Hash(req), ret)
+}
@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/core"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxTraceBlockRange is the maximum number of blocks traced by a single call
+// to TraceBlockRange.
+const maxTraceBlockRange = 32
+
+type (
+	// TraceConfig defines the tracer transactions are traced with and its
+	// tracer specific configuration. The tracer is one of "structLogger", the
+	// default, "stateDiff", "transfers", "callTracer", "prestateTracer" or any
+	// custom tracer the node registered.
+	//
+	// NOTE: JavaScript tracers are not supported.
+	TraceConfig struct {
+		Tracer       string          `json:"tracer"`
+		TracerConfig json.RawMessage `json:"tracerConfig"`
+	}
+
+	// TxTrace is the trace of a single transaction. The result's format
+	// depends on the tracer.
+	TxTrace struct {
+		TxHash ethcmn.Hash     `json:"txHash"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+
+	// BlockTrace is the trace of every Ethereum transaction of a block.
+	BlockTrace struct {
+		Number hexutil.Uint64 `json:"number"`
+		Hash   ethcmn.Hash    `json:"hash"`
+		Traces []TxTrace      `json:"traces"`
+	}
+)
+
+// PublicDebugAPI offers the debugging JSON-RPC methods served under the
+// "debug" namespace.
+type PublicDebugAPI struct {
+	backend Backend
+}
+
+// NewPublicDebugAPI returns a reference to a new PublicDebugAPI using the
+// given Backend for application queries.
+func NewPublicDebugAPI(backend Backend) *PublicDebugAPI {
+	return &PublicDebugAPI{
+		backend: backend,
+	}
+}
+
+// GetStateDiff returns the storage slots and account balances changed by the
+// transaction with the given hash. State diffs are only available if the node
+// records them and only for transactions included in the blocks it retains
+// them for.
+func (api *PublicDebugAPI) GetStateDiff(txHash ethcmn.Hash) (*core.StateDiff, error) {
+	bz, err := api.backend.Query(app.QueryPathStateDiff, txHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	diff := new(core.StateDiff)
+	if err := json.Unmarshal(bz, diff); err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// TraceBlockByNumber re-executes every Ethereum transaction of the block with
+// the given number on top of the state prior to the block and returns their
+// traces. A nil config traces with the struct logger.
+func (api *PublicDebugAPI) TraceBlockByNumber(blockNum ethrpc.BlockNumber, config *TraceConfig) ([]TxTrace, error) {
+	height, err := api.resolveBlockNumber(blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	trace, err := api.traceBlock(height, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return trace.Traces, nil
+}
+
+// TraceBlockRange traces every block from start to end, both inclusive, as
+// TraceBlockByNumber does. At most 32 blocks are traced by a single call.
+func (api *PublicDebugAPI) TraceBlockRange(start, end ethrpc.BlockNumber, config *TraceConfig) ([]BlockTrace, error) {
+	from, err := api.resolveBlockNumber(start)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := api.resolveBlockNumber(end)
+	if err != nil {
+		return nil, err
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d to %d", from, to)
+	}
+
+	if to-from+1 > maxTraceBlockRange {
+		return nil, fmt.Errorf("block range exceeds %d blocks", maxTraceBlockRange)
+	}
+
+	traces := make([]BlockTrace, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		trace, err := api.traceBlock(height, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trace block %d: %v", height, err)
+		}
+
+		traces = append(traces, *trace)
+	}
+
+	return traces, nil
+}
+
+// traceBlock traces the block at the given height through an application
+// query, providing the application the block's header fields and
+// transactions.
+func (api *PublicDebugAPI) traceBlock(height int64, config *TraceConfig) (*BlockTrace, error) {
+	header, err := api.backend.BlockHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	_, txs, err := api.backend.BlockTransactions(height)
+	if err != nil {
+		return nil, err
+	}
+
+	req := app.TraceBlockRequest{
+		Height:        height,
+		Time:          header.Time,
+		LastBlockHash: header.LastBlockHash,
+		Txs:           make([][]byte, len(txs)),
+	}
+
+	if config != nil {
+		req.Tracer = config.Tracer
+		req.TracerConfig = config.TracerConfig
+	}
+
+	for i, tx := range txs {
+		if req.Txs[i], err = rlp.EncodeToBytes(tx.Transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := api.backend.Query(app.QueryPathTraceBlock, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &BlockTrace{Number: hexutil.Uint64(height), Hash: header.Hash}
+	if err := json.Unmarshal(bz, &trace.Traces); err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
+
+// resolveBlockNumber returns the block height for a given block number, the
+// latest and pending block numbers resolving to the latest committed block.
+func (api *PublicDebugAPI) resolveBlockNumber(blockNum ethrpc.BlockNumber) (int64, error) {
+	switch blockNum {
+	case ethrpc.LatestBlockNumber, ethrpc.PendingBlockNumber:
+		return api.backend.LatestBlockNumber()
+
+	default:
+		return blockNum.Int64(), nil
+	}
+}
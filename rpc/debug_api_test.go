@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/core"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStateDiff(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicDebugAPI(backend)
+
+	txHash := ethcmn.BytesToHash([]byte("tx"))
+
+	_, err := api.GetStateDiff(txHash)
+	require.NotNil(t, err)
+
+	expected := core.StateDiff{
+		TxHash: txHash,
+		Storage: []core.StorageDiff{
+			{Address: from, Key: ethcmn.BigToHash(big.NewInt(1)), Current: ethcmn.BigToHash(big.NewInt(2))},
+		},
+		Balances: []core.BalanceDiff{
+			{
+				Address:  from,
+				Original: (*hexutil.Big)(big.NewInt(10)),
+				Current:  (*hexutil.Big)(big.NewInt(4)),
+				Delta:    big.NewInt(-6),
+			},
+		},
+	}
+
+	bz, err := json.Marshal(expected)
+	require.Nil(t, err)
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathStateDiff: {string(txHash.Bytes()): bz},
+	}
+
+	diff, err := api.GetStateDiff(txHash)
+	require.Nil(t, err)
+	require.Equal(t, expected, *diff)
+}
+
+// traceBlockQueries returns the application queries serving the traces, made
+// with the given tracer and config, of the transactions of every block of a
+// test backend.
+func traceBlockQueries(t *testing.T, backend *mockBackend, tracer string, config json.RawMessage) map[string][]byte {
+	queries := make(map[string][]byte)
+
+	for height := int64(1); height <= backend.latest; height++ {
+		header, err := backend.BlockHeader(height)
+		require.Nil(t, err)
+
+		req := app.TraceBlockRequest{
+			Height:        height,
+			Time:          header.Time,
+			LastBlockHash: header.LastBlockHash,
+			Tracer:        tracer,
+			TracerConfig:  config,
+		}
+
+		var results []core.TxTraceResult
+		for _, tx := range backend.blocks[height] {
+			txBytes, err := rlp.EncodeToBytes(tx)
+			require.Nil(t, err)
+
+			req.Txs = append(req.Txs, txBytes)
+			results = append(results, core.TxTraceResult{TxHash: tx.Hash(), Result: map[string]int64{"height": height}})
+		}
+
+		reqBytes, err := json.Marshal(req)
+		require.Nil(t, err)
+
+		queries[string(reqBytes)], err = json.Marshal(results)
+		require.Nil(t, err)
+	}
+
+	return queries
+}
+
+func TestTraceBlockByNumber(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathTraceBlock: traceBlockQueries(t, backend, core.TracerTransfers, nil),
+	}
+
+	api := NewPublicDebugAPI(backend)
+
+	traces, err := api.TraceBlockByNumber(ethrpc.LatestBlockNumber, &TraceConfig{Tracer: core.TracerTransfers})
+	require.Nil(t, err)
+	require.Len(t, traces, 2)
+
+	for i, trace := range traces {
+		require.Equal(t, backend.blocks[2][i].Hash(), trace.TxHash)
+		require.JSONEq(t, `{"height":2}`, string(trace.Result))
+	}
+
+	// the tracer is part of the query
+	_, err = api.TraceBlockByNumber(1, nil)
+	require.NotNil(t, err)
+
+	// a block that does not exist cannot be traced
+	_, err = api.TraceBlockByNumber(3, &TraceConfig{Tracer: core.TracerTransfers})
+	require.NotNil(t, err)
+
+	// the tracer config is part of the query
+	tracerConfig := json.RawMessage(`{"onlyTopCall":true}`)
+	backend.queries[app.QueryPathTraceBlock] = traceBlockQueries(t, backend, core.TracerCall, tracerConfig)
+
+	traces, err = api.TraceBlockByNumber(2, &TraceConfig{Tracer: core.TracerCall, TracerConfig: tracerConfig})
+	require.Nil(t, err)
+	require.Len(t, traces, 2)
+
+	_, err = api.TraceBlockByNumber(2, &TraceConfig{Tracer: core.TracerCall})
+	require.NotNil(t, err)
+}
+
+func TestTraceBlockRange(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathTraceBlock: traceBlockQueries(t, backend, "", nil),
+	}
+
+	api := NewPublicDebugAPI(backend)
+
+	testCases := []struct {
+		start, end      ethrpc.BlockNumber
+		expectedHeights []int64
+		expectErr       bool
+	}{
+		{1, 2, []int64{1, 2}, false},
+		{1, ethrpc.LatestBlockNumber, []int64{1, 2}, false},
+		{2, 2, []int64{2}, false},
+		{2, 1, nil, true},
+		{1, 3, nil, true},
+		{1, 1 + maxTraceBlockRange, nil, true},
+	}
+
+	for i, tc := range testCases {
+		traces, err := api.TraceBlockRange(tc.start, tc.end, nil)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Len(t, traces, len(tc.expectedHeights), fmt.Sprintf("unexpected traces: test case #%d", i))
+
+		for j, trace := range traces {
+			height := tc.expectedHeights[j]
+
+			require.Equal(t, hexutil.Uint64(height), trace.Number, fmt.Sprintf("unexpected number: test case #%d", i))
+			require.Equal(t, blockHash(height), trace.Hash, fmt.Sprintf("unexpected hash: test case #%d", i))
+			require.Len(t, trace.Traces, 2, fmt.Sprintf("unexpected traces: test case #%d", i))
+		}
+	}
+}
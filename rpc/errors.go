@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+)
+
+// Errors go-ethereum returns when rejecting a transaction, which wallets match
+// on textually. Like every error returned by a JSON-RPC method, they are
+// served with the -32000 server error code, as by go-ethereum.
+const (
+	errIntrinsicGasTooLow = "intrinsic gas too low"
+	errUnderpriced        = "transaction underpriced"
+	errOversizedData      = "oversized data"
+	errInvalidSender      = "invalid sender"
+	errInvalidChainID     = "invalid chain id for signer"
+	errMaxInitCodeSize    = "max initcode size exceeded"
+)
+
+// sdkErrorMessages maps the codes of the SDK's errors, raised in the root
+// codespace, to the go-ethereum error returned for the same cause.
+var sdkErrorMessages = map[sdk.CodeType]string{
+	sdk.CodeInsufficientFunds: types.AbortInsufficientFunds,
+	sdk.CodeInsufficientCoins: types.AbortInsufficientFunds,
+	sdk.CodeOutOfGas:          errIntrinsicGasTooLow,
+	sdk.CodeUnauthorized:      errInvalidSender,
+}
+
+// ethermintErrorMessages maps the codes of Ethermint's errors, raised in the
+// application's codespace, to the go-ethereum error returned for the same
+// cause.
+var ethermintErrorMessages = map[sdk.CodeType]string{
+	types.CodeInvalidChainID: errInvalidChainID,
+	types.CodeInvalidSender:  errInvalidSender,
+	types.CodeTxTooLarge:     errOversizedData,
+	types.CodeGasPriceTooLow: errUnderpriced,
+	types.CodeIntrinsicGas:   errIntrinsicGasTooLow,
+	types.CodePayloadGas:     errIntrinsicGasTooLow,
+	types.CodeInitCodeSize:   errMaxInitCodeSize,
+}
+
+// gethErrorMessage returns the go-ethereum error returned for the cause of the
+// given ABCI code, given the codespace Ethermint's errors are raised in, or
+// sdk.CodespaceUndefined if unknown. A boolean is returned reflecting if the
+// code has such an error.
+func gethErrorMessage(codespace sdk.CodespaceType, code uint32) (string, bool) {
+	var messages map[sdk.CodeType]string
+
+	switch space := sdk.CodespaceType(code >> 16); {
+	case space == sdk.CodespaceRoot:
+		messages = sdkErrorMessages
+
+	case space == codespace && codespace != sdk.CodespaceUndefined:
+		messages = ethermintErrorMessages
+	}
+
+	msg, ok := messages[sdk.CodeType(code&0xffff)]
+	return msg, ok
+}
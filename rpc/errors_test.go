@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGethErrorMessage(t *testing.T) {
+	codespace := sdk.CodespaceType(3)
+
+	testCases := []struct {
+		codespace   sdk.CodespaceType
+		code        sdk.ABCICodeType
+		expectedMsg string
+		expectOK    bool
+	}{
+		{codespace, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInsufficientCoins), types.AbortInsufficientFunds, true},
+		{codespace, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), "invalid sender", true},
+		{codespace, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInternal), "", false},
+		{codespace, sdk.ToABCICode(codespace, types.CodeInvalidChainID), "invalid chain id for signer", true},
+		{codespace, sdk.ToABCICode(codespace, types.CodePayloadGas), "intrinsic gas too low", true},
+		{codespace, sdk.ToABCICode(codespace, types.CodeInitCodeSize), "max initcode size exceeded", true},
+		{codespace, sdk.ToABCICode(codespace, types.CodeNestedTx), "", false},
+
+		// Ethermint's codes are only mapped in its codespace
+		{codespace, sdk.ToABCICode(codespace+1, types.CodeGasPriceTooLow), "", false},
+		{sdk.CodespaceUndefined, sdk.ToABCICode(sdk.CodespaceUndefined, types.CodeGasPriceTooLow), "", false},
+	}
+
+	for i, tc := range testCases {
+		msg, ok := gethErrorMessage(tc.codespace, uint32(tc.code))
+		require.Equal(t, tc.expectOK, ok, fmt.Sprintf("unexpected result: test case #%d", i))
+		require.Equal(t, tc.expectedMsg, msg, fmt.Sprintf("unexpected message: test case #%d", i))
+	}
+}
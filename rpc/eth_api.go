@@ -0,0 +1,287 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cosmos/ethermint/app"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethProtocolVersion is the version of the Ethereum wire protocol reported by
+// the go-ethereum release Ethermint's EVM is taken from.
+const ethProtocolVersion = 63
+
+// PublicEthAPI offers the Ethereum JSON-RPC methods served under the "eth"
+// namespace.
+type PublicEthAPI struct {
+	backend   Backend
+	chainID   *big.Int
+	gpo       *GasPriceOracle
+	etherbase *Etherbase
+	calls     *callCache
+
+	logPollInterval time.Duration
+}
+
+// NewPublicEthAPI returns a reference to a new PublicEthAPI using the given
+// Backend for chain queries. The chain ID is used to derive transaction
+// senders, the GasPriceOracle to suggest gas prices and the Etherbase as the
+// reported coinbase.
+func NewPublicEthAPI(backend Backend, chainID *big.Int, gpo *GasPriceOracle, etherbase *Etherbase) *PublicEthAPI {
+	return &PublicEthAPI{
+		backend:   backend,
+		chainID:   chainID,
+		gpo:       gpo,
+		etherbase: etherbase,
+		calls:     newCallCache(callCacheSize),
+
+		logPollInterval: defaultLogPollInterval,
+	}
+}
+
+// ProtocolVersion returns the Ethereum protocol version reported by
+// go-ethereum, which clients check for compatibility. Ethermint nodes do not
+// speak the Ethereum wire protocol, as they are networked by Tendermint.
+func (api *PublicEthAPI) ProtocolVersion() hexutil.Uint {
+	return hexutil.Uint(ethProtocolVersion)
+}
+
+// Coinbase returns the fee recipient address configured by the node operator.
+// If none is configured, the address of the validator operating the node is
+// returned instead.
+func (api *PublicEthAPI) Coinbase() (ethcmn.Address, error) {
+	if addr, ok := api.etherbase.Get(); ok {
+		return addr, nil
+	}
+
+	return api.backend.ValidatorAddress()
+}
+
+// GasPrice returns a suggested gas price based on the gas prices paid in the
+// most recent blocks.
+func (api *PublicEthAPI) GasPrice() (*hexutil.Big, error) {
+	price, err := api.gpo.SuggestPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	return (*hexutil.Big)(price), nil
+}
+
+// MaxPriorityFeePerGas returns a suggested priority fee per unit of gas. As
+// Ethermint does not burn a base fee, the entire gas price is paid to the
+// proposer and the suggestion equals the suggested gas price.
+func (api *PublicEthAPI) MaxPriorityFeePerGas() (*hexutil.Big, error) {
+	return api.GasPrice()
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction at a given index
+// within the block at a given height, indices counting every transaction of
+// the block. A nil transaction is returned if the block does not contain an
+// Ethereum transaction at the given index.
+func (api *PublicEthAPI) GetTransactionByBlockNumberAndIndex(
+	blockNum ethrpc.BlockNumber, index hexutil.Uint,
+) (*RPCTransaction, error) {
+
+	height, err := api.resolveBlockNumber(blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHash, txs, err := api.backend.BlockTransactions(height)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range txs {
+		if tx.Index == uint64(index) {
+			return NewRPCTransaction(tx.Transaction, blockHash, uint64(height), tx.Index, api.chainID), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetTransactionReceipt returns the receipt of the committed transaction with
+// the given hash. A transaction whose execution failed, e.g. as it reverted
+// or ran out of gas, is still included in its block and its receipt reports
+// a status of 0 along with the gas it used. A nil receipt is returned if the
+// transaction is unknown or still pending.
+func (api *PublicEthAPI) GetTransactionReceipt(hash ethcmn.Hash) (*RPCReceipt, error) {
+	res, err := api.backend.TxResult(hash)
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	return newBlockReceipt(api.backend, hash, res, api.chainID)
+}
+
+// newBlockReceipt returns the receipt of the committed transaction with the
+// given hash and result, located among the Ethereum transactions of its block.
+// As in Ethereum, its logs are indexed within the block, following those
+// emitted by the preceding transactions.
+func newBlockReceipt(backend Backend, hash ethcmn.Hash, res *BroadcastResult, chainID *big.Int) (*RPCReceipt, error) {
+	blockHash, txs, err := backend.BlockTransactions(res.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	var logIndex uint
+	for _, tx := range txs {
+		if tx.Hash() == hash {
+			return NewRPCReceipt(tx.Transaction, blockHash, uint64(res.Height), tx.Index, logIndex, res, chainID)
+		}
+
+		prevRes, err := backend.TxResult(tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+
+		if prevRes == nil {
+			continue
+		}
+
+		data, err := decodeTxResult(prevRes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result of transaction %s: %v", tx.Hash().Hex(), err)
+		}
+
+		logIndex += uint(len(data.Logs))
+	}
+
+	return nil, fmt.Errorf("transaction %s not found in block %d", hash.Hex(), res.Height)
+}
+
+// SendRawTransaction broadcasts an RLP encoded signed transaction and returns
+// its hash once it passed the mempool checks.
+func (api *PublicEthAPI) SendRawTransaction(rawTx hexutil.Bytes) (ethcmn.Hash, error) {
+	tx, err := decodeRawTransaction(rawTx)
+	if err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	if _, err := api.backend.BroadcastTx(rawTx, BroadcastSync); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// EstimateGas returns the lowest gas limit the given message call executes
+// successfully with on the state as of the given block, which defaults to
+// the latest block. The estimate is the result of a binary search over actual
+// executions performed by the application (see core.EstimateGas).
+func (api *PublicEthAPI) EstimateGas(args CallArgs, blockNum *ethrpc.BlockNumber) (hexutil.Uint64, error) {
+	reqBytes, err := api.callRequest(args, blockNum)
+	if err != nil {
+		return 0, err
+	}
+
+	bz, err := api.backend.Query(app.QueryPathEstimateGas, reqBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	var gas uint64
+	if err := json.Unmarshal(bz, &gas); err != nil {
+		return 0, err
+	}
+
+	return hexutil.Uint64(gas), nil
+}
+
+// Call executes the given message call on the state as of the given block,
+// which defaults to the latest block, without creating a transaction and
+// returns its return data. An error is returned if the call fails, including
+// the revert reason if it reverted.
+//
+// Results are cached per block, as dashboards commonly poll the same view
+// functions far more often than blocks are committed.
+func (api *PublicEthAPI) Call(args CallArgs, blockNum *ethrpc.BlockNumber) (hexutil.Bytes, error) {
+	reqBytes, err := api.callRequest(args, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret, ok := api.calls.get(reqBytes); ok {
+		return ret, nil
+	}
+
+	ret, err := api.backend.Query(app.QueryPathCall, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	api.calls.add(reqBytes, ret)
+	return ret, nil
+}
+
+// callRequest returns the encoded application request executing the given
+// message call on the state as of the given block, which defaults to the
+// latest block.
+func (api *PublicEthAPI) callRequest(args CallArgs, blockNum *ethrpc.BlockNumber) ([]byte, error) {
+	num := ethrpc.LatestBlockNumber
+	if blockNum != nil {
+		num = *blockNum
+	}
+
+	height, err := api.resolveBlockNumber(num)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := api.backend.BlockHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	req := app.EstimateGasRequest{
+		Height:        height,
+		Time:          header.Time,
+		LastBlockHash: header.LastBlockHash,
+		To:            args.To,
+	}
+
+	if args.From != nil {
+		req.From = *args.From
+	}
+
+	if args.Gas != nil {
+		req.Gas = uint64(*args.Gas)
+	}
+
+	if args.GasPrice != nil {
+		req.GasPrice = args.GasPrice.ToInt()
+	}
+
+	if args.Value != nil {
+		req.Value = args.Value.ToInt()
+	}
+
+	if args.Input != nil {
+		req.Data = *args.Input
+	} else if args.Data != nil {
+		req.Data = *args.Data
+	}
+
+	return json.Marshal(req)
+}
+
+// resolveBlockNumber returns the block height for a given block number. As
+// Tendermint provides instant finality, both the latest and pending block
+// numbers resolve to the latest committed block.
+func (api *PublicEthAPI) resolveBlockNumber(blockNum ethrpc.BlockNumber) (int64, error) {
+	switch blockNum {
+	case ethrpc.LatestBlockNumber, ethrpc.PendingBlockNumber:
+		return api.backend.LatestBlockNumber()
+
+	default:
+		return blockNum.Int64(), nil
+	}
+}
@@ -0,0 +1,487 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+var (
+	testChainID       = big.NewInt(3)
+	testValidatorAddr = ethcmn.HexToAddress("0x00000000000000000000000000000000000000aa")
+)
+
+// mockBackend implements the Backend interface using in-memory blocks.
+type mockBackend struct {
+	blocks map[int64][]*types.Transaction
+	latest int64
+
+	pendingTxs   []*types.Transaction
+	broadcastTxs [][]byte
+	results      map[ethcmn.Hash]*BroadcastResult
+	deliverData  types.ResultData
+	stores       map[string]map[string][]byte
+	queries      map[string]map[string][]byte
+	catchingUp   bool
+	peers        []PeerInfo
+}
+
+func (mb *mockBackend) LatestBlockNumber() (int64, error) {
+	return mb.latest, nil
+}
+
+func (mb *mockBackend) BlockTransactions(height int64) (ethcmn.Hash, []BlockTx, error) {
+	txs := make([]BlockTx, len(mb.blocks[height]))
+	for i, tx := range mb.blocks[height] {
+		txs[i] = BlockTx{Transaction: tx, Index: uint64(i)}
+	}
+
+	return blockHash(height), txs, nil
+}
+
+func (mb *mockBackend) BlockHeader(height int64) (*BlockHeader, error) {
+	if height < 1 || height > mb.latest {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+
+	return &BlockHeader{
+		Hash:          blockHash(height),
+		Height:        height,
+		Time:          height * 10,
+		LastBlockHash: blockHash(height - 1).Bytes(),
+		AppHash:       appHash(height),
+	}, nil
+}
+
+func (mb *mockBackend) PendingTransactions() ([]*types.Transaction, error) {
+	return mb.pendingTxs, nil
+}
+
+// BroadcastTx commits every transaction in a new block when broadcasting in
+// BroadcastCommit mode, executed with the configured result data using 21000
+// gas.
+func (mb *mockBackend) BroadcastTx(txBytes []byte, mode BroadcastMode) (*BroadcastResult, error) {
+	mb.broadcastTxs = append(mb.broadcastTxs, txBytes)
+	res := &BroadcastResult{Hash: tmhash.Sum(txBytes)}
+
+	if mode == BroadcastCommit {
+		tx, err := decodeRawTransaction(txBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		mb.latest++
+		mb.blocks[mb.latest] = []*types.Transaction{tx}
+
+		res.Height = mb.latest
+		res.GasUsed = 21000
+
+		data := mb.deliverData
+		data.GasUsed = 21000
+
+		if tx.To() == nil {
+			from, err := tx.VerifySig(testChainID)
+			if err != nil {
+				return nil, err
+			}
+
+			data.ContractAddress = ethcrypto.CreateAddress(from, tx.Nonce())
+		}
+
+		if res.Data, err = types.EncodeResultData(resultCdc, data); err != nil {
+			return nil, err
+		}
+
+		if mb.results == nil {
+			mb.results = make(map[ethcmn.Hash]*BroadcastResult)
+		}
+
+		mb.results[tx.Hash()] = res
+	}
+
+	return res, nil
+}
+
+func (mb *mockBackend) TxResult(hash ethcmn.Hash) (*BroadcastResult, error) {
+	return mb.results[hash], nil
+}
+
+// AccountTransactions scans the committed blocks in order.
+func (mb *mockBackend) AccountTransactions(
+	account ethcmn.Address, recipient bool, page, perPage int,
+) ([]ethcmn.Hash, int, error) {
+
+	hashes := []ethcmn.Hash{}
+
+	for height := int64(1); height <= mb.latest; height++ {
+		for _, tx := range mb.blocks[height] {
+			from, err := tx.VerifySig(testChainID)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if (!recipient && from == account) || (recipient && tx.To() != nil && *tx.To() == account) {
+				hashes = append(hashes, tx.Hash())
+			}
+		}
+	}
+
+	total := len(hashes)
+	start, end := (page-1)*perPage, page*perPage
+
+	if start > total {
+		start = total
+	}
+
+	if end > total {
+		end = total
+	}
+
+	return hashes[start:end], total, nil
+}
+
+func (mb *mockBackend) QueryStore(storeName string, key []byte) ([]byte, error) {
+	return mb.stores[storeName][string(key)], nil
+}
+
+func (mb *mockBackend) ValidatorAddress() (ethcmn.Address, error) {
+	return testValidatorAddr, nil
+}
+
+func (mb *mockBackend) Query(path string, data []byte) ([]byte, error) {
+	bz, ok := mb.queries[path][string(data)]
+	if !ok {
+		return nil, fmt.Errorf("query failed: %s", path)
+	}
+
+	return bz, nil
+}
+
+func (mb *mockBackend) SyncStatus() (*SyncStatus, error) {
+	return &SyncStatus{LatestBlockHeight: mb.latest, CatchingUp: mb.catchingUp, TxIndexEnabled: true}, nil
+}
+
+func (mb *mockBackend) Peers() ([]PeerInfo, error) {
+	return mb.peers, nil
+}
+
+// DialPeers connects to every peer immediately.
+func (mb *mockBackend) DialPeers(peers []string, persistent bool) error {
+	for _, peer := range peers {
+		mb.peers = append(mb.peers, PeerInfo{ID: peer, Outbound: true})
+	}
+
+	return nil
+}
+
+func blockHash(height int64) ethcmn.Hash {
+	return ethcmn.BigToHash(big.NewInt(height + 1000))
+}
+
+// appHash returns the 20-byte application hash of the header at the given
+// height.
+func appHash(height int64) []byte {
+	return ethcmn.LeftPadBytes(big.NewInt(height+2000).Bytes(), 20)
+}
+
+func newTestBackend(t *testing.T) (*mockBackend, ethcmn.Address) {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	backend := &mockBackend{blocks: make(map[int64][]*types.Transaction), latest: 2}
+
+	for height := int64(1); height <= backend.latest; height++ {
+		for i := 0; i < 2; i++ {
+			tx := types.NewTransaction(
+				uint64(height*10+int64(i)), ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil,
+			)
+			tx.Sign(testChainID, priv)
+
+			backend.blocks[height] = append(backend.blocks[height], tx)
+		}
+	}
+
+	return backend, ethcrypto.PubkeyToAddress(priv.PublicKey)
+}
+
+func TestGetTransactionByBlockNumberAndIndex(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	testCases := []struct {
+		blockNum      ethrpc.BlockNumber
+		index         hexutil.Uint
+		expectedBlock int64
+		expectNil     bool
+	}{
+		{1, 0, 1, false},
+		{1, 1, 1, false},
+		{1, 2, 1, true},
+		{3, 0, 3, true},
+		{ethrpc.LatestBlockNumber, 1, 2, false},
+		{ethrpc.PendingBlockNumber, 0, 2, false},
+	}
+
+	for i, tc := range testCases {
+		rpcTx, err := api.GetTransactionByBlockNumberAndIndex(tc.blockNum, tc.index)
+		require.Nil(t, err, "unexpected error: test case #%d", i)
+
+		if tc.expectNil {
+			require.Nil(t, rpcTx, "expected nil transaction: test case #%d", i)
+			continue
+		}
+
+		tx := backend.blocks[tc.expectedBlock][tc.index]
+
+		require.NotNil(t, rpcTx, "unexpected nil transaction: test case #%d", i)
+		require.Equal(t, blockHash(tc.expectedBlock), rpcTx.BlockHash, "test case #%d", i)
+		require.Equal(t, big.NewInt(tc.expectedBlock), rpcTx.BlockNumber.ToInt(), "test case #%d", i)
+		require.Equal(t, tc.index, rpcTx.TransactionIndex, "test case #%d", i)
+		require.Equal(t, tx.Hash(), rpcTx.Hash, "test case #%d", i)
+		require.Equal(t, from, rpcTx.From, "test case #%d", i)
+	}
+}
+
+func TestSendRawTransaction(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	tx := backend.blocks[1][0]
+
+	rawTx, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	txHash, err := api.SendRawTransaction(rawTx)
+	require.Nil(t, err)
+	require.Equal(t, tx.Hash(), txHash)
+	require.Equal(t, [][]byte{rawTx}, backend.broadcastTxs)
+
+	_, err = api.SendRawTransaction([]byte("invalid"))
+	require.NotNil(t, err)
+}
+
+func TestGetTransactionReceipt(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	log := types.Log{Address: ethcmn.HexToAddress("0x01"), Topics: []ethcmn.Hash{{0x02}}, Data: []byte{0x03}}
+
+	testCases := []struct {
+		data           types.ResultData
+		expectedStatus uint
+		expectedLogs   int
+	}{
+		{types.ResultData{Logs: []types.Log{log, log}}, 1, 2},
+		// a failed transaction, e.g. reverted or out of gas, is included all
+		// the same and charged the gas it used, its logs being reverted
+		{types.ResultData{Failed: true}, 0, 0},
+	}
+
+	for i, tc := range testCases {
+		tx := types.NewTransaction(uint64(i), ethcmn.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil)
+		tx.Sign(testChainID, priv)
+
+		rawTx, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		backend.deliverData = tc.data
+
+		res, err := backend.BroadcastTx(rawTx, BroadcastCommit)
+		require.Nil(t, err)
+		require.Zero(t, res.Code)
+
+		receipt, err := api.GetTransactionReceipt(tx.Hash())
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.NotNil(t, receipt, fmt.Sprintf("unexpected nil receipt: test case #%d", i))
+		require.Equal(t, tx.Hash(), receipt.TransactionHash, fmt.Sprintf("unexpected hash: test case #%d", i))
+		require.Equal(t, blockHash(backend.latest), receipt.BlockHash, fmt.Sprintf("unexpected block: test case #%d", i))
+		require.Equal(t, tc.expectedStatus, uint(receipt.Status), fmt.Sprintf("unexpected status: test case #%d", i))
+		require.Equal(t, uint64(21000), uint64(receipt.GasUsed), fmt.Sprintf("unexpected gas used: test case #%d", i))
+		require.Len(t, receipt.Logs, tc.expectedLogs, fmt.Sprintf("unexpected logs: test case #%d", i))
+
+		for j, rpcLog := range receipt.Logs {
+			require.Equal(t, &ethtypes.Log{
+				Address:     log.Address,
+				Topics:      log.Topics,
+				Data:        log.Data,
+				BlockNumber: uint64(backend.latest),
+				TxHash:      tx.Hash(),
+				BlockHash:   blockHash(backend.latest),
+				Index:       uint(j),
+			}, rpcLog, fmt.Sprintf("unexpected log: test case #%d", i))
+		}
+	}
+
+	// the logs of a receipt are indexed within the block
+	encode := func(data types.ResultData) []byte {
+		bz, err := types.EncodeResultData(resultCdc, data)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	txs := backend.blocks[1]
+	backend.results[txs[0].Hash()] = &BroadcastResult{Height: 1, Data: encode(types.ResultData{Logs: []types.Log{log, log}})}
+	backend.results[txs[1].Hash()] = &BroadcastResult{Height: 1, Data: encode(types.ResultData{Logs: []types.Log{log}})}
+
+	receipt, err := api.GetTransactionReceipt(txs[1].Hash())
+	require.Nil(t, err)
+	require.Len(t, receipt.Logs, 1)
+	require.Equal(t, uint(1), receipt.Logs[0].TxIndex)
+	require.Equal(t, uint(2), receipt.Logs[0].Index)
+
+	// unknown transactions have no receipt
+	receipt, err = api.GetTransactionReceipt(ethcmn.Hash{})
+	require.Nil(t, err)
+	require.Nil(t, receipt)
+}
+
+func TestEstimateGas(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	to := ethcmn.HexToAddress("0x01")
+	data := hexutil.Bytes{0x01, 0x02}
+
+	// estimateQuery returns the application query estimating the gas of the
+	// test call on the state as of the block at the given height
+	estimateQuery := func(height int64, gas uint64) string {
+		header, err := backend.BlockHeader(height)
+		require.Nil(t, err)
+
+		bz, err := json.Marshal(app.EstimateGasRequest{
+			Height:        height,
+			Time:          header.Time,
+			LastBlockHash: header.LastBlockHash,
+			From:          from,
+			To:            &to,
+			Gas:           gas,
+			Value:         big.NewInt(5),
+			Data:          data,
+		})
+		require.Nil(t, err)
+
+		return string(bz)
+	}
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathEstimateGas: {
+			estimateQuery(2, 0):     []byte("21136"),
+			estimateQuery(1, 30000): []byte("21137"),
+		},
+	}
+
+	value := (*hexutil.Big)(big.NewInt(5))
+	gas := hexutil.Uint64(30000)
+	height := ethrpc.BlockNumber(1)
+
+	estimate, err := api.EstimateGas(CallArgs{From: &from, To: &to, Value: value, Data: &data}, nil)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Uint64(21136), estimate)
+
+	// the input takes precedence over the data
+	estimate, err = api.EstimateGas(CallArgs{From: &from, To: &to, Value: value, Data: &hexutil.Bytes{0xff}, Input: &data, Gas: &gas}, &height)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Uint64(21137), estimate)
+
+	_, err = api.EstimateGas(CallArgs{From: &from, To: &to, Value: value, Data: &hexutil.Bytes{}}, nil)
+	require.NotNil(t, err)
+
+	height = ethrpc.BlockNumber(3)
+	_, err = api.EstimateGas(CallArgs{From: &from, To: &to, Value: value, Data: &data}, &height)
+	require.NotNil(t, err)
+}
+
+func TestCall(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	to := ethcmn.HexToAddress("0x01")
+	data := hexutil.Bytes{0x01, 0x02}
+	args := CallArgs{From: &from, To: &to, Data: &data}
+
+	// callQuery returns the application query executing the test call on the
+	// state as of the block at the given height
+	callQuery := func(height int64) string {
+		header, err := backend.BlockHeader(height)
+		require.Nil(t, err)
+
+		bz, err := json.Marshal(app.EstimateGasRequest{
+			Height:        height,
+			Time:          header.Time,
+			LastBlockHash: header.LastBlockHash,
+			From:          from,
+			To:            &to,
+			Data:          data,
+		})
+		require.Nil(t, err)
+
+		return string(bz)
+	}
+
+	large := make([]byte, maxCachedCallResultSize+1)
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathCall: {
+			callQuery(1): large,
+			callQuery(2): {0x2a},
+		},
+	}
+
+	ret, err := api.Call(args, nil)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Bytes{0x2a}, ret)
+
+	height := ethrpc.BlockNumber(1)
+
+	ret, err = api.Call(args, &height)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Bytes(large), ret)
+
+	// results are served from the cache until a new block is committed,
+	// except for those too large to be cached
+	delete(backend.queries[app.QueryPathCall], callQuery(1))
+	delete(backend.queries[app.QueryPathCall], callQuery(2))
+
+	ret, err = api.Call(args, nil)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Bytes{0x2a}, ret)
+
+	_, err = api.Call(args, &height)
+	require.NotNil(t, err)
+
+	backend.latest++
+
+	_, err = api.Call(args, nil)
+	require.NotNil(t, err)
+
+	// failed calls are not cached
+	backend.queries[app.QueryPathCall][callQuery(3)] = []byte{0x2b}
+
+	ret, err = api.Call(args, nil)
+	require.Nil(t, err)
+	require.Equal(t, hexutil.Bytes{0x2b}, ret)
+}
+
+func TestProtocolVersion(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	require.Equal(t, hexutil.Uint(63), api.ProtocolVersion())
+}
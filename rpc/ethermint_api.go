@@ -0,0 +1,489 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/denom"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Roles of a node with respect to the history it can serve.
+const (
+	// NodeRoleArchive is the role of a node retaining the state of every
+	// height.
+	NodeRoleArchive = "archive"
+
+	// NodeRoleFull is the role of a node pruning historical state.
+	NodeRoleFull = "full"
+)
+
+// Directions of the transactions of an account's history.
+const (
+	// AccountTxsSent selects the transactions sent by an account.
+	AccountTxsSent = "from"
+
+	// AccountTxsReceived selects the transactions received by an account.
+	AccountTxsReceived = "to"
+)
+
+// maxAccountTxsPerPage is the maximum size of a page of an account's
+// transaction history, which is the maximum Tendermint's indexer serves.
+const maxAccountTxsPerPage = 100
+
+// NodeInfo defines the metadata of the node backing the RPC server, allowing
+// gateways to route historical queries to nodes able to serve them.
+type NodeInfo struct {
+	Role                    string         `json:"role"`
+	Pruning                 string         `json:"pruning"`
+	EarliestQueryableHeight hexutil.Uint64 `json:"earliestQueryableHeight"`
+	LatestBlockNumber       hexutil.Uint64 `json:"latestBlockNumber"`
+	TxIndexEnabled          bool           `json:"txIndexEnabled"`
+	StateDiffsEnabled       bool           `json:"stateDiffsEnabled"`
+	CatchingUp              bool           `json:"catchingUp"`
+}
+
+// AccountTxsPage defines a page of the transaction history of an account
+// along with the total number of transactions in the history.
+type AccountTxsPage struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	Page         hexutil.Uint      `json:"page"`
+	PerPage      hexutil.Uint      `json:"perPage"`
+	Total        hexutil.Uint      `json:"total"`
+}
+
+// NetworkConfig defines the metadata of the network served, as configured by
+// the operator, that wallets display to their users.
+type NetworkConfig struct {
+	// ChainName is the human readable name of the chain
+	ChainName string
+
+	// RPCURLs are the public URLs of the chain's JSON-RPC servers
+	RPCURLs []string
+
+	// BlockExplorerURLs are the URLs of the chain's block explorers
+	BlockExplorerURLs []string
+}
+
+// NativeCurrency defines the native asset of a chain as displayed by wallets.
+type NativeCurrency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// ChainConfig defines the configuration wallet connectors need to add the
+// chain to a wallet. It follows the parameters of EIP-3085's
+// wallet_addEthereumChain so that it can be passed to the wallet as is.
+type ChainConfig struct {
+	ChainID           *hexutil.Big   `json:"chainId"`
+	ChainName         string         `json:"chainName"`
+	NativeCurrency    NativeCurrency `json:"nativeCurrency"`
+	RPCURLs           []string       `json:"rpcUrls"`
+	BlockExplorerURLs []string       `json:"blockExplorerUrls,omitempty"`
+}
+
+// NonceRange defines an inclusive range of nonces.
+type NonceRange struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// NonceQueue describes the transactions of a single sender pending in the
+// mempool relative to the sender's nonce as of the latest committed block.
+// Pending nonces follow the committed nonce without interruption and are
+// executable in order, while queued nonces are blocked by the gaps preceding
+// them until transactions filling the gaps are submitted. Stale nonces were
+// already consumed and their transactions will be evicted.
+type NonceQueue struct {
+	Sender  ethcmn.Address   `json:"sender"`
+	Nonce   hexutil.Uint64   `json:"nonce"`
+	Pending []hexutil.Uint64 `json:"pending"`
+	Queued  []hexutil.Uint64 `json:"queued"`
+	Gaps    []NonceRange     `json:"gaps"`
+	Stale   []hexutil.Uint64 `json:"stale"`
+}
+
+// newNonceQueue returns the NonceQueue of a sender with the given committed
+// nonce and nonces of pending transactions, in any order and possibly
+// duplicated by replacement transactions.
+func newNonceQueue(sender ethcmn.Address, nonce uint64, nonces []uint64) *NonceQueue {
+	queue := &NonceQueue{
+		Sender:  sender,
+		Nonce:   hexutil.Uint64(nonce),
+		Pending: []hexutil.Uint64{},
+		Queued:  []hexutil.Uint64{},
+		Gaps:    []NonceRange{},
+		Stale:   []hexutil.Uint64{},
+	}
+
+	nonces = append([]uint64(nil), nonces...)
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	next := nonce
+	for i, n := range nonces {
+		if i > 0 && n == nonces[i-1] {
+			continue
+		}
+
+		switch {
+		case n < nonce:
+			queue.Stale = append(queue.Stale, hexutil.Uint64(n))
+
+		case n == next && len(queue.Gaps) == 0:
+			queue.Pending = append(queue.Pending, hexutil.Uint64(n))
+
+		default:
+			if n > next {
+				queue.Gaps = append(queue.Gaps, NonceRange{From: hexutil.Uint64(next), To: hexutil.Uint64(n - 1)})
+			}
+
+			queue.Queued = append(queue.Queued, hexutil.Uint64(n))
+		}
+
+		if n >= next {
+			next = n + 1
+		}
+	}
+
+	return queue
+}
+
+// PublicEthermintAPI offers the Ethermint specific JSON-RPC methods served
+// under the "ethermint" namespace.
+type PublicEthermintAPI struct {
+	backend Backend
+	chainID *big.Int
+	network NetworkConfig
+}
+
+// NewPublicEthermintAPI returns a reference to a new PublicEthermintAPI using
+// the given Backend for chain queries. The chain ID is used to derive
+// transaction senders and, along with the network configuration, reported to
+// wallets.
+func NewPublicEthermintAPI(backend Backend, chainID *big.Int, network NetworkConfig) *PublicEthermintAPI {
+	return &PublicEthermintAPI{
+		backend: backend,
+		chainID: chainID,
+		network: network,
+	}
+}
+
+// PendingTransactions returns the Ethereum transactions pending in the
+// mempool. If a sender is given, only the transactions sent by it are
+// returned. A mixed-case sender must carry a valid EIP-55 checksum.
+func (api *PublicEthermintAPI) PendingTransactions(sender *types.HexAddress) ([]*RPCTransaction, error) {
+	txs, err := api.backend.PendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcTxs := make([]*RPCTransaction, 0, len(txs))
+	for _, tx := range txs {
+		rpcTx := NewPendingRPCTransaction(tx, api.chainID)
+
+		if sender != nil && rpcTx.From != sender.Address() {
+			continue
+		}
+
+		rpcTxs = append(rpcTxs, rpcTx)
+	}
+
+	return rpcTxs, nil
+}
+
+// NonceQueues returns, for every sender of Ethereum transactions pending in
+// the mempool ordered by address, which nonces are pending and which gaps
+// block them, allowing operators to diagnose stuck transactions. If a sender
+// is given, only its queue is returned. A mixed-case sender must carry a valid
+// EIP-55 checksum.
+func (api *PublicEthermintAPI) NonceQueues(sender *types.HexAddress) ([]*NonceQueue, error) {
+	txs, err := api.backend.PendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	nonces := make(map[ethcmn.Address][]uint64)
+	for _, tx := range txs {
+		from, err := tx.VerifySig(api.chainID)
+		if err != nil || (sender != nil && from != sender.Address()) {
+			continue
+		}
+
+		nonces[from] = append(nonces[from], tx.Nonce())
+	}
+
+	senders := make([]ethcmn.Address, 0, len(nonces))
+	for from := range nonces {
+		senders = append(senders, from)
+	}
+
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+
+	if len(senders) == 0 {
+		return []*NonceQueue{}, nil
+	}
+
+	committed, err := api.committedNonces(senders)
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make([]*NonceQueue, len(senders))
+	for i, from := range senders {
+		queues[i] = newNonceQueue(from, committed[i], nonces[from])
+	}
+
+	return queues, nil
+}
+
+// committedNonces returns the nonces, as of the latest committed block, of the
+// given accounts in a single query.
+func (api *PublicEthermintAPI) committedNonces(addrs []ethcmn.Address) ([]uint64, error) {
+	data, err := json.Marshal(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := api.backend.Query(app.QueryPathNonces, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonces []uint64
+	if err := json.Unmarshal(bz, &nonces); err != nil {
+		return nil, err
+	}
+
+	if len(nonces) != len(addrs) {
+		return nil, fmt.Errorf("expected %d nonces, got %d", len(addrs), len(nonces))
+	}
+
+	return nonces, nil
+}
+
+// BroadcastRawTransaction broadcasts an RLP encoded signed transaction using
+// the given broadcast mode and returns its hash.
+func (api *PublicEthermintAPI) BroadcastRawTransaction(rawTx hexutil.Bytes, mode BroadcastMode) (ethcmn.Hash, error) {
+	tx, err := decodeRawTransaction(rawTx)
+	if err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	if _, err := api.backend.BroadcastTx(rawTx, mode); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// SendAndWait broadcasts an RLP encoded signed transaction and blocks until
+// it is committed in a block. The transaction's receipt is returned, sparing
+// clients from polling for it. As Tendermint provides instant finality, the
+// receipt is final.
+func (api *PublicEthermintAPI) SendAndWait(rawTx hexutil.Bytes) (*RPCReceipt, error) {
+	tx, err := decodeRawTransaction(rawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := api.backend.BroadcastTx(rawTx, BroadcastCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBlockReceipt(api.backend, tx.Hash(), res, api.chainID)
+}
+
+// NodeInfo returns the role, pruning strategy, earliest queryable height,
+// enabled indexers and synchronization status of the node backing the RPC
+// server.
+func (api *PublicEthermintAPI) NodeInfo() (*NodeInfo, error) {
+	bz, err := api.backend.Query(app.QueryPathInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var appInfo app.AppInfo
+	if err := json.Unmarshal(bz, &appInfo); err != nil {
+		return nil, err
+	}
+
+	status, err := api.backend.SyncStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	role := NodeRoleFull
+	if appInfo.Pruning == app.PruningNothing {
+		role = NodeRoleArchive
+	}
+
+	return &NodeInfo{
+		Role:                    role,
+		Pruning:                 appInfo.Pruning,
+		EarliestQueryableHeight: hexutil.Uint64(appInfo.EarliestQueryableHeight),
+		LatestBlockNumber:       hexutil.Uint64(status.LatestBlockHeight),
+		TxIndexEnabled:          status.TxIndexEnabled,
+		StateDiffsEnabled:       appInfo.StateDiffsEnabled,
+		CatchingUp:              status.CatchingUp,
+	}, nil
+}
+
+// Account returns the committed account at the given address, given either as
+// a hex Ethereum address or as a bech32 SDK account address, along with both
+// forms of the address (see app.AccountResponse). The account is returned as
+// encoded by the application, as its type depends on the modules in use.
+func (api *PublicEthermintAPI) Account(address string) (json.RawMessage, error) {
+	if _, err := types.ParseAddress(address); err != nil {
+		return nil, err
+	}
+
+	return api.backend.Query(app.QueryPathAccount, []byte(address))
+}
+
+// DenomMetadata returns the name, symbol and decimals of the asset with the
+// given denom, allowing wallets to display native and bridged assets.
+func (api *PublicEthermintAPI) DenomMetadata(name string) (*denom.Metadata, error) {
+	if name == "" {
+		return nil, fmt.Errorf("empty denom")
+	}
+
+	bz, err := api.backend.Query(app.QueryPathDenomMetadata, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata denom.Metadata
+	if err := json.Unmarshal(bz, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// Denoms returns the metadata of all registered assets ordered by denom.
+func (api *PublicEthermintAPI) Denoms() ([]denom.Metadata, error) {
+	bz, err := api.backend.Query(app.QueryPathDenomMetadata, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []denom.Metadata
+	if err := json.Unmarshal(bz, &all); err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// Config returns the configuration of the chain wallet connectors need to add
+// it to a wallet, e.g. to populate MetaMask's "Add Network" dialog. The native
+// currency is described by the metadata of the native denom.
+func (api *PublicEthermintAPI) Config() (*ChainConfig, error) {
+	native, err := api.DenomMetadata(types.DenomDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcURLs := api.network.RPCURLs
+	if rpcURLs == nil {
+		rpcURLs = []string{}
+	}
+
+	return &ChainConfig{
+		ChainID:   (*hexutil.Big)(new(big.Int).Set(api.chainID)),
+		ChainName: api.network.ChainName,
+		NativeCurrency: NativeCurrency{
+			Name:     native.Name,
+			Symbol:   native.Symbol,
+			Decimals: native.Decimals,
+		},
+		RPCURLs:           rpcURLs,
+		BlockExplorerURLs: api.network.BlockExplorerURLs,
+	}, nil
+}
+
+// GetTxsByAccount returns the given 1-based page, of at most 100
+// transactions, of the committed Ethereum transactions sent by the given
+// account, if the direction is AccountTxsSent, or received by it, if the
+// direction is AccountTxsReceived, ordered by height. It spares explorers
+// from indexing the chain themselves, as Ethereum's JSON-RPC API serves no
+// account history. The node must index the app.TagEthFrom and app.TagEthTo
+// tags. A mixed-case account must carry a valid EIP-55 checksum.
+func (api *PublicEthermintAPI) GetTxsByAccount(
+	account types.HexAddress, direction string, page, perPage hexutil.Uint,
+) (*AccountTxsPage, error) {
+
+	if direction != AccountTxsSent && direction != AccountTxsReceived {
+		return nil, fmt.Errorf("invalid direction %q: expected %q or %q", direction, AccountTxsSent, AccountTxsReceived)
+	}
+
+	if page < 1 {
+		return nil, fmt.Errorf("invalid page %d: pages start at 1", page)
+	}
+
+	if perPage < 1 || perPage > maxAccountTxsPerPage {
+		return nil, fmt.Errorf("invalid page size %d: expected 1 to %d", perPage, maxAccountTxsPerPage)
+	}
+
+	hashes, total, err := api.backend.AccountTransactions(
+		account.Address(), direction == AccountTxsReceived, int(page), int(perPage),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := api.committedTransactions(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountTxsPage{Transactions: txs, Page: page, PerPage: perPage, Total: hexutil.Uint(total)}, nil
+}
+
+// committedTransactions returns the committed Ethereum transactions with the
+// given hashes, querying every block containing any of them once.
+func (api *PublicEthermintAPI) committedTransactions(hashes []ethcmn.Hash) ([]*RPCTransaction, error) {
+	rpcTxs := make([]*RPCTransaction, 0, len(hashes))
+	blocks := make(map[int64]map[ethcmn.Hash]*RPCTransaction)
+
+	for _, hash := range hashes {
+		res, err := api.backend.TxResult(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if res == nil {
+			return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+		}
+
+		if _, ok := blocks[res.Height]; !ok {
+			blockHash, txs, err := api.backend.BlockTransactions(res.Height)
+			if err != nil {
+				return nil, err
+			}
+
+			blocks[res.Height] = make(map[ethcmn.Hash]*RPCTransaction, len(txs))
+			for _, tx := range txs {
+				blocks[res.Height][tx.Hash()] = NewRPCTransaction(tx.Transaction, blockHash, uint64(res.Height), tx.Index, api.chainID)
+			}
+		}
+
+		rpcTx, ok := blocks[res.Height][hash]
+		if !ok {
+			return nil, fmt.Errorf("transaction %s not found in block %d", hash.Hex(), res.Height)
+		}
+
+		rpcTxs = append(rpcTxs, rpcTx)
+	}
+
+	return rpcTxs, nil
+}
@@ -0,0 +1,541 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/denom"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestPendingTransactions(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	other := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(uint64(i), ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		tx.Sign(testChainID, priv)
+
+		backend.pendingTxs = append(backend.pendingTxs, tx)
+	}
+
+	testCases := []struct {
+		sender      *types.HexAddress
+		expectedLen int
+	}{
+		{nil, 3},
+		{(*types.HexAddress)(&other), 3},
+		{(*types.HexAddress)(&from), 0},
+	}
+
+	for i, tc := range testCases {
+		rpcTxs, err := api.PendingTransactions(tc.sender)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Len(t, rpcTxs, tc.expectedLen, fmt.Sprintf("unexpected result: test case #%d", i))
+
+		for _, rpcTx := range rpcTxs {
+			require.Equal(t, other, rpcTx.From, fmt.Sprintf("unexpected sender: test case #%d", i))
+			require.Nil(t, rpcTx.BlockNumber, fmt.Sprintf("unexpected block number: test case #%d", i))
+		}
+	}
+}
+
+func TestGetTxsByAccount(t *testing.T) {
+	backend, from := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	backend.results = make(map[ethcmn.Hash]*BroadcastResult)
+	for height, txs := range backend.blocks {
+		for _, tx := range txs {
+			backend.results[tx.Hash()] = &BroadcastResult{Height: height}
+		}
+	}
+
+	account := types.HexAddress(from)
+	none := types.HexAddress(ethcmn.HexToAddress("0x0000000000000000000000000000000000000011"))
+
+	testCases := []struct {
+		account   types.HexAddress
+		direction string
+		page      hexutil.Uint
+		perPage   hexutil.Uint
+		expected  []*types.Transaction
+		total     hexutil.Uint
+		expectErr bool
+	}{
+		{account, AccountTxsSent, 1, 10, append(backend.blocks[1], backend.blocks[2]...), 4, false},
+		{account, AccountTxsSent, 2, 3, backend.blocks[2][1:], 4, false},
+		{account, AccountTxsSent, 3, 3, []*types.Transaction{}, 4, false},
+		{account, AccountTxsReceived, 1, 10, []*types.Transaction{}, 0, false},
+		{types.HexAddress{}, AccountTxsReceived, 1, 1, backend.blocks[1][:1], 4, false},
+		{none, AccountTxsSent, 1, 10, []*types.Transaction{}, 0, false},
+		{account, "both", 1, 10, nil, 0, true},
+		{account, AccountTxsSent, 0, 10, nil, 0, true},
+		{account, AccountTxsSent, 1, 0, nil, 0, true},
+		{account, AccountTxsSent, 1, maxAccountTxsPerPage + 1, nil, 0, true},
+	}
+
+	for i, tc := range testCases {
+		res, err := api.GetTxsByAccount(tc.account, tc.direction, tc.page, tc.perPage)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("unexpected result: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.page, res.Page, fmt.Sprintf("unexpected page: test case #%d", i))
+		require.Equal(t, tc.perPage, res.PerPage, fmt.Sprintf("unexpected page size: test case #%d", i))
+		require.Equal(t, tc.total, res.Total, fmt.Sprintf("unexpected total: test case #%d", i))
+		require.Len(t, res.Transactions, len(tc.expected), fmt.Sprintf("unexpected result: test case #%d", i))
+
+		for j, tx := range tc.expected {
+			rpcTx := res.Transactions[j]
+
+			require.Equal(t, tx.Hash(), rpcTx.Hash, fmt.Sprintf("unexpected transaction: test case #%d", i))
+			require.Equal(t, from, rpcTx.From, fmt.Sprintf("unexpected sender: test case #%d", i))
+			require.Equal(
+				t, blockHash(rpcTx.BlockNumber.ToInt().Int64()), rpcTx.BlockHash,
+				fmt.Sprintf("unexpected block hash: test case #%d", i),
+			)
+		}
+	}
+
+	// the history of a node whose index lacks a transaction cannot be served
+	delete(backend.results, backend.blocks[1][0].Hash())
+
+	_, err := api.GetTxsByAccount(account, AccountTxsSent, 1, 10)
+	require.NotNil(t, err)
+}
+
+func TestNewNonceQueue(t *testing.T) {
+	u := func(nonces ...uint64) []hexutil.Uint64 {
+		res := []hexutil.Uint64{}
+		for _, n := range nonces {
+			res = append(res, hexutil.Uint64(n))
+		}
+
+		return res
+	}
+
+	testCases := []struct {
+		nonce           uint64
+		nonces          []uint64
+		expectedPending []hexutil.Uint64
+		expectedQueued  []hexutil.Uint64
+		expectedGaps    []NonceRange
+		expectedStale   []hexutil.Uint64
+	}{
+		{5, nil, u(), u(), []NonceRange{}, u()},
+		{5, []uint64{6, 5, 7}, u(5, 6, 7), u(), []NonceRange{}, u()},
+		// a replacement transaction shares its nonce
+		{5, []uint64{5, 5, 6}, u(5, 6), u(), []NonceRange{}, u()},
+		{5, []uint64{8, 9}, u(), u(8, 9), []NonceRange{{5, 7}}, u()},
+		{5, []uint64{5, 7, 10, 11}, u(5), u(7, 10, 11), []NonceRange{{6, 6}, {8, 9}}, u()},
+		{5, []uint64{3, 4, 5}, u(5), u(), []NonceRange{}, u(3, 4)},
+	}
+
+	for i, tc := range testCases {
+		queue := newNonceQueue(testValidatorAddr, tc.nonce, tc.nonces)
+
+		require.Equal(t, testValidatorAddr, queue.Sender)
+		require.Equal(t, hexutil.Uint64(tc.nonce), queue.Nonce)
+		require.Equal(t, tc.expectedPending, queue.Pending, fmt.Sprintf("unexpected pending: test case #%d", i))
+		require.Equal(t, tc.expectedQueued, queue.Queued, fmt.Sprintf("unexpected queued: test case #%d", i))
+		require.Equal(t, tc.expectedGaps, queue.Gaps, fmt.Sprintf("unexpected gaps: test case #%d", i))
+		require.Equal(t, tc.expectedStale, queue.Stale, fmt.Sprintf("unexpected stale: test case #%d", i))
+	}
+}
+
+func TestNonceQueues(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	var senders []ethcmn.Address
+
+	for _, nonces := range [][]uint64{{2, 4}, {0}} {
+		priv, err := ethcrypto.GenerateKey()
+		require.Nil(t, err)
+
+		for _, nonce := range nonces {
+			tx := types.NewTransaction(nonce, ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+			tx.Sign(testChainID, priv)
+
+			backend.pendingTxs = append(backend.pendingTxs, tx)
+		}
+
+		senders = append(senders, ethcrypto.PubkeyToAddress(priv.PublicKey))
+	}
+
+	marshal := func(v interface{}) string {
+		bz, err := json.Marshal(v)
+		require.Nil(t, err)
+		return string(bz)
+	}
+
+	// the nonces of all senders are queried at once, in sender order
+	sorted, nonces := []ethcmn.Address{senders[0], senders[1]}, []uint64{2, 0}
+	if bytes.Compare(senders[0][:], senders[1][:]) > 0 {
+		sorted[0], sorted[1] = sorted[1], sorted[0]
+		nonces[0], nonces[1] = nonces[1], nonces[0]
+	}
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathNonces: {
+			marshal(sorted):                       []byte(marshal(nonces)),
+			marshal([]ethcmn.Address{senders[1]}): []byte("[0]"),
+		},
+	}
+
+	queues, err := api.NonceQueues(nil)
+	require.Nil(t, err)
+	require.Len(t, queues, 2)
+
+	// queues are ordered by sender
+	if bytes.Compare(senders[0][:], senders[1][:]) > 0 {
+		queues[0], queues[1] = queues[1], queues[0]
+	}
+
+	require.Equal(t, newNonceQueue(senders[0], 2, []uint64{2, 4}), queues[0])
+	require.Equal(t, newNonceQueue(senders[1], 0, []uint64{0}), queues[1])
+
+	queues, err = api.NonceQueues((*types.HexAddress)(&senders[1]))
+	require.Nil(t, err)
+	require.Len(t, queues, 1)
+	require.Equal(t, senders[1], queues[0].Sender)
+
+	// the nonce of a sender cannot be queried
+	backend.queries = nil
+
+	_, err = api.NonceQueues(nil)
+	require.NotNil(t, err)
+}
+
+func TestSendAndWait(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	from := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	tx := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), []byte("code"))
+	tx.Sign(testChainID, priv)
+
+	rawTx, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	receipt, err := api.SendAndWait(rawTx)
+	require.Nil(t, err)
+	require.Equal(t, tx.Hash(), receipt.TransactionHash)
+	require.Equal(t, blockHash(backend.latest), receipt.BlockHash)
+	require.Equal(t, uint64(backend.latest), uint64(receipt.BlockNumber))
+	require.Equal(t, from, receipt.From)
+	require.Equal(t, ethcrypto.CreateAddress(from, 0), *receipt.ContractAddress)
+	require.Equal(t, uint(1), uint(receipt.Status))
+	require.Equal(t, uint64(21000), uint64(receipt.GasUsed))
+
+	_, err = api.SendAndWait([]byte("invalid"))
+	require.NotNil(t, err)
+}
+
+func TestBroadcastRawTransaction(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	rawTx, err := rlp.EncodeToBytes(backend.blocks[1][0])
+	require.Nil(t, err)
+
+	txHash, err := api.BroadcastRawTransaction(rawTx, BroadcastAsync)
+	require.Nil(t, err)
+	require.Equal(t, backend.blocks[1][0].Hash(), txHash)
+	require.Len(t, backend.broadcastTxs, 1)
+
+	_, err = api.BroadcastRawTransaction([]byte("invalid"), BroadcastAsync)
+	require.NotNil(t, err)
+}
+
+func TestNewRPCReceiptRevertReason(t *testing.T) {
+	tx := types.NewTransaction(0, ethcmn.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil)
+
+	// Error("not owner")
+	revertData := hexutil.MustDecode(
+		"0x08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000009" +
+			"6e6f74206f776e65720000000000000000000000000000000000000000000000",
+	)
+
+	testCases := []struct {
+		data           types.ResultData
+		expectedStatus uint
+		expectedReason string
+	}{
+		{types.ResultData{Ret: revertData}, 1, ""},
+		{types.ResultData{Ret: revertData, Failed: true}, 0, "not owner"},
+		{types.ResultData{Ret: []byte("no reason"), Failed: true}, 0, ""},
+		{types.ResultData{Failed: true}, 0, ""},
+	}
+
+	for i, tc := range testCases {
+		bz, err := types.EncodeResultData(resultCdc, tc.data)
+		require.Nil(t, err)
+
+		receipt, err := NewRPCReceipt(tx, ethcmn.Hash{}, 1, 0, 0, &BroadcastResult{Data: bz}, testChainID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		require.Equal(t, tc.expectedStatus, uint(receipt.Status), fmt.Sprintf("unexpected status: test case #%d", i))
+		require.Equal(t, tc.expectedReason, receipt.RevertReason, fmt.Sprintf("unexpected reason: test case #%d", i))
+	}
+}
+
+// deliverBlock executes and commits a block of the given application
+// containing the given transactions, returning their results as broadcast in
+// BroadcastCommit mode.
+func deliverBlock(
+	t *testing.T, ethApp *app.EthermintApp, header *abci.Header, txs ...*types.Transaction,
+) []*BroadcastResult {
+
+	header.Height++
+	ethApp.BeginBlock(abci.RequestBeginBlock{Header: *header})
+
+	results := make([]*BroadcastResult, len(txs))
+	for i, tx := range txs {
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err)
+
+		res := ethApp.DeliverTx(bz)
+		results[i] = &BroadcastResult{Height: header.Height, Code: res.Code, Log: res.Log, Data: res.Data, GasUsed: res.GasUsed}
+	}
+
+	ethApp.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	ethApp.Commit()
+
+	return results
+}
+
+func TestNewRPCReceiptExecution(t *testing.T) {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	from := ethcrypto.PubkeyToAddress(priv.PublicKey)
+
+	ethApp := app.NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), app.SetEthChainID(testChainID))
+
+	genesis := app.DefaultGenesisState(testChainID)
+	genesis.Alloc = append(genesis.Alloc, app.GenesisAccount{
+		Address: from,
+		Balance: (*ethmath.HexOrDecimal256)(big.NewInt(1000000000)),
+	})
+
+	appState, err := json.Marshal(genesis)
+	require.Nil(t, err)
+
+	ethApp.InitChain(abci.RequestInitChain{ChainId: "ethermint", AppStateBytes: appState})
+	header := abci.Header{ChainID: "ethermint"}
+
+	// the contract reverts every call with Error("not owner")
+	runtime := "6064600c60003960646000fd" +
+		"08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"6e6f74206f776e65720000000000000000000000000000000000000000000000"
+
+	initCode := hexutil.MustDecode("0x6070" + "80600b6000396000f3" + runtime)
+
+	create := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), initCode)
+	create.Sign(testChainID, priv)
+
+	contract := ethcrypto.CreateAddress(from, 0)
+
+	call := types.NewTransaction(1, contract, big.NewInt(0), 100000, big.NewInt(1), nil)
+	call.Sign(testChainID, priv)
+
+	testCases := []struct {
+		tx               *types.Transaction
+		expectedStatus   uint
+		expectedContract *ethcmn.Address
+		expectedReason   string
+	}{
+		{create, 1, &contract, ""},
+		// a reverted call is executed and included, but fails
+		{call, 0, nil, "not owner"},
+	}
+
+	for i, tc := range testCases {
+		res := deliverBlock(t, ethApp, &header, tc.tx)[0]
+		require.Equal(t, uint32(0), res.Code, fmt.Sprintf("unexpected code: test case #%d", i))
+
+		receipt, err := NewRPCReceipt(tc.tx, ethcmn.Hash{}, uint64(res.Height), 0, 0, res, testChainID)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expectedStatus, uint(receipt.Status), fmt.Sprintf("unexpected status: test case #%d", i))
+		require.Equal(t, uint64(res.GasUsed), uint64(receipt.GasUsed), fmt.Sprintf("unexpected gas used: test case #%d", i))
+		require.NotZero(t, uint64(receipt.GasUsed), fmt.Sprintf("unexpected gas used: test case #%d", i))
+		require.Equal(t, tc.expectedContract, receipt.ContractAddress, fmt.Sprintf("unexpected contract: test case #%d", i))
+		require.Equal(t, tc.expectedReason, receipt.RevertReason, fmt.Sprintf("unexpected reason: test case #%d", i))
+	}
+}
+
+func TestNodeInfo(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	backend.catchingUp = true
+
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	_, err := api.NodeInfo()
+	require.NotNil(t, err)
+
+	testCases := []struct {
+		appInfo      app.AppInfo
+		expectedRole string
+	}{
+		{app.AppInfo{Pruning: app.PruningNothing, EarliestQueryableHeight: 1}, NodeRoleArchive},
+		{app.AppInfo{Pruning: app.PruningSyncable, EarliestQueryableHeight: 900, StateDiffsEnabled: true}, NodeRoleFull},
+	}
+
+	for i, tc := range testCases {
+		bz, err := json.Marshal(tc.appInfo)
+		require.Nil(t, err)
+
+		backend.queries = map[string]map[string][]byte{app.QueryPathInfo: {"": bz}}
+
+		info, err := api.NodeInfo()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, &NodeInfo{
+			Role:                    tc.expectedRole,
+			Pruning:                 tc.appInfo.Pruning,
+			EarliestQueryableHeight: hexutil.Uint64(tc.appInfo.EarliestQueryableHeight),
+			LatestBlockNumber:       hexutil.Uint64(backend.latest),
+			TxIndexEnabled:          true,
+			StateDiffsEnabled:       tc.appInfo.StateDiffsEnabled,
+			CatchingUp:              true,
+		}, info, fmt.Sprintf("unexpected node info: test case #%d", i))
+	}
+}
+
+func TestAccount(t *testing.T) {
+	backend, addr := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	bz := []byte(`{"address":"` + addr.Hex() + `"}`)
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathAccount: {addr.Hex(): bz, types.Bech32Address(addr): bz},
+	}
+
+	testCases := []struct {
+		address   string
+		expectErr bool
+	}{
+		{addr.Hex(), false},
+		{types.Bech32Address(addr), false},
+		{"0x01", true},
+		{"", true},
+	}
+
+	for i, tc := range testCases {
+		res, err := api.Account(tc.address)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, json.RawMessage(bz), res, fmt.Sprintf("unexpected account: test case #%d", i))
+	}
+}
+
+func TestDenomMetadata(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	api := NewPublicEthermintAPI(backend, testChainID, NetworkConfig{})
+
+	bridged := denom.Metadata{Denom: "uatom", Name: "Cosmos Hub Atom", Symbol: "ATOM", Decimals: 6}
+
+	bzBridged, err := json.Marshal(bridged)
+	require.Nil(t, err)
+
+	bzAll, err := json.Marshal([]denom.Metadata{denom.NativeMetadata(), bridged})
+	require.Nil(t, err)
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathDenomMetadata: {"": bzAll, "uatom": bzBridged},
+	}
+
+	metadata, err := api.DenomMetadata("uatom")
+	require.Nil(t, err)
+	require.Equal(t, &bridged, metadata)
+
+	_, err = api.DenomMetadata("unknown")
+	require.NotNil(t, err)
+
+	_, err = api.DenomMetadata("")
+	require.NotNil(t, err)
+
+	all, err := api.Denoms()
+	require.Nil(t, err)
+	require.Equal(t, []denom.Metadata{denom.NativeMetadata(), bridged}, all)
+}
+
+func TestConfig(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	network := NetworkConfig{
+		ChainName:         "Ethermint Testnet",
+		RPCURLs:           []string{"https://rpc.example.com"},
+		BlockExplorerURLs: []string{"https://explorer.example.com"},
+	}
+
+	api := NewPublicEthermintAPI(backend, testChainID, network)
+
+	// the native denom's metadata is required
+	_, err := api.Config()
+	require.NotNil(t, err)
+
+	bz, err := json.Marshal(denom.NativeMetadata())
+	require.Nil(t, err)
+
+	backend.queries = map[string]map[string][]byte{
+		app.QueryPathDenomMetadata: {types.DenomDefault: bz},
+	}
+
+	config, err := api.Config()
+	require.Nil(t, err)
+
+	bz, err = json.Marshal(config)
+	require.Nil(t, err)
+
+	native := denom.NativeMetadata()
+	require.JSONEq(t, fmt.Sprintf(`{
+		"chainId": "%s",
+		"chainName": "Ethermint Testnet",
+		"nativeCurrency": {"name": "%s", "symbol": "%s", "decimals": %d},
+		"rpcUrls": ["https://rpc.example.com"],
+		"blockExplorerUrls": ["https://explorer.example.com"]
+	}`, hexutil.EncodeBig(testChainID), native.Name, native.Symbol, native.Decimals), string(bz))
+
+	// the RPC URLs are always encoded as a list
+	config, err = NewPublicEthermintAPI(backend, testChainID, NetworkConfig{}).Config()
+	require.Nil(t, err)
+	require.Equal(t, []string{}, config.RPCURLs)
+	require.Nil(t, config.BlockExplorerURLs)
+}
@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/faucet"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PublicFaucetAPI offers the testnet faucet JSON-RPC methods served under the
+// "faucet" namespace. Requests are only processed by chains that have the
+// faucet module enabled.
+type PublicFaucetAPI struct {
+	cdc     *wire.Codec
+	backend Backend
+}
+
+// NewPublicFaucetAPI returns a reference to a new PublicFaucetAPI using the
+// given codec to encode faucet transactions and Backend to broadcast them.
+func NewPublicFaucetAPI(cdc *wire.Codec, backend Backend) *PublicFaucetAPI {
+	return &PublicFaucetAPI{
+		cdc:     cdc,
+		backend: backend,
+	}
+}
+
+// RequestFunds broadcasts a request for faucet funds to be dripped to a given
+// address and returns the hash of the resulting transaction. Whether funds
+// are actually dripped is subject to the faucet's per period limits. A
+// mixed-case address must carry a valid EIP-55 checksum.
+func (api *PublicFaucetAPI) RequestFunds(addr types.HexAddress) (hexutil.Bytes, error) {
+	msg := faucet.NewMsgRequestFunds(sdk.AccAddress(addr.Address().Bytes()))
+	tx := auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(0), nil, "")
+
+	txBytes, err := api.cdc.MarshalBinary(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := api.backend.BroadcastTx(txBytes, BroadcastSync)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Hash, nil
+}
+
+// GetClaim returns the record of faucet funds claimed by a given address. A
+// nil claim is returned if the address never claimed funds.
+func (api *PublicFaucetAPI) GetClaim(addr types.HexAddress) (*faucet.Claim, error) {
+	bz, err := api.backend.QueryStore(faucet.StoreName, faucet.ClaimKey(sdk.AccAddress(addr.Address().Bytes())))
+	if err != nil || bz == nil {
+		return nil, err
+	}
+
+	claim := new(faucet.Claim)
+	if err := api.cdc.UnmarshalBinary(bz, claim); err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
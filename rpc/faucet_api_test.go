@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/faucet"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+func TestRequestFunds(t *testing.T) {
+	cdc := app.MakeCodec()
+	backend, _ := newTestBackend(t)
+	api := NewPublicFaucetAPI(cdc, backend)
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+
+	txHash, err := api.RequestFunds(types.HexAddress(addr))
+	require.Nil(t, err)
+	require.Len(t, backend.broadcastTxs, 1)
+	require.Equal(t, tmhash.Sum(backend.broadcastTxs[0]), []byte(txHash))
+
+	var tx auth.StdTx
+	require.Nil(t, cdc.UnmarshalBinary(backend.broadcastTxs[0], &tx))
+	require.Equal(t, []sdk.Msg{faucet.NewMsgRequestFunds(sdk.AccAddress(addr.Bytes()))}, tx.GetMsgs())
+}
+
+func TestGetClaim(t *testing.T) {
+	cdc := app.MakeCodec()
+	backend, _ := newTestBackend(t)
+	api := NewPublicFaucetAPI(cdc, backend)
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+
+	claim, err := api.GetClaim(types.HexAddress(addr))
+	require.Nil(t, err)
+	require.Nil(t, claim)
+
+	expected := faucet.Claim{LastClaimTime: 10, Total: sdk.Coins{sdk.NewCoin("photon", 100)}}
+	key := faucet.ClaimKey(sdk.AccAddress(addr.Bytes()))
+
+	backend.stores = map[string]map[string][]byte{
+		faucet.StoreName: {string(key): cdc.MustMarshalBinary(expected)},
+	}
+
+	claim, err = api.GetClaim(types.HexAddress(addr))
+	require.Nil(t, err)
+	require.Equal(t, expected, *claim)
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// GasPriceConfig defines the configuration of a GasPriceOracle.
+type GasPriceConfig struct {
+	// Blocks is the number of most recent blocks to sample gas prices from
+	Blocks int
+
+	// Percentile is the percentile of the sampled gas prices to suggest
+	Percentile int
+
+	// Default is the gas price suggested when no gas prices can be sampled
+	Default *big.Int
+}
+
+// DefaultGasPriceConfig returns the default GasPriceOracle configuration.
+func DefaultGasPriceConfig() GasPriceConfig {
+	return GasPriceConfig{
+		Blocks:     20,
+		Percentile: 60,
+		Default:    big.NewInt(1),
+	}
+}
+
+// GasPriceOracle suggests gas prices by sampling the gas prices paid by the
+// transactions included in the most recent blocks.
+type GasPriceOracle struct {
+	backend Backend
+	config  GasPriceConfig
+
+	mtx        sync.Mutex
+	lastHeight int64
+	lastPrice  *big.Int
+}
+
+// NewGasPriceOracle returns a reference to a new GasPriceOracle using the given
+// Backend for chain queries. Invalid configuration values are replaced with
+// their defaults.
+func NewGasPriceOracle(backend Backend, config GasPriceConfig) *GasPriceOracle {
+	defaultConfig := DefaultGasPriceConfig()
+
+	if config.Blocks < 1 {
+		config.Blocks = defaultConfig.Blocks
+	}
+
+	if config.Percentile < 0 || config.Percentile > 100 {
+		config.Percentile = defaultConfig.Percentile
+	}
+
+	if config.Default == nil {
+		config.Default = defaultConfig.Default
+	}
+
+	return &GasPriceOracle{
+		backend:   backend,
+		config:    config,
+		lastPrice: config.Default,
+	}
+}
+
+// SuggestPrice returns the configured percentile of the gas prices paid in
+// the most recent blocks. The suggestion is only recomputed once a new block
+// is committed. If no transactions were included in the sampled blocks, the
+// previous suggestion is returned.
+func (gpo *GasPriceOracle) SuggestPrice() (*big.Int, error) {
+	gpo.mtx.Lock()
+	defer gpo.mtx.Unlock()
+
+	latest, err := gpo.backend.LatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	if latest == gpo.lastHeight {
+		return new(big.Int).Set(gpo.lastPrice), nil
+	}
+
+	var prices []*big.Int
+	for height := latest; height > 0 && height > latest-int64(gpo.config.Blocks); height-- {
+		_, txs, err := gpo.backend.BlockTransactions(height)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range txs {
+			prices = append(prices, tx.GasPrice())
+		}
+	}
+
+	if len(prices) > 0 {
+		sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+		gpo.lastPrice = prices[(len(prices)-1)*gpo.config.Percentile/100]
+	}
+
+	gpo.lastHeight = latest
+	return new(big.Int).Set(gpo.lastPrice), nil
+}
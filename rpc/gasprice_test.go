@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newGasPriceTestBackend(pricesPerBlock ...[]int64) *mockBackend {
+	backend := &mockBackend{blocks: make(map[int64][]*types.Transaction)}
+
+	for _, prices := range pricesPerBlock {
+		backend.latest++
+
+		for i, price := range prices {
+			tx := types.NewTransaction(uint64(i), ethcmn.Address{}, big.NewInt(0), 21000, big.NewInt(price), nil)
+			backend.blocks[backend.latest] = append(backend.blocks[backend.latest], tx)
+		}
+	}
+
+	return backend
+}
+
+func TestGasPriceOracleSuggestPrice(t *testing.T) {
+	testCases := []struct {
+		prices        [][]int64
+		config        GasPriceConfig
+		expectedPrice int64
+	}{
+		{nil, DefaultGasPriceConfig(), 1},
+		{[][]int64{{}, {}}, GasPriceConfig{Default: big.NewInt(7)}, 7},
+		{[][]int64{{5, 1, 3}, {4, 2}}, GasPriceConfig{Blocks: 2, Percentile: 50}, 3},
+		{[][]int64{{5, 1, 3}, {4, 2}}, GasPriceConfig{Blocks: 2, Percentile: 100}, 5},
+		{[][]int64{{5, 1, 3}, {4, 2}}, GasPriceConfig{Blocks: 2, Percentile: 0}, 1},
+		// only the most recent blocks are sampled
+		{[][]int64{{100, 100}, {4, 2}}, GasPriceConfig{Blocks: 1, Percentile: 100}, 4},
+	}
+
+	for i, tc := range testCases {
+		gpo := NewGasPriceOracle(newGasPriceTestBackend(tc.prices...), tc.config)
+
+		price, err := gpo.SuggestPrice()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, big.NewInt(tc.expectedPrice), price, fmt.Sprintf("unexpected price: test case #%d", i))
+	}
+}
+
+func TestGasPriceOracleCache(t *testing.T) {
+	backend := newGasPriceTestBackend([]int64{10})
+	gpo := NewGasPriceOracle(backend, GasPriceConfig{Blocks: 1, Percentile: 50})
+
+	price, err := gpo.SuggestPrice()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(10), price)
+
+	// the suggestion is only recomputed once a new block is committed
+	backend.blocks[1][0] = types.NewTransaction(0, ethcmn.Address{}, big.NewInt(0), 21000, big.NewInt(20), nil)
+
+	price, err = gpo.SuggestPrice()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(10), price)
+
+	// an empty block retains the previous suggestion
+	backend.latest++
+
+	price, err = gpo.SuggestPrice()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(10), price)
+
+	backend.latest++
+	backend.blocks[backend.latest] = backend.blocks[1]
+
+	price, err = gpo.SuggestPrice()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(20), price)
+
+	api := NewPublicEthAPI(backend, testChainID, gpo, NewEtherbase(ethcmn.Address{}))
+
+	gasPrice, err := api.GasPrice()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(20), gasPrice.ToInt())
+
+	priorityFee, err := api.MaxPriorityFeePerGas()
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(20), priorityFee.ToInt())
+}
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCHeader defines the Ethereum header synthesized for a committed block.
+//
+// Every root is derived from data every node agrees on, so that any two nodes
+// serve the same header for the same block:
+//
+//   - The state root is the application hash of the block's Tendermint header,
+//     left-padded to 32 bytes. As Tendermint includes the application hash
+//     committing to the state as of a height in the header of the following
+//     block, it commits to the state prior to the block's transactions, unlike
+//     an Ethereum state root, but is verifiable against the header (see the
+//     client/proofs package).
+//
+//   - The transactions root is the root of the Ethereum trie of the block's
+//     RLP encoded Ethereum transactions keyed by index, as in Ethereum.
+//
+//   - The receipts root is the root of the Ethereum trie of the receipts of the
+//     block's Ethereum transactions keyed by index, as in Ethereum. A receipt is
+//     derived solely from the code and data of the transaction's result, which
+//     the next block's Tendermint header commits to, and never from the log or
+//     gas used reported by the node, which may differ across nodes: its status
+//     is successful if the code is zero and the data's types.ResultData does not
+//     reflect a failure, its gas used is that of the ResultData, or zero if the
+//     transaction was not executed, and its logs those of the ResultData.
+//
+// The logs bloom and gas used are accumulated over the receipts.
+type RPCHeader struct {
+	Number           hexutil.Uint64 `json:"number"`
+	Hash             ethcmn.Hash    `json:"hash"`
+	ParentHash       ethcmn.Hash    `json:"parentHash"`
+	Timestamp        hexutil.Uint64 `json:"timestamp"`
+	StateRoot        ethcmn.Hash    `json:"stateRoot"`
+	TransactionsRoot ethcmn.Hash    `json:"transactionsRoot"`
+	ReceiptsRoot     ethcmn.Hash    `json:"receiptsRoot"`
+	LogsBloom        ethtypes.Bloom `json:"logsBloom"`
+	GasUsed          hexutil.Uint64 `json:"gasUsed"`
+}
+
+// transactionList implements go-ethereum's DerivableList interface, deriving
+// the transactions root of a block.
+type transactionList []*types.Transaction
+
+func (l transactionList) Len() int {
+	return len(l)
+}
+
+func (l transactionList) GetRlp(i int) []byte {
+	bz, err := rlp.EncodeToBytes(l[i])
+	if err != nil {
+		panic(err)
+	}
+
+	return bz
+}
+
+// NewRPCHeader returns the Ethereum header synthesized for the block of the
+// given header containing the given Ethereum transactions, in order, with the
+// given results.
+func NewRPCHeader(header *BlockHeader, txs []*types.Transaction, results []*BroadcastResult) (*RPCHeader, error) {
+	if len(txs) != len(results) {
+		return nil, fmt.Errorf("got %d results for %d transactions", len(results), len(txs))
+	}
+
+	receipts := make(ethtypes.Receipts, len(txs))
+	cumulativeGasUsed := uint64(0)
+
+	for i, res := range results {
+		data, err := decodeTxResult(res)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result of transaction %s: %v", txs[i].Hash().Hex(), err)
+		}
+
+		cumulativeGasUsed += data.GasUsed
+
+		receipt := ethtypes.NewReceipt(nil, res.Code != 0 || data.Failed, cumulativeGasUsed)
+		receipt.Logs = make([]*ethtypes.Log, len(data.Logs))
+
+		for j, log := range data.Logs {
+			receipt.Logs[j] = &ethtypes.Log{Address: log.Address, Topics: log.Topics, Data: log.Data}
+		}
+
+		receipt.Bloom = ethtypes.CreateBloom(ethtypes.Receipts{receipt})
+		receipts[i] = receipt
+	}
+
+	return &RPCHeader{
+		Number:           hexutil.Uint64(header.Height),
+		Hash:             header.Hash,
+		ParentHash:       ethcmn.BytesToHash(header.LastBlockHash),
+		Timestamp:        hexutil.Uint64(header.Time),
+		StateRoot:        ethcmn.BytesToHash(header.AppHash),
+		TransactionsRoot: ethtypes.DeriveSha(transactionList(txs)),
+		ReceiptsRoot:     ethtypes.DeriveSha(receipts),
+		LogsBloom:        ethtypes.CreateBloom(receipts),
+		GasUsed:          hexutil.Uint64(cumulativeGasUsed),
+	}, nil
+}
+
+// decodeTxResult returns the types.ResultData of the given transaction result,
+// which is empty if the transaction was not executed.
+func decodeTxResult(res *BroadcastResult) (types.ResultData, error) {
+	if res.Code != 0 || len(res.Data) == 0 {
+		return types.ResultData{}, nil
+	}
+
+	return types.DecodeResultData(resultCdc, res.Data)
+}
+
+// GetHeaderByNumber returns the Ethereum header synthesized for the committed
+// block at the given number (see RPCHeader).
+func (api *PublicEthAPI) GetHeaderByNumber(blockNum ethrpc.BlockNumber) (*RPCHeader, error) {
+	height, err := api.resolveBlockNumber(blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := api.backend.BlockHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	_, txs, err := api.backend.BlockTransactions(height)
+	if err != nil {
+		return nil, err
+	}
+
+	ethTxs := make([]*types.Transaction, len(txs))
+	results := make([]*BroadcastResult, len(txs))
+
+	for i, tx := range txs {
+		ethTxs[i] = tx.Transaction
+
+		if results[i], err = api.backend.TxResult(tx.Hash()); err != nil {
+			return nil, err
+		}
+
+		if results[i] == nil {
+			return nil, fmt.Errorf("no result of transaction %s", tx.Hash().Hex())
+		}
+	}
+
+	return NewRPCHeader(header, ethTxs, results)
+}
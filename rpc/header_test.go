@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHeaderByNumber(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	backend.latest = 3
+	backend.blocks[3] = nil
+
+	logAddr := ethcmn.BytesToAddress([]byte("emitter"))
+
+	encode := func(data types.ResultData) []byte {
+		bz, err := types.EncodeResultData(resultCdc, data)
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	// the first block holds a successful transaction emitting a log and a
+	// failed one, the second one that was not executed and a successful one
+	backend.results = map[ethcmn.Hash]*BroadcastResult{
+		backend.blocks[1][0].Hash(): {Data: encode(types.ResultData{
+			GasUsed: 21000, Logs: []types.Log{{Address: logAddr, Topics: []ethcmn.Hash{{0x01}}}},
+		})},
+		backend.blocks[1][1].Hash(): {Data: encode(types.ResultData{GasUsed: 30000, Failed: true}), Log: "reverted"},
+		backend.blocks[2][0].Hash(): {Code: 13, Log: "insufficient funds"},
+		backend.blocks[2][1].Hash(): {Data: encode(types.ResultData{GasUsed: 21000})},
+	}
+
+	// another node serving the same blocks reports its own logs and gas used,
+	// which are not committed to by any header
+	other := *backend
+	other.results = make(map[ethcmn.Hash]*BroadcastResult)
+
+	for hash, res := range backend.results {
+		otherRes := *res
+		otherRes.Log = "other node"
+		otherRes.GasUsed = 1
+
+		other.results[hash] = &otherRes
+	}
+
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+	otherAPI := NewPublicEthAPI(&other, testChainID, NewGasPriceOracle(&other, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	testCases := []struct {
+		blockNum        ethrpc.BlockNumber
+		expectedHeight  int64
+		expectedGasUsed uint64
+	}{
+		{1, 1, 51000},
+		{2, 2, 21000},
+		{ethrpc.LatestBlockNumber, 3, 0},
+	}
+
+	for i, tc := range testCases {
+		header, err := api.GetHeaderByNumber(tc.blockNum)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		otherHeader, err := otherAPI.GetHeaderByNumber(tc.blockNum)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, header, otherHeader, fmt.Sprintf("headers differ across nodes: test case #%d", i))
+
+		require.Equal(t, hexutil.Uint64(tc.expectedHeight), header.Number)
+		require.Equal(t, blockHash(tc.expectedHeight), header.Hash)
+		require.Equal(t, blockHash(tc.expectedHeight-1), header.ParentHash)
+		require.Equal(t, hexutil.Uint64(tc.expectedHeight*10), header.Timestamp)
+		require.Equal(t, ethcmn.BytesToHash(appHash(tc.expectedHeight)), header.StateRoot)
+		require.Equal(t, hexutil.Uint64(tc.expectedGasUsed), header.GasUsed, fmt.Sprintf("unexpected gas used: test case #%d", i))
+
+		// the transactions root is that of the same transactions on Ethereum
+		var ethTxs ethtypes.Transactions
+		for _, tx := range backend.blocks[tc.expectedHeight] {
+			bz, err := rlp.EncodeToBytes(tx)
+			require.Nil(t, err)
+
+			ethTx := new(ethtypes.Transaction)
+			require.Nil(t, rlp.DecodeBytes(bz, ethTx))
+
+			ethTxs = append(ethTxs, ethTx)
+		}
+
+		require.Equal(t, ethtypes.DeriveSha(ethTxs), header.TransactionsRoot)
+	}
+
+	header, err := api.GetHeaderByNumber(1)
+	require.Nil(t, err)
+	require.True(t, ethtypes.BloomLookup(header.LogsBloom, logAddr))
+
+	empty, err := api.GetHeaderByNumber(3)
+	require.Nil(t, err)
+	require.Equal(t, ethtypes.EmptyRootHash, empty.TransactionsRoot)
+	require.Equal(t, ethtypes.EmptyRootHash, empty.ReceiptsRoot)
+	require.Equal(t, ethtypes.Bloom{}, empty.LogsBloom)
+
+	// the receipts root commits to the status of every transaction
+	backend.results[backend.blocks[1][1].Hash()] = &BroadcastResult{Data: encode(types.ResultData{GasUsed: 30000})}
+
+	succeeded, err := api.GetHeaderByNumber(1)
+	require.Nil(t, err)
+	require.NotEqual(t, header.ReceiptsRoot, succeeded.ReceiptsRoot)
+	require.Equal(t, header.TransactionsRoot, succeeded.TransactionsRoot)
+
+	// a header cannot be synthesized without the result of every transaction
+	delete(backend.results, backend.blocks[2][0].Hash())
+
+	_, err = api.GetHeaderByNumber(2)
+	require.NotNil(t, err)
+}
+
+func TestNewRPCHeader(t *testing.T) {
+	header := &BlockHeader{Height: 1}
+	tx := types.NewTransaction(0, ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+
+	_, err := NewRPCHeader(header, []*types.Transaction{tx}, nil)
+	require.NotNil(t, err)
+
+	_, err = NewRPCHeader(header, []*types.Transaction{tx}, []*BroadcastResult{{Data: []byte("invalid")}})
+	require.NotNil(t, err)
+}
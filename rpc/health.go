@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/ethermint/app"
+)
+
+// Paths the health probes are served under by WithHealthProbes.
+const (
+	ReadinessPath = "/readyz"
+	LivenessPath  = "/livez"
+)
+
+// HealthThresholds defines the limits beyond which a health probe reports the
+// node backing the RPC server as unhealthy. A zero limit disables its check.
+type HealthThresholds struct {
+	// MaxBlockLag is the number of blocks the node may lag behind the
+	// reference node by
+	MaxBlockLag int64
+
+	// MaxEventBacklog is the number of committed blocks whose events the
+	// node's event sink may not have indexed yet (see app.SetEventSink)
+	MaxEventBacklog int64
+}
+
+// HealthConfig defines the thresholds of the readiness and liveness probes.
+// The block lag of the node is measured against the latest block of the
+// reference node, e.g. a trusted sentry, and is not checked without one.
+type HealthConfig struct {
+	Readiness HealthThresholds
+	Liveness  HealthThresholds
+	Reference Backend
+}
+
+// HealthReport defines the response of a health probe. The block lag and
+// event backlog are only reported if checked. Warnings report checks that
+// could not be performed without failing the probe, e.g. as the reference
+// node is unavailable.
+type HealthReport struct {
+	Healthy           bool     `json:"healthy"`
+	LatestBlockHeight int64    `json:"latestBlockHeight"`
+	CatchingUp        bool     `json:"catchingUp"`
+	BlockLag          int64    `json:"blockLag"`
+	EventBacklog      int64    `json:"eventBacklog"`
+	Errors            []string `json:"errors"`
+	Warnings          []string `json:"warnings"`
+}
+
+// WithHealthProbes returns an http.Handler serving the readiness and liveness
+// probes of the node backing the given backend, e.g. for Kubernetes, under
+// ReadinessPath and LivenessPath, and any other request with the given
+// handler. A probe responds with a JSON encoded HealthReport, with 200 OK if
+// the node is healthy or 503 Service Unavailable otherwise.
+//
+// Both probes fail if the node is unavailable or exceeds their thresholds.
+// The readiness probe also fails while the node is catching up, whereas a
+// node catching up is live. As the probes serve no chain data, the given
+// handler may require authentication while the probes do not.
+func WithHealthProbes(handler http.Handler, backend Backend, cfg HealthConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ReadinessPath:
+			writeHealthReport(w, checkHealth(backend, cfg.Reference, cfg.Readiness, true))
+
+		case LivenessPath:
+			writeHealthReport(w, checkHealth(backend, cfg.Reference, cfg.Liveness, false))
+
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+// checkHealth returns the health of the node backing the given backend
+// against the given thresholds, failing if the node is catching up if ready
+// is set.
+func checkHealth(backend, reference Backend, thresholds HealthThresholds, ready bool) HealthReport {
+	report := HealthReport{Errors: []string{}, Warnings: []string{}}
+
+	status, err := backend.SyncStatus()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("node unavailable: %v", err))
+		return report
+	}
+
+	report.LatestBlockHeight = status.LatestBlockHeight
+	report.CatchingUp = status.CatchingUp
+
+	if ready && status.CatchingUp {
+		report.Errors = append(report.Errors, "node is catching up")
+	}
+
+	if thresholds.MaxBlockLag > 0 && reference != nil {
+		checkBlockLag(&report, reference, thresholds.MaxBlockLag)
+	}
+
+	if thresholds.MaxEventBacklog > 0 {
+		checkEventBacklog(&report, backend, thresholds.MaxEventBacklog)
+	}
+
+	report.Healthy = len(report.Errors) == 0
+	return report
+}
+
+// checkBlockLag reports the number of blocks the node lags behind the
+// reference node by. An unavailable reference node only warns so that its
+// outage does not fail the probes of every RPC server measured against it.
+func checkBlockLag(report *HealthReport, reference Backend, maxLag int64) {
+	latest, err := reference.LatestBlockNumber()
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("reference node unavailable: %v", err))
+		return
+	}
+
+	if lag := latest - report.LatestBlockHeight; lag > 0 {
+		report.BlockLag = lag
+	}
+
+	if report.BlockLag > maxLag {
+		report.Errors = append(
+			report.Errors, fmt.Sprintf("node lags %d blocks behind, exceeding %d", report.BlockLag, maxLag),
+		)
+	}
+}
+
+// checkEventBacklog reports the number of committed blocks whose events the
+// node's event sink has not indexed yet.
+func checkEventBacklog(report *HealthReport, backend Backend, maxBacklog int64) {
+	bz, err := backend.Query(app.QueryPathInfo, nil)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to query event backlog: %v", err))
+		return
+	}
+
+	var info app.AppInfo
+	if err := json.Unmarshal(bz, &info); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to decode event backlog: %v", err))
+		return
+	}
+
+	report.EventBacklog = info.EventBacklog
+
+	if info.EventBacklog > maxBacklog {
+		report.Errors = append(
+			report.Errors, fmt.Sprintf("%d blocks of events not indexed, exceeding %d", info.EventBacklog, maxBacklog),
+		)
+	}
+}
+
+// writeHealthReport writes the given report with the status of its health.
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// the status is already written so an encoding error cannot be reported
+	_ = json.NewEncoder(w).Encode(report)
+}
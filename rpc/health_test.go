@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableBackend implements a Backend whose node cannot be reached.
+type unavailableBackend struct {
+	*mockBackend
+}
+
+func (ub unavailableBackend) LatestBlockNumber() (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (ub unavailableBackend) SyncStatus() (*SyncStatus, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestWithHealthProbes(t *testing.T) {
+	appInfo := func(backlog int64) map[string]map[string][]byte {
+		bz, err := json.Marshal(app.AppInfo{EventBacklog: backlog})
+		require.Nil(t, err)
+
+		return map[string]map[string][]byte{app.QueryPathInfo: {"": bz}}
+	}
+
+	cfg := HealthConfig{
+		Readiness: HealthThresholds{MaxBlockLag: 5, MaxEventBacklog: 10},
+		Liveness:  HealthThresholds{MaxBlockLag: 100},
+		Reference: &mockBackend{latest: 110},
+	}
+
+	testCases := []struct {
+		backend       Backend
+		cfg           HealthConfig
+		path          string
+		expectedCode  int
+		expectedLag   int64
+		expectedWarns int
+	}{
+		{&mockBackend{latest: 105, queries: appInfo(10)}, cfg, ReadinessPath, http.StatusOK, 5, 0},
+		{&mockBackend{latest: 104, queries: appInfo(0)}, cfg, ReadinessPath, http.StatusServiceUnavailable, 6, 0},
+		{&mockBackend{latest: 104, queries: appInfo(0)}, cfg, LivenessPath, http.StatusOK, 6, 0},
+		{&mockBackend{latest: 9, queries: appInfo(0)}, cfg, LivenessPath, http.StatusServiceUnavailable, 101, 0},
+		{&mockBackend{latest: 120, queries: appInfo(11)}, cfg, ReadinessPath, http.StatusServiceUnavailable, 0, 0},
+		{&mockBackend{latest: 120}, cfg, ReadinessPath, http.StatusServiceUnavailable, 0, 0},
+		{&mockBackend{latest: 120, queries: appInfo(11)}, cfg, LivenessPath, http.StatusOK, 0, 0},
+		{&mockBackend{latest: 110, queries: appInfo(0), catchingUp: true}, cfg, ReadinessPath, http.StatusServiceUnavailable, 0, 0},
+		{&mockBackend{latest: 110, queries: appInfo(0), catchingUp: true}, cfg, LivenessPath, http.StatusOK, 0, 0},
+		{unavailableBackend{&mockBackend{}}, cfg, LivenessPath, http.StatusServiceUnavailable, 0, 0},
+		{&mockBackend{latest: 1, queries: appInfo(0)}, HealthConfig{Readiness: cfg.Readiness}, ReadinessPath, http.StatusOK, 0, 0},
+		{
+			&mockBackend{latest: 1, queries: appInfo(0)},
+			HealthConfig{Readiness: cfg.Readiness, Reference: unavailableBackend{&mockBackend{}}},
+			ReadinessPath, http.StatusOK, 0, 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		rec := httptest.NewRecorder()
+		WithHealthProbes(http.NotFoundHandler(), tc.backend, tc.cfg).ServeHTTP(rec, httptest.NewRequest("GET", tc.path, nil))
+		require.Equal(t, tc.expectedCode, rec.Code, fmt.Sprintf("unexpected status: test case #%d", i))
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var report HealthReport
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		require.Equal(t, tc.expectedCode == http.StatusOK, report.Healthy, fmt.Sprintf("unexpected health: test case #%d", i))
+		require.Equal(t, report.Healthy, len(report.Errors) == 0, fmt.Sprintf("unexpected errors: test case #%d", i))
+		require.Equal(t, tc.expectedLag, report.BlockLag, fmt.Sprintf("unexpected block lag: test case #%d", i))
+		require.Len(t, report.Warnings, tc.expectedWarns, fmt.Sprintf("unexpected warnings: test case #%d", i))
+	}
+
+	// any other request is served by the wrapped handler
+	rec := httptest.NewRecorder()
+	WithHealthProbes(http.NotFoundHandler(), &mockBackend{}, cfg).ServeHTTP(rec, httptest.NewRequest("POST", "/", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
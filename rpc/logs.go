@@ -0,0 +1,273 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// defaultLogPollInterval is the interval at which log subscriptions poll
+	// for newly committed blocks.
+	defaultLogPollInterval = time.Second
+
+	// maxLogReplayBlocks is the number of past blocks a log subscription may
+	// replay. As logs are not indexed, every block replayed is queried.
+	maxLogReplayBlocks = 10000
+)
+
+// resultCdc decodes the types.ResultData of transaction results, which holds
+// no interfaces and therefore requires no registered types.
+var resultCdc = wire.NewCodec()
+
+// LogFilter defines the criteria of a log subscription, in the same JSON form
+// as go-ethereum's filter criteria. A log matches if it is emitted by any of
+// the addresses, if any is given, and if each of its topics is any of the
+// topics given at the same position. An empty position matches any topic.
+type LogFilter struct {
+	FromBlock *ethrpc.BlockNumber
+	Addresses []ethcmn.Address
+	Topics    [][]ethcmn.Hash
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. As in go-ethereum,
+// the address may be given as a single address or a list of addresses and
+// every topic position as null, a single topic or a list of topics.
+func (f *LogFilter) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FromBlock *ethrpc.BlockNumber `json:"fromBlock"`
+		Address   json.RawMessage     `json:"address"`
+		Topics    []json.RawMessage   `json:"topics"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	f.FromBlock = raw.FromBlock
+
+	if len(raw.Address) > 0 && string(raw.Address) != "null" {
+		var addr ethcmn.Address
+		if err := json.Unmarshal(raw.Address, &f.Addresses); err != nil {
+			if err := json.Unmarshal(raw.Address, &addr); err != nil {
+				return fmt.Errorf("invalid address filter: %v", err)
+			}
+
+			f.Addresses = []ethcmn.Address{addr}
+		}
+	}
+
+	f.Topics = make([][]ethcmn.Hash, len(raw.Topics))
+
+	for i, rawTopic := range raw.Topics {
+		if string(rawTopic) == "null" {
+			continue
+		}
+
+		var topic ethcmn.Hash
+		if err := json.Unmarshal(rawTopic, &f.Topics[i]); err != nil {
+			if err := json.Unmarshal(rawTopic, &topic); err != nil {
+				return fmt.Errorf("invalid topic filter at position %d: %v", i, err)
+			}
+
+			f.Topics[i] = []ethcmn.Hash{topic}
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the given log matches the filter.
+func (f LogFilter) Matches(log *ethtypes.Log) bool {
+	if len(f.Addresses) > 0 && !containsAddress(f.Addresses, log.Address) {
+		return false
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, topics := range f.Topics {
+		if len(topics) > 0 && !containsHash(topics, log.Topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsAddress(addrs []ethcmn.Address, addr ethcmn.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsHash(hashes []ethcmn.Hash, hash ethcmn.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Logs creates a subscription, served as eth_subscribe("logs"), notifying the
+// logs matching the given filter of every block committed from then on. If
+// the filter's fromBlock is a past block, the matching logs of every block
+// since are notified first, in order, up to maxLogReplayBlocks blocks back,
+// after which the subscription continues with newly committed blocks. The
+// filter's toBlock is ignored, as by go-ethereum.
+func (api *PublicEthAPI) Logs(ctx context.Context, filter LogFilter) (*ethrpc.Subscription, error) {
+	notifier, supported := ethrpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, ethrpc.ErrNotificationsUnsupported
+	}
+
+	latest, err := api.backend.LatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	next := latest + 1
+	if filter.FromBlock != nil && *filter.FromBlock >= 0 && int64(*filter.FromBlock) <= latest {
+		if next = int64(*filter.FromBlock); next < 1 {
+			next = 1
+		}
+
+		if latest-next >= maxLogReplayBlocks {
+			return nil, fmt.Errorf("cannot replay logs of more than %d blocks", maxLogReplayBlocks)
+		}
+	}
+
+	sub := notifier.CreateSubscription()
+	go api.notifyLogs(notifier, sub, filter, next)
+
+	return sub, nil
+}
+
+// notifyLogs notifies the logs matching the given filter of every committed
+// block from the given height on until the subscription ends. A block whose
+// logs cannot be queried is retried at the next poll. As notifications are
+// dropped until the server activates the subscription, once its ID is sent to
+// the client, nothing is notified before the first poll.
+func (api *PublicEthAPI) notifyLogs(notifier *ethrpc.Notifier, sub *ethrpc.Subscription, filter LogFilter, next int64) {
+	ticker := time.NewTicker(api.logPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sub.Err():
+			return
+		case <-notifier.Closed():
+			return
+		case <-ticker.C:
+		}
+
+		latest, err := api.backend.LatestBlockNumber()
+
+		for err == nil && next <= latest {
+			if err = api.notifyBlockLogs(notifier, sub, filter, next); err == nil {
+				next++
+			}
+		}
+	}
+}
+
+// notifyBlockLogs notifies the logs matching the given filter of the block at
+// the given height.
+func (api *PublicEthAPI) notifyBlockLogs(
+	notifier *ethrpc.Notifier, sub *ethrpc.Subscription, filter LogFilter, height int64,
+) error {
+
+	logs, err := api.blockLogs(height)
+	if err != nil {
+		return err
+	}
+
+	for _, log := range logs {
+		if !filter.Matches(log) {
+			continue
+		}
+
+		if err := notifier.Notify(sub.ID, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// blockLogs returns the logs emitted by the successful Ethereum transactions
+// of the block at the given height, in order.
+func (api *PublicEthAPI) blockLogs(height int64) ([]*ethtypes.Log, error) {
+	blockHash, txs, err := api.backend.BlockTransactions(height)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []*ethtypes.Log{}
+
+	for _, tx := range txs {
+		res, err := api.backend.TxResult(tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+
+		if res == nil || res.Code != 0 || len(res.Data) == 0 {
+			continue
+		}
+
+		data, err := types.DecodeResultData(resultCdc, res.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result of transaction %s: %v", tx.Hash().Hex(), err)
+		}
+
+		logs = append(logs, newRPCLogs(data.Logs, uint64(height), blockHash, tx.Hash(), tx.Index, uint(len(logs)))...)
+	}
+
+	return logs, nil
+}
+
+// newRPCLogs returns the Ethereum logs of the given logs emitted by the
+// transaction of the given hash at the given location, the first log being at
+// the given index within the block.
+func newRPCLogs(
+	logs []types.Log, blockNumber uint64, blockHash, txHash ethcmn.Hash, txIndex uint64, logIndex uint,
+) []*ethtypes.Log {
+
+	ethLogs := make([]*ethtypes.Log, len(logs))
+
+	for i, log := range logs {
+		// amino decodes no topics as nil, which clients reject as null
+		topics := log.Topics
+		if topics == nil {
+			topics = []ethcmn.Hash{}
+		}
+
+		ethLogs[i] = &ethtypes.Log{
+			Address:     log.Address,
+			Topics:      topics,
+			Data:        log.Data,
+			BlockNumber: blockNumber,
+			TxHash:      txHash,
+			TxIndex:     uint(txIndex),
+			BlockHash:   blockHash,
+			Index:       logIndex + uint(i),
+		}
+	}
+
+	return ethLogs
+}
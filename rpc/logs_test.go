@@ -0,0 +1,213 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testLogAddr1 = ethcmn.HexToAddress("0x0000000000000000000000000000000000000011")
+	testLogAddr2 = ethcmn.HexToAddress("0x0000000000000000000000000000000000000022")
+	testTopic1   = ethcmn.HexToHash("0x01")
+	testTopic2   = ethcmn.HexToHash("0x02")
+)
+
+// lockedBackend guards a mockBackend queried by a log subscription while the
+// test commits new blocks.
+type lockedBackend struct {
+	*mockBackend
+
+	mtx sync.Mutex
+}
+
+func (lb *lockedBackend) LatestBlockNumber() (int64, error) {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+
+	return lb.mockBackend.LatestBlockNumber()
+}
+
+func (lb *lockedBackend) BlockTransactions(height int64) (ethcmn.Hash, []BlockTx, error) {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+
+	return lb.mockBackend.BlockTransactions(height)
+}
+
+func (lb *lockedBackend) TxResult(hash ethcmn.Hash) (*BroadcastResult, error) {
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+
+	return lb.mockBackend.TxResult(hash)
+}
+
+// setLogs sets the successful result of the given transaction emitting the
+// given logs.
+func (lb *lockedBackend) setLogs(t *testing.T, tx *types.Transaction, logs ...types.Log) {
+	bz, err := types.EncodeResultData(resultCdc, types.ResultData{Logs: logs})
+	require.Nil(t, err)
+
+	lb.mtx.Lock()
+	defer lb.mtx.Unlock()
+
+	if lb.results == nil {
+		lb.results = make(map[ethcmn.Hash]*BroadcastResult)
+	}
+
+	lb.results[tx.Hash()] = &BroadcastResult{Hash: tx.Hash().Bytes(), Data: bz}
+}
+
+func TestLogFilterUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		filter    string
+		expected  LogFilter
+		expectErr bool
+	}{
+		{`{}`, LogFilter{Topics: [][]ethcmn.Hash{}}, false},
+		{
+			fmt.Sprintf(`{"fromBlock":"0x2","address":"%s"}`, testLogAddr1.Hex()),
+			LogFilter{
+				FromBlock: func() *ethrpc.BlockNumber { n := ethrpc.BlockNumber(2); return &n }(),
+				Addresses: []ethcmn.Address{testLogAddr1},
+				Topics:    [][]ethcmn.Hash{},
+			},
+			false,
+		},
+		{
+			fmt.Sprintf(`{"address":["%s","%s"]}`, testLogAddr1.Hex(), testLogAddr2.Hex()),
+			LogFilter{Addresses: []ethcmn.Address{testLogAddr1, testLogAddr2}, Topics: [][]ethcmn.Hash{}},
+			false,
+		},
+		{
+			fmt.Sprintf(`{"topics":[null,"%s",["%s","%s"]]}`, testTopic1.Hex(), testTopic1.Hex(), testTopic2.Hex()),
+			LogFilter{Topics: [][]ethcmn.Hash{nil, {testTopic1}, {testTopic1, testTopic2}}},
+			false,
+		},
+		{`{"address":"0x01"}`, LogFilter{}, true},
+		{`{"topics":[1]}`, LogFilter{}, true},
+	}
+
+	for i, tc := range testCases {
+		var filter LogFilter
+		err := json.Unmarshal([]byte(tc.filter), &filter)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("unexpected result: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			require.Equal(t, tc.expected, filter, fmt.Sprintf("unexpected filter: test case #%d", i))
+		}
+	}
+}
+
+func TestLogFilterMatches(t *testing.T) {
+	log := &ethtypes.Log{Address: testLogAddr1, Topics: []ethcmn.Hash{testTopic1, testTopic2}}
+
+	testCases := []struct {
+		filter   LogFilter
+		expected bool
+	}{
+		{LogFilter{}, true},
+		{LogFilter{Addresses: []ethcmn.Address{testLogAddr2, testLogAddr1}}, true},
+		{LogFilter{Addresses: []ethcmn.Address{testLogAddr2}}, false},
+		{LogFilter{Topics: [][]ethcmn.Hash{nil, {testTopic2}}}, true},
+		{LogFilter{Topics: [][]ethcmn.Hash{{testTopic2}}}, false},
+		{LogFilter{Topics: [][]ethcmn.Hash{nil, nil, nil}}, false},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, tc.filter.Matches(log), fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestLogsSubscription(t *testing.T) {
+	mock, _ := newTestBackend(t)
+	backend := &lockedBackend{mockBackend: mock}
+
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+	api.logPollInterval = 10 * time.Millisecond
+
+	backend.setLogs(t, mock.blocks[1][0], types.Log{Address: testLogAddr1, Topics: []ethcmn.Hash{testTopic1}})
+	backend.setLogs(
+		t, mock.blocks[2][1],
+		types.Log{Address: testLogAddr2, Topics: []ethcmn.Hash{testTopic1}},
+		types.Log{Address: testLogAddr1, Topics: []ethcmn.Hash{testTopic2}},
+	)
+
+	srv := ethrpc.NewServer()
+	require.Nil(t, srv.RegisterName("eth", api))
+	defer srv.Stop()
+
+	client := ethrpc.DialInProc(srv)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// replaying more blocks than allowed fails
+	backend.mtx.Lock()
+	mock.latest += maxLogReplayBlocks
+	backend.mtx.Unlock()
+
+	_, err := client.EthSubscribe(ctx, make(chan ethtypes.Log), "logs", map[string]interface{}{"fromBlock": "0x1"})
+	require.NotNil(t, err)
+
+	backend.mtx.Lock()
+	mock.latest -= maxLogReplayBlocks
+	backend.mtx.Unlock()
+
+	// the logs of past blocks are replayed before those of new blocks
+	logs := make(chan ethtypes.Log)
+	sub, err := client.EthSubscribe(ctx, logs, "logs", map[string]interface{}{
+		"fromBlock": "0x1",
+		"address":   testLogAddr1,
+	})
+	require.Nil(t, err)
+	defer sub.Unsubscribe()
+
+	tx := types.NewTransaction(0, ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	backend.setLogs(t, tx, types.Log{Address: testLogAddr1, Data: []byte("live")})
+
+	backend.mtx.Lock()
+	mock.blocks[3] = []*types.Transaction{tx}
+	mock.latest = 3
+	backend.mtx.Unlock()
+
+	expected := []ethtypes.Log{
+		{
+			Address: testLogAddr1, Topics: []ethcmn.Hash{testTopic1}, Data: []byte{},
+			BlockNumber: 1, TxHash: mock.blocks[1][0].Hash(), TxIndex: 0, BlockHash: blockHash(1), Index: 0,
+		},
+		{
+			Address: testLogAddr1, Topics: []ethcmn.Hash{testTopic2}, Data: []byte{},
+			BlockNumber: 2, TxHash: mock.blocks[2][1].Hash(), TxIndex: 1, BlockHash: blockHash(2), Index: 1,
+		},
+		{
+			Address: testLogAddr1, Topics: []ethcmn.Hash{}, Data: []byte("live"),
+			BlockNumber: 3, TxHash: tx.Hash(), TxIndex: 0, BlockHash: blockHash(3), Index: 0,
+		},
+	}
+
+	for i, log := range expected {
+		select {
+		case received := <-logs:
+			require.Equal(t, log, received, fmt.Sprintf("unexpected log: log #%d", i))
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-ctx.Done():
+			t.Fatalf("log #%d not received", i)
+		}
+	}
+}
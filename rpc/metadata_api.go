@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// ContractMetadata defines the metadata of a verified contract, allowing
+// explorers to decode calls to the contract without external services. The
+// contract was verified by the node operator, e.g. by recompiling its source,
+// against the deployed code of the given hash as of the given height.
+type ContractMetadata struct {
+	Address    ethcmn.Address  `json:"address"`
+	Name       string          `json:"name"`
+	ABI        json.RawMessage `json:"abi"`
+	SourceHash ethcmn.Hash     `json:"sourceHash"`
+	CodeHash   ethcmn.Hash     `json:"codeHash"`
+	VerifiedAt hexutil.Uint64  `json:"verifiedAt"`
+}
+
+// MetadataStore persists the metadata of verified contracts, keyed by
+// contract address, in a database local to the RPC server.
+type MetadataStore struct {
+	db dbm.DB
+}
+
+// NewMetadataStore returns a reference to a new MetadataStore persisting the
+// metadata of verified contracts in the given database.
+func NewMetadataStore(db dbm.DB) *MetadataStore {
+	return &MetadataStore{db: db}
+}
+
+// Get returns the metadata of the contract at the given address or nil if the
+// contract is not verified.
+func (ms *MetadataStore) Get(addr ethcmn.Address) (*ContractMetadata, error) {
+	bz := ms.db.Get(addr.Bytes())
+	if bz == nil {
+		return nil, nil
+	}
+
+	metadata := new(ContractMetadata)
+	if err := json.Unmarshal(bz, metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// Set persists the given contract metadata, replacing any metadata of the
+// same contract.
+func (ms *MetadataStore) Set(metadata ContractMetadata) error {
+	bz, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	ms.db.SetSync(metadata.Address.Bytes(), bz)
+	return nil
+}
+
+// Delete removes the metadata of the contract at the given address.
+func (ms *MetadataStore) Delete(addr ethcmn.Address) {
+	ms.db.DeleteSync(addr.Bytes())
+}
+
+// PublicMetadataAPI offers the JSON-RPC methods serving the metadata of
+// verified contracts under the "ethermint" namespace.
+type PublicMetadataAPI struct {
+	store *MetadataStore
+}
+
+// NewPublicMetadataAPI returns a reference to a new PublicMetadataAPI serving
+// the contract metadata held by the given MetadataStore.
+func NewPublicMetadataAPI(store *MetadataStore) *PublicMetadataAPI {
+	return &PublicMetadataAPI{store: store}
+}
+
+// ContractMetadata returns the metadata of the verified contract at the given
+// address or nil if the contract is not verified.
+func (api *PublicMetadataAPI) ContractMetadata(addr types.HexAddress) (*ContractMetadata, error) {
+	return api.store.Get(addr.Address())
+}
+
+// PrivateMetadataAPI offers the node operator JSON-RPC methods served under
+// the "admin" namespace, managing the metadata of verified contracts.
+type PrivateMetadataAPI struct {
+	cdc     *wire.Codec
+	backend Backend
+	store   *MetadataStore
+}
+
+// NewPrivateMetadataAPI returns a reference to a new PrivateMetadataAPI
+// checking submitted contract metadata against the contracts deployed on the
+// Backend's node, whose accounts are decoded using the given codec, and
+// persisting it in the given MetadataStore.
+func NewPrivateMetadataAPI(cdc *wire.Codec, backend Backend, store *MetadataStore) *PrivateMetadataAPI {
+	return &PrivateMetadataAPI{
+		cdc:     cdc,
+		backend: backend,
+		store:   store,
+	}
+}
+
+// AddContractMetadata persists the given metadata of a verified contract,
+// replacing any metadata of the same contract. An error is returned if the
+// ABI is invalid or if the given code hash is not that of the code currently
+// deployed at the contract's address, so that metadata cannot be attached to
+// a contract other than the one verified. The height the metadata is checked
+// at is recorded, overriding the given one.
+func (api *PrivateMetadataAPI) AddContractMetadata(metadata ContractMetadata) (bool, error) {
+	if _, err := ethabi.JSON(bytes.NewReader(metadata.ABI)); err != nil {
+		return false, fmt.Errorf("invalid ABI: %v", err)
+	}
+
+	height, err := api.backend.LatestBlockNumber()
+	if err != nil {
+		return false, err
+	}
+
+	codeHash, err := api.codeHash(metadata.Address)
+	if err != nil {
+		return false, err
+	}
+
+	if codeHash != metadata.CodeHash {
+		return false, fmt.Errorf(
+			"code hash %s does not match the code deployed at %s: %s",
+			metadata.CodeHash.Hex(), types.ChecksumHex(metadata.Address), codeHash.Hex(),
+		)
+	}
+
+	metadata.VerifiedAt = hexutil.Uint64(height)
+	return true, api.store.Set(metadata)
+}
+
+// RemoveContractMetadata removes the metadata of the contract at the given
+// address, if any.
+func (api *PrivateMetadataAPI) RemoveContractMetadata(addr types.HexAddress) bool {
+	api.store.Delete(addr.Address())
+	return true
+}
+
+// codeHash returns the hash of the code deployed at the given address. An
+// error is returned if there is no contract at the address.
+func (api *PrivateMetadataAPI) codeHash(addr ethcmn.Address) (ethcmn.Hash, error) {
+	bz, err := api.backend.Query(app.QueryPathAccount, addr.Bytes())
+	if err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	var res app.AccountResponse
+	if err := api.cdc.UnmarshalJSON(bz, &res); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	acc, ok := res.Account.(*types.Account)
+	if !ok || len(acc.CodeHash) == 0 {
+		return ethcmn.Hash{}, errors.New("no contract deployed at " + types.ChecksumHex(addr))
+	}
+
+	return ethcmn.BytesToHash(acc.CodeHash), nil
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const testMetadataABI = `[{"type":"function","name":"increment","constant":false,"inputs":[],"outputs":[]}]`
+
+func TestMetadataAPIs(t *testing.T) {
+	cdc := app.MakeCodec()
+
+	contract := ethcmn.BytesToAddress([]byte("contract"))
+	user := ethcmn.BytesToAddress([]byte("user"))
+	codeHash := ethcrypto.Keccak256Hash([]byte("code"))
+
+	accountResponse := func(acc auth.Account) []byte {
+		bz, err := cdc.MarshalJSON(app.AccountResponse{Account: acc})
+		require.Nil(t, err)
+
+		return bz
+	}
+
+	backend := &mockBackend{
+		latest: 42,
+		queries: map[string]map[string][]byte{
+			app.QueryPathAccount: {
+				string(contract.Bytes()): accountResponse(&types.Account{CodeHash: codeHash.Bytes()}),
+				string(user.Bytes()):     accountResponse(&types.Account{}),
+			},
+		},
+	}
+
+	store := NewMetadataStore(dbm.NewMemDB())
+	admin := NewPrivateMetadataAPI(cdc, backend, store)
+	api := NewPublicMetadataAPI(store)
+
+	valid := ContractMetadata{
+		Address:    contract,
+		Name:       "Counter",
+		ABI:        json.RawMessage(testMetadataABI),
+		SourceHash: ethcrypto.Keccak256Hash([]byte("source")),
+		CodeHash:   codeHash,
+	}
+
+	withABI := valid
+	withABI.ABI = json.RawMessage(`{"not":"an abi"}`)
+
+	withCodeHash := valid
+	withCodeHash.CodeHash = ethcrypto.Keccak256Hash([]byte("other code"))
+
+	withUser := valid
+	withUser.Address = user
+
+	withMissing := valid
+	withMissing.Address = ethcmn.BytesToAddress([]byte("missing"))
+
+	testCases := []struct {
+		metadata  ContractMetadata
+		expectErr bool
+	}{
+		{withABI, true},
+		{withCodeHash, true},
+		{withUser, true},
+		{withMissing, true},
+		{valid, false},
+	}
+
+	for i, tc := range testCases {
+		_, err := admin.AddContractMetadata(tc.metadata)
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected error: test case #%d", i))
+	}
+
+	metadata, err := api.ContractMetadata(types.HexAddress(contract))
+	require.Nil(t, err)
+
+	valid.VerifiedAt = hexutil.Uint64(42)
+	require.Equal(t, &valid, metadata)
+
+	metadata, err = api.ContractMetadata(types.HexAddress(user))
+	require.Nil(t, err)
+	require.Nil(t, metadata)
+
+	require.True(t, admin.RemoveContractMetadata(types.HexAddress(contract)))
+
+	metadata, err = api.ContractMetadata(types.HexAddress(contract))
+	require.Nil(t, err)
+	require.Nil(t, metadata)
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"sync"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// Etherbase holds the fee recipient address configured by the node operator,
+// the equivalent of geth's etherbase. It is safe for concurrent use.
+type Etherbase struct {
+	mtx  sync.RWMutex
+	addr ethcmn.Address
+}
+
+// NewEtherbase returns a reference to a new Etherbase set to the given
+// address. An empty address leaves the etherbase unset.
+func NewEtherbase(addr ethcmn.Address) *Etherbase {
+	return &Etherbase{addr: addr}
+}
+
+// Get returns the configured etherbase and true if it is set or false
+// otherwise.
+func (e *Etherbase) Get() (ethcmn.Address, bool) {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+
+	return e.addr, e.addr != (ethcmn.Address{})
+}
+
+// Set sets the etherbase to the given address.
+func (e *Etherbase) Set(addr ethcmn.Address) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.addr = addr
+}
+
+// PrivateMinerAPI offers the node operator JSON-RPC methods served under the
+// "miner" namespace.
+type PrivateMinerAPI struct {
+	etherbase *Etherbase
+}
+
+// NewPrivateMinerAPI returns a reference to a new PrivateMinerAPI managing the
+// given Etherbase.
+func NewPrivateMinerAPI(etherbase *Etherbase) *PrivateMinerAPI {
+	return &PrivateMinerAPI{
+		etherbase: etherbase,
+	}
+}
+
+// SetEtherbase sets the fee recipient address reported as the node's
+// coinbase.
+func (api *PrivateMinerAPI) SetEtherbase(addr types.HexAddress) bool {
+	api.etherbase.Set(addr.Address())
+	return true
+}
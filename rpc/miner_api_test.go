@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinbase(t *testing.T) {
+	backend, _ := newTestBackend(t)
+	etherbase := NewEtherbase(ethcmn.Address{})
+
+	ethAPI := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), etherbase)
+	minerAPI := NewPrivateMinerAPI(etherbase)
+
+	// the validator address is reported unless an etherbase is configured
+	coinbase, err := ethAPI.Coinbase()
+	require.Nil(t, err)
+	require.Equal(t, testValidatorAddr, coinbase)
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	require.True(t, minerAPI.SetEtherbase(types.HexAddress(addr)))
+
+	coinbase, err = ethAPI.Coinbase()
+	require.Nil(t, err)
+	require.Equal(t, addr, coinbase)
+}
+
+func TestEtherbase(t *testing.T) {
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+
+	_, ok := NewEtherbase(ethcmn.Address{}).Get()
+	require.False(t, ok)
+
+	etherbase := NewEtherbase(addr)
+
+	got, ok := etherbase.Get()
+	require.True(t, ok)
+	require.Equal(t, addr, got)
+
+	etherbase.Set(ethcmn.Address{})
+
+	_, ok = etherbase.Get()
+	require.False(t, ok)
+}
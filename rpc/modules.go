@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// AdminNamespace is the namespace of the admin JSON-RPC APIs. It cannot be
+// disabled at runtime so that operators cannot lock themselves out.
+const AdminNamespace = "admin"
+
+// Modules serves a set of JSON-RPC APIs over HTTP, allowing the namespaces
+// served to be toggled at runtime. It is safe for concurrent use.
+//
+// The underlying ethrpc.Server cannot unregister a namespace, so a new server
+// serving only the enabled namespaces is built whenever a namespace is
+// toggled. Requests in flight are completed by the server they started on.
+type Modules struct {
+	mtx      sync.RWMutex
+	apis     []ethrpc.API
+	disabled map[string]bool
+	handler  http.Handler
+
+	// wrap returns the http.Handler serving the given server (e.g. applying
+	// CORS and virtual host checks)
+	wrap func(*ethrpc.Server) http.Handler
+}
+
+var _ http.Handler = (*Modules)(nil)
+
+// NewModules returns a reference to a new Modules serving no APIs. Every
+// server built is served through the handler returned by wrap.
+func NewModules(wrap func(*ethrpc.Server) http.Handler) *Modules {
+	m := &Modules{
+		disabled: make(map[string]bool),
+		wrap:     wrap,
+	}
+
+	m.handler = wrap(ethrpc.NewServer())
+	return m
+}
+
+// Register registers the given APIs, each enabled. An error is returned if an
+// API cannot be served.
+func (m *Modules) Register(apis ...ethrpc.API) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.rebuild(append(m.apis, apis...), m.disabled)
+}
+
+// Enabled returns every registered namespace and whether it is enabled.
+func (m *Modules) Enabled() map[string]bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	enabled := make(map[string]bool)
+	for _, api := range m.apis {
+		enabled[api.Namespace] = !m.disabled[api.Namespace]
+	}
+
+	return enabled
+}
+
+// SetEnabled enables or disables the given namespace. An error is returned if
+// the namespace is not registered or is the admin namespace.
+func (m *Modules) SetEnabled(namespace string, enabled bool) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if namespace == AdminNamespace {
+		return fmt.Errorf("the %s namespace cannot be toggled", AdminNamespace)
+	}
+
+	registered := false
+	for _, api := range m.apis {
+		registered = registered || api.Namespace == namespace
+	}
+
+	if !registered {
+		return fmt.Errorf("unknown namespace %s", namespace)
+	}
+
+	disabled := make(map[string]bool, len(m.disabled)+1)
+	for ns, ok := range m.disabled {
+		disabled[ns] = ok
+	}
+
+	disabled[namespace] = !enabled
+
+	return m.rebuild(m.apis, disabled)
+}
+
+// ServeHTTP implements the http.Handler interface by serving the request with
+// the server of the currently enabled namespaces.
+func (m *Modules) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mtx.RLock()
+	handler := m.handler
+	m.mtx.RUnlock()
+
+	handler.ServeHTTP(w, r)
+}
+
+// rebuild builds a server serving the enabled namespaces of the given APIs
+// and, if successful, replaces the current server with it.
+//
+// CONTRACT: The caller must hold the write lock.
+func (m *Modules) rebuild(apis []ethrpc.API, disabled map[string]bool) error {
+	server := ethrpc.NewServer()
+
+	for _, api := range apis {
+		if disabled[api.Namespace] {
+			continue
+		}
+
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+
+	m.apis = apis
+	m.disabled = disabled
+	m.handler = m.wrap(server)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModules(t *testing.T) *Modules {
+	modules := NewModules(func(srv *ethrpc.Server) http.Handler { return srv })
+
+	err := modules.Register(
+		ethrpc.API{Namespace: "miner", Service: NewPrivateMinerAPI(NewEtherbase(ethcmn.Address{}))},
+		ethrpc.API{Namespace: AdminNamespace, Service: NewPrivateAdminAPI(&mockBackend{}, modules, nil, "", nil)},
+	)
+	require.Nil(t, err)
+
+	return modules
+}
+
+// callModules performs a JSON-RPC call of the given method against a Modules
+// and returns the JSON-RPC error, if any.
+func callModules(t *testing.T, modules *Modules, method string, params string) *struct{ Message string } {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	modules.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res struct {
+		Error *struct{ Message string }
+	}
+
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res.Error
+}
+
+func TestModules(t *testing.T) {
+	modules := newTestModules(t)
+	setEtherbase := `["0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0"]`
+
+	require.Equal(t, map[string]bool{"miner": true, AdminNamespace: true}, modules.Enabled())
+	require.Nil(t, callModules(t, modules, "miner_setEtherbase", setEtherbase))
+
+	// methods of a disabled namespace cannot be called
+	require.Nil(t, callModules(t, modules, "admin_disableModule", `["miner"]`))
+	require.Equal(t, map[string]bool{"miner": false, AdminNamespace: true}, modules.Enabled())
+	require.NotNil(t, callModules(t, modules, "miner_setEtherbase", setEtherbase))
+
+	require.Nil(t, callModules(t, modules, "admin_enableModule", `["miner"]`))
+	require.Nil(t, callModules(t, modules, "miner_setEtherbase", setEtherbase))
+
+	// the admin namespace and unknown namespaces cannot be toggled
+	require.NotNil(t, modules.SetEnabled(AdminNamespace, false))
+	require.NotNil(t, modules.SetEnabled("unknown", false))
+	require.Equal(t, map[string]bool{"miner": true, AdminNamespace: true}, modules.Enabled())
+}
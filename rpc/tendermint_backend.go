@@ -0,0 +1,389 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpclib "github.com/tendermint/tendermint/rpc/lib/client"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// maxPendingTxs is the maximum number of pending transactions fetched from the
+// Tendermint mempool.
+const maxPendingTxs = 1000
+
+// TendermintClient defines the set of Tendermint RPC calls required by a
+// TendermintBackend.
+type TendermintClient interface {
+	Status() (*ctypes.ResultStatus, error)
+	Block(height *int64) (*ctypes.ResultBlock, error)
+	UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error)
+	BroadcastTxAsync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error)
+	BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error)
+	BroadcastTxCommit(tx tmtypes.Tx) (*ctypes.ResultBroadcastTxCommit, error)
+	TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error)
+	ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error)
+	NetInfo() (*ctypes.ResultNetInfo, error)
+	DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error)
+}
+
+// httpClient implements a TendermintClient over HTTP against a remote
+// Tendermint node.
+type httpClient struct {
+	*rpcclient.HTTP
+
+	rpc *rpclib.JSONRPCClient
+}
+
+// NewHTTPClient returns a TendermintClient for a remote Tendermint node
+// listening on the given address in the form tcp://<host>:<port>.
+func NewHTTPClient(remote string) TendermintClient {
+	rpc := rpclib.NewJSONRPCClient(remote)
+
+	cdc := rpc.Codec()
+	ctypes.RegisterAmino(cdc)
+	rpc.SetCodec(cdc)
+
+	return &httpClient{
+		HTTP: rpcclient.NewHTTP(remote, "/websocket"),
+		rpc:  rpc,
+	}
+}
+
+// UnconfirmedTxs returns up to limit transactions pending in the remote node's
+// mempool.
+func (c *httpClient) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	result := new(ctypes.ResultUnconfirmedTxs)
+
+	if _, err := c.rpc.Call("unconfirmed_txs", map[string]interface{}{"limit": limit}, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DialPeers dials the given peers from the remote node.
+//
+// NOTE: The remote node must expose its unsafe RPC routes (rpc.unsafe).
+func (c *httpClient) DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
+	result := new(ctypes.ResultDialPeers)
+
+	params := map[string]interface{}{"peers": peers, "persistent": persistent}
+	if _, err := c.rpc.Call("dial_peers", params, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TendermintBackend implements the Backend interface by querying a Tendermint
+// node over its RPC and ABCI query endpoints. As it requires no local state,
+// it allows RPC servers to be run separately from, and scaled independently
+// of, full nodes.
+type TendermintBackend struct {
+	client TendermintClient
+}
+
+var _ Backend = (*TendermintBackend)(nil)
+
+// NewTendermintBackend returns a reference to a new TendermintBackend using
+// the given client.
+func NewTendermintBackend(client TendermintClient) *TendermintBackend {
+	return &TendermintBackend{client: client}
+}
+
+// LatestBlockNumber implements the Backend interface.
+func (b *TendermintBackend) LatestBlockNumber() (int64, error) {
+	status, err := b.client.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// ValidatorAddress implements the Backend interface. The Tendermint validator
+// address is interpreted as an Ethereum address.
+func (b *TendermintBackend) ValidatorAddress() (ethcmn.Address, error) {
+	status, err := b.client.Status()
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+
+	return ethcmn.BytesToAddress(status.ValidatorInfo.Address), nil
+}
+
+// SyncStatus implements the Backend interface.
+func (b *TendermintBackend) SyncStatus() (*SyncStatus, error) {
+	status, err := b.client.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncStatus{
+		LatestBlockHeight: status.SyncInfo.LatestBlockHeight,
+		CatchingUp:        status.SyncInfo.CatchingUp,
+		TxIndexEnabled:    status.TxIndexEnabled(),
+	}, nil
+}
+
+// Peers implements the Backend interface.
+func (b *TendermintBackend) Peers() ([]PeerInfo, error) {
+	netInfo, err := b.client.NetInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make([]PeerInfo, len(netInfo.Peers))
+	for i, peer := range netInfo.Peers {
+		peers[i] = PeerInfo{
+			ID:         string(peer.NodeInfo.ID),
+			Moniker:    peer.NodeInfo.Moniker,
+			ListenAddr: peer.NodeInfo.ListenAddr,
+			Network:    peer.NodeInfo.Network,
+			Version:    peer.NodeInfo.Version,
+			Outbound:   peer.IsOutbound,
+		}
+	}
+
+	return peers, nil
+}
+
+// DialPeers implements the Backend interface.
+func (b *TendermintBackend) DialPeers(peers []string, persistent bool) error {
+	_, err := b.client.DialPeers(peers, persistent)
+	return err
+}
+
+// BlockTransactions implements the Backend interface. Any transaction that is
+// not an Ethereum transaction is omitted.
+func (b *TendermintBackend) BlockTransactions(height int64) (ethcmn.Hash, []BlockTx, error) {
+	res, err := b.client.Block(&height)
+	if err != nil {
+		return ethcmn.Hash{}, nil, err
+	}
+
+	blockHash := ethcmn.BytesToHash(res.BlockMeta.BlockID.Hash)
+	return blockHash, decodeTransactions(res.Block.Txs), nil
+}
+
+// BlockHeader implements the Backend interface.
+func (b *TendermintBackend) BlockHeader(height int64) (*BlockHeader, error) {
+	res, err := b.client.Block(&height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockHeader{
+		Hash:          ethcmn.BytesToHash(res.BlockMeta.BlockID.Hash),
+		Height:        res.Block.Height,
+		Time:          res.Block.Time.Unix(),
+		LastBlockHash: res.Block.LastBlockID.Hash,
+		AppHash:       res.Block.AppHash,
+	}, nil
+}
+
+// PendingTransactions implements the Backend interface. Any transaction that
+// is not an Ethereum transaction is omitted.
+func (b *TendermintBackend) PendingTransactions() ([]*types.Transaction, error) {
+	res, err := b.client.UnconfirmedTxs(maxPendingTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	blockTxs := decodeTransactions(res.Txs)
+
+	txs := make([]*types.Transaction, len(blockTxs))
+	for i, tx := range blockTxs {
+		txs[i] = tx.Transaction
+	}
+
+	return txs, nil
+}
+
+// BroadcastTx implements the Backend interface. A transaction that is
+// committed in a block but fails execution does not result in an error, its
+// result is instead reflected by the returned code. A transaction aborted by
+// the ante handler results in the error go-ethereum returns for its cause.
+func (b *TendermintBackend) BroadcastTx(txBytes []byte, mode BroadcastMode) (*BroadcastResult, error) {
+	switch mode {
+	case BroadcastAsync, BroadcastSync:
+		broadcast := b.client.BroadcastTxSync
+		if mode == BroadcastAsync {
+			broadcast = b.client.BroadcastTxAsync
+		}
+
+		res, err := broadcast(txBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Code != 0 {
+			return nil, b.broadcastError(res.Code, res.Log, res.Data)
+		}
+
+		return &BroadcastResult{Hash: res.Hash, Code: res.Code, Log: res.Log, Data: res.Data}, nil
+
+	case BroadcastCommit:
+		res, err := b.client.BroadcastTxCommit(txBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.CheckTx.Code != 0 {
+			return nil, b.broadcastError(res.CheckTx.Code, res.CheckTx.Log, res.CheckTx.Data)
+		}
+
+		return &BroadcastResult{
+			Hash:    res.Hash,
+			Height:  res.Height,
+			Code:    res.DeliverTx.Code,
+			Log:     res.DeliverTx.Log,
+			Data:    res.DeliverTx.Data,
+			GasUsed: res.DeliverTx.GasUsed,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported broadcast mode: %s", mode)
+	}
+}
+
+// broadcastError returns the error of a transaction failing CheckTx with the
+// given code, log and data. The error of a transaction aborted by the ante
+// handler is its types.AnteAbort reason, while that of a transaction failing
+// for a cause go-ethereum rejects transactions for is go-ethereum's error.
+func (b *TendermintBackend) broadcastError(code uint32, log string, data []byte) error {
+	if abort, ok := types.DecodeAnteAbort(data); ok {
+		return errors.New(abort.Reason)
+	}
+
+	if msg, ok := gethErrorMessage(b.codespace(), code); ok {
+		return errors.New(msg)
+	}
+
+	return fmt.Errorf("transaction failed to broadcast with code %d: %s", code, log)
+}
+
+// codespace returns the codespace the node's application raises Ethermint's
+// errors in, or sdk.CodespaceUndefined if it cannot be queried.
+func (b *TendermintBackend) codespace() sdk.CodespaceType {
+	bz, err := b.Query(app.QueryPathInfo, nil)
+	if err != nil {
+		return sdk.CodespaceUndefined
+	}
+
+	var info app.AppInfo
+	if err := json.Unmarshal(bz, &info); err != nil {
+		return sdk.CodespaceUndefined
+	}
+
+	return info.Codespace
+}
+
+// TxResult implements the Backend interface. Transactions are searched for by
+// the app.TagEthTxHash tag, which the node must index, e.g. by listing it in
+// Tendermint's index_tags.
+func (b *TendermintBackend) TxResult(hash ethcmn.Hash) (*BroadcastResult, error) {
+	res, err := b.client.TxSearch(fmt.Sprintf("%s='%s'", app.TagEthTxHash, hash.Hex()), false, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Txs) == 0 {
+		return nil, nil
+	}
+
+	tx := res.Txs[0]
+
+	return &BroadcastResult{
+		Hash:    tx.Hash,
+		Height:  tx.Height,
+		Code:    tx.TxResult.Code,
+		Log:     tx.TxResult.Log,
+		Data:    tx.TxResult.Data,
+		GasUsed: tx.TxResult.GasUsed,
+	}, nil
+}
+
+// AccountTransactions implements the Backend interface. Transactions are
+// searched for by the app.TagEthFrom or app.TagEthTo tag, which the node must
+// index, e.g. by listing it in Tendermint's index_tags.
+func (b *TendermintBackend) AccountTransactions(
+	account ethcmn.Address, recipient bool, page, perPage int,
+) ([]ethcmn.Hash, int, error) {
+
+	tag := app.TagEthFrom
+	if recipient {
+		tag = app.TagEthTo
+	}
+
+	res, err := b.client.TxSearch(fmt.Sprintf("%s='%s'", tag, account.Hex()), false, page, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hashes := []ethcmn.Hash{}
+
+	// Tendermint serves the last page in place of any page past it
+	if (page-1)*perPage >= res.TotalCount {
+		return hashes, res.TotalCount, nil
+	}
+
+	for _, resTx := range res.Txs {
+		tx, err := decodeRawTransaction(resTx.Tx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid transaction %X: %v", resTx.Hash, err)
+		}
+
+		hashes = append(hashes, tx.Hash())
+	}
+
+	return hashes, res.TotalCount, nil
+}
+
+// QueryStore implements the Backend interface.
+func (b *TendermintBackend) QueryStore(storeName string, key []byte) ([]byte, error) {
+	return b.Query(fmt.Sprintf("/store/%s/key", storeName), key)
+}
+
+// Query implements the Backend interface.
+func (b *TendermintBackend) Query(path string, data []byte) ([]byte, error) {
+	res, err := b.client.ABCIQuery(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Response.Code != 0 {
+		return nil, fmt.Errorf("query failed with code %d: %s", res.Response.Code, res.Response.Log)
+	}
+
+	return res.Response.Value, nil
+}
+
+// decodeTransactions decodes all the Ethereum transactions out of a list of
+// Tendermint transactions, preserving their order and index in the list. The
+// SDK transactions are omitted: an amino encoded auth.StdTx, which is not RLP
+// encoded, and an EmbeddedTx, wrapped in an Ethereum transaction.
+func decodeTransactions(txs tmtypes.Txs) []BlockTx {
+	ethTxs := make([]BlockTx, 0, len(txs))
+
+	for i, txBytes := range txs {
+		tx, err := decodeRawTransaction(txBytes)
+		if err != nil || tx.IsEmbeddedTx() {
+			continue
+		}
+
+		ethTxs = append(ethTxs, BlockTx{Transaction: tx, Index: uint64(i)})
+	}
+
+	return ethTxs
+}
@@ -0,0 +1,496 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// mockTendermintClient implements the TendermintClient interface using
+// in-memory blocks, mempool and store.
+type mockTendermintClient struct {
+	blocks    map[int64]tmtypes.Txs
+	mempool   tmtypes.Txs
+	store     map[string][]byte
+	code      uint32
+	data      []byte
+	peers     []ctypes.Peer
+	dialed    []string
+	txResults []*ctypes.ResultTx
+	codespace sdk.CodespaceType
+}
+
+func (mc *mockTendermintClient) Status() (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{
+		NodeInfo:      p2p.NodeInfo{Other: []string{"tx_index=on"}},
+		SyncInfo:      ctypes.SyncInfo{LatestBlockHeight: int64(len(mc.blocks)), CatchingUp: true},
+		ValidatorInfo: ctypes.ValidatorInfo{Address: testValidatorAddr.Bytes()},
+	}, nil
+}
+
+func (mc *mockTendermintClient) Block(height *int64) (*ctypes.ResultBlock, error) {
+	txs, ok := mc.blocks[*height]
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", *height)
+	}
+
+	return &ctypes.ResultBlock{
+		BlockMeta: &tmtypes.BlockMeta{BlockID: tmtypes.BlockID{Hash: blockHash(*height).Bytes()}},
+		Block: &tmtypes.Block{
+			Header: &tmtypes.Header{
+				Height:      *height,
+				Time:        time.Unix(*height*10, 0),
+				LastBlockID: tmtypes.BlockID{Hash: blockHash(*height - 1).Bytes()},
+				AppHash:     appHash(*height),
+			},
+			Data: &tmtypes.Data{Txs: txs},
+		},
+	}, nil
+}
+
+func (mc *mockTendermintClient) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	return &ctypes.ResultUnconfirmedTxs{N: len(mc.mempool), Txs: mc.mempool}, nil
+}
+
+func (mc *mockTendermintClient) BroadcastTxAsync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	mc.mempool = append(mc.mempool, tx)
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func (mc *mockTendermintClient) BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if mc.code != 0 {
+		return &ctypes.ResultBroadcastTx{Code: mc.code, Log: "failed", Data: mc.data}, nil
+	}
+
+	return mc.BroadcastTxAsync(tx)
+}
+
+func (mc *mockTendermintClient) BroadcastTxCommit(tx tmtypes.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	res := &ctypes.ResultBroadcastTxCommit{Hash: tx.Hash()}
+	if mc.code != 0 {
+		res.CheckTx = abci.ResponseCheckTx{Code: mc.code, Log: "failed", Data: mc.data}
+		return res, nil
+	}
+
+	height := int64(len(mc.blocks) + 1)
+	mc.blocks[height] = tmtypes.Txs{tx}
+
+	res.Height = height
+	res.DeliverTx = abci.ResponseDeliverTx{GasUsed: 21000}
+
+	return res, nil
+}
+
+// TxSearch only supports queries matching a single tag. As Tendermint's, it
+// serves the last page in place of any page past it.
+func (mc *mockTendermintClient) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	res := &ctypes.ResultTxSearch{Txs: []*ctypes.ResultTx{}}
+
+	for _, txResult := range mc.txResults {
+		for _, tag := range txResult.TxResult.Tags {
+			if fmt.Sprintf("%s='%s'", tag.Key, tag.Value) == query {
+				res.Txs = append(res.Txs, txResult)
+			}
+		}
+	}
+
+	res.TotalCount = len(res.Txs)
+
+	if lastPage := (res.TotalCount - 1 + perPage) / perPage; page > lastPage {
+		page = lastPage
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * perPage
+	if end := start + perPage; end < len(res.Txs) {
+		res.Txs = res.Txs[:end]
+	}
+
+	res.Txs = res.Txs[start:]
+	return res, nil
+}
+
+func (mc *mockTendermintClient) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	if path == app.QueryPathInfo && mc.codespace != sdk.CodespaceUndefined {
+		bz, err := json.Marshal(app.AppInfo{Codespace: mc.codespace})
+		if err != nil {
+			return nil, err
+		}
+
+		return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: bz}}, nil
+	}
+
+	if path != "/store/test/key" {
+		return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Code: 1}}, nil
+	}
+
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: mc.store[string(data)]}}, nil
+}
+
+func (mc *mockTendermintClient) NetInfo() (*ctypes.ResultNetInfo, error) {
+	return &ctypes.ResultNetInfo{NPeers: len(mc.peers), Peers: mc.peers}, nil
+}
+
+func (mc *mockTendermintClient) DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
+	mc.dialed = append(mc.dialed, peers...)
+	return &ctypes.ResultDialPeers{}, nil
+}
+
+func newTestEncodedTx(t *testing.T, nonce uint64) []byte {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	tx := types.NewTransaction(nonce, ethcmn.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx.Sign(testChainID, priv)
+
+	bz, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	return bz
+}
+
+// newTestEmbeddedTx returns an encoded Ethereum transaction wrapping an
+// EmbeddedTx.
+func newTestEmbeddedTx(t *testing.T) []byte {
+	tx := types.NewTransaction(0, types.EmbeddedTxAddress, big.NewInt(0), 100000, big.NewInt(1), []byte("embedded"))
+
+	bz, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	return bz
+}
+
+func TestTendermintBackendBlocks(t *testing.T) {
+	client := &mockTendermintClient{
+		blocks: map[int64]tmtypes.Txs{
+			1: {newTestEncodedTx(t, 0), tmtypes.Tx("not an ethereum tx"), newTestEmbeddedTx(t), newTestEncodedTx(t, 1)},
+			2: {},
+		},
+	}
+	backend := NewTendermintBackend(client)
+
+	latest, err := backend.LatestBlockNumber()
+	require.Nil(t, err)
+	require.Equal(t, int64(2), latest)
+
+	// the Ethereum transactions keep their index among all the block's
+	// transactions
+	testCases := []struct {
+		height        int64
+		expectedNonce []uint64
+		expectedIndex []uint64
+		expectErr     bool
+	}{
+		{1, []uint64{0, 1}, []uint64{0, 3}, false},
+		{2, []uint64{}, []uint64{}, false},
+		{3, nil, nil, true},
+	}
+
+	for i, tc := range testCases {
+		hash, txs, err := backend.BlockTransactions(tc.height)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, blockHash(tc.height), hash, fmt.Sprintf("unexpected block hash: test case #%d", i))
+		require.Len(t, txs, len(tc.expectedNonce), fmt.Sprintf("unexpected txs: test case #%d", i))
+
+		for j, tx := range txs {
+			require.Equal(t, tc.expectedNonce[j], tx.Nonce(), fmt.Sprintf("unexpected nonce: test case #%d", i))
+			require.Equal(t, tc.expectedIndex[j], tx.Index, fmt.Sprintf("unexpected index: test case #%d", i))
+		}
+	}
+}
+
+func TestTendermintBackendBlockHeader(t *testing.T) {
+	backend := NewTendermintBackend(&mockTendermintClient{blocks: map[int64]tmtypes.Txs{1: nil, 2: nil}})
+
+	header, err := backend.BlockHeader(2)
+	require.Nil(t, err)
+	require.Equal(t, &BlockHeader{
+		Hash:          blockHash(2),
+		Height:        2,
+		Time:          20,
+		LastBlockHash: blockHash(1).Bytes(),
+		AppHash:       appHash(2),
+	}, header)
+
+	_, err = backend.BlockHeader(3)
+	require.NotNil(t, err)
+}
+
+func TestTendermintBackendBroadcastTx(t *testing.T) {
+	client := &mockTendermintClient{blocks: make(map[int64]tmtypes.Txs)}
+	backend := NewTendermintBackend(client)
+
+	txBytes := newTestEncodedTx(t, 5)
+
+	testCases := []struct {
+		mode           BroadcastMode
+		code           uint32
+		expectedHeight int64
+		expectErr      bool
+	}{
+		{BroadcastAsync, 0, 0, false},
+		{BroadcastSync, 0, 0, false},
+		{BroadcastSync, 1, 0, true},
+		{BroadcastCommit, 0, 1, false},
+		{BroadcastCommit, 1, 0, true},
+		{BroadcastMode("unknown"), 0, 0, true},
+	}
+
+	for i, tc := range testCases {
+		client.code = tc.code
+		res, err := backend.BroadcastTx(txBytes, tc.mode)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tmtypes.Tx(txBytes).Hash(), res.Hash, fmt.Sprintf("unexpected hash: test case #%d", i))
+		require.Equal(t, tc.expectedHeight, res.Height, fmt.Sprintf("unexpected height: test case #%d", i))
+	}
+}
+
+func TestTendermintBackendBroadcastTxAbort(t *testing.T) {
+	client := &mockTendermintClient{blocks: make(map[int64]tmtypes.Txs), code: 3}
+	backend := NewTendermintBackend(client)
+
+	txBytes := newTestEncodedTx(t, 5)
+
+	// an ante handler abort results in go-ethereum's error for its cause
+	for i, mode := range []BroadcastMode{BroadcastSync, BroadcastCommit} {
+		client.data = []byte(`{"reason":"nonce too low","nonce":6}`)
+
+		_, err := backend.BroadcastTx(txBytes, mode)
+		require.EqualError(t, err, types.AbortNonceTooLow, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		client.data = []byte("not an abort")
+
+		_, err = backend.BroadcastTx(txBytes, mode)
+		require.EqualError(t, err, "transaction failed to broadcast with code 3: failed")
+	}
+}
+
+func TestTendermintBackendBroadcastTxGethError(t *testing.T) {
+	codespace := sdk.CodespaceType(3)
+
+	client := &mockTendermintClient{blocks: make(map[int64]tmtypes.Txs), codespace: codespace}
+	backend := NewTendermintBackend(client)
+
+	txBytes := newTestEncodedTx(t, 5)
+
+	testCases := []struct {
+		code        sdk.ABCICodeType
+		codespace   sdk.CodespaceType
+		expectedErr string
+	}{
+		{sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInsufficientFunds), codespace, types.AbortInsufficientFunds},
+		{sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeOutOfGas), codespace, "intrinsic gas too low"},
+		{sdk.ToABCICode(codespace, types.CodeGasPriceTooLow), codespace, "transaction underpriced"},
+		{sdk.ToABCICode(codespace, types.CodeTxTooLarge), codespace, "oversized data"},
+		{sdk.ToABCICode(codespace, types.CodeTxExpired), codespace, "transaction failed to broadcast with code 196612: failed"},
+		{sdk.ToABCICode(codespace, types.CodeGasPriceTooLow), sdk.CodespaceUndefined, "transaction failed to broadcast with code 196614: failed"},
+	}
+
+	for i, tc := range testCases {
+		client.code = uint32(tc.code)
+		client.codespace = tc.codespace
+
+		_, err := backend.BroadcastTx(txBytes, BroadcastSync)
+		require.EqualError(t, err, tc.expectedErr, fmt.Sprintf("unexpected error: test case #%d", i))
+	}
+
+	// the error is served with go-ethereum's error code
+	client.code = uint32(sdk.ToABCICode(codespace, types.CodeIntrinsicGas))
+	client.codespace = codespace
+
+	api := NewPublicEthAPI(backend, testChainID, NewGasPriceOracle(backend, DefaultGasPriceConfig()), NewEtherbase(ethcmn.Address{}))
+
+	srv := ethrpc.NewServer()
+	require.Nil(t, srv.RegisterName("eth", api))
+	defer srv.Stop()
+
+	rpcClient := ethrpc.DialInProc(srv)
+	defer rpcClient.Close()
+
+	err := rpcClient.Call(nil, "eth_sendRawTransaction", hexutil.Bytes(txBytes))
+	require.EqualError(t, err, "intrinsic gas too low")
+
+	rpcErr, ok := err.(ethrpc.Error)
+	require.True(t, ok)
+	require.Equal(t, -32000, rpcErr.ErrorCode())
+}
+
+func TestTendermintBackendTxResult(t *testing.T) {
+	txBytes := newTestEncodedTx(t, 0)
+
+	tx, err := decodeRawTransaction(txBytes)
+	require.Nil(t, err)
+
+	client := &mockTendermintClient{
+		txResults: []*ctypes.ResultTx{{
+			Hash:   tmtypes.Tx(txBytes).Hash(),
+			Height: 3,
+			TxResult: abci.ResponseDeliverTx{
+				Code:    1,
+				Log:     "out of gas",
+				GasUsed: 21000,
+				Tags:    []cmn.KVPair{{Key: []byte(app.TagEthTxHash), Value: []byte(tx.Hash().Hex())}},
+			},
+		}},
+	}
+	backend := NewTendermintBackend(client)
+
+	res, err := backend.TxResult(tx.Hash())
+	require.Nil(t, err)
+	require.Equal(t, &BroadcastResult{
+		Hash: tmtypes.Tx(txBytes).Hash(), Height: 3, Code: 1, Log: "out of gas", GasUsed: 21000,
+	}, res)
+
+	res, err = backend.TxResult(ethcmn.Hash{})
+	require.Nil(t, err)
+	require.Nil(t, res)
+}
+
+func TestTendermintBackendAccountTransactions(t *testing.T) {
+	account := ethcmn.HexToAddress("0x0000000000000000000000000000000000000011")
+	client := &mockTendermintClient{}
+
+	var hashes []ethcmn.Hash
+
+	for i := 0; i < 3; i++ {
+		txBytes := newTestEncodedTx(t, uint64(i))
+
+		tx, err := decodeRawTransaction(txBytes)
+		require.Nil(t, err)
+
+		tag := app.TagEthFrom
+		if i == 2 {
+			tag = app.TagEthTo
+		}
+
+		client.txResults = append(client.txResults, &ctypes.ResultTx{
+			Hash:     tmtypes.Tx(txBytes).Hash(),
+			Height:   int64(i + 1),
+			Tx:       txBytes,
+			TxResult: abci.ResponseDeliverTx{Tags: []cmn.KVPair{{Key: []byte(tag), Value: []byte(account.Hex())}}},
+		})
+		hashes = append(hashes, tx.Hash())
+	}
+
+	backend := NewTendermintBackend(client)
+
+	testCases := []struct {
+		recipient bool
+		page      int
+		perPage   int
+		expected  []ethcmn.Hash
+		total     int
+	}{
+		{false, 1, 10, hashes[:2], 2},
+		{false, 2, 1, hashes[1:2], 2},
+		{false, 3, 1, []ethcmn.Hash{}, 2},
+		{true, 1, 10, hashes[2:], 1},
+	}
+
+	for i, tc := range testCases {
+		txs, total, err := backend.AccountTransactions(account, tc.recipient, tc.page, tc.perPage)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expected, txs, fmt.Sprintf("unexpected transactions: test case #%d", i))
+		require.Equal(t, tc.total, total, fmt.Sprintf("unexpected total: test case #%d", i))
+	}
+
+	txs, total, err := backend.AccountTransactions(ethcmn.Address{}, false, 1, 10)
+	require.Nil(t, err)
+	require.Empty(t, txs)
+	require.Zero(t, total)
+}
+
+func TestTendermintBackendPendingTransactions(t *testing.T) {
+	client := &mockTendermintClient{
+		mempool: tmtypes.Txs{newTestEncodedTx(t, 5), tmtypes.Tx("not an ethereum tx"), newTestEmbeddedTx(t)},
+	}
+	backend := NewTendermintBackend(client)
+
+	txs, err := backend.PendingTransactions()
+	require.Nil(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, uint64(5), txs[0].Nonce())
+}
+
+func TestTendermintBackendQueryStore(t *testing.T) {
+	client := &mockTendermintClient{store: map[string][]byte{"key": []byte("value")}}
+	backend := NewTendermintBackend(client)
+
+	value, err := backend.QueryStore("test", []byte("key"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("value"), value)
+
+	value, err = backend.QueryStore("test", []byte("missing"))
+	require.Nil(t, err)
+	require.Nil(t, value)
+
+	_, err = backend.QueryStore("unknown", []byte("key"))
+	require.NotNil(t, err)
+}
+
+func TestTendermintBackendValidatorAddress(t *testing.T) {
+	backend := NewTendermintBackend(&mockTendermintClient{})
+
+	addr, err := backend.ValidatorAddress()
+	require.Nil(t, err)
+	require.Equal(t, testValidatorAddr, addr)
+}
+
+func TestTendermintBackendSyncStatus(t *testing.T) {
+	backend := NewTendermintBackend(&mockTendermintClient{blocks: map[int64]tmtypes.Txs{1: nil, 2: nil}})
+
+	status, err := backend.SyncStatus()
+	require.Nil(t, err)
+	require.Equal(t, &SyncStatus{LatestBlockHeight: 2, CatchingUp: true, TxIndexEnabled: true}, status)
+}
+
+func TestTendermintBackendPeers(t *testing.T) {
+	client := &mockTendermintClient{
+		peers: []ctypes.Peer{
+			{NodeInfo: p2p.NodeInfo{ID: "abcd", Moniker: "val0", ListenAddr: "10.0.0.1:26656", Network: "test"}, IsOutbound: true},
+		},
+	}
+	backend := NewTendermintBackend(client)
+
+	peers, err := backend.Peers()
+	require.Nil(t, err)
+	require.Equal(t, []PeerInfo{
+		{ID: "abcd", Moniker: "val0", ListenAddr: "10.0.0.1:26656", Network: "test", Outbound: true},
+	}, peers)
+
+	require.Nil(t, backend.DialPeers([]string{"efgh@10.0.0.2:26656"}, true))
+	require.Equal(t, []string{"efgh@10.0.0.2:26656"}, client.dialed)
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// CallArgs defines the arguments of a message call executed without a
+// transaction, e.g. by eth_estimateGas. The call data may be given as either
+// data or input, the latter taking precedence.
+type CallArgs struct {
+	From     *ethcmn.Address `json:"from"`
+	To       *ethcmn.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     *hexutil.Bytes  `json:"data"`
+	Input    *hexutil.Bytes  `json:"input"`
+}
+
+// RPCTransaction represents a transaction that will serialize to the RPC
+// representation of a transaction.
+type RPCTransaction struct {
+	BlockHash        ethcmn.Hash     `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             ethcmn.Address  `json:"from"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             ethcmn.Hash     `json:"hash"`
+	Input            hexutil.Bytes   `json:"input"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	To               *ethcmn.Address `json:"to"`
+	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
+	Value            *hexutil.Big    `json:"value"`
+	V                *hexutil.Big    `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+}
+
+// NewRPCTransaction returns a transaction that will serialize to the RPC
+// representation, with the given location metadata set. The sender is derived
+// from the transaction's signature using the given chain ID.
+func NewRPCTransaction(
+	tx *types.Transaction, blockHash ethcmn.Hash, blockNumber, index uint64, chainID *big.Int,
+) *RPCTransaction {
+
+	rpcTx := NewPendingRPCTransaction(tx, chainID)
+	rpcTx.BlockHash = blockHash
+	rpcTx.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
+	rpcTx.TransactionIndex = hexutil.Uint(index)
+
+	return rpcTx
+}
+
+// NewPendingRPCTransaction returns a pending transaction, one that is not yet
+// included in a block, that will serialize to the RPC representation. As in
+// Ethereum, the block number of a pending transaction is nil.
+func NewPendingRPCTransaction(tx *types.Transaction, chainID *big.Int) *RPCTransaction {
+	// an invalid signature results in an empty sender as in Ethereum
+	from, _ := tx.VerifySig(chainID)
+	v, r, s := tx.RawSignatureValues()
+
+	return &RPCTransaction{
+		From:     from,
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Hash:     tx.Hash(),
+		Input:    hexutil.Bytes(tx.Data()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		To:       tx.To(),
+		Value:    (*hexutil.Big)(tx.Value()),
+		V:        (*hexutil.Big)(v),
+		R:        (*hexutil.Big)(r),
+		S:        (*hexutil.Big)(s),
+	}
+}
+
+// RPCReceipt represents a transaction receipt that will serialize to the RPC
+// representation of a receipt.
+type RPCReceipt struct {
+	BlockHash        ethcmn.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionHash  ethcmn.Hash     `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	From             ethcmn.Address  `json:"from"`
+	To               *ethcmn.Address `json:"to"`
+	GasUsed          hexutil.Uint64  `json:"gasUsed"`
+	ContractAddress  *ethcmn.Address `json:"contractAddress"`
+	Logs             []*ethtypes.Log `json:"logs"`
+	Status           hexutil.Uint    `json:"status"`
+	RevertReason     string          `json:"revertReason,omitempty"`
+}
+
+// NewRPCReceipt returns a receipt that will serialize to the RPC
+// representation for a transaction committed at a given location with the
+// given broadcast result. The sender is derived from the transaction's
+// signature using the given chain ID. The status, gas used and created
+// contract address are those of the types.ResultData held by the result: a
+// transaction whose execution failed, e.g. as it reverted, has a successful
+// result, its failure being reflected by the data, while a transaction that
+// was not executed has a failed result and no data. The revert reason of a
+// failed execution is decoded from its return data if present. The logs of a
+// successful execution are indexed within the block from the given log index.
+func NewRPCReceipt(
+	tx *types.Transaction, blockHash ethcmn.Hash, blockNumber, index uint64, logIndex uint, res *BroadcastResult,
+	chainID *big.Int,
+) (*RPCReceipt, error) {
+
+	data, err := decodeTxResult(res)
+	if err != nil {
+		return nil, fmt.Errorf("invalid result of transaction %s: %v", tx.Hash().Hex(), err)
+	}
+
+	from, _ := tx.VerifySig(chainID)
+
+	receipt := &RPCReceipt{
+		BlockHash:        blockHash,
+		BlockNumber:      hexutil.Uint64(blockNumber),
+		TransactionHash:  tx.Hash(),
+		TransactionIndex: hexutil.Uint64(index),
+		From:             from,
+		To:               tx.To(),
+		GasUsed:          hexutil.Uint64(data.GasUsed),
+		Logs:             newRPCLogs(data.Logs, blockNumber, blockHash, tx.Hash(), index, logIndex),
+	}
+
+	switch {
+	case data.Failed:
+		receipt.RevertReason, _ = core.UnpackRevertReason(data.Ret)
+
+	case res.Code == 0:
+		receipt.Status = hexutil.Uint(ethtypes.ReceiptStatusSuccessful)
+	}
+
+	if tx.To() == nil {
+		receipt.ContractAddress = &data.ContractAddress
+	}
+
+	return receipt, nil
+}
+
+// decodeRawTransaction decodes an RLP encoded Ethereum transaction.
+func decodeRawTransaction(rawTx []byte) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(rawTx, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,25 @@
+package rpc
+
+import (
+	"net/http"
+	"strings"
+
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// WithWebsocket returns an http.Handler serving WebSocket upgrade requests
+// with the given server's WebSocket handler, accepting connections from the
+// given origins, and any other request with the given handler. Subscriptions,
+// e.g. eth_subscribe, are only served over WebSocket connections.
+func WithWebsocket(handler http.Handler, srv *ethrpc.Server, allowedOrigins []string) http.Handler {
+	ws := srv.WebsocketHandler(allowedOrigins)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			ws.ServeHTTP(w, r)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	tmcfg "github.com/tendermint/tendermint/config"
+)
+
+// peerIDLength is the length of a Tendermint peer ID, i.e. of the address of
+// a node key, in bytes.
+const peerIDLength = 20
+
+// P2PConfig defines the peer-to-peer configuration of the Tendermint node
+// backing an Ethermint daemon. Peers are given as "id@host:port" addresses.
+type P2PConfig struct {
+	// Seeds are the nodes dialed to discover peers through peer exchange
+	Seeds []string
+
+	// PersistentPeers are the nodes the node keeps connections to at all
+	// times
+	PersistentPeers []string
+
+	// PrivatePeerIDs are the IDs of the peers never gossiped to other peers
+	PrivatePeerIDs []string
+
+	// PEX enables the peer exchange reactor through which the node discovers
+	// peers and shares its own
+	PEX bool
+
+	// SeedMode has the node crawl the network for peers, responding to peer
+	// exchange requests and disconnecting afterwards
+	SeedMode bool
+
+	// AddrBook is the path to the address book of discovered peers
+	AddrBook string
+
+	// AddrBookStrict only adds routable addresses to the address book
+	AddrBookStrict bool
+}
+
+// DefaultP2PConfig returns the default peer-to-peer configuration, i.e. that of
+// a Tendermint node.
+func DefaultP2PConfig() P2PConfig {
+	return P2PConfigFromTendermint(tmcfg.DefaultP2PConfig())
+}
+
+// LockedP2PConfig returns the peer-to-peer configuration of a node connecting
+// to a fixed set of peers only, e.g. within a private consortium. Peer
+// exchange is disabled so that the node neither discovers nor is discovered
+// by any other node, and its address book is not strict as consortium peers
+// commonly use private addresses.
+func LockedP2PConfig(peers ...string) P2PConfig {
+	cfg := DefaultP2PConfig()
+	cfg.PersistentPeers = peers
+	cfg.PEX = false
+	cfg.AddrBookStrict = false
+
+	return cfg
+}
+
+// P2PConfigFromTendermint returns the peer-to-peer configuration of a
+// Tendermint node configuration.
+func P2PConfigFromTendermint(tmCfg *tmcfg.P2PConfig) P2PConfig {
+	return P2PConfig{
+		Seeds:           splitList(tmCfg.Seeds),
+		PersistentPeers: splitList(tmCfg.PersistentPeers),
+		PrivatePeerIDs:  splitList(tmCfg.PrivatePeerIDs),
+		PEX:             tmCfg.PexReactor,
+		SeedMode:        tmCfg.SeedMode,
+		AddrBook:        tmCfg.AddrBook,
+		AddrBookStrict:  tmCfg.AddrBookStrict,
+	}
+}
+
+// Locked returns true if the node only connects to its persistent peers.
+func (cfg P2PConfig) Locked() bool {
+	return !cfg.PEX && len(cfg.Seeds) == 0
+}
+
+// Validate returns an error if the configuration is invalid. Every peer must
+// have a valid address and be listed once, and seeds as well as seed mode
+// require peer exchange, without which the node would never use them.
+func (cfg P2PConfig) Validate() error {
+	if err := validatePeers("seed", cfg.Seeds); err != nil {
+		return err
+	}
+
+	if err := validatePeers("persistent peer", cfg.PersistentPeers); err != nil {
+		return err
+	}
+
+	for _, id := range cfg.PrivatePeerIDs {
+		if err := validatePeerID(id); err != nil {
+			return fmt.Errorf("invalid private peer ID %q: %v", id, err)
+		}
+	}
+
+	switch {
+	case !cfg.PEX && len(cfg.Seeds) > 0:
+		return fmt.Errorf("seeds require peer exchange")
+
+	case !cfg.PEX && cfg.SeedMode:
+		return fmt.Errorf("seed mode requires peer exchange")
+
+	case cfg.PEX && cfg.AddrBook == "":
+		return fmt.Errorf("peer exchange requires an address book")
+	}
+
+	return nil
+}
+
+// Apply sets the peer-to-peer configuration of a Tendermint node
+// configuration.
+func (cfg P2PConfig) Apply(tmCfg *tmcfg.P2PConfig) {
+	tmCfg.Seeds = strings.Join(cfg.Seeds, ",")
+	tmCfg.PersistentPeers = strings.Join(cfg.PersistentPeers, ",")
+	tmCfg.PrivatePeerIDs = strings.Join(cfg.PrivatePeerIDs, ",")
+	tmCfg.PexReactor = cfg.PEX
+	tmCfg.SeedMode = cfg.SeedMode
+	tmCfg.AddrBook = cfg.AddrBook
+	tmCfg.AddrBookStrict = cfg.AddrBookStrict
+}
+
+// validatePeers returns an error if any of the given peers has an invalid
+// address or is listed more than once.
+func validatePeers(kind string, peers []string) error {
+	seen := make(map[string]bool, len(peers))
+
+	for _, peer := range peers {
+		if err := validatePeerAddress(peer); err != nil {
+			return fmt.Errorf("invalid %s address %q: %v", kind, peer, err)
+		}
+
+		if seen[peer] {
+			return fmt.Errorf("duplicate %s %q", kind, peer)
+		}
+
+		seen[peer] = true
+	}
+
+	return nil
+}
+
+// validatePeerAddress returns an error if a peer address is not of the
+// "id@host:port" format. Host names are not resolved.
+func validatePeerAddress(addr string) error {
+	parts := strings.SplitN(addr, "@", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("missing peer ID")
+	}
+
+	if err := validatePeerID(parts[0]); err != nil {
+		return err
+	}
+
+	host, port, err := net.SplitHostPort(parts[1])
+	if err != nil {
+		return err
+	}
+
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	return nil
+}
+
+// validatePeerID returns an error if a peer ID is not a hex encoded node key
+// address.
+func validatePeerID(id string) error {
+	bz, err := hex.DecodeString(id)
+	if err != nil || len(bz) != peerIDLength {
+		return fmt.Errorf("peer ID must be %d hex encoded bytes", peerIDLength)
+	}
+
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty elements.
+func splitList(list string) []string {
+	var elems []string
+	for _, elem := range strings.Split(list, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+
+	return elems
+}
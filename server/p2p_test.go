@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmcfg "github.com/tendermint/tendermint/config"
+)
+
+const (
+	testPeerID = "0123456789abcdef0123456789abcdef01234567"
+	testPeer1  = testPeerID + "@10.0.0.1:26656"
+	testPeer2  = "89abcdef0123456789abcdef0123456789abcdef@node2.example.com:26656"
+)
+
+func TestP2PConfigValidate(t *testing.T) {
+	testCases := []struct {
+		modify    func(*P2PConfig)
+		expectErr bool
+	}{
+		{func(cfg *P2PConfig) {}, false},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{testPeer1}; cfg.PersistentPeers = []string{testPeer2} }, false},
+		{func(cfg *P2PConfig) { cfg.SeedMode = true; cfg.Seeds = []string{testPeer1, testPeer2} }, false},
+		{func(cfg *P2PConfig) { cfg.PrivatePeerIDs = []string{testPeerID} }, false},
+		{func(cfg *P2PConfig) { cfg.PersistentPeers = []string{"[::1]:26656"} }, true},
+		{func(cfg *P2PConfig) { cfg.PersistentPeers = []string{testPeerID + "@[::1]:26656"} }, false},
+		{func(cfg *P2PConfig) { cfg.PersistentPeers = []string{testPeer1, testPeer1} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{"0123456789abcdef@10.0.0.1:26656"} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{"0123456789abcdef0123456789abcdef0123456g@10.0.0.1:26656"} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{testPeerID + "@10.0.0.1"} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{testPeerID + "@:26656"} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{testPeerID + "@10.0.0.1:0"} }, true},
+		{func(cfg *P2PConfig) { cfg.Seeds = []string{testPeerID + "@10.0.0.1:65536"} }, true},
+		{func(cfg *P2PConfig) { cfg.PrivatePeerIDs = []string{"node"} }, true},
+		{func(cfg *P2PConfig) { cfg.PEX = false; cfg.Seeds = []string{testPeer1} }, true},
+		{func(cfg *P2PConfig) { cfg.PEX = false; cfg.SeedMode = true }, true},
+		{func(cfg *P2PConfig) { cfg.PEX = false; cfg.AddrBook = "" }, false},
+		{func(cfg *P2PConfig) { cfg.AddrBook = "" }, true},
+	}
+
+	for i, tc := range testCases {
+		cfg := DefaultP2PConfig()
+		tc.modify(&cfg)
+
+		err := cfg.Validate()
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("unexpected result: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
+
+func TestLockedP2PConfig(t *testing.T) {
+	cfg := LockedP2PConfig(testPeer1, testPeer2)
+	require.Nil(t, cfg.Validate())
+	require.True(t, cfg.Locked())
+	require.False(t, cfg.PEX)
+	require.False(t, cfg.AddrBookStrict)
+	require.Equal(t, []string{testPeer1, testPeer2}, cfg.PersistentPeers)
+
+	require.False(t, DefaultP2PConfig().Locked())
+
+	cfg.Seeds = []string{testPeer1}
+	require.False(t, cfg.Locked())
+}
+
+func TestP2PConfigApply(t *testing.T) {
+	cfg := LockedP2PConfig(testPeer1, testPeer2)
+	cfg.PrivatePeerIDs = []string{testPeerID}
+
+	tmCfg := tmcfg.DefaultP2PConfig()
+	cfg.Apply(tmCfg)
+
+	require.Equal(t, "", tmCfg.Seeds)
+	require.Equal(t, testPeer1+","+testPeer2, tmCfg.PersistentPeers)
+	require.Equal(t, testPeerID, tmCfg.PrivatePeerIDs)
+	require.False(t, tmCfg.PexReactor)
+	require.False(t, tmCfg.AddrBookStrict)
+
+	// settings not covered by the configuration are left untouched
+	require.Equal(t, tmcfg.DefaultP2PConfig().ListenAddress, tmCfg.ListenAddress)
+
+	require.Equal(t, cfg, P2PConfigFromTendermint(tmCfg))
+
+	// lists are split on commas, ignoring whitespace and empty elements
+	tmCfg.Seeds = " " + testPeer1 + ", ," + testPeer2
+	require.Equal(t, []string{testPeer1, testPeer2}, P2PConfigFromTendermint(tmCfg).Seeds)
+}
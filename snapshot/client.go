@@ -0,0 +1,90 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cosmos/ethermint/state"
+
+	"google.golang.org/grpc"
+)
+
+// Dial returns a client connection to a snapshot gRPC server at the given
+// address.
+//
+// NOTE: The connection is not encrypted. Snapshot entries are verified against
+// the snapshot's header, which must be checked against a trusted source.
+func Dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, grpc.WithInsecure(), grpc.WithCodec(jsonCodec{}))
+}
+
+// Fetch streams the snapshot of the Ethereum state at a given height from a
+// snapshot server and writes it to a state.SnapshotWriter. A non-positive
+// height fetches the latest height. The returned header is the header of the
+// snapshot streamed.
+func Fetch(ctx context.Context, conn *grpc.ClientConn, height int64, w state.SnapshotWriter) (state.SnapshotHeader, error) {
+	stream, err := conn.NewStream(ctx, &streamDesc, streamMethod)
+	if err != nil {
+		return state.SnapshotHeader{}, err
+	}
+
+	if err := stream.SendMsg(&StreamRequest{Height: height}); err != nil {
+		return state.SnapshotHeader{}, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return state.SnapshotHeader{}, err
+	}
+
+	sw := &responseWriter{w: w}
+
+	for {
+		var res StreamResponse
+
+		err := stream.RecvMsg(&res)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return state.SnapshotHeader{}, err
+		}
+
+		if err := sw.write(res); err != nil {
+			return state.SnapshotHeader{}, err
+		}
+	}
+
+	return sw.finish()
+}
+
+// responseWriter writes the messages of a snapshot, as streamed or stored in
+// a file, to a state.SnapshotWriter.
+type responseWriter struct {
+	w      state.SnapshotWriter
+	header *state.SnapshotHeader
+}
+
+// write writes a single message. An error is returned if the message is not
+// the header while no header has been written or vice versa.
+func (sw *responseWriter) write(res StreamResponse) error {
+	switch {
+	case res.Header != nil && sw.header == nil:
+		sw.header = res.Header
+		return sw.w.WriteHeader(*sw.header)
+
+	case res.Entry != nil && sw.header != nil:
+		return sw.w.WriteEntry(*res.Entry)
+
+	default:
+		return fmt.Errorf("unexpected snapshot message")
+	}
+}
+
+// finish returns the header written or an error if no header was written.
+func (sw *responseWriter) finish() (state.SnapshotHeader, error) {
+	if sw.header == nil {
+		return state.SnapshotHeader{}, fmt.Errorf("snapshot ended without a header")
+	}
+
+	return *sw.header, nil
+}
@@ -0,0 +1,56 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/cosmos/ethermint/state"
+)
+
+// fileWriter implements the state.SnapshotWriter interface by encoding the
+// header and every entry as a JSON StreamResponse, one per line.
+type fileWriter struct {
+	enc *json.Encoder
+}
+
+// NewFileWriter returns a state.SnapshotWriter writing a snapshot file to the
+// given writer. A snapshot file holds the same messages as a snapshot stream
+// and can be imported with ReadFile.
+func NewFileWriter(w io.Writer) state.SnapshotWriter {
+	return &fileWriter{enc: json.NewEncoder(w)}
+}
+
+// WriteHeader implements the state.SnapshotWriter interface.
+func (fw *fileWriter) WriteHeader(header state.SnapshotHeader) error {
+	return fw.enc.Encode(&StreamResponse{Header: &header})
+}
+
+// WriteEntry implements the state.SnapshotWriter interface.
+func (fw *fileWriter) WriteEntry(entry state.SnapshotEntry) error {
+	return fw.enc.Encode(&StreamResponse{Entry: &entry})
+}
+
+// ReadFile reads a snapshot file written by a writer returned by NewFileWriter
+// and writes it to a state.SnapshotWriter. The returned header is the header
+// of the snapshot read.
+func ReadFile(r io.Reader, w state.SnapshotWriter) (state.SnapshotHeader, error) {
+	dec := json.NewDecoder(r)
+	sw := &responseWriter{w: w}
+
+	for {
+		var res StreamResponse
+
+		err := dec.Decode(&res)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return state.SnapshotHeader{}, err
+		}
+
+		if err := sw.write(res); err != nil {
+			return state.SnapshotHeader{}, err
+		}
+	}
+
+	return sw.finish()
+}
@@ -0,0 +1,46 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cosmos/ethermint/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	source := newTestSource(t)
+
+	var buf bytes.Buffer
+	require.Nil(t, source.ExportSnapshot(1, NewFileWriter(&buf)))
+
+	target := newTestDatabase(t)
+
+	importer, err := state.NewSnapshotImporter(target)
+	require.Nil(t, err)
+
+	header, err := ReadFile(bytes.NewReader(buf.Bytes()), importer)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), header.Height)
+
+	commitID, entries, err := importer.Commit()
+	require.Nil(t, err)
+	require.Equal(t, 3, entries)
+
+	expected, err := source.Dump(1)
+	require.Nil(t, err)
+
+	actual, err := target.Dump(commitID.Version)
+	require.Nil(t, err)
+	require.Equal(t, expected.Accounts, actual.Accounts)
+
+	// a file without a header cannot be read
+	importer, err = state.NewSnapshotImporter(newTestDatabase(t))
+	require.Nil(t, err)
+
+	lines := bytes.SplitN(buf.Bytes(), []byte("\n"), 2)
+
+	_, err = ReadFile(bytes.NewReader(lines[1]), importer)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,120 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/cosmos/ethermint/state"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// ServiceName is the name of the gRPC service streaming state snapshots.
+	ServiceName = "ethermint.snapshot.Snapshot"
+
+	streamMethod = "/" + ServiceName + "/Stream"
+)
+
+type (
+	// StreamRequest requests a snapshot of the Ethereum state at a given
+	// height. A non-positive height requests the latest height.
+	StreamRequest struct {
+		Height int64 `json:"height"`
+	}
+
+	// StreamResponse is a single message of a snapshot stream. The first
+	// message carries the snapshot's header and every subsequent message
+	// carries a single entry.
+	StreamResponse struct {
+		Header *state.SnapshotHeader `json:"header,omitempty"`
+		Entry  *state.SnapshotEntry  `json:"entry,omitempty"`
+	}
+
+	// jsonCodec implements the grpc.Codec interface using JSON, as the
+	// service's messages are plain Go types rather than generated protobuf
+	// messages.
+	jsonCodec struct{}
+
+	// snapshotServer defines the handler type of the snapshot service.
+	snapshotServer interface {
+		stream(req StreamRequest, stream grpc.ServerStream) error
+	}
+
+	// server implements the snapshot service on top of a state Database.
+	// Snapshots are exported one at a time as exporting loads the requested
+	// version of the Database.
+	server struct {
+		mtx sync.Mutex
+		db  *state.Database
+	}
+
+	// streamWriter implements the state.SnapshotWriter interface by sending
+	// the header and every entry on a gRPC stream.
+	streamWriter struct {
+		stream grpc.ServerStream
+	}
+)
+
+var (
+	serviceDesc = grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*snapshotServer)(nil),
+		Streams:     []grpc.StreamDesc{streamDesc},
+	}
+
+	streamDesc = grpc.StreamDesc{
+		StreamName:    "Stream",
+		Handler:       streamHandler,
+		ServerStreams: true,
+	}
+)
+
+// Marshal implements the grpc.Codec interface.
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal implements the grpc.Codec interface.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// String implements the grpc.Codec interface.
+func (jsonCodec) String() string { return "json" }
+
+// NewServer returns a reference to a new gRPC server serving snapshots of the
+// given state Database.
+func NewServer(db *state.Database) *grpc.Server {
+	srv := grpc.NewServer(grpc.CustomCodec(jsonCodec{}))
+	srv.RegisterService(&serviceDesc, &server{db: db})
+
+	return srv
+}
+
+func streamHandler(srv interface{}, stream grpc.ServerStream) error {
+	var req StreamRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	return srv.(snapshotServer).stream(req, stream)
+}
+
+func (s *server) stream(req StreamRequest, stream grpc.ServerStream) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	height := req.Height
+	if height <= 0 {
+		height = s.db.LatestVersion()
+	}
+
+	return s.db.ExportSnapshot(height, streamWriter{stream})
+}
+
+// WriteHeader implements the state.SnapshotWriter interface.
+func (w streamWriter) WriteHeader(header state.SnapshotHeader) error {
+	return w.stream.SendMsg(&StreamResponse{Header: &header})
+}
+
+// WriteEntry implements the state.SnapshotWriter interface.
+func (w streamWriter) WriteEntry(entry state.SnapshotEntry) error {
+	return w.stream.SendMsg(&StreamResponse{Entry: &entry})
+}
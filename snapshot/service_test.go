@@ -0,0 +1,85 @@
+package snapshot
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/cosmos/ethermint/state"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func newTestDatabase(t *testing.T) *state.Database {
+	db, err := state.NewDatabase(dbm.NewMemDB(), dbm.NewMemDB())
+	require.Nil(t, err)
+
+	return db
+}
+
+// newTestSource returns a Database holding a single contract with code and
+// storage committed at height 1.
+func newTestSource(t *testing.T) *state.Database {
+	source := newTestDatabase(t)
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, source)
+	require.Nil(t, err)
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	stateDB.AddBalance(addr, big.NewInt(100))
+	stateDB.SetCode(addr, []byte{0x60, 0x00, 0x60, 0x00, 0xf3})
+	stateDB.SetState(addr, ethcmn.BigToHash(big.NewInt(1)), ethcmn.BigToHash(big.NewInt(42)))
+
+	_, err = stateDB.Commit(false)
+	require.Nil(t, err)
+	source.Commit()
+
+	return source
+}
+
+func TestFetch(t *testing.T) {
+	source := newTestSource(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	srv := NewServer(source)
+	go srv.Serve(listener)
+	defer srv.Stop()
+
+	conn, err := Dial(listener.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	target := newTestDatabase(t)
+
+	importer, err := state.NewSnapshotImporter(target)
+	require.Nil(t, err)
+
+	// the latest height is streamed by default
+	header, err := Fetch(context.Background(), conn, 0, importer)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), header.Height)
+
+	commitID, entries, err := importer.Commit()
+	require.Nil(t, err)
+	require.Equal(t, 3, entries)
+
+	expected, err := source.Dump(1)
+	require.Nil(t, err)
+
+	actual, err := target.Dump(commitID.Version)
+	require.Nil(t, err)
+	require.Equal(t, expected.Accounts, actual.Accounts)
+
+	// a height that does not exist cannot be streamed
+	importer, err = state.NewSnapshotImporter(newTestDatabase(t))
+	require.Nil(t, err)
+
+	_, err = Fetch(context.Background(), conn, 10, importer)
+	require.NotNil(t, err)
+}
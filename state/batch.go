@@ -0,0 +1,83 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// GetAccounts returns the Ethereum accounts at the given addresses, in the
+// order of the addresses, with a nil account for every address without one.
+// Unlike reading them through an Ethereum StateDB, the accounts are read in a
+// single pass over the accounts store (see types.MultiGet), e.g. to serve the
+// accounts touched by a block. As with OpenTrie, uncommitted state is
+// included. An error is returned if an account is malformed.
+func (db *Database) GetAccounts(addrs []ethcmn.Address) ([]*ethstate.Account, error) {
+	keys := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		keys[i] = addr.Bytes()
+	}
+
+	accs := make([]*ethstate.Account, len(addrs))
+	for i, bz := range types.MultiGet(db.readStore(db.accountsCache, AccountsKey), keys) {
+		if bz == nil {
+			continue
+		}
+
+		accs[i] = new(ethstate.Account)
+		if err := rlp.DecodeBytes(bz, accs[i]); err != nil {
+			return nil, fmt.Errorf("failed to decode account %s: %v", addrs[i].Hex(), err)
+		}
+	}
+
+	return accs, nil
+}
+
+// GetStorage returns the values of the given storage slots of the contract at
+// an address, in the order of the slots, with an empty value for every slot
+// that is not set. The slots are read in a single pass over the storage store
+// (see types.MultiGet). As with OpenStorageTrie, uncommitted state is
+// included. An error is returned if a value is malformed.
+func (db *Database) GetStorage(addr ethcmn.Address, slots []ethcmn.Hash) ([]ethcmn.Hash, error) {
+	// contract storage is prefixed by the hash of the contract's address
+	prefix := ethcrypto.Keccak256(addr.Bytes())
+
+	keys := make([][]byte, len(slots))
+	for i, slot := range slots {
+		keys[i] = append(append([]byte{}, prefix...), slot.Bytes()...)
+	}
+
+	values := make([]ethcmn.Hash, len(slots))
+	for i, bz := range types.MultiGet(db.readStore(db.storageCache, StorageKey), keys) {
+		if len(bz) == 0 {
+			continue
+		}
+
+		_, content, _, err := rlp.Split(bz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode storage slot %s of %s: %v", slots[i].Hex(), addr.Hex(), err)
+		}
+
+		values[i].SetBytes(content)
+	}
+
+	return values, nil
+}
+
+// readStore returns the given cache of a store, or the committed store if no
+// state has been opened yet.
+func (db *Database) readStore(cache store.CacheKVStore, key sdk.StoreKey) sdk.KVStore {
+	if cache != nil {
+		return cache
+	}
+
+	return db.stateStore.GetCommitKVStore(key)
+}
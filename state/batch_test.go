@@ -0,0 +1,72 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseGetAccountsAndStorage(t *testing.T) {
+	testDB := newDatabase()
+
+	addr1 := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	addr2 := ethcmn.HexToAddress("0x0100000000000000000000000000000000000000")
+	missing := ethcmn.HexToAddress("0x0200000000000000000000000000000000000000")
+
+	slot1 := ethcmn.BigToHash(big.NewInt(1))
+	slot2 := ethcmn.BigToHash(big.NewInt(2))
+
+	// nothing can be read prior to opening any state
+	accs, err := testDB.GetAccounts([]ethcmn.Address{addr1})
+	require.Nil(t, err)
+	require.Equal(t, []*ethstate.Account{nil}, accs)
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr1, big.NewInt(100))
+	stateDB.SetNonce(addr2, 7)
+	stateDB.SetState(addr1, slot1, ethcmn.BigToHash(big.NewInt(42)))
+	stateDB.SetState(addr1, slot2, ethcmn.HexToHash("0xff00000000000000000000000000000000000000000000000000000000000001"))
+
+	root, err := stateDB.Commit(false)
+	require.Nil(t, err)
+
+	// uncommitted state is read
+	accs, err = testDB.GetAccounts([]ethcmn.Address{addr2, missing, addr1})
+	require.Nil(t, err)
+	require.Len(t, accs, 3)
+	require.Equal(t, uint64(7), accs[0].Nonce)
+	require.Nil(t, accs[1])
+	require.Equal(t, big.NewInt(100), accs[2].Balance)
+
+	testDB.Commit()
+
+	// committed state is read by a fresh state
+	stateDB, err = ethstate.New(root, testDB)
+	require.Nil(t, err)
+
+	accs, err = testDB.GetAccounts([]ethcmn.Address{addr1})
+	require.Nil(t, err)
+	require.Equal(t, stateDB.GetBalance(addr1), accs[0].Balance)
+
+	values, err := testDB.GetStorage(addr1, []ethcmn.Hash{slot2, {}, slot1})
+	require.Nil(t, err)
+	require.Equal(t, []ethcmn.Hash{stateDB.GetState(addr1, slot2), {}, stateDB.GetState(addr1, slot1)}, values)
+	require.Equal(t, ethcmn.BigToHash(big.NewInt(42)), values[2])
+
+	values, err = testDB.GetStorage(addr2, []ethcmn.Hash{slot1})
+	require.Nil(t, err)
+	require.Equal(t, []ethcmn.Hash{{}}, values)
+
+	// a view reads the state as of its version
+	view, err := testDB.View(0)
+	require.Nil(t, err)
+
+	accs, err = view.GetAccounts([]ethcmn.Address{addr1, addr2})
+	require.Nil(t, err)
+	require.Equal(t, []*ethstate.Account{nil, nil}, accs)
+}
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/types"
+
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethtrie "github.com/ethereum/go-ethereum/trie"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// accountStore implements a KVStore serving the Ethereum accounts, keyed by
+// address, of the accounts of an auth.AccountMapper. The balance of an
+// Ethereum account is the account's amount of the EVM-native denom, its nonce
+// the account's sequence and its code hash that of the types.Account.
+//
+// NOTE: Only Get, Has, Set and Delete are supported as the store is only ever
+// accessed through a CacheKVStore, which writes in ascending key order.
+type accountStore struct {
+	store.KVStore
+
+	ctx      sdk.Context
+	am       auth.AccountMapper
+	evmDenom string
+}
+
+var _ store.KVStore = (*accountStore)(nil)
+
+// Get implements the KVStore interface. It returns the RLP encoded Ethereum
+// account at the given address, or nil if there is no account.
+func (as *accountStore) Get(key []byte) []byte {
+	acc := as.am.GetAccount(as.ctx, key)
+	if acc == nil {
+		return nil
+	}
+
+	seq := acc.GetSequence()
+	if seq < 0 {
+		seq = 0
+	}
+
+	ethAcc := ethstate.Account{
+		Nonce:    uint64(seq),
+		Balance:  types.EVMBalance(acc.GetCoins(), as.evmDenom),
+		CodeHash: emptyCodeHash,
+	}
+
+	if ethermintAcc, ok := acc.(*types.Account); ok && len(ethermintAcc.CodeHash) != 0 {
+		ethAcc.CodeHash = ethermintAcc.CodeHash
+	}
+
+	bz, err := rlp.EncodeToBytes(ethAcc)
+	if err != nil {
+		panic(err)
+	}
+
+	return bz
+}
+
+// Has implements the KVStore interface.
+func (as *accountStore) Has(key []byte) bool {
+	return as.am.GetAccount(as.ctx, key) != nil
+}
+
+// Set implements the KVStore interface. It sets the balance, nonce and code
+// hash of the RLP encoded Ethereum account on the account at the given
+// address, creating the account if it does not exist. The storage root is
+// not retained as contract storage is keyed by address.
+func (as *accountStore) Set(key, value []byte) {
+	var ethAcc ethstate.Account
+	if err := rlp.DecodeBytes(value, &ethAcc); err != nil {
+		panic(err)
+	}
+
+	acc := as.am.GetAccount(as.ctx, key)
+	if acc == nil {
+		acc = as.am.NewAccountWithAddress(as.ctx, key)
+	}
+
+	// the sequence is shared with embedded transactions so it never regresses,
+	// even if a self-destructed contract is re-created
+	if seq := acc.GetSequence(); seq < 0 || uint64(seq) < ethAcc.Nonce {
+		if err := acc.SetSequence(int64(ethAcc.Nonce)); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := acc.SetCoins(types.SetEVMBalance(acc.GetCoins(), as.evmDenom, ethAcc.Balance)); err != nil {
+		panic(err)
+	}
+
+	if ethermintAcc, ok := acc.(*types.Account); ok {
+		ethermintAcc.CodeHash = nil
+		if !bytes.Equal(ethAcc.CodeHash, emptyCodeHash) {
+			ethermintAcc.CodeHash = ethAcc.CodeHash
+		}
+	}
+
+	as.am.SetAccount(as.ctx, acc)
+}
+
+// Delete implements the KVStore interface. As accounts cannot be removed, the
+// balance and code hash of the account at the given address are cleared, e.g.
+// upon self-destruct, while its sequence is retained.
+func (as *accountStore) Delete(key []byte) {
+	acc := as.am.GetAccount(as.ctx, key)
+	if acc == nil {
+		return
+	}
+
+	if err := acc.SetCoins(types.SetEVMBalance(acc.GetCoins(), as.evmDenom, new(big.Int))); err != nil {
+		panic(err)
+	}
+
+	if ethermintAcc, ok := acc.(*types.Account); ok {
+		ethermintAcc.CodeHash = nil
+	}
+
+	as.am.SetAccount(as.ctx, acc)
+}
+
+// NewContextDatabase returns a Database serving the Ethereum state held in an
+// application's multi-store as of a given context, e.g. to execute a
+// transaction: the accounts of an auth.AccountMapper, whose balances are their
+// amounts of the given EVM-native denom (see accountStore), and the contract
+// storage and code held in the stores of the given keys, laid out as in a
+// Database's own stores. Committing a StateDB opened on the Database writes
+// its changes to the context's stores, which are committed along with the
+// multi-store they belong to. As with a view, the Database itself cannot be
+// committed.
+func NewContextDatabase(
+	ctx sdk.Context, am auth.AccountMapper, evmDenom string, storageKey, codeKey sdk.StoreKey,
+) *Database {
+
+	codeStore := ctx.KVStore(codeKey)
+	codeSizeCache, _ := lru.New(codeSizeCacheSize)
+
+	return &Database{
+		accountsCache: store.NewCacheKVStore(&accountStore{ctx: ctx, am: am, evmDenom: evmDenom}),
+		storageCache:  store.NewCacheKVStore(ctx.KVStore(storageKey)),
+		codeDB:        codeStore,
+		ethTrieDB:     ethtrie.NewDatabase(&core.EthereumDB{CodeDB: codeStore}),
+		codeSizeCache: codeSizeCache,
+		isView:        true,
+		viewVersion:   ctx.BlockHeight(),
+	}
+}
@@ -0,0 +1,100 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestContextDatabase(t *testing.T) {
+	cdc := wire.NewCodec()
+	auth.RegisterWire(cdc)
+	types.RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyStorage := sdk.NewKVStoreKey(StorageKey.Name())
+	keyCode := sdk.NewKVStoreKey(CodeKey.Name())
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	for _, key := range []*sdk.KVStoreKey{keyAcc, keyStorage, keyCode} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{Height: 1}, false, log.NewNopLogger())
+	am := auth.NewAccountMapper(cdc, keyAcc, types.ProtoAccount)
+
+	addr := ethcmn.BytesToAddress([]byte("contract"))
+	slot := ethcmn.BytesToHash([]byte("slot"))
+	code := []byte("code")
+
+	// the account holds coins of other denominations which must be retained
+	acc := am.NewAccountWithAddress(ctx, addr.Bytes())
+	require.Nil(t, acc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(types.DenomDefault, 10)}))
+	require.Nil(t, acc.SetSequence(3))
+	am.SetAccount(ctx, acc)
+
+	openState := func() *ethstate.StateDB {
+		stateDB, err := ethstate.New(ethcmn.Hash{}, NewContextDatabase(ctx, am, types.DenomDefault, keyStorage, keyCode))
+		require.Nil(t, err)
+
+		return stateDB
+	}
+
+	stateDB := openState()
+	require.Equal(t, big.NewInt(10), stateDB.GetBalance(addr))
+	require.Equal(t, uint64(3), stateDB.GetNonce(addr))
+
+	stateDB.AddBalance(addr, big.NewInt(90))
+	stateDB.SetNonce(addr, 4)
+	stateDB.SetCode(addr, code)
+	stateDB.SetState(addr, slot, ethcmn.BytesToHash([]byte("value")))
+
+	_, err := stateDB.Commit(true)
+	require.Nil(t, err)
+
+	acc = am.GetAccount(ctx, addr.Bytes())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(types.DenomDefault, 100)}, acc.GetCoins())
+	require.Equal(t, int64(4), acc.GetSequence())
+	require.Equal(t, ethcrypto.Keccak256(code), acc.(*types.Account).CodeHash)
+
+	stateDB = openState()
+	require.Equal(t, code, stateDB.GetCode(addr))
+	require.Equal(t, ethcmn.BytesToHash([]byte("value")), stateDB.GetState(addr, slot))
+
+	// a self-destructed account retains its sequence, which never regresses
+	stateDB.Suicide(addr)
+
+	_, err = stateDB.Commit(true)
+	require.Nil(t, err)
+
+	acc = am.GetAccount(ctx, addr.Bytes())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, acc.GetCoins())
+	require.Equal(t, int64(4), acc.GetSequence())
+	require.Empty(t, acc.(*types.Account).CodeHash)
+
+	stateDB = openState()
+	require.Equal(t, uint64(4), stateDB.GetNonce(addr))
+	require.Empty(t, stateDB.GetCode(addr))
+
+	stateDB.SetNonce(addr, 0)
+
+	_, err = stateDB.Commit(true)
+	require.Nil(t, err)
+	require.Equal(t, int64(4), am.GetAccount(ctx, addr.Bytes()).GetSequence())
+}
@@ -52,13 +52,18 @@ type Database struct {
 	// the ApplyTransaction function, therefore in Ethermint we need to make
 	// sure this commit is invoked somewhere after each block or whatever the
 	// appropriate time for it.
-	codeDB    dbm.DB
+	codeDB    core.KVReadWriter
 	ethTrieDB *ethtrie.Database
 
 	// codeSizeCache contains an LRU cache of a specified capacity to cache
 	// EXTCODESIZE calls.
 	codeSizeCache *lru.Cache
 
+	// isView is true if the Database is a read-only view of the state as of
+	// viewVersion (see View)
+	isView      bool
+	viewVersion int64
+
 	Tracing bool
 }
 
@@ -107,11 +112,23 @@ func (db *Database) LatestVersion() int64 {
 // type which implements the Ethereum state.Trie interface. It us used for
 // storage of accounts. An error is returned if state cannot load for a
 // given version. The account cache is reset if the state is successfully
-// loaded and the version is not the latest.
+// loaded and the version is not the latest. A view always opens the state as
+// of its version.
 //
 // CONTRACT: The root parameter is not interpreted as a state root hash, but as
 // an encoding of an Cosmos SDK IAVL tree version.
 func (db *Database) OpenTrie(root ethcmn.Hash) (ethstate.Trie, error) {
+	if db.isView {
+		return &Trie{
+			store:         db.accountsCache,
+			accountsCache: db.accountsCache,
+			storageCache:  db.storageCache,
+			ethTrieDB:     db.ethTrieDB,
+			empty:         db.viewVersion == 0,
+			root:          rootHashFromVersion(db.viewVersion),
+		}, nil
+	}
+
 	if !isRootEmpty(root) {
 		version := versionFromRootHash(root)
 
@@ -157,7 +174,7 @@ func (db *Database) OpenStorageTrie(addrHash, root ethcmn.Hash) (ethstate.Trie,
 		store:  db.storageCache,
 		prefix: addrHash.Bytes(),
 		empty:  isRootEmpty(root),
-		root:   rootHashFromVersion(db.stateStore.LastCommitID().Version),
+		root:   rootHashFromVersion(db.version()),
 	}, nil
 }
 
@@ -196,8 +213,12 @@ func (db *Database) ContractCodeSize(addrHash, codeHash ethcmn.Hash) (int, error
 }
 
 // Commit commits the underlying Cosmos SDK multi-store returning the commit
-// ID.
+// ID. It panics if the Database is a view.
 func (db *Database) Commit() sdk.CommitID {
+	if db.isView {
+		panic("cannot commit a state view")
+	}
+
 	return db.stateStore.Commit()
 }
 
@@ -209,6 +230,15 @@ func (db *Database) TrieDB() *ethtrie.Database {
 	return db.ethTrieDB
 }
 
+// version returns the version of the state served by the Database.
+func (db *Database) version() int64 {
+	if db.isView {
+		return db.viewVersion
+	}
+
+	return db.stateStore.LastCommitID().Version
+}
+
 // isRootEmpty returns true if a given root hash is empty or false otherwise.
 func isRootEmpty(root ethcmn.Hash) bool {
 	return root == ethcmn.Hash{}
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"sort"
+	"strconv"
+
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+)
+
+// Fields of an account two state dumps may differ in.
+const (
+	DiffFieldAccount = "account"
+	DiffFieldBalance = "balance"
+	DiffFieldNonce   = "nonce"
+	DiffFieldCode    = "code"
+	DiffFieldStorage = "storage"
+)
+
+// DumpDifference defines a field of an account that differs between two state
+// dumps. The key is the storage slot of a storage difference and is empty
+// otherwise. A storage slot or account missing from a dump has an empty value,
+// while an existing account has the value "exists".
+type DumpDifference struct {
+	Address string `json:"address"`
+	Field   string `json:"field"`
+	Key     string `json:"key,omitempty"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+}
+
+// DiffDumps returns the differences between two state dumps, e.g. of the same
+// height on two nodes disagreeing on the application hash, sorted by address,
+// field and key. An account missing from either dump is reported once rather
+// than field by field.
+func DiffDumps(a, b ethstate.Dump) []DumpDifference {
+	diffs := []DumpDifference{}
+
+	for addr, accA := range a.Accounts {
+		accB, ok := b.Accounts[addr]
+		if !ok {
+			diffs = append(diffs, DumpDifference{Address: addr, Field: DiffFieldAccount, A: "exists"})
+			continue
+		}
+
+		diffs = append(diffs, diffAccounts(addr, accA, accB)...)
+	}
+
+	for addr := range b.Accounts {
+		if _, ok := a.Accounts[addr]; !ok {
+			diffs = append(diffs, DumpDifference{Address: addr, Field: DiffFieldAccount, B: "exists"})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Address != diffs[j].Address {
+			return diffs[i].Address < diffs[j].Address
+		}
+
+		if diffs[i].Field != diffs[j].Field {
+			return diffs[i].Field < diffs[j].Field
+		}
+
+		return diffs[i].Key < diffs[j].Key
+	})
+
+	return diffs
+}
+
+// diffAccounts returns the differences between the dumps of an account
+// existing in both dumps.
+func diffAccounts(addr string, a, b ethstate.DumpAccount) []DumpDifference {
+	var diffs []DumpDifference
+
+	add := func(field, key, valueA, valueB string) {
+		if valueA != valueB {
+			diffs = append(diffs, DumpDifference{Address: addr, Field: field, Key: key, A: valueA, B: valueB})
+		}
+	}
+
+	add(DiffFieldBalance, "", a.Balance, b.Balance)
+	add(DiffFieldNonce, "", strconv.FormatUint(a.Nonce, 10), strconv.FormatUint(b.Nonce, 10))
+	add(DiffFieldCode, "", a.Code, b.Code)
+
+	for key, valueA := range a.Storage {
+		add(DiffFieldStorage, key, valueA, b.Storage[key])
+	}
+
+	for key, valueB := range b.Storage {
+		if _, ok := a.Storage[key]; !ok {
+			add(DiffFieldStorage, key, "", valueB)
+		}
+	}
+
+	return diffs
+}
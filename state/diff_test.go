@@ -0,0 +1,41 @@
+package state
+
+import (
+	"testing"
+
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDumps(t *testing.T) {
+	a := ethstate.Dump{Accounts: map[string]ethstate.DumpAccount{
+		"01": {Balance: "100", Nonce: 1, Storage: map[string]string{"0a": "01", "0b": "02"}},
+		"02": {Balance: "5", Code: "6000"},
+		"03": {Balance: "7"},
+	}}
+
+	b := ethstate.Dump{Accounts: map[string]ethstate.DumpAccount{
+		"01": {Balance: "99", Nonce: 1, Storage: map[string]string{"0a": "01", "0b": "03", "0c": "04"}},
+		"02": {Balance: "5", Code: "6000"},
+		"04": {Balance: "7"},
+	}}
+
+	require.Equal(t, []DumpDifference{
+		{Address: "01", Field: DiffFieldBalance, A: "100", B: "99"},
+		{Address: "01", Field: DiffFieldStorage, Key: "0b", A: "02", B: "03"},
+		{Address: "01", Field: DiffFieldStorage, Key: "0c", A: "", B: "04"},
+		{Address: "03", Field: DiffFieldAccount, A: "exists"},
+		{Address: "04", Field: DiffFieldAccount, B: "exists"},
+	}, DiffDumps(a, b))
+
+	require.Empty(t, DiffDumps(a, a))
+
+	// a difference in nonce or code alone is reported
+	b.Accounts = map[string]ethstate.DumpAccount{"02": {Balance: "5", Nonce: 1, Code: "6001"}}
+	a.Accounts = map[string]ethstate.DumpAccount{"02": a.Accounts["02"]}
+
+	require.Equal(t, []DumpDifference{
+		{Address: "02", Field: DiffFieldCode, A: "6000", B: "6001"},
+		{Address: "02", Field: DiffFieldNonce, A: "0", B: "1"},
+	}, DiffDumps(a, b))
+}
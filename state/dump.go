@@ -0,0 +1,78 @@
+package state
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Dump returns the complete state of all accounts, including their balance,
+// nonce, code and storage, at a given version of the underlying multi-store.
+// The result is in the same format as Ethereum's state dump, allowing it to
+// be consumed by existing Ethereum tooling. An error is returned if the state
+// cannot be loaded for the given version or contains a malformed account.
+//
+// NOTE: As with OpenTrie, the multi-store is loaded at the given version and
+// any uncommitted state is discarded.
+func (db *Database) Dump(version int64) (ethstate.Dump, error) {
+	if db.stateStore.LastCommitID().Version != version {
+		if err := db.stateStore.LoadVersion(version); err != nil {
+			return ethstate.Dump{}, err
+		}
+
+		db.accountsCache = nil
+		db.storageCache = nil
+	}
+
+	dump := ethstate.Dump{
+		Root:     fmt.Sprintf("%x", rootHashFromVersion(version)),
+		Accounts: make(map[string]ethstate.DumpAccount),
+	}
+
+	accountsStore := db.stateStore.GetCommitKVStore(AccountsKey)
+	storageStore := db.stateStore.GetCommitKVStore(StorageKey)
+
+	it := accountsStore.Iterator(nil, nil)
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		var data ethstate.Account
+		if err := rlp.DecodeBytes(it.Value(), &data); err != nil {
+			return ethstate.Dump{}, fmt.Errorf("failed to decode account %x: %v", it.Key(), err)
+		}
+
+		code, err := db.ContractCode(ethcmn.Hash{}, ethcmn.BytesToHash(data.CodeHash))
+		if err != nil {
+			return ethstate.Dump{}, err
+		}
+
+		account := ethstate.DumpAccount{
+			Balance:  data.Balance.String(),
+			Nonce:    data.Nonce,
+			Root:     ethcmn.Bytes2Hex(data.Root[:]),
+			CodeHash: ethcmn.Bytes2Hex(data.CodeHash),
+			Code:     ethcmn.Bytes2Hex(code),
+			Storage:  make(map[string]string),
+		}
+
+		// contract storage is prefixed by the hash of the contract's address
+		prefix := ethcrypto.Keccak256(it.Key())
+
+		storageIt := sdk.KVStorePrefixIterator(storageStore, prefix)
+		for ; storageIt.Valid(); storageIt.Next() {
+			key := storageIt.Key()[len(prefix):]
+			account.Storage[ethcmn.Bytes2Hex(key)] = ethcmn.Bytes2Hex(storageIt.Value())
+		}
+
+		storageIt.Close()
+
+		dump.Accounts[ethcmn.Bytes2Hex(it.Key())] = account
+	}
+
+	return dump, nil
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseDump(t *testing.T) {
+	testDB := newDatabase()
+
+	addr1 := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	addr2 := ethcmn.HexToAddress("0x35e8e5dC5FBd97c5b421A80B596C030a2Be2A04D")
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr1, big.NewInt(100))
+	stateDB.SetNonce(addr1, 3)
+	stateDB.SetCode(addr2, code)
+	stateDB.SetState(addr2, ethcmn.BigToHash(big.NewInt(1)), ethcmn.BigToHash(big.NewInt(42)))
+
+	root, err := stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	// modify the state in a later version which must not be part of the dump
+	stateDB, err = ethstate.New(root, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr1, big.NewInt(1))
+
+	_, err = stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	dump, err := testDB.Dump(versionFromRootHash(root))
+	require.Nil(t, err)
+	require.Equal(t, ethcmn.Bytes2Hex(root[:]), dump.Root)
+	require.Len(t, dump.Accounts, 2)
+
+	account1 := dump.Accounts[ethcmn.Bytes2Hex(addr1[:])]
+	require.Equal(t, "100", account1.Balance)
+	require.Equal(t, uint64(3), account1.Nonce)
+	require.Empty(t, account1.Code)
+	require.Empty(t, account1.Storage)
+
+	account2 := dump.Accounts[ethcmn.Bytes2Hex(addr2[:])]
+	require.Equal(t, ethcmn.Bytes2Hex(code), account2.Code)
+	require.Equal(t, ethcmn.Bytes2Hex(ethcrypto.Keccak256(code)), account2.CodeHash)
+	require.Equal(t, map[string]string{
+		ethcmn.Bytes2Hex(ethcmn.BigToHash(big.NewInt(1)).Bytes()): "2a",
+	}, account2.Storage)
+
+	// a version that does not exist cannot be dumped
+	_, err = testDB.Dump(10)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,53 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Query serves ABCI store queries against the Ethereum state. Queries of the
+// form /<store>/key and /<store>/subspace against the account and storage
+// stores are served by the underlying IAVL trees and thus support Merkle
+// proofs and historical heights. Queries of the form /code/key are served
+// from the code database without a proof, as code is addressed by its hash
+// and hence self-verifying.
+func (db *Database) Query(req abci.RequestQuery) abci.ResponseQuery {
+	switch {
+	case strings.HasPrefix(req.Path, "/"+CodeKey.Name()+"/"):
+		return db.queryCode(req)
+
+	case strings.HasPrefix(req.Path, "/"+AccountsKey.Name()+"/"),
+		strings.HasPrefix(req.Path, "/"+StorageKey.Name()+"/"):
+
+		queryable, ok := db.stateStore.(sdk.Queryable)
+		if !ok {
+			return sdk.ErrUnknownRequest("state store doesn't support queries").QueryResult()
+		}
+
+		return queryable.Query(req)
+
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf("unknown state query path: %s", req.Path)).QueryResult()
+	}
+}
+
+func (db *Database) queryCode(req abci.RequestQuery) abci.ResponseQuery {
+	if req.Path != "/"+CodeKey.Name()+"/key" {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("unknown code query path: %s", req.Path)).QueryResult()
+	}
+
+	if req.Prove {
+		return sdk.ErrUnknownRequest("proofs are not supported for code queries").QueryResult()
+	}
+
+	return abci.ResponseQuery{
+		Code:   uint32(sdk.ABCICodeOK),
+		Key:    req.Data,
+		Value:  db.codeDB.Get(req.Data),
+		Height: db.LatestVersion(),
+	}
+}
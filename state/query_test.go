@@ -0,0 +1,61 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestDatabaseQuery(t *testing.T) {
+	testDB := newDatabase()
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr, big.NewInt(100))
+	stateDB.SetCode(addr, code)
+
+	_, err = stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	codeHash := ethcrypto.Keccak256(code)
+
+	testCases := []struct {
+		path      string
+		data      []byte
+		prove     bool
+		expectOK  bool
+		expectVal bool
+	}{
+		{"/account/key", addr.Bytes(), false, true, true},
+		{"/account/key", addr.Bytes(), true, true, true},
+		{"/account/key", ethcmn.Address{}.Bytes(), false, true, false},
+		{"/code/key", codeHash, false, true, true},
+		{"/code/key", codeHash, true, false, false},
+		{"/code/subspace", codeHash, false, false, false},
+		{"/acc/key", addr.Bytes(), false, false, false},
+	}
+
+	for i, tc := range testCases {
+		res := testDB.Query(abci.RequestQuery{Path: tc.path, Data: tc.data, Prove: tc.prove})
+
+		if !tc.expectOK {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expectVal, len(res.Value) != 0, fmt.Sprintf("unexpected value: test case #%d", i))
+		require.Equal(t, tc.prove, len(res.Proof) != 0, fmt.Sprintf("unexpected proof: test case #%d", i))
+	}
+}
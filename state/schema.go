@@ -0,0 +1,189 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	// InitialSchemaVersion is the version of the store layout prior to any
+	// migration being applied.
+	InitialSchemaVersion int64 = 1
+)
+
+var (
+	// schemaVersionKey is the key under which the schema version is persisted
+	// in the state database. It cannot collide with any key written by the
+	// Cosmos SDK multi-store as those are all prefixed by "s/".
+	schemaVersionKey = []byte("ethermint/schemaVersion")
+
+	storeLayouts []StoreLayout
+	migrations   []Migration
+)
+
+// StoreLayout describes how a single store of the Ethermint state is laid out
+// in the underlying databases.
+type StoreLayout struct {
+	// Name is the name of the store
+	Name string
+
+	// Database is the name of the database the store is persisted in
+	Database string
+
+	// Key describes how the keys of the store are constructed
+	Key string
+
+	// Value describes how the values of the store are encoded
+	Value string
+}
+
+// Migration defines a migration of the store layout from the previous schema
+// version to the given version.
+type Migration struct {
+	// Version is the schema version after the migration is applied
+	Version int64
+
+	// Description is a human readable summary of the layout change
+	Description string
+
+	// Migrate performs the migration on the given state and code databases
+	Migrate func(stateDB, codeDB dbm.DB) error
+}
+
+func init() {
+	RegisterStoreLayout(StoreLayout{
+		Name:     AccountsKey.Name(),
+		Database: "state",
+		Key:      "address (20 bytes)",
+		Value:    "RLP encoded Ethereum account (nonce, balance, storage root, code hash)",
+	})
+	RegisterStoreLayout(StoreLayout{
+		Name:     StorageKey.Name(),
+		Database: "state",
+		Key:      "keccak256(address) (32 bytes) || storage key (32 bytes)",
+		Value:    "RLP encoded storage value",
+	})
+	RegisterStoreLayout(StoreLayout{
+		Name:     CodeKey.Name(),
+		Database: "code",
+		Key:      "keccak256(code) (32 bytes)",
+		Value:    "contract byte code",
+	})
+}
+
+// RegisterStoreLayout registers the layout of a store with the schema
+// registry. It panics if a store with the same name is already registered.
+func RegisterStoreLayout(layout StoreLayout) {
+	for _, l := range storeLayouts {
+		if l.Name == layout.Name {
+			panic(fmt.Sprintf("store layout %s already registered", layout.Name))
+		}
+	}
+
+	storeLayouts = append(storeLayouts, layout)
+}
+
+// StoreLayouts returns all the registered store layouts sorted by name.
+func StoreLayouts() []StoreLayout {
+	layouts := make([]StoreLayout, len(storeLayouts))
+	copy(layouts, storeLayouts)
+
+	sort.Slice(layouts, func(i, j int) bool { return layouts[i].Name < layouts[j].Name })
+	return layouts
+}
+
+// RegisterMigration registers a migration with the schema registry. Migrations
+// must be registered in order, each one upgrading the latest schema version by
+// exactly one, otherwise it panics.
+func RegisterMigration(migration Migration) {
+	if migration.Version != LatestSchemaVersion()+1 {
+		panic(fmt.Sprintf(
+			"invalid migration version %d; expected %d", migration.Version, LatestSchemaVersion()+1,
+		))
+	}
+
+	migrations = append(migrations, migration)
+}
+
+// LatestSchemaVersion returns the schema version resulting from applying all
+// the registered migrations.
+func LatestSchemaVersion() int64 {
+	return InitialSchemaVersion + int64(len(migrations))
+}
+
+// SchemaVersion returns the schema version persisted in the given state
+// database. A database without a persisted version is assumed to be of the
+// initial schema version.
+func SchemaVersion(stateDB dbm.DB) int64 {
+	bz := stateDB.Get(schemaVersionKey)
+	if bz == nil {
+		return InitialSchemaVersion
+	}
+
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// setSchemaVersion persists the given schema version in the state database.
+func setSchemaVersion(stateDB dbm.DB, version int64) {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(version))
+
+	stateDB.SetSync(schemaVersionKey, bz)
+}
+
+// Migrate applies, in order, all the registered migrations required to bring
+// the given databases from their current schema version to the target
+// version. The schema version is persisted after each successful migration so
+// that a failed migration can be resumed. An error is returned if the target
+// version is unknown or older than the current version.
+func Migrate(stateDB, codeDB dbm.DB, target int64) error {
+	current := SchemaVersion(stateDB)
+
+	switch {
+	case target > LatestSchemaVersion():
+		return fmt.Errorf("unknown schema version %d; latest is %d", target, LatestSchemaVersion())
+
+	case target < current:
+		return fmt.Errorf("cannot downgrade schema version from %d to %d", current, target)
+	}
+
+	for _, migration := range migrations[current-InitialSchemaVersion : target-InitialSchemaVersion] {
+		if err := migration.Migrate(stateDB, codeDB); err != nil {
+			return fmt.Errorf("failed to migrate to schema version %d: %v", migration.Version, err)
+		}
+
+		setSchemaVersion(stateDB, migration.Version)
+	}
+
+	return nil
+}
+
+// WriteSchemaDoc writes a markdown document describing the registered store
+// layouts and migrations to the given writer.
+func WriteSchemaDoc(w io.Writer) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# Store Layout (schema version %d)\n\n", LatestSchemaVersion())
+	fmt.Fprintln(&buf, "| Store | Database | Key | Value |")
+	fmt.Fprintln(&buf, "|-------|----------|-----|-------|")
+
+	for _, l := range StoreLayouts() {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n", l.Name, l.Database, l.Key, l.Value)
+	}
+
+	if len(migrations) != 0 {
+		fmt.Fprint(&buf, "\n## Migrations\n\n")
+
+		for _, m := range migrations {
+			fmt.Fprintf(&buf, "- v%d: %s\n", m.Version, m.Description)
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,108 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// withTestMigrations registers the given number of migrations, each recording
+// its version in the given slice, for the duration of the test function.
+func withTestMigrations(n int, applied *[]int64, fn func()) {
+	registered := migrations
+	defer func() { migrations = registered }()
+
+	for i := 0; i < n; i++ {
+		version := LatestSchemaVersion() + 1
+
+		RegisterMigration(Migration{
+			Version:     version,
+			Description: fmt.Sprintf("test migration %d", version),
+			Migrate: func(stateDB, _ dbm.DB) error {
+				if stateDB.Has([]byte("fail")) {
+					return errors.New("migration failed")
+				}
+
+				*applied = append(*applied, version)
+				return nil
+			},
+		})
+	}
+
+	fn()
+}
+
+func TestMigrate(t *testing.T) {
+	var applied []int64
+
+	withTestMigrations(3, &applied, func() {
+		stateDB, codeDB := dbm.NewMemDB(), dbm.NewMemDB()
+		require.Equal(t, InitialSchemaVersion, SchemaVersion(stateDB))
+		require.Equal(t, int64(4), LatestSchemaVersion())
+
+		require.Nil(t, Migrate(stateDB, codeDB, 2))
+		require.Equal(t, []int64{2}, applied)
+		require.Equal(t, int64(2), SchemaVersion(stateDB))
+
+		require.Nil(t, Migrate(stateDB, codeDB, 4))
+		require.Equal(t, []int64{2, 3, 4}, applied)
+		require.Equal(t, int64(4), SchemaVersion(stateDB))
+
+		// migrating to the current version is a no-op
+		require.Nil(t, Migrate(stateDB, codeDB, 4))
+		require.Equal(t, []int64{2, 3, 4}, applied)
+
+		require.NotNil(t, Migrate(stateDB, codeDB, 5))
+		require.NotNil(t, Migrate(stateDB, codeDB, 3))
+	})
+}
+
+func TestMigrateFailure(t *testing.T) {
+	var applied []int64
+
+	withTestMigrations(2, &applied, func() {
+		stateDB, codeDB := dbm.NewMemDB(), dbm.NewMemDB()
+		stateDB.Set([]byte("fail"), []byte{1})
+
+		require.NotNil(t, Migrate(stateDB, codeDB, 3))
+		require.Equal(t, InitialSchemaVersion, SchemaVersion(stateDB))
+
+		// a failed migration can be resumed once the cause is resolved
+		stateDB.Delete([]byte("fail"))
+		require.Nil(t, Migrate(stateDB, codeDB, 3))
+		require.Equal(t, []int64{2, 3}, applied)
+	})
+}
+
+func TestRegisterMigrationInvalidVersion(t *testing.T) {
+	require.Panics(t, func() {
+		RegisterMigration(Migration{Version: LatestSchemaVersion() + 2})
+	})
+}
+
+func TestRegisterStoreLayoutDuplicate(t *testing.T) {
+	require.Panics(t, func() {
+		RegisterStoreLayout(StoreLayout{Name: AccountsKey.Name()})
+	})
+}
+
+func TestWriteSchemaDoc(t *testing.T) {
+	var applied []int64
+
+	withTestMigrations(1, &applied, func() {
+		var buf bytes.Buffer
+		require.Nil(t, WriteSchemaDoc(&buf))
+
+		doc := buf.String()
+		require.Contains(t, doc, "# Store Layout (schema version 2)")
+		require.Contains(t, doc, "- v2: test migration 2")
+
+		for _, name := range []string{AccountsKey.Name(), StorageKey.Name(), CodeKey.Name()} {
+			require.Contains(t, doc, fmt.Sprintf("| %s |", name))
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	amino "github.com/tendermint/go-amino"
+	"github.com/tendermint/iavl"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+var (
+	emptyCodeHash = ethcrypto.Keccak256(nil)
+
+	// provenStoreKeys are the keys of the stores whose snapshot entries carry
+	// a Merkle proof, indexed by name.
+	provenStoreKeys = map[string]*sdk.KVStoreKey{
+		AccountsKey.Name(): AccountsKey,
+		StorageKey.Name():  StorageKey,
+	}
+)
+
+type (
+	// SnapshotHeader describes a snapshot of the Ethereum state at a given
+	// height. The roots are the root hashes of the account and storage stores
+	// at that height, against which the proof of every entry is verified.
+	SnapshotHeader struct {
+		Height int64             `json:"height"`
+		Roots  map[string][]byte `json:"roots"`
+	}
+
+	// SnapshotEntry defines a single key-value pair of a snapshot. Entries of
+	// the account and storage stores carry a Merkle proof against the
+	// store's root. Entries of the code store are keyed by the hash of the
+	// code and are hence self-verifying.
+	SnapshotEntry struct {
+		Store string `json:"store"`
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+		Proof []byte `json:"proof,omitempty"`
+	}
+
+	// SnapshotWriter defines the interface a snapshot is exported to. The
+	// header is always written prior to any entry.
+	SnapshotWriter interface {
+		WriteHeader(header SnapshotHeader) error
+		WriteEntry(entry SnapshotEntry) error
+	}
+
+	// SnapshotImporter implements the SnapshotWriter interface on top of an
+	// empty Database. It verifies every entry against the snapshot's header
+	// and writes it to the Database. The imported state is persisted upon
+	// Commit.
+	//
+	// CONTRACT: The roots of the header must be checked against a trusted
+	// source (e.g. the application hash of the block at the snapshot's
+	// height) as the entries are only as trustworthy as the header is.
+	SnapshotImporter struct {
+		db      *Database
+		header  *SnapshotHeader
+		entries int
+	}
+)
+
+// ExportSnapshot writes the complete Ethereum state at a given version of the
+// underlying multi-store to a SnapshotWriter. Accounts are written in key
+// order, each followed by the code it references, if not yet written, and its
+// storage. An error is returned if the state cannot be loaded for the given
+// version or if the writer fails.
+//
+// NOTE: As with Dump, the multi-store is loaded at the given version and any
+// uncommitted state is discarded.
+func (db *Database) ExportSnapshot(version int64, w SnapshotWriter) error {
+	if db.stateStore.LastCommitID().Version != version {
+		if err := db.stateStore.LoadVersion(version); err != nil {
+			return err
+		}
+
+		db.accountsCache = nil
+		db.storageCache = nil
+	}
+
+	accountsStore := db.stateStore.GetCommitKVStore(AccountsKey)
+	storageStore := db.stateStore.GetCommitKVStore(StorageKey)
+
+	header := SnapshotHeader{
+		Height: version,
+		Roots: map[string][]byte{
+			AccountsKey.Name(): accountsStore.LastCommitID().Hash,
+			StorageKey.Name():  storageStore.LastCommitID().Hash,
+		},
+	}
+
+	if err := w.WriteHeader(header); err != nil {
+		return err
+	}
+
+	writtenCode := make(map[ethcmn.Hash]bool)
+
+	it := accountsStore.Iterator(nil, nil)
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		if err := db.writeProvenEntry(w, AccountsKey.Name(), version, it.Key(), it.Value()); err != nil {
+			return err
+		}
+
+		var data ethstate.Account
+		if err := rlp.DecodeBytes(it.Value(), &data); err != nil {
+			return fmt.Errorf("failed to decode account %x: %v", it.Key(), err)
+		}
+
+		codeHash := ethcmn.BytesToHash(data.CodeHash)
+		if !bytes.Equal(data.CodeHash, emptyCodeHash) && !writtenCode[codeHash] {
+			entry := SnapshotEntry{Store: CodeKey.Name(), Key: codeHash.Bytes(), Value: db.codeDB.Get(codeHash[:])}
+			if err := w.WriteEntry(entry); err != nil {
+				return err
+			}
+
+			writtenCode[codeHash] = true
+		}
+
+		// contract storage is prefixed by the hash of the contract's address
+		storageIt := sdk.KVStorePrefixIterator(storageStore, ethcrypto.Keccak256(it.Key()))
+
+		for ; storageIt.Valid(); storageIt.Next() {
+			err := db.writeProvenEntry(w, StorageKey.Name(), version, storageIt.Key(), storageIt.Value())
+			if err != nil {
+				storageIt.Close()
+				return err
+			}
+		}
+
+		storageIt.Close()
+	}
+
+	return nil
+}
+
+// writeProvenEntry writes an entry of the account or storage store along with
+// a Merkle proof of its value at the given version.
+func (db *Database) writeProvenEntry(w SnapshotWriter, storeName string, version int64, key, value []byte) error {
+	res := db.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", storeName),
+		Data:   key,
+		Height: version,
+		Prove:  true,
+	})
+
+	if !res.IsOK() || len(res.Proof) == 0 {
+		return fmt.Errorf("failed to prove %s key %x: %s", storeName, key, res.Log)
+	}
+
+	return w.WriteEntry(SnapshotEntry{Store: storeName, Key: key, Value: value, Proof: res.Proof})
+}
+
+// NewSnapshotImporter returns a reference to a new SnapshotImporter writing to
+// the given Database. An error is returned if the Database is not empty.
+func NewSnapshotImporter(db *Database) (*SnapshotImporter, error) {
+	if version := db.LatestVersion(); version != 0 {
+		return nil, fmt.Errorf("cannot import a snapshot into a database at version %d", version)
+	}
+
+	return &SnapshotImporter{db: db}, nil
+}
+
+// WriteHeader implements the SnapshotWriter interface.
+func (si *SnapshotImporter) WriteHeader(header SnapshotHeader) error {
+	if si.header != nil {
+		return fmt.Errorf("snapshot header already written")
+	}
+
+	si.header = &header
+	return nil
+}
+
+// WriteEntry implements the SnapshotWriter interface. An error is returned if
+// the entry fails verification.
+func (si *SnapshotImporter) WriteEntry(entry SnapshotEntry) error {
+	if si.header == nil {
+		return fmt.Errorf("snapshot entry written prior to the header")
+	}
+
+	switch entry.Store {
+	case CodeKey.Name():
+		if !bytes.Equal(ethcrypto.Keccak256(entry.Value), entry.Key) {
+			return fmt.Errorf("code does not match its hash %x", entry.Key)
+		}
+
+		si.db.codeDB.Set(entry.Key, entry.Value)
+
+	case AccountsKey.Name(), StorageKey.Name():
+		if err := verifyEntryProof(si.header.Roots[entry.Store], entry); err != nil {
+			return fmt.Errorf("invalid proof for %s key %x: %v", entry.Store, entry.Key, err)
+		}
+
+		si.db.stateStore.GetCommitKVStore(provenStoreKeys[entry.Store]).Set(entry.Key, entry.Value)
+
+	default:
+		return fmt.Errorf("unknown snapshot store %s", entry.Store)
+	}
+
+	si.entries++
+	return nil
+}
+
+// Commit commits the imported state to the Database, returning the commit ID
+// and the number of entries imported.
+func (si *SnapshotImporter) Commit() (sdk.CommitID, int, error) {
+	if si.header == nil {
+		return sdk.CommitID{}, 0, fmt.Errorf("no snapshot imported")
+	}
+
+	return si.db.Commit(), si.entries, nil
+}
+
+func verifyEntryProof(root []byte, entry SnapshotEntry) error {
+	var proof iavl.RangeProof
+	if err := amino.NewCodec().UnmarshalBinary(entry.Proof, &proof); err != nil {
+		return err
+	}
+
+	if err := proof.Verify(root); err != nil {
+		return err
+	}
+
+	return proof.VerifyItem(entry.Key, entry.Value)
+}
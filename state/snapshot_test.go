@@ -0,0 +1,159 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotRecorder implements the SnapshotWriter interface by recording the
+// written header and entries.
+type snapshotRecorder struct {
+	header  SnapshotHeader
+	entries []SnapshotEntry
+}
+
+func (sr *snapshotRecorder) WriteHeader(header SnapshotHeader) error {
+	sr.header = header
+	return nil
+}
+
+func (sr *snapshotRecorder) WriteEntry(entry SnapshotEntry) error {
+	sr.entries = append(sr.entries, entry)
+	return nil
+}
+
+func (sr *snapshotRecorder) replay(w SnapshotWriter) error {
+	if err := w.WriteHeader(sr.header); err != nil {
+		return err
+	}
+
+	for _, entry := range sr.entries {
+		if err := w.WriteEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newSnapshotSource(t *testing.T) (*Database, int64) {
+	testDB := newDatabase()
+
+	addr1 := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	addr2 := ethcmn.HexToAddress("0x35e8e5dC5FBd97c5b421A80B596C030a2Be2A04D")
+	addr3 := ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr1, big.NewInt(100))
+	stateDB.SetNonce(addr1, 3)
+	stateDB.SetCode(addr2, code)
+	stateDB.SetState(addr2, ethcmn.BigToHash(big.NewInt(1)), ethcmn.BigToHash(big.NewInt(42)))
+	stateDB.SetState(addr2, ethcmn.BigToHash(big.NewInt(2)), ethcmn.BigToHash(big.NewInt(43)))
+	stateDB.SetCode(addr3, code)
+
+	root, err := stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	// modify the state in a later version which must not be part of the
+	// snapshot
+	stateDB, err = ethstate.New(root, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr1, big.NewInt(1))
+
+	_, err = stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	return testDB, versionFromRootHash(root)
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+	source, version := newSnapshotSource(t)
+
+	var recorder snapshotRecorder
+	require.Nil(t, source.ExportSnapshot(version, &recorder))
+	require.Equal(t, version, recorder.header.Height)
+
+	// three accounts, a single shared code and two storage slots
+	require.Len(t, recorder.entries, 6)
+
+	target := newDatabase()
+
+	importer, err := NewSnapshotImporter(target)
+	require.Nil(t, err)
+	require.Nil(t, recorder.replay(importer))
+
+	commitID, entries, err := importer.Commit()
+	require.Nil(t, err)
+	require.Equal(t, int64(1), commitID.Version)
+	require.Equal(t, len(recorder.entries), entries)
+
+	expected, err := source.Dump(version)
+	require.Nil(t, err)
+
+	actual, err := target.Dump(commitID.Version)
+	require.Nil(t, err)
+	require.Equal(t, expected.Accounts, actual.Accounts)
+
+	// a snapshot can only be imported into an empty database
+	_, err = NewSnapshotImporter(target)
+	require.NotNil(t, err)
+}
+
+func TestSnapshotImportInvalid(t *testing.T) {
+	source, version := newSnapshotSource(t)
+
+	var recorder snapshotRecorder
+	require.Nil(t, source.ExportSnapshot(version, &recorder))
+
+	testCases := []func(sr *snapshotRecorder){
+		// tampered account value
+		func(sr *snapshotRecorder) { sr.entries[0].Value = append([]byte{}, sr.entries[1].Value...) },
+		// tampered store root
+		func(sr *snapshotRecorder) { sr.header.Roots[AccountsKey.Name()] = make([]byte, 32) },
+		// proof of another key
+		func(sr *snapshotRecorder) { sr.entries[0].Proof = sr.entries[len(sr.entries)-1].Proof },
+		// missing proof
+		func(sr *snapshotRecorder) { sr.entries[0].Proof = nil },
+		// code not matching its hash
+		func(sr *snapshotRecorder) {
+			for i := range sr.entries {
+				if sr.entries[i].Store == CodeKey.Name() {
+					sr.entries[i].Value = []byte{0x00}
+				}
+			}
+		},
+		// unknown store
+		func(sr *snapshotRecorder) { sr.entries[0].Store = "unknown" },
+	}
+
+	for i, tamper := range testCases {
+		tampered := snapshotRecorder{
+			header:  SnapshotHeader{Height: recorder.header.Height, Roots: make(map[string][]byte)},
+			entries: append([]SnapshotEntry{}, recorder.entries...),
+		}
+
+		for name, root := range recorder.header.Roots {
+			tampered.header.Roots[name] = root
+		}
+
+		tamper(&tampered)
+
+		importer, err := NewSnapshotImporter(newDatabase())
+		require.Nil(t, err)
+		require.NotNil(t, tampered.replay(importer), fmt.Sprintf("expected error: test case #%d", i))
+	}
+
+	// a version that does not exist cannot be exported
+	require.NotNil(t, source.ExportSnapshot(version+10, &snapshotRecorder{}))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 
 	"github.com/cosmos/cosmos-sdk/store"
+	"github.com/cosmos/ethermint/types"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethdb "github.com/ethereum/go-ethereum/ethdb"
@@ -130,8 +131,9 @@ func (t *Trie) Commit(_ ethtrie.LeafCallback) (ethcmn.Hash, error) {
 			t.storageCache = nil
 		}
 
-		// persist the mappings of codeHash => code
-		for _, n := range t.ethTrieDB.Nodes() {
+		// persist the mappings of codeHash => code, sorted by code hash as the
+		// nodes are listed in map order
+		for _, n := range types.SortHashes(t.ethTrieDB.Nodes()) {
 			if err := t.ethTrieDB.Commit(n, false); err != nil {
 				return ethcmn.Hash{}, err
 			}
@@ -194,6 +194,47 @@ func TestTrieCommit(t *testing.T) {
 	}
 }
 
+// recordingDB records the keys set in a database in order.
+type recordingDB struct {
+	*dbm.MemDB
+
+	keys [][]byte
+}
+
+func (rdb *recordingDB) Set(key, value []byte) {
+	rdb.keys = append(rdb.keys, key)
+	rdb.MemDB.Set(key, value)
+}
+
+func TestTrieCommitCodeDeterminism(t *testing.T) {
+	var expected [][]byte
+	for i := 1; i <= 16; i++ {
+		expected = append(expected, ethcmn.BytesToHash([]byte{byte(i)}).Bytes())
+	}
+
+	// the code of a commit is inserted in map order, yet always persisted in
+	// the order of its hashes
+	for run := 0; run < 50; run++ {
+		codeDB := &recordingDB{MemDB: dbm.NewMemDB()}
+
+		testDB, err := NewDatabase(dbm.NewMemDB(), codeDB)
+		require.Nil(t, err)
+
+		testTrie, err := testDB.OpenTrie(rootHashFromVersion(0))
+		require.Nil(t, err)
+
+		for _, i := range rand.Perm(len(expected)) {
+			testDB.TrieDB().Insert(ethcmn.BytesToHash(expected[i]), []byte{byte(i)})
+		}
+
+		require.Nil(t, testTrie.TryUpdate([]byte("foo"), []byte("bar")))
+
+		_, err = testTrie.Commit(nil)
+		require.Nil(t, err)
+		require.Equal(t, expected, codeDB.keys, fmt.Sprintf("unexpected order: run #%d", run))
+	}
+}
+
 func TestTrieHash(t *testing.T) {
 	testTrie := newTestTrie()
 	testPrefixTrie := newTestPrefixTrie()
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// versionedStore implements a read-only KVStore over a store of the state
+// multi-store as of a given version. Reads are served by historical store
+// queries so that the multi-store itself is never loaded at the version.
+//
+// NOTE: Only Get and Has are supported. Iteration and writes panic as a view
+// is only ever read through a CacheKVStore and never committed.
+type versionedStore struct {
+	store.KVStore
+
+	db      *Database
+	name    string
+	version int64
+}
+
+var _ store.KVStore = (*versionedStore)(nil)
+
+// Get implements the KVStore interface.
+func (vs *versionedStore) Get(key []byte) []byte {
+	if vs.version == 0 {
+		return nil
+	}
+
+	res := vs.db.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", vs.name),
+		Data:   key,
+		Height: vs.version,
+	})
+
+	return res.Value
+}
+
+// Has implements the KVStore interface.
+func (vs *versionedStore) Has(key []byte) bool {
+	return vs.Get(key) != nil
+}
+
+// Set implements the KVStore interface.
+func (vs *versionedStore) Set(key, value []byte) {
+	panic("cannot write to a state view")
+}
+
+// Delete implements the KVStore interface.
+func (vs *versionedStore) Delete(key []byte) {
+	panic("cannot write to a state view")
+}
+
+// Iterator implements the KVStore interface.
+func (vs *versionedStore) Iterator(start, end []byte) sdk.Iterator {
+	panic("cannot iterate a state view")
+}
+
+// ReverseIterator implements the KVStore interface.
+func (vs *versionedStore) ReverseIterator(start, end []byte) sdk.Iterator {
+	panic("cannot iterate a state view")
+}
+
+// View returns a Database serving the Ethereum state as of a given version of
+// the underlying multi-store, e.g. to re-execute transactions on top of past
+// state. Unlike Dump, the multi-store is not loaded at the version, so the
+// Database remains usable and any uncommitted state is retained. The state of
+// the view may be modified in memory but the view cannot be committed. A
+// version of zero refers to the empty state prior to the first commit. An
+// error is returned if the version does not exist.
+func (db *Database) View(version int64) (*Database, error) {
+	if version < 0 || version > db.LatestVersion() {
+		return nil, fmt.Errorf("state version %d does not exist", version)
+	}
+
+	if version > 0 {
+		// any key will do as the response only reflects whether the version
+		// exists
+		res := db.Query(abci.RequestQuery{Path: fmt.Sprintf("/%s/key", AccountsKey.Name()), Data: []byte{0}, Height: version})
+		if res.Log != "" {
+			return nil, fmt.Errorf("state version %d does not exist: %s", version, res.Log)
+		}
+	}
+
+	view := &Database{
+		stateStore:    db.stateStore,
+		codeDB:        db.codeDB,
+		ethTrieDB:     db.ethTrieDB,
+		codeSizeCache: db.codeSizeCache,
+		viewVersion:   version,
+		isView:        true,
+	}
+
+	view.accountsCache = store.NewCacheKVStore(&versionedStore{
+		KVStore: db.stateStore.GetCommitKVStore(AccountsKey), db: db, name: AccountsKey.Name(), version: version,
+	})
+	view.storageCache = store.NewCacheKVStore(&versionedStore{
+		KVStore: db.stateStore.GetCommitKVStore(StorageKey), db: db, name: StorageKey.Name(), version: version,
+	})
+
+	return view, nil
+}
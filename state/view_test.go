@@ -0,0 +1,81 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseView(t *testing.T) {
+	testDB := newDatabase()
+
+	addr := ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0")
+	slot := ethcmn.BigToHash(big.NewInt(1))
+
+	stateDB, err := ethstate.New(ethcmn.Hash{}, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr, big.NewInt(100))
+	stateDB.SetState(addr, slot, ethcmn.BigToHash(big.NewInt(42)))
+
+	root, err := stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	// modify the state in a later version which must not be visible in a view
+	// of the first version
+	stateDB, err = ethstate.New(root, testDB)
+	require.Nil(t, err)
+
+	stateDB.AddBalance(addr, big.NewInt(1))
+	stateDB.SetState(addr, slot, ethcmn.BigToHash(big.NewInt(43)))
+
+	_, err = stateDB.Commit(false)
+	require.Nil(t, err)
+	testDB.Commit()
+
+	testCases := []struct {
+		version         int64
+		expectedBalance int64
+		expectedState   int64
+		expectErr       bool
+	}{
+		{0, 0, 0, false},
+		{1, 100, 42, false},
+		{2, 101, 43, false},
+		{3, 0, 0, true},
+		{-1, 0, 0, true},
+	}
+
+	for i, tc := range testCases {
+		view, err := testDB.View(tc.version)
+
+		if tc.expectErr {
+			require.NotNil(t, err, "expected error: test case #%d", i)
+			continue
+		}
+
+		require.Nil(t, err, "unexpected error: test case #%d", i)
+
+		viewStateDB, err := ethstate.New(ethcmn.Hash{}, view)
+		require.Nil(t, err, "unexpected error: test case #%d", i)
+		require.Equal(t, big.NewInt(tc.expectedBalance), viewStateDB.GetBalance(addr), "test case #%d", i)
+		require.Equal(t, ethcmn.BigToHash(big.NewInt(tc.expectedState)), viewStateDB.GetState(addr, slot), "test case #%d", i)
+
+		// modifications of a view are not persisted
+		viewStateDB.AddBalance(addr, big.NewInt(1000))
+		viewStateDB.IntermediateRoot(false)
+
+		require.Panics(t, func() { view.Commit() }, "test case #%d", i)
+	}
+
+	// the database itself is neither loaded at an earlier version nor modified
+	require.Equal(t, int64(2), testDB.LatestVersion())
+
+	stateDB, err = ethstate.New(rootHashFromVersion(2), testDB)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(101), stateDB.GetBalance(addr))
+}
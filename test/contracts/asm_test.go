@@ -0,0 +1,128 @@
+package contracts
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+// assemble assembles an annotated EVM listing as found under testdata. The
+// listing is made of whitespace separated instructions, where:
+//
+// "name:" defines a label and emits a JUMPDEST.
+// "PUSHn 0x.." pushes an n byte hex literal.
+// "PUSH @name" pushes the offset of a label as two bytes.
+// "PUSH $init", "PUSH $runtime" and "PUSH $code" push the size of the init
+// code, of the runtime code and of both as two bytes.
+// ".runtime" starts the runtime code, whose label offsets are relative to it.
+//
+// Everything following a ";" on a line is a comment.
+func assemble(listing string) ([]byte, error) {
+	var tokens []string
+	for _, line := range strings.Split(listing, "\n") {
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+
+		tokens = append(tokens, strings.Fields(line)...)
+	}
+
+	// the first pass resolves labels and sizes, the second emits the code
+	sizes := make(map[string]int)
+	labels := make(map[string]int)
+
+	var code []byte
+
+	for pass := 0; pass < 2; pass++ {
+		code = code[:0]
+		base := 0
+
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+
+			switch {
+			case token == ".runtime":
+				sizes["$init"] = len(code)
+				base = len(code)
+
+			case strings.HasSuffix(token, ":"):
+				labels[strings.TrimSuffix(token, ":")] = len(code) - base
+				code = append(code, byte(ethvm.JUMPDEST))
+
+			case token == "PUSH":
+				i++
+				if i == len(tokens) {
+					return nil, fmt.Errorf("missing operand of PUSH")
+				}
+
+				operand := tokens[i]
+
+				value, ok := labels[strings.TrimPrefix(operand, "@")]
+				if strings.HasPrefix(operand, "$") {
+					value, ok = sizes[operand]
+				}
+
+				if !ok && pass == 1 {
+					return nil, fmt.Errorf("undefined operand %s", operand)
+				}
+
+				code = append(code, byte(ethvm.PUSH2), byte(value>>8), byte(value))
+
+			default:
+				op := ethvm.StringToOp(token)
+				if op.String() != token {
+					return nil, fmt.Errorf("unknown instruction %s", token)
+				}
+
+				code = append(code, byte(op))
+
+				if op.IsPush() {
+					i++
+					if i == len(tokens) {
+						return nil, fmt.Errorf("missing operand of %s", token)
+					}
+
+					n := int(op-ethvm.PUSH1) + 1
+
+					value, ok := new(big.Int).SetString(tokens[i], 0)
+					if !ok || value.Sign() == -1 || len(value.Bytes()) > n {
+						return nil, fmt.Errorf("invalid operand %s of %s", tokens[i], token)
+					}
+
+					code = append(code, ethcmn.LeftPadBytes(value.Bytes(), n)...)
+				}
+			}
+		}
+
+		sizes["$runtime"] = len(code) - sizes["$init"]
+		sizes["$code"] = len(code)
+	}
+
+	return code, nil
+}
+
+func TestListings(t *testing.T) {
+	testCases := []struct {
+		listing  string
+		expected string
+	}{
+		{"testdata/erc20.asm", ERC20Bin},
+		{"testdata/erc721.asm", ERC721Bin},
+	}
+
+	for i, tc := range testCases {
+		listing, err := ioutil.ReadFile(tc.listing)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		code, err := assemble(string(listing))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expected, ethcmn.Bytes2Hex(code), fmt.Sprintf("unexpected code: test case #%d", i))
+	}
+}
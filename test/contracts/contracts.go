@@ -0,0 +1,86 @@
+// Package contracts provides reference ERC20 and ERC721 token contracts,
+// along with helpers to build calls to them, for use in end-to-end tests and
+// as example code.
+//
+// The contracts are minimal, hand-assembled implementations of the standards'
+// functions and events, so that they can be verified without a Solidity
+// compiler. Their annotated listings are found under testdata and are
+// assembled by the package's tests, which fail should the listings and the
+// embedded bytecode diverge.
+package contracts
+
+import (
+	"math/big"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// ERC20Bin is the hex encoded creation code of the reference ERC20 token.
+	// The constructor takes the total supply as its single argument and
+	// credits it to the creator.
+	ERC20Bin = "60206101f36040396040516000556040516001336000526020526040600020553360007fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206040a361019b61005860003961019b6000f334610069577c010000000000000000000000000000000000000000000000000000000060003504806318160ddd1461006e57806370a0823114610083578063a9059cbb1461009e578063095ea7b3146100ad578063dd62ed3e1461009057806323b872dd146100f3575b600080fd5b60005b545b60005260206000f35b6001610073565b610071600160043561018d565b61007160243560043561017b565b61007c60243560043533610120565b6100b96004353361017b565b6024359055602435600052600435337f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560206000a361007c565b6100ff3360043561017b565b8054604435818111610069579003905561007c604435602435600435610120565b61012b60018261018d565b805480851161006957849003905561014460018361018d565b8054840190558260005281817fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3505050565b6101879060029061018d565b9061018d565b60005260205260406000209056"
+
+	// ERC721Bin is the hex encoded creation code of the reference ERC721
+	// token. Only the creator may mint tokens.
+	ERC721Bin = "336004556102ea6100136000396102ea6000f33461007f577c010000000000000000000000000000000000000000000000000000000060003504806340c10f191461008f5780636352211e146100f357806370a08231146100fe578063095ea7b314610175578063081812fc1461010b578063a22cb46514610132578063e985e9c51461012457806323b872dd146101ce575b600080fd5b545b60005260206000f35b60045433141561007f576004351561007f576100ae60006024356102dc565b805461007f5760043590556100c46004356102a0565b60243560043560007fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef600080a4005b61008660043561026c565b61008460016004356102dc565b61011660043561026c565b5061008460026004356102dc565b6100846024356004356102ca565b61013e600435336102ca565b6024359055602435600052600435337f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c3160206000a3005b61018060243561026c565b61018981610283565b1561007f5761019b60026024356102dc565b6004359055602435600435827f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925600080a4005b6024351561007f576101e160443561026c565b80600435141561007f576101f860026044356102dc565b8054331461020f5761020982610283565b1561007f575b600090555061021f6004356102b5565b61022a6024356102a0565b61023760006044356102dc565b60243590556044356024356004357fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef600080a4005b6102776000826102dc565b54801561007f57905090565b80331461029a5761029433826102ca565b54905090565b50600190565b6102ab6001826102dc565b8054600101905550565b6102c06001826102dc565b6001815403905550565b6102d6906003906102dc565b906102dc565b60005260205260406000209056"
+)
+
+var (
+	// TransferEvent is the topic of the Transfer event of both token
+	// standards.
+	TransferEvent = EventTopic("Transfer(address,address,uint256)")
+
+	// ApprovalEvent is the topic of the Approval event of both token
+	// standards.
+	ApprovalEvent = EventTopic("Approval(address,address,uint256)")
+
+	// ApprovalForAllEvent is the topic of the ERC721 ApprovalForAll event.
+	ApprovalForAllEvent = EventTopic("ApprovalForAll(address,address,bool)")
+)
+
+// ERC20Code returns the code deploying the reference ERC20 token with the
+// given total supply.
+func ERC20Code(supply *big.Int) []byte {
+	return append(ethcmn.Hex2Bytes(ERC20Bin), ethcmn.BigToHash(supply).Bytes()...)
+}
+
+// ERC721Code returns the code deploying the reference ERC721 token.
+func ERC721Code() []byte {
+	return ethcmn.Hex2Bytes(ERC721Bin)
+}
+
+// Selector returns the four byte selector of the function with the given
+// canonical signature, e.g. "transfer(address,uint256)".
+func Selector(signature string) []byte {
+	return ethcrypto.Keccak256([]byte(signature))[:4]
+}
+
+// EventTopic returns the topic of the event with the given canonical
+// signature.
+func EventTopic(signature string) ethcmn.Hash {
+	return ethcrypto.Keccak256Hash([]byte(signature))
+}
+
+// PackCall returns the data of a call to the function with the given
+// canonical signature. As every argument of the reference tokens' functions
+// is a static type, the arguments are given as already encoded words.
+func PackCall(signature string, args ...ethcmn.Hash) []byte {
+	data := Selector(signature)
+	for _, arg := range args {
+		data = append(data, arg.Bytes()...)
+	}
+
+	return data
+}
+
+// AddressWord returns the given address encoded as a word.
+func AddressWord(addr ethcmn.Address) ethcmn.Hash {
+	return ethcmn.BytesToHash(addr.Bytes())
+}
+
+// IntWord returns the given unsigned integer encoded as a word.
+func IntWord(x int64) ethcmn.Hash {
+	return ethcmn.BigToHash(big.NewInt(x))
+}
@@ -0,0 +1,352 @@
+package contracts
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testChainID = big.NewInt(3)
+	testBalance = big.NewInt(1000000000000000000)
+)
+
+// testAccount defines an externally owned account signing transactions.
+type testAccount struct {
+	priv  *ecdsa.PrivateKey
+	addr  ethcmn.Address
+	nonce uint64
+}
+
+func newTestAccount(t *testing.T) *testAccount {
+	priv, err := ethcrypto.GenerateKey()
+	require.Nil(t, err)
+
+	return &testAccount{priv: priv, addr: ethcrypto.PubkeyToAddress(priv.PublicKey)}
+}
+
+// tx returns a signed transaction of the account calling the given contract,
+// or creating a contract if nil, with the given data.
+func (acc *testAccount) tx(to *ethcmn.Address, data []byte) *types.Transaction {
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(acc.nonce, big.NewInt(0), 1000000, big.NewInt(1), data)
+	} else {
+		tx = types.NewTransaction(acc.nonce, *to, big.NewInt(0), 1000000, big.NewInt(1), data)
+	}
+
+	tx.Sign(testChainID, acc.priv)
+	acc.nonce++
+
+	return tx
+}
+
+// txResult defines the result of a transaction executed by a testChain.
+type txResult struct {
+	ret             []byte
+	contractAddress ethcmn.Address
+	failed          bool
+	logs            []*ethtypes.Log
+}
+
+// testChain executes signed transactions in blocks of an EthermintApp, each
+// transaction being checked before the block delivering it is committed.
+type testChain struct {
+	t      *testing.T
+	app    *app.EthermintApp
+	header abci.Header
+}
+
+// newTestChain returns a testChain whose genesis state funds the given
+// accounts.
+func newTestChain(t *testing.T, accs ...*testAccount) *testChain {
+	ethApp := app.NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), app.SetEthChainID(testChainID))
+
+	genesis := app.DefaultGenesisState(testChainID)
+	for _, acc := range accs {
+		genesis.Alloc = append(genesis.Alloc, app.GenesisAccount{
+			Address: acc.addr,
+			Balance: (*ethmath.HexOrDecimal256)(testBalance),
+		})
+	}
+
+	appState, err := json.Marshal(genesis)
+	require.Nil(t, err)
+
+	ethApp.InitChain(abci.RequestInitChain{ChainId: "test-chain", AppStateBytes: appState})
+
+	c := &testChain{t: t, app: ethApp, header: abci.Header{ChainID: "test-chain", Time: 1000}}
+	c.block()
+
+	return c
+}
+
+// block checks the given transactions, then executes them in a new block and
+// commits it.
+func (c *testChain) block(txs ...*types.Transaction) []txResult {
+	txBytes := make([][]byte, len(txs))
+	for i, tx := range txs {
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(c.t, err)
+
+		res := c.app.CheckTx(bz)
+		require.True(c.t, res.IsOK(), res.Log)
+
+		txBytes[i] = bz
+	}
+
+	c.header.Height++
+	c.header.Time++
+	c.app.BeginBlock(abci.RequestBeginBlock{Header: c.header})
+
+	cdc := app.MakeCodec()
+	results := make([]txResult, len(txs))
+
+	for i, bz := range txBytes {
+		res := c.app.DeliverTx(bz)
+		require.True(c.t, res.IsOK(), res.Log)
+
+		data, err := types.DecodeResultData(cdc, res.Data)
+		require.Nil(c.t, err)
+
+		logs := make([]*ethtypes.Log, len(data.Logs))
+		for j, log := range data.Logs {
+			logs[j] = &ethtypes.Log{Address: log.Address, Topics: log.Topics, Data: log.Data}
+		}
+
+		results[i] = txResult{ret: data.Ret, contractAddress: data.ContractAddress, failed: data.Failed, logs: logs}
+	}
+
+	c.app.EndBlock(abci.RequestEndBlock{Height: c.header.Height})
+	c.header.AppHash = c.app.Commit().Data
+
+	return results
+}
+
+// call queries the application for a call to the given contract on top of
+// the latest committed state.
+func (c *testChain) call(to ethcmn.Address, data []byte) abci.ResponseQuery {
+	bz, err := json.Marshal(app.EstimateGasRequest{Height: c.header.Height, Time: c.header.Time, To: &to, Data: data})
+	require.Nil(c.t, err)
+
+	return c.app.Query(abci.RequestQuery{Path: app.QueryPathCall, Data: bz})
+}
+
+// deploy creates a contract with the given code in a new block, returning
+// the contract's address and the logs emitted by its constructor.
+func (c *testChain) deploy(acc *testAccount, code []byte) (ethcmn.Address, []*ethtypes.Log) {
+	addr := ethcrypto.CreateAddress(acc.addr, acc.nonce)
+
+	res := c.block(acc.tx(nil, code))
+	require.False(c.t, res[0].failed)
+	require.Equal(c.t, addr, res[0].contractAddress)
+
+	return addr, res[0].logs
+}
+
+// testLog returns a log emitted by the given contract.
+func testLog(addr ethcmn.Address, data []byte, topics ...ethcmn.Hash) *ethtypes.Log {
+	return &ethtypes.Log{Address: addr, Topics: topics, Data: data}
+}
+
+// requireLogs asserts that the given logs match the expected ones, ignoring
+// the fields identifying the block and transaction they were emitted in.
+func requireLogs(t *testing.T, expected, logs []*ethtypes.Log, msg string) {
+	require.Len(t, logs, len(expected), msg)
+
+	for i, log := range logs {
+		require.Equal(t, expected[i].Address, log.Address, msg)
+		require.Equal(t, expected[i].Topics, log.Topics, msg)
+		require.Equal(t, ethcmn.Bytes2Hex(expected[i].Data), ethcmn.Bytes2Hex(log.Data), msg)
+	}
+}
+
+func TestERC20(t *testing.T) {
+	alice, bob, carol := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	chain := newTestChain(t, alice, bob, carol)
+
+	token, logs := chain.deploy(alice, ERC20Code(big.NewInt(1000)))
+
+	a, b, c := AddressWord(alice.addr), AddressWord(bob.addr), AddressWord(carol.addr)
+	requireLogs(t, []*ethtypes.Log{testLog(token, IntWord(1000).Bytes(), TransferEvent, ethcmn.Hash{}, a)}, logs, "")
+
+	testCases := []struct {
+		from         *testAccount
+		data         []byte
+		expectFailed bool
+		expectLogs   []*ethtypes.Log
+	}{
+		{
+			alice, PackCall("transfer(address,uint256)", b, IntWord(300)), false,
+			[]*ethtypes.Log{testLog(token, IntWord(300).Bytes(), TransferEvent, a, b)},
+		},
+		{
+			alice, PackCall("approve(address,uint256)", c, IntWord(200)), false,
+			[]*ethtypes.Log{testLog(token, IntWord(200).Bytes(), ApprovalEvent, a, c)},
+		},
+		{
+			carol, PackCall("transferFrom(address,address,uint256)", a, c, IntWord(150)), false,
+			[]*ethtypes.Log{testLog(token, IntWord(150).Bytes(), TransferEvent, a, c)},
+		},
+		// the allowance left is insufficient
+		{carol, PackCall("transferFrom(address,address,uint256)", a, b, IntWord(100)), true, nil},
+		// the balance is insufficient
+		{bob, PackCall("transfer(address,uint256)", a, IntWord(301)), true, nil},
+		// bob has no allowance over alice's tokens
+		{bob, PackCall("transferFrom(address,address,uint256)", a, b, IntWord(1)), true, nil},
+		{bob, PackCall("decimals()"), true, nil},
+		{
+			bob, PackCall("transfer(address,uint256)", a, IntWord(300)), false,
+			[]*ethtypes.Log{testLog(token, IntWord(300).Bytes(), TransferEvent, b, a)},
+		},
+	}
+
+	for i, tc := range testCases {
+		res := chain.block(tc.from.tx(&token, tc.data))
+		require.Equal(t, tc.expectFailed, res[0].failed, fmt.Sprintf("unexpected result: test case #%d", i))
+		requireLogs(t, tc.expectLogs, res[0].logs, fmt.Sprintf("unexpected logs: test case #%d", i))
+
+		// successful transfers and approvals return true
+		if !tc.expectFailed {
+			require.Equal(t, IntWord(1).Bytes(), res[0].ret, fmt.Sprintf("unexpected return value: test case #%d", i))
+		}
+	}
+
+	viewCases := []struct {
+		data     []byte
+		expected int64
+	}{
+		{PackCall("totalSupply()"), 1000},
+		{PackCall("balanceOf(address)", a), 850},
+		{PackCall("balanceOf(address)", b), 0},
+		{PackCall("balanceOf(address)", c), 150},
+		{PackCall("allowance(address,address)", a, c), 50},
+		{PackCall("allowance(address,address)", c, a), 0},
+	}
+
+	for i, tc := range viewCases {
+		res := chain.call(token, tc.data)
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected failure: test case #%d", i))
+		require.Equal(t, IntWord(tc.expected).Bytes(), res.Value, fmt.Sprintf("unexpected value: test case #%d", i))
+	}
+}
+
+func TestERC721(t *testing.T) {
+	alice, bob, carol := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	chain := newTestChain(t, alice, bob, carol)
+
+	token, logs := chain.deploy(alice, ERC721Code())
+	require.Empty(t, logs)
+
+	zero, a, b, c := ethcmn.Hash{}, AddressWord(alice.addr), AddressWord(bob.addr), AddressWord(carol.addr)
+	id1, id2, id3 := IntWord(1), IntWord(2), IntWord(3)
+
+	testCases := []struct {
+		from         *testAccount
+		data         []byte
+		expectFailed bool
+		expectLogs   []*ethtypes.Log
+	}{
+		{
+			alice, PackCall("mint(address,uint256)", b, id1), false,
+			[]*ethtypes.Log{testLog(token, nil, TransferEvent, zero, b, id1)},
+		},
+		{
+			alice, PackCall("mint(address,uint256)", b, id2), false,
+			[]*ethtypes.Log{testLog(token, nil, TransferEvent, zero, b, id2)},
+		},
+		// only the creator may mint and tokens are unique
+		{carol, PackCall("mint(address,uint256)", c, id3), true, nil},
+		{alice, PackCall("mint(address,uint256)", c, id1), true, nil},
+		// only the owner or its operators may approve
+		{carol, PackCall("approve(address,uint256)", c, id1), true, nil},
+		{
+			bob, PackCall("approve(address,uint256)", c, id1), false,
+			[]*ethtypes.Log{testLog(token, nil, ApprovalEvent, b, c, id1)},
+		},
+		{
+			carol, PackCall("transferFrom(address,address,uint256)", b, c, id1), false,
+			[]*ethtypes.Log{testLog(token, nil, TransferEvent, b, c, id1)},
+		},
+		// the approval is cleared by the transfer
+		{bob, PackCall("transferFrom(address,address,uint256)", c, b, id1), true, nil},
+		{carol, PackCall("transferFrom(address,address,uint256)", b, c, id2), true, nil},
+		{
+			bob, PackCall("setApprovalForAll(address,bool)", c, IntWord(1)), false,
+			[]*ethtypes.Log{testLog(token, IntWord(1).Bytes(), ApprovalForAllEvent, b, c)},
+		},
+		// the token must be transferred from its owner
+		{carol, PackCall("transferFrom(address,address,uint256)", a, c, id2), true, nil},
+		{
+			carol, PackCall("transferFrom(address,address,uint256)", b, a, id2), false,
+			[]*ethtypes.Log{testLog(token, nil, TransferEvent, b, a, id2)},
+		},
+		// unknown tokens cannot be transferred
+		{carol, PackCall("transferFrom(address,address,uint256)", b, a, id3), true, nil},
+	}
+
+	for i, tc := range testCases {
+		res := chain.block(tc.from.tx(&token, tc.data))
+		require.Equal(t, tc.expectFailed, res[0].failed, fmt.Sprintf("unexpected result: test case #%d", i))
+		requireLogs(t, tc.expectLogs, res[0].logs, fmt.Sprintf("unexpected logs: test case #%d", i))
+	}
+
+	viewCases := []struct {
+		data         []byte
+		expectFailed bool
+		expected     ethcmn.Hash
+	}{
+		{PackCall("ownerOf(uint256)", id1), false, c},
+		{PackCall("ownerOf(uint256)", id2), false, a},
+		{PackCall("ownerOf(uint256)", id3), true, zero},
+		{PackCall("balanceOf(address)", a), false, IntWord(1)},
+		{PackCall("balanceOf(address)", b), false, IntWord(0)},
+		{PackCall("balanceOf(address)", c), false, IntWord(1)},
+		{PackCall("getApproved(uint256)", id1), false, zero},
+		{PackCall("getApproved(uint256)", id3), true, zero},
+		{PackCall("isApprovedForAll(address,address)", b, c), false, IntWord(1)},
+		{PackCall("isApprovedForAll(address,address)", c, b), false, IntWord(0)},
+	}
+
+	for i, tc := range viewCases {
+		res := chain.call(token, tc.data)
+		require.Equal(t, tc.expectFailed, !res.IsOK(), fmt.Sprintf("unexpected result: test case #%d", i))
+
+		if !tc.expectFailed {
+			require.Equal(t, tc.expected.Bytes(), res.Value, fmt.Sprintf("unexpected value: test case #%d", i))
+		}
+	}
+}
+
+func TestContractsRejectValue(t *testing.T) {
+	alice := newTestAccount(t)
+	chain := newTestChain(t, alice)
+
+	erc20, _ := chain.deploy(alice, ERC20Code(big.NewInt(1)))
+	erc721, _ := chain.deploy(alice, ERC721Code())
+
+	for i, addr := range []ethcmn.Address{erc20, erc721} {
+		tx := types.NewTransaction(alice.nonce, addr, big.NewInt(1), 1000000, big.NewInt(1), nil)
+		tx.Sign(testChainID, alice.priv)
+		alice.nonce++
+
+		res := chain.block(tx)
+		require.True(t, res[0].failed, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
@@ -0,0 +1,111 @@
+; Minimal ERC20 token.
+;
+; The constructor takes the total supply as its single argument and credits it
+; to the creator. Storage follows the layout of a Solidity contract declaring
+; totalSupply, balances and allowances in order: the total supply is stored in
+; slot 0, the balance of an account at keccak256(account . 1) and the allowance
+; of a spender over an owner's tokens at keccak256(spender . keccak256(owner . 2)).
+;
+; Every function reverts without data on failure, including on insufficient
+; balances or allowances and when called with value. Arguments are not
+; validated.
+
+; mem[0x40] = supply, appended to the code as the constructor's argument
+    PUSH1 0x20 PUSH $code PUSH1 0x40 CODECOPY
+    PUSH1 0x40 MLOAD PUSH1 0x00 SSTORE
+; balances[creator] = supply
+    PUSH1 0x40 MLOAD PUSH1 0x01 CALLER PUSH1 0x00 MSTORE PUSH1 0x20 MSTORE
+    PUSH1 0x40 PUSH1 0x00 SHA3 SSTORE
+; emit Transfer(0, creator, supply)
+    CALLER PUSH1 0x00
+    PUSH32 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+    PUSH1 0x20 PUSH1 0x40 LOG3
+; return the runtime code
+    PUSH $runtime PUSH $init PUSH1 0x00 CODECOPY
+    PUSH $runtime PUSH1 0x00 RETURN
+
+.runtime
+
+    CALLVALUE PUSH @revert JUMPI
+; selector = calldata[0:4]
+    PUSH29 0x0100000000000000000000000000000000000000000000000000000000
+    PUSH1 0x00 CALLDATALOAD DIV
+    DUP1 PUSH4 0x18160ddd EQ PUSH @totalSupply JUMPI
+    DUP1 PUSH4 0x70a08231 EQ PUSH @balanceOf JUMPI
+    DUP1 PUSH4 0xa9059cbb EQ PUSH @transfer JUMPI
+    DUP1 PUSH4 0x095ea7b3 EQ PUSH @approve JUMPI
+    DUP1 PUSH4 0xdd62ed3e EQ PUSH @allowance JUMPI
+    DUP1 PUSH4 0x23b872dd EQ PUSH @transferFrom JUMPI
+revert:
+    PUSH1 0x00 DUP1 REVERT
+
+; totalSupply()
+totalSupply:
+    PUSH1 0x00
+sload_return:               ; [slot]
+    SLOAD
+return:                     ; [word]
+    PUSH1 0x00 MSTORE PUSH1 0x20 PUSH1 0x00 RETURN
+return_true:
+    PUSH1 0x01 PUSH @return JUMP
+
+; balanceOf(address owner)
+balanceOf:
+    PUSH @sload_return PUSH1 0x01 PUSH1 0x04 CALLDATALOAD PUSH @mapslot JUMP
+
+; allowance(address owner, address spender)
+allowance:
+    PUSH @sload_return PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD PUSH @allowslot JUMP
+
+; transfer(address to, uint256 value)
+transfer:
+    PUSH @return_true PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD CALLER PUSH @move JUMP
+
+; approve(address spender, uint256 value)
+approve:
+    PUSH @approve1 PUSH1 0x04 CALLDATALOAD CALLER PUSH @allowslot JUMP
+approve1:                   ; [slot]
+    PUSH1 0x24 CALLDATALOAD SWAP1 SSTORE
+; emit Approval(owner, spender, value)
+    PUSH1 0x24 CALLDATALOAD PUSH1 0x00 MSTORE
+    PUSH1 0x04 CALLDATALOAD CALLER
+    PUSH32 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925
+    PUSH1 0x20 PUSH1 0x00 LOG3
+    PUSH @return_true JUMP
+
+; transferFrom(address from, address to, uint256 value)
+transferFrom:
+    PUSH @transferFrom1 CALLER PUSH1 0x04 CALLDATALOAD PUSH @allowslot JUMP
+transferFrom1:              ; [slot]
+    DUP1 SLOAD PUSH1 0x44 CALLDATALOAD
+    DUP2 DUP2 GT PUSH @revert JUMPI
+    SWAP1 SUB SWAP1 SSTORE
+    PUSH @return_true PUSH1 0x44 CALLDATALOAD PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD
+    PUSH @move JUMP
+
+; move moves value tokens from one account to another and emits a Transfer
+; event, reverting if the sender's balance is insufficient.
+move:                       ; [from, to, value, ret] -> []
+    PUSH @move1 PUSH1 0x01 DUP3 PUSH @mapslot JUMP
+move1:                      ; [fromSlot, from, to, value, ret]
+    DUP1 SLOAD DUP1 DUP6 GT PUSH @revert JUMPI
+    DUP5 SWAP1 SUB SWAP1 SSTORE
+    PUSH @move2 PUSH1 0x01 DUP4 PUSH @mapslot JUMP
+move2:                      ; [toSlot, from, to, value, ret]
+    DUP1 SLOAD DUP5 ADD SWAP1 SSTORE
+    DUP3 PUSH1 0x00 MSTORE
+    DUP2 DUP2
+    PUSH32 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+    PUSH1 0x20 PUSH1 0x00 LOG3
+    POP POP POP JUMP
+
+; allowslot returns the slot of a spender's allowance over an owner's tokens.
+allowslot:                  ; [owner, spender, ret] -> [slot]
+    PUSH @allowslot1 SWAP1 PUSH1 0x02 SWAP1 PUSH @mapslot JUMP
+allowslot1:                 ; [ownerSlot, spender, ret]
+    SWAP1 PUSH @mapslot JUMP
+
+; mapslot returns the slot of a key in the mapping declared at the given slot.
+mapslot:                    ; [key, index, ret] -> [slot]
+    PUSH1 0x00 MSTORE PUSH1 0x20 MSTORE
+    PUSH1 0x40 PUSH1 0x00 SHA3 SWAP1 JUMP
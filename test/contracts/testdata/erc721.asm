@@ -0,0 +1,169 @@
+; Minimal ERC721 non-fungible token.
+;
+; The creator is the only account allowed to mint tokens. Storage follows the
+; layout of a Solidity contract declaring owners, balances, tokenApprovals,
+; operatorApprovals and minter in order: the owner of a token is stored at
+; keccak256(id . 0), the balance of an account at keccak256(account . 1), the
+; approved account of a token at keccak256(id . 2), whether an operator is
+; approved for all of an owner's tokens at
+; keccak256(operator . keccak256(owner . 3)) and the minter in slot 4.
+;
+; Every function reverts without data on failure, including on unknown tokens,
+; unauthorized callers and when called with value. Safe transfers and ERC165
+; are not supported and arguments are not validated.
+
+; minter = creator
+    CALLER PUSH1 0x04 SSTORE
+; return the runtime code
+    PUSH $runtime PUSH $init PUSH1 0x00 CODECOPY
+    PUSH $runtime PUSH1 0x00 RETURN
+
+.runtime
+
+    CALLVALUE PUSH @revert JUMPI
+; selector = calldata[0:4]
+    PUSH29 0x0100000000000000000000000000000000000000000000000000000000
+    PUSH1 0x00 CALLDATALOAD DIV
+    DUP1 PUSH4 0x40c10f19 EQ PUSH @mint JUMPI
+    DUP1 PUSH4 0x6352211e EQ PUSH @ownerOf JUMPI
+    DUP1 PUSH4 0x70a08231 EQ PUSH @balanceOf JUMPI
+    DUP1 PUSH4 0x095ea7b3 EQ PUSH @approve JUMPI
+    DUP1 PUSH4 0x081812fc EQ PUSH @getApproved JUMPI
+    DUP1 PUSH4 0xa22cb465 EQ PUSH @setApprovalForAll JUMPI
+    DUP1 PUSH4 0xe985e9c5 EQ PUSH @isApprovedForAll JUMPI
+    DUP1 PUSH4 0x23b872dd EQ PUSH @transferFrom JUMPI
+revert:
+    PUSH1 0x00 DUP1 REVERT
+
+sload_return:               ; [slot]
+    SLOAD
+return:                     ; [word]
+    PUSH1 0x00 MSTORE PUSH1 0x20 PUSH1 0x00 RETURN
+
+; mint(address to, uint256 id)
+mint:
+    PUSH1 0x04 SLOAD CALLER EQ ISZERO PUSH @revert JUMPI
+    PUSH1 0x04 CALLDATALOAD ISZERO PUSH @revert JUMPI
+    PUSH @mint1 PUSH1 0x00 PUSH1 0x24 CALLDATALOAD PUSH @mapslot JUMP
+mint1:                      ; [ownerSlot]
+    DUP1 SLOAD PUSH @revert JUMPI
+    PUSH1 0x04 CALLDATALOAD SWAP1 SSTORE
+    PUSH @mint2 PUSH1 0x04 CALLDATALOAD PUSH @incbal JUMP
+mint2:
+; emit Transfer(0, to, id)
+    PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD PUSH1 0x00
+    PUSH32 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+    PUSH1 0x00 DUP1 LOG4
+    STOP
+
+; ownerOf(uint256 id)
+ownerOf:
+    PUSH @return PUSH1 0x04 CALLDATALOAD PUSH @ownerof JUMP
+
+; balanceOf(address owner)
+balanceOf:
+    PUSH @sload_return PUSH1 0x01 PUSH1 0x04 CALLDATALOAD PUSH @mapslot JUMP
+
+; getApproved(uint256 id)
+getApproved:
+    PUSH @getApproved1 PUSH1 0x04 CALLDATALOAD PUSH @ownerof JUMP
+getApproved1:               ; [owner]
+    POP PUSH @sload_return PUSH1 0x02 PUSH1 0x04 CALLDATALOAD PUSH @mapslot JUMP
+
+; isApprovedForAll(address owner, address operator)
+isApprovedForAll:
+    PUSH @sload_return PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD PUSH @opslot JUMP
+
+; setApprovalForAll(address operator, bool approved)
+setApprovalForAll:
+    PUSH @setApprovalForAll1 PUSH1 0x04 CALLDATALOAD CALLER PUSH @opslot JUMP
+setApprovalForAll1:         ; [slot]
+    PUSH1 0x24 CALLDATALOAD SWAP1 SSTORE
+; emit ApprovalForAll(owner, operator, approved)
+    PUSH1 0x24 CALLDATALOAD PUSH1 0x00 MSTORE
+    PUSH1 0x04 CALLDATALOAD CALLER
+    PUSH32 0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31
+    PUSH1 0x20 PUSH1 0x00 LOG3
+    STOP
+
+; approve(address to, uint256 id)
+approve:
+    PUSH @approve1 PUSH1 0x24 CALLDATALOAD PUSH @ownerof JUMP
+approve1:                   ; [owner]
+    PUSH @approve2 DUP2 PUSH @allowed JUMP
+approve2:                   ; [allowed, owner]
+    ISZERO PUSH @revert JUMPI
+    PUSH @approve3 PUSH1 0x02 PUSH1 0x24 CALLDATALOAD PUSH @mapslot JUMP
+approve3:                   ; [approvedSlot, owner]
+    PUSH1 0x04 CALLDATALOAD SWAP1 SSTORE
+; emit Approval(owner, to, id)
+    PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD DUP3
+    PUSH32 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925
+    PUSH1 0x00 DUP1 LOG4
+    STOP
+
+; transferFrom(address from, address to, uint256 id)
+transferFrom:
+    PUSH1 0x24 CALLDATALOAD ISZERO PUSH @revert JUMPI
+    PUSH @transferFrom1 PUSH1 0x44 CALLDATALOAD PUSH @ownerof JUMP
+transferFrom1:              ; [owner]
+    DUP1 PUSH1 0x04 CALLDATALOAD EQ ISZERO PUSH @revert JUMPI
+    PUSH @transferFrom2 PUSH1 0x02 PUSH1 0x44 CALLDATALOAD PUSH @mapslot JUMP
+transferFrom2:              ; [approvedSlot, owner]
+    DUP1 SLOAD CALLER EQ PUSH @transferFrom4 JUMPI
+    PUSH @transferFrom3 DUP3 PUSH @allowed JUMP
+transferFrom3:              ; [allowed, approvedSlot, owner]
+    ISZERO PUSH @revert JUMPI
+transferFrom4:              ; [approvedSlot, owner]
+    PUSH1 0x00 SWAP1 SSTORE POP
+    PUSH @transferFrom5 PUSH1 0x04 CALLDATALOAD PUSH @decbal JUMP
+transferFrom5:
+    PUSH @transferFrom6 PUSH1 0x24 CALLDATALOAD PUSH @incbal JUMP
+transferFrom6:
+    PUSH @transferFrom7 PUSH1 0x00 PUSH1 0x44 CALLDATALOAD PUSH @mapslot JUMP
+transferFrom7:              ; [ownerSlot]
+    PUSH1 0x24 CALLDATALOAD SWAP1 SSTORE
+; emit Transfer(from, to, id)
+    PUSH1 0x44 CALLDATALOAD PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD
+    PUSH32 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+    PUSH1 0x00 DUP1 LOG4
+    STOP
+
+; ownerof returns the owner of a token, reverting if it does not exist.
+ownerof:                    ; [id, ret] -> [owner]
+    PUSH @ownerof1 PUSH1 0x00 DUP3 PUSH @mapslot JUMP
+ownerof1:                   ; [ownerSlot, id, ret]
+    SLOAD DUP1 ISZERO PUSH @revert JUMPI
+    SWAP1 POP SWAP1 JUMP
+
+; allowed returns whether the caller is either the given owner or one of its
+; approved operators.
+allowed:                    ; [owner, ret] -> [allowed]
+    DUP1 CALLER EQ PUSH @allowed2 JUMPI
+    PUSH @allowed1 CALLER DUP3 PUSH @opslot JUMP
+allowed1:                   ; [operatorSlot, owner, ret]
+    SLOAD SWAP1 POP SWAP1 JUMP
+allowed2:                   ; [owner, ret]
+    POP PUSH1 0x01 SWAP1 JUMP
+
+; incbal and decbal increment and decrement the balance of an account.
+incbal:                     ; [account, ret] -> []
+    PUSH @incbal1 PUSH1 0x01 DUP3 PUSH @mapslot JUMP
+incbal1:                    ; [slot, account, ret]
+    DUP1 SLOAD PUSH1 0x01 ADD SWAP1 SSTORE POP JUMP
+decbal:                     ; [account, ret] -> []
+    PUSH @decbal1 PUSH1 0x01 DUP3 PUSH @mapslot JUMP
+decbal1:                    ; [slot, account, ret]
+    PUSH1 0x01 DUP2 SLOAD SUB SWAP1 SSTORE POP JUMP
+
+; opslot returns the slot of whether an operator is approved for all of an
+; owner's tokens.
+opslot:                     ; [owner, operator, ret] -> [slot]
+    PUSH @opslot1 SWAP1 PUSH1 0x03 SWAP1 PUSH @mapslot JUMP
+opslot1:                    ; [ownerSlot, operator, ret]
+    SWAP1 PUSH @mapslot JUMP
+
+; mapslot returns the slot of a key in the mapping declared at the given slot.
+mapslot:                    ; [key, index, ret] -> [slot]
+    PUSH1 0x00 MSTORE PUSH1 0x20 MSTORE
+    PUSH1 0x40 PUSH1 0x00 SHA3 SWAP1 JUMP
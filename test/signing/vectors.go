@@ -0,0 +1,168 @@
+// Package signing provides deterministic test vectors of the documents signed
+// by the signers of an EmbeddedTx, along with helpers to verify them, so that
+// wallets implemented in other languages can check that they produce the same
+// sign bytes and signatures as Ethermint.
+//
+// The vectors are published as JSON under testdata, hex encoding all bytes.
+// Each vector lists the chain ID, account number and sequence signed over, the
+// sign bytes of the messages (i.e. their canonical JSON), the resulting sign
+// bytes of the document and the signature of a fixed private key. A wallet
+// may produce vectors of its own in the same format and have ReadVectors and
+// Verify check them. The package's tests fail should the published vectors
+// and those returned by Vectors diverge.
+package signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Vector defines a test vector of an EmbeddedTx signature. The signature is a
+// recoverable secp256k1 signature of the [R || S || V] format, where V is 0 or
+// 1, over the keccak256 hash of the sign bytes.
+type Vector struct {
+	Description   string            `json:"description"`
+	ChainID       string            `json:"chain_id"`
+	AccountNumber int64             `json:"account_number"`
+	Sequence      int64             `json:"sequence"`
+	Messages      []json.RawMessage `json:"messages"`
+	PrivateKey    hexutil.Bytes     `json:"private_key"`
+	Address       ethcmn.Address    `json:"address"`
+	SignBytes     hexutil.Bytes     `json:"sign_bytes"`
+	Signature     hexutil.Bytes     `json:"signature"`
+}
+
+// signBytesMsg implements an sdk.Msg consisting of its sign bytes alone, so
+// that the sign bytes of a vector are computed as those of an EmbeddedTx.
+type signBytesMsg json.RawMessage
+
+func (msg signBytesMsg) Type() string                 { return "" }
+func (msg signBytesMsg) ValidateBasic() sdk.Error     { return nil }
+func (msg signBytesMsg) GetSignBytes() []byte         { return msg }
+func (msg signBytesMsg) GetSigners() []sdk.AccAddress { return nil }
+
+// Vectors returns the test vectors of EmbeddedTx signatures. They cover
+// multiple messages, characters escaped by Go's JSON encoder and account
+// numbers and sequences beyond 2^53. As the sign bytes are sorted by decoding
+// them into generic JSON values, such integers are rounded to the nearest
+// float64 and e.g. a sequence of 2^63-1 is signed as 9223372036854776000.
+func Vectors() []Vector {
+	privKey1 := crypto.PrivKeySecp256k1(ethcrypto.Keccak256([]byte("ethermint signing vector key 1")))
+	privKey2 := crypto.PrivKeySecp256k1(ethcrypto.Keccak256([]byte("ethermint signing vector key 2")))
+
+	addr1 := sdk.AccAddress(privKey1.PubKey().Address())
+	addr2 := sdk.AccAddress(privKey2.PubKey().Address())
+
+	send := newMsgSend(addr1, addr2, 10)
+
+	return []Vector{
+		newVector("single message", privKey1, "ethermint", 0, 0, send),
+		newVector("non-zero account number and sequence", privKey1, "ethermint", 1, 7, send),
+		newVector(
+			"multiple messages", privKey2, "ethermint-1", 42, 1000,
+			newMsgSend(addr2, addr1, 5), send,
+		),
+		newVector("escaped chain ID characters", privKey1, "test<&>chain", 3, 4, send),
+		newVector(
+			"account number and sequence beyond 2^53", privKey2, "ethermint",
+			9007199254740993, 9223372036854775807, send,
+		),
+	}
+}
+
+// ReadVectors reads a JSON array of test vectors.
+func ReadVectors(r io.Reader) ([]Vector, error) {
+	var vectors []Vector
+	if err := json.NewDecoder(r).Decode(&vectors); err != nil {
+		return nil, err
+	}
+
+	return vectors, nil
+}
+
+// Verify returns an error if the sign bytes of the vector differ from those
+// Ethermint computes, if the address is not that of the private key, if any,
+// or if the signature does not recover to the address.
+func (v Vector) Verify() error {
+	msgs := make([]sdk.Msg, len(v.Messages))
+	for i, msg := range v.Messages {
+		msgs[i] = signBytesMsg(msg)
+	}
+
+	signBytes := types.EmbeddedSignBytes(v.ChainID, v.AccountNumber, v.Sequence, msgs)
+	if !bytes.Equal(signBytes, v.SignBytes) {
+		return fmt.Errorf("sign bytes %s differ from expected %s", v.SignBytes, hexutil.Bytes(signBytes))
+	}
+
+	if len(v.PrivateKey) > 0 {
+		privKey, err := ethcrypto.ToECDSA(v.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("invalid private key: %v", err)
+		}
+
+		if addr := ethcrypto.PubkeyToAddress(privKey.PublicKey); addr != v.Address {
+			return fmt.Errorf("address %s differs from address %s of the private key", v.Address.Hex(), addr.Hex())
+		}
+	}
+
+	pubKey, err := ethcrypto.SigToPub(ethcrypto.Keccak256(signBytes), v.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	if signer := ethcrypto.PubkeyToAddress(*pubKey); signer != v.Address {
+		return fmt.Errorf("signature recovers to %s instead of %s", signer.Hex(), v.Address.Hex())
+	}
+
+	return nil
+}
+
+// newVector returns a test vector of the signature of the given messages by
+// the given private key.
+func newVector(
+	description string, privKey crypto.PrivKeySecp256k1, chainID string, accountNumber, sequence int64,
+	msgs ...sdk.Msg,
+) Vector {
+
+	signBytes := types.EmbeddedSignBytes(chainID, accountNumber, sequence, msgs)
+
+	sig, err := privKey.Sign(signBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	msgsBytes := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		msgsBytes[i] = msg.GetSignBytes()
+	}
+
+	return Vector{
+		Description:   description,
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		Messages:      msgsBytes,
+		PrivateKey:    hexutil.Bytes(privKey),
+		Address:       ethcmn.BytesToAddress(privKey.PubKey().Address()),
+		SignBytes:     signBytes,
+		Signature:     hexutil.Bytes(sig.(crypto.SignatureSecp256k1)),
+	}
+}
+
+// newMsgSend returns a bank message sending the given amount of the default
+// denomination.
+func newMsgSend(from, to sdk.AccAddress, amount int64) sdk.Msg {
+	coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, amount)}
+	return bank.NewMsgSend([]bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+}
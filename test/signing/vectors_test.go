@@ -0,0 +1,87 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const vectorsFile = "testdata/embedded_tx_vectors.json"
+
+var update = flag.Bool("update", false, "write the test vectors to "+vectorsFile)
+
+func TestVectors(t *testing.T) {
+	vectors := Vectors()
+
+	bz, err := json.MarshalIndent(vectors, "", "    ")
+	require.Nil(t, err)
+
+	bz = append(bz, '\n')
+
+	if *update {
+		require.Nil(t, ioutil.WriteFile(vectorsFile, bz, 0644))
+	}
+
+	published, err := ioutil.ReadFile(vectorsFile)
+	require.Nil(t, err)
+	require.Equal(t, string(bz), string(published), "published vectors diverge, run the tests with -update")
+
+	// messages are indented along with the vectors, so read vectors are
+	// compared once encoded again
+	read, err := ReadVectors(bytes.NewReader(published))
+	require.Nil(t, err)
+
+	readBz, err := json.MarshalIndent(read, "", "    ")
+	require.Nil(t, err)
+	require.Equal(t, string(published), string(append(readBz, '\n')))
+
+	// vectors are deterministic
+	require.Equal(t, vectors, Vectors())
+
+	for i, v := range read {
+		require.Nil(t, v.Verify(), fmt.Sprintf("unexpected error: test case #%d", i))
+	}
+}
+
+func TestVectorVerify(t *testing.T) {
+	vector := Vectors()[2]
+
+	testCases := []struct {
+		modify    func(*Vector)
+		expectErr bool
+	}{
+		{func(v *Vector) {}, false},
+		{func(v *Vector) { v.PrivateKey = nil }, false},
+		{func(v *Vector) { v.ChainID = "ethermint-2" }, true},
+		{func(v *Vector) { v.AccountNumber++ }, true},
+		{func(v *Vector) { v.Sequence++ }, true},
+		{func(v *Vector) { v.Messages = v.Messages[:1] }, true},
+		{func(v *Vector) { v.SignBytes = append(hexutil.Bytes{}, v.SignBytes[1:]...) }, true},
+		{func(v *Vector) { v.PrivateKey = Vectors()[0].PrivateKey }, true},
+		{func(v *Vector) { v.PrivateKey = hexutil.Bytes{0x01} }, true},
+		{func(v *Vector) { v.Address = Vectors()[0].Address; v.PrivateKey = nil }, true},
+		{func(v *Vector) { v.Signature = Vectors()[0].Signature }, true},
+		{func(v *Vector) { v.Signature = v.Signature[:64] }, true},
+	}
+
+	for i, tc := range testCases {
+		v := vector
+		v.Messages = append([]json.RawMessage{}, vector.Messages...)
+		tc.modify(&v)
+
+		err := v.Verify()
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("unexpected result: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/app"
+	"github.com/cosmos/ethermint/rpc"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// txKind defines a kind of transaction sent by the load test.
+type txKind int
+
+const (
+	kindTransfer txKind = iota
+	kindDeploy
+	kindCall
+	numKinds
+)
+
+var (
+	kindNames = [numKinds]string{"transfer", "deploy", "call"}
+
+	// kindGas defines the gas limit of every kind of transaction. A call
+	// setting the counter's storage slot for the first time uses the most gas.
+	kindGas = [numKinds]uint64{21000, 100000, 50000}
+
+	// counterCode is the init code of the contract deployed by the load test,
+	// whose runtime code increments storage slot zero on every call.
+	counterCode = ethcmn.FromHex("0x600a600c600039600a6000f3" + "60016000540160005500")
+)
+
+func (k txKind) String() string {
+	return kindNames[k]
+}
+
+// parseMix parses a comma separated list of <kind>=<weight> pairs into the
+// schedule of the kinds of transactions sent in turn by every account, each
+// kind appearing as many times as its weight.
+func parseMix(s string) ([]txKind, error) {
+	var mix []txKind
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --%s pair %q, expected <kind>=<weight>", flagMix, pair)
+		}
+
+		kind, ok := parseKind(parts[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction kind %q in --%s", parts[0], flagMix)
+		}
+
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q of %s in --%s", parts[1], kind, flagMix)
+		}
+
+		for i := 0; i < weight; i++ {
+			mix = append(mix, kind)
+		}
+	}
+
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("--%s must have a positive weight", flagMix)
+	}
+
+	return mix, nil
+}
+
+// parseKind returns the kind of transaction with the given name.
+func parseKind(name string) (txKind, bool) {
+	for k := txKind(0); k < numKinds; k++ {
+		if kindNames[k] == name {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
+
+// config defines the parameters of a load test.
+type config struct {
+	node         string
+	faucetKey    *ecdsa.PrivateKey
+	chainID      *big.Int
+	accounts     int
+	txs          int
+	mix          []txKind
+	rate         float64
+	gasPrice     *big.Int
+	fund         *big.Int
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// account defines an account sending transactions, tracking its next nonce.
+type account struct {
+	key   *ecdsa.PrivateKey
+	addr  ethcmn.Address
+	nonce uint64
+}
+
+func newAccount(key *ecdsa.PrivateKey) *account {
+	return &account{key: key, addr: ethcrypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// sentTx records a transaction sent by the load test, along with the error
+// of the node rejecting it, if any, or the receipt served for it otherwise.
+// The receipt of a transaction whose receipt could not be polled in time is
+// nil and the polling error is recorded.
+type sentTx struct {
+	kind        txKind
+	hash        ethcmn.Hash
+	sentAt      time.Time
+	acceptedAt  time.Time
+	err         error
+	receipt     *rpc.RPCReceipt
+	receiptErr  error
+	confirmedAt time.Time
+}
+
+// client sends transactions signed for a chain to a JSON-RPC endpoint.
+type client struct {
+	rpc     *ethrpc.Client
+	cdc     *wire.Codec
+	chainID *big.Int
+}
+
+// nonce returns the committed nonce of the account at the given address.
+func (c *client) nonce(addr ethcmn.Address) (uint64, error) {
+	var raw json.RawMessage
+	if err := c.rpc.Call(&raw, "ethermint_account", addr.Hex()); err != nil {
+		return 0, err
+	}
+
+	var res app.AccountResponse
+	if err := c.cdc.UnmarshalJSON(raw, &res); err != nil {
+		return 0, err
+	}
+
+	if res.Account == nil {
+		return 0, nil
+	}
+
+	return uint64(res.Account.GetSequence()), nil
+}
+
+// send signs a transaction from the given account, to the given address or
+// creating a contract if nil, and sends it, consuming the account's nonce
+// once the node accepted it.
+func (c *client) send(
+	from *account, to *ethcmn.Address, value *big.Int, gas uint64, gasPrice *big.Int, data []byte,
+) (ethcmn.Hash, error) {
+
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(from.nonce, value, gas, gasPrice, data)
+	} else {
+		tx = types.NewTransaction(from.nonce, *to, value, gas, gasPrice, data)
+	}
+
+	tx.Sign(c.chainID, from.key)
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	var hash ethcmn.Hash
+	if err := c.rpc.Call(&hash, "eth_sendRawTransaction", hexutil.Bytes(txBytes)); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	from.nonce++
+	return hash, nil
+}
+
+// waitReceipt polls for the receipt of the transaction with the given hash
+// at the given interval until it is served or the deadline passes.
+func (c *client) waitReceipt(hash ethcmn.Hash, deadline time.Time, interval time.Duration) (*rpc.RPCReceipt, error) {
+	for {
+		var receipt *rpc.RPCReceipt
+		if err := c.rpc.Call(&receipt, "eth_getTransactionReceipt", hash); err != nil {
+			return nil, err
+		}
+
+		if receipt != nil {
+			return receipt, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no receipt for transaction %s", hash.Hex())
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// limiter paces the transactions sent by all accounts to an overall rate.
+type limiter struct {
+	ticker *time.Ticker
+}
+
+// newLimiter returns a limiter pacing transactions to the given rate, per
+// second, or not at all if the rate is not positive.
+func newLimiter(rate float64) *limiter {
+	if rate <= 0 {
+		return &limiter{}
+	}
+
+	return &limiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / rate))}
+}
+
+func (l *limiter) wait() {
+	if l.ticker != nil {
+		<-l.ticker.C
+	}
+}
+
+func (l *limiter) stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
+
+// run runs a load test against the node given by the configuration and
+// returns its report.
+func run(cfg config) (*report, error) {
+	rpcClient, err := ethrpc.Dial(cfg.node)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rpcClient.Close()
+
+	c := &client{rpc: rpcClient, cdc: app.MakeCodec(), chainID: cfg.chainID}
+
+	accounts, contract, err := setup(c, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "sending %d transactions from %d accounts\n", cfg.accounts*cfg.txs, cfg.accounts)
+
+	start := time.Now()
+	return newReport(spam(c, cfg, accounts, contract), start), nil
+}
+
+// setup generates and funds the accounts of the load test from the faucet
+// key, along with deploying the counter contract if the mix has calls, and
+// waits for the setup transactions to be committed. The address of the
+// contract is returned, if deployed.
+func setup(c *client, cfg config) ([]*account, ethcmn.Address, error) {
+	faucet := newAccount(cfg.faucetKey)
+
+	var err error
+	if faucet.nonce, err = c.nonce(faucet.addr); err != nil {
+		return nil, ethcmn.Address{}, fmt.Errorf("failed to query the faucet's nonce: %v", err)
+	}
+
+	var hashes []ethcmn.Hash
+	if hasKind(cfg.mix, kindCall) {
+		hash, err := c.send(faucet, nil, new(big.Int), kindGas[kindDeploy], cfg.gasPrice, counterCode)
+		if err != nil {
+			return nil, ethcmn.Address{}, fmt.Errorf("failed to deploy the counter contract: %v", err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	fmt.Fprintf(os.Stderr, "funding %d accounts from %s\n", cfg.accounts, faucet.addr.Hex())
+
+	accounts := make([]*account, cfg.accounts)
+	for i := range accounts {
+		key, err := ethcrypto.GenerateKey()
+		if err != nil {
+			return nil, ethcmn.Address{}, err
+		}
+
+		accounts[i] = newAccount(key)
+
+		hash, err := c.send(faucet, &accounts[i].addr, cfg.fund, kindGas[kindTransfer], cfg.gasPrice, nil)
+		if err != nil {
+			return nil, ethcmn.Address{}, fmt.Errorf("failed to fund %s: %v", accounts[i].addr.Hex(), err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	contract, err := waitSetup(c, cfg, hashes)
+	return accounts, contract, err
+}
+
+// waitSetup waits for the receipts of the given setup transactions, all of
+// which must have succeeded, and returns the address of the contract created
+// by any of them.
+func waitSetup(c *client, cfg config, hashes []ethcmn.Hash) (ethcmn.Address, error) {
+	var contract ethcmn.Address
+
+	deadline := time.Now().Add(cfg.timeout)
+	for _, hash := range hashes {
+		receipt, err := c.waitReceipt(hash, deadline, cfg.pollInterval)
+		if err != nil {
+			return ethcmn.Address{}, err
+		}
+
+		if receipt.Status != hexutil.Uint(ethtypes.ReceiptStatusSuccessful) {
+			return ethcmn.Address{}, fmt.Errorf("setup transaction %s failed", hash.Hex())
+		}
+
+		if receipt.ContractAddress != nil {
+			contract = *receipt.ContractAddress
+		}
+	}
+
+	return contract, nil
+}
+
+func hasKind(mix []txKind, kind txKind) bool {
+	for _, k := range mix {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spam has every account send its transactions concurrently, each account
+// transferring to the next one and calling the given contract, and returns
+// the transactions sent once their receipts are served or timed out.
+func spam(c *client, cfg config, accounts []*account, contract ethcmn.Address) []*sentTx {
+	lim := newLimiter(cfg.rate)
+	defer lim.stop()
+
+	sent := make([][]*sentTx, len(accounts))
+
+	var wg sync.WaitGroup
+	for i := range accounts {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			to := accounts[(i+1)%len(accounts)].addr
+			sent[i] = sendAll(c, cfg, lim, accounts[i], to, contract, i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	var all []*sentTx
+	for _, txs := range sent {
+		all = append(all, txs...)
+	}
+
+	return all
+}
+
+// sendAll sends the transactions of an account, starting at the given offset
+// into the mix so that accounts interleave kinds, while polling for the
+// receipts of the transactions already accepted.
+func sendAll(
+	c *client, cfg config, lim *limiter, from *account, to, contract ethcmn.Address, offset int,
+) []*sentTx {
+
+	accepted := make(chan *sentTx, cfg.txs)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for tx := range accepted {
+			tx.receipt, tx.receiptErr = c.waitReceipt(tx.hash, tx.sentAt.Add(cfg.timeout), cfg.pollInterval)
+			tx.confirmedAt = time.Now()
+		}
+	}()
+
+	txs := make([]*sentTx, cfg.txs)
+	for i := range txs {
+		lim.wait()
+
+		txs[i] = sendKind(c, cfg, from, cfg.mix[(offset+i)%len(cfg.mix)], to, contract)
+		if txs[i].err == nil {
+			accepted <- txs[i]
+		}
+	}
+
+	close(accepted)
+	<-done
+
+	return txs
+}
+
+// sendKind sends a transaction of the given kind from an account: a transfer
+// of one unit to the given address, a deployment of the counter contract or
+// a call to the given contract.
+func sendKind(c *client, cfg config, from *account, kind txKind, to, contract ethcmn.Address) *sentTx {
+	var (
+		recipient = &to
+		value     = big.NewInt(1)
+		data      []byte
+	)
+
+	switch kind {
+	case kindDeploy:
+		recipient, value, data = nil, new(big.Int), counterCode
+
+	case kindCall:
+		recipient, value = &contract, new(big.Int)
+	}
+
+	tx := &sentTx{kind: kind, sentAt: time.Now()}
+	tx.hash, tx.err = c.send(from, recipient, value, kindGas[kind], cfg.gasPrice, data)
+	tx.acceptedAt = time.Now()
+
+	return tx
+}
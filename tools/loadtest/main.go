@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/cosmos/ethermint/app"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagNode         = "node"
+	flagFaucetKey    = "faucet-key"
+	flagChainID      = "chain-id"
+	flagAccounts     = "accounts"
+	flagTxs          = "txs"
+	flagMix          = "mix"
+	flagRate         = "rate"
+	flagGasPrice     = "gas-price"
+	flagFund         = "fund"
+	flagTimeout      = "timeout"
+	flagPollInterval = "poll-interval"
+)
+
+func main() {
+	var (
+		cfg                          config
+		faucetKey, mix, price, funds string
+		chainID                      int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Send a load of Ethereum transactions to a node and report their latency and throughput",
+		Long: `Send a load of Ethereum transactions to a node and report their latency and throughput.
+
+New accounts are generated and funded by the faucet key, which also deploys a
+counter contract if calls are sent. Every account then sends its transactions,
+of the kinds given by the mix in turn, to the node's JSON-RPC endpoint, at the
+given overall rate if any, and polls for their receipts. The time taken by the
+node to accept a transaction and the time until its receipt is served are
+reported, along with the rates at which transactions were sent and confirmed.`,
+		Example: "loadtest --node http://localhost:8545 --faucet-key faucet.hex --accounts 20 --txs 50 " +
+			"--mix transfer=8,deploy=1,call=1 --rate 200",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var err error
+
+			if cfg.faucetKey, err = ethcrypto.LoadECDSA(faucetKey); err != nil {
+				return fmt.Errorf("invalid private key in %s: %v", faucetKey, err)
+			}
+
+			if cfg.mix, err = parseMix(mix); err != nil {
+				return err
+			}
+
+			if cfg.gasPrice, err = parseAmount(flagGasPrice, price); err != nil {
+				return err
+			}
+
+			if cfg.fund, err = parseAmount(flagFund, funds); err != nil {
+				return err
+			}
+
+			if cfg.accounts <= 0 || cfg.txs < 0 {
+				return fmt.Errorf("--%s must be positive and --%s non-negative", flagAccounts, flagTxs)
+			}
+
+			cfg.chainID = big.NewInt(chainID)
+
+			rep, err := run(cfg)
+			if err != nil {
+				return err
+			}
+
+			rep.print(os.Stdout)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.node, flagNode, "http://localhost:8545", "JSON-RPC endpoint to send the transactions to")
+	cmd.Flags().StringVar(&faucetKey, flagFaucetKey, "", "path to a file holding the hex encoded private key funding the accounts")
+	cmd.Flags().Int64Var(&chainID, flagChainID, app.DefaultEthChainID, "Ethereum chain ID to sign for")
+	cmd.Flags().IntVar(&cfg.accounts, flagAccounts, 10, "number of accounts sending transactions concurrently")
+	cmd.Flags().IntVar(&cfg.txs, flagTxs, 100, "number of transactions sent by each account")
+	cmd.Flags().StringVar(
+		&mix, flagMix, "transfer=8,deploy=1,call=1",
+		"comma separated weights of the kinds of transactions sent, among transfer, deploy and call",
+	)
+	cmd.Flags().Float64Var(&cfg.rate, flagRate, 0, "overall rate, in transactions per second, to send at; 0 sends as fast as possible")
+	cmd.Flags().StringVar(&price, flagGasPrice, "1", "gas price of the transactions, in the native denom's base unit")
+	cmd.Flags().StringVar(&funds, flagFund, "1000000000000", "amount the faucet funds each account with")
+	cmd.Flags().DurationVar(&cfg.timeout, flagTimeout, time.Minute, "time to wait for the receipt of a transaction")
+	cmd.Flags().DurationVar(&cfg.pollInterval, flagPollInterval, 100*time.Millisecond, "interval between receipt polls")
+
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// parseAmount returns the value of the given flag parsed as a non-negative
+// integer.
+func parseAmount(name, s string) (*big.Int, error) {
+	x, ok := new(big.Int).SetString(s, 0)
+	if !ok || x.Sign() == -1 {
+		return nil, fmt.Errorf("invalid --%s %s", name, s)
+	}
+
+	return x, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// kindStats defines the outcome of the transactions of a kind sent by the
+// load test.
+type kindStats struct {
+	sent        int
+	rejected    int
+	confirmed   int
+	failed      int
+	unconfirmed int
+}
+
+// report defines the outcome of a load test. The submission latency of a
+// transaction is the time taken by the node to accept it, and its
+// confirmation latency the time from sending it until its receipt was
+// served, bounded below by the receipt poll interval.
+type report struct {
+	kinds   [numKinds]kindStats
+	submit  []time.Duration
+	confirm []time.Duration
+
+	sendDuration    time.Duration
+	confirmDuration time.Duration
+	firstBlock      uint64
+	lastBlock       uint64
+
+	firstErr error
+}
+
+// newReport returns the report of the given transactions, sent from the given
+// start time.
+func newReport(txs []*sentTx, start time.Time) *report {
+	rep := &report{}
+
+	var lastAccepted, lastConfirmed time.Time
+	for _, tx := range txs {
+		if tx.acceptedAt.After(lastAccepted) {
+			lastAccepted = tx.acceptedAt
+		}
+
+		if tx.receipt != nil && tx.confirmedAt.After(lastConfirmed) {
+			lastConfirmed = tx.confirmedAt
+		}
+
+		rep.add(tx)
+	}
+
+	rep.sendDuration = lastAccepted.Sub(start)
+	if !lastConfirmed.IsZero() {
+		rep.confirmDuration = lastConfirmed.Sub(start)
+	}
+
+	sort.Slice(rep.submit, func(i, j int) bool { return rep.submit[i] < rep.submit[j] })
+	sort.Slice(rep.confirm, func(i, j int) bool { return rep.confirm[i] < rep.confirm[j] })
+
+	return rep
+}
+
+// add accounts for a transaction sent.
+func (rep *report) add(tx *sentTx) {
+	stats := &rep.kinds[tx.kind]
+	stats.sent++
+
+	switch {
+	case tx.err != nil:
+		stats.rejected++
+		rep.recordErr(tx.err)
+		return
+
+	case tx.receipt == nil:
+		stats.unconfirmed++
+		rep.recordErr(tx.receiptErr)
+
+	default:
+		stats.confirmed++
+		if tx.receipt.Status == 0 {
+			stats.failed++
+		}
+
+		rep.confirm = append(rep.confirm, tx.confirmedAt.Sub(tx.sentAt))
+		rep.addBlock(uint64(tx.receipt.BlockNumber))
+	}
+
+	rep.submit = append(rep.submit, tx.acceptedAt.Sub(tx.sentAt))
+}
+
+func (rep *report) recordErr(err error) {
+	if rep.firstErr == nil {
+		rep.firstErr = err
+	}
+}
+
+func (rep *report) addBlock(height uint64) {
+	if rep.firstBlock == 0 || height < rep.firstBlock {
+		rep.firstBlock = height
+	}
+
+	if height > rep.lastBlock {
+		rep.lastBlock = height
+	}
+}
+
+// print writes the report in a human readable form to the given writer.
+func (rep *report) print(w io.Writer) {
+	var total kindStats
+	for k := txKind(0); k < numKinds; k++ {
+		stats := rep.kinds[k]
+		if stats.sent == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%-10s %s\n", k.String()+":", stats)
+		total.sent += stats.sent
+		total.rejected += stats.rejected
+		total.confirmed += stats.confirmed
+		total.failed += stats.failed
+		total.unconfirmed += stats.unconfirmed
+	}
+
+	fmt.Fprintf(w, "%-10s %s\n\n", "total:", total)
+
+	fmt.Fprintf(w, "submission latency:   %s\n", latencies(rep.submit))
+	fmt.Fprintf(w, "confirmation latency: %s\n\n", latencies(rep.confirm))
+
+	fmt.Fprintf(
+		w, "sent:      %.1f tx/s (%d in %s)\n",
+		rate(total.sent-total.rejected, rep.sendDuration), total.sent-total.rejected, rep.sendDuration,
+	)
+	fmt.Fprintf(
+		w, "confirmed: %.1f tx/s (%d in %s, blocks %d to %d)\n",
+		rate(total.confirmed, rep.confirmDuration), total.confirmed, rep.confirmDuration, rep.firstBlock, rep.lastBlock,
+	)
+
+	if rep.firstErr != nil {
+		fmt.Fprintf(w, "\nfirst error: %v\n", rep.firstErr)
+	}
+}
+
+func (stats kindStats) String() string {
+	return fmt.Sprintf(
+		"%d sent, %d rejected, %d confirmed (%d failed), %d unconfirmed",
+		stats.sent, stats.rejected, stats.confirmed, stats.failed, stats.unconfirmed,
+	)
+}
+
+// latencies returns the 50th, 90th and 99th percentiles and the maximum of
+// the given sorted latencies.
+func latencies(sorted []time.Duration) string {
+	if len(sorted) == 0 {
+		return "n/a"
+	}
+
+	percentile := func(p int) time.Duration {
+		return sorted[(len(sorted)*p+99)/100-1]
+	}
+
+	return fmt.Sprintf(
+		"p50 %s, p90 %s, p99 %s, max %s",
+		percentile(50), percentile(90), percentile(99), sorted[len(sorted)-1],
+	)
+}
+
+// rate returns the given number of transactions per second of the given
+// duration.
+func rate(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return float64(n) / d.Seconds()
+}
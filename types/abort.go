@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Reasons an Ethereum transaction is aborted by the ante handler. They are
+// the errors go-ethereum returns for the same causes, as wallets match on them
+// textually, e.g. to resubmit a transaction with a higher nonce.
+const (
+	AbortNonceTooLow       = "nonce too low"
+	AbortNonceTooHigh      = "nonce too high"
+	AbortInsufficientFunds = "insufficient funds for gas * price + value"
+)
+
+// AnteAbort defines the machine-readable cause of an Ethereum transaction
+// being aborted by the ante handler. It is JSON encoded as the data of the
+// transaction's result, so that clients need not parse its log. The sender's
+// nonce is always set while the cost of the transaction, i.e. its value plus
+// its gas limit times its gas price, and the sender's balance are only set if
+// the sender cannot afford the transaction.
+type AnteAbort struct {
+	Reason   string   `json:"reason"`
+	Nonce    uint64   `json:"nonce"`
+	Required *big.Int `json:"required,omitempty"`
+	Balance  *big.Int `json:"balance,omitempty"`
+}
+
+// abortError is an sdk.Error whose result carries the JSON encoded AnteAbort
+// causing it.
+type abortError struct {
+	sdkError
+
+	abort AnteAbort
+}
+
+// sdkError aliases sdk.Error so that it may be embedded without its field
+// name clashing with the interface's Error method.
+type sdkError = sdk.Error
+
+// NewAbortError returns an sdk.Error wrapping the given error whose result
+// additionally carries the given abort as its data.
+func NewAbortError(err sdk.Error, abort AnteAbort) sdk.Error {
+	return abortError{sdkError: err, abort: abort}
+}
+
+// Result implements the sdk.Error interface.
+func (err abortError) Result() sdk.Result {
+	bz, encErr := json.Marshal(err.abort)
+	if encErr != nil {
+		panic(encErr)
+	}
+
+	res := err.sdkError.Result()
+	res.Data = bz
+
+	return res
+}
+
+// DecodeAnteAbort decodes the AnteAbort carried as the data of a result. A
+// boolean is returned reflecting if the data holds an abort.
+func DecodeAnteAbort(data []byte) (AnteAbort, bool) {
+	var abort AnteAbort
+	if err := json.Unmarshal(data, &abort); err != nil || abort.Reason == "" {
+		return AnteAbort{}, false
+	}
+
+	return abort, true
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbortError(t *testing.T) {
+	abort := AnteAbort{
+		Reason: AbortInsufficientFunds, Nonce: 3, Required: big.NewInt(21001), Balance: big.NewInt(21000),
+	}
+
+	err := NewAbortError(sdk.ErrInsufficientCoins("insufficient funds"), abort)
+	require.Equal(t, sdk.CodeInsufficientCoins, err.Code())
+
+	res := err.Result()
+	require.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInsufficientCoins), res.Code)
+
+	decoded, ok := DecodeAnteAbort(res.Data)
+	require.True(t, ok)
+	require.Equal(t, abort, decoded)
+
+	testCases := [][]byte{nil, []byte("not an abort"), []byte(`{"nonce":1}`)}
+
+	for i, data := range testCases {
+		_, ok := DecodeAnteAbort(data)
+		require.False(t, ok, fmt.Sprintf("unexpected abort: test case #%d", i))
+	}
+}
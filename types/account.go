@@ -0,0 +1,73 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+var _ auth.Account = (*Account)(nil)
+
+// Account implements the auth.Account interface and embeds an
+// auth.BaseAccount type. It is compatible with the auth.AccountMapper and
+// additionally tracks the hash of the account's EVM code.
+type Account struct {
+	auth.BaseAccount
+
+	CodeHash []byte `json:"code_hash"`
+}
+
+// ProtoAccount defines the prototype function for an Ethereum account used by
+// the auth.AccountMapper.
+func ProtoAccount() auth.Account {
+	return &Account{}
+}
+
+// NewAccountWithAddress returns an Account with the given address and no
+// code, suitable for an externally owned account.
+func NewAccountWithAddress(addr sdk.AccAddress) Account {
+	return Account{BaseAccount: auth.NewBaseAccountWithAddress(addr)}
+}
+
+// GetAccounts returns the accounts an auth.AccountMapper stores under the
+// given store key at the given addresses, in the order of the addresses, with
+// a nil account for every address without one. Unlike fetching the accounts
+// one by one through the mapper, they are read in a single pass over the
+// store (see MultiGet), e.g. for a precompile or query serving many accounts.
+func GetAccounts(ctx sdk.Context, cdc *wire.Codec, key sdk.StoreKey, addrs []sdk.AccAddress) []auth.Account {
+	keys := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		keys[i] = auth.AddressStoreKey(addr)
+	}
+
+	accs := make([]auth.Account, len(addrs))
+	for i, bz := range MultiGet(ctx.KVStore(key), keys) {
+		if bz != nil {
+			cdc.MustUnmarshalBinaryBare(bz, &accs[i])
+		}
+	}
+
+	return accs
+}
+
+// IterateAccounts iterates, in ascending address order, over the accounts an
+// auth.AccountMapper stores under the given store key whose address starts
+// with the given prefix, or over all accounts if the prefix is empty, until
+// process returns true. Unlike the mapper's IterateAccounts, it may be
+// restricted to a range of addresses and releases its iterator once done.
+func IterateAccounts(
+	ctx sdk.Context, cdc *wire.Codec, key sdk.StoreKey, prefix []byte, process func(auth.Account) (stop bool),
+) {
+
+	it := sdk.KVStorePrefixIterator(ctx.KVStore(key), auth.AddressStoreKey(prefix))
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		var acc auth.Account
+		cdc.MustUnmarshalBinaryBare(it.Value(), &acc)
+
+		if process(acc) {
+			return
+		}
+	}
+}
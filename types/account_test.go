@@ -0,0 +1,185 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	testAccountAminoBinary = "939ce34c0a500a14756f45e3fa69347a9a973a725e3c98bc4db0b5a0120d0a0670686f746f6e12033130301a251624de64205d036a858ce89f844491762eb89e2bfbd50a4a0a0da658e4b2628b25b117ae09200228041204636f6465"
+	testAccountJSON        = `{"type":"ethermint/Account","value":{"BaseAccount":{"address":"cosmosaccaddr1w4h5tcl6dy684x5h8fe9u0ych3xmpddqu6j9x4","coins":[{"denom":"photon","amount":"100"}],"public_key":{"type":"tendermint/PubKeyEd25519","value":"XQNqhYzon4REkXYuuJ4r+9UKSgoNpljksmKLJbEXrgk="},"account_number":"1","sequence":"2"},"code_hash":"Y29kZQ=="}}`
+)
+
+func newTestCodec() *wire.Codec {
+	cdc := wire.NewCodec()
+
+	auth.RegisterWire(cdc)
+	RegisterWire(cdc)
+	wire.RegisterCrypto(cdc)
+
+	return cdc
+}
+
+func newTestAccount() auth.Account {
+	acc := NewAccountWithAddress(sdk.AccAddress(ethcmn.HexToAddress("0x756F45E3FA69347A9A973A725E3C98bC4db0b5a0").Bytes()))
+	acc.Coins = sdk.Coins{sdk.NewCoin(DenomDefault, 100)}
+	acc.PubKey = crypto.GenPrivKeyEd25519FromSecret([]byte("secret")).PubKey()
+	acc.AccountNumber = 1
+	acc.Sequence = 2
+	acc.CodeHash = []byte("code")
+
+	return &acc
+}
+
+func TestAccountAmino(t *testing.T) {
+	cdc := newTestCodec()
+	acc := newTestAccount()
+
+	bz, err := cdc.MarshalBinaryBare(acc)
+	require.Nil(t, err)
+	require.Equal(t, testAccountAminoBinary, hex.EncodeToString(bz))
+
+	var decodedAcc auth.Account
+	require.Nil(t, cdc.UnmarshalBinaryBare(bz, &decodedAcc))
+	require.Equal(t, acc, decodedAcc)
+}
+
+func TestAccountJSON(t *testing.T) {
+	cdc := newTestCodec()
+	acc := newTestAccount()
+
+	bz, err := cdc.MarshalJSON(acc)
+	require.Nil(t, err)
+	require.Equal(t, testAccountJSON, string(bz))
+
+	var decodedAcc auth.Account
+	require.Nil(t, cdc.UnmarshalJSON(bz, &decodedAcc))
+	require.Equal(t, acc, decodedAcc)
+}
+
+func TestAccountMapper(t *testing.T) {
+	cdc := newTestCodec()
+	keyAcc := sdk.NewKVStoreKey("acc")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	mapper := auth.NewAccountMapper(cdc, keyAcc, ProtoAccount)
+
+	addr := sdk.AccAddress(testAddr1.Bytes())
+	acc := mapper.NewAccountWithAddress(ctx, addr)
+	require.IsType(t, &Account{}, acc)
+
+	ethAcc := acc.(*Account)
+	ethAcc.CodeHash = []byte("code")
+	mapper.SetAccount(ctx, ethAcc)
+
+	storedAcc := mapper.GetAccount(ctx, addr)
+	require.Equal(t, ethAcc, storedAcc)
+}
+
+func TestGetAccounts(t *testing.T) {
+	cdc := newTestCodec()
+	keyAcc := sdk.NewKVStoreKey("acc")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	mapper := auth.NewAccountMapper(cdc, keyAcc, ProtoAccount)
+
+	addrs := []sdk.AccAddress{
+		sdk.AccAddress(ethcmn.HexToAddress("0x0200000000000000000000000000000000000000").Bytes()),
+		sdk.AccAddress(ethcmn.HexToAddress("0x0100000000000000000000000000000000000000").Bytes()),
+		sdk.AccAddress(ethcmn.HexToAddress("0x0300000000000000000000000000000000000000").Bytes()),
+	}
+
+	for _, addr := range addrs[:2] {
+		mapper.SetAccount(ctx, mapper.NewAccountWithAddress(ctx, addr))
+	}
+
+	accs := GetAccounts(ctx, cdc, keyAcc, []sdk.AccAddress{addrs[0], addrs[2], addrs[1], addrs[0]})
+	require.Len(t, accs, 4)
+
+	for i, addr := range []sdk.AccAddress{addrs[0], nil, addrs[1], addrs[0]} {
+		if addr == nil {
+			require.Nil(t, accs[i], fmt.Sprintf("unexpected account: index %d", i))
+			continue
+		}
+
+		require.Equal(t, mapper.GetAccount(ctx, addr), accs[i], fmt.Sprintf("unexpected account: index %d", i))
+	}
+
+	require.Empty(t, GetAccounts(ctx, cdc, keyAcc, nil))
+}
+
+func TestIterateAccounts(t *testing.T) {
+	cdc := newTestCodec()
+	keyAcc := sdk.NewKVStoreKey("acc")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	mapper := auth.NewAccountMapper(cdc, keyAcc, ProtoAccount)
+
+	addrs := []string{
+		"0x0200000000000000000000000000000000000000",
+		"0x0100000000000000000000000000000000000000",
+		"0x0102000000000000000000000000000000000000",
+	}
+
+	for _, addr := range addrs {
+		mapper.SetAccount(ctx, mapper.NewAccountWithAddress(ctx, sdk.AccAddress(ethcmn.HexToAddress(addr).Bytes())))
+	}
+
+	testCases := []struct {
+		prefix   []byte
+		stopAt   int
+		expected []string
+	}{
+		{nil, 0, []string{addrs[1], addrs[2], addrs[0]}},
+		{[]byte{0x01}, 0, []string{addrs[1], addrs[2]}},
+		{[]byte{0x01, 0x02}, 0, []string{addrs[2]}},
+		{[]byte{0x03}, 0, nil},
+		{nil, 2, []string{addrs[1], addrs[2]}},
+	}
+
+	for i, tc := range testCases {
+		var iterated []string
+
+		IterateAccounts(ctx, cdc, keyAcc, tc.prefix, func(acc auth.Account) bool {
+			require.IsType(t, &Account{}, acc)
+
+			iterated = append(iterated, ethcmn.BytesToAddress(acc.GetAddress()).Hex())
+			return len(iterated) == tc.stopAt
+		})
+
+		var expected []string
+		for _, addr := range tc.expected {
+			expected = append(expected, ethcmn.HexToAddress(addr).Hex())
+		}
+
+		require.Equal(t, expected, iterated, fmt.Sprintf("unexpected accounts: test case #%d", i))
+	}
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// ParseHexAddress parses a hex encoded Ethereum address with an optional 0x
+// prefix. Mixed-case addresses must carry a valid EIP-55 checksum, whereas
+// all lower-case or all upper-case addresses are accepted as unchecksummed.
+// An error is returned if the address is malformed or badly checksummed.
+func ParseHexAddress(s string) (ethcmn.Address, error) {
+	hexAddr := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+
+	if len(hexAddr) != 2*ethcmn.AddressLength {
+		return ethcmn.Address{}, fmt.Errorf(
+			"invalid address %s: expected %d hex characters, got %d", s, 2*ethcmn.AddressLength, len(hexAddr),
+		)
+	}
+
+	bz, err := hex.DecodeString(hexAddr)
+	if err != nil {
+		return ethcmn.Address{}, fmt.Errorf("invalid address %s: %v", s, err)
+	}
+
+	addr := ethcmn.BytesToAddress(bz)
+
+	if isMixedCase(hexAddr) {
+		if checksummed := ChecksumHex(addr); checksummed[2:] != hexAddr {
+			return ethcmn.Address{}, fmt.Errorf(
+				"invalid address checksum for %s: did you mean %s? (use an all lower-case address to skip the checksum)",
+				s, checksummed,
+			)
+		}
+	}
+
+	return addr, nil
+}
+
+// ParseAddress parses an account address given either as a hex encoded
+// Ethereum address, as accepted by ParseHexAddress, or as a bech32 encoded SDK
+// account address. Both encode the same 20 bytes, so that either form refers
+// to the same account.
+func ParseAddress(s string) (ethcmn.Address, error) {
+	if !strings.HasPrefix(strings.ToLower(s), sdk.Bech32PrefixAccAddr) {
+		return ParseHexAddress(s)
+	}
+
+	accAddr, err := sdk.AccAddressFromBech32(strings.ToLower(s))
+	if err != nil {
+		return ethcmn.Address{}, fmt.Errorf("invalid address %s: %v", s, err)
+	}
+
+	if len(accAddr) != ethcmn.AddressLength {
+		return ethcmn.Address{}, fmt.Errorf(
+			"invalid address %s: expected %d bytes, got %d", s, ethcmn.AddressLength, len(accAddr),
+		)
+	}
+
+	return ethcmn.BytesToAddress(accAddr), nil
+}
+
+// Bech32Address returns the bech32 encoding of an Ethereum address as an SDK
+// account address.
+func Bech32Address(addr ethcmn.Address) string {
+	return sdk.AccAddress(addr.Bytes()).String()
+}
+
+// ChecksumHex returns the 0x prefixed EIP-55 checksummed hex encoding of an
+// Ethereum address.
+func ChecksumHex(addr ethcmn.Address) string {
+	return addr.Hex()
+}
+
+// isMixedCase returns true if the given hex string contains both lower-case
+// and upper-case letters.
+func isMixedCase(s string) bool {
+	return strings.ToLower(s) != s && strings.ToUpper(s) != s
+}
+
+// HexAddress defines an Ethereum address that is JSON encoded using its EIP-55
+// checksum and validated using ParseHexAddress when JSON decoded. It is meant
+// for validating user supplied addresses, e.g. as JSON-RPC parameters.
+type HexAddress ethcmn.Address
+
+// Address returns the HexAddress as an Ethereum address.
+func (a HexAddress) Address() ethcmn.Address {
+	return ethcmn.Address(a)
+}
+
+// String implements the fmt.Stringer interface.
+func (a HexAddress) String() string {
+	return ChecksumHex(a.Address())
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (a HexAddress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *HexAddress) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+
+	addr, err := ParseHexAddress(s)
+	if err != nil {
+		return err
+	}
+
+	*a = HexAddress(addr)
+	return nil
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHexAddress(t *testing.T) {
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	expectedAddr := ethcmn.HexToAddress(checksummed)
+
+	testCases := []struct {
+		input     string
+		expectErr bool
+	}{
+		{checksummed, false},
+		{strings.TrimPrefix(checksummed, "0x"), false},
+		{strings.ToLower(checksummed), false},
+		{"0x" + strings.ToUpper(checksummed[2:]), false},
+		{"0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", true},
+		{"", true},
+	}
+
+	for i, tc := range testCases {
+		addr, err := ParseHexAddress(tc.input)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, expectedAddr, addr, fmt.Sprintf("unexpected address: test case #%d", i))
+	}
+}
+
+func TestParseHexAddressChecksumError(t *testing.T) {
+	_, err := ParseHexAddress("0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	require.NotNil(t, err)
+
+	// the error suggests the correctly checksummed address
+	require.Contains(t, err.Error(), "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+}
+
+func TestParseAddress(t *testing.T) {
+	expectedAddr := ethcmn.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	bech32Addr := Bech32Address(expectedAddr)
+
+	require.True(t, strings.HasPrefix(bech32Addr, sdk.Bech32PrefixAccAddr))
+
+	testCases := []struct {
+		input     string
+		expectErr bool
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		{"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", false},
+		{bech32Addr, false},
+		{strings.ToUpper(bech32Addr), false},
+		{"0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{bech32Addr[:len(bech32Addr)-1], true},
+		{sdk.AccAddress([]byte("short")).String(), true},
+		{"", true},
+	}
+
+	for i, tc := range testCases {
+		addr, err := ParseAddress(tc.input)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, expectedAddr, addr, fmt.Sprintf("unexpected address: test case #%d", i))
+	}
+}
+
+func TestHexAddressJSON(t *testing.T) {
+	addr := HexAddress(ethcmn.HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"))
+
+	bz, err := json.Marshal(addr)
+	require.Nil(t, err)
+	require.Equal(t, `"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"`, string(bz))
+
+	var decodedAddr HexAddress
+	require.Nil(t, json.Unmarshal(bz, &decodedAddr))
+	require.Equal(t, addr, decodedAddr)
+
+	require.NotNil(t, json.Unmarshal([]byte(`"0xfb6916095ca1df60bB79Ce92cE3Ea74c37c5d359"`), &decodedAddr))
+	require.NotNil(t, json.Unmarshal([]byte(`1`), &decodedAddr))
+}
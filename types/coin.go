@@ -0,0 +1,31 @@
+package types
+
+import (
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DenomDefault defines the default denomination of the native EVM asset
+	// that is used to pay for gas and block rewards.
+	DenomDefault = "photon"
+)
+
+// EVMBalance returns the amount of the given coins of the given denom, the
+// EVM-native denom, as seen by the EVM.
+func EVMBalance(coins sdk.Coins, denom string) *big.Int {
+	return coins.AmountOf(denom).BigInt()
+}
+
+// SetEVMBalance returns the given coins with the amount of the given denom,
+// the EVM-native denom, replaced by the given balance. The amounts of other
+// denoms are left untouched.
+func SetEVMBalance(coins sdk.Coins, denom string, balance *big.Int) sdk.Coins {
+	delta := sdk.NewIntFromBigInt(balance).Sub(coins.AmountOf(denom))
+	if delta.IsZero() {
+		return coins
+	}
+
+	return coins.Plus(sdk.Coins{{Denom: denom, Amount: delta}})
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEVMBalance(t *testing.T) {
+	coins := sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(DenomDefault, 10)}
+
+	testCases := []struct {
+		balance  *big.Int
+		expected sdk.Coins
+	}{
+		{big.NewInt(10), coins},
+		{big.NewInt(25), sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(DenomDefault, 25)}},
+		{big.NewInt(3), sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin(DenomDefault, 3)}},
+		{big.NewInt(0), sdk.Coins{sdk.NewCoin("atom", 5)}},
+	}
+
+	for i, tc := range testCases {
+		res := SetEVMBalance(coins, DenomDefault, tc.balance)
+		require.Equal(t, tc.expected, res, fmt.Sprintf("unexpected coins: test case #%d", i))
+		require.Equal(t, tc.balance, EVMBalance(res, DenomDefault), fmt.Sprintf("unexpected balance: test case #%d", i))
+	}
+
+	require.Equal(t, big.NewInt(0), EVMBalance(coins, "stake"))
+	require.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5), sdk.NewCoin("stake", 1)}, SetEVMBalance(coins[:1], "stake", big.NewInt(1)))
+}
@@ -0,0 +1,221 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// EmbeddedTxAddress is the reserved address Ethereum transactions embedding
+// an SDK transaction in their payload are sent to. No account may be created
+// at this address.
+var EmbeddedTxAddress = ethcmn.HexToAddress("0x0000000000000000000000000000000000000100")
+
+// MaxEmbeddedMsgs is the maximum number of messages an EmbeddedTx may contain.
+// The number of messages is checked before they are decoded so that a payload
+// listing more cannot cause them to be allocated.
+const MaxEmbeddedMsgs = 64
+
+var _ sdk.Tx = EmbeddedTx{}
+
+// EmbeddedTx implements an SDK transaction. It is to be encoded into the
+// payload field of an Ethereum transaction sent to the EmbeddedTxAddress in
+// order to route and handle SDK messages. The Ethereum transaction pays for
+// the gas of all messages while each message signer signs the messages with
+// its own account number and sequence.
+type EmbeddedTx struct {
+	Messages   []sdk.Msg `json:"messages"`
+	Signatures [][]byte  `json:"signatures"`
+}
+
+// embeddedSignDoc defines the document signed by every signer of an
+// EmbeddedTx.
+type embeddedSignDoc struct {
+	ChainID       string            `json:"chain_id"`
+	AccountNumber int64             `json:"account_number"`
+	Sequence      int64             `json:"sequence"`
+	Messages      []json.RawMessage `json:"messages"`
+}
+
+// NewEmbeddedTx returns a new EmbeddedTx containing the given messages and the
+// signatures of their signers, ordered as returned by GetSigners.
+func NewEmbeddedTx(msgs []sdk.Msg, sigs [][]byte) EmbeddedTx {
+	return EmbeddedTx{Messages: msgs, Signatures: sigs}
+}
+
+// GetMsgs implements the sdk.Tx interface.
+func (tx EmbeddedTx) GetMsgs() []sdk.Msg {
+	return tx.Messages
+}
+
+// GetSigners returns the unique signers of all the messages in the order in
+// which they first appear.
+func (tx EmbeddedTx) GetSigners() []sdk.AccAddress {
+	signers := NewAccAddressSet()
+	for _, msg := range tx.Messages {
+		for _, signer := range msg.GetSigners() {
+			signers.Add(signer)
+		}
+	}
+
+	return signers.Addresses()
+}
+
+// ValidateBasic implements the sdk.Tx interface. An EmbeddedTx must contain at
+// least one message, every message must be valid and there must be exactly
+// one signature per unique signer.
+func (tx EmbeddedTx) ValidateBasic() sdk.Error {
+	if len(tx.Messages) == 0 {
+		return ErrInvalidValue(DefaultCodespace, "embedded transaction contains no messages")
+	}
+
+	for _, msg := range tx.Messages {
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	if len(tx.Signatures) != len(tx.GetSigners()) {
+		return sdk.ErrUnauthorized("invalid number of embedded transaction signatures")
+	}
+
+	return nil
+}
+
+// EmbeddedSignBytes returns the bytes to be signed by a signer of an
+// EmbeddedTx with the given account number and sequence.
+func EmbeddedSignBytes(chainID string, accountNumber, sequence int64, msgs []sdk.Msg) []byte {
+	msgsBytes := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		msgsBytes[i] = json.RawMessage(msg.GetSignBytes())
+	}
+
+	bz, err := json.Marshal(embeddedSignDoc{
+		ChainID:       chainID,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		Messages:      msgsBytes,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// IsEmbeddedTx returns true if the Ethereum transaction is sent to the
+// EmbeddedTxAddress and thus carries an EmbeddedTx as its payload.
+func (tx *Transaction) IsEmbeddedTx() bool {
+	to := tx.To()
+	return to != nil && *to == EmbeddedTxAddress
+}
+
+// TxDecoder returns an sdk.TxDecoder that decodes a single RLP list into an
+// Ethereum Transaction, returning its EmbeddedTx instead if the transaction is
+// sent to the EmbeddedTxAddress. Any other bytes are decoded into an
+// auth.StdTx as the BaseApp's default decoder does. An EmbeddedTx containing
+// more than MaxEmbeddedMsgs messages or any message that is a transaction
+// itself, e.g. an Ethereum transaction embedding another EmbeddedTx, is
+// rejected.
+func TxDecoder(codec *wire.Codec) sdk.TxDecoder {
+	return func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		if kind, _, rest, err := rlp.Split(txBytes); err != nil || kind != rlp.List || len(rest) != 0 {
+			var tx auth.StdTx
+			if err := codec.UnmarshalBinary(txBytes, &tx); err != nil {
+				return nil, sdk.ErrTxDecode(err.Error())
+			}
+
+			return tx, nil
+		}
+
+		tx := new(Transaction)
+		if err := rlp.DecodeBytes(txBytes, tx); err != nil {
+			return nil, sdk.ErrTxDecode(err.Error())
+		}
+
+		if !tx.IsEmbeddedTx() {
+			return tx, nil
+		}
+
+		return decodeEmbeddedTx(codec, tx.Data())
+	}
+}
+
+// decodeEmbeddedTx decodes an amino encoded EmbeddedTx, failing before
+// decoding any message if it contains more than MaxEmbeddedMsgs messages.
+func decodeEmbeddedTx(codec *wire.Codec, bz []byte) (sdk.Tx, sdk.Error) {
+	numMsgs, err := countEmbeddedMsgs(codec, bz)
+	if err != nil {
+		return nil, sdk.ErrTxDecode(err.Error())
+	}
+
+	if numMsgs > MaxEmbeddedMsgs {
+		return nil, ErrTooManyMsgs(
+			DefaultCodespace, fmt.Sprintf("%d embedded messages exceed maximum of %d", numMsgs, MaxEmbeddedMsgs),
+		)
+	}
+
+	var embeddedTx EmbeddedTx
+	if err := codec.UnmarshalBinary(bz, &embeddedTx); err != nil {
+		return nil, sdk.ErrTxDecode(err.Error())
+	}
+
+	for i, msg := range embeddedTx.Messages {
+		if _, ok := msg.(sdk.Tx); ok {
+			return nil, ErrNestedTx(DefaultCodespace, fmt.Sprintf("embedded message #%d is a transaction", i))
+		}
+	}
+
+	return embeddedTx, nil
+}
+
+// countEmbeddedMsgs returns the number of messages of an amino encoded
+// EmbeddedTx without decoding them. Both fields of an EmbeddedTx are lists of
+// length prefixed elements, each element being preceded by its field's key,
+// so that messages are counted by skipping over the elements.
+func countEmbeddedMsgs(codec *wire.Codec, bz []byte) (int, error) {
+	size, n := binary.Uvarint(bz)
+	if n <= 0 || size != uint64(len(bz)-n) {
+		return 0, errors.New("invalid embedded transaction length prefix")
+	}
+
+	// the fields follow the registered type prefix of an EmbeddedTx
+	prefix := codec.MustMarshalBinaryBare(EmbeddedTx{})
+	if !bytes.HasPrefix(bz[n:], prefix) {
+		return 0, errors.New("invalid embedded transaction type prefix")
+	}
+
+	bz = bz[n+len(prefix):]
+
+	var numMsgs int
+	for len(bz) > 0 {
+		key, n := binary.Uvarint(bz)
+		if n <= 0 || key&0x07 != 2 {
+			return 0, errors.New("invalid embedded transaction field")
+		}
+
+		bz = bz[n:]
+
+		size, n := binary.Uvarint(bz)
+		if n <= 0 || size > uint64(len(bz)-n) {
+			return 0, errors.New("invalid embedded transaction field length")
+		}
+
+		bz = bz[n+int(size):]
+
+		if key>>3 == 1 {
+			numMsgs++
+		}
+	}
+
+	return numMsgs, nil
+}
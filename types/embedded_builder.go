@@ -0,0 +1,124 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcore "github.com/ethereum/go-ethereum/core"
+)
+
+// EmbeddedTxBuilder implements a fluent builder of Ethereum transactions
+// embedding an SDK transaction. It takes care of signing the messages,
+// marshaling the EmbeddedTx into the payload and addressing the Ethereum
+// transaction to the EmbeddedTxAddress. Any error encountered is deferred
+// until the transaction is built.
+type EmbeddedTxBuilder struct {
+	codec   *wire.Codec
+	chainID string
+
+	msgs []sdk.Msg
+	sigs [][]byte
+
+	nonce    uint64
+	gasLimit uint64
+	gasPrice *big.Int
+
+	err error
+}
+
+// NewEmbeddedTxBuilder returns a reference to a new EmbeddedTxBuilder of a
+// transaction containing the given messages. The codec must have the
+// EmbeddedTx and all message types registered and the chain ID is the one
+// messages are signed for. By default, the Ethereum transaction has a zero
+// nonce, a gas price of one and a gas limit equal to its intrinsic gas.
+func NewEmbeddedTxBuilder(codec *wire.Codec, chainID string, msgs ...sdk.Msg) *EmbeddedTxBuilder {
+	return &EmbeddedTxBuilder{
+		codec:    codec,
+		chainID:  chainID,
+		msgs:     msgs,
+		gasPrice: big.NewInt(1),
+	}
+}
+
+// WithNonce sets the nonce of the Ethereum transaction.
+func (b *EmbeddedTxBuilder) WithNonce(nonce uint64) *EmbeddedTxBuilder {
+	b.nonce = nonce
+	return b
+}
+
+// WithGas sets the gas limit and gas price of the Ethereum transaction.
+func (b *EmbeddedTxBuilder) WithGas(gasLimit uint64, gasPrice *big.Int) *EmbeddedTxBuilder {
+	b.gasLimit = gasLimit
+	b.gasPrice = gasPrice
+	return b
+}
+
+// Sign signs the messages with a given private key on behalf of a signer with
+// the given account number and sequence. Signers must sign in the order
+// returned by the EmbeddedTx's GetSigners.
+func (b *EmbeddedTxBuilder) Sign(privKey crypto.PrivKeySecp256k1, accountNumber, sequence int64) *EmbeddedTxBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	sig, err := privKey.Sign(EmbeddedSignBytes(b.chainID, accountNumber, sequence, b.msgs))
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	b.sigs = append(b.sigs, sig.(crypto.SignatureSecp256k1))
+	return b
+}
+
+// EmbeddedTx returns the signed EmbeddedTx. An error is returned if signing
+// failed.
+func (b *EmbeddedTxBuilder) EmbeddedTx() (EmbeddedTx, error) {
+	if b.err != nil {
+		return EmbeddedTx{}, b.err
+	}
+
+	return NewEmbeddedTx(b.msgs, b.sigs), nil
+}
+
+// Build returns an Ethereum transaction addressed to the EmbeddedTxAddress
+// with the signed EmbeddedTx as its payload, signed with the given Ethereum
+// chain ID and private key. An error is returned if signing or marshaling
+// failed.
+func (b *EmbeddedTxBuilder) Build(ethChainID *big.Int, privKey *ecdsa.PrivateKey) (*Transaction, error) {
+	tx, err := b.BuildUnsigned()
+	if err != nil {
+		return nil, err
+	}
+
+	tx.Sign(ethChainID, privKey)
+	return tx, nil
+}
+
+// BuildUnsigned returns the Ethereum transaction returned by Build without
+// signing it, e.g. for it to be signed offline. The messages remain signed
+// with the keys given to Sign.
+func (b *EmbeddedTxBuilder) BuildUnsigned() (*Transaction, error) {
+	embeddedTx, err := b.EmbeddedTx()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := b.codec.MarshalBinary(embeddedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := b.gasLimit
+	if gasLimit == 0 {
+		if gasLimit, err = ethcore.IntrinsicGas(payload, false, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewTransaction(b.nonce, EmbeddedTxAddress, big.NewInt(0), gasLimit, b.gasPrice, payload), nil
+}
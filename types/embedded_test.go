@@ -0,0 +1,228 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEmbeddedCodec() *wire.Codec {
+	cdc := newTestCodec()
+
+	sdk.RegisterWire(cdc)
+	bank.RegisterWire(cdc)
+
+	return cdc
+}
+
+func newTestMsgSend(from, to sdk.AccAddress) sdk.Msg {
+	coins := sdk.Coins{sdk.NewCoin(DenomDefault, 10)}
+	return bank.NewMsgSend([]bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(to, coins)})
+}
+
+func TestEmbeddedTxBuilder(t *testing.T) {
+	cdc := newTestEmbeddedCodec()
+
+	privKey1, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	privKey2, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	signer1 := sdk.AccAddress(privKey1.PubKey().Address())
+	signer2 := sdk.AccAddress(privKey2.PubKey().Address())
+
+	msgs := []sdk.Msg{newTestMsgSend(signer1, signer2), newTestMsgSend(signer2, signer1)}
+
+	tx, err := NewEmbeddedTxBuilder(cdc, "ethermint", msgs...).
+		WithNonce(5).
+		Sign(privKey1, 0, 1).
+		Sign(privKey2, 1, 7).
+		Build(testChainID, testPrivKey1)
+	require.Nil(t, err)
+
+	require.True(t, tx.IsEmbeddedTx())
+	require.Equal(t, uint64(5), tx.Nonce())
+	require.Equal(t, big.NewInt(1), tx.GasPrice())
+
+	sender, err := tx.VerifySig(testChainID)
+	require.Nil(t, err)
+	require.Equal(t, testAddr1, sender)
+
+	var embeddedTx EmbeddedTx
+	require.Nil(t, cdc.UnmarshalBinary(tx.Data(), &embeddedTx))
+	require.Nil(t, embeddedTx.ValidateBasic())
+	require.Equal(t, msgs, embeddedTx.GetMsgs())
+	require.Equal(t, []sdk.AccAddress{signer1, signer2}, embeddedTx.GetSigners())
+
+	accNums, seqs := []int64{0, 1}, []int64{1, 7}
+
+	for i, signer := range embeddedTx.GetSigners() {
+		signBytes := EmbeddedSignBytes("ethermint", accNums[i], seqs[i], msgs)
+
+		pubKey, err := ethcrypto.SigToPub(ethcrypto.Keccak256(signBytes), embeddedTx.Signatures[i])
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, signer.Bytes(), ethcrypto.PubkeyToAddress(*pubKey).Bytes(), fmt.Sprintf("unexpected signer: test case #%d", i))
+	}
+}
+
+func TestEmbeddedTxBuilderUnsigned(t *testing.T) {
+	cdc := newTestEmbeddedCodec()
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	signer := sdk.AccAddress(privKey.PubKey().Address())
+	builder := NewEmbeddedTxBuilder(cdc, "ethermint", newTestMsgSend(signer, signer)).WithNonce(5).Sign(privKey, 0, 1)
+
+	unsignedTx, err := builder.BuildUnsigned()
+	require.Nil(t, err)
+
+	_, err = unsignedTx.VerifySig(testChainID)
+	require.NotNil(t, err)
+
+	tx, err := builder.Build(testChainID, testPrivKey1)
+	require.Nil(t, err)
+
+	// signing the unsigned transaction yields the built transaction
+	unsignedTx.Sign(testChainID, testPrivKey1)
+	require.Equal(t, tx.Hash(), unsignedTx.Hash())
+}
+
+func TestEmbeddedTxValidateBasic(t *testing.T) {
+	signer1 := sdk.AccAddress(testAddr1.Bytes())
+	signer2 := sdk.AccAddress(testAddr2.Bytes())
+	sig := make([]byte, 65)
+
+	testCases := []struct {
+		tx        EmbeddedTx
+		expectErr bool
+	}{
+		{NewEmbeddedTx([]sdk.Msg{newTestMsgSend(signer1, signer2)}, [][]byte{sig}), false},
+		// signatures are required once per unique signer
+		{NewEmbeddedTx([]sdk.Msg{newTestMsgSend(signer1, signer2), newTestMsgSend(signer1, signer2)}, [][]byte{sig}), false},
+		{NewEmbeddedTx(nil, nil), true},
+		{NewEmbeddedTx([]sdk.Msg{newTestMsgSend(signer1, signer2)}, nil), true},
+		{NewEmbeddedTx([]sdk.Msg{newTestMsgSend(signer1, signer2)}, [][]byte{sig, sig}), true},
+		{NewEmbeddedTx([]sdk.Msg{bank.NewMsgSend(nil, nil)}, nil), true},
+	}
+
+	for i, tc := range testCases {
+		err := tc.tx.ValidateBasic()
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
+
+func TestTxDecoder(t *testing.T) {
+	cdc := newTestEmbeddedCodec()
+
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	signer := sdk.AccAddress(privKey.PubKey().Address())
+	msg := newTestMsgSend(signer, signer)
+
+	embeddedEthTx, err := NewEmbeddedTxBuilder(cdc, "ethermint", msg).Sign(privKey, 0, 0).Build(testChainID, testPrivKey1)
+	require.Nil(t, err)
+
+	embeddedTxBytes, err := rlp.EncodeToBytes(embeddedEthTx)
+	require.Nil(t, err)
+
+	ethTxBytes, err := rlp.EncodeToBytes(newTestTx(0))
+	require.Nil(t, err)
+
+	stdTxBytes := cdc.MustMarshalBinary(auth.NewStdTx([]sdk.Msg{msg}, auth.StdFee{}, nil, ""))
+
+	testCases := []struct {
+		txBytes      []byte
+		expectedType interface{}
+		expectErr    bool
+	}{
+		{ethTxBytes, &Transaction{}, false},
+		{embeddedTxBytes, EmbeddedTx{}, false},
+		{stdTxBytes, auth.StdTx{}, false},
+		{[]byte("invalid"), nil, true},
+	}
+
+	for i, tc := range testCases {
+		tx, err := TxDecoder(cdc)(tc.txBytes)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.IsType(t, tc.expectedType, tx, fmt.Sprintf("unexpected type: test case #%d", i))
+	}
+}
+
+// testNestedTxMsg is a message that is also a transaction.
+type testNestedTxMsg struct {
+	bank.MsgSend
+}
+
+func (msg testNestedTxMsg) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{msg.MsgSend}
+}
+
+func TestDecodeEmbeddedTx(t *testing.T) {
+	cdc := newTestEmbeddedCodec()
+	cdc.RegisterConcrete(testNestedTxMsg{}, "test/NestedTxMsg", nil)
+
+	signer := sdk.AccAddress(testAddr1.Bytes())
+	msg := newTestMsgSend(signer, signer)
+
+	newMsgs := func(n int) []sdk.Msg {
+		msgs := make([]sdk.Msg, n)
+		for i := range msgs {
+			msgs[i] = msg
+		}
+
+		return msgs
+	}
+
+	valid := cdc.MustMarshalBinary(NewEmbeddedTx(newMsgs(2), [][]byte{make([]byte, 65)}))
+
+	testCases := []struct {
+		bz           []byte
+		expectedMsgs int
+		expectedCode sdk.CodeType
+	}{
+		{valid, 2, sdk.CodeOK},
+		{cdc.MustMarshalBinary(NewEmbeddedTx(nil, nil)), 0, sdk.CodeOK},
+		{cdc.MustMarshalBinary(NewEmbeddedTx(newMsgs(MaxEmbeddedMsgs), nil)), MaxEmbeddedMsgs, sdk.CodeOK},
+		{cdc.MustMarshalBinary(NewEmbeddedTx(newMsgs(MaxEmbeddedMsgs+1), nil)), 0, CodeTooManyMsgs},
+		{cdc.MustMarshalBinary(NewEmbeddedTx([]sdk.Msg{msg, testNestedTxMsg{msg.(bank.MsgSend)}}, nil)), 0, CodeNestedTx},
+		{valid[:len(valid)-1], 0, sdk.CodeTxDecode},
+		{cdc.MustMarshalBinary(auth.NewStdTx(newMsgs(1), auth.StdFee{}, nil, "")), 0, sdk.CodeTxDecode},
+		{nil, 0, sdk.CodeTxDecode},
+	}
+
+	for i, tc := range testCases {
+		tx, err := decodeEmbeddedTx(cdc, tc.bz)
+
+		if tc.expectedCode != sdk.CodeOK {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			require.Equal(t, tc.expectedCode, err.Code(), fmt.Sprintf("unexpected code: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Len(t, tx.GetMsgs(), tc.expectedMsgs, fmt.Sprintf("unexpected messages: test case #%d", i))
+	}
+}
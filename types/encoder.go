@@ -0,0 +1,73 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Encodings of transactions broadcast to Tendermint, both of which are decoded
+// by the TxDecoder.
+const (
+	// TxEncodingRLP is the encoding of Ethereum transactions, broadcast as raw
+	// RLP, e.g. through eth_sendRawTransaction. SDK messages are broadcast in
+	// this encoding by embedding them into an Ethereum transaction (see
+	// EmbeddedTx).
+	TxEncodingRLP = "rlp"
+
+	// TxEncodingAmino is the encoding of SDK transactions, broadcast as an
+	// amino encoded auth.StdTx as by Cosmos SDK clients.
+	TxEncodingAmino = "amino"
+)
+
+// TxEncoder defines a function encoding a transaction into the bytes
+// broadcast to Tendermint, i.e. the inverse of the TxDecoder.
+type TxEncoder func(tx sdk.Tx) ([]byte, error)
+
+// NewTxEncoder returns the TxEncoder of the given encoding, using the given
+// codec to amino encode transactions. An error is returned if the encoding is
+// unknown.
+func NewTxEncoder(codec *wire.Codec, encoding string) (TxEncoder, error) {
+	switch encoding {
+	case TxEncodingRLP:
+		return RLPTxEncoder, nil
+
+	case TxEncodingAmino:
+		return AminoTxEncoder(codec), nil
+
+	default:
+		return nil, fmt.Errorf(
+			"unknown transaction encoding %q, expected %q or %q", encoding, TxEncodingRLP, TxEncodingAmino,
+		)
+	}
+}
+
+// RLPTxEncoder encodes an Ethereum Transaction as RLP. Any other transaction
+// is rejected, as SDK messages must be embedded into an Ethereum transaction
+// to be RLP encoded.
+func RLPTxEncoder(tx sdk.Tx) ([]byte, error) {
+	ethTx, ok := tx.(*Transaction)
+	if !ok {
+		return nil, fmt.Errorf("cannot RLP encode a transaction of type %T", tx)
+	}
+
+	return rlp.EncodeToBytes(ethTx)
+}
+
+// AminoTxEncoder returns a TxEncoder amino encoding an auth.StdTx with the
+// given codec. Any other transaction is rejected, as the TxDecoder decodes
+// amino encoded bytes into an auth.StdTx only.
+func AminoTxEncoder(codec *wire.Codec) TxEncoder {
+	return func(tx sdk.Tx) ([]byte, error) {
+		stdTx, ok := tx.(auth.StdTx)
+		if !ok {
+			return nil, fmt.Errorf("cannot amino encode a transaction of type %T", tx)
+		}
+
+		return codec.MarshalBinary(stdTx)
+	}
+}
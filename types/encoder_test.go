@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxEncoder(t *testing.T) {
+	cdc := newTestEmbeddedCodec()
+
+	signer := sdk.AccAddress(testAddr1.Bytes())
+	msg := newTestMsgSend(signer, signer)
+
+	ethTx := newTestTx(0)
+	stdTx := auth.NewStdTx([]sdk.Msg{msg}, auth.StdFee{}, nil, "memo")
+
+	embeddedEthTx, err := NewEmbeddedTxBuilder(cdc, "ethermint", msg).Build(testChainID, testPrivKey1)
+	require.Nil(t, err)
+
+	testCases := []struct {
+		encoding     string
+		tx           sdk.Tx
+		expectedType interface{}
+		expectErr    bool
+	}{
+		{TxEncodingRLP, ethTx, &Transaction{}, false},
+		{TxEncodingRLP, embeddedEthTx, EmbeddedTx{}, false},
+		{TxEncodingRLP, stdTx, nil, true},
+		{TxEncodingRLP, NewEmbeddedTx([]sdk.Msg{msg}, nil), nil, true},
+		{TxEncodingAmino, stdTx, auth.StdTx{}, false},
+		{TxEncodingAmino, ethTx, nil, true},
+		{TxEncodingAmino, NewEmbeddedTx([]sdk.Msg{msg}, nil), nil, true},
+	}
+
+	for i, tc := range testCases {
+		encoder, err := NewTxEncoder(cdc, tc.encoding)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		txBytes, err := encoder(tc.tx)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		// every encoded transaction is decoded by the TxDecoder
+		decodedTx, sdkErr := TxDecoder(cdc)(txBytes)
+		require.Nil(t, sdkErr, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.IsType(t, tc.expectedType, decodedTx, fmt.Sprintf("unexpected type: test case #%d", i))
+
+		if ethTx, ok := tc.tx.(*Transaction); ok {
+			var decodedEthTx Transaction
+			require.Nil(t, rlp.DecodeBytes(txBytes, &decodedEthTx))
+			require.Equal(t, ethTx.Hash(), decodedEthTx.Hash(), fmt.Sprintf("unexpected transaction: test case #%d", i))
+		} else {
+			require.Equal(t, tc.tx, decodedTx, fmt.Sprintf("unexpected transaction: test case #%d", i))
+		}
+	}
+
+	_, err = NewTxEncoder(cdc, "json")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace is the Codespace requested for Ethermint's errors. An
+	// application registers it upon construction and is assigned the next
+	// available codespace if it is already taken, e.g. by the bank module,
+	// raising its errors in the codespace it is assigned. Messages validate
+	// themselves in the DefaultCodespace as they are unaware of the
+	// application.
+	DefaultCodespace sdk.CodespaceType = 2
+
+	// Ethermint error codes
+	CodeInvalidValue     sdk.CodeType = 1
+	CodeInvalidChainID   sdk.CodeType = 2
+	CodeInvalidSender    sdk.CodeType = 3
+	CodeTxExpired        sdk.CodeType = 4
+	CodeTxTooLarge       sdk.CodeType = 5
+	CodeGasPriceTooLow   sdk.CodeType = 6
+	CodeIntrinsicGas     sdk.CodeType = 7
+	CodePayloadGas       sdk.CodeType = 8
+	CodeInitCodeSize     sdk.CodeType = 9
+	CodeTooManyMsgs      sdk.CodeType = 10
+	CodeConsensusFailure sdk.CodeType = 11
+	CodeNestedTx         sdk.CodeType = 12
+	CodeTxNotExecutable  sdk.CodeType = 13
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidValue:
+		return "invalid value"
+	case CodeInvalidChainID:
+		return "invalid chain ID"
+	case CodeInvalidSender:
+		return "could not derive sender from transaction"
+	case CodeTxExpired:
+		return "transaction expired"
+	case CodeTxTooLarge:
+		return "transaction too large"
+	case CodeGasPriceTooLow:
+		return "gas price too low"
+	case CodeIntrinsicGas:
+		return "gas limit below intrinsic gas"
+	case CodePayloadGas:
+		return "gas limit too low for payload size"
+	case CodeInitCodeSize:
+		return "init code too large"
+	case CodeTooManyMsgs:
+		return "too many messages"
+	case CodeConsensusFailure:
+		return "consensus failure candidate"
+	case CodeNestedTx:
+		return "nested transaction"
+	case CodeTxNotExecutable:
+		return "transaction cannot be executed"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidValue returns a standardized SDK error resulting from an invalid
+// value.
+func ErrInvalidValue(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidValue, msg)
+}
+
+// ErrInvalidChainID returns a standardized SDK error resulting from an invalid
+// chain ID.
+func ErrInvalidChainID(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidChainID, msg)
+}
+
+// ErrInvalidSender returns a standardized SDK error resulting from an invalid
+// transaction sender.
+func ErrInvalidSender(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidSender, msg)
+}
+
+// ErrTxExpired returns a standardized SDK error resulting from a transaction
+// that remained pending for longer than its time-to-live.
+func ErrTxExpired(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeTxExpired, msg)
+}
+
+// ErrTxTooLarge returns a standardized SDK error resulting from a transaction
+// exceeding the maximum transaction size.
+func ErrTxTooLarge(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeTxTooLarge, msg)
+}
+
+// ErrGasPriceTooLow returns a standardized SDK error resulting from a
+// transaction's gas price being below the minimum gas price.
+func ErrGasPriceTooLow(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeGasPriceTooLow, msg)
+}
+
+// ErrIntrinsicGas returns a standardized SDK error resulting from a
+// transaction's gas limit being below its intrinsic gas.
+func ErrIntrinsicGas(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeIntrinsicGas, msg)
+}
+
+// ErrPayloadGas returns a standardized SDK error resulting from a
+// transaction's gas limit being too low relative to the size of its payload.
+func ErrPayloadGas(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodePayloadGas, msg)
+}
+
+// ErrInitCodeSize returns a standardized SDK error resulting from a contract
+// creation exceeding the maximum init code size.
+func ErrInitCodeSize(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInitCodeSize, msg)
+}
+
+// ErrTooManyMsgs returns a standardized SDK error resulting from a
+// transaction containing more than the maximum number of messages.
+func ErrTooManyMsgs(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeTooManyMsgs, msg)
+}
+
+// ErrNestedTx returns a standardized SDK error resulting from a transaction
+// containing a message that is a transaction itself.
+func ErrNestedTx(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeNestedTx, msg)
+}
+
+// ErrTxNotExecutable returns a standardized SDK error resulting from an
+// Ethereum transaction the EVM cannot execute at all, e.g. as its sender
+// cannot pay for its gas, as opposed to an execution that fails.
+func ErrTxNotExecutable(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeTxNotExecutable, msg)
+}
+
+// ErrConsensusFailure returns a standardized SDK error resulting from an
+// unexpected panic while handling a message. As the panic may not occur on
+// every node, e.g. due to a node-specific bug, the message's execution is a
+// candidate for a consensus failure.
+func ErrConsensusFailure(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeConsensusFailure, msg)
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignedMessagePrefix defines the prefix of messages signed as of EIP-191's
+// version 0x45, i.e. as signed by personal_sign and eth_sign, which prevents
+// a signed message from being a valid transaction.
+const SignedMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// TextHash returns the hash of a message signed as of EIP-191, i.e. the
+// keccak256 hash of the message prefixed with SignedMessagePrefix and the
+// message's length in decimal.
+func TextHash(msg []byte) []byte {
+	return ethcrypto.Keccak256([]byte(fmt.Sprintf("%s%d", SignedMessagePrefix, len(msg))), msg)
+}
+
+// SignMessage signs an arbitrary message as of EIP-191 with the given private
+// key. The signature is of the [R || S || V] format where V is 27 or 28, as
+// returned by personal_sign.
+func SignMessage(priv *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+	sig, err := ethcrypto.Sign(TextHash(msg), priv)
+	if err != nil {
+		return nil, err
+	}
+
+	sig[64] += 27
+	return sig, nil
+}
+
+// RecoverMessageSigner returns the address of the account that signed an
+// arbitrary message as of EIP-191. The signature must be of the [R || S || V]
+// format where V is either 27 or 28, or 0 or 1 as some signers produce.
+func RecoverMessageSigner(msg, sig []byte) (ethcmn.Address, error) {
+	if len(sig) != 65 {
+		return ethcmn.Address{}, fmt.Errorf("invalid signature length %d; expected 65", len(sig))
+	}
+
+	sig = ethcmn.CopyBytes(sig)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	if sig[64] > 1 {
+		return ethcmn.Address{}, fmt.Errorf("invalid signature recovery ID %d", sig[64])
+	}
+
+	pub, err := ethcrypto.SigToPub(TextHash(msg), sig)
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+
+	return ethcrypto.PubkeyToAddress(*pub), nil
+}
+
+// VerifyMessage returns an error unless the given account signed an arbitrary
+// message as of EIP-191 with the given signature.
+func VerifyMessage(addr ethcmn.Address, msg, sig []byte) error {
+	signer, err := RecoverMessageSigner(msg, sig)
+	if err != nil {
+		return err
+	}
+
+	if signer != addr {
+		return fmt.Errorf("invalid signature: recovered signer %s does not match %s", ChecksumHex(signer), ChecksumHex(addr))
+	}
+
+	return nil
+}
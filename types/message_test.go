@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextHash(t *testing.T) {
+	// the hash signed by personal_sign for "hello"
+	require.Equal(
+		t, "0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750", hexutil.Encode(TextHash([]byte("hello"))),
+	)
+}
+
+func TestSignMessage(t *testing.T) {
+	msg := []byte("login to example.com at nonce 42")
+
+	sig, err := SignMessage(testPrivKey1, msg)
+	require.Nil(t, err)
+	require.Len(t, sig, 65)
+	require.True(t, sig[64] == 27 || sig[64] == 28)
+
+	// the signature of the unprefixed hash differs
+	rawSig, err := ethcrypto.Sign(ethcrypto.Keccak256(msg), testPrivKey1)
+	require.Nil(t, err)
+	require.NotEqual(t, rawSig[:64], sig[:64])
+
+	lowV := ethcmn.CopyBytes(sig)
+	lowV[64] -= 27
+
+	badV := ethcmn.CopyBytes(sig)
+	badV[64] = 29
+
+	testCases := []struct {
+		addr      ethcmn.Address
+		msg       []byte
+		sig       []byte
+		expectErr bool
+	}{
+		{testAddr1, msg, sig, false},
+		{testAddr1, msg, lowV, false},
+		{testAddr2, msg, sig, true},
+		{testAddr1, []byte("another message"), sig, true},
+		{testAddr1, msg, sig[:64], true},
+		{testAddr1, msg, badV, true},
+	}
+
+	for i, tc := range testCases {
+		err := VerifyMessage(tc.addr, tc.msg, tc.sig)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+	}
+
+	signer, err := RecoverMessageSigner(msg, sig)
+	require.Nil(t, err)
+	require.Equal(t, testAddr1, signer)
+}
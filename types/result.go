@@ -0,0 +1,62 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+type (
+	// Log defines a log emitted during EVM execution. Unlike an Ethereum log,
+	// it does not carry the block and transaction it belongs to as those are
+	// known to the client querying the transaction's result.
+	Log struct {
+		Address ethcmn.Address `json:"address"`
+		Topics  []ethcmn.Hash  `json:"topics"`
+		Data    []byte         `json:"data"`
+	}
+
+	// ResultData defines the output of an EVM execution. It is amino encoded
+	// into the Data of the execution's sdk.Result, allowing clients to decode
+	// the output of a transaction rather than parse its log. The contract
+	// address is only set upon contract creation, and the return data of a
+	// failed execution holds its revert data, if any. Failed is only set for
+	// an execution whose result does not otherwise reflect its failure, e.g.
+	// that of an Ethereum transaction, which pays for its gas regardless.
+	ResultData struct {
+		Ret             []byte         `json:"ret"`
+		ContractAddress ethcmn.Address `json:"contract_address"`
+		Logs            []Log          `json:"logs"`
+		GasUsed         uint64         `json:"gas_used"`
+		Failed          bool           `json:"failed"`
+	}
+)
+
+// NewLogs converts the logs emitted by the EVM into Logs.
+func NewLogs(ethLogs []*ethtypes.Log) []Log {
+	logs := make([]Log, len(ethLogs))
+	for i, log := range ethLogs {
+		logs[i] = Log{Address: log.Address, Topics: log.Topics, Data: log.Data}
+	}
+
+	return logs
+}
+
+// EncodeResultData returns the amino encoding of the given ResultData, as
+// set into an sdk.Result's Data.
+func EncodeResultData(cdc *wire.Codec, data ResultData) ([]byte, error) {
+	return cdc.MarshalBinary(data)
+}
+
+// DecodeResultData decodes the Data of an sdk.Result, as returned by
+// EncodeResultData.
+func DecodeResultData(cdc *wire.Codec, bz []byte) (ResultData, error) {
+	var data ResultData
+
+	if err := cdc.UnmarshalBinary(bz, &data); err != nil {
+		return ResultData{}, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultData(t *testing.T) {
+	cdc := wire.NewCodec()
+
+	ethLogs := []*ethtypes.Log{
+		{
+			Address:     testAddr1,
+			Topics:      []ethcmn.Hash{ethcmn.BytesToHash([]byte("topic"))},
+			Data:        []byte("data"),
+			BlockNumber: 10,
+		},
+	}
+
+	data := ResultData{
+		Ret:             []byte{0x01, 0x02},
+		ContractAddress: testAddr2,
+		Logs:            NewLogs(ethLogs),
+		GasUsed:         21000,
+	}
+
+	require.Equal(t, []Log{{testAddr1, ethLogs[0].Topics, []byte("data")}}, data.Logs)
+
+	bz, err := EncodeResultData(cdc, data)
+	require.Nil(t, err)
+
+	decoded, err := DecodeResultData(cdc, bz)
+	require.Nil(t, err)
+	require.Equal(t, data, decoded)
+
+	_, err = DecodeResultData(cdc, []byte{0xff})
+	require.NotNil(t, err)
+}
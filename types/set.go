@@ -0,0 +1,75 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// AccAddressSet implements a set of account addresses. Unlike ranging over a
+// map, whose order is randomized by the Go runtime, its addresses are always
+// returned in a deterministic order so that a set may be used wherever its
+// order affects state or sign bytes, e.g. when collecting the signers of a
+// transaction.
+type AccAddressSet struct {
+	index map[string]bool
+	addrs []sdk.AccAddress
+}
+
+// NewAccAddressSet returns a reference to a new AccAddressSet containing the
+// given addresses.
+func NewAccAddressSet(addrs ...sdk.AccAddress) *AccAddressSet {
+	set := &AccAddressSet{index: make(map[string]bool)}
+	for _, addr := range addrs {
+		set.Add(addr)
+	}
+
+	return set
+}
+
+// Add adds an address to the set. It returns false if the set already
+// contains the address.
+func (set *AccAddressSet) Add(addr sdk.AccAddress) bool {
+	if set.index[string(addr)] {
+		return false
+	}
+
+	set.index[string(addr)] = true
+	set.addrs = append(set.addrs, addr)
+
+	return true
+}
+
+// Has returns true if the set contains the given address.
+func (set *AccAddressSet) Has(addr sdk.AccAddress) bool {
+	return set.index[string(addr)]
+}
+
+// Len returns the number of addresses in the set.
+func (set *AccAddressSet) Len() int {
+	return len(set.addrs)
+}
+
+// Addresses returns the addresses of the set in the order in which they were
+// first added.
+func (set *AccAddressSet) Addresses() []sdk.AccAddress {
+	return append([]sdk.AccAddress(nil), set.addrs...)
+}
+
+// Sorted returns the addresses of the set sorted by their bytes.
+func (set *AccAddressSet) Sorted() []sdk.AccAddress {
+	addrs := set.Addresses()
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i], addrs[j]) < 0 })
+
+	return addrs
+}
+
+// SortHashes sorts the given hashes by their bytes in place, e.g. the keys
+// of a map, and returns them.
+func SortHashes(hashes []ethcmn.Hash) []ethcmn.Hash {
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	return hashes
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// determinismRuns is the number of runs compared by determinism tests. Map
+// iteration is randomized on every range, so that an order derived from a map
+// is bound to differ between a few runs.
+const determinismRuns = 50
+
+func newTestAccAddresses(n int) []sdk.AccAddress {
+	addrs := make([]sdk.AccAddress, n)
+	for i := range addrs {
+		// addresses are created in descending byte order
+		addrs[i] = sdk.AccAddress(ethcmn.BigToAddress(ethcmn.Big1).Bytes())
+		addrs[i][0] = byte(n - i)
+	}
+
+	return addrs
+}
+
+func TestAccAddressSet(t *testing.T) {
+	addrs := newTestAccAddresses(3)
+
+	set := NewAccAddressSet(addrs[1], addrs[0])
+	require.Equal(t, 2, set.Len())
+	require.True(t, set.Has(addrs[0]))
+	require.False(t, set.Has(addrs[2]))
+
+	require.True(t, set.Add(addrs[2]))
+	require.False(t, set.Add(addrs[1]))
+	require.Equal(t, 3, set.Len())
+
+	require.Equal(t, []sdk.AccAddress{addrs[1], addrs[0], addrs[2]}, set.Addresses())
+	require.Equal(t, []sdk.AccAddress{addrs[2], addrs[1], addrs[0]}, set.Sorted())
+
+	// the returned addresses do not alias the set
+	set.Addresses()[0] = addrs[2]
+	require.Equal(t, addrs[1], set.Addresses()[0])
+
+	require.Empty(t, NewAccAddressSet().Addresses())
+}
+
+func TestAccAddressSetDeterminism(t *testing.T) {
+	addrs := newTestAccAddresses(32)
+
+	expected := NewAccAddressSet(addrs...)
+
+	for i := 0; i < determinismRuns; i++ {
+		set := NewAccAddressSet(addrs...)
+		for _, addr := range addrs {
+			set.Add(addr)
+		}
+
+		require.Equal(t, expected.Addresses(), set.Addresses(), fmt.Sprintf("unexpected order: run #%d", i))
+		require.Equal(t, expected.Sorted(), set.Sorted(), fmt.Sprintf("unexpected sorted order: run #%d", i))
+	}
+}
+
+func TestEmbeddedTxGetSignersDeterminism(t *testing.T) {
+	addrs := newTestAccAddresses(16)
+
+	// every message is signed by two signers, the second being the first
+	// signer of the next message
+	coins := sdk.Coins{sdk.NewCoin(DenomDefault, 10)}
+
+	msgs := make([]sdk.Msg, len(addrs))
+	for i := range msgs {
+		msgs[i] = bank.NewMsgSend(
+			[]bank.Input{bank.NewInput(addrs[i], coins), bank.NewInput(addrs[(i+1)%len(addrs)], coins)}, nil,
+		)
+	}
+
+	tx := NewEmbeddedTx(msgs, nil)
+
+	for i := 0; i < determinismRuns; i++ {
+		require.Equal(t, addrs, tx.GetSigners(), fmt.Sprintf("unexpected signers: run #%d", i))
+	}
+}
+
+func TestSortHashes(t *testing.T) {
+	hashes := make([]ethcmn.Hash, 32)
+	index := make(map[ethcmn.Hash]bool)
+
+	for i := range hashes {
+		hashes[i] = ethcmn.BytesToHash([]byte{byte(i)})
+		index[hashes[i]] = true
+	}
+
+	for i := 0; i < determinismRuns; i++ {
+		keys := make([]ethcmn.Hash, 0, len(index))
+		for hash := range index {
+			keys = append(keys, hash)
+		}
+
+		require.Equal(t, hashes, SortHashes(keys), fmt.Sprintf("unexpected order: run #%d", i))
+	}
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MultiGet returns the values of the given keys in a store, in the order of
+// the keys, with a nil value for every key that is not set. The keys are read
+// in a single pass in ascending order, so that the lookups of neighbouring
+// keys in an IAVL tree share the nodes already loaded, and a key given more
+// than once is read, and charged for by a gas metered store, only once.
+func MultiGet(store sdk.KVStore, keys [][]byte) [][]byte {
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return bytes.Compare(keys[order[i]], keys[order[j]]) < 0 })
+
+	values := make([][]byte, len(keys))
+	for i, idx := range order {
+		if i > 0 && bytes.Equal(keys[idx], keys[order[i-1]]) {
+			values[idx] = values[order[i-1]]
+			continue
+		}
+
+		values[idx] = store.Get(keys[idx])
+	}
+
+	return values
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// recordingStore records the keys read from the KVStore it wraps.
+type recordingStore struct {
+	sdk.KVStore
+
+	reads [][]byte
+}
+
+func (rs *recordingStore) Get(key []byte) []byte {
+	rs.reads = append(rs.reads, key)
+	return rs.KVStore.Get(key)
+}
+
+func TestMultiGet(t *testing.T) {
+	key := sdk.NewKVStoreKey("test")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	kvStore := ms.GetKVStore(key)
+	kvStore.Set([]byte("a"), []byte("1"))
+	kvStore.Set([]byte("b"), []byte("2"))
+	kvStore.Set([]byte("c"), []byte("3"))
+
+	testCases := []struct {
+		keys           []string
+		expectedValues [][]byte
+		expectedReads  []string
+	}{
+		{[]string{}, [][]byte{}, nil},
+		{[]string{"a"}, [][]byte{[]byte("1")}, []string{"a"}},
+		{[]string{"c", "a", "b"}, [][]byte{[]byte("3"), []byte("1"), []byte("2")}, []string{"a", "b", "c"}},
+		{[]string{"b", "x", "b"}, [][]byte{[]byte("2"), nil, []byte("2")}, []string{"b", "x"}},
+	}
+
+	for i, tc := range testCases {
+		keys := make([][]byte, len(tc.keys))
+		for j, k := range tc.keys {
+			keys[j] = []byte(k)
+		}
+
+		rs := &recordingStore{KVStore: kvStore}
+		values := MultiGet(rs, keys)
+
+		var reads []string
+		for _, k := range rs.reads {
+			reads = append(reads, string(k))
+		}
+
+		require.Equal(t, tc.expectedValues, values, fmt.Sprintf("unexpected values: test case #%d", i))
+		require.Equal(t, tc.expectedReads, reads, fmt.Sprintf("unexpected reads: test case #%d", i))
+	}
+}
@@ -0,0 +1,577 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sync/atomic"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethsha "github.com/ethereum/go-ethereum/crypto/sha3"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TypeTxEthereum reflects an Ethereum Transaction type.
+	TypeTxEthereum = "Ethereum"
+)
+
+type (
+	// Transaction implements the Ethereum transaction structure as an exact
+	// copy. It implements the Cosmos sdk.Tx interface. Due to the private
+	// fields, it must be replicated here and cannot be embedded or used
+	// directly.
+	//
+	// NOTE: The transaction also implements the sdk.Msg interface to perform
+	// basic validation that is done in the BaseApp.
+	Transaction struct {
+		data TxData
+
+		// caches
+		hash atomic.Value
+		size atomic.Value
+		from atomic.Value
+	}
+
+	// TxData implements the Ethereum transaction data structure as an exact
+	// copy. It is used solely as intended in Ethereum abiding by the protocol.
+	TxData struct {
+		AccountNonce uint64          `json:"nonce"`
+		Price        *big.Int        `json:"gasPrice"`
+		GasLimit     uint64          `json:"gas"`
+		Recipient    *ethcmn.Address `json:"to" rlp:"nil"` // nil means contract creation
+		Amount       *big.Int        `json:"value"`
+		Payload      []byte          `json:"input"`
+
+		// signature values
+		V *big.Int `json:"v"`
+		R *big.Int `json:"r"`
+		S *big.Int `json:"s"`
+
+		// hash is only used when marshaling to JSON
+		Hash *ethcmn.Hash `json:"hash" rlp:"-"`
+	}
+
+	// txdataMarshaling defines the JSON representation of TxData. It mirrors
+	// the representation used by Ethereum in which all numeric values and
+	// byte slices are hex encoded.
+	txdataMarshaling struct {
+		AccountNonce hexutil.Uint64  `json:"nonce"`
+		Price        *hexutil.Big    `json:"gasPrice"`
+		GasLimit     hexutil.Uint64  `json:"gas"`
+		Recipient    *ethcmn.Address `json:"to"`
+		Amount       *hexutil.Big    `json:"value"`
+		Payload      hexutil.Bytes   `json:"input"`
+		V            *hexutil.Big    `json:"v"`
+		R            *hexutil.Big    `json:"r"`
+		S            *hexutil.Big    `json:"s"`
+		Hash         *ethcmn.Hash    `json:"hash"`
+	}
+
+	// sigCache is used to cache the derived sender and contains the chain ID
+	// used to derive it.
+	sigCache struct {
+		chainID *big.Int
+		from    ethcmn.Address
+	}
+)
+
+// NewTransaction returns a reference to a new Ethereum transaction.
+func NewTransaction(
+	nonce uint64, to ethcmn.Address, amount *big.Int,
+	gasLimit uint64, gasPrice *big.Int, payload []byte,
+) *Transaction {
+
+	return newTransaction(nonce, &to, amount, gasLimit, gasPrice, payload)
+}
+
+// NewContractCreation returns a reference to a new Ethereum transaction
+// designated for contract creation.
+func NewContractCreation(
+	nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, payload []byte,
+) *Transaction {
+
+	return newTransaction(nonce, nil, amount, gasLimit, gasPrice, payload)
+}
+
+func newTransaction(
+	nonce uint64, to *ethcmn.Address, amount *big.Int,
+	gasLimit uint64, gasPrice *big.Int, payload []byte,
+) *Transaction {
+
+	if len(payload) > 0 {
+		payload = ethcmn.CopyBytes(payload)
+	}
+
+	txData := TxData{
+		Recipient:    to,
+		AccountNonce: nonce,
+		Payload:      payload,
+		GasLimit:     gasLimit,
+		Amount:       new(big.Int),
+		Price:        new(big.Int),
+		V:            new(big.Int),
+		R:            new(big.Int),
+		S:            new(big.Int),
+	}
+
+	if amount != nil {
+		txData.Amount.Set(amount)
+	}
+	if gasPrice != nil {
+		txData.Price.Set(gasPrice)
+	}
+
+	return &Transaction{data: txData}
+}
+
+// TxData returns a copy of the Transaction's underlying TxData.
+func (tx *Transaction) TxData() TxData {
+	return tx.data
+}
+
+// Data returns a copy of the transaction's input data.
+func (tx *Transaction) Data() []byte {
+	return ethcmn.CopyBytes(tx.data.Payload)
+}
+
+// Gas returns the transaction's gas limit.
+func (tx *Transaction) Gas() uint64 {
+	return tx.data.GasLimit
+}
+
+// GasPrice returns a copy of the transaction's gas price.
+func (tx *Transaction) GasPrice() *big.Int {
+	return new(big.Int).Set(tx.data.Price)
+}
+
+// Value returns a copy of the amount transferred by the transaction.
+func (tx *Transaction) Value() *big.Int {
+	return new(big.Int).Set(tx.data.Amount)
+}
+
+// Nonce returns the transaction's account nonce.
+func (tx *Transaction) Nonce() uint64 {
+	return tx.data.AccountNonce
+}
+
+// To returns a copy of the recipient address of the transaction. It returns
+// nil if the transaction is a contract creation.
+func (tx *Transaction) To() *ethcmn.Address {
+	if tx.data.Recipient == nil {
+		return nil
+	}
+
+	to := *tx.data.Recipient
+	return &to
+}
+
+// Fee returns the maximum fee the sender may pay for the transaction, which is
+// gasPrice * gasLimit.
+func (tx *Transaction) Fee() *big.Int {
+	return new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+}
+
+// Cost returns the maximum amount the sender may be charged for the
+// transaction, which is value + gasPrice * gasLimit.
+func (tx *Transaction) Cost() *big.Int {
+	return new(big.Int).Add(tx.Fee(), tx.data.Amount)
+}
+
+// Sign calculates a secp256k1 ECDSA signature and signs the transaction. It
+// takes a private key and chainID to sign an Ethereum transaction according to
+// EIP155 standard. A zero chainID results in a transaction that is not replay
+// protected. It mutates the transaction as it populates the V, R, S fields of
+// the Transaction's signature.
+func (tx *Transaction) Sign(chainID *big.Int, priv *ecdsa.PrivateKey) {
+	h := tx.SigHash(chainID)
+
+	sig, err := ethcrypto.Sign(h[:], priv)
+	if err != nil {
+		panic(err)
+	}
+
+	if len(sig) != 65 {
+		panic(fmt.Sprintf("wrong size for signature: got %d, want 65", len(sig)))
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+
+	var v *big.Int
+	if chainID.Sign() == 0 {
+		v = new(big.Int).SetBytes([]byte{sig[64] + 27})
+	} else {
+		v = big.NewInt(int64(sig[64] + 35))
+		chainIDMul := new(big.Int).Mul(chainID, big.NewInt(2))
+		v.Add(v, chainIDMul)
+	}
+
+	tx.data.V = v
+	tx.data.R = r
+	tx.data.S = s
+
+	// invalidate any cached values as the signature has changed
+	tx.hash = atomic.Value{}
+	tx.size = atomic.Value{}
+	tx.from = atomic.Value{}
+}
+
+// EncodeRLP implements the rlp.Encoder interface.
+func (tx *Transaction) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &tx.data)
+}
+
+// DecodeRLP implements the rlp.Decoder interface.
+func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
+	_, size, _ := s.Kind()
+
+	err := s.Decode(&tx.data)
+	if err == nil {
+		tx.size.Store(ethcmn.StorageSize(rlp.ListSize(size)))
+	}
+
+	return err
+}
+
+// MarshalJSON implements the json.Marshaler interface. It encodes the
+// transaction in the same representation as Ethereum including the
+// transaction's hash.
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	hash := tx.Hash()
+
+	data := tx.data
+	data.Hash = &hash
+
+	return data.MarshalJSON()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (td TxData) MarshalJSON() ([]byte, error) {
+	enc := txdataMarshaling{
+		AccountNonce: hexutil.Uint64(td.AccountNonce),
+		Price:        (*hexutil.Big)(td.Price),
+		GasLimit:     hexutil.Uint64(td.GasLimit),
+		Recipient:    td.Recipient,
+		Amount:       (*hexutil.Big)(td.Amount),
+		Payload:      td.Payload,
+		V:            (*hexutil.Big)(td.V),
+		R:            (*hexutil.Big)(td.R),
+		S:            (*hexutil.Big)(td.S),
+		Hash:         td.Hash,
+	}
+
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It decodes a
+// transaction from its Ethereum JSON representation and verifies that the
+// signature values are valid. Any given hash is ignored.
+func (tx *Transaction) UnmarshalJSON(input []byte) error {
+	var data TxData
+	if err := data.UnmarshalJSON(input); err != nil {
+		return err
+	}
+
+	var v byte
+	if isProtectedV(data.V) {
+		chainID := deriveChainID(data.V)
+		v = byte(new(big.Int).Sub(data.V, new(big.Int).Mul(chainID, big.NewInt(2))).Uint64() - 35)
+	} else {
+		v = byte(data.V.Uint64() - 27)
+	}
+
+	if !ethcrypto.ValidateSignatureValues(v, data.R, data.S, false) {
+		return errors.New("invalid transaction v, r, s values")
+	}
+
+	data.Hash = nil
+	*tx = Transaction{data: data}
+
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The gas price,
+// value, input and signature fields are required.
+func (td *TxData) UnmarshalJSON(input []byte) error {
+	var dec txdataMarshaling
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+
+	switch {
+	case dec.Price == nil:
+		return errors.New("missing required field 'gasPrice' for TxData")
+	case dec.Amount == nil:
+		return errors.New("missing required field 'value' for TxData")
+	case dec.Payload == nil:
+		return errors.New("missing required field 'input' for TxData")
+	case dec.V == nil:
+		return errors.New("missing required field 'v' for TxData")
+	case dec.R == nil:
+		return errors.New("missing required field 'r' for TxData")
+	case dec.S == nil:
+		return errors.New("missing required field 's' for TxData")
+	}
+
+	*td = TxData{
+		AccountNonce: uint64(dec.AccountNonce),
+		Price:        (*big.Int)(dec.Price),
+		GasLimit:     uint64(dec.GasLimit),
+		Recipient:    dec.Recipient,
+		Amount:       (*big.Int)(dec.Amount),
+		Payload:      dec.Payload,
+		V:            (*big.Int)(dec.V),
+		R:            (*big.Int)(dec.R),
+		S:            (*big.Int)(dec.S),
+		Hash:         dec.Hash,
+	}
+
+	return nil
+}
+
+// Hash hashes the RLP encoding of a transaction.
+func (tx *Transaction) Hash() ethcmn.Hash {
+	if hash := tx.hash.Load(); hash != nil {
+		return hash.(ethcmn.Hash)
+	}
+
+	v := rlpHash(tx)
+	tx.hash.Store(v)
+
+	return v
+}
+
+// Size returns the true RLP encoded storage size of the transaction, either
+// by encoding and returning it, or returning a previously cached value.
+func (tx *Transaction) Size() ethcmn.StorageSize {
+	if size := tx.size.Load(); size != nil {
+		return size.(ethcmn.StorageSize)
+	}
+
+	c := writeCounter(0)
+	rlp.Encode(&c, &tx.data)
+	tx.size.Store(ethcmn.StorageSize(c))
+
+	return ethcmn.StorageSize(c)
+}
+
+// SigHash returns the RLP hash of a transaction with a given chainID used for
+// signing. If the chainID is zero, the hash of an unprotected (pre-EIP155)
+// transaction is returned.
+func (tx *Transaction) SigHash(chainID *big.Int) ethcmn.Hash {
+	if chainID.Sign() == 0 {
+		return rlpHash([]interface{}{
+			tx.data.AccountNonce,
+			tx.data.Price,
+			tx.data.GasLimit,
+			tx.data.Recipient,
+			tx.data.Amount,
+			tx.data.Payload,
+		})
+	}
+
+	return rlpHash([]interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		chainID, uint(0), uint(0),
+	})
+}
+
+// RawSignatureValues returns the V, R, S signature values of the transaction.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
+	return tx.data.V, tx.data.R, tx.data.S
+}
+
+// Protected returns a boolean reflecting if the transaction is replay
+// protected according to EIP155.
+func (tx *Transaction) Protected() bool {
+	return isProtectedV(tx.data.V)
+}
+
+// ChainID returns the chain ID the transaction was signed for. It returns zero
+// if the transaction is not replay protected.
+func (tx *Transaction) ChainID() *big.Int {
+	return deriveChainID(tx.data.V)
+}
+
+// VerifySig attempts to verify a Transaction's signature for a given chainID.
+// A derived address is returned upon success or an error if recovery fails.
+// Transactions that are not replay protected are verified independently of
+// the chainID. Whether such transactions are allowed is left to the caller,
+// see Sender. As on Ethereum since Homestead, malleable signatures, i.e. whose
+// S value lies in the upper half of the curve order, are rejected.
+func (tx *Transaction) VerifySig(chainID *big.Int) (ethcmn.Address, error) {
+	if sc := tx.from.Load(); sc != nil {
+		sigCache := sc.(sigCache)
+
+		// If the chainID used to derive from in a previous call is not the same
+		// as the one currently used, invalidate the cache.
+		if sigCache.chainID.Cmp(chainID) == 0 {
+			return sigCache.from, nil
+		}
+	}
+
+	// do not allow recovery for transactions with an unprotected chainID
+	if chainID.Sign() == 0 {
+		return ethcmn.Address{}, errors.New("invalid chainID")
+	}
+
+	sigChainID := new(big.Int)
+	if tx.Protected() {
+		sigChainID = chainID
+
+		if tx.ChainID().Cmp(chainID) != 0 {
+			return ethcmn.Address{}, errors.New("invalid chainID for signer")
+		}
+	}
+
+	// as from Homestead on, signatures in the upper range of S are rejected,
+	// which would otherwise allow the hash of a transaction to be altered
+	// without invalidating its signature
+	if !ethcrypto.ValidateSignatureValues(0, tx.data.R, tx.data.S, true) {
+		return ethcmn.Address{}, errors.New("invalid signature values")
+	}
+
+	txHash := tx.SigHash(sigChainID)
+	sig := recoverEthSig(tx.data.R, tx.data.S, tx.data.V, sigChainID)
+
+	pub, err := ethcrypto.Ecrecover(txHash[:], sig)
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+
+	var addr ethcmn.Address
+	copy(addr[:], ethcrypto.Keccak256(pub[1:])[12:])
+
+	tx.from.Store(sigCache{chainID: new(big.Int).Set(chainID), from: addr})
+	return addr, nil
+}
+
+// Sender returns the address derived from the transaction's signature for a
+// given chain configuration and block height. Transactions that are not replay
+// protected are only allowed if EIP155 is not yet active at the given height.
+func (tx *Transaction) Sender(config *ethparams.ChainConfig, height *big.Int) (ethcmn.Address, error) {
+	if !tx.Protected() && config.IsEIP155(height) {
+		return ethcmn.Address{}, errors.New("transaction is not replay protected")
+	}
+
+	return tx.VerifySig(config.ChainID)
+}
+
+// Type implements the sdk.Msg interface. It returns the type of the
+// Transaction.
+func (tx *Transaction) Type() string {
+	return TypeTxEthereum
+}
+
+// ValidateBasic implements the sdk.Msg interface. It performs basic validation
+// checks of a Transaction. If returns an sdk.Error if validation fails.
+func (tx *Transaction) ValidateBasic() sdk.Error {
+	if tx.data.Price.Sign() != 1 {
+		return ErrInvalidValue(DefaultCodespace, "price must be positive")
+	}
+
+	if tx.data.Amount.Sign() == -1 {
+		return ErrInvalidValue(DefaultCodespace, "amount cannot be negative")
+	}
+
+	return nil
+}
+
+// GetSignBytes performs a no-op and should not be used. It implements the
+// sdk.Msg Interface
+func (tx *Transaction) GetSignBytes() (sigBytes []byte) { return }
+
+// GetSigners performs a no-op and should not be used. Only a single signer
+// exists for an Ethereum transaction. It implements the sdk.Msg Interface.
+func (tx *Transaction) GetSigners() (signers []sdk.AccAddress) { return }
+
+// GetMsgs returns a single message containing the Transaction itself. It
+// implements the Cosmos sdk.Tx interface.
+func (tx *Transaction) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{tx}
+}
+
+// recoverEthSig recovers a signature according to the Ethereum specification.
+func recoverEthSig(R, S, Vb, chainID *big.Int) []byte {
+	var v byte
+
+	r, s := R.Bytes(), S.Bytes()
+	sig := make([]byte, 65)
+
+	copy(sig[32-len(r):32], r)
+	copy(sig[64-len(s):64], s)
+
+	if chainID.Sign() == 0 {
+		v = byte(Vb.Uint64() - 27)
+	} else {
+		chainIDMul := new(big.Int).Mul(chainID, big.NewInt(2))
+		V := new(big.Int).Sub(Vb, chainIDMul)
+
+		v = byte(V.Uint64() - 35)
+	}
+
+	sig[64] = v
+	return sig
+}
+
+// isProtectedV returns a boolean reflecting if a given V signature value is
+// replay protected according to EIP155.
+func isProtectedV(V *big.Int) bool {
+	if V.BitLen() <= 8 {
+		v := V.Uint64()
+		return v != 27 && v != 28
+	}
+
+	// anything not 27 or 28 is considered protected
+	return true
+}
+
+// deriveChainID derives the chain ID from a given EIP155 protected V signature
+// value.
+func deriveChainID(V *big.Int) *big.Int {
+	if V.BitLen() <= 64 {
+		v := V.Uint64()
+		if v == 27 || v == 28 {
+			return new(big.Int)
+		}
+
+		return new(big.Int).SetUint64((v - 35) / 2)
+	}
+
+	v := new(big.Int).Sub(V, big.NewInt(35))
+	return v.Div(v, big.NewInt(2))
+}
+
+func rlpHash(x interface{}) (h ethcmn.Hash) {
+	hasher := ethsha.NewKeccak256()
+
+	rlp.Encode(hasher, x)
+	hasher.Sum(h[:0])
+
+	return
+}
+
+// writeCounter counts the number of bytes written to it and is used to
+// compute the RLP encoded size of a transaction.
+type writeCounter ethcmn.StorageSize
+
+func (c *writeCounter) Write(b []byte) (int, error) {
+	*c += writeCounter(len(b))
+	return len(b), nil
+}
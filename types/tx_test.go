@@ -0,0 +1,438 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testChainID = big.NewInt(3)
+
+	testPrivKey1, _ = ethcrypto.GenerateKey()
+	testPrivKey2, _ = ethcrypto.GenerateKey()
+
+	testAddr1 = ethcrypto.PubkeyToAddress(testPrivKey1.PublicKey)
+	testAddr2 = ethcrypto.PubkeyToAddress(testPrivKey2.PublicKey)
+)
+
+func newTestTx(nonce uint64) *Transaction {
+	tx := NewTransaction(nonce, testAddr2, big.NewInt(10), 100, big.NewInt(100), []byte("test"))
+	tx.Sign(testChainID, testPrivKey1)
+
+	return tx
+}
+
+func TestTransactionRLPEncoding(t *testing.T) {
+	tx := newTestTx(0)
+
+	bz, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err)
+
+	decodedTx := new(Transaction)
+	require.Nil(t, rlp.DecodeBytes(bz, decodedTx))
+
+	require.Equal(t, tx.Hash(), decodedTx.Hash())
+	require.Equal(t, tx.Size(), decodedTx.Size())
+	require.Equal(t, tx.TxData(), decodedTx.TxData())
+}
+
+func TestTransactionVerifySig(t *testing.T) {
+	testCases := []struct {
+		tx           *Transaction
+		chainID      *big.Int
+		expectedAddr ethcmn.Address
+		expectErr    bool
+	}{
+		{newTestTx(0), testChainID, testAddr1, false},
+		{newTestTx(1), big.NewInt(4), ethcmn.Address{}, true},
+		{newTestTx(2), big.NewInt(0), ethcmn.Address{}, true},
+	}
+
+	for i, tc := range testCases {
+		addr, err := tc.tx.VerifySig(tc.chainID)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expectedAddr, addr, fmt.Sprintf("unexpected address: test case #%d", i))
+	}
+}
+
+func TestTransactionProtected(t *testing.T) {
+	tx := newTestTx(0)
+	require.True(t, tx.Protected())
+	require.Equal(t, testChainID, tx.ChainID())
+
+	v, r, s := tx.RawSignatureValues()
+	require.NotNil(t, v)
+	require.NotZero(t, r.Sign())
+	require.NotZero(t, s.Sign())
+
+	unprotectedTx := NewTransaction(0, testAddr2, big.NewInt(10), 100, big.NewInt(100), nil)
+	unprotectedTx.Sign(big.NewInt(0), testPrivKey1)
+	require.False(t, unprotectedTx.Protected())
+	require.Zero(t, unprotectedTx.ChainID().Sign())
+
+	// unprotected transactions are verified independently of the chainID
+	addr, err := unprotectedTx.VerifySig(testChainID)
+	require.Nil(t, err)
+	require.Equal(t, testAddr1, addr)
+}
+
+func TestTransactionSender(t *testing.T) {
+	legacyConfig := &ethparams.ChainConfig{ChainID: testChainID}
+	eip155Config := &ethparams.ChainConfig{ChainID: testChainID, EIP155Block: big.NewInt(10)}
+
+	unprotectedTx := NewTransaction(0, testAddr2, big.NewInt(10), 100, big.NewInt(100), nil)
+	unprotectedTx.Sign(big.NewInt(0), testPrivKey1)
+
+	testCases := []struct {
+		tx        *Transaction
+		config    *ethparams.ChainConfig
+		height    int64
+		expectErr bool
+	}{
+		{newTestTx(0), legacyConfig, 20, false},
+		{newTestTx(0), eip155Config, 20, false},
+		{unprotectedTx, legacyConfig, 20, false},
+		{unprotectedTx, eip155Config, 9, false},
+		{unprotectedTx, eip155Config, 10, true},
+	}
+
+	for i, tc := range testCases {
+		addr, err := tc.tx.Sender(tc.config, big.NewInt(tc.height))
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, testAddr1, addr, fmt.Sprintf("unexpected address: test case #%d", i))
+	}
+}
+
+func TestTransactionMarshalJSON(t *testing.T) {
+	tx := newTestTx(7)
+
+	bz, err := json.Marshal(tx)
+	require.Nil(t, err)
+
+	var fields map[string]interface{}
+	require.Nil(t, json.Unmarshal(bz, &fields))
+
+	require.Equal(t, tx.Hash().Hex(), fields["hash"])
+	require.Equal(t, "0x7", fields["nonce"])
+	require.Equal(t, "0x64", fields["gas"])
+	require.Equal(t, "0x64", fields["gasPrice"])
+	require.Equal(t, "0xa", fields["value"])
+	require.Equal(t, "0x74657374", fields["input"])
+	require.Equal(t, testAddr2.Hex(), ethcmn.HexToAddress(fields["to"].(string)).Hex())
+
+	// the hash must not leak into the transaction's own data
+	require.Nil(t, tx.TxData().Hash)
+}
+
+func TestTransactionUnmarshalJSON(t *testing.T) {
+	tx := newTestTx(7)
+
+	bz, err := json.Marshal(tx)
+	require.Nil(t, err)
+
+	decodedTx := new(Transaction)
+	require.Nil(t, json.Unmarshal(bz, decodedTx))
+	require.Equal(t, tx.Hash(), decodedTx.Hash())
+	require.Equal(t, tx.TxData(), decodedTx.TxData())
+
+	addr, err := decodedTx.VerifySig(testChainID)
+	require.Nil(t, err)
+	require.Equal(t, testAddr1, addr)
+
+	// contract creation omitting the recipient
+	contractTx := NewContractCreation(0, big.NewInt(0), 100, big.NewInt(1), []byte("code"))
+	contractTx.Sign(testChainID, testPrivKey1)
+
+	bz, err = json.Marshal(contractTx)
+	require.Nil(t, err)
+
+	decodedTx = new(Transaction)
+	require.Nil(t, json.Unmarshal(bz, decodedTx))
+	require.Nil(t, decodedTx.TxData().Recipient)
+	require.Equal(t, contractTx.Hash(), decodedTx.Hash())
+}
+
+func TestTransactionUnmarshalJSONInvalid(t *testing.T) {
+	testCases := []string{
+		`{"nonce":"0x1","gas":"0x64","value":"0x0","input":"0x","v":"0x1b","r":"0x1","s":"0x1"}`,
+		`{"nonce":"0x1","gasPrice":"0x1","gas":"0x64","input":"0x","v":"0x1b","r":"0x1","s":"0x1"}`,
+		`{"nonce":"0x1","gasPrice":"0x1","gas":"0x64","value":"0x0","v":"0x1b","r":"0x1","s":"0x1"}`,
+		`{"nonce":"0x1","gasPrice":"0x1","gas":"0x64","value":"0x0","input":"0x","r":"0x1","s":"0x1"}`,
+		`{"nonce":"0x1","gasPrice":"0x1","gas":"0x64","value":"0x0","input":"0x","v":"0x1b","r":"0x0","s":"0x1"}`,
+		`{"nonce":1,"gasPrice":"0x1","gas":"0x64","value":"0x0","input":"0x","v":"0x1b","r":"0x1","s":"0x1"}`,
+	}
+
+	for i, tc := range testCases {
+		tx := new(Transaction)
+		require.NotNil(t, json.Unmarshal([]byte(tc), tx), fmt.Sprintf("expected error: test case #%d", i))
+	}
+}
+
+func TestTransactionAccessors(t *testing.T) {
+	tx := NewTransaction(3, testAddr2, big.NewInt(10), 100, big.NewInt(2), []byte("test"))
+
+	require.Equal(t, uint64(3), tx.Nonce())
+	require.Equal(t, uint64(100), tx.Gas())
+	require.Equal(t, big.NewInt(2), tx.GasPrice())
+	require.Equal(t, big.NewInt(10), tx.Value())
+	require.Equal(t, []byte("test"), tx.Data())
+	require.Equal(t, testAddr2, *tx.To())
+	require.Equal(t, big.NewInt(200), tx.Fee())
+	require.Equal(t, big.NewInt(210), tx.Cost())
+
+	// returned values must not alias the transaction's data
+	tx.GasPrice().SetInt64(5)
+	tx.Value().SetInt64(5)
+	tx.Data()[0] = 'x'
+	*tx.To() = testAddr1
+
+	require.Equal(t, big.NewInt(210), tx.Cost())
+	require.Equal(t, []byte("test"), tx.Data())
+	require.Equal(t, testAddr2, *tx.To())
+
+	contractTx := NewContractCreation(0, big.NewInt(1), 10, big.NewInt(1), nil)
+	require.Nil(t, contractTx.To())
+	require.Equal(t, big.NewInt(11), contractTx.Cost())
+}
+
+func TestTransactionValidateBasic(t *testing.T) {
+	testCases := []struct {
+		tx        *Transaction
+		expectErr bool
+	}{
+		{NewTransaction(0, testAddr1, big.NewInt(0), 100, big.NewInt(1), nil), false},
+		{NewTransaction(0, testAddr1, big.NewInt(1), 100, big.NewInt(0), nil), true},
+		{NewTransaction(0, testAddr1, big.NewInt(-1), 100, big.NewInt(1), nil), true},
+	}
+
+	for i, tc := range testCases {
+		err := tc.tx.ValidateBasic()
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
+
+// randomBigInt returns a random non-negative integer of up to 256 bits,
+// favoring the zero and small values RLP encodes specially.
+func randomBigInt(r *rand.Rand) *big.Int {
+	switch r.Intn(4) {
+	case 0:
+		return new(big.Int)
+
+	case 1:
+		return big.NewInt(r.Int63n(256))
+
+	default:
+		bz := make([]byte, r.Intn(33))
+		r.Read(bz)
+
+		return new(big.Int).SetBytes(bz)
+	}
+}
+
+// randomTxData returns random transaction data which need not be validly
+// signed.
+func randomTxData(r *rand.Rand) TxData {
+	data := TxData{
+		AccountNonce: r.Uint64() >> uint(r.Intn(64)),
+		Price:        randomBigInt(r),
+		GasLimit:     r.Uint64() >> uint(r.Intn(64)),
+		Amount:       randomBigInt(r),
+		V:            randomBigInt(r),
+		R:            randomBigInt(r),
+		S:            randomBigInt(r),
+	}
+
+	if r.Intn(4) != 0 {
+		var to ethcmn.Address
+		r.Read(to[:])
+
+		data.Recipient = &to
+	}
+
+	if n := r.Intn(4); n != 0 {
+		data.Payload = make([]byte, r.Intn(64<<uint(n)))
+		r.Read(data.Payload)
+	}
+
+	return data
+}
+
+// requireSameTransaction requires an Ethermint transaction and an Ethereum
+// transaction to be identical both in content and encoding. Integers are
+// compared by value as their internal representations may differ.
+func requireSameTransaction(t *testing.T, tx *Transaction, ethTx *ethtypes.Transaction, msgAndArgs ...interface{}) {
+	require.Equal(t, tx.Nonce(), ethTx.Nonce(), msgAndArgs...)
+	require.Equal(t, tx.GasPrice().String(), ethTx.GasPrice().String(), msgAndArgs...)
+	require.Equal(t, tx.Gas(), ethTx.Gas(), msgAndArgs...)
+	require.Equal(t, tx.To(), ethTx.To(), msgAndArgs...)
+	require.Equal(t, tx.Value().String(), ethTx.Value().String(), msgAndArgs...)
+	require.True(t, bytes.Equal(tx.Data(), ethTx.Data()), msgAndArgs...)
+
+	v, r, s := tx.RawSignatureValues()
+	ethV, ethR, ethS := ethTx.RawSignatureValues()
+	require.Equal(t, fmt.Sprint(v, r, s), fmt.Sprint(ethV, ethR, ethS), msgAndArgs...)
+
+	bz, err := rlp.EncodeToBytes(tx)
+	require.Nil(t, err, msgAndArgs...)
+
+	ethBz, err := rlp.EncodeToBytes(ethTx)
+	require.Nil(t, err, msgAndArgs...)
+
+	require.Equal(t, ethBz, bz, msgAndArgs...)
+	require.Equal(t, ethTx.Hash(), tx.Hash(), msgAndArgs...)
+}
+
+// TestTransactionRLPDifferential differentially fuzzes the RLP encoding of
+// transactions against go-ethereum's, which must never diverge as Ethereum
+// clients and tooling sign and decode Ethermint transactions. Random
+// transactions are round-tripped through both encodings, after which random
+// mutations of their encodings must be accepted or rejected alike.
+func TestTransactionRLPDifferential(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		msg := fmt.Sprintf("unexpected encoding: test case #%d", i)
+		tx := &Transaction{data: randomTxData(r)}
+
+		bz, err := rlp.EncodeToBytes(tx)
+		require.Nil(t, err, msg)
+
+		ethTx := new(ethtypes.Transaction)
+		require.Nil(t, rlp.DecodeBytes(bz, ethTx), msg)
+		requireSameTransaction(t, tx, ethTx, msg)
+
+		ethBz, err := rlp.EncodeToBytes(ethTx)
+		require.Nil(t, err, msg)
+
+		decodedTx := new(Transaction)
+		require.Nil(t, rlp.DecodeBytes(ethBz, decodedTx), msg)
+		requireSameTransaction(t, decodedTx, ethTx, msg)
+
+		for j := 0; j < 10; j++ {
+			msg := fmt.Sprintf("unexpected decoding: test case #%d, mutation #%d", i, j)
+			mutated := mutateBytes(r, bz)
+
+			decodedTx, ethTx := new(Transaction), new(ethtypes.Transaction)
+			err, ethErr := rlp.DecodeBytes(mutated, decodedTx), rlp.DecodeBytes(mutated, ethTx)
+
+			require.Equal(t, ethErr == nil, err == nil, msg)
+			if err == nil {
+				requireSameTransaction(t, decodedTx, ethTx, msg)
+			}
+		}
+	}
+}
+
+// mutateBytes returns a copy of the given bytes with a random byte changed,
+// inserted or removed, or truncated at a random position.
+func mutateBytes(r *rand.Rand, bz []byte) []byte {
+	mutated := ethcmn.CopyBytes(bz)
+	pos := r.Intn(len(mutated))
+
+	switch r.Intn(4) {
+	case 0:
+		mutated[pos] = byte(r.Intn(256))
+
+	case 1:
+		mutated = append(mutated[:pos], append([]byte{byte(r.Intn(256))}, mutated[pos:]...)...)
+
+	case 2:
+		mutated = append(mutated[:pos], mutated[pos+1:]...)
+
+	default:
+		mutated = mutated[:pos]
+	}
+
+	return mutated
+}
+
+// txCorpusFile holds a corpus of signed Ethereum transactions in the forms
+// found on mainnet: the EIP-155 reference vectors, transactions of the test
+// suite of go-ethereum and transactions signed by go-ethereum before and after
+// EIP-155, including contract creations and signatures in the upper range of
+// S which only Frontier accepted. Further transactions may be appended along
+// with the hash and sender, if any, go-ethereum derives for them.
+const txCorpusFile = "testdata/eth_tx_corpus.json"
+
+type txCorpusEntry struct {
+	Description string          `json:"description"`
+	ChainID     uint64          `json:"chain_id"`
+	Raw         hexutil.Bytes   `json:"raw"`
+	Hash        ethcmn.Hash     `json:"hash"`
+	Sender      *ethcmn.Address `json:"sender"`
+}
+
+// TestTransactionCorpus replays the transaction corpus through the decoder and
+// signature verification, which must derive the same transaction, hash and
+// sender as go-ethereum does since Homestead, as a change to either would let
+// the hash of a transaction be altered or its sender be spoofed.
+func TestTransactionCorpus(t *testing.T) {
+	bz, err := ioutil.ReadFile(txCorpusFile)
+	require.Nil(t, err)
+
+	var corpus []txCorpusEntry
+	require.Nil(t, json.Unmarshal(bz, &corpus))
+	require.NotEmpty(t, corpus)
+
+	for i, entry := range corpus {
+		msg := fmt.Sprintf("%s: test case #%d", entry.Description, i)
+
+		tx, ethTx := new(Transaction), new(ethtypes.Transaction)
+		require.Nil(t, rlp.DecodeBytes(entry.Raw, tx), msg)
+		require.Nil(t, rlp.DecodeBytes(entry.Raw, ethTx), msg)
+
+		requireSameTransaction(t, tx, ethTx, msg)
+		require.Equal(t, entry.Hash, tx.Hash(), msg)
+
+		chainID := new(big.Int).SetUint64(entry.ChainID)
+
+		var signer ethtypes.Signer = ethtypes.HomesteadSigner{}
+		if ethTx.Protected() {
+			signer = ethtypes.NewEIP155Signer(chainID)
+		}
+
+		from, err := tx.VerifySig(chainID)
+		ethFrom, ethErr := ethtypes.Sender(signer, ethTx)
+
+		if entry.Sender == nil {
+			require.NotNil(t, err, msg)
+			require.NotNil(t, ethErr, msg)
+			continue
+		}
+
+		require.Nil(t, err, msg)
+		require.Nil(t, ethErr, msg)
+		require.Equal(t, *entry.Sender, from, msg)
+		require.Equal(t, ethFrom, from, msg)
+	}
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the concrete types defined by Ethermint. The
+// auth.Account and sdk.Msg interfaces must be registered with the codec
+// beforehand (e.g. through auth.RegisterWire and sdk.RegisterWire).
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(&Account{}, "ethermint/Account", nil)
+	cdc.RegisterConcrete(EmbeddedTx{}, "ethermint/EmbeddedTx", nil)
+}
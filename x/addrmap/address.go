@@ -0,0 +1,38 @@
+package addrmap
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+)
+
+// EthereumAddress returns the address Ethermint derives from a secp256k1
+// public key: the last 20 bytes of the keccak256 hash of the uncompressed key,
+// as in Ethereum.
+func EthereumAddress(pubKey crypto.PubKeySecp256k1) sdk.AccAddress {
+	return sdk.AccAddress(pubKey.Address())
+}
+
+// LegacyAddress returns the address a vanilla Cosmos chain derives from the
+// same secp256k1 public key: the ripemd160 hash of the sha256 hash of the
+// compressed key. It is the address holding a migrated user's balances from
+// before the user held an Ethermint account.
+func LegacyAddress(pubKey crypto.PubKeySecp256k1) sdk.AccAddress {
+	var key tmcrypto.PubKeySecp256k1
+	copy(key[:], pubKey)
+
+	return sdk.AccAddress(key.Address())
+}
+
+// validPubKey returns true if the given public key is a valid compressed
+// secp256k1 public key.
+func validPubKey(pubKey crypto.PubKeySecp256k1) bool {
+	if len(pubKey) != tmcrypto.PubKeySecp256k1Size {
+		return false
+	}
+
+	_, err := ethcrypto.DecompressPubkey(pubKey)
+	return err == nil
+}
@@ -0,0 +1,36 @@
+package addrmap
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the address map module.
+	DefaultCodespace sdk.CodespaceType = 15
+
+	// Address map error codes
+	CodeInvalidPubKey sdk.CodeType = 1
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidPubKey:
+		return "invalid secp256k1 public key"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidPubKey returns a standardized SDK error resulting from a public key
+// that is not a valid compressed secp256k1 public key.
+func ErrInvalidPubKey(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidPubKey, "")
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
@@ -0,0 +1,30 @@
+package addrmap
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHandler returns a handler for address map module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgLinkLegacyAddress:
+			return handleMsgLinkLegacyAddress(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized address map message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgLinkLegacyAddress(ctx sdk.Context, k Keeper, msg MsgLinkLegacyAddress) sdk.Result {
+	_, tags, err := k.Link(ctx, msg.PubKey)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{Tags: tags}
+}
@@ -0,0 +1,106 @@
+package addrmap
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+)
+
+// StoreName is the name of the store the address map module's state is
+// persisted in.
+const StoreName = "addrmap"
+
+// Tags emitted for every link.
+const (
+	TagEthereum = "addrmap.ethereum"
+	TagLegacy   = "addrmap.legacy"
+)
+
+var (
+	legacyKeyPrefix   = []byte("legacy:")
+	ethereumKeyPrefix = []byte("ethereum:")
+)
+
+// Keeper implements the address map module's state management. It records,
+// in both directions, the mapping between the Ethereum and legacy Cosmos
+// addresses of the public keys that linked them, and moves the balances of
+// linked legacy addresses to their Ethereum address.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	ck       bank.Keeper
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new address map Keeper.
+func NewKeeper(key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		ck:        ck,
+		codespace: codespace,
+	}
+}
+
+// GetEthereumAddress returns the Ethereum address a given legacy address is
+// linked to. A boolean is returned reflecting if the address is linked.
+func (k Keeper) GetEthereumAddress(ctx sdk.Context, legacy sdk.AccAddress) (sdk.AccAddress, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(LegacyKey(legacy))
+	if bz == nil {
+		return nil, false
+	}
+
+	return sdk.AccAddress(bz), true
+}
+
+// GetLegacyAddress returns the legacy address a given Ethereum address is
+// linked to. A boolean is returned reflecting if the address is linked.
+func (k Keeper) GetLegacyAddress(ctx sdk.Context, addr sdk.AccAddress) (sdk.AccAddress, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(EthereumKey(addr))
+	if bz == nil {
+		return nil, false
+	}
+
+	return sdk.AccAddress(bz), true
+}
+
+// Link links the legacy Cosmos address of a given public key to its Ethereum
+// address and moves the balance of the former to the latter. As funds may
+// still be sent to the legacy address, linking an already linked key again
+// moves any balance received since.
+func (k Keeper) Link(ctx sdk.Context, pubKey crypto.PubKeySecp256k1) (sdk.Coins, sdk.Tags, sdk.Error) {
+	if !validPubKey(pubKey) {
+		return nil, nil, ErrInvalidPubKey(k.codespace)
+	}
+
+	addr, legacy := EthereumAddress(pubKey), LegacyAddress(pubKey)
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(LegacyKey(legacy), addr.Bytes())
+	store.Set(EthereumKey(addr), legacy.Bytes())
+
+	tags := sdk.NewTags(TagEthereum, []byte(addr.String()), TagLegacy, []byte(legacy.String()))
+
+	coins := k.ck.GetCoins(ctx, legacy)
+	if coins.IsZero() {
+		return nil, tags, nil
+	}
+
+	sendTags, err := k.ck.SendCoins(ctx, legacy, addr, coins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coins, tags.AppendTags(sendTags), nil
+}
+
+// LegacyKey returns the store key of the Ethereum address a given legacy
+// address is linked to.
+func LegacyKey(legacy sdk.AccAddress) []byte {
+	return append(legacyKeyPrefix, legacy.Bytes()...)
+}
+
+// EthereumKey returns the store key of the legacy address a given Ethereum
+// address is linked to.
+func EthereumKey(addr sdk.AccAddress) []byte {
+	return append(ethereumKeyPrefix, addr.Bytes()...)
+}
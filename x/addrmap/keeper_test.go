@@ -0,0 +1,132 @@
+package addrmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func newTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyAddrMap := sdk.NewKVStoreKey("addrmap")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyAddrMap, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount))
+
+	return ctx, ck, NewKeeper(keyAddrMap, ck, DefaultCodespace)
+}
+
+func newTestPubKey(t *testing.T) crypto.PubKeySecp256k1 {
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	return privKey.PubKey().(crypto.PubKeySecp256k1)
+}
+
+func TestAddressSchemes(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.Nil(t, err)
+
+	pubKey := privKey.PubKey().(crypto.PubKeySecp256k1)
+
+	// the Ethereum address is that of go-ethereum and the legacy address that
+	// of the Cosmos SDK for the same key
+	require.Equal(t, ethcmn.BytesToAddress(EthereumAddress(pubKey)), ethcmn.BytesToAddress(privKey.PubKey().Address()))
+	var tmPrivKey tmcrypto.PrivKeySecp256k1
+	copy(tmPrivKey[:], privKey)
+
+	require.Equal(t, sdk.AccAddress(tmPrivKey.PubKey().Address()), LegacyAddress(pubKey))
+	require.NotEqual(t, EthereumAddress(pubKey), LegacyAddress(pubKey))
+}
+
+func TestLink(t *testing.T) {
+	ctx, ck, k := newTestInput(t)
+	handler := NewHandler(k)
+
+	pubKey := newTestPubKey(t)
+	addr, legacy := EthereumAddress(pubKey), LegacyAddress(pubKey)
+
+	coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, 100)}
+	_, _, err := ck.AddCoins(ctx, legacy, coins)
+	require.Nil(t, err)
+
+	_, found := k.GetEthereumAddress(ctx, legacy)
+	require.False(t, found)
+
+	res := handler(ctx, NewMsgLinkLegacyAddress(pubKey))
+	require.True(t, res.IsOK())
+
+	linked, found := k.GetEthereumAddress(ctx, legacy)
+	require.True(t, found)
+	require.Equal(t, addr, linked)
+
+	linked, found = k.GetLegacyAddress(ctx, addr)
+	require.True(t, found)
+	require.Equal(t, legacy, linked)
+
+	require.Equal(t, coins, ck.GetCoins(ctx, addr))
+	require.True(t, ck.GetCoins(ctx, legacy).IsZero())
+
+	// funds received by the legacy address after linking are moved by linking
+	// again, while linking without funds to move succeeds
+	_, _, err = ck.AddCoins(ctx, legacy, coins)
+	require.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		res = handler(ctx, NewMsgLinkLegacyAddress(pubKey))
+		require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: link #%d", i))
+	}
+
+	require.Equal(t, coins.Plus(coins), ck.GetCoins(ctx, addr))
+	require.True(t, ck.GetCoins(ctx, legacy).IsZero())
+}
+
+func TestMsgLinkLegacyAddress(t *testing.T) {
+	pubKey := newTestPubKey(t)
+
+	testCases := []struct {
+		pubKey    crypto.PubKeySecp256k1
+		expectErr bool
+	}{
+		{pubKey, false},
+		{nil, true},
+		{pubKey[1:], true},
+		{make(crypto.PubKeySecp256k1, 33), true},
+	}
+
+	for i, tc := range testCases {
+		msg := NewMsgLinkLegacyAddress(tc.pubKey)
+
+		if tc.expectErr {
+			require.NotNil(t, msg.ValidateBasic(), fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, msg.ValidateBasic(), fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+
+	// the message must be signed by the Ethereum address of its key
+	require.Equal(t, []sdk.AccAddress{EthereumAddress(pubKey)}, NewMsgLinkLegacyAddress(pubKey).GetSigners())
+}
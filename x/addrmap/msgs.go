@@ -0,0 +1,53 @@
+package addrmap
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/crypto"
+)
+
+// MsgType is the type and route of the address map module's messages.
+const MsgType = "addrmap"
+
+// MsgLinkLegacyAddress defines a request to link the legacy Cosmos address of
+// a secp256k1 public key to its Ethereum address, moving the balance of the
+// former to the latter. The message must be signed by the Ethereum address of
+// the key, which proves ownership of both addresses.
+type MsgLinkLegacyAddress struct {
+	PubKey crypto.PubKeySecp256k1 `json:"pub_key"`
+}
+
+var _ sdk.Msg = MsgLinkLegacyAddress{}
+
+// NewMsgLinkLegacyAddress returns a new MsgLinkLegacyAddress for a given
+// public key.
+func NewMsgLinkLegacyAddress(pubKey crypto.PubKeySecp256k1) MsgLinkLegacyAddress {
+	return MsgLinkLegacyAddress{PubKey: pubKey}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgLinkLegacyAddress) Type() string { return MsgType }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgLinkLegacyAddress) ValidateBasic() sdk.Error {
+	if !validPubKey(msg.PubKey) {
+		return ErrInvalidPubKey(DefaultCodespace)
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgLinkLegacyAddress) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgLinkLegacyAddress must be
+// signed by the Ethereum address of its public key.
+func (msg MsgLinkLegacyAddress) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{EthereumAddress(msg.PubKey)}
+}
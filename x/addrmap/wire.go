@@ -0,0 +1,17 @@
+package addrmap
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the address map module's concrete types on a wire
+// codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgLinkLegacyAddress{}, "ethermint/addrmap/LinkLegacyAddress", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
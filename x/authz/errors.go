@@ -0,0 +1,78 @@
+package authz
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the authz module.
+	DefaultCodespace sdk.CodespaceType = 12
+
+	// Authz error codes
+	CodeInvalidMsg         sdk.CodeType = 1
+	CodeGrantNotFound      sdk.CodeType = 2
+	CodeGrantExpired       sdk.CodeType = 3
+	CodeSpendLimitExceeded sdk.CodeType = 4
+	CodeInvalidExpiration  sdk.CodeType = 5
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidMsg:
+		return "invalid authorization message"
+	case CodeGrantNotFound:
+		return "grant not found"
+	case CodeGrantExpired:
+		return "grant expired"
+	case CodeSpendLimitExceeded:
+		return "spend limit exceeded"
+	case CodeInvalidExpiration:
+		return "invalid grant expiration"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidMsg returns a standardized SDK error resulting from an invalid
+// grant or revoke message.
+func ErrInvalidMsg(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidMsg, msg)
+}
+
+// ErrGrantNotFound returns a standardized SDK error resulting from a grantee
+// acting on behalf of a granter without a grant for the message type.
+func ErrGrantNotFound(codespace sdk.CodespaceType, granter, grantee sdk.AccAddress, msgType string) sdk.Error {
+	return newError(
+		codespace, CodeGrantNotFound, fmt.Sprintf("no grant from %s to %s for %s messages", granter, grantee, msgType),
+	)
+}
+
+// ErrGrantExpired returns a standardized SDK error resulting from the use of
+// an expired grant.
+func ErrGrantExpired(codespace sdk.CodespaceType, expiration int64) sdk.Error {
+	return newError(codespace, CodeGrantExpired, fmt.Sprintf("grant expired at %d", expiration))
+}
+
+// ErrSpendLimitExceeded returns a standardized SDK error resulting from a
+// message spending more than the remaining spend limit of a grant.
+func ErrSpendLimitExceeded(codespace sdk.CodespaceType, spend, limit sdk.Coins) sdk.Error {
+	return newError(
+		codespace, CodeSpendLimitExceeded, fmt.Sprintf("spend of %s exceeds remaining limit %s", spend, limit),
+	)
+}
+
+// ErrInvalidExpiration returns a standardized SDK error resulting from a grant
+// expiring at or before the current block time.
+func ErrInvalidExpiration(codespace sdk.CodespaceType, expiration int64) sdk.Error {
+	return newError(codespace, CodeInvalidExpiration, fmt.Sprintf("expiration %d is not in the future", expiration))
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
@@ -0,0 +1,47 @@
+package authz
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState defines the authz module's genesis state.
+type GenesisState struct {
+	Grants []Grant `json:"grants"`
+}
+
+// DefaultGenesisState returns the default authz module genesis state. No
+// grant exists by default.
+func DefaultGenesisState() GenesisState {
+	return GenesisState{Grants: []Grant{}}
+}
+
+// ValidateGenesis returns an error if any grant of the given authz module
+// genesis state is invalid.
+func ValidateGenesis(data GenesisState) error {
+	for _, grant := range data.Grants {
+		msg := NewMsgGrant(grant.Granter, grant.Grantee, grant.MsgType, grant.Expiration, grant.SpendLimit)
+		if err := msg.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InitGenesis validates and sets the authz module's genesis state.
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	if err := ValidateGenesis(data); err != nil {
+		return err
+	}
+
+	for _, grant := range data.Grants {
+		k.SetGrant(ctx, grant)
+	}
+
+	return nil
+}
+
+// WriteGenesis returns the authz module's current state as a GenesisState.
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{Grants: k.GetGrants(ctx)}
+}
@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Tags emitted for every grant and revocation.
+const (
+	TagGranter = "authz.granter"
+	TagGrantee = "authz.grantee"
+)
+
+// NewHandler returns a handler for authz module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgGrant:
+			return handleMsgGrant(ctx, k, msg)
+
+		case MsgRevoke:
+			return handleMsgRevoke(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized authz message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgGrant(ctx sdk.Context, k Keeper, msg MsgGrant) sdk.Result {
+	if msg.Expiration != 0 && msg.Expiration <= ctx.BlockHeader().Time {
+		return ErrInvalidExpiration(k.codespace, msg.Expiration).Result()
+	}
+
+	k.SetGrant(ctx, Grant{
+		Granter:    msg.Granter,
+		Grantee:    msg.Grantee,
+		MsgType:    msg.MsgType,
+		Expiration: msg.Expiration,
+		SpendLimit: msg.SpendLimit,
+	})
+
+	return sdk.Result{Tags: grantTags(msg.Granter, msg.Grantee)}
+}
+
+func handleMsgRevoke(ctx sdk.Context, k Keeper, msg MsgRevoke) sdk.Result {
+	if _, ok := k.GetGrant(ctx, msg.Granter, msg.Grantee, msg.MsgType); !ok {
+		return ErrGrantNotFound(k.codespace, msg.Granter, msg.Grantee, msg.MsgType).Result()
+	}
+
+	k.DeleteGrant(ctx, msg.Granter, msg.Grantee, msg.MsgType)
+	return sdk.Result{Tags: grantTags(msg.Granter, msg.Grantee)}
+}
+
+func grantTags(granter, grantee sdk.AccAddress) sdk.Tags {
+	return sdk.NewTags(TagGranter, []byte(granter.String()), TagGrantee, []byte(grantee.String()))
+}
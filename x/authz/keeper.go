@@ -0,0 +1,152 @@
+package authz
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+)
+
+// StoreName is the name of the store the authz module's state is persisted in.
+const StoreName = "authz"
+
+var grantKeyPrefix = []byte("grant:")
+
+// Grant authorizes a grantee to sign messages of a given type on behalf of a
+// granter. The message type is the type returned by sdk.Msg's Type (i.e. the
+// message's route).
+type Grant struct {
+	Granter sdk.AccAddress `json:"granter"`
+	Grantee sdk.AccAddress `json:"grantee"`
+	MsgType string         `json:"msg_type"`
+
+	// Expiration is the block time, in seconds since the epoch, from which
+	// the grant may no longer be used. A zero expiration never expires.
+	Expiration int64 `json:"expiration"`
+
+	// SpendLimit is the remaining amount of coins the grantee may spend from
+	// the granter's account. An empty limit places no restriction.
+	SpendLimit sdk.Coins `json:"spend_limit"`
+}
+
+// Keeper implements the authz module's state management. It stores the grants
+// through which accounts delegate the signing of specific message types to
+// other accounts, e.g. to separate operational keys from custody keys.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new authz Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// SetGrant creates or replaces a grant.
+func (k Keeper) SetGrant(ctx sdk.Context, grant Grant) {
+	key := GrantKey(grant.Granter, grant.Grantee, grant.MsgType)
+	ctx.KVStore(k.storeKey).Set(key, k.cdc.MustMarshalBinary(grant))
+}
+
+// GetGrant returns the grant from a granter to a grantee for a given message
+// type. A boolean is returned reflecting if the grant exists.
+func (k Keeper) GetGrant(ctx sdk.Context, granter, grantee sdk.AccAddress, msgType string) (Grant, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(GrantKey(granter, grantee, msgType))
+	if bz == nil {
+		return Grant{}, false
+	}
+
+	var grant Grant
+	k.cdc.MustUnmarshalBinary(bz, &grant)
+
+	return grant, true
+}
+
+// DeleteGrant deletes the grant from a granter to a grantee for a given
+// message type.
+func (k Keeper) DeleteGrant(ctx sdk.Context, granter, grantee sdk.AccAddress, msgType string) {
+	ctx.KVStore(k.storeKey).Delete(GrantKey(granter, grantee, msgType))
+}
+
+// GetGrants returns all grants ordered by granter, grantee and message type.
+func (k Keeper) GetGrants(ctx sdk.Context) []Grant {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), grantKeyPrefix)
+	defer iter.Close()
+
+	grants := []Grant{}
+	for ; iter.Valid(); iter.Next() {
+		var grant Grant
+		k.cdc.MustUnmarshalBinary(iter.Value(), &grant)
+
+		grants = append(grants, grant)
+	}
+
+	return grants
+}
+
+// Authorize verifies that a grantee may sign a given message on behalf of a
+// granter as of the context's block time and consumes the coins the message
+// spends from the granter's account against the grant's spend limit. A grant
+// whose spend limit is exhausted is removed.
+func (k Keeper) Authorize(ctx sdk.Context, granter, grantee sdk.AccAddress, msg sdk.Msg) sdk.Error {
+	grant, ok := k.GetGrant(ctx, granter, grantee, msg.Type())
+	if !ok {
+		return ErrGrantNotFound(k.codespace, granter, grantee, msg.Type())
+	}
+
+	if grant.Expiration != 0 && ctx.BlockHeader().Time >= grant.Expiration {
+		return ErrGrantExpired(k.codespace, grant.Expiration)
+	}
+
+	spend := MsgSpend(msg, granter)
+	if len(grant.SpendLimit) == 0 || spend.IsZero() {
+		return nil
+	}
+
+	if !grant.SpendLimit.IsGTE(spend) {
+		return ErrSpendLimitExceeded(k.codespace, spend, grant.SpendLimit)
+	}
+
+	grant.SpendLimit = grant.SpendLimit.Minus(spend)
+	if grant.SpendLimit.IsZero() {
+		k.DeleteGrant(ctx, granter, grantee, grant.MsgType)
+		return nil
+	}
+
+	k.SetGrant(ctx, grant)
+	return nil
+}
+
+// MsgSpend returns the coins a message spends from a given account. Only the
+// inputs of a bank.MsgSend are considered to spend coins.
+func MsgSpend(msg sdk.Msg, addr sdk.AccAddress) sdk.Coins {
+	var spend sdk.Coins
+
+	if msg, ok := msg.(bank.MsgSend); ok {
+		for _, in := range msg.Inputs {
+			if bytes.Equal(in.Address, addr) {
+				spend = spend.Plus(in.Coins)
+			}
+		}
+	}
+
+	return spend
+}
+
+// GrantKey returns the store key of the grant from a granter to a grantee for
+// a given message type. The addresses are length prefixed so that no two
+// grants share a key.
+func GrantKey(granter, grantee sdk.AccAddress, msgType string) []byte {
+	key := append(append([]byte{}, grantKeyPrefix...), byte(len(granter)))
+	key = append(append(key, granter...), byte(len(grantee)))
+	key = append(key, grantee...)
+
+	return append(key, msgType...)
+}
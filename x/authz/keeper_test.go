@@ -0,0 +1,159 @@
+package authz
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testGranter   = sdk.AccAddress([]byte("test_granter________"))
+	testGrantee   = sdk.AccAddress([]byte("test_grantee________"))
+	testRecipient = sdk.AccAddress([]byte("test_recipient______"))
+)
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyAuthz := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAuthz, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{Time: 100}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyAuthz, DefaultCodespace)
+}
+
+func newTestSend(from sdk.AccAddress, amount int64) bank.MsgSend {
+	coins := sdk.Coins{sdk.NewCoin(types.DenomDefault, amount)}
+	return bank.NewMsgSend([]bank.Input{bank.NewInput(from, coins)}, []bank.Output{bank.NewOutput(testRecipient, coins)})
+}
+
+func TestMsgValidateBasic(t *testing.T) {
+	limit := sdk.Coins{sdk.NewCoin(types.DenomDefault, 10)}
+
+	testCases := []struct {
+		msg     sdk.Msg
+		expPass bool
+	}{
+		{NewMsgGrant(testGranter, testGrantee, "bank", 0, nil), true},
+		{NewMsgGrant(testGranter, testGrantee, "bank", 200, limit), true},
+		{NewMsgGrant(nil, testGrantee, "bank", 0, nil), false},
+		{NewMsgGrant(testGranter, nil, "bank", 0, nil), false},
+		{NewMsgGrant(testGranter, testGranter, "bank", 0, nil), false},
+		{NewMsgGrant(testGranter, testGrantee, "", 0, nil), false},
+		{NewMsgGrant(testGranter, testGrantee, "bank", -1, nil), false},
+		{NewMsgGrant(testGranter, testGrantee, "bank", 0, sdk.Coins{sdk.NewCoin(types.DenomDefault, -1)}), false},
+		{NewMsgRevoke(testGranter, testGrantee, "bank"), true},
+		{NewMsgRevoke(testGranter, testGrantee, ""), false},
+		{NewMsgRevoke(testGranter, testGranter, "bank"), false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.msg.ValidateBasic()
+		require.Equal(t, tc.expPass, err == nil, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
+
+func TestGrantRevoke(t *testing.T) {
+	ctx, k := newTestInput(t)
+	handler := NewHandler(k)
+
+	// expirations must be in the future
+	res := handler(ctx, NewMsgGrant(testGranter, testGrantee, "bank", 100, nil))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeInvalidExpiration), res.Code)
+
+	res = handler(ctx, NewMsgGrant(testGranter, testGrantee, "bank", 200, nil))
+	require.True(t, res.IsOK())
+	require.Equal(t, grantTags(testGranter, testGrantee), res.Tags)
+
+	grant, ok := k.GetGrant(ctx, testGranter, testGrantee, "bank")
+	require.True(t, ok)
+	require.Equal(t, int64(200), grant.Expiration)
+
+	// grants are directional and per message type
+	_, ok = k.GetGrant(ctx, testGrantee, testGranter, "bank")
+	require.False(t, ok)
+	_, ok = k.GetGrant(ctx, testGranter, testGrantee, "stake")
+	require.False(t, ok)
+
+	require.Equal(t, GenesisState{Grants: []Grant{grant}}, WriteGenesis(ctx, k))
+
+	res = handler(ctx, NewMsgRevoke(testGranter, testGrantee, "bank"))
+	require.True(t, res.IsOK())
+
+	_, ok = k.GetGrant(ctx, testGranter, testGrantee, "bank")
+	require.False(t, ok)
+
+	res = handler(ctx, NewMsgRevoke(testGranter, testGrantee, "bank"))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeGrantNotFound), res.Code)
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	limit := sdk.Coins{sdk.NewCoin(types.DenomDefault, 10)}
+	k.SetGrant(ctx, Grant{Granter: testGranter, Grantee: testGrantee, MsgType: "bank", Expiration: 200, SpendLimit: limit})
+
+	testCases := []struct {
+		time          int64
+		msg           sdk.Msg
+		expectedCode  sdk.CodeType
+		expectedLimit sdk.Coins
+	}{
+		{100, NewMsgRevoke(testGranter, testRecipient, "bank"), CodeGrantNotFound, limit},
+		{100, newTestSend(testGranter, 4), sdk.CodeOK, sdk.Coins{sdk.NewCoin(types.DenomDefault, 6)}},
+		{100, newTestSend(testGranter, 7), CodeSpendLimitExceeded, sdk.Coins{sdk.NewCoin(types.DenomDefault, 6)}},
+		// coins spent from other accounts are not accounted for
+		{100, newTestSend(testRecipient, 7), sdk.CodeOK, sdk.Coins{sdk.NewCoin(types.DenomDefault, 6)}},
+		{200, newTestSend(testGranter, 1), CodeGrantExpired, sdk.Coins{sdk.NewCoin(types.DenomDefault, 6)}},
+		{199, newTestSend(testGranter, 6), sdk.CodeOK, nil},
+		// an exhausted grant is removed
+		{199, newTestSend(testGranter, 1), CodeGrantNotFound, nil},
+	}
+
+	for i, tc := range testCases {
+		ctx = ctx.WithBlockHeader(abci.Header{Time: tc.time})
+
+		err := k.Authorize(ctx, testGranter, testGrantee, tc.msg)
+		if tc.expectedCode == sdk.CodeOK {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		} else {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			require.Equal(t, tc.expectedCode, err.Code(), fmt.Sprintf("unexpected code: test case #%d", i))
+		}
+
+		grant, ok := k.GetGrant(ctx, testGranter, testGrantee, "bank")
+		require.Equal(t, tc.expectedLimit != nil, ok, fmt.Sprintf("unexpected grant: test case #%d", i))
+		require.Equal(t, tc.expectedLimit, grant.SpendLimit, fmt.Sprintf("unexpected limit: test case #%d", i))
+	}
+
+	// grants without a spend limit are not restricted
+	k.SetGrant(ctx, Grant{Granter: testGranter, Grantee: testGrantee, MsgType: "bank"})
+	require.Nil(t, k.Authorize(ctx, testGranter, testGrantee, newTestSend(testGranter, 1000)))
+}
+
+func TestInitGenesisInvalid(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	testCases := []Grant{
+		{Granter: testGranter, Grantee: testGranter, MsgType: "bank"},
+		{Granter: testGranter, Grantee: testGrantee},
+		{Granter: testGranter, Grantee: testGrantee, MsgType: "bank", Expiration: -1},
+	}
+
+	for i, tc := range testCases {
+		err := InitGenesis(ctx, k, GenesisState{Grants: []Grant{tc}})
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+	}
+}
@@ -0,0 +1,131 @@
+package authz
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MsgGrant defines a message authorizing a grantee to sign messages of a given
+// type on behalf of the granter, replacing any existing grant for that type.
+type MsgGrant struct {
+	Granter    sdk.AccAddress `json:"granter"`
+	Grantee    sdk.AccAddress `json:"grantee"`
+	MsgType    string         `json:"msg_type"`
+	Expiration int64          `json:"expiration"`
+	SpendLimit sdk.Coins      `json:"spend_limit"`
+}
+
+// MsgRevoke defines a message revoking a grantee's authorization to sign
+// messages of a given type on behalf of the granter.
+type MsgRevoke struct {
+	Granter sdk.AccAddress `json:"granter"`
+	Grantee sdk.AccAddress `json:"grantee"`
+	MsgType string         `json:"msg_type"`
+}
+
+var (
+	_ sdk.Msg = MsgGrant{}
+	_ sdk.Msg = MsgRevoke{}
+)
+
+// NewMsgGrant returns a new MsgGrant. A zero expiration never expires and an
+// empty spend limit places no restriction on the coins spent.
+func NewMsgGrant(
+	granter, grantee sdk.AccAddress, msgType string, expiration int64, spendLimit sdk.Coins,
+) MsgGrant {
+
+	return MsgGrant{
+		Granter:    granter,
+		Grantee:    grantee,
+		MsgType:    msgType,
+		Expiration: expiration,
+		SpendLimit: spendLimit,
+	}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgGrant) Type() string { return "authz" }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgGrant) ValidateBasic() sdk.Error {
+	if err := validateGrantees(msg.Granter, msg.Grantee, msg.MsgType); err != nil {
+		return err
+	}
+
+	switch {
+	case msg.Expiration < 0:
+		return ErrInvalidMsg(DefaultCodespace, "expiration cannot be negative")
+
+	case !msg.SpendLimit.IsValid() || !msg.SpendLimit.IsNotNegative():
+		return ErrInvalidMsg(DefaultCodespace, "invalid spend limit "+msg.SpendLimit.String())
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgGrant) GetSignBytes() []byte {
+	return mustSortedSignBytes(msg)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgGrant must be signed by
+// the granter.
+func (msg MsgGrant) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
+
+// NewMsgRevoke returns a new MsgRevoke.
+func NewMsgRevoke(granter, grantee sdk.AccAddress, msgType string) MsgRevoke {
+	return MsgRevoke{
+		Granter: granter,
+		Grantee: grantee,
+		MsgType: msgType,
+	}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgRevoke) Type() string { return "authz" }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgRevoke) ValidateBasic() sdk.Error {
+	return validateGrantees(msg.Granter, msg.Grantee, msg.MsgType)
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgRevoke) GetSignBytes() []byte {
+	return mustSortedSignBytes(msg)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgRevoke must be signed by
+// the granter.
+func (msg MsgRevoke) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
+
+func validateGrantees(granter, grantee sdk.AccAddress, msgType string) sdk.Error {
+	switch {
+	case len(granter) == 0:
+		return ErrInvalidMsg(DefaultCodespace, "granter cannot be empty")
+
+	case len(grantee) == 0:
+		return ErrInvalidMsg(DefaultCodespace, "grantee cannot be empty")
+
+	case bytes.Equal(granter, grantee):
+		return ErrInvalidMsg(DefaultCodespace, "granter and grantee must differ")
+
+	case msgType == "":
+		return ErrInvalidMsg(DefaultCodespace, "message type cannot be empty")
+	}
+
+	return nil
+}
+
+func mustSortedSignBytes(msg sdk.Msg) []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
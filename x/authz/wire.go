@@ -0,0 +1,17 @@
+package authz
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the authz module's concrete types on a wire codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgGrant{}, "ethermint/authz/Grant", nil)
+	cdc.RegisterConcrete(MsgRevoke{}, "ethermint/authz/Revoke", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
@@ -0,0 +1,63 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/types"
+)
+
+// Keeper extends the SDK's bank keeper, holding balances of any number of
+// denominations, e.g. bridged IBC assets, by designating one of them as the
+// EVM-native denom: the denom of the balances the EVM reads and writes,
+// transferred as the value of Ethereum transactions and paying for their gas.
+// Balances of other denoms are only transferred by bank messages, e.g. an
+// embedded MsgSend, and are left untouched by the EVM.
+type Keeper struct {
+	bank.Keeper
+
+	evmDenom string
+}
+
+// NewKeeper returns a new bank Keeper holding the balances of the accounts of
+// the given auth.AccountMapper, with the given denom as the EVM-native denom.
+// It panics if the denom is invalid.
+func NewKeeper(am auth.AccountMapper, evmDenom string) Keeper {
+	return Keeper{Keeper: bank.NewKeeper(am)}.WithEVMDenom(evmDenom)
+}
+
+// WithEVMDenom returns a copy of the Keeper designating the given denom as
+// the EVM-native denom. It panics if the denom is invalid.
+func (k Keeper) WithEVMDenom(denom string) Keeper {
+	if err := ValidateDenom(denom); err != nil {
+		panic(err)
+	}
+
+	k.evmDenom = denom
+	return k
+}
+
+// EVMDenom returns the EVM-native denom.
+func (k Keeper) EVMDenom() string {
+	return k.evmDenom
+}
+
+// GetEVMBalance returns the balance of the given address as seen by the EVM,
+// i.e. its amount of the EVM-native denom.
+func (k Keeper) GetEVMBalance(ctx sdk.Context, addr sdk.AccAddress) *big.Int {
+	return types.EVMBalance(k.GetCoins(ctx, addr), k.evmDenom)
+}
+
+// ValidateDenom returns an error if the given denom is empty or contains
+// whitespace.
+func ValidateDenom(denom string) error {
+	if denom == "" || strings.ContainsAny(denom, " \t\n") {
+		return fmt.Errorf("invalid denom %q", denom)
+	}
+
+	return nil
+}
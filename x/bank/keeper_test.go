@@ -0,0 +1,83 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var testAddr = sdk.AccAddress([]byte("test_address________"))
+
+func newTestInput(t *testing.T) (sdk.Context, auth.AccountMapper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount)
+}
+
+func TestKeeperEVMDenom(t *testing.T) {
+	ctx, am := newTestInput(t)
+
+	k := NewKeeper(am, types.DenomDefault)
+	require.Equal(t, types.DenomDefault, k.EVMDenom())
+
+	coins := sdk.Coins{sdk.NewCoin("aatom", 7), sdk.NewCoin(types.DenomDefault, 3)}
+	_, _, err := k.AddCoins(ctx, testAddr, coins)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(3), k.GetEVMBalance(ctx, testAddr))
+
+	// both keepers share the same balances
+	ak := k.WithEVMDenom("aatom")
+	require.Equal(t, "aatom", ak.EVMDenom())
+	require.Equal(t, types.DenomDefault, k.EVMDenom())
+	require.Equal(t, big.NewInt(7), ak.GetEVMBalance(ctx, testAddr))
+	require.Equal(t, coins, ak.GetCoins(ctx, testAddr))
+
+	require.Equal(t, big.NewInt(0), k.WithEVMDenom("stake").GetEVMBalance(ctx, testAddr))
+
+	require.Panics(t, func() { NewKeeper(am, "") })
+	require.Panics(t, func() { k.WithEVMDenom("a atom") })
+}
+
+func TestValidateDenom(t *testing.T) {
+	testCases := []struct {
+		denom     string
+		expectErr bool
+	}{
+		{types.DenomDefault, false},
+		{"ibc/aatom", false},
+		{"", true},
+		{" ", true},
+		{"photon\n", true},
+		{"a\tatom", true},
+	}
+
+	for i, tc := range testCases {
+		err := ValidateDenom(tc.denom)
+
+		if tc.expectErr {
+			require.Error(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.NoError(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+	}
+}
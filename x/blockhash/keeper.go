@@ -0,0 +1,134 @@
+package blockhash
+
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+const (
+	// StoreName is the name of the block hash module's KVStore.
+	StoreName = "blockhash"
+
+	// HistorySize is the number of most recent block hashes retained, which
+	// is the number of blocks the EVM's BLOCKHASH opcode may look back.
+	HistorySize = 256
+)
+
+var (
+	hashPrefix = []byte("hash:")
+	timeKey    = []byte("time")
+)
+
+type (
+	// Keeper maintains a bounded history of the most recent block hashes in a
+	// ring buffer of HistorySize slots, backing the EVM's BLOCKHASH opcode.
+	// The hash of block n is stored in slot n % HistorySize, overwriting the
+	// hash of block n - HistorySize. The keeper also records the current
+	// block's time, backing the EVM's TIMESTAMP opcode.
+	Keeper struct {
+		storeKey sdk.StoreKey
+		cdc      *wire.Codec
+	}
+
+	// entry is a single slot of the ring buffer. The height is stored along
+	// with the hash so that a slot is never mistaken for the hash of a block
+	// other than the one it was written for.
+	entry struct {
+		Height int64  `json:"height"`
+		Hash   []byte `json:"hash"`
+	}
+)
+
+// NewKeeper returns a new block hash Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey) Keeper {
+	return Keeper{
+		storeKey: key,
+		cdc:      cdc,
+	}
+}
+
+// SetBlockHash records the hash of the block at the given height, replacing
+// the hash of the block HistorySize blocks prior to it.
+func (k Keeper) SetBlockHash(ctx sdk.Context, height int64, hash []byte) {
+	bz := k.cdc.MustMarshalBinary(entry{Height: height, Hash: hash})
+	ctx.KVStore(k.storeKey).Set(SlotKey(height), bz)
+}
+
+// GetBlockHash returns the hash of the block at the given height. False is
+// returned if the hash was never recorded or has since been evicted from the
+// history.
+func (k Keeper) GetBlockHash(ctx sdk.Context, height int64) ([]byte, bool) {
+	if height < 0 {
+		return nil, false
+	}
+
+	bz := ctx.KVStore(k.storeKey).Get(SlotKey(height))
+	if bz == nil {
+		return nil, false
+	}
+
+	var e entry
+	k.cdc.MustUnmarshalBinary(bz, &e)
+
+	if e.Height != height {
+		return nil, false
+	}
+
+	return e.Hash, true
+}
+
+// GetHashFn returns an ethvm.GetHashFunc backing the BLOCKHASH opcode with the
+// recorded history. The zero hash is returned for any block that is not among
+// the HistorySize blocks preceding the context's block, matching the
+// opcode's semantics.
+func (k Keeper) GetHashFn(ctx sdk.Context) ethvm.GetHashFunc {
+	return func(n uint64) ethcmn.Hash {
+		height := int64(n)
+		current := ctx.BlockHeight()
+
+		if height < 0 || height >= current || height < current-HistorySize {
+			return ethcmn.Hash{}
+		}
+
+		hash, ok := k.GetBlockHash(ctx, height)
+		if !ok {
+			return ethcmn.Hash{}
+		}
+
+		return ethcmn.BytesToHash(hash)
+	}
+}
+
+// SetBlockTime records the time, in seconds, exposed to the EVM for the
+// current block.
+func (k Keeper) SetBlockTime(ctx sdk.Context, time int64) {
+	ctx.KVStore(k.storeKey).Set(timeKey, k.cdc.MustMarshalBinary(time))
+}
+
+// GetBlockTime returns the time, in seconds, exposed to the EVM for the
+// current block. Zero is returned if no time was ever recorded.
+func (k Keeper) GetBlockTime(ctx sdk.Context) int64 {
+	bz := ctx.KVStore(k.storeKey).Get(timeKey)
+	if bz == nil {
+		return 0
+	}
+
+	var time int64
+	k.cdc.MustUnmarshalBinary(bz, &time)
+
+	return time
+}
+
+// SlotKey returns the store key of the ring buffer slot holding the hash of
+// the block at the given height.
+func SlotKey(height int64) []byte {
+	slot := make([]byte, 2)
+	binary.BigEndian.PutUint16(slot, uint16(height%HistorySize))
+
+	return append(append([]byte{}, hashPrefix...), slot...)
+}
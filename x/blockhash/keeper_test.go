@@ -0,0 +1,133 @@
+package blockhash
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyBlockHash := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyBlockHash, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyBlockHash)
+}
+
+func testBlockHash(height int64) []byte {
+	return ethcmn.BigToHash(big.NewInt(height + 1000)).Bytes()
+}
+
+// processBlocks runs the BeginBlocker for every block from 1 up to and
+// including the given height.
+func processBlocks(ctx sdk.Context, k Keeper, height int64) sdk.Context {
+	for h := int64(1); h <= height; h++ {
+		header := abci.Header{Height: h}
+		if h > 1 {
+			header.LastBlockHash = testBlockHash(h - 1)
+		}
+
+		ctx = ctx.WithBlockHeader(header).WithBlockHeight(h)
+		BeginBlocker(ctx, abci.RequestBeginBlock{Header: header}, k)
+	}
+
+	return ctx
+}
+
+func TestBeginBlocker(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	ctx = processBlocks(ctx, k, 1)
+
+	_, ok := k.GetBlockHash(ctx, 0)
+	require.False(t, ok)
+
+	ctx = processBlocks(ctx, k, 3)
+
+	for h := int64(1); h <= 2; h++ {
+		hash, ok := k.GetBlockHash(ctx, h)
+		require.True(t, ok, fmt.Sprintf("expected hash: block #%d", h))
+		require.Equal(t, testBlockHash(h), hash, fmt.Sprintf("unexpected hash: block #%d", h))
+	}
+
+	// the hash of the current block is unknown until the next block
+	_, ok = k.GetBlockHash(ctx, 3)
+	require.False(t, ok)
+}
+
+func TestGetHashFnBoundary(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	current := int64(HistorySize + 10)
+	ctx = processBlocks(ctx, k, current)
+
+	testCases := []struct {
+		height   int64
+		expected []byte
+	}{
+		{current - HistorySize - 1, nil},
+		{current - HistorySize, testBlockHash(current - HistorySize)},
+		{current - HistorySize + 1, testBlockHash(current - HistorySize + 1)},
+		{current - 1, testBlockHash(current - 1)},
+		{current, nil},
+		{current + 1, nil},
+		{1, nil},
+		{0, nil},
+	}
+
+	getHash := k.GetHashFn(ctx)
+
+	for i, tc := range testCases {
+		require.Equal(
+			t, ethcmn.BytesToHash(tc.expected), getHash(uint64(tc.height)),
+			fmt.Sprintf("unexpected hash: test case #%d", i),
+		)
+	}
+
+	// the slot of an evicted block is reused by the block HistorySize blocks later
+	_, ok := k.GetBlockHash(ctx, current-HistorySize-1)
+	require.False(t, ok)
+
+	hash, ok := k.GetBlockHash(ctx, current-1)
+	require.True(t, ok)
+	require.Equal(t, testBlockHash(current-1), hash)
+}
+
+func TestBeginBlockerTime(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	require.Equal(t, int64(0), k.GetBlockTime(ctx))
+
+	// consecutive blocks may share a time, but the time never regresses
+	testCases := []struct {
+		time, expected int64
+	}{
+		{1000, 1000},
+		{1002, 1002},
+		{1002, 1002},
+		{1001, 1002},
+		{1005, 1005},
+	}
+
+	for i, tc := range testCases {
+		header := abci.Header{Height: int64(i + 1), Time: tc.time}
+		ctx = ctx.WithBlockHeader(header).WithBlockHeight(header.Height)
+		BeginBlocker(ctx, abci.RequestBeginBlock{Header: header}, k)
+
+		require.Equal(t, tc.expected, k.GetBlockTime(ctx), fmt.Sprintf("unexpected time: test case #%d", i))
+	}
+}
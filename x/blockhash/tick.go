@@ -0,0 +1,21 @@
+package blockhash
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/core"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// BeginBlocker records the hash of the previous block at the start of every
+// block. The first block has no previous block and records no hash. The
+// block's time is recorded as well, never preceding the previous block's.
+func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k Keeper) sdk.Tags {
+	k.SetBlockTime(ctx, core.BlockTime(req.Header, k.GetBlockTime(ctx)))
+
+	if req.Header.Height > 1 && len(req.Header.LastBlockHash) != 0 {
+		k.SetBlockHash(ctx, req.Header.Height-1, req.Header.LastBlockHash)
+	}
+
+	return sdk.EmptyTags()
+}
@@ -0,0 +1,56 @@
+package circuit
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the circuit module.
+	DefaultCodespace sdk.CodespaceType = 13
+
+	// Circuit error codes
+	CodeInvalidMsg   sdk.CodeType = 1
+	CodeUnauthorized sdk.CodeType = 2
+	CodeCircuitOpen  sdk.CodeType = 3
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidMsg:
+		return "invalid circuit breaker message"
+	case CodeUnauthorized:
+		return "unauthorized circuit breaker authority"
+	case CodeCircuitOpen:
+		return "message type disabled by the circuit breaker"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidMsg returns a standardized SDK error resulting from an invalid
+// circuit breaker message.
+func ErrInvalidMsg(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidMsg, msg)
+}
+
+// ErrUnauthorized returns a standardized SDK error resulting from the circuit
+// breaker being toggled by an address other than its authority.
+func ErrUnauthorized(codespace sdk.CodespaceType, signer sdk.AccAddress) sdk.Error {
+	return newError(codespace, CodeUnauthorized, fmt.Sprintf("%s is not the circuit breaker authority", signer))
+}
+
+// ErrCircuitOpen returns a standardized SDK error resulting from a message of
+// a type disabled by the circuit breaker.
+func ErrCircuitOpen(codespace sdk.CodespaceType, msgType string) sdk.Error {
+	return newError(codespace, CodeCircuitOpen, fmt.Sprintf("%s messages are temporarily disabled", msgType))
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
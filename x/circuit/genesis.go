@@ -0,0 +1,40 @@
+package circuit
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState defines the circuit module's genesis state.
+type GenesisState struct {
+	Params Params `json:"params"`
+}
+
+// DefaultGenesisState returns the default circuit module genesis state.
+func DefaultGenesisState() GenesisState {
+	return GenesisState{Params: DefaultParams()}
+}
+
+// ValidateGenesis returns an error if the given circuit module genesis state
+// is invalid.
+func ValidateGenesis(data GenesisState) error {
+	if err := ValidateParams(data.Params); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// InitGenesis validates and sets the circuit module's genesis state.
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	if err := ValidateGenesis(data); err != nil {
+		return err
+	}
+
+	k.SetParams(ctx, data.Params)
+	return nil
+}
+
+// WriteGenesis returns the circuit module's current state as a GenesisState.
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{Params: k.GetParams(ctx)}
+}
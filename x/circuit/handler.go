@@ -0,0 +1,29 @@
+package circuit
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHandler returns a handler for circuit module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgSetDisabled:
+			return handleMsgSetDisabled(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized circuit message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgSetDisabled(ctx sdk.Context, k Keeper, msg MsgSetDisabled) sdk.Result {
+	if err := k.SetDisabledMsgTypes(ctx, msg.Authority, msg.MsgTypes); err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
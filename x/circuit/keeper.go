@@ -0,0 +1,117 @@
+package circuit
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// StoreName is the name of the store the circuit module's state is persisted in.
+const StoreName = "circuit"
+
+var paramsKey = []byte("params")
+
+// Keeper implements the circuit module's state management. It maintains a
+// circuit breaker, toggled by a single authority during security incidents,
+// disabling transactions carrying messages of specific types.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new circuit Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetParams returns the circuit module parameters. The default parameters are
+// returned if none have been set.
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+
+	var params Params
+	k.cdc.MustUnmarshalBinary(bz, &params)
+
+	return params
+}
+
+// SetParams sets the circuit module parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	ctx.KVStore(k.storeKey).Set(paramsKey, k.cdc.MustMarshalBinary(params))
+}
+
+// SetDisabledMsgTypes replaces the message types disabled by the circuit
+// breaker. An error is returned if the signer is not the breaker's authority.
+func (k Keeper) SetDisabledMsgTypes(ctx sdk.Context, signer sdk.AccAddress, msgTypes []string) sdk.Error {
+	params := k.GetParams(ctx)
+
+	if len(params.Authority) == 0 || !bytes.Equal(params.Authority, signer) {
+		return ErrUnauthorized(k.codespace, signer)
+	}
+
+	if err := validateMsgTypes(msgTypes); err != nil {
+		return err
+	}
+
+	params.DisabledMsgTypes = append([]string{}, msgTypes...)
+	k.SetParams(ctx, params)
+
+	return nil
+}
+
+// CheckMsgs returns an error if any of the given messages is of a type
+// disabled by the circuit breaker.
+func (k Keeper) CheckMsgs(ctx sdk.Context, msgs []sdk.Msg) sdk.Error {
+	disabled := k.GetParams(ctx).DisabledMsgTypes
+	if len(disabled) == 0 {
+		return nil
+	}
+
+	for _, msg := range msgs {
+		for _, msgType := range disabled {
+			if msg.Type() == msgType {
+				return ErrCircuitOpen(k.codespace, msgType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ZeroGasAllowed returns true if the given messages may be submitted with a
+// zero gas price, i.e. if there is at least one and every one of them is of a
+// type allowed to.
+func (k Keeper) ZeroGasAllowed(ctx sdk.Context, msgs []sdk.Msg) bool {
+	allowed := k.GetParams(ctx).ZeroGasMsgTypes
+	if len(allowed) == 0 || len(msgs) == 0 {
+		return false
+	}
+
+	for _, msg := range msgs {
+		if !containsMsgType(allowed, msg.Type()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsMsgType(msgTypes []string, msgType string) bool {
+	for _, t := range msgTypes {
+		if t == msgType {
+			return true
+		}
+	}
+
+	return false
+}
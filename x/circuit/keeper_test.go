@@ -0,0 +1,157 @@
+package circuit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testAuthority = sdk.AccAddress([]byte("test_authority______"))
+	testAddr      = sdk.AccAddress([]byte("test_address________"))
+)
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyCircuit := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyCircuit, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyCircuit, DefaultCodespace)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{Authority: testAuthority}}))
+
+	handler := NewHandler(k)
+	sendMsg := bank.MsgSend{}
+	ethTx := &types.Transaction{}
+
+	testCases := []struct {
+		msg              MsgSetDisabled
+		expectedCode     sdk.CodeType
+		expectedDisabled []sdk.Msg
+		expectedEnabled  []sdk.Msg
+	}{
+		{NewMsgSetDisabled(testAuthority, types.TypeTxEthereum), sdk.CodeOK, []sdk.Msg{ethTx}, []sdk.Msg{sendMsg}},
+		// only the authority may toggle the breaker
+		{NewMsgSetDisabled(testAddr), CodeUnauthorized, []sdk.Msg{ethTx}, []sdk.Msg{sendMsg}},
+		{NewMsgSetDisabled(testAuthority, types.TypeTxEthereum, "bank"), sdk.CodeOK, []sdk.Msg{ethTx, sendMsg}, nil},
+		// the breaker's own messages cannot be disabled
+		{NewMsgSetDisabled(testAuthority, MsgType), CodeInvalidMsg, []sdk.Msg{ethTx, sendMsg}, []sdk.Msg{MsgSetDisabled{}}},
+		{NewMsgSetDisabled(testAuthority), sdk.CodeOK, nil, []sdk.Msg{ethTx, sendMsg}},
+	}
+
+	for i, tc := range testCases {
+		expectedCode := sdk.ABCICodeOK
+		if tc.expectedCode != sdk.CodeOK {
+			expectedCode = sdk.ToABCICode(DefaultCodespace, tc.expectedCode)
+		}
+
+		res := handler(ctx, tc.msg)
+		require.Equal(t, expectedCode, res.Code, fmt.Sprintf("unexpected code: test case #%d", i))
+
+		for _, msg := range tc.expectedDisabled {
+			err := k.CheckMsgs(ctx, []sdk.Msg{sendMsg, msg})
+			require.NotNil(t, err, fmt.Sprintf("expected disabled %s: test case #%d", msg.Type(), i))
+			require.Equal(t, CodeCircuitOpen, err.Code(), fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+
+		require.Nil(t, k.CheckMsgs(ctx, tc.expectedEnabled), fmt.Sprintf("expected enabled: test case #%d", i))
+	}
+
+	require.Equal(t, testAuthority, WriteGenesis(ctx, k).Params.Authority)
+}
+
+func TestCircuitBreakerNoAuthority(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	res := NewHandler(k)(ctx, NewMsgSetDisabled(testAuthority, types.TypeTxEthereum))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnauthorized), res.Code)
+	require.Empty(t, k.GetParams(ctx).DisabledMsgTypes)
+}
+
+func TestValidateParams(t *testing.T) {
+	testCases := []struct {
+		msgTypes  []string
+		expectErr bool
+	}{
+		{nil, false},
+		{[]string{types.TypeTxEthereum, "bank"}, false},
+		{[]string{""}, true},
+		{[]string{MsgType}, true},
+		{[]string{"bank", "bank"}, true},
+	}
+
+	for i, tc := range testCases {
+		err := ValidateParams(Params{Authority: testAuthority, DisabledMsgTypes: tc.msgTypes})
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected result: test case #%d", i))
+
+		err = NewMsgSetDisabled(testAuthority, tc.msgTypes...).ValidateBasic()
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected msg result: test case #%d", i))
+	}
+
+	require.NotNil(t, NewMsgSetDisabled(nil).ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{testAuthority}, NewMsgSetDisabled(testAuthority).GetSigners())
+}
+
+func TestZeroGasAllowed(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	sendMsg := bank.MsgSend{}
+	circuitMsg := MsgSetDisabled{}
+
+	require.False(t, k.ZeroGasAllowed(ctx, []sdk.Msg{sendMsg}))
+
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{ZeroGasMsgTypes: []string{MsgType}}}))
+
+	testCases := []struct {
+		msgs     []sdk.Msg
+		expected bool
+	}{
+		{[]sdk.Msg{circuitMsg}, true},
+		{[]sdk.Msg{circuitMsg, circuitMsg}, true},
+		{[]sdk.Msg{circuitMsg, sendMsg}, false},
+		{[]sdk.Msg{sendMsg}, false},
+		{nil, false},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, k.ZeroGasAllowed(ctx, tc.msgs), fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+
+	require.Equal(t, []string{MsgType}, WriteGenesis(ctx, k).Params.ZeroGasMsgTypes)
+}
+
+func TestValidateZeroGasParams(t *testing.T) {
+	testCases := []struct {
+		msgTypes  []string
+		expectErr bool
+	}{
+		{nil, false},
+		// the breaker's own messages may be submitted with a zero gas price
+		{[]string{MsgType, "bank"}, false},
+		{[]string{""}, true},
+		{[]string{"bank", "bank"}, true},
+	}
+
+	for i, tc := range testCases {
+		err := ValidateParams(Params{ZeroGasMsgTypes: tc.msgTypes})
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected result: test case #%d", i))
+	}
+}
@@ -0,0 +1,51 @@
+package circuit
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MsgType is the type, and route, of the circuit module's messages.
+const MsgType = "circuit"
+
+// MsgSetDisabled defines a message, signed by the circuit breaker authority,
+// replacing the set of message types rejected by the ante handler. An empty
+// set resets the breaker.
+type MsgSetDisabled struct {
+	Authority sdk.AccAddress `json:"authority"`
+	MsgTypes  []string       `json:"msg_types"`
+}
+
+var _ sdk.Msg = MsgSetDisabled{}
+
+// NewMsgSetDisabled returns a new MsgSetDisabled.
+func NewMsgSetDisabled(authority sdk.AccAddress, msgTypes ...string) MsgSetDisabled {
+	return MsgSetDisabled{Authority: authority, MsgTypes: msgTypes}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgSetDisabled) Type() string { return MsgType }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgSetDisabled) ValidateBasic() sdk.Error {
+	if len(msg.Authority) == 0 {
+		return ErrInvalidMsg(DefaultCodespace, "authority cannot be empty")
+	}
+
+	return validateMsgTypes(msg.MsgTypes)
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgSetDisabled) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgSetDisabled must be signed
+// by the circuit breaker authority.
+func (msg MsgSetDisabled) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Authority}
+}
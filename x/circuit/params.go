@@ -0,0 +1,84 @@
+package circuit
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Params defines the parameters of the circuit module.
+type Params struct {
+	// Authority is the address allowed to toggle the circuit breaker, e.g. a
+	// multisig held by the chain's governance. The breaker cannot be toggled
+	// if no authority is set.
+	Authority sdk.AccAddress `json:"authority"`
+
+	// DisabledMsgTypes are the types of the messages rejected by the ante
+	// handler, e.g. "Ethereum" for Ethereum transactions
+	DisabledMsgTypes []string `json:"disabled_msg_types"`
+
+	// ZeroGasMsgTypes are the types of the messages an embedded SDK
+	// transaction may carry while paying a zero gas price, e.g. validator
+	// unjailing or governance votes, so that a failing fee market cannot
+	// prevent the chain from being governed. A transaction must carry
+	// messages of these types only.
+	ZeroGasMsgTypes []string `json:"zero_gas_msg_types"`
+}
+
+// DefaultParams returns the default circuit module parameters. No message
+// type is disabled, none may be submitted with a zero gas price and no
+// authority is set.
+func DefaultParams() Params {
+	return Params{DisabledMsgTypes: []string{}, ZeroGasMsgTypes: []string{}}
+}
+
+// ValidateParams returns an error if the given parameters are invalid.
+func ValidateParams(params Params) sdk.Error {
+	if err := validateMsgTypes(params.DisabledMsgTypes); err != nil {
+		return err
+	}
+
+	return validateZeroGasMsgTypes(params.ZeroGasMsgTypes)
+}
+
+// validateMsgTypes returns an error if the given message types are empty,
+// duplicated or include the circuit module's own messages, which must remain
+// available to reset the breaker.
+func validateMsgTypes(msgTypes []string) sdk.Error {
+	seen := make(map[string]bool, len(msgTypes))
+
+	for _, msgType := range msgTypes {
+		switch {
+		case msgType == "":
+			return ErrInvalidMsg(DefaultCodespace, "message type cannot be empty")
+
+		case msgType == MsgType:
+			return ErrInvalidMsg(DefaultCodespace, "circuit breaker messages cannot be disabled")
+
+		case seen[msgType]:
+			return ErrInvalidMsg(DefaultCodespace, "duplicate message type "+msgType)
+		}
+
+		seen[msgType] = true
+	}
+
+	return nil
+}
+
+// validateZeroGasMsgTypes returns an error if the given message types are
+// empty or duplicated.
+func validateZeroGasMsgTypes(msgTypes []string) sdk.Error {
+	seen := make(map[string]bool, len(msgTypes))
+
+	for _, msgType := range msgTypes {
+		switch {
+		case msgType == "":
+			return ErrInvalidMsg(DefaultCodespace, "zero gas message type cannot be empty")
+
+		case seen[msgType]:
+			return ErrInvalidMsg(DefaultCodespace, "duplicate zero gas message type "+msgType)
+		}
+
+		seen[msgType] = true
+	}
+
+	return nil
+}
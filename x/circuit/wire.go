@@ -0,0 +1,16 @@
+package circuit
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the circuit module's concrete types on a wire codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgSetDisabled{}, "ethermint/circuit/SetDisabled", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
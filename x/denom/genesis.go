@@ -0,0 +1,69 @@
+package denom
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+)
+
+// GenesisState defines the denom module's genesis state.
+type GenesisState struct {
+	Metadata []Metadata `json:"metadata"`
+	Params   Params     `json:"params"`
+}
+
+// DefaultGenesisState returns the default denom module genesis state.
+func DefaultGenesisState() GenesisState {
+	return GenesisState{Metadata: []Metadata{NativeMetadata()}, Params: DefaultParams()}
+}
+
+// ValidateGenesis returns an error if the given denom module genesis state
+// is invalid. Every fee denom must have metadata, either in the genesis state
+// or as the native denom.
+func ValidateGenesis(data GenesisState) error {
+	seen := make(map[string]bool)
+
+	for _, metadata := range data.Metadata {
+		if err := ValidateMetadata(metadata); err != nil {
+			return err
+		}
+
+		if seen[metadata.Denom] {
+			return fmt.Errorf("duplicate metadata for denom %s", metadata.Denom)
+		}
+
+		seen[metadata.Denom] = true
+	}
+
+	if err := ValidateParams(data.Params); err != nil {
+		return err
+	}
+
+	for _, feeDenom := range data.Params.FeeDenoms {
+		if !seen[feeDenom.Denom] && feeDenom.Denom != types.DenomDefault {
+			return fmt.Errorf("no metadata for fee denom %s", feeDenom.Denom)
+		}
+	}
+
+	return nil
+}
+
+// InitGenesis validates and sets the denom module's genesis state.
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	if err := ValidateGenesis(data); err != nil {
+		return err
+	}
+
+	for _, metadata := range data.Metadata {
+		k.SetMetadata(ctx, metadata)
+	}
+
+	k.SetParams(ctx, data.Params)
+	return nil
+}
+
+// WriteGenesis returns the denom module's current state as a GenesisState.
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{Metadata: k.GetAllMetadata(ctx), Params: k.GetParams(ctx)}
+}
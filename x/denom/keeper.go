@@ -0,0 +1,163 @@
+package denom
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/types"
+)
+
+// StoreName is the name of the store the denom module's state is persisted in.
+const StoreName = "denom"
+
+var (
+	metadataKeyPrefix = []byte("metadata:")
+	paramsKey         = []byte("params")
+)
+
+// Metadata describes how an asset is displayed by wallets and contracts.
+type Metadata struct {
+	// Denom is the denomination of the asset's base unit
+	Denom string `json:"denom"`
+
+	// Name is the human readable name of the asset (e.g. "Photon")
+	Name string `json:"name"`
+
+	// Symbol is the ticker symbol of the asset (e.g. "PHOTON")
+	Symbol string `json:"symbol"`
+
+	// Decimals is the number of decimals between the base unit and the
+	// displayed unit
+	Decimals uint8 `json:"decimals"`
+}
+
+// NativeMetadata returns the metadata of the native EVM asset, which is
+// served until metadata is explicitly set for it.
+func NativeMetadata() Metadata {
+	return Metadata{
+		Denom:    types.DenomDefault,
+		Name:     "Photon",
+		Symbol:   "PHOTON",
+		Decimals: 18,
+	}
+}
+
+// ValidateMetadata returns an error if the given metadata is invalid.
+func ValidateMetadata(metadata Metadata) error {
+	if metadata.Denom == "" || strings.ContainsAny(metadata.Denom, " \t\n") {
+		return fmt.Errorf("invalid denom %q", metadata.Denom)
+	}
+
+	if strings.TrimSpace(metadata.Name) == "" {
+		return fmt.Errorf("empty name for denom %s", metadata.Denom)
+	}
+
+	if strings.TrimSpace(metadata.Symbol) == "" {
+		return fmt.Errorf("empty symbol for denom %s", metadata.Denom)
+	}
+
+	return nil
+}
+
+// Keeper implements the denom module's state management. It maintains a
+// registry of the metadata of the native and bridged assets along with the
+// assets accepted to pay fees.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+}
+
+// NewKeeper returns a new denom Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey) Keeper {
+	return Keeper{
+		storeKey: key,
+		cdc:      cdc,
+	}
+}
+
+// SetMetadata sets the metadata of an asset, replacing any existing metadata
+// for its denom.
+func (k Keeper) SetMetadata(ctx sdk.Context, metadata Metadata) {
+	ctx.KVStore(k.storeKey).Set(MetadataKey(metadata.Denom), k.cdc.MustMarshalBinary(metadata))
+}
+
+// GetMetadata returns the metadata of the asset with the given denom. A
+// boolean is returned reflecting if the denom is registered. The native EVM
+// asset is always registered.
+func (k Keeper) GetMetadata(ctx sdk.Context, denom string) (Metadata, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(MetadataKey(denom))
+	if bz == nil {
+		if denom == types.DenomDefault {
+			return NativeMetadata(), true
+		}
+
+		return Metadata{}, false
+	}
+
+	var metadata Metadata
+	k.cdc.MustUnmarshalBinary(bz, &metadata)
+
+	return metadata, true
+}
+
+// GetAllMetadata returns the metadata of all registered assets ordered by
+// denom, including the native EVM asset.
+func (k Keeper) GetAllMetadata(ctx sdk.Context) []Metadata {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), metadataKeyPrefix)
+	defer iter.Close()
+
+	all := []Metadata{}
+	native := false
+
+	for ; iter.Valid(); iter.Next() {
+		var metadata Metadata
+		k.cdc.MustUnmarshalBinary(iter.Value(), &metadata)
+
+		if !native && metadata.Denom > types.DenomDefault {
+			all = append(all, NativeMetadata())
+			native = true
+		}
+
+		native = native || metadata.Denom == types.DenomDefault
+		all = append(all, metadata)
+	}
+
+	if !native {
+		all = append(all, NativeMetadata())
+	}
+
+	return all
+}
+
+// GetParams returns the denom module parameters. The default parameters are
+// returned if none have been set.
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+
+	var params Params
+	k.cdc.MustUnmarshalBinary(bz, &params)
+
+	return params
+}
+
+// SetParams sets the denom module parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	ctx.KVStore(k.storeKey).Set(paramsKey, k.cdc.MustMarshalBinary(params))
+}
+
+// ConvertFee returns the amount of the native denom the given fee is worth as
+// of the current parameters. An error is returned if the fee is paid in a
+// denom that is not allowed.
+func (k Keeper) ConvertFee(ctx sdk.Context, fee sdk.Coins) (sdk.Int, sdk.Error) {
+	return ConvertFee(k.GetParams(ctx), fee)
+}
+
+// MetadataKey returns the store key of the metadata of a given denom.
+func MetadataKey(denom string) []byte {
+	return append(append([]byte{}, metadataKeyPrefix...), denom...)
+}
@@ -0,0 +1,199 @@
+package denom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var testBridgedMetadata = Metadata{Denom: "uatom", Name: "Cosmos Hub Atom", Symbol: "ATOM", Decimals: 6}
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyDenom := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyDenom, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyDenom)
+}
+
+func TestMetadata(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	// the native asset is registered prior to genesis
+	metadata, ok := k.GetMetadata(ctx, types.DenomDefault)
+	require.True(t, ok)
+	require.Equal(t, NativeMetadata(), metadata)
+	require.Equal(t, []Metadata{NativeMetadata()}, k.GetAllMetadata(ctx))
+
+	_, ok = k.GetMetadata(ctx, testBridgedMetadata.Denom)
+	require.False(t, ok)
+
+	other := Metadata{Denom: "zeta", Name: "Zeta", Symbol: "ZETA", Decimals: 8}
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Metadata: []Metadata{other, testBridgedMetadata}}))
+
+	metadata, ok = k.GetMetadata(ctx, testBridgedMetadata.Denom)
+	require.True(t, ok)
+	require.Equal(t, testBridgedMetadata, metadata)
+
+	// the native asset is listed in denom order
+	expected := []Metadata{NativeMetadata(), testBridgedMetadata, other}
+	require.Equal(t, expected, k.GetAllMetadata(ctx))
+	require.Equal(t, GenesisState{Metadata: expected}, WriteGenesis(ctx, k))
+
+	// the native asset's metadata may be overridden
+	native := NativeMetadata()
+	native.Name = "Ethermint Photon"
+	k.SetMetadata(ctx, native)
+
+	expected[0] = native
+	require.Equal(t, expected, k.GetAllMetadata(ctx))
+}
+
+func TestInitGenesisInvalid(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	testCases := [][]Metadata{
+		{{Denom: "", Name: "Atom", Symbol: "ATOM"}},
+		{{Denom: "u atom", Name: "Atom", Symbol: "ATOM"}},
+		{{Denom: "uatom", Name: " ", Symbol: "ATOM"}},
+		{{Denom: "uatom", Name: "Atom", Symbol: ""}},
+		{testBridgedMetadata, testBridgedMetadata},
+	}
+
+	for i, tc := range testCases {
+		err := InitGenesis(ctx, k, GenesisState{Metadata: tc})
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+	}
+}
+
+func TestInitGenesisInvalidParams(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	testCases := []struct {
+		feeDenoms []FeeDenom
+		expectErr bool
+	}{
+		{[]FeeDenom{{"uatom", sdk.NewRat(2)}}, false},
+		// the fee denom must be registered
+		{[]FeeDenom{{"uosmo", sdk.NewRat(2)}}, true},
+		{[]FeeDenom{{types.DenomDefault, sdk.NewRat(1)}}, true},
+		{[]FeeDenom{{"uatom", sdk.NewRat(2)}, {"uatom", sdk.NewRat(3)}}, true},
+		{[]FeeDenom{{"uatom", sdk.ZeroRat()}}, true},
+		{[]FeeDenom{{"uatom", sdk.NewRat(-1)}}, true},
+		{[]FeeDenom{{Denom: "uatom"}}, true},
+	}
+
+	for i, tc := range testCases {
+		genesis := GenesisState{Metadata: []Metadata{testBridgedMetadata}, Params: Params{FeeDenoms: tc.feeDenoms}}
+		err := InitGenesis(ctx, k, genesis)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+	}
+}
+
+func TestConvertFee(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	// only the native denom is accepted by default
+	require.Equal(t, DefaultParams(), k.GetParams(ctx))
+
+	_, err := k.ConvertFee(ctx, sdk.Coins{sdk.NewCoin("uatom", 10)})
+	require.NotNil(t, err)
+
+	k.SetParams(ctx, Params{FeeDenoms: []FeeDenom{{"uatom", sdk.NewRat(5, 2)}, {"uosmo", sdk.NewRat(1, 3)}}})
+
+	testCases := []struct {
+		fee       sdk.Coins
+		expected  int64
+		expectErr bool
+	}{
+		{sdk.Coins{sdk.NewCoin(types.DenomDefault, 7)}, 7, false},
+		{sdk.Coins{sdk.NewCoin("uatom", 10)}, 25, false},
+		// the converted fee is rounded down
+		{sdk.Coins{sdk.NewCoin("uatom", 3)}, 7, false},
+		{sdk.Coins{sdk.NewCoin("uatom", 1), sdk.NewCoin("uosmo", 1)}, 2, false},
+		{sdk.Coins{sdk.NewCoin(types.DenomDefault, 1), sdk.NewCoin("uatom", 2)}, 6, false},
+		{nil, 0, false},
+		{sdk.Coins{sdk.NewCoin("ujuno", 1)}, 0, true},
+		{sdk.Coins{sdk.NewCoin("uatom", 2), sdk.NewCoin("ujuno", 1)}, 0, true},
+	}
+
+	for i, tc := range testCases {
+		amount, err := k.ConvertFee(ctx, tc.fee)
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		require.Equal(t, tc.expected, amount.Int64(), fmt.Sprintf("unexpected amount: test case #%d", i))
+	}
+}
+
+func TestPrecompile(t *testing.T) {
+	ctx, k := newTestInput(t)
+	k.SetMetadata(ctx, testBridgedMetadata)
+
+	p := NewPrecompile(ctx, k)
+
+	testCases := []struct {
+		denom    string
+		expected Metadata
+		expPass  bool
+	}{
+		{types.DenomDefault, NativeMetadata(), true},
+		{testBridgedMetadata.Denom, testBridgedMetadata, true},
+		{"unknown", Metadata{}, false},
+	}
+
+	for i, tc := range testCases {
+		input, err := precompileABI.Pack("metadata", tc.denom)
+		require.Nil(t, err)
+		require.Equal(t, uint64(PrecompileGas), p.RequiredGas(input))
+
+		ret, err := p.Run(input)
+		if !tc.expPass {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			continue
+		}
+
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		var out struct {
+			Name     string
+			Symbol   string
+			Decimals uint8
+		}
+
+		require.Nil(t, precompileABI.Unpack(&out, "metadata", ret))
+		require.Equal(t, tc.expected.Name, out.Name, fmt.Sprintf("unexpected name: test case #%d", i))
+		require.Equal(t, tc.expected.Symbol, out.Symbol, fmt.Sprintf("unexpected symbol: test case #%d", i))
+		require.Equal(t, tc.expected.Decimals, out.Decimals, fmt.Sprintf("unexpected decimals: test case #%d", i))
+	}
+
+	// malformed input is rejected
+	_, err := p.Run([]byte{0x01, 0x02})
+	require.NotNil(t, err)
+
+	_, err = p.Run([]byte{0x01, 0x02, 0x03, 0x04})
+	require.NotNil(t, err)
+}
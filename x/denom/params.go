@@ -0,0 +1,85 @@
+package denom
+
+import (
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+)
+
+// FeeDenom defines a denom, other than the native denom, accepted to pay fees
+// along with its conversion rate into the native denom.
+type FeeDenom struct {
+	// Denom is the denomination of the asset's base unit
+	Denom string `json:"denom"`
+
+	// Rate is the amount of the native denom's base unit a single base unit
+	// of the asset is worth
+	Rate sdk.Rat `json:"rate"`
+}
+
+// Params defines the parameters of the denom module.
+type Params struct {
+	// FeeDenoms is the allow-list of denoms, besides the native denom, the
+	// fees of embedded transactions may be paid in
+	FeeDenoms []FeeDenom `json:"fee_denoms"`
+}
+
+// DefaultParams returns the default denom module parameters. Only the native
+// denom is accepted to pay fees.
+func DefaultParams() Params {
+	return Params{FeeDenoms: []FeeDenom{}}
+}
+
+// ValidateParams returns an error if the given parameters are invalid.
+func ValidateParams(params Params) error {
+	seen := make(map[string]bool)
+
+	for _, feeDenom := range params.FeeDenoms {
+		if feeDenom.Denom == types.DenomDefault {
+			return fmt.Errorf("native denom %s cannot have a conversion rate", feeDenom.Denom)
+		}
+
+		if seen[feeDenom.Denom] {
+			return fmt.Errorf("duplicate fee denom %s", feeDenom.Denom)
+		}
+
+		if feeDenom.Rate.Rat == nil || feeDenom.Rate.Sign() != 1 {
+			return fmt.Errorf("conversion rate of fee denom %s must be positive", feeDenom.Denom)
+		}
+
+		seen[feeDenom.Denom] = true
+	}
+
+	return nil
+}
+
+// ConvertFee returns the amount of the native denom the given fee is worth as
+// of the given parameters, rounded down. An error is returned if any of the
+// fee's denoms is neither the native denom nor an allowed fee denom.
+func ConvertFee(params Params, fee sdk.Coins) (sdk.Int, sdk.Error) {
+	rates := make(map[string]*big.Rat, len(params.FeeDenoms))
+	for _, feeDenom := range params.FeeDenoms {
+		rates[feeDenom.Denom] = feeDenom.Rate.Rat
+	}
+
+	total := new(big.Rat)
+
+	for _, coin := range fee {
+		amount := new(big.Rat).SetInt(coin.Amount.BigInt())
+
+		if coin.Denom != types.DenomDefault {
+			rate, ok := rates[coin.Denom]
+			if !ok {
+				return sdk.Int{}, sdk.ErrInvalidCoins(fmt.Sprintf("fees cannot be paid in %s", coin.Denom))
+			}
+
+			amount.Mul(amount, rate)
+		}
+
+		total.Add(total, amount)
+	}
+
+	return sdk.NewIntFromBigInt(new(big.Int).Quo(total.Num(), total.Denom())), nil
+}
@@ -0,0 +1,94 @@
+package denom
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+const (
+	// PrecompileGas is the fixed gas cost of a call to the denom metadata
+	// precompile.
+	PrecompileGas = 2000
+
+	// PrecompileABI is the ABI of the denom metadata precompile. The call
+	// reverts if the given denom is not registered.
+	PrecompileABI = `[{
+		"name": "metadata",
+		"type": "function",
+		"constant": true,
+		"inputs": [{"name": "denom", "type": "string"}],
+		"outputs": [
+			{"name": "name", "type": "string"},
+			{"name": "symbol", "type": "string"},
+			{"name": "decimals", "type": "uint8"}
+		]
+	}]`
+)
+
+var (
+	// PrecompileAddress is the address of the read-only precompile serving
+	// the metadata of registered assets to contracts.
+	PrecompileAddress = ethcmn.HexToAddress("0x0000000000000000000000000000000000000101")
+
+	precompileABI abi.ABI
+)
+
+func init() {
+	var err error
+
+	if precompileABI, err = abi.JSON(strings.NewReader(PrecompileABI)); err != nil {
+		panic(fmt.Sprintf("invalid denom precompile ABI: %v", err))
+	}
+}
+
+// Precompile implements a read-only EVM precompiled contract serving the
+// metadata registered in a Keeper as of the given context. It never modifies
+// state and may therefore be invoked through STATICCALL.
+type Precompile struct {
+	ctx sdk.Context
+	k   Keeper
+}
+
+var _ ethvm.PrecompiledContract = Precompile{}
+
+// NewPrecompile returns a new denom metadata Precompile reading from the
+// given context.
+func NewPrecompile(ctx sdk.Context, k Keeper) Precompile {
+	return Precompile{ctx: ctx, k: k}
+}
+
+// RequiredGas implements the ethvm.PrecompiledContract interface.
+func (p Precompile) RequiredGas(_ []byte) uint64 {
+	return PrecompileGas
+}
+
+// Run implements the ethvm.PrecompiledContract interface. It returns the ABI
+// encoded name, symbol and decimals of the denom given as input.
+func (p Precompile) Run(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("invalid input length %d", len(input))
+	}
+
+	method, err := precompileABI.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	var denom string
+	if err := method.Inputs.Unpack(&denom, input[4:]); err != nil {
+		return nil, err
+	}
+
+	metadata, ok := p.k.GetMetadata(p.ctx, denom)
+	if !ok {
+		return nil, fmt.Errorf("unknown denom %s", denom)
+	}
+
+	return method.Outputs.Pack(metadata.Name, metadata.Symbol, metadata.Decimals)
+}
@@ -0,0 +1,72 @@
+package evm
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the EVM module.
+	DefaultCodespace sdk.CodespaceType = 14
+
+	// EVM error codes
+	CodeInvalidMsg      sdk.CodeType = 1
+	CodeUnauthorized    sdk.CodeType = 2
+	CodeGasPriceTooHigh sdk.CodeType = 3
+	CodeGasLimitTooLow  sdk.CodeType = 4
+	CodeGasLimitTooHigh sdk.CodeType = 5
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidMsg:
+		return "invalid EVM message"
+	case CodeUnauthorized:
+		return "unauthorized EVM parameters authority"
+	case CodeGasPriceTooHigh:
+		return "gas price too high"
+	case CodeGasLimitTooLow:
+		return "gas limit too low"
+	case CodeGasLimitTooHigh:
+		return "gas limit too high"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidMsg returns a standardized SDK error resulting from an invalid
+// EVM module message.
+func ErrInvalidMsg(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidMsg, msg)
+}
+
+// ErrUnauthorized returns a standardized SDK error resulting from the EVM
+// parameters being updated by an address other than their authority.
+func ErrUnauthorized(codespace sdk.CodespaceType, signer sdk.AccAddress) sdk.Error {
+	return newError(codespace, CodeUnauthorized, signer.String()+" is not the EVM parameters authority")
+}
+
+// ErrGasPriceTooHigh returns a standardized SDK error resulting from a
+// transaction paying a gas price above the maximum gas price.
+func ErrGasPriceTooHigh(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeGasPriceTooHigh, msg)
+}
+
+// ErrGasLimitTooLow returns a standardized SDK error resulting from a
+// transaction whose gas limit is below the minimum gas limit.
+func ErrGasLimitTooLow(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeGasLimitTooLow, msg)
+}
+
+// ErrGasLimitTooHigh returns a standardized SDK error resulting from a
+// transaction whose gas limit is above the maximum gas limit.
+func ErrGasLimitTooHigh(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeGasLimitTooHigh, msg)
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
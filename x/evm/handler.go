@@ -0,0 +1,29 @@
+package evm
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHandler returns a handler for EVM module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgSetGasParams:
+			return handleMsgSetGasParams(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized EVM message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgSetGasParams(ctx sdk.Context, k Keeper, msg MsgSetGasParams) sdk.Result {
+	if err := k.SetGasParams(ctx, msg.Authority, msg.MaxGasPrice, msg.MinGasLimit, msg.MaxGasLimit); err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
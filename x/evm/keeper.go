@@ -0,0 +1,130 @@
+package evm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/core"
+
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+)
+
+// StoreName is the name of the store the EVM module's state is persisted in.
+const StoreName = "evm"
+
+var paramsKey = []byte("params")
+
+// Keeper implements the EVM module's state management. It maintains the
+// consensus parameters configuring the EVM identically on every validator.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new EVM Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		cdc:       cdc,
+		codespace: codespace,
+	}
+}
+
+// GetParams returns the EVM module parameters. The default parameters are
+// returned if none have been set.
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+
+	var params Params
+	k.cdc.MustUnmarshalBinary(bz, &params)
+
+	return params
+}
+
+// SetParams sets the EVM module parameters. It panics if the parameters are
+// invalid.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	if err := ValidateParams(params); err != nil {
+		panic(err)
+	}
+
+	ctx.KVStore(k.storeKey).Set(paramsKey, k.cdc.MustMarshalBinary(params))
+}
+
+// DisabledOpcodes returns the opcodes disabled as of the current parameters.
+func (k Keeper) DisabledOpcodes(ctx sdk.Context) []ethvm.OpCode {
+	// parameters are validated before being set
+	ops, err := core.ParseOpcodes(k.GetParams(ctx).DisabledOpcodes)
+	if err != nil {
+		panic(err)
+	}
+
+	return ops
+}
+
+// SetGasParams replaces the maximum gas price and the gas limit bounds of
+// Ethereum transactions. An error is returned if the signer is not the
+// parameters' authority or if the bounds are invalid.
+func (k Keeper) SetGasParams(
+	ctx sdk.Context, signer sdk.AccAddress, maxGasPrice, minGasLimit, maxGasLimit uint64,
+) sdk.Error {
+
+	params := k.GetParams(ctx)
+
+	if len(params.Authority) == 0 || !bytes.Equal(params.Authority, signer) {
+		return ErrUnauthorized(k.codespace, signer)
+	}
+
+	if err := validateGasLimits(minGasLimit, maxGasLimit); err != nil {
+		return ErrInvalidMsg(k.codespace, err.Error())
+	}
+
+	params.MaxGasPrice = maxGasPrice
+	params.MinGasLimit = minGasLimit
+	params.MaxGasLimit = maxGasLimit
+	k.SetParams(ctx, params)
+
+	return nil
+}
+
+// FailedTxRefund returns the share of the given fee, paid for the gas used by
+// an Ethereum transaction whose execution failed, refunded to its sender as of
+// the current parameters.
+func (k Keeper) FailedTxRefund(ctx sdk.Context, fee *big.Int) *big.Int {
+	refund := new(big.Int).Mul(fee, new(big.Int).SetUint64(k.GetParams(ctx).FailedTxRefund))
+	return refund.Div(refund, big.NewInt(100))
+}
+
+// CheckGas returns an error if the given gas price exceeds the maximum gas
+// price or if the given gas limit is out of the gas limit bounds.
+func (k Keeper) CheckGas(ctx sdk.Context, gasPrice *big.Int, gasLimit uint64) sdk.Error {
+	params := k.GetParams(ctx)
+
+	if params.MaxGasPrice != 0 && (!gasPrice.IsUint64() || gasPrice.Uint64() > params.MaxGasPrice) {
+		return ErrGasPriceTooHigh(
+			k.codespace, fmt.Sprintf("gas price %s exceeds maximum of %d", gasPrice, params.MaxGasPrice),
+		)
+	}
+
+	if gasLimit < params.MinGasLimit {
+		return ErrGasLimitTooLow(
+			k.codespace, fmt.Sprintf("gas limit %d below minimum of %d", gasLimit, params.MinGasLimit),
+		)
+	}
+
+	if params.MaxGasLimit != 0 && gasLimit > params.MaxGasLimit {
+		return ErrGasLimitTooHigh(
+			k.codespace, fmt.Sprintf("gas limit %d exceeds maximum of %d", gasLimit, params.MaxGasLimit),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testAuthority = sdk.AccAddress([]byte("test_authority______"))
+	testAddr      = sdk.AccAddress([]byte("test_address________"))
+)
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyEVM := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyEVM, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyEVM, DefaultCodespace)
+}
+
+func TestInitGenesis(t *testing.T) {
+	testCases := []struct {
+		disabled  []string
+		expected  []ethvm.OpCode
+		expectErr bool
+	}{
+		{nil, []ethvm.OpCode{}, false},
+		{[]string{"SELFDESTRUCT", "0xf5"}, []ethvm.OpCode{ethvm.SELFDESTRUCT, ethvm.OpCode(0xf5)}, false},
+		{[]string{"STOP"}, nil, true},
+		{[]string{"SELFDESTRUCT", "SELFDESTRUCT"}, nil, true},
+	}
+
+	for i, tc := range testCases {
+		ctx, k := newTestInput(t)
+
+		err := InitGenesis(ctx, k, GenesisState{Params{DisabledOpcodes: tc.disabled}})
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			require.Equal(t, DefaultParams(), k.GetParams(ctx), fmt.Sprintf("unexpected params: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			require.Equal(t, tc.expected, k.DisabledOpcodes(ctx), fmt.Sprintf("unexpected opcodes: test case #%d", i))
+			require.Equal(t, tc.disabled, WriteGenesis(ctx, k).Params.DisabledOpcodes)
+		}
+	}
+
+	ctx, k := newTestInput(t)
+	require.Panics(t, func() { k.SetParams(ctx, Params{DisabledOpcodes: []string{"UNKNOWN"}}) })
+}
+
+func TestSetGasParams(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{DisabledOpcodes: []string{}, Authority: testAuthority}}))
+
+	handler := NewHandler(k)
+
+	testCases := []struct {
+		msg          MsgSetGasParams
+		expectedCode sdk.CodeType
+		expected     [3]uint64
+	}{
+		{NewMsgSetGasParams(testAuthority, 100, 21000, 8000000), sdk.CodeOK, [3]uint64{100, 21000, 8000000}},
+		// only the authority may update the gas parameters
+		{NewMsgSetGasParams(testAddr, 0, 0, 0), CodeUnauthorized, [3]uint64{100, 21000, 8000000}},
+		{NewMsgSetGasParams(testAuthority, 0, 2, 1), CodeInvalidMsg, [3]uint64{100, 21000, 8000000}},
+		{NewMsgSetGasParams(testAuthority, 0, 0, 0), sdk.CodeOK, [3]uint64{0, 0, 0}},
+	}
+
+	for i, tc := range testCases {
+		expectedCode := sdk.ABCICodeOK
+		if tc.expectedCode != sdk.CodeOK {
+			expectedCode = sdk.ToABCICode(DefaultCodespace, tc.expectedCode)
+		}
+
+		res := handler(ctx, tc.msg)
+		require.Equal(t, expectedCode, res.Code, fmt.Sprintf("unexpected code: test case #%d", i))
+
+		params := k.GetParams(ctx)
+		require.Equal(
+			t, tc.expected, [3]uint64{params.MaxGasPrice, params.MinGasLimit, params.MaxGasLimit},
+			fmt.Sprintf("unexpected params: test case #%d", i),
+		)
+	}
+
+	require.Equal(t, testAuthority, WriteGenesis(ctx, k).Params.Authority)
+
+	// the gas parameters cannot be updated if no authority is set
+	ctx, k = newTestInput(t)
+
+	res := NewHandler(k)(ctx, NewMsgSetGasParams(testAuthority, 1, 0, 0))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeUnauthorized), res.Code)
+	require.Equal(t, uint64(0), k.GetParams(ctx).MaxGasPrice)
+}
+
+func TestCheckGas(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	testCases := []struct {
+		params       Params
+		gasPrice     *big.Int
+		gasLimit     uint64
+		expectedCode sdk.CodeType
+	}{
+		{DefaultParams(), new(big.Int).Lsh(big.NewInt(1), 100), 1 << 63, sdk.CodeOK},
+		{Params{MaxGasPrice: 10}, big.NewInt(10), 0, sdk.CodeOK},
+		{Params{MaxGasPrice: 10}, big.NewInt(11), 0, CodeGasPriceTooHigh},
+		{Params{MaxGasPrice: 10}, new(big.Int).Lsh(big.NewInt(1), 64), 0, CodeGasPriceTooHigh},
+		{Params{MinGasLimit: 21000}, big.NewInt(1), 20999, CodeGasLimitTooLow},
+		{Params{MinGasLimit: 21000, MaxGasLimit: 21000}, big.NewInt(1), 21000, sdk.CodeOK},
+		{Params{MaxGasLimit: 21000}, big.NewInt(1), 21001, CodeGasLimitTooHigh},
+	}
+
+	for i, tc := range testCases {
+		k.SetParams(ctx, tc.params)
+
+		err := k.CheckGas(ctx, tc.gasPrice, tc.gasLimit)
+
+		if tc.expectedCode == sdk.CodeOK {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		} else {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+			require.Equal(t, tc.expectedCode, err.Code(), fmt.Sprintf("unexpected code: test case #%d", i))
+		}
+	}
+}
+
+func TestFailedTxRefund(t *testing.T) {
+	ctx, keeper := newTestInput(t)
+
+	testCases := []struct {
+		refund         uint64
+		fee            int64
+		expectedRefund int64
+	}{
+		{0, 21000, 0},
+		{50, 21000, 10500},
+		{50, 21001, 10500},
+		{100, 21000, 21000},
+	}
+
+	for i, tc := range testCases {
+		params := DefaultParams()
+		params.FailedTxRefund = tc.refund
+		keeper.SetParams(ctx, params)
+
+		refund := keeper.FailedTxRefund(ctx, big.NewInt(tc.fee))
+		require.Equal(t, big.NewInt(tc.expectedRefund), refund, fmt.Sprintf("unexpected refund: test case #%d", i))
+	}
+
+	require.NotNil(t, ValidateParams(Params{FailedTxRefund: 101}))
+}
+
+func TestValidateParams(t *testing.T) {
+	testCases := []struct {
+		minGasLimit uint64
+		maxGasLimit uint64
+		expectErr   bool
+	}{
+		{0, 0, false},
+		{21000, 0, false},
+		{21000, 21000, false},
+		{21001, 21000, true},
+	}
+
+	for i, tc := range testCases {
+		err := ValidateParams(Params{MinGasLimit: tc.minGasLimit, MaxGasLimit: tc.maxGasLimit})
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected result: test case #%d", i))
+
+		err = NewMsgSetGasParams(testAuthority, 0, tc.minGasLimit, tc.maxGasLimit).ValidateBasic()
+		require.Equal(t, tc.expectErr, err != nil, fmt.Sprintf("unexpected msg result: test case #%d", i))
+	}
+
+	require.NotNil(t, NewMsgSetGasParams(nil, 0, 0, 0).ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{testAuthority}, NewMsgSetGasParams(testAuthority, 0, 0, 0).GetSigners())
+}
@@ -0,0 +1,62 @@
+package evm
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MsgType is the type, and route, of the EVM module's messages.
+const MsgType = "evm"
+
+// MsgSetGasParams defines a message, signed by the EVM parameters authority,
+// replacing the maximum gas price and the gas limit bounds of Ethereum
+// transactions. A zero value disables the corresponding bound.
+type MsgSetGasParams struct {
+	Authority   sdk.AccAddress `json:"authority"`
+	MaxGasPrice uint64         `json:"max_gas_price"`
+	MinGasLimit uint64         `json:"min_gas_limit"`
+	MaxGasLimit uint64         `json:"max_gas_limit"`
+}
+
+var _ sdk.Msg = MsgSetGasParams{}
+
+// NewMsgSetGasParams returns a new MsgSetGasParams.
+func NewMsgSetGasParams(authority sdk.AccAddress, maxGasPrice, minGasLimit, maxGasLimit uint64) MsgSetGasParams {
+	return MsgSetGasParams{
+		Authority:   authority,
+		MaxGasPrice: maxGasPrice,
+		MinGasLimit: minGasLimit,
+		MaxGasLimit: maxGasLimit,
+	}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgSetGasParams) Type() string { return MsgType }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgSetGasParams) ValidateBasic() sdk.Error {
+	if len(msg.Authority) == 0 {
+		return ErrInvalidMsg(DefaultCodespace, "authority cannot be empty")
+	}
+
+	if err := validateGasLimits(msg.MinGasLimit, msg.MaxGasLimit); err != nil {
+		return ErrInvalidMsg(DefaultCodespace, err.Error())
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgSetGasParams) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgSetGasParams must be
+// signed by the EVM parameters authority.
+func (msg MsgSetGasParams) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Authority}
+}
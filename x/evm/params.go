@@ -0,0 +1,78 @@
+package evm
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/core"
+)
+
+// Params defines the parameters of the EVM module.
+type Params struct {
+	// DisabledOpcodes are the opcodes, by name or hex encoded byte, the EVM
+	// refuses to execute, e.g. SELFDESTRUCT on permissioned chains
+	DisabledOpcodes []string `json:"disabled_opcodes"`
+
+	// Authority is the address allowed to update the gas parameters below,
+	// e.g. a multisig held by the chain's governance. They cannot be updated
+	// if no authority is set.
+	Authority sdk.AccAddress `json:"authority"`
+
+	// MaxGasPrice is the highest gas price, in the native denom's base unit,
+	// an Ethereum transaction may pay. It protects senders from fee spikes,
+	// e.g. griefing on permissioned chains. Zero disables the cap.
+	MaxGasPrice uint64 `json:"max_gas_price"`
+
+	// MinGasLimit and MaxGasLimit bound the gas limit of an Ethereum
+	// transaction. A zero bound is disabled.
+	MinGasLimit uint64 `json:"min_gas_limit"`
+	MaxGasLimit uint64 `json:"max_gas_limit"`
+
+	// FailedTxRefund is the share, in percent, of the fee paid for the gas
+	// used by an Ethereum transaction whose execution failed, e.g. as it
+	// reverted or ran out of gas, refunded to its sender by the fee
+	// collector. Zero charges the entire fee, as on Ethereum. It is only set
+	// at genesis.
+	//
+	// A failed execution still consumed the validators' resources, so public
+	// chains should charge it in full: refunds make spamming the chain with
+	// failing transactions free. Consortium chains, whose senders are known
+	// and accountable, may refund failures up to entirely, so that members
+	// are not charged for honest mistakes.
+	//
+	// Transactions rejected before execution are never charged, whatever the
+	// refund. A transaction rejected upon CheckTx is not included in a block,
+	// while one rejected upon DeliverTx may no longer be authorized by its
+	// sender's nonce, so that charging it would allow anyone to replay it to
+	// drain its sender's balance.
+	FailedTxRefund uint64 `json:"failed_tx_refund"`
+}
+
+// DefaultParams returns the default EVM module parameters. Every opcode is
+// enabled, no authority is set and gas prices and limits are not bounded.
+func DefaultParams() Params {
+	return Params{DisabledOpcodes: []string{}}
+}
+
+// ValidateParams returns an error if the given parameters are invalid.
+func ValidateParams(params Params) error {
+	if _, err := core.ParseOpcodes(params.DisabledOpcodes); err != nil {
+		return err
+	}
+
+	if params.FailedTxRefund > 100 {
+		return fmt.Errorf("failed transaction refund of %d%% exceeds 100%%", params.FailedTxRefund)
+	}
+
+	return validateGasLimits(params.MinGasLimit, params.MaxGasLimit)
+}
+
+// validateGasLimits returns an error if the given minimum gas limit exceeds
+// the given maximum gas limit, unless the latter is disabled.
+func validateGasLimits(min, max uint64) error {
+	if max != 0 && min > max {
+		return fmt.Errorf("minimum gas limit %d exceeds maximum gas limit %d", min, max)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package evm
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the EVM module's concrete types on a wire codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgSetGasParams{}, "ethermint/evm/SetGasParams", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
@@ -0,0 +1,65 @@
+package faucet
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the faucet module.
+	DefaultCodespace sdk.CodespaceType = 10
+
+	// Faucet error codes
+	CodeInvalidRecipient sdk.CodeType = 1
+	CodeClaimTooSoon     sdk.CodeType = 2
+	CodeCapReached       sdk.CodeType = 3
+	CodeRateLimited      sdk.CodeType = 4
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidRecipient:
+		return "invalid recipient"
+	case CodeClaimTooSoon:
+		return "faucet funds already claimed within the current period"
+	case CodeCapReached:
+		return "faucet cap reached"
+	case CodeRateLimited:
+		return "faucet claims per block exceeded"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidRecipient returns a standardized SDK error resulting from an
+// invalid faucet recipient.
+func ErrInvalidRecipient(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeInvalidRecipient, "")
+}
+
+// ErrClaimTooSoon returns a standardized SDK error resulting from a recipient
+// claiming faucet funds more than once within a single period.
+func ErrClaimTooSoon(codespace sdk.CodespaceType, nextClaimTime int64) sdk.Error {
+	return newError(codespace, CodeClaimTooSoon, fmt.Sprintf("next claim allowed at %d", nextClaimTime))
+}
+
+// ErrCapReached returns a standardized SDK error resulting from a claim that
+// would raise the total amount dripped by the faucet above its cap.
+func ErrCapReached(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeCapReached, "")
+}
+
+// ErrRateLimited returns a standardized SDK error resulting from a claim in a
+// block that already served the maximum number of claims.
+func ErrRateLimited(codespace sdk.CodespaceType) sdk.Error {
+	return newError(codespace, CodeRateLimited, "")
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
@@ -0,0 +1,30 @@
+package faucet
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHandler returns a handler for faucet module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgRequestFunds:
+			return handleMsgRequestFunds(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized faucet message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgRequestFunds(ctx sdk.Context, k Keeper, msg MsgRequestFunds) sdk.Result {
+	_, tags, err := k.RequestFunds(ctx, msg.Recipient)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{Tags: tags}
+}
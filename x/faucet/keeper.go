@@ -0,0 +1,169 @@
+package faucet
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// StoreName is the name of the store the faucet module's state is persisted in.
+const StoreName = "faucet"
+
+var (
+	// FaucetAddr is the address of the account the faucet drips funds from,
+	// which must be funded, e.g. at genesis, for claims to succeed.
+	FaucetAddr = sdk.AccAddress(tmhash.Sum([]byte("faucet")))
+
+	paramsKey       = []byte("params")
+	totalClaimedKey = []byte("totalClaimed")
+	blockClaimsKey  = []byte("blockClaims")
+	claimKeyPrefix  = []byte("claim:")
+)
+
+// Claim records the faucet funds claimed by a single address.
+type Claim struct {
+	// LastClaimTime is the block time of the most recent claim
+	LastClaimTime int64 `json:"last_claim_time"`
+
+	// Total is the total amount ever claimed
+	Total sdk.Coins `json:"total"`
+}
+
+// blockClaims records the number of claims served in a single block.
+type blockClaims struct {
+	Height int64 `json:"height"`
+	Count  int64 `json:"count"`
+}
+
+// Keeper implements the faucet module's state management. It drips a capped
+// amount of tokens per address per period out of the account at FaucetAddr,
+// up to a total cap and a maximum number of claims per block, and keeps
+// on-chain accounting of all claims.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+	ck       bank.Keeper
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new faucet Keeper.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		cdc:       cdc,
+		ck:        ck,
+		codespace: codespace,
+	}
+}
+
+// GetParams returns the faucet module parameters. The default parameters are
+// returned if none have been set.
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+
+	var params Params
+	k.cdc.MustUnmarshalBinary(bz, &params)
+
+	return params
+}
+
+// SetParams sets the faucet module parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	ctx.KVStore(k.storeKey).Set(paramsKey, k.cdc.MustMarshalBinary(params))
+}
+
+// GetClaim returns the claim record of a given address. A boolean is returned
+// reflecting if the address ever claimed funds.
+func (k Keeper) GetClaim(ctx sdk.Context, addr sdk.AccAddress) (Claim, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(ClaimKey(addr))
+	if bz == nil {
+		return Claim{}, false
+	}
+
+	var claim Claim
+	k.cdc.MustUnmarshalBinary(bz, &claim)
+
+	return claim, true
+}
+
+// GetTotalClaimed returns the total amount dripped by the faucet.
+func (k Keeper) GetTotalClaimed(ctx sdk.Context) sdk.Coins {
+	bz := ctx.KVStore(k.storeKey).Get(totalClaimedKey)
+	if bz == nil {
+		return nil
+	}
+
+	var total sdk.Coins
+	k.cdc.MustUnmarshalBinary(bz, &total)
+
+	return total
+}
+
+// RequestFunds drips the faucet amount from the account at FaucetAddr to a
+// given recipient. An error is returned if the recipient already claimed funds
+// within the current period, if the current block already served the maximum
+// number of claims, if the claim would raise the total amount dripped above
+// the faucet's cap or if the faucet account cannot afford it.
+func (k Keeper) RequestFunds(ctx sdk.Context, recipient sdk.AccAddress) (sdk.Coins, sdk.Tags, sdk.Error) {
+	params := k.GetParams(ctx)
+	now := ctx.BlockHeader().Time
+
+	claim, found := k.GetClaim(ctx, recipient)
+	if found && now < claim.LastClaimTime+params.Period {
+		return nil, nil, ErrClaimTooSoon(k.codespace, claim.LastClaimTime+params.Period)
+	}
+
+	claims := k.getBlockClaims(ctx)
+	if claims.Height != ctx.BlockHeight() {
+		claims = blockClaims{Height: ctx.BlockHeight()}
+	}
+
+	if claims.Count >= params.MaxClaimsPerBlock {
+		return nil, nil, ErrRateLimited(k.codespace)
+	}
+
+	total := k.GetTotalClaimed(ctx).Plus(params.Amount)
+	if !params.Cap.IsGTE(total) {
+		return nil, nil, ErrCapReached(k.codespace)
+	}
+
+	tags, err := k.ck.SendCoins(ctx, FaucetAddr, recipient, params.Amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claim.LastClaimTime = now
+	claim.Total = claim.Total.Plus(params.Amount)
+	claims.Count++
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(ClaimKey(recipient), k.cdc.MustMarshalBinary(claim))
+	store.Set(totalClaimedKey, k.cdc.MustMarshalBinary(total))
+	store.Set(blockClaimsKey, k.cdc.MustMarshalBinary(claims))
+
+	return params.Amount, tags, nil
+}
+
+// getBlockClaims returns the number of claims served in the block they were
+// last served in.
+func (k Keeper) getBlockClaims(ctx sdk.Context) blockClaims {
+	var claims blockClaims
+
+	bz := ctx.KVStore(k.storeKey).Get(blockClaimsKey)
+	if bz != nil {
+		k.cdc.MustUnmarshalBinary(bz, &claims)
+	}
+
+	return claims
+}
+
+// ClaimKey returns the store key of the claim record of a given address.
+func ClaimKey(addr sdk.AccAddress) []byte {
+	return append(claimKeyPrefix, addr.Bytes()...)
+}
@@ -0,0 +1,162 @@
+package faucet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testAddr1 = sdk.AccAddress([]byte("test_address_1______"))
+	testAddr2 = sdk.AccAddress([]byte("test_address_2______"))
+)
+
+func newTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyFaucet := sdk.NewKVStoreKey("faucet")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyFaucet, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount))
+
+	return ctx, ck, NewKeeper(cdc, keyFaucet, ck, DefaultCodespace)
+}
+
+func TestRequestFunds(t *testing.T) {
+	ctx, ck, k := newTestInput(t)
+
+	amount := sdk.Coins{sdk.NewCoin(types.DenomDefault, 100)}
+	faucetFunds := sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000)}
+
+	params := Params{Amount: amount, Period: 60, Cap: faucetFunds, MaxClaimsPerBlock: 10}
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{params}))
+
+	_, _, err := ck.AddCoins(ctx, FaucetAddr, faucetFunds)
+	require.Nil(t, err)
+
+	handler := NewHandler(k)
+
+	testCases := []struct {
+		recipient       sdk.AccAddress
+		time            int64
+		expectErr       bool
+		expectedBalance int64
+	}{
+		{testAddr1, 1000, false, 100},
+		{testAddr1, 1059, true, 100},
+		{testAddr2, 1059, false, 100},
+		{testAddr1, 1060, false, 200},
+		{testAddr2, 1060, true, 100},
+	}
+
+	for i, tc := range testCases {
+		ctx = ctx.WithBlockHeader(abci.Header{Time: tc.time})
+		res := handler(ctx, NewMsgRequestFunds(tc.recipient))
+
+		if tc.expectErr {
+			require.False(t, res.IsOK(), fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d", i))
+		}
+
+		expected := sdk.Coins{sdk.NewCoin(types.DenomDefault, tc.expectedBalance)}
+		require.Equal(t, expected, ck.GetCoins(ctx, tc.recipient), fmt.Sprintf("unexpected balance: test case #%d", i))
+	}
+
+	claim, found := k.GetClaim(ctx, testAddr1)
+	require.True(t, found)
+	require.Equal(t, int64(1060), claim.LastClaimTime)
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 200)}, claim.Total)
+
+	_, found = k.GetClaim(ctx, sdk.AccAddress([]byte("unknown")))
+	require.False(t, found)
+
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 300)}, k.GetTotalClaimed(ctx))
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 700)}, ck.GetCoins(ctx, FaucetAddr))
+}
+
+func TestRequestFundsLimits(t *testing.T) {
+	ctx, ck, k := newTestInput(t)
+
+	amount := sdk.Coins{sdk.NewCoin(types.DenomDefault, 100)}
+	cap := sdk.Coins{sdk.NewCoin(types.DenomDefault, 300)}
+
+	params := Params{Amount: amount, Period: 60, Cap: cap, MaxClaimsPerBlock: 1}
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{params}))
+
+	fund := func(amount int64) {
+		_, _, err := ck.AddCoins(ctx, FaucetAddr, sdk.Coins{sdk.NewCoin(types.DenomDefault, amount)})
+		require.Nil(t, err)
+	}
+
+	fund(250)
+
+	handler := NewHandler(k)
+
+	testCases := []struct {
+		recipient    sdk.AccAddress
+		height       int64
+		fund         int64
+		expectedCode sdk.CodeType
+	}{
+		{sdk.AccAddress([]byte("test_address_1______")), 1, 0, sdk.CodeOK},
+		// a single claim is served per block
+		{sdk.AccAddress([]byte("test_address_2______")), 1, 0, CodeRateLimited},
+		{sdk.AccAddress([]byte("test_address_2______")), 2, 0, sdk.CodeOK},
+		// the faucet account only holds 50
+		{sdk.AccAddress([]byte("test_address_3______")), 3, 0, sdk.CodeInsufficientCoins},
+		{sdk.AccAddress([]byte("test_address_3______")), 4, 1000, sdk.CodeOK},
+		// the faucet already dripped its cap of 300
+		{sdk.AccAddress([]byte("test_address_4______")), 5, 0, CodeCapReached},
+	}
+
+	for i, tc := range testCases {
+		if tc.fund > 0 {
+			fund(tc.fund)
+		}
+
+		ctx = ctx.WithBlockHeader(abci.Header{Height: tc.height, Time: tc.height}).WithBlockHeight(tc.height)
+		res := handler(ctx, NewMsgRequestFunds(tc.recipient))
+
+		codespace := DefaultCodespace
+		if tc.expectedCode == sdk.CodeInsufficientCoins {
+			codespace = sdk.CodespaceRoot
+		}
+
+		if tc.expectedCode == sdk.CodeOK {
+			require.True(t, res.IsOK(), fmt.Sprintf("unexpected error: test case #%d: %s", i, res.Log))
+		} else {
+			require.Equal(
+				t, sdk.ToABCICode(codespace, tc.expectedCode), res.Code, fmt.Sprintf("unexpected code: test case #%d", i),
+			)
+		}
+	}
+
+	require.Equal(t, cap, k.GetTotalClaimed(ctx))
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 950)}, ck.GetCoins(ctx, FaucetAddr))
+}
+
+func TestMsgRequestFundsValidateBasic(t *testing.T) {
+	require.Nil(t, NewMsgRequestFunds(testAddr1).ValidateBasic())
+	require.NotNil(t, NewMsgRequestFunds(nil).ValidateBasic())
+	require.Empty(t, NewMsgRequestFunds(testAddr1).GetSigners())
+}
@@ -0,0 +1,51 @@
+package faucet
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MsgType is the type, and route, of the faucet module's messages.
+const MsgType = "faucet"
+
+// MsgRequestFunds defines a request for faucet funds to be dripped to a given
+// recipient. The message carries no signers as the recipient need not exist
+// prior to claiming funds. Claims are instead limited per recipient and period
+// by the faucet Keeper.
+type MsgRequestFunds struct {
+	Recipient sdk.AccAddress `json:"recipient"`
+}
+
+var _ sdk.Msg = MsgRequestFunds{}
+
+// NewMsgRequestFunds returns a new MsgRequestFunds for a given recipient.
+func NewMsgRequestFunds(recipient sdk.AccAddress) MsgRequestFunds {
+	return MsgRequestFunds{Recipient: recipient}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgRequestFunds) Type() string { return MsgType }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgRequestFunds) ValidateBasic() sdk.Error {
+	if len(msg.Recipient) == 0 {
+		return ErrInvalidRecipient(DefaultCodespace)
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgRequestFunds) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgRequestFunds requires no
+// signatures.
+func (msg MsgRequestFunds) GetSigners() []sdk.AccAddress {
+	return nil
+}
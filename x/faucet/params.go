@@ -0,0 +1,54 @@
+package faucet
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+)
+
+// Params defines the parameters of the faucet module.
+type Params struct {
+	// Amount is the maximum amount dripped to a single address per period
+	Amount sdk.Coins `json:"amount"`
+
+	// Period is the duration, in seconds, an address must wait between claims
+	Period int64 `json:"period"`
+
+	// Cap is the maximum total amount ever dripped by the faucet, across all
+	// addresses
+	Cap sdk.Coins `json:"cap"`
+
+	// MaxClaimsPerBlock is the maximum number of claims served in a single
+	// block, across all addresses
+	MaxClaimsPerBlock int64 `json:"max_claims_per_block"`
+}
+
+// DefaultParams returns the default faucet module parameters.
+func DefaultParams() Params {
+	return Params{
+		Amount:            sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000)},
+		Period:            24 * 60 * 60,
+		Cap:               sdk.Coins{sdk.NewCoin(types.DenomDefault, 1000000000)},
+		MaxClaimsPerBlock: 10,
+	}
+}
+
+// ValidateParams returns an error if the given parameters are invalid.
+func ValidateParams(params Params) sdk.Error {
+	if !params.Amount.IsValid() || !params.Amount.IsNotNegative() {
+		return sdk.ErrInvalidCoins(params.Amount.String())
+	}
+
+	if !params.Cap.IsValid() || !params.Cap.IsNotNegative() {
+		return sdk.ErrInvalidCoins(params.Cap.String())
+	}
+
+	if params.Period < 0 {
+		return sdk.ErrInternal("faucet period cannot be negative")
+	}
+
+	if params.MaxClaimsPerBlock < 0 {
+		return sdk.ErrInternal("faucet maximum claims per block cannot be negative")
+	}
+
+	return nil
+}
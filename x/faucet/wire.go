@@ -0,0 +1,16 @@
+package faucet
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the faucet module's concrete types on a wire codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgRequestFunds{}, "ethermint/faucet/RequestFunds", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
@@ -0,0 +1,25 @@
+package ica
+
+import (
+	"encoding/binary"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// addressPrefix domain separates derived interchain account addresses from
+// any other keccak256 based address derivation.
+var addressPrefix = []byte("ethermint/ica")
+
+// DeriveAddress returns the address of the interchain account owned by a given
+// owner on a given controller. The controller ID is length prefixed so that no
+// two distinct (controller, owner) pairs share the same preimage. As the
+// address has no known private key, the account may only ever be operated
+// through remote execution by its controller.
+func DeriveAddress(controller, owner string) ethcmn.Address {
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(controller)))
+
+	hash := ethcrypto.Keccak256(addressPrefix, length, []byte(controller), []byte(owner))
+	return ethcmn.BytesToAddress(hash[12:])
+}
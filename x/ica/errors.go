@@ -0,0 +1,74 @@
+package ica
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the interchain accounts module.
+	DefaultCodespace sdk.CodespaceType = 11
+
+	// Interchain accounts error codes
+	CodeInvalidMsg        sdk.CodeType = 1
+	CodeUnknownController sdk.CodeType = 2
+	CodeUnauthorized      sdk.CodeType = 3
+	CodeAddressCollision  sdk.CodeType = 4
+	CodeExecutionFailed   sdk.CodeType = 5
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeInvalidMsg:
+		return "invalid remote execution message"
+	case CodeUnknownController:
+		return "unknown controller"
+	case CodeUnauthorized:
+		return "unauthorized controller authority"
+	case CodeAddressCollision:
+		return "interchain account address collision"
+	case CodeExecutionFailed:
+		return "remote execution failed"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrInvalidMsg returns a standardized SDK error resulting from an invalid
+// remote execution message.
+func ErrInvalidMsg(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeInvalidMsg, msg)
+}
+
+// ErrUnknownController returns a standardized SDK error resulting from a
+// message referencing a controller that is not registered.
+func ErrUnknownController(codespace sdk.CodespaceType, id string) sdk.Error {
+	return newError(codespace, CodeUnknownController, fmt.Sprintf("controller %s is not registered", id))
+}
+
+// ErrUnauthorized returns a standardized SDK error resulting from a message
+// signed by an address other than the controller's authority.
+func ErrUnauthorized(codespace sdk.CodespaceType, id string) sdk.Error {
+	return newError(codespace, CodeUnauthorized, fmt.Sprintf("signer is not the authority of controller %s", id))
+}
+
+// ErrAddressCollision returns a standardized SDK error resulting from a
+// derived address already bound to a different controller and owner.
+func ErrAddressCollision(codespace sdk.CodespaceType, addr string) sdk.Error {
+	return newError(codespace, CodeAddressCollision, fmt.Sprintf("address %s is bound to another owner", addr))
+}
+
+// ErrExecutionFailed returns a standardized SDK error resulting from a failed
+// EVM call.
+func ErrExecutionFailed(codespace sdk.CodespaceType, err error) sdk.Error {
+	return newError(codespace, CodeExecutionFailed, err.Error())
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
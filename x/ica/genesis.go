@@ -0,0 +1,59 @@
+package ica
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState defines the interchain accounts module's genesis state.
+type GenesisState struct {
+	Controllers []Controller `json:"controllers"`
+}
+
+// DefaultGenesisState returns the default interchain accounts module genesis
+// state. No controller is authorized by default.
+func DefaultGenesisState() GenesisState {
+	return GenesisState{Controllers: []Controller{}}
+}
+
+// ValidateGenesis returns an error if the given interchain accounts module
+// genesis state is invalid. Every controller must have an ID and an authority
+// and no ID may be duplicated.
+func ValidateGenesis(data GenesisState) error {
+	seen := make(map[string]bool)
+
+	for _, c := range data.Controllers {
+		if c.ID == "" || len(c.Authority) == 0 {
+			return fmt.Errorf("controller must have an ID and an authority")
+		}
+
+		if seen[c.ID] {
+			return fmt.Errorf("duplicate controller %s", c.ID)
+		}
+
+		seen[c.ID] = true
+	}
+
+	return nil
+}
+
+// InitGenesis validates and sets the interchain accounts module's genesis
+// state.
+func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) error {
+	if err := ValidateGenesis(data); err != nil {
+		return err
+	}
+
+	for _, c := range data.Controllers {
+		k.SetController(ctx, c)
+	}
+
+	return nil
+}
+
+// WriteGenesis returns the interchain accounts module's current state as a
+// GenesisState.
+func WriteGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return GenesisState{Controllers: k.GetControllers(ctx)}
+}
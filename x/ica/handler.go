@@ -0,0 +1,43 @@
+package ica
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/types"
+)
+
+// NewHandler returns a handler for interchain accounts module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgRemoteExecute:
+			return handleMsgRemoteExecute(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized interchain accounts message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+// handleMsgRemoteExecute executes a remote EVM call. The call's output is
+// encoded as types.ResultData into the result's data, including upon failure
+// so that clients may decode the call's revert reason.
+func handleMsgRemoteExecute(ctx sdk.Context, k Keeper, msg MsgRemoteExecute) sdk.Result {
+	data, tags, err := k.RemoteExecute(ctx, msg)
+
+	bz, encErr := types.EncodeResultData(k.cdc, data)
+	if encErr != nil {
+		return sdk.ErrInternal(encErr.Error()).Result()
+	}
+
+	if err != nil {
+		res := err.Result()
+		res.Data = bz
+
+		return res
+	}
+
+	return sdk.Result{Data: bz, Tags: tags}
+}
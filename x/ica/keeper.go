@@ -0,0 +1,193 @@
+package ica
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// StoreName is the name of the store the interchain accounts module's state
+// is persisted in.
+const StoreName = "ica"
+
+// Tags emitted for every successful remote execution.
+const (
+	TagController = "ica.controller"
+	TagAccount    = "ica.account"
+)
+
+var (
+	controllerKeyPrefix = []byte("controller:")
+	accountKeyPrefix    = []byte("account:")
+)
+
+type (
+	// Controller defines an external module or chain that is authorized to
+	// execute EVM calls on behalf of its owners' interchain accounts. Only
+	// messages signed by the controller's authority (e.g. a relayer or module
+	// account) are accepted.
+	Controller struct {
+		ID        string         `json:"id"`
+		Authority sdk.AccAddress `json:"authority"`
+	}
+
+	// AccountOwner identifies the controller and owner an interchain account
+	// address is bound to.
+	AccountOwner struct {
+		Controller string `json:"controller"`
+		Owner      string `json:"owner"`
+	}
+
+	// Call defines an EVM call to be made on behalf of an interchain account.
+	Call struct {
+		From     ethcmn.Address
+		To       ethcmn.Address
+		Value    *big.Int
+		Data     []byte
+		GasLimit uint64
+	}
+
+	// Executor defines a function that executes an EVM call against the
+	// state of a given context, returning the call's output. The output of a
+	// failed call holds its revert data, if any.
+	Executor func(ctx sdk.Context, call Call) (types.ResultData, error)
+)
+
+// Keeper implements the interchain accounts module's state management. It
+// acts as the host of interchain accounts, authorizing remote execution
+// requests and binding derived account addresses to their controller and
+// owner.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	cdc      *wire.Codec
+	executor Executor
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new interchain accounts Keeper using the given Executor
+// to make EVM calls.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, executor Executor, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		cdc:       cdc,
+		executor:  executor,
+		codespace: codespace,
+	}
+}
+
+// SetController registers or updates an authorized controller.
+func (k Keeper) SetController(ctx sdk.Context, controller Controller) {
+	ctx.KVStore(k.storeKey).Set(ControllerKey(controller.ID), k.cdc.MustMarshalBinary(controller))
+}
+
+// GetController returns the controller with a given ID. A boolean is returned
+// reflecting if the controller is registered.
+func (k Keeper) GetController(ctx sdk.Context, id string) (Controller, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(ControllerKey(id))
+	if bz == nil {
+		return Controller{}, false
+	}
+
+	var controller Controller
+	k.cdc.MustUnmarshalBinary(bz, &controller)
+
+	return controller, true
+}
+
+// GetControllers returns all the registered controllers sorted by ID.
+func (k Keeper) GetControllers(ctx sdk.Context) []Controller {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), controllerKeyPrefix)
+	defer iter.Close()
+
+	controllers := []Controller{}
+	for ; iter.Valid(); iter.Next() {
+		var controller Controller
+		k.cdc.MustUnmarshalBinary(iter.Value(), &controller)
+
+		controllers = append(controllers, controller)
+	}
+
+	return controllers
+}
+
+// GetAccountOwner returns the controller and owner a given interchain account
+// address is bound to. A boolean is returned reflecting if the address was
+// ever used for remote execution.
+func (k Keeper) GetAccountOwner(ctx sdk.Context, addr ethcmn.Address) (AccountOwner, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(AccountKey(addr))
+	if bz == nil {
+		return AccountOwner{}, false
+	}
+
+	var owner AccountOwner
+	k.cdc.MustUnmarshalBinary(bz, &owner)
+
+	return owner, true
+}
+
+// RemoteExecute executes the EVM call of a given message on behalf of the
+// derived interchain account of its controller and owner. An error is returned
+// if the controller is not registered, if the message is not signed by the
+// controller's authority, if the derived address is bound to another
+// controller or owner, or if the call fails. The output of a failed call is
+// returned along with the error, whose message includes any decoded revert
+// reason.
+func (k Keeper) RemoteExecute(ctx sdk.Context, msg MsgRemoteExecute) (types.ResultData, sdk.Tags, sdk.Error) {
+	controller, ok := k.GetController(ctx, msg.Controller)
+	if !ok {
+		return types.ResultData{}, nil, ErrUnknownController(k.codespace, msg.Controller)
+	}
+
+	if !bytes.Equal(controller.Authority, msg.Authority) {
+		return types.ResultData{}, nil, ErrUnauthorized(k.codespace, msg.Controller)
+	}
+
+	addr := DeriveAddress(msg.Controller, msg.Owner)
+	owner := AccountOwner{Controller: msg.Controller, Owner: msg.Owner}
+
+	bound, found := k.GetAccountOwner(ctx, addr)
+	if found && bound != owner {
+		return types.ResultData{}, nil, ErrAddressCollision(k.codespace, addr.Hex())
+	}
+
+	res, err := k.executor(ctx, Call{
+		From:     addr,
+		To:       msg.Recipient,
+		Value:    msg.Value.BigInt(),
+		Data:     msg.Data,
+		GasLimit: msg.GasLimit,
+	})
+	if err != nil {
+		if reason, ok := core.UnpackRevertReason(res.Ret); ok {
+			err = fmt.Errorf("%v: %s", err, reason)
+		}
+
+		return res, nil, ErrExecutionFailed(k.codespace, err)
+	}
+
+	if !found {
+		ctx.KVStore(k.storeKey).Set(AccountKey(addr), k.cdc.MustMarshalBinary(owner))
+	}
+
+	tags := sdk.NewTags(TagController, []byte(msg.Controller), TagAccount, []byte(addr.Hex()))
+	return res, tags, nil
+}
+
+// ControllerKey returns the store key of the controller with a given ID.
+func ControllerKey(id string) []byte {
+	return append(append([]byte{}, controllerKeyPrefix...), id...)
+}
+
+// AccountKey returns the store key of the owner binding of a given interchain
+// account address.
+func AccountKey(addr ethcmn.Address) []byte {
+	return append(append([]byte{}, accountKeyPrefix...), addr.Bytes()...)
+}
@@ -0,0 +1,176 @@
+package ica
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testAuthority = sdk.AccAddress([]byte("test_authority______"))
+	testRelayer   = sdk.AccAddress([]byte("test_relayer________"))
+	testContract  = ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	errReverted   = errors.New("execution reverted")
+	testLog       = types.Log{Address: testContract, Topics: []ethcmn.Hash{{0x01}}, Data: []byte{0x02}}
+
+	// Panic(uint256) with an assertion failure code
+	testRevertData = append(
+		[]byte{0x4e, 0x48, 0x7b, 0x71}, ethcmn.LeftPadBytes([]byte{0x01}, 32)...,
+	)
+)
+
+func newTestInput(t *testing.T, executor Executor) (sdk.Context, Keeper) {
+	keyICA := sdk.NewKVStoreKey("ica")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyICA, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(wire.NewCodec(), keyICA, executor, DefaultCodespace)
+}
+
+func TestDeriveAddress(t *testing.T) {
+	addr := DeriveAddress("chain-a", "alice")
+
+	require.Equal(t, addr, DeriveAddress("chain-a", "alice"))
+	require.NotEqual(t, addr, DeriveAddress("chain-b", "alice"))
+	require.NotEqual(t, addr, DeriveAddress("chain-a", "bob"))
+
+	// the controller ID is length prefixed so shifting bytes between the
+	// controller and the owner yields a different address
+	require.NotEqual(t, DeriveAddress("chain-a", "bob"), DeriveAddress("chain-ab", "ob"))
+}
+
+func TestRemoteExecute(t *testing.T) {
+	var calls []Call
+
+	ctx, k := newTestInput(t, func(_ sdk.Context, call Call) (types.ResultData, error) {
+		if len(call.Data) == 0 {
+			return types.ResultData{}, errReverted
+		}
+
+		if call.Data[0] == 0xfe {
+			return types.ResultData{Ret: testRevertData, GasUsed: 21000}, errReverted
+		}
+
+		calls = append(calls, call)
+		return types.ResultData{Ret: call.Data, Logs: []types.Log{testLog}, GasUsed: 21000}, nil
+	})
+
+	genesis := GenesisState{Controllers: []Controller{{ID: "chain-a", Authority: testAuthority}}}
+	require.Nil(t, InitGenesis(ctx, k, genesis))
+	require.Equal(t, genesis, WriteGenesis(ctx, k))
+
+	handler := NewHandler(k)
+
+	testCases := []struct {
+		controller   string
+		authority    sdk.AccAddress
+		data         []byte
+		expectedCode sdk.CodeType
+	}{
+		{"chain-a", testAuthority, []byte{0x01}, sdk.CodeOK},
+		{"chain-b", testAuthority, []byte{0x01}, CodeUnknownController},
+		{"chain-a", testRelayer, []byte{0x01}, CodeUnauthorized},
+		{"chain-a", testAuthority, nil, CodeExecutionFailed},
+		{"chain-a", testAuthority, []byte{0xfe}, CodeExecutionFailed},
+	}
+
+	for i, tc := range testCases {
+		msg := NewMsgRemoteExecute(
+			tc.controller, "alice", tc.authority, testContract, sdk.NewInt(10), tc.data, 100000,
+		)
+		require.Nil(t, msg.ValidateBasic(), fmt.Sprintf("unexpected error: test case #%d", i))
+
+		expectedCode := sdk.ABCICodeOK
+		if tc.expectedCode != sdk.CodeOK {
+			expectedCode = sdk.ToABCICode(DefaultCodespace, tc.expectedCode)
+		}
+
+		res := handler(ctx, msg)
+		require.Equal(t, expectedCode, res.Code, fmt.Sprintf("unexpected code: test case #%d", i))
+
+		data, err := types.DecodeResultData(k.cdc, res.Data)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+
+		if res.IsOK() {
+			expected := types.ResultData{Ret: tc.data, Logs: []types.Log{testLog}, GasUsed: 21000}
+			require.Equal(t, expected, data, fmt.Sprintf("unexpected data: test case #%d", i))
+		}
+
+		if bytes.Equal(tc.data, []byte{0xfe}) {
+			require.Equal(t, testRevertData, data.Ret, fmt.Sprintf("unexpected data: test case #%d", i))
+			require.Contains(t, res.Log, "panic: assertion failed", fmt.Sprintf("unexpected log: test case #%d", i))
+		}
+	}
+
+	addr := DeriveAddress("chain-a", "alice")
+
+	require.Len(t, calls, 1)
+	require.Equal(t, addr, calls[0].From)
+	require.Equal(t, testContract, calls[0].To)
+	require.Equal(t, int64(10), calls[0].Value.Int64())
+
+	owner, found := k.GetAccountOwner(ctx, addr)
+	require.True(t, found)
+	require.Equal(t, AccountOwner{Controller: "chain-a", Owner: "alice"}, owner)
+}
+
+func TestRemoteExecuteAddressCollision(t *testing.T) {
+	ctx, k := newTestInput(t, func(_ sdk.Context, call Call) (types.ResultData, error) {
+		return types.ResultData{}, nil
+	})
+
+	k.SetController(ctx, Controller{ID: "chain-a", Authority: testAuthority})
+
+	// bind the derived address to another owner
+	addr := DeriveAddress("chain-a", "alice")
+	ctx.KVStore(k.storeKey).Set(AccountKey(addr), k.cdc.MustMarshalBinary(AccountOwner{"chain-b", "mallory"}))
+
+	msg := NewMsgRemoteExecute("chain-a", "alice", testAuthority, testContract, sdk.ZeroInt(), nil, 100000)
+
+	_, _, err := k.RemoteExecute(ctx, msg)
+	require.NotNil(t, err)
+	require.Equal(t, CodeAddressCollision, err.Code())
+}
+
+func TestMsgRemoteExecuteValidateBasic(t *testing.T) {
+	testCases := []struct {
+		msg       MsgRemoteExecute
+		expectErr bool
+	}{
+		{NewMsgRemoteExecute("chain-a", "alice", testAuthority, testContract, sdk.ZeroInt(), nil, 1), false},
+		{NewMsgRemoteExecute("", "alice", testAuthority, testContract, sdk.ZeroInt(), nil, 1), true},
+		{NewMsgRemoteExecute("chain-a", "", testAuthority, testContract, sdk.ZeroInt(), nil, 1), true},
+		{NewMsgRemoteExecute("chain-a", "alice", nil, testContract, sdk.ZeroInt(), nil, 1), true},
+		{NewMsgRemoteExecute("chain-a", "alice", testAuthority, testContract, sdk.NewInt(-1), nil, 1), true},
+		{NewMsgRemoteExecute("chain-a", "alice", testAuthority, testContract, sdk.ZeroInt(), nil, 0), true},
+		{MsgRemoteExecute{Controller: "chain-a", Owner: "alice", Authority: testAuthority, GasLimit: 1}, true},
+	}
+
+	for i, tc := range testCases {
+		err := tc.msg.ValidateBasic()
+
+		if tc.expectErr {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+			require.Equal(t, []sdk.AccAddress{testAuthority}, tc.msg.GetSigners())
+			require.NotPanics(t, func() { tc.msg.GetSignBytes() })
+		}
+	}
+}
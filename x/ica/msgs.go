@@ -0,0 +1,81 @@
+package ica
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// MsgRemoteExecute defines a request, relayed by a controller's authority, to
+// execute an EVM call on behalf of the interchain account of a given owner on
+// that controller. The call is made from the account derived from the
+// controller and owner.
+type MsgRemoteExecute struct {
+	Controller string         `json:"controller"`
+	Owner      string         `json:"owner"`
+	Authority  sdk.AccAddress `json:"authority"`
+	Recipient  ethcmn.Address `json:"recipient"`
+	Value      sdk.Int        `json:"value"`
+	Data       []byte         `json:"data"`
+	GasLimit   uint64         `json:"gas_limit"`
+}
+
+var _ sdk.Msg = MsgRemoteExecute{}
+
+// NewMsgRemoteExecute returns a new MsgRemoteExecute.
+func NewMsgRemoteExecute(
+	controller, owner string, authority sdk.AccAddress, recipient ethcmn.Address,
+	value sdk.Int, data []byte, gasLimit uint64,
+) MsgRemoteExecute {
+
+	return MsgRemoteExecute{
+		Controller: controller,
+		Owner:      owner,
+		Authority:  authority,
+		Recipient:  recipient,
+		Value:      value,
+		Data:       data,
+		GasLimit:   gasLimit,
+	}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgRemoteExecute) Type() string { return "ica" }
+
+// ValidateBasic implements the sdk.Msg interface.
+func (msg MsgRemoteExecute) ValidateBasic() sdk.Error {
+	switch {
+	case msg.Controller == "":
+		return ErrInvalidMsg(DefaultCodespace, "controller cannot be empty")
+
+	case msg.Owner == "":
+		return ErrInvalidMsg(DefaultCodespace, "owner cannot be empty")
+
+	case len(msg.Authority) == 0:
+		return ErrInvalidMsg(DefaultCodespace, "authority cannot be empty")
+
+	case msg.Value == (sdk.Int{}) || msg.Value.Sign() < 0:
+		return ErrInvalidMsg(DefaultCodespace, "value must be non-negative")
+
+	case msg.GasLimit == 0:
+		return ErrInvalidMsg(DefaultCodespace, "gas limit must be positive")
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgRemoteExecute) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgRemoteExecute must be
+// signed by the authority of its controller.
+func (msg MsgRemoteExecute) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Authority}
+}
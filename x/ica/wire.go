@@ -0,0 +1,17 @@
+package ica
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the interchain accounts module's concrete types on a
+// wire codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgRemoteExecute{}, "ethermint/ica/RemoteExecute", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}
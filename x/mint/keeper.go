@@ -0,0 +1,88 @@
+package mint
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+var (
+	// DefaultFeeCollectorAddr is the default address of the account that
+	// collects gas fees and block rewards for distribution to validators and
+	// delegators.
+	DefaultFeeCollectorAddr = sdk.AccAddress(tmhash.Sum([]byte("fee_collector")))
+
+	paramsKey = []byte("params")
+)
+
+// Keeper implements the mint module's state management. It mints a
+// configurable reward every block into the fee collector account, which also
+// collects the gas fees of Ethereum transactions. The reward accrues there as
+// no module distributes the fee collector's funds. No reward is minted until
+// one is set, e.g. at genesis.
+type Keeper struct {
+	storeKey     sdk.StoreKey
+	cdc          *wire.Codec
+	ck           bank.Keeper
+	feeCollector sdk.AccAddress
+}
+
+// NewKeeper returns a new mint Keeper minting into the account at
+// DefaultFeeCollectorAddr.
+func NewKeeper(cdc *wire.Codec, key sdk.StoreKey, ck bank.Keeper) Keeper {
+	return Keeper{
+		storeKey:     key,
+		cdc:          cdc,
+		ck:           ck,
+		feeCollector: DefaultFeeCollectorAddr,
+	}
+}
+
+// WithFeeCollector returns a copy of the Keeper minting into the account at
+// the given address instead.
+func (k Keeper) WithFeeCollector(addr sdk.AccAddress) Keeper {
+	k.feeCollector = addr
+	return k
+}
+
+// FeeCollector returns the address of the account the Keeper mints into.
+func (k Keeper) FeeCollector() sdk.AccAddress {
+	return k.feeCollector
+}
+
+// GetParams returns the mint module parameters. The default parameters are
+// returned if none have been set.
+func (k Keeper) GetParams(ctx sdk.Context) Params {
+	bz := ctx.KVStore(k.storeKey).Get(paramsKey)
+	if bz == nil {
+		return DefaultParams()
+	}
+
+	var params Params
+	k.cdc.MustUnmarshalBinary(bz, &params)
+
+	return params
+}
+
+// SetParams sets the mint module parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	ctx.KVStore(k.storeKey).Set(paramsKey, k.cdc.MustMarshalBinary(params))
+}
+
+// MintBlockReward mints the configured block reward into the fee collector
+// account. The minted coins are returned.
+func (k Keeper) MintBlockReward(ctx sdk.Context) (sdk.Coins, sdk.Tags, sdk.Error) {
+	reward := k.GetParams(ctx).BlockReward
+	if reward.IsZero() {
+		return nil, sdk.EmptyTags(), nil
+	}
+
+	_, tags, err := k.ck.AddCoins(ctx, k.feeCollector, reward)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reward, tags, nil
+}
@@ -0,0 +1,92 @@
+package mint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/wire"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func newTestInput(t *testing.T) (sdk.Context, bank.Keeper, Keeper) {
+	keyAcc := sdk.NewKVStoreKey("acc")
+	keyMint := sdk.NewKVStoreKey("mint")
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyMint, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	cdc := wire.NewCodec()
+	auth.RegisterBaseAccount(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	ck := bank.NewKeeper(auth.NewAccountMapper(cdc, keyAcc, auth.ProtoBaseAccount))
+
+	return ctx, ck, NewKeeper(cdc, keyMint, ck)
+}
+
+func TestBeginBlocker(t *testing.T) {
+	ctx, ck, k := newTestInput(t)
+
+	// no reward is minted prior to genesis
+	require.Equal(t, DefaultParams(), k.GetParams(ctx))
+	require.Empty(t, BeginBlocker(ctx, abci.RequestBeginBlock{}, k))
+	require.Empty(t, ck.GetCoins(ctx, DefaultFeeCollectorAddr))
+
+	reward := sdk.Coins{sdk.NewCoin(types.DenomDefault, 5)}
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{BlockReward: reward}}))
+
+	for i := 1; i <= 3; i++ {
+		tags := BeginBlocker(ctx, abci.RequestBeginBlock{}, k)
+		require.Contains(t, tags.ToKVPairs(), sdk.MakeTag(TagBlockReward, []byte(reward.String())))
+
+		expected := sdk.Coins{sdk.NewCoin(types.DenomDefault, int64(5*i))}
+		require.Equal(t, expected, ck.GetCoins(ctx, DefaultFeeCollectorAddr), fmt.Sprintf("unexpected balance: block #%d", i))
+	}
+
+	// a zero reward mints nothing
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{}}))
+	require.Empty(t, BeginBlocker(ctx, abci.RequestBeginBlock{}, k))
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.DenomDefault, 15)}, ck.GetCoins(ctx, DefaultFeeCollectorAddr))
+}
+
+func TestInitGenesisInvalid(t *testing.T) {
+	ctx, _, k := newTestInput(t)
+
+	testCases := []sdk.Coins{
+		{sdk.NewCoin(types.DenomDefault, 0)},
+		{sdk.NewCoin(types.DenomDefault, -1)},
+		{sdk.NewCoin("b", 1), sdk.NewCoin("a", 1)},
+	}
+
+	for i, tc := range testCases {
+		err := InitGenesis(ctx, k, GenesisState{Params{BlockReward: tc}})
+		require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+	}
+}
+
+func TestWithFeeCollector(t *testing.T) {
+	ctx, ck, k := newTestInput(t)
+
+	feeCollector := sdk.AccAddress([]byte("fee_collector_b"))
+	k = k.WithFeeCollector(feeCollector)
+	require.Equal(t, feeCollector, k.FeeCollector())
+
+	reward := sdk.Coins{sdk.NewCoin(types.DenomDefault, 5)}
+	require.Nil(t, InitGenesis(ctx, k, GenesisState{Params{BlockReward: reward}}))
+
+	BeginBlocker(ctx, abci.RequestBeginBlock{}, k)
+	require.Equal(t, reward, ck.GetCoins(ctx, feeCollector))
+	require.Empty(t, ck.GetCoins(ctx, DefaultFeeCollectorAddr))
+}
@@ -0,0 +1,27 @@
+package mint
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Params defines the parameters of the mint module.
+type Params struct {
+	// BlockReward is the amount minted every block into the fee collector
+	// account
+	BlockReward sdk.Coins `json:"block_reward"`
+}
+
+// DefaultParams returns the default mint module parameters, which mint no
+// block reward. A reward must be set explicitly, e.g. at genesis.
+func DefaultParams() Params {
+	return Params{}
+}
+
+// ValidateParams returns an error if the given parameters are invalid.
+func ValidateParams(params Params) sdk.Error {
+	if !params.BlockReward.IsValid() || !params.BlockReward.IsNotNegative() {
+		return sdk.ErrInvalidCoins(params.BlockReward.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package mint
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// TagBlockReward is the tag emitted with the amount minted every block.
+const TagBlockReward = "mint.reward"
+
+// BeginBlocker mints the block reward at the start of every block.
+func BeginBlocker(ctx sdk.Context, _ abci.RequestBeginBlock, k Keeper) sdk.Tags {
+	reward, tags, err := k.MintBlockReward(ctx)
+	if err != nil {
+		// the block reward is validated upon genesis so this should never occur
+		panic(fmt.Sprintf("failed to mint block reward: %v", err))
+	}
+
+	if reward.IsZero() {
+		return tags
+	}
+
+	return tags.AppendTag(TagBlockReward, []byte(reward.String()))
+}
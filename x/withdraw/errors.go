@@ -0,0 +1,36 @@
+package withdraw
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// DefaultCodespace reserves a Codespace for the withdraw module.
+	DefaultCodespace sdk.CodespaceType = 16
+
+	// Withdraw error codes
+	CodeNotValidator sdk.CodeType = 1
+)
+
+func codeToDefaultMsg(code sdk.CodeType) string {
+	switch code {
+	case CodeNotValidator:
+		return "not a validator operator"
+	default:
+		return sdk.CodeToDefaultMsg(code)
+	}
+}
+
+// ErrNotValidator returns a standardized SDK error resulting from an address
+// that is not the operator of any validator.
+func ErrNotValidator(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return newError(codespace, CodeNotValidator, msg)
+}
+
+func newError(codespace sdk.CodespaceType, code sdk.CodeType, msg string) sdk.Error {
+	if msg == "" {
+		msg = codeToDefaultMsg(code)
+	}
+
+	return sdk.NewError(codespace, code, msg)
+}
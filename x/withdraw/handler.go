@@ -0,0 +1,30 @@
+package withdraw
+
+import (
+	"reflect"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewHandler returns a handler for withdraw module messages.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case MsgSetWithdrawAddress:
+			return handleMsgSetWithdrawAddress(ctx, k, msg)
+
+		default:
+			errMsg := "unrecognized withdraw message type: " + reflect.TypeOf(msg).Name()
+			return sdk.ErrUnknownRequest(errMsg).Result()
+		}
+	}
+}
+
+func handleMsgSetWithdrawAddress(ctx sdk.Context, k Keeper, msg MsgSetWithdrawAddress) sdk.Result {
+	tags, err := k.SetWithdrawAddress(ctx, msg.Validator, msg.WithdrawAddress)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{Tags: tags}
+}
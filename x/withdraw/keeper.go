@@ -0,0 +1,82 @@
+package withdraw
+
+import (
+	"bytes"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StoreName is the name of the store the withdraw module's state is
+// persisted in.
+const StoreName = "withdraw"
+
+// Tags emitted for every change of a withdrawal address.
+const (
+	TagValidator       = "withdraw.validator"
+	TagWithdrawAddress = "withdraw.address"
+)
+
+var withdrawAddressKeyPrefix = []byte("address:")
+
+// Keeper implements the withdraw module's state management. It records the
+// address the fees and rewards of every validator are to be withdrawn to,
+// which defaults to the validator's operator address.
+type Keeper struct {
+	storeKey sdk.StoreKey
+	vs       sdk.ValidatorSet
+
+	codespace sdk.CodespaceType
+}
+
+// NewKeeper returns a new withdraw Keeper recognizing the operators of the
+// validators of a given validator set.
+func NewKeeper(key sdk.StoreKey, vs sdk.ValidatorSet, codespace sdk.CodespaceType) Keeper {
+	return Keeper{
+		storeKey:  key,
+		vs:        vs,
+		codespace: codespace,
+	}
+}
+
+// Codespace returns the codespace the Keeper raises its errors in.
+func (k Keeper) Codespace() sdk.CodespaceType {
+	return k.codespace
+}
+
+// GetWithdrawAddress returns the address the fees and rewards of the
+// validator operated by a given address are withdrawn to, being the operator
+// address itself unless set otherwise.
+func (k Keeper) GetWithdrawAddress(ctx sdk.Context, validator sdk.AccAddress) sdk.AccAddress {
+	bz := ctx.KVStore(k.storeKey).Get(WithdrawAddressKey(validator))
+	if bz == nil {
+		return validator
+	}
+
+	return sdk.AccAddress(bz)
+}
+
+// SetWithdrawAddress sets the address the fees and rewards of the validator
+// operated by a given address are withdrawn to. Setting the operator address
+// itself restores the default. An error is returned if the address operates
+// no validator.
+func (k Keeper) SetWithdrawAddress(ctx sdk.Context, validator, withdrawAddr sdk.AccAddress) (sdk.Tags, sdk.Error) {
+	if k.vs.Validator(ctx, validator) == nil {
+		return nil, ErrNotValidator(k.codespace, "")
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	if bytes.Equal(validator, withdrawAddr) {
+		store.Delete(WithdrawAddressKey(validator))
+	} else {
+		store.Set(WithdrawAddressKey(validator), withdrawAddr.Bytes())
+	}
+
+	tags := sdk.NewTags(TagValidator, []byte(validator.String()), TagWithdrawAddress, []byte(withdrawAddr.String()))
+	return tags, nil
+}
+
+// WithdrawAddressKey returns the store key of the withdrawal address of the
+// validator operated by a given address.
+func WithdrawAddressKey(validator sdk.AccAddress) []byte {
+	return append(withdrawAddressKeyPrefix, validator.Bytes()...)
+}
@@ -0,0 +1,110 @@
+package withdraw
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	testValidator = sdk.AccAddress([]byte("test_validator______"))
+	testTreasury  = sdk.AccAddress([]byte("test_treasury_______"))
+	testAddr      = sdk.AccAddress([]byte("test_address________"))
+)
+
+// testValidatorSet is a validator set of a single validator, operated by
+// testValidator.
+type testValidatorSet struct {
+	sdk.ValidatorSet
+}
+
+func (testValidatorSet) Validator(_ sdk.Context, addr sdk.AccAddress) sdk.Validator {
+	if !bytes.Equal(addr, testValidator) {
+		return nil
+	}
+
+	return stake.NewValidator(testValidator, nil, stake.Description{})
+}
+
+func newTestInput(t *testing.T) (sdk.Context, Keeper) {
+	keyWithdraw := sdk.NewKVStoreKey(StoreName)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyWithdraw, sdk.StoreTypeIAVL, db)
+	require.Nil(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return ctx, NewKeeper(keyWithdraw, testValidatorSet{}, DefaultCodespace)
+}
+
+func TestSetWithdrawAddress(t *testing.T) {
+	ctx, k := newTestInput(t)
+
+	// the operator address is the default withdrawal address
+	require.Equal(t, testValidator, k.GetWithdrawAddress(ctx, testValidator))
+
+	tags, err := k.SetWithdrawAddress(ctx, testValidator, testTreasury)
+	require.Nil(t, err)
+	require.Equal(t, sdk.NewTags(
+		TagValidator, []byte(testValidator.String()), TagWithdrawAddress, []byte(testTreasury.String()),
+	), tags)
+	require.Equal(t, testTreasury, k.GetWithdrawAddress(ctx, testValidator))
+
+	// setting the operator address restores the default
+	_, err = k.SetWithdrawAddress(ctx, testValidator, testValidator)
+	require.Nil(t, err)
+	require.Equal(t, testValidator, k.GetWithdrawAddress(ctx, testValidator))
+	require.Nil(t, ctx.KVStore(k.storeKey).Get(WithdrawAddressKey(testValidator)))
+
+	// only validator operators may set a withdrawal address
+	_, err = k.SetWithdrawAddress(ctx, testAddr, testTreasury)
+	require.Equal(t, CodeNotValidator, err.Code())
+	require.Equal(t, testAddr, k.GetWithdrawAddress(ctx, testAddr))
+}
+
+func TestHandleMsgSetWithdrawAddress(t *testing.T) {
+	ctx, k := newTestInput(t)
+	handler := NewHandler(k)
+
+	res := handler(ctx, NewMsgSetWithdrawAddress(testValidator, testTreasury))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, testTreasury, k.GetWithdrawAddress(ctx, testValidator))
+
+	res = handler(ctx, NewMsgSetWithdrawAddress(testAddr, testTreasury))
+	require.Equal(t, sdk.ToABCICode(DefaultCodespace, CodeNotValidator), res.Code)
+}
+
+func TestMsgSetWithdrawAddressValidateBasic(t *testing.T) {
+	testCases := []struct {
+		msg        MsgSetWithdrawAddress
+		expectPass bool
+	}{
+		{NewMsgSetWithdrawAddress(testValidator, testTreasury), true},
+		{NewMsgSetWithdrawAddress(testValidator, testValidator), true},
+		{NewMsgSetWithdrawAddress(nil, testTreasury), false},
+		{NewMsgSetWithdrawAddress(testValidator, nil), false},
+		{NewMsgSetWithdrawAddress(testValidator, sdk.AccAddress([]byte("short"))), false},
+	}
+
+	for i, tc := range testCases {
+		err := tc.msg.ValidateBasic()
+
+		if tc.expectPass {
+			require.Nil(t, err, fmt.Sprintf("unexpected error: test case #%d", i))
+		} else {
+			require.NotNil(t, err, fmt.Sprintf("expected error: test case #%d", i))
+		}
+	}
+
+	require.Equal(t, []sdk.AccAddress{testValidator}, NewMsgSetWithdrawAddress(testValidator, testTreasury).GetSigners())
+}
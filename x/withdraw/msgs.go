@@ -0,0 +1,59 @@
+package withdraw
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// MsgType is the type and route of the withdraw module's messages.
+const MsgType = "withdraw"
+
+// MsgSetWithdrawAddress defines a request of a validator operator to have the
+// fees and rewards of its validator withdrawn to a separate Ethereum address,
+// so that the operator's key need not hold them.
+type MsgSetWithdrawAddress struct {
+	Validator       sdk.AccAddress `json:"validator"`
+	WithdrawAddress sdk.AccAddress `json:"withdraw_address"`
+}
+
+var _ sdk.Msg = MsgSetWithdrawAddress{}
+
+// NewMsgSetWithdrawAddress returns a new MsgSetWithdrawAddress for a given
+// validator operator and withdrawal address.
+func NewMsgSetWithdrawAddress(validator, withdrawAddr sdk.AccAddress) MsgSetWithdrawAddress {
+	return MsgSetWithdrawAddress{Validator: validator, WithdrawAddress: withdrawAddr}
+}
+
+// Type implements the sdk.Msg interface.
+func (msg MsgSetWithdrawAddress) Type() string { return MsgType }
+
+// ValidateBasic implements the sdk.Msg interface. Both addresses must be
+// Ethereum addresses.
+func (msg MsgSetWithdrawAddress) ValidateBasic() sdk.Error {
+	if len(msg.Validator) != ethcmn.AddressLength {
+		return sdk.ErrInvalidAddress("invalid validator operator address")
+	}
+
+	if len(msg.WithdrawAddress) != ethcmn.AddressLength {
+		return sdk.ErrInvalidAddress("invalid withdrawal address")
+	}
+
+	return nil
+}
+
+// GetSignBytes implements the sdk.Msg interface.
+func (msg MsgSetWithdrawAddress) GetSignBytes() []byte {
+	bz, err := msgCdc.MarshalJSON(msg)
+	if err != nil {
+		panic(err)
+	}
+
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners implements the sdk.Msg interface. A MsgSetWithdrawAddress must
+// be signed by the validator operator.
+func (msg MsgSetWithdrawAddress) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Validator}
+}
@@ -0,0 +1,17 @@
+package withdraw
+
+import (
+	"github.com/cosmos/cosmos-sdk/wire"
+)
+
+// RegisterWire registers the withdraw module's concrete types on a wire
+// codec.
+func RegisterWire(cdc *wire.Codec) {
+	cdc.RegisterConcrete(MsgSetWithdrawAddress{}, "ethermint/withdraw/SetWithdrawAddress", nil)
+}
+
+var msgCdc = wire.NewCodec()
+
+func init() {
+	RegisterWire(msgCdc)
+}